@@ -24,6 +24,12 @@ type Authorization struct {
 	Permissions []Permission `json:"permissions"`
 }
 
+// Cursor returns the position of the authorization in a listing sorted by sortBy.
+// Authorizations may only be sorted by ID.
+func (a *Authorization) Cursor(sortBy string) (PageCursor, error) {
+	return idPageCursor(sortBy, a.ID)
+}
+
 // Valid ensures that the authorization is valid.
 func (a *Authorization) Valid() error {
 	for _, p := range a.Permissions {
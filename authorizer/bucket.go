@@ -11,13 +11,17 @@ var _ influxdb.BucketService = (*BucketService)(nil)
 // BucketService wraps a influxdb.BucketService and authorizes actions
 // against it appropriately.
 type BucketService struct {
-	s influxdb.BucketService
+	s      influxdb.BucketService
+	shares influxdb.ShareService
 }
 
 // NewBucketService constructs an instance of an authorizing bucket serivce.
-func NewBucketService(s influxdb.BucketService) *BucketService {
+// Access granted by the share grants in shares is honored; a nil shares
+// authorizes against the owning organization alone.
+func NewBucketService(s influxdb.BucketService, shares influxdb.ShareService) *BucketService {
 	return &BucketService{
-		s: s,
+		s:      s,
+		shares: shares,
 	}
 }
 
@@ -51,6 +55,18 @@ func authorizeWriteBucket(ctx context.Context, orgID, id influxdb.ID) error {
 	return nil
 }
 
+// authorizeRead checks read access to the bucket id owned by orgID, honoring share grants.
+func (s *BucketService) authorizeRead(ctx context.Context, orgID, id influxdb.ID) error {
+	err := authorizeReadBucket(ctx, orgID, id)
+	return authorizeShared(ctx, s.shares, err, influxdb.ReadAction, influxdb.BucketsResourceType, id)
+}
+
+// authorizeWrite checks write access to the bucket id owned by orgID, honoring share grants.
+func (s *BucketService) authorizeWrite(ctx context.Context, orgID, id influxdb.ID) error {
+	err := authorizeWriteBucket(ctx, orgID, id)
+	return authorizeShared(ctx, s.shares, err, influxdb.WriteAction, influxdb.BucketsResourceType, id)
+}
+
 // FindBucketByID checks to see if the authorizer on context has read access to the id provided.
 func (s *BucketService) FindBucketByID(ctx context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
 	b, err := s.s.FindBucketByID(ctx, id)
@@ -58,7 +74,7 @@ func (s *BucketService) FindBucketByID(ctx context.Context, id influxdb.ID) (*in
 		return nil, err
 	}
 
-	if err := authorizeReadBucket(ctx, b.OrganizationID, id); err != nil {
+	if err := s.authorizeRead(ctx, b.OrganizationID, id); err != nil {
 		return nil, err
 	}
 
@@ -72,7 +88,7 @@ func (s *BucketService) FindBucket(ctx context.Context, filter influxdb.BucketFi
 		return nil, err
 	}
 
-	if err := authorizeReadBucket(ctx, b.OrganizationID, b.ID); err != nil {
+	if err := s.authorizeRead(ctx, b.OrganizationID, b.ID); err != nil {
 		return nil, err
 	}
 
@@ -92,7 +108,7 @@ func (s *BucketService) FindBuckets(ctx context.Context, filter influxdb.BucketF
 	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
 	buckets := bs[:0]
 	for _, b := range bs {
-		err := authorizeReadBucket(ctx, b.OrganizationID, b.ID)
+		err := s.authorizeRead(ctx, b.OrganizationID, b.ID)
 		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
 			return nil, 0, err
 		}
@@ -128,7 +144,7 @@ func (s *BucketService) UpdateBucket(ctx context.Context, id influxdb.ID, upd in
 		return nil, err
 	}
 
-	if err := authorizeWriteBucket(ctx, b.OrganizationID, id); err != nil {
+	if err := s.authorizeWrite(ctx, b.OrganizationID, id); err != nil {
 		return nil, err
 	}
 
@@ -136,6 +152,7 @@ func (s *BucketService) UpdateBucket(ctx context.Context, id influxdb.ID, upd in
 }
 
 // DeleteBucket checks to see if the authorizer on context has write access to the bucket provided.
+// Share grants never allow deleting a bucket.
 func (s *BucketService) DeleteBucket(ctx context.Context, id influxdb.ID) error {
 	b, err := s.s.FindBucketByID(ctx, id)
 	if err != nil {
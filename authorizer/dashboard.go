@@ -11,13 +11,17 @@ var _ influxdb.DashboardService = (*DashboardService)(nil)
 // DashboardService wraps a influxdb.DashboardService and authorizes actions
 // against it appropriately.
 type DashboardService struct {
-	s influxdb.DashboardService
+	s      influxdb.DashboardService
+	shares influxdb.ShareService
 }
 
 // NewDashboardService constructs an instance of an authorizing dashboard serivce.
-func NewDashboardService(s influxdb.DashboardService) *DashboardService {
+// Access granted by the share grants in shares is honored; a nil shares
+// authorizes against the owning organization alone.
+func NewDashboardService(s influxdb.DashboardService, shares influxdb.ShareService) *DashboardService {
 	return &DashboardService{
-		s: s,
+		s:      s,
+		shares: shares,
 	}
 }
 
@@ -51,6 +55,18 @@ func authorizeWriteDashboard(ctx context.Context, orgID, id influxdb.ID) error {
 	return nil
 }
 
+// authorizeRead checks read access to the dashboard id owned by orgID, honoring share grants.
+func (s *DashboardService) authorizeRead(ctx context.Context, orgID, id influxdb.ID) error {
+	err := authorizeReadDashboard(ctx, orgID, id)
+	return authorizeShared(ctx, s.shares, err, influxdb.ReadAction, influxdb.DashboardsResourceType, id)
+}
+
+// authorizeWrite checks write access to the dashboard id owned by orgID, honoring share grants.
+func (s *DashboardService) authorizeWrite(ctx context.Context, orgID, id influxdb.ID) error {
+	err := authorizeWriteDashboard(ctx, orgID, id)
+	return authorizeShared(ctx, s.shares, err, influxdb.WriteAction, influxdb.DashboardsResourceType, id)
+}
+
 // FindDashboardByID checks to see if the authorizer on context has read access to the id provided.
 func (s *DashboardService) FindDashboardByID(ctx context.Context, id influxdb.ID) (*influxdb.Dashboard, error) {
 	b, err := s.s.FindDashboardByID(ctx, id)
@@ -58,7 +74,7 @@ func (s *DashboardService) FindDashboardByID(ctx context.Context, id influxdb.ID
 		return nil, err
 	}
 
-	if err := authorizeReadDashboard(ctx, b.OrganizationID, id); err != nil {
+	if err := s.authorizeRead(ctx, b.OrganizationID, id); err != nil {
 		return nil, err
 	}
 
@@ -78,7 +94,7 @@ func (s *DashboardService) FindDashboards(ctx context.Context, filter influxdb.D
 	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
 	dashboards := bs[:0]
 	for _, b := range bs {
-		err := authorizeReadDashboard(ctx, b.OrganizationID, b.ID)
+		err := s.authorizeRead(ctx, b.OrganizationID, b.ID)
 		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
 			return nil, 0, err
 		}
@@ -114,7 +130,7 @@ func (s *DashboardService) UpdateDashboard(ctx context.Context, id influxdb.ID,
 		return nil, err
 	}
 
-	if err := authorizeWriteDashboard(ctx, b.OrganizationID, id); err != nil {
+	if err := s.authorizeWrite(ctx, b.OrganizationID, id); err != nil {
 		return nil, err
 	}
 
@@ -122,6 +138,7 @@ func (s *DashboardService) UpdateDashboard(ctx context.Context, id influxdb.ID,
 }
 
 // DeleteDashboard checks to see if the authorizer on context has write access to the dashboard provided.
+// Share grants never allow deleting a dashboard.
 func (s *DashboardService) DeleteDashboard(ctx context.Context, id influxdb.ID) error {
 	b, err := s.s.FindDashboardByID(ctx, id)
 	if err != nil {
@@ -141,7 +158,7 @@ func (s *DashboardService) AddDashboardCell(ctx context.Context, id influxdb.ID,
 		return err
 	}
 
-	if err := authorizeWriteDashboard(ctx, b.OrganizationID, id); err != nil {
+	if err := s.authorizeWrite(ctx, b.OrganizationID, id); err != nil {
 		return err
 	}
 
@@ -154,7 +171,7 @@ func (s *DashboardService) RemoveDashboardCell(ctx context.Context, dashboardID
 		return err
 	}
 
-	if err := authorizeWriteDashboard(ctx, b.OrganizationID, dashboardID); err != nil {
+	if err := s.authorizeWrite(ctx, b.OrganizationID, dashboardID); err != nil {
 		return err
 	}
 
@@ -167,7 +184,7 @@ func (s *DashboardService) UpdateDashboardCell(ctx context.Context, dashboardID
 		return nil, err
 	}
 
-	if err := authorizeWriteDashboard(ctx, b.OrganizationID, dashboardID); err != nil {
+	if err := s.authorizeWrite(ctx, b.OrganizationID, dashboardID); err != nil {
 		return nil, err
 	}
 
@@ -180,7 +197,7 @@ func (s *DashboardService) GetDashboardCellView(ctx context.Context, dashboardID
 		return nil, err
 	}
 
-	if err := authorizeReadDashboard(ctx, b.OrganizationID, dashboardID); err != nil {
+	if err := s.authorizeRead(ctx, b.OrganizationID, dashboardID); err != nil {
 		return nil, err
 	}
 
@@ -193,7 +210,7 @@ func (s *DashboardService) UpdateDashboardCellView(ctx context.Context, dashboar
 		return nil, err
 	}
 
-	if err := authorizeWriteDashboard(ctx, b.OrganizationID, dashboardID); err != nil {
+	if err := s.authorizeWrite(ctx, b.OrganizationID, dashboardID); err != nil {
 		return nil, err
 	}
 
@@ -206,7 +223,7 @@ func (s *DashboardService) ReplaceDashboardCells(ctx context.Context, id influxd
 		return err
 	}
 
-	if err := authorizeWriteDashboard(ctx, b.OrganizationID, id); err != nil {
+	if err := s.authorizeWrite(ctx, b.OrganizationID, id); err != nil {
 		return err
 	}
 
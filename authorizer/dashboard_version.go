@@ -0,0 +1,66 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.DashboardVersionService = (*DashboardVersionService)(nil)
+
+// DashboardVersionService wraps a influxdb.DashboardVersionService and
+// authorizes actions against it by the access to the dashboard they concern.
+type DashboardVersionService struct {
+	s          influxdb.DashboardVersionService
+	dashboards *DashboardService
+}
+
+// NewDashboardVersionService constructs an instance of an authorizing dashboard
+// version service. Dashboards are looked up in dashboards, which should not
+// itself be authorizing, and access granted by the share grants in shares is honored.
+func NewDashboardVersionService(s influxdb.DashboardVersionService, dashboards influxdb.DashboardService, shares influxdb.ShareService) *DashboardVersionService {
+	return &DashboardVersionService{
+		s:          s,
+		dashboards: NewDashboardService(dashboards, shares),
+	}
+}
+
+func (s *DashboardVersionService) authorizeRead(ctx context.Context, id influxdb.ID) error {
+	d, err := s.dashboards.s.FindDashboardByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.dashboards.authorizeRead(ctx, d.OrganizationID, id)
+}
+
+// FindDashboardVersions checks to see if the authorizer on context has read access to the dashboard id.
+func (s *DashboardVersionService) FindDashboardVersions(ctx context.Context, id influxdb.ID, opts influxdb.FindOptions) ([]*influxdb.DashboardVersion, int, error) {
+	if err := s.authorizeRead(ctx, id); err != nil {
+		return nil, 0, err
+	}
+
+	return s.s.FindDashboardVersions(ctx, id, opts)
+}
+
+// FindDashboardVersion checks to see if the authorizer on context has read access to the dashboard id.
+func (s *DashboardVersionService) FindDashboardVersion(ctx context.Context, id influxdb.ID, version int) (*influxdb.DashboardVersion, error) {
+	if err := s.authorizeRead(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.s.FindDashboardVersion(ctx, id, version)
+}
+
+// RestoreDashboardVersion checks to see if the authorizer on context has write access to the dashboard id.
+func (s *DashboardVersionService) RestoreDashboardVersion(ctx context.Context, id influxdb.ID, version int) (*influxdb.Dashboard, error) {
+	d, err := s.dashboards.s.FindDashboardByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.dashboards.authorizeWrite(ctx, d.OrganizationID, id); err != nil {
+		return nil, err
+	}
+
+	return s.s.RestoreDashboardVersion(ctx, id, version)
+}
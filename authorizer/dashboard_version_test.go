@@ -0,0 +1,52 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestDashboardVersionService(t *testing.T) {
+	dashboards := &mock.DashboardService{
+		FindDashboardByIDF: func(ctx context.Context, id influxdb.ID) (*influxdb.Dashboard, error) {
+			return &influxdb.Dashboard{
+				ID:             id,
+				OrganizationID: 10,
+			}, nil
+		},
+	}
+	s := authorizer.NewDashboardVersionService(mock.NewDashboardVersionService(), dashboards, nil)
+
+	read := influxdb.Permission{
+		Action: "read",
+		Resource: influxdb.Resource{
+			Type: influxdb.DashboardsResourceType,
+			ID:   influxdbtesting.IDPtr(1),
+		},
+	}
+	ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{read}})
+
+	if _, _, err := s.FindDashboardVersions(ctx, 1, influxdb.FindOptions{}); err != nil {
+		t.Errorf("expected versions to be readable, got %v", err)
+	}
+	if _, err := s.FindDashboardVersion(ctx, 1, 1); err != nil {
+		t.Errorf("expected a version to be readable, got %v", err)
+	}
+
+	_, _, err := s.FindDashboardVersions(ctx, 2, influxdb.FindOptions{})
+	influxdbtesting.ErrorsEqual(t, err, &influxdb.Error{
+		Msg:  "read:orgs/000000000000000a/dashboards/0000000000000002 is unauthorized",
+		Code: influxdb.EUnauthorized,
+	})
+
+	_, err = s.RestoreDashboardVersion(ctx, 1, 1)
+	influxdbtesting.ErrorsEqual(t, err, &influxdb.Error{
+		Msg:  "write:orgs/000000000000000a/dashboards/0000000000000001 is unauthorized",
+		Code: influxdb.EUnauthorized,
+	})
+}
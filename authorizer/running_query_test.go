@@ -0,0 +1,79 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestRunningQueryService(t *testing.T) {
+	queries := map[influxdb.ID]*influxdb.RunningQuery{
+		1: {ID: 1, OrganizationID: 10, UserID: 2},
+		2: {ID: 2, OrganizationID: 10, UserID: 3},
+		3: {ID: 3, OrganizationID: 20, UserID: 2},
+	}
+
+	var canceled []influxdb.ID
+	m := mock.NewRunningQueryService()
+	m.FindRunningQueriesF = func(context.Context, influxdb.RunningQueryFilter) ([]*influxdb.RunningQuery, error) {
+		return []*influxdb.RunningQuery{queries[1], queries[2], queries[3]}, nil
+	}
+	m.FindRunningQueryByIDF = func(ctx context.Context, id influxdb.ID) (*influxdb.RunningQuery, error) {
+		return queries[id], nil
+	}
+	m.CancelRunningQueryF = func(ctx context.Context, id influxdb.ID) error {
+		canceled = append(canceled, id)
+		return nil
+	}
+	s := authorizer.NewRunningQueryService(m)
+
+	read := influxdb.Permission{
+		Action: "read",
+		Resource: influxdb.Resource{
+			Type: influxdb.OrgsResourceType,
+			ID:   influxdbtesting.IDPtr(10),
+		},
+	}
+	ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{read}})
+
+	qs, err := s.FindRunningQueries(ctx, influxdb.RunningQueryFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qs) != 2 || qs[0].ID != 1 || qs[1].ID != 2 {
+		t.Fatalf("expected the queries of org 10 only, got %+v", qs)
+	}
+
+	_, err = s.FindRunningQueryByID(ctx, 3)
+	influxdbtesting.ErrorsEqual(t, err, &influxdb.Error{
+		Msg:  "read:orgs/0000000000000014 is unauthorized",
+		Code: influxdb.EUnauthorized,
+	})
+
+	// Users may cancel their own queries.
+	if err := s.CancelRunningQuery(ctx, 1); err != nil {
+		t.Errorf("expected own query to be canceled, got %v", err)
+	}
+
+	err = s.CancelRunningQuery(ctx, 2)
+	influxdbtesting.ErrorsEqual(t, err, &influxdb.Error{
+		Msg:  "write:orgs/000000000000000a is unauthorized",
+		Code: influxdb.EUnauthorized,
+	})
+
+	write := read
+	write.Action = "write"
+	ctx = influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{read, write}})
+	if err := s.CancelRunningQuery(ctx, 2); err != nil {
+		t.Errorf("expected query of the org to be canceled, got %v", err)
+	}
+
+	if len(canceled) != 2 || canceled[0] != 1 || canceled[1] != 2 {
+		t.Fatalf("unexpected canceled queries %v", canceled)
+	}
+}
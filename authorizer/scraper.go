@@ -68,10 +68,10 @@ func (s *ScraperTargetStoreService) GetTargetByID(ctx context.Context, id influx
 }
 
 // ListTargets retrieves all scraper targets that match the provided filter and then filters the list down to only the resources that are authorized.
-func (s *ScraperTargetStoreService) ListTargets(ctx context.Context) ([]influxdb.ScraperTarget, error) {
+func (s *ScraperTargetStoreService) ListTargets(ctx context.Context, opt ...influxdb.FindOptions) ([]influxdb.ScraperTarget, error) {
 	// TODO: we'll likely want to push this operation into the database eventually since fetching the whole list of data
 	// will likely be expensive.
-	ss, err := s.s.ListTargets(ctx)
+	ss, err := s.s.ListTargets(ctx, opt...)
 	if err != nil {
 		return nil, err
 	}
@@ -137,7 +137,7 @@ func TestScraperTargetStoreService_ListTargets(t *testing.T) {
 			name: "authorized to see all scrapers",
 			fields: fields{
 				ScraperTargetStoreService: &mock.ScraperTargetStoreService{
-					ListTargetsF: func(ctx context.Context) ([]influxdb.ScraperTarget, error) {
+					ListTargetsF: func(ctx context.Context, opt ...influxdb.FindOptions) ([]influxdb.ScraperTarget, error) {
 						return []influxdb.ScraperTarget{
 							{
 								ID:    1,
@@ -184,7 +184,7 @@ func TestScraperTargetStoreService_ListTargets(t *testing.T) {
 			name: "authorized to access a single orgs scrapers",
 			fields: fields{
 				ScraperTargetStoreService: &mock.ScraperTargetStoreService{
-					ListTargetsF: func(ctx context.Context) ([]influxdb.ScraperTarget, error) {
+					ListTargetsF: func(ctx context.Context, opt ...influxdb.FindOptions) ([]influxdb.ScraperTarget, error) {
 						return []influxdb.ScraperTarget{
 							{
 								ID:    1,
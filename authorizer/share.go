@@ -0,0 +1,162 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.ShareService = (*ShareService)(nil)
+
+// authorizeShared is called with the error err of authorizing the action a on
+// the resource id within the organization owning it. If the authorizer on
+// context was denied, the action is still allowed when the resource is shared
+// with another organization in which the authorizer is allowed the action.
+func authorizeShared(ctx context.Context, shares influxdb.ShareService, err error, a influxdb.Action, rt influxdb.ResourceType, id influxdb.ID) error {
+	if shares == nil || influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+		return err
+	}
+
+	gs, _, ferr := shares.FindShares(ctx, influxdb.ShareFilter{
+		ResourceID:   &id,
+		ResourceType: rt,
+	})
+	if ferr != nil {
+		return ferr
+	}
+
+	for _, g := range gs {
+		if !g.Allows(a) {
+			continue
+		}
+
+		p, perr := influxdb.NewPermissionAtID(id, a, rt, g.TargetOrgID)
+		if perr != nil {
+			return perr
+		}
+		if IsAllowed(ctx, *p) == nil {
+			return nil
+		}
+	}
+
+	return err
+}
+
+// ShareService wraps a influxdb.ShareService and authorizes actions
+// against it appropriately.
+type ShareService struct {
+	s          influxdb.ShareService
+	orgService OrganizationService
+}
+
+// NewShareService constructs an instance of an authorizing share service.
+// The organization owning a resource being shared is found with orgSvc.
+func NewShareService(orgSvc OrganizationService, s influxdb.ShareService) *ShareService {
+	return &ShareService{
+		s:          s,
+		orgService: orgSvc,
+	}
+}
+
+// authorizeManageShare checks that the authorizer on context has write access
+// to the shared resource within the organization owning it.
+func authorizeManageShare(ctx context.Context, g *influxdb.ShareGrant) error {
+	p, err := influxdb.NewPermissionAtID(g.ResourceID, influxdb.WriteAction, g.ResourceType, g.OrgID)
+	if err != nil {
+		return err
+	}
+
+	return IsAllowed(ctx, *p)
+}
+
+// authorizeReadShare checks that the authorizer on context may see the grant,
+// either as a reader of the shared resource or of the target organization.
+func authorizeReadShare(ctx context.Context, g *influxdb.ShareGrant) error {
+	p, err := influxdb.NewPermissionAtID(g.ResourceID, influxdb.ReadAction, g.ResourceType, g.OrgID)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err == nil || influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+		return err
+	}
+
+	p, err = newOrgPermission(influxdb.ReadAction, g.TargetOrgID)
+	if err != nil {
+		return err
+	}
+
+	return IsAllowed(ctx, *p)
+}
+
+// FindShareByID checks to see if the authorizer on context has read access to the grant.
+func (s *ShareService) FindShareByID(ctx context.Context, id influxdb.ID) (*influxdb.ShareGrant, error) {
+	g, err := s.s.FindShareByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadShare(ctx, g); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// FindShares retrieves all grants that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *ShareService) FindShares(ctx context.Context, filter influxdb.ShareFilter) ([]*influxdb.ShareGrant, int, error) {
+	gs, _, err := s.s.FindShares(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	grants := gs[:0]
+	for _, g := range gs {
+		err := authorizeReadShare(ctx, g)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		grants = append(grants, g)
+	}
+
+	return grants, len(grants), nil
+}
+
+// CreateShare checks to see if the authorizer on context has write access to the resource being shared.
+func (s *ShareService) CreateShare(ctx context.Context, g *influxdb.ShareGrant) error {
+	orgID, err := s.orgService.FindResourceOrganizationID(ctx, g.ResourceType, g.ResourceID)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeManageShare(ctx, &influxdb.ShareGrant{
+		ResourceType: g.ResourceType,
+		ResourceID:   g.ResourceID,
+		OrgID:        orgID,
+	}); err != nil {
+		return err
+	}
+
+	return s.s.CreateShare(ctx, g)
+}
+
+// DeleteShare checks to see if the authorizer on context has write access to the shared resource.
+func (s *ShareService) DeleteShare(ctx context.Context, id influxdb.ID) error {
+	g, err := s.s.FindShareByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeManageShare(ctx, g); err != nil {
+		return err
+	}
+
+	return s.s.DeleteShare(ctx, id)
+}
@@ -0,0 +1,193 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBucketService_SharedAccess(t *testing.T) {
+	buckets := &mock.BucketService{
+		FindBucketByIDFn: func(ctx context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+			return &influxdb.Bucket{
+				ID:             id,
+				OrganizationID: 10,
+			}, nil
+		},
+		UpdateBucketFn: func(ctx context.Context, id influxdb.ID, upd influxdb.BucketUpdate) (*influxdb.Bucket, error) {
+			return &influxdb.Bucket{
+				ID:             id,
+				OrganizationID: 10,
+			}, nil
+		},
+		DeleteBucketFn: func(ctx context.Context, id influxdb.ID) error {
+			return nil
+		},
+	}
+	shares := mock.NewShareService()
+	shares.FindSharesF = func(ctx context.Context, filter influxdb.ShareFilter) ([]*influxdb.ShareGrant, int, error) {
+		if *filter.ResourceID != 1 {
+			return nil, 0, nil
+		}
+		return []*influxdb.ShareGrant{
+			{
+				ID:           100,
+				ResourceType: influxdb.BucketsResourceType,
+				ResourceID:   1,
+				OrgID:        10,
+				TargetOrgID:  20,
+				Action:       influxdb.ReadAction,
+			},
+		}, 1, nil
+	}
+
+	targetOrgPermissions := func(a influxdb.Action) []influxdb.Permission {
+		return []influxdb.Permission{
+			{
+				Action: a,
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(20),
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		shares      influxdb.ShareService
+		fn          func(ctx context.Context, s *authorizer.BucketService) error
+		wantErr     error
+	}{
+		{
+			name:        "read a bucket shared with the organization",
+			permissions: targetOrgPermissions(influxdb.ReadAction),
+			shares:      shares,
+			fn: func(ctx context.Context, s *authorizer.BucketService) error {
+				_, err := s.FindBucketByID(ctx, 1)
+				return err
+			},
+		},
+		{
+			name:        "shares are ignored without a share service",
+			permissions: targetOrgPermissions(influxdb.ReadAction),
+			fn: func(ctx context.Context, s *authorizer.BucketService) error {
+				_, err := s.FindBucketByID(ctx, 1)
+				return err
+			},
+			wantErr: &influxdb.Error{
+				Msg:  "read:orgs/000000000000000a/buckets/0000000000000001 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+		{
+			name:        "read a bucket not shared with the organization",
+			permissions: targetOrgPermissions(influxdb.ReadAction),
+			shares:      shares,
+			fn: func(ctx context.Context, s *authorizer.BucketService) error {
+				_, err := s.FindBucketByID(ctx, 2)
+				return err
+			},
+			wantErr: &influxdb.Error{
+				Msg:  "read:orgs/000000000000000a/buckets/0000000000000002 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+		{
+			name:        "update a bucket shared for reading",
+			permissions: targetOrgPermissions(influxdb.WriteAction),
+			shares:      shares,
+			fn: func(ctx context.Context, s *authorizer.BucketService) error {
+				_, err := s.UpdateBucket(ctx, 1, influxdb.BucketUpdate{})
+				return err
+			},
+			wantErr: &influxdb.Error{
+				Msg:  "write:orgs/000000000000000a/buckets/0000000000000001 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewBucketService(buckets, tt.shares)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{tt.permissions})
+
+			influxdbtesting.ErrorsEqual(t, tt.fn(ctx, s), tt.wantErr)
+		})
+	}
+}
+
+func TestDashboardService_SharedAccess(t *testing.T) {
+	dashboards := &mock.DashboardService{
+		FindDashboardByIDF: func(ctx context.Context, id influxdb.ID) (*influxdb.Dashboard, error) {
+			return &influxdb.Dashboard{
+				ID:             id,
+				OrganizationID: 10,
+			}, nil
+		},
+		UpdateDashboardF: func(ctx context.Context, id influxdb.ID, upd influxdb.DashboardUpdate) (*influxdb.Dashboard, error) {
+			return &influxdb.Dashboard{
+				ID:             id,
+				OrganizationID: 10,
+			}, nil
+		},
+		DeleteDashboardF: func(ctx context.Context, id influxdb.ID) error {
+			return nil
+		},
+	}
+	shares := mock.NewShareService()
+	shares.FindSharesF = func(ctx context.Context, filter influxdb.ShareFilter) ([]*influxdb.ShareGrant, int, error) {
+		return []*influxdb.ShareGrant{
+			{
+				ID:           100,
+				ResourceType: influxdb.DashboardsResourceType,
+				ResourceID:   *filter.ResourceID,
+				OrgID:        10,
+				TargetOrgID:  20,
+				Action:       influxdb.WriteAction,
+			},
+		}, 1, nil
+	}
+
+	s := authorizer.NewDashboardService(dashboards, shares)
+
+	ctx := context.Background()
+	ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{
+		{
+			Action: influxdb.ReadAction,
+			Resource: influxdb.Resource{
+				Type:  influxdb.DashboardsResourceType,
+				OrgID: influxdbtesting.IDPtr(20),
+			},
+		},
+		{
+			Action: influxdb.WriteAction,
+			Resource: influxdb.Resource{
+				Type:  influxdb.DashboardsResourceType,
+				OrgID: influxdbtesting.IDPtr(20),
+			},
+		},
+	}})
+
+	if _, err := s.FindDashboardByID(ctx, 1); err != nil {
+		t.Errorf("a write grant must allow reading the dashboard: %v", err)
+	}
+
+	if _, err := s.UpdateDashboard(ctx, 1, influxdb.DashboardUpdate{}); err != nil {
+		t.Errorf("a write grant must allow updating the dashboard: %v", err)
+	}
+
+	influxdbtesting.ErrorsEqual(t, s.DeleteDashboard(ctx, 1), &influxdb.Error{
+		Msg:  "write:orgs/000000000000000a/dashboards/0000000000000001 is unauthorized",
+		Code: influxdb.EUnauthorized,
+	})
+}
@@ -13,6 +13,12 @@ var (
 	authorizationIndex  = []byte("authorizationindexv1")
 )
 
+// authorizationPages orders authorizations for listings, which are only
+// ordered by ID.
+var authorizationPages = &pageIndex{
+	bucket: authorizationBucket,
+}
+
 var _ platform.AuthorizationService = (*Client)(nil)
 
 func (c *Client) initializeAuthorizations(ctx context.Context, tx *bolt.Tx) error {
@@ -158,7 +164,7 @@ func (c *Client) FindAuthorizations(ctx context.Context, filter platform.Authori
 
 	as := []*platform.Authorization{}
 	err := c.db.View(func(tx *bolt.Tx) error {
-		auths, err := c.findAuthorizations(ctx, tx, filter)
+		auths, err := c.findAuthorizations(ctx, tx, filter, findOptions(opt))
 		if err != nil {
 			return err
 		}
@@ -176,7 +182,7 @@ func (c *Client) FindAuthorizations(ctx context.Context, filter platform.Authori
 	return as, len(as), nil
 }
 
-func (c *Client) findAuthorizations(ctx context.Context, tx *bolt.Tx, f platform.AuthorizationFilter) ([]*platform.Authorization, error) {
+func (c *Client) findAuthorizations(ctx context.Context, tx *bolt.Tx, f platform.AuthorizationFilter, opts platform.FindOptions) ([]*platform.Authorization, error) {
 	// If the users name was provided, look up user by ID first
 	if f.User != nil {
 		u, err := c.findUserByName(ctx, tx, *f.User)
@@ -186,11 +192,11 @@ func (c *Client) findAuthorizations(ctx context.Context, tx *bolt.Tx, f platform
 		f.UserID = &u.ID
 	}
 
-	as := []*platform.Authorization{}
+	p := &pager{opts: opts}
 	filterFn := filterAuthorizationsFn(f)
-	err := c.forEachAuthorization(ctx, tx, func(a *platform.Authorization) bool {
+	err := c.forEachAuthorization(ctx, tx, opts, func(a *platform.Authorization) bool {
 		if filterFn(a) {
-			as = append(as, a)
+			return p.add(a)
 		}
 		return true
 	})
@@ -198,6 +204,12 @@ func (c *Client) findAuthorizations(ctx context.Context, tx *bolt.Tx, f platform
 		return nil, err
 	}
 
+	page := p.results()
+	as := make([]*platform.Authorization, 0, len(page))
+	for _, a := range page {
+		as = append(as, a.(*platform.Authorization))
+	}
+
 	return as, nil
 }
 
@@ -322,10 +334,14 @@ func decodeAuthorization(b []byte, a *platform.Authorization) error {
 	return nil
 }
 
-// forEachAuthorization will iterate through all authorizations while fn returns true.
-func (c *Client) forEachAuthorization(ctx context.Context, tx *bolt.Tx, fn func(*platform.Authorization) bool) error {
-	cur := tx.Bucket(authorizationBucket).Cursor()
-	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+// forEachAuthorization will iterate through authorizations in the order given by opts while fn returns true.
+func (c *Client) forEachAuthorization(ctx context.Context, tx *bolt.Tx, opts platform.FindOptions, fn func(*platform.Authorization) bool) error {
+	s, err := authorizationPages.scan(tx, opts)
+	if err != nil {
+		return err
+	}
+
+	for v := s.next(); v != nil; v = s.next() {
 		a := &platform.Authorization{}
 
 		if err := decodeAuthorization(v, a); err != nil {
@@ -30,6 +30,13 @@ type Client struct {
 	IDGenerator    platform.IDGenerator
 	TokenGenerator platform.TokenGenerator
 	time           func() time.Time
+
+	// DisableAutoMigrate prevents Open from applying pending schema migrations.
+	DisableAutoMigrate bool
+
+	// DashboardVersionLimit is the number of prior versions kept per
+	// dashboard; DefaultDashboardVersionLimit when zero.
+	DashboardVersionLimit int
 }
 
 // NewClient returns an instance of a Client.
@@ -85,96 +92,115 @@ func (c *Client) Open(ctx context.Context) error {
 	return nil
 }
 
-// initialize creates Buckets that are missing
+// initialize creates the migrations bucket and, unless DisableAutoMigrate is
+// set, brings the schema up to date with the latest registered migration.
 func (c *Client) initialize(ctx context.Context) error {
+	var current int
+	var empty bool
 	if err := c.db.Update(func(tx *bolt.Tx) error {
-		// Always create ID bucket.
-		if err := c.initializeID(tx); err != nil {
+		if err := c.initializeMigrations(ctx, tx); err != nil {
 			return err
 		}
 
-		// Always create Buckets bucket.
-		if err := c.initializeBuckets(ctx, tx); err != nil {
-			return err
-		}
+		current = getSchemaVersion(tx)
+		var err error
+		empty, err = isEmptyStore(tx)
+		return err
+	}); err != nil {
+		return err
+	}
 
-		// Always create Organizations bucket.
-		if err := c.initializeOrganizations(ctx, tx); err != nil {
-			return err
-		}
+	if c.DisableAutoMigrate {
+		return nil
+	}
 
-		// Always create Dashboards bucket.
-		if err := c.initializeDashboards(ctx, tx); err != nil {
-			return err
-		}
+	return c.migrateUp(ctx, current, LatestSchemaVersion(), !empty)
+}
 
-		// Always create User bucket.
-		if err := c.initializeUsers(ctx, tx); err != nil {
-			return err
-		}
+// createInitialBuckets creates the buckets of the original, unversioned schema.
+func createInitialBuckets(ctx context.Context, c *Client, tx *bolt.Tx) error {
+	// Always create ID bucket.
+	if err := c.initializeID(tx); err != nil {
+		return err
+	}
 
-		// Always create Authorization bucket.
-		if err := c.initializeAuthorizations(ctx, tx); err != nil {
-			return err
-		}
+	// Always create Buckets bucket.
+	if err := c.initializeBuckets(ctx, tx); err != nil {
+		return err
+	}
 
-		// Always create Onboarding bucket.
-		if err := c.initializeOnboarding(ctx, tx); err != nil {
-			return err
-		}
+	// Always create Organizations bucket.
+	if err := c.initializeOrganizations(ctx, tx); err != nil {
+		return err
+	}
 
-		// Always create Telegraf Config bucket.
-		if err := c.initializeTelegraf(ctx, tx); err != nil {
-			return err
-		}
+	// Always create Dashboards bucket.
+	if err := c.initializeDashboards(ctx, tx); err != nil {
+		return err
+	}
 
-		// Always create Source bucket.
-		if err := c.initializeSources(ctx, tx); err != nil {
-			return err
-		}
+	// Always create User bucket.
+	if err := c.initializeUsers(ctx, tx); err != nil {
+		return err
+	}
 
-		// Always create Views bucket.
-		if err := c.initializeViews(ctx, tx); err != nil {
-			return err
-		}
+	// Always create Authorization bucket.
+	if err := c.initializeAuthorizations(ctx, tx); err != nil {
+		return err
+	}
 
-		// Always create Macros bucket.
-		if err := c.initializeMacros(ctx, tx); err != nil {
-			return err
-		}
+	// Always create Onboarding bucket.
+	if err := c.initializeOnboarding(ctx, tx); err != nil {
+		return err
+	}
 
-		// Always create Scraper bucket.
-		if err := c.initializeScraperTargets(ctx, tx); err != nil {
-			return err
-		}
+	// Always create Telegraf Config bucket.
+	if err := c.initializeTelegraf(ctx, tx); err != nil {
+		return err
+	}
 
-		// Always create UserResourceMapping bucket.
-		if err := c.initializeUserResourceMappings(ctx, tx); err != nil {
-			return err
-		}
+	// Always create Source bucket.
+	if err := c.initializeSources(ctx, tx); err != nil {
+		return err
+	}
 
-		// Always create labels bucket.
-		if err := c.initializeLabels(ctx, tx); err != nil {
-			return err
-		}
+	// Always create Views bucket.
+	if err := c.initializeViews(ctx, tx); err != nil {
+		return err
+	}
 
-		// Always create Session bucket.
-		if err := c.initializeSessions(ctx, tx); err != nil {
-			return err
-		}
+	// Always create Macros bucket.
+	if err := c.initializeMacros(ctx, tx); err != nil {
+		return err
+	}
 
-		// Always create KeyValueLog bucket.
-		if err := c.initializeKeyValueLog(ctx, tx); err != nil {
-			return err
-		}
+	// Always create Scraper bucket.
+	if err := c.initializeScraperTargets(ctx, tx); err != nil {
+		return err
+	}
 
-		// Always create SecretService bucket.
-		if err := c.initializeSecretService(ctx, tx); err != nil {
-			return err
-		}
+	// Always create UserResourceMapping bucket.
+	if err := c.initializeUserResourceMappings(ctx, tx); err != nil {
+		return err
+	}
 
-		return nil
-	}); err != nil {
+	// Always create labels bucket.
+	if err := c.initializeLabels(ctx, tx); err != nil {
+		return err
+	}
+
+	// Always create Session bucket.
+	if err := c.initializeSessions(ctx, tx); err != nil {
+		return err
+	}
+
+	// Always create KeyValueLog bucket.
+	if err := c.initializeKeyValueLog(ctx, tx); err != nil {
+		return err
+	}
+
+	// Always create SecretService bucket.
+	if err := c.initializeSecretService(ctx, tx); err != nil {
 		return err
 	}
 
@@ -12,10 +12,21 @@ import (
 )
 
 var (
-	bucketBucket = []byte("bucketsv1")
-	bucketIndex  = []byte("bucketindexv1")
+	bucketBucket    = []byte("bucketsv1")
+	bucketIndex     = []byte("bucketindexv1")
+	bucketNameIndex = []byte("bucketnameindexv1")
 )
 
+// bucketPages orders buckets for listings.
+var bucketPages = &pageIndex{
+	bucket:  bucketBucket,
+	indexes: map[string][]byte{"Name": bucketNameIndex},
+	decode: func(v []byte) (platform.Pageable, error) {
+		b := &platform.Bucket{}
+		return b, json.Unmarshal(v, b)
+	},
+}
+
 var _ platform.BucketService = (*Client)(nil)
 var _ platform.BucketOperationLogService = (*Client)(nil)
 
@@ -179,7 +190,7 @@ func (c *Client) FindBucket(ctx context.Context, filter platform.BucketFilter) (
 		}
 
 		filterFn := filterBucketsFn(filter)
-		return c.forEachBucket(ctx, tx, false, func(bkt *platform.Bucket) bool {
+		return c.forEachBucket(ctx, tx, platform.FindOptions{}, func(bkt *platform.Bucket) bool {
 			if filterFn(bkt) {
 				b = bkt
 				return false
@@ -237,7 +248,7 @@ func filterBucketsFn(filter platform.BucketFilter) func(b *platform.Bucket) bool
 // Filters using ID, or OrganizationID and bucket Name should be efficient.
 // Other filters will do a linear scan across all buckets searching for a match.
 func (c *Client) FindBuckets(ctx context.Context, filter platform.BucketFilter, opts ...platform.FindOptions) ([]*platform.Bucket, int, error) {
-	if filter.ID != nil {
+	if filter.ID != nil && filter.Labels.Empty() {
 		b, err := c.FindBucketByID(ctx, *filter.ID)
 		if err != nil {
 			return nil, 0, err
@@ -246,7 +257,7 @@ func (c *Client) FindBuckets(ctx context.Context, filter platform.BucketFilter,
 		return []*platform.Bucket{b}, 1, nil
 	}
 
-	if filter.Name != nil && filter.OrganizationID != nil {
+	if filter.Name != nil && filter.OrganizationID != nil && filter.Labels.Empty() {
 		b, err := c.FindBucketByName(ctx, *filter.OrganizationID, *filter.Name)
 		if err != nil {
 			return nil, 0, err
@@ -284,36 +295,31 @@ func (c *Client) findBuckets(ctx context.Context, tx *bolt.Tx, filter platform.B
 		filter.OrganizationID = &o.ID
 	}
 
-	var offset, limit, count int
-	var descending bool
-	if len(opts) > 0 {
-		offset = opts[0].Offset
-		limit = opts[0].Limit
-		descending = opts[0].Descending
+	labeled, err := c.labelFilter(ctx, tx, filter.Labels)
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
 	}
 
+	p := &pager{opts: findOptions(opts)}
 	filterFn := filterBucketsFn(filter)
-	err := c.forEachBucket(ctx, tx, descending, func(b *platform.Bucket) bool {
-		if filterFn(b) {
-			if count >= offset {
-				bs = append(bs, b)
-			}
-			count++
-		}
-
-		if limit > 0 && len(bs) >= limit {
-			return false
+	err = c.forEachBucket(ctx, tx, p.opts, func(b *platform.Bucket) bool {
+		if filterFn(b) && labeled(b.ID) {
+			return p.add(b)
 		}
-
 		return true
 	})
-
 	if err != nil {
 		return nil, &platform.Error{
 			Err: err,
 		}
 	}
 
+	for _, b := range p.results() {
+		bs = append(bs, b.(*platform.Bucket))
+	}
+
 	return bs, nil
 }
 
@@ -438,6 +444,11 @@ func (c *Client) putBucket(ctx context.Context, tx *bolt.Tx, b *platform.Bucket)
 			Err: err,
 		}
 	}
+	if err := bucketPages.put(tx, encodedID, b); err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
 	if err := tx.Bucket(bucketBucket).Put(encodedID, v); err != nil {
 		return &platform.Error{
 			Err: err,
@@ -460,18 +471,14 @@ func bucketIndexKey(b *platform.Bucket) ([]byte, *platform.Error) {
 	return k, nil
 }
 
-// forEachBucket will iterate through all buckets while fn returns true.
-func (c *Client) forEachBucket(ctx context.Context, tx *bolt.Tx, descending bool, fn func(*platform.Bucket) bool) error {
-	cur := tx.Bucket(bucketBucket).Cursor()
-
-	var k, v []byte
-	if descending {
-		k, v = cur.Last()
-	} else {
-		k, v = cur.First()
+// forEachBucket will iterate through buckets in the order given by opts while fn returns true.
+func (c *Client) forEachBucket(ctx context.Context, tx *bolt.Tx, opts platform.FindOptions, fn func(*platform.Bucket) bool) error {
+	s, err := bucketPages.scan(tx, opts)
+	if err != nil {
+		return err
 	}
 
-	for k != nil {
+	for v := s.next(); v != nil; v = s.next() {
 		b := &platform.Bucket{}
 		if err := json.Unmarshal(v, b); err != nil {
 			return err
@@ -482,12 +489,6 @@ func (c *Client) forEachBucket(ctx context.Context, tx *bolt.Tx, descending bool
 		if !fn(b) {
 			break
 		}
-
-		if descending {
-			k, v = cur.Prev()
-		} else {
-			k, v = cur.Next()
-		}
 	}
 
 	return nil
@@ -588,6 +589,11 @@ func (c *Client) deleteBucket(ctx context.Context, tx *bolt.Tx, id platform.ID)
 			Err:  err,
 		}
 	}
+	if err := bucketPages.delete(tx, encodedID); err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
 	if err := tx.Bucket(bucketBucket).Delete(encodedID); err != nil {
 		return &platform.Error{
 			Err: err,
@@ -601,6 +607,11 @@ func (c *Client) deleteBucket(ctx context.Context, tx *bolt.Tx, id platform.ID)
 			Err: err,
 		}
 	}
+	if err := c.deleteResourceShares(ctx, tx, id); err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
 
 	return nil
 }
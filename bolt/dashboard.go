@@ -15,8 +15,25 @@ var (
 	dashboardBucket         = []byte("dashboardsv2")
 	orgDashboardIndex       = []byte("orgsdashboardsv1")
 	dashboardCellViewBucket = []byte("dashboardcellviewsv1")
+	dashboardNameIndex      = []byte("dashboardnameindexv1")
+	dashboardCreatedIndex   = []byte("dashboardcreatedindexv1")
+	dashboardUpdatedIndex   = []byte("dashboardupdatedindexv1")
 )
 
+// dashboardPages orders dashboards for listings.
+var dashboardPages = &pageIndex{
+	bucket: dashboardBucket,
+	indexes: map[string][]byte{
+		"Name":      dashboardNameIndex,
+		"CreatedAt": dashboardCreatedIndex,
+		"UpdatedAt": dashboardUpdatedIndex,
+	},
+	decode: func(v []byte) (platform.Pageable, error) {
+		d := &platform.Dashboard{}
+		return d, json.Unmarshal(v, d)
+	},
+}
+
 // TODO(desa): what do we want these to be?
 const (
 	dashboardCreatedEvent = "Dashboard Created"
@@ -27,6 +44,8 @@ const (
 	dashboardCellAddedEvent     = "Dashboard Cell Added"
 	dashboardCellRemovedEvent   = "Dashboard Cell Removed"
 	dashboardCellUpdatedEvent   = "Dashboard Cell Updated"
+
+	dashboardCellViewUpdatedEvent = "Dashboard Cell View Updated"
 )
 
 var _ platform.DashboardService = (*Client)(nil)
@@ -104,7 +123,7 @@ func (c *Client) FindDashboard(ctx context.Context, filter platform.DashboardFil
 	var d *platform.Dashboard
 	err := c.db.View(func(tx *bolt.Tx) error {
 		filterFn := filterDashboardsFn(filter)
-		return c.forEachDashboard(ctx, tx, opts[0].Descending, func(dash *platform.Dashboard) bool {
+		return c.forEachDashboard(ctx, tx, findOptions(opts), func(dash *platform.Dashboard) bool {
 			if filterFn(dash) {
 				d = dash
 				return false
@@ -139,13 +158,19 @@ func filterDashboardsFn(filter platform.DashboardFilter) func(d *platform.Dashbo
 		}
 	}
 
+	if filter.OrganizationID != nil {
+		return func(d *platform.Dashboard) bool {
+			return d.OrganizationID == *filter.OrganizationID
+		}
+	}
+
 	return func(d *platform.Dashboard) bool { return true }
 }
 
 // FindDashboards retrives all dashboards that match an arbitrary dashboard filter.
 func (c *Client) FindDashboards(ctx context.Context, filter platform.DashboardFilter, opts platform.FindOptions) ([]*platform.Dashboard, int, error) {
 	ds := []*platform.Dashboard{}
-	if len(filter.IDs) == 1 {
+	if len(filter.IDs) == 1 && filter.Labels.Empty() {
 		d, err := c.FindDashboardByID(ctx, *filter.IDs[0])
 		if err != nil && platform.ErrorCode(err) != platform.ENotFound {
 			return ds, 0, &platform.Error{
@@ -174,93 +199,41 @@ func (c *Client) FindDashboards(ctx context.Context, filter platform.DashboardFi
 		}
 	}
 
-	platform.SortDashboards(opts, ds)
-
 	return ds, len(ds), nil
 }
 
-func (c *Client) findOrganizationDashboards(ctx context.Context, tx *bolt.Tx, orgID platform.ID) ([]*platform.Dashboard, error) {
-	// TODO(desa): support find options.
-	cur := tx.Bucket(orgDashboardIndex).Cursor()
-	prefix, err := orgID.Encode()
-	if err != nil {
-		return nil, err
-	}
-
-	ds := []*platform.Dashboard{}
-	for k, _ := cur.Seek(prefix); bytes.HasPrefix(k, prefix); k, _ = cur.Next() {
-		_, id, err := decodeOrgDashboardIndexKey(k)
-		if err != nil {
-			return nil, err
-		}
-
-		d, err := c.findDashboardByID(ctx, tx, id)
-		if err != nil {
-			return nil, err
-		}
-
-		ds = append(ds, d)
-	}
-
-	return ds, nil
-}
-
-func decodeOrgDashboardIndexKey(indexKey []byte) (orgID platform.ID, dashID platform.ID, err error) {
-	if len(indexKey) != 2*platform.IDLength {
-		return 0, 0, &platform.Error{Code: platform.EInvalid, Msg: "malformed org dashboard index key (please report this error)"}
-	}
-
-	if err := (&orgID).Decode(indexKey[:platform.IDLength]); err != nil {
-		return 0, 0, &platform.Error{Code: platform.EInvalid, Msg: "bad org id", Err: platform.ErrInvalidID}
-	}
-
-	if err := (&dashID).Decode(indexKey[platform.IDLength:]); err != nil {
-		return 0, 0, &platform.Error{Code: platform.EInvalid, Msg: "bad dashboard id", Err: platform.ErrInvalidID}
-	}
-
-	return orgID, dashID, nil
-}
-
-func (c *Client) findDashboards(ctx context.Context, tx *bolt.Tx, filter platform.DashboardFilter, opts ...platform.FindOptions) ([]*platform.Dashboard, error) {
-	if filter.OrganizationID != nil {
-		return c.findOrganizationDashboards(ctx, tx, *filter.OrganizationID)
-	}
-
+func (c *Client) findDashboards(ctx context.Context, tx *bolt.Tx, filter platform.DashboardFilter, opts platform.FindOptions) ([]*platform.Dashboard, error) {
 	if filter.Organization != nil {
 		o, err := c.findOrganizationByName(ctx, tx, *filter.Organization)
 		if err != nil {
 			return nil, err
 		}
-		return c.findOrganizationDashboards(ctx, tx, o.ID)
+		filter.OrganizationID = &o.ID
 	}
 
-	var offset, limit, count int
-	var descending bool
-	if len(opts) > 0 {
-		offset = opts[0].Offset
-		limit = opts[0].Limit
-		descending = opts[0].Descending
+	labeled, err := c.labelFilter(ctx, tx, filter.Labels)
+	if err != nil {
+		return nil, err
 	}
 
-	ds := []*platform.Dashboard{}
+	p := &pager{opts: opts}
 	filterFn := filterDashboardsFn(filter)
-	err := c.forEachDashboard(ctx, tx, descending, func(d *platform.Dashboard) bool {
-		if filterFn(d) {
-			if count >= offset {
-				ds = append(ds, d)
-			}
-			count++
-		}
-		if limit > 0 && len(ds) >= limit {
-			return false
+	err = c.forEachDashboard(ctx, tx, opts, func(d *platform.Dashboard) bool {
+		if filterFn(d) && labeled(d.ID) {
+			return p.add(d)
 		}
 		return true
 	})
-
 	if err != nil {
 		return nil, err
 	}
 
+	page := p.results()
+	ds := make([]*platform.Dashboard, 0, len(page))
+	for _, d := range page {
+		ds = append(ds, d.(*platform.Dashboard))
+	}
+
 	return ds, nil
 }
 
@@ -338,6 +311,10 @@ func (c *Client) ReplaceDashboardCells(ctx context.Context, id platform.ID, cs [
 			}
 		}
 
+		if err := c.putDashboardVersion(ctx, tx, d, dashboardCellsReplacedEvent); err != nil {
+			return err
+		}
+
 		d.Cells = cs
 		if err := c.appendDashboardEventToLog(ctx, tx, d.ID, dashboardCellsReplacedEvent); err != nil {
 			return err
@@ -359,6 +336,9 @@ func (c *Client) addDashboardCell(ctx context.Context, tx *bolt.Tx, id platform.
 	if err != nil {
 		return err
 	}
+	if err := c.putDashboardVersion(ctx, tx, d, dashboardCellAddedEvent); err != nil {
+		return err
+	}
 	cell.ID = c.IDGenerator.ID()
 	if err := c.createCellView(ctx, tx, id, cell.ID, opts.View); err != nil {
 		return err
@@ -414,6 +394,13 @@ func (c *Client) RemoveDashboardCell(ctx context.Context, dashboardID, cellID pl
 			}
 		}
 
+		if err := c.putDashboardVersion(ctx, tx, d, dashboardCellRemovedEvent); err != nil {
+			return &platform.Error{
+				Err: err,
+				Op:  op,
+			}
+		}
+
 		if err := c.deleteDashboardCellView(ctx, tx, d.ID, d.Cells[idx].ID); err != nil {
 			return &platform.Error{
 				Err: err,
@@ -545,6 +532,14 @@ func (c *Client) UpdateDashboardCellView(ctx context.Context, dashboardID, cellI
 			return err
 		}
 
+		d, err := c.findDashboardByID(ctx, tx, dashboardID)
+		if err != nil {
+			return err
+		}
+		if err := c.putDashboardVersion(ctx, tx, d, dashboardCellViewUpdatedEvent); err != nil {
+			return err
+		}
+
 		if err := upd.Apply(view); err != nil {
 			return err
 		}
@@ -599,6 +594,10 @@ func (c *Client) UpdateDashboardCell(ctx context.Context, dashboardID, cellID pl
 			}
 		}
 
+		if err := c.putDashboardVersion(ctx, tx, d, dashboardCellUpdatedEvent); err != nil {
+			return err
+		}
+
 		if err := upd.Apply(d.Cells[idx]); err != nil {
 			return err
 		}
@@ -690,6 +689,9 @@ func (c *Client) putDashboard(ctx context.Context, tx *bolt.Tx, d *platform.Dash
 	if err != nil {
 		return err
 	}
+	if err := dashboardPages.put(tx, encodedID, d); err != nil {
+		return err
+	}
 	if err := tx.Bucket(dashboardBucket).Put(encodedID, v); err != nil {
 		return err
 	}
@@ -702,18 +704,14 @@ func (c *Client) putDashboardWithMeta(ctx context.Context, tx *bolt.Tx, d *platf
 	return c.putDashboard(ctx, tx, d)
 }
 
-// forEachDashboard will iterate through all dashboards while fn returns true.
-func (c *Client) forEachDashboard(ctx context.Context, tx *bolt.Tx, descending bool, fn func(*platform.Dashboard) bool) error {
-	cur := tx.Bucket(dashboardBucket).Cursor()
-
-	var k, v []byte
-	if descending {
-		k, v = cur.Last()
-	} else {
-		k, v = cur.First()
+// forEachDashboard will iterate through dashboards in the order given by opts while fn returns true.
+func (c *Client) forEachDashboard(ctx context.Context, tx *bolt.Tx, opts platform.FindOptions, fn func(*platform.Dashboard) bool) error {
+	s, err := dashboardPages.scan(tx, opts)
+	if err != nil {
+		return err
 	}
 
-	for k != nil {
+	for v := s.next(); v != nil; v = s.next() {
 		d := &platform.Dashboard{}
 		if err := json.Unmarshal(v, d); err != nil {
 			return err
@@ -722,12 +720,6 @@ func (c *Client) forEachDashboard(ctx context.Context, tx *bolt.Tx, descending b
 		if !fn(d) {
 			break
 		}
-
-		if descending {
-			k, v = cur.Prev()
-		} else {
-			k, v = cur.Next()
-		}
 	}
 
 	return nil
@@ -765,6 +757,10 @@ func (c *Client) updateDashboard(ctx context.Context, tx *bolt.Tx, id platform.I
 		return nil, err
 	}
 
+	if err := c.putDashboardVersion(ctx, tx, d, dashboardUpdatedEvent); err != nil {
+		return nil, err
+	}
+
 	if err := upd.Apply(d); err != nil {
 		return nil, err
 	}
@@ -818,6 +814,16 @@ func (c *Client) deleteDashboard(ctx context.Context, tx *bolt.Tx, id platform.I
 		return platform.NewError(platform.WithErrorErr(err))
 	}
 
+	if err := c.deleteDashboardVersions(ctx, tx, d.ID); err != nil {
+		return platform.NewError(platform.WithErrorErr(err))
+	}
+
+	if err := dashboardPages.delete(tx, encodedID); err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+
 	if err := tx.Bucket(dashboardBucket).Delete(encodedID); err != nil {
 		return &platform.Error{
 			Err: err,
@@ -834,6 +840,12 @@ func (c *Client) deleteDashboard(ctx context.Context, tx *bolt.Tx, id platform.I
 		}
 	}
 
+	if err := c.deleteResourceShares(ctx, tx, id); err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+
 	if err := c.appendDashboardEventToLog(ctx, tx, d.ID, dashboardRemovedEvent); err != nil {
 		return &platform.Error{
 			Err: err,
@@ -51,3 +51,43 @@ func initDashboardService(f platformtesting.DashboardFields, t *testing.T) (plat
 func TestDashboardService(t *testing.T) {
 	platformtesting.DashboardService(initDashboardService, t)
 }
+
+func TestDashboardVersionService(t *testing.T) {
+	platformtesting.DashboardVersionService(initDashboardService, t)
+}
+
+func TestClient_DashboardVersionLimit(t *testing.T) {
+	c, closeFn, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("failed to create new bolt client: %v", err)
+	}
+	defer closeFn()
+	c.DashboardVersionLimit = 2
+
+	ctx := context.Background()
+	d := &platform.Dashboard{OrganizationID: platform.ID(1), Name: "d"}
+	if err := c.CreateDashboard(ctx, d); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a", "b", "c", "d"} {
+		name := name
+		if _, err := c.UpdateDashboard(ctx, d.ID, platform.DashboardUpdate{Name: &name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	vs, total, err := c.FindDashboardVersions(ctx, d.ID, platform.FindOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 || vs[0].Version != 4 || vs[1].Version != 3 || vs[0].Dashboard.Name != "c" {
+		t.Fatalf("expected the last two versions, got %+v", vs)
+	}
+
+	if err := c.DeleteDashboard(ctx, d.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.FindDashboardVersion(ctx, d.ID, 4); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected the versions of a deleted dashboard to be removed, got %v", err)
+	}
+}
@@ -0,0 +1,279 @@
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+
+	bolt "github.com/coreos/bbolt"
+	platform "github.com/influxdata/influxdb"
+	platformcontext "github.com/influxdata/influxdb/context"
+)
+
+var dashboardVersionBucket = []byte("dashboardversionsv1")
+
+const dashboardRestoredEvent = "Dashboard Restored"
+
+// DefaultDashboardVersionLimit is the number of versions kept per dashboard
+// when the client sets no limit.
+const DefaultDashboardVersionLimit = 50
+
+var _ platform.DashboardVersionService = (*Client)(nil)
+
+// createDashboardVersionBucket creates the bucket holding the prior versions
+// of dashboards.
+func createDashboardVersionBucket(ctx context.Context, c *Client, tx *bolt.Tx) error {
+	if _, err := tx.CreateBucketIfNotExists(dashboardVersionBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+func dropDashboardVersionBucket(ctx context.Context, c *Client, tx *bolt.Tx) error {
+	return tx.DeleteBucket(dashboardVersionBucket)
+}
+
+func (c *Client) dashboardVersionLimit() int {
+	if c.DashboardVersionLimit > 0 {
+		return c.DashboardVersionLimit
+	}
+	return DefaultDashboardVersionLimit
+}
+
+// encodeDashboardVersionKey returns the key of a version of the dashboard id.
+// Versions are big endian so that they sort in order under the dashboard.
+func encodeDashboardVersionKey(id platform.ID, version int) ([]byte, error) {
+	did, err := id.Encode()
+	if err != nil {
+		return nil, err
+	}
+	key := make([]byte, len(did)+8)
+	copy(key, did)
+	binary.BigEndian.PutUint64(key[len(did):], uint64(version))
+	return key, nil
+}
+
+// dashboardVersionKeys returns the keys of the versions of the dashboard id, oldest first.
+func (c *Client) dashboardVersionKeys(ctx context.Context, tx *bolt.Tx, id platform.ID) ([][]byte, error) {
+	prefix, err := id.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	var keys [][]byte
+	cur := tx.Bucket(dashboardVersionBucket).Cursor()
+	for k, _ := cur.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = cur.Next() {
+		keys = append(keys, append([]byte(nil), k...))
+	}
+	return keys, nil
+}
+
+// putDashboardVersion records d, the state of a dashboard about to be
+// changed, as its newest version and prunes the versions beyond the limit.
+func (c *Client) putDashboardVersion(ctx context.Context, tx *bolt.Tx, d *platform.Dashboard, change string) error {
+	keys, err := c.dashboardVersionKeys(ctx, tx, d.ID)
+	if err != nil {
+		return err
+	}
+
+	version := 1
+	if len(keys) > 0 {
+		last := keys[len(keys)-1]
+		version = int(binary.BigEndian.Uint64(last[len(last)-8:])) + 1
+	}
+
+	v := &platform.DashboardVersion{
+		Version: version,
+		ProtoDashboard: platform.ProtoDashboard{
+			Dashboard: *d,
+			Views:     make(map[string]platform.View, len(d.Cells)),
+		},
+		Change: change,
+		Time:   c.time(),
+	}
+	if a, err := platformcontext.GetAuthorizer(ctx); err == nil {
+		v.UserID = a.GetUserID()
+	}
+	for _, cell := range d.Cells {
+		view, err := c.findDashboardCellView(ctx, tx, d.ID, cell.ID)
+		if platform.ErrorCode(err) == platform.ENotFound {
+			continue
+		} else if err != nil {
+			return err
+		}
+		v.Views[cell.ID.String()] = *view
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	k, err := encodeDashboardVersionKey(d.ID, version)
+	if err != nil {
+		return err
+	}
+	bkt := tx.Bucket(dashboardVersionBucket)
+	if err := bkt.Put(k, b); err != nil {
+		return err
+	}
+
+	keys = append(keys, k)
+	for len(keys) > c.dashboardVersionLimit() {
+		if err := bkt.Delete(keys[0]); err != nil {
+			return err
+		}
+		keys = keys[1:]
+	}
+	return nil
+}
+
+// deleteDashboardVersions removes every version of the dashboard id.
+func (c *Client) deleteDashboardVersions(ctx context.Context, tx *bolt.Tx, id platform.ID) error {
+	keys, err := c.dashboardVersionKeys(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	bkt := tx.Bucket(dashboardVersionBucket)
+	for _, k := range keys {
+		if err := bkt.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindDashboardVersions returns the versions of the dashboard id, newest first.
+func (c *Client) FindDashboardVersions(ctx context.Context, id platform.ID, opts platform.FindOptions) ([]*platform.DashboardVersion, int, error) {
+	vs := []*platform.DashboardVersion{}
+	var total int
+	err := c.db.View(func(tx *bolt.Tx) error {
+		if _, err := c.findDashboardByID(ctx, tx, id); err != nil {
+			return err
+		}
+
+		keys, err := c.dashboardVersionKeys(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		total = len(keys)
+
+		bkt := tx.Bucket(dashboardVersionBucket)
+		for i := len(keys) - 1 - opts.Offset; i >= 0; i-- {
+			if opts.Limit > 0 && len(vs) >= opts.Limit {
+				break
+			}
+			v := &platform.DashboardVersion{}
+			if err := json.Unmarshal(bkt.Get(keys[i]), v); err != nil {
+				return err
+			}
+			vs = append(vs, v)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, &platform.Error{
+			Err: err,
+			Op:  getOp(platform.OpFindDashboardVersions),
+		}
+	}
+	return vs, total, nil
+}
+
+// FindDashboardVersion returns a single version of the dashboard id.
+func (c *Client) FindDashboardVersion(ctx context.Context, id platform.ID, version int) (*platform.DashboardVersion, error) {
+	var v *platform.DashboardVersion
+	err := c.db.View(func(tx *bolt.Tx) error {
+		var err error
+		v, err = c.findDashboardVersion(ctx, tx, id, version)
+		return err
+	})
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+			Op:  getOp(platform.OpFindDashboardVersion),
+		}
+	}
+	return v, nil
+}
+
+func (c *Client) findDashboardVersion(ctx context.Context, tx *bolt.Tx, id platform.ID, version int) (*platform.DashboardVersion, error) {
+	if version <= 0 {
+		return nil, &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  platform.ErrDashboardVersionNotFound,
+		}
+	}
+
+	k, err := encodeDashboardVersionKey(id, version)
+	if err != nil {
+		return nil, err
+	}
+	b := tx.Bucket(dashboardVersionBucket).Get(k)
+	if len(b) == 0 {
+		return nil, &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  platform.ErrDashboardVersionNotFound,
+		}
+	}
+
+	v := &platform.DashboardVersion{}
+	if err := json.Unmarshal(b, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// RestoreDashboardVersion returns the name, description and cells of the
+// dashboard id, and the views of its cells, to a version. The state replaced
+// is recorded as the newest version, so a restore can itself be undone.
+func (c *Client) RestoreDashboardVersion(ctx context.Context, id platform.ID, version int) (*platform.Dashboard, error) {
+	var d *platform.Dashboard
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		v, err := c.findDashboardVersion(ctx, tx, id, version)
+		if err != nil {
+			return err
+		}
+
+		d, err = c.findDashboardByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := c.putDashboardVersion(ctx, tx, d, dashboardRestoredEvent); err != nil {
+			return err
+		}
+
+		for _, cell := range d.Cells {
+			if err := c.deleteDashboardCellView(ctx, tx, d.ID, cell.ID); err != nil {
+				return err
+			}
+		}
+
+		d.Name = v.Dashboard.Name
+		d.Description = v.Dashboard.Description
+		d.Cells = v.Dashboard.Cells
+		for _, cell := range d.Cells {
+			var view *platform.View
+			if cv, ok := v.Views[cell.ID.String()]; ok {
+				view = &cv
+			}
+			if err := c.createCellView(ctx, tx, d.ID, cell.ID, view); err != nil {
+				return err
+			}
+		}
+
+		if err := c.appendDashboardEventToLog(ctx, tx, d.ID, dashboardRestoredEvent); err != nil {
+			return err
+		}
+
+		return c.putDashboardWithMeta(ctx, tx, d)
+	})
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+			Op:  getOp(platform.OpRestoreDashboardVersion),
+		}
+	}
+	return d, nil
+}
@@ -12,6 +12,8 @@ import (
 var (
 	labelBucket        = []byte("labelsv1")
 	labelMappingBucket = []byte("labelmappingsv1")
+	// labelResourceIndex maps label ID + resource ID to the resource type.
+	labelResourceIndex = []byte("labelresourcesv1")
 )
 
 func (c *Client) initializeLabels(ctx context.Context, tx *bolt.Tx) error {
@@ -182,6 +184,29 @@ func (c *Client) FindResourceLabels(ctx context.Context, filter influxdb.LabelMa
 	return ls, nil
 }
 
+// FindLabelResources returns the IDs of the resources of type rt carrying the labels selected by sel.
+func (c *Client) FindLabelResources(ctx context.Context, rt influxdb.ResourceType, sel influxdb.LabelSelector) ([]influxdb.ID, error) {
+	if sel.Empty() {
+		return nil, &influxdb.Error{Code: influxdb.EInvalid, Msg: "selector requires a label"}
+	}
+
+	var rids []influxdb.ID
+	err := c.db.View(func(tx *bolt.Tx) error {
+		var err error
+		rids, err = c.labeledResources(ctx, tx, rt, sel)
+		return err
+	})
+
+	if err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+			Op:  getOp(influxdb.OpFindLabelMapping),
+		}
+	}
+
+	return rids, nil
+}
+
 // CreateLabelMapping creates a new mapping between a resource and a label.
 func (c *Client) CreateLabelMapping(ctx context.Context, m *influxdb.LabelMapping) error {
 	_, err := c.FindLabelByID(ctx, m.LabelID)
@@ -234,6 +259,12 @@ func (c *Client) deleteLabelMapping(ctx context.Context, tx *bolt.Tx, m *influxd
 		}
 	}
 
+	if err := tx.Bucket(labelResourceIndex).Delete(labelResourceIndexKey(key)); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
 	return nil
 }
 
@@ -415,6 +446,12 @@ func (c *Client) putLabelMapping(ctx context.Context, tx *bolt.Tx, m *influxdb.L
 		}
 	}
 
+	if err := tx.Bucket(labelResourceIndex).Put(labelResourceIndexKey(key), []byte(m.ResourceType)); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
 	return nil
 }
 
@@ -466,3 +503,118 @@ func (c *Client) deleteLabel(ctx context.Context, tx *bolt.Tx, id influxdb.ID) e
 // 	}
 // 	return nil
 // }
+
+// labelResourceIndexKey returns the index key of the label mapping with key mappingKey.
+// Mappings are keyed by resource ID then label ID; the index reverses the two.
+func labelResourceIndexKey(mappingKey []byte) []byte {
+	k := make([]byte, len(mappingKey))
+	copy(k, mappingKey[influxdb.IDLength:])
+	copy(k[influxdb.IDLength:], mappingKey[:influxdb.IDLength])
+	return k
+}
+
+// createLabelResourceIndex indexes every existing label mapping by label.
+func createLabelResourceIndex(ctx context.Context, c *Client, tx *bolt.Tx) error {
+	idx, err := tx.CreateBucketIfNotExists(labelResourceIndex)
+	if err != nil {
+		return err
+	}
+
+	return tx.Bucket(labelMappingBucket).ForEach(func(k, v []byte) error {
+		if len(k) != 2*influxdb.IDLength {
+			return nil
+		}
+
+		m := &influxdb.LabelMapping{}
+		if err := json.Unmarshal(v, m); err != nil {
+			return err
+		}
+		return idx.Put(labelResourceIndexKey(k), []byte(m.ResourceType))
+	})
+}
+
+func dropLabelResourceIndex(ctx context.Context, c *Client, tx *bolt.Tx) error {
+	return tx.DeleteBucket(labelResourceIndex)
+}
+
+// labeledResources returns the IDs of the resources of type rt, or of any type
+// if rt is empty, carrying the labels selected by sel, which must not be empty.
+// Resources are looked up in the label resource index, once per selected label.
+func (c *Client) labeledResources(ctx context.Context, tx *bolt.Tx, rt influxdb.ResourceType, sel influxdb.LabelSelector) ([]influxdb.ID, error) {
+
+	labelIDs := map[string][]influxdb.ID{}
+	for _, name := range sel.Names {
+		labelIDs[name] = nil
+	}
+	err := c.forEachLabel(ctx, tx, func(l *influxdb.Label) bool {
+		if ids, ok := labelIDs[l.Name]; ok {
+			labelIDs[l.Name] = append(ids, l.ID)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// matches counts the selected label names carried by each resource.
+	matches := map[influxdb.ID]int{}
+	cur := tx.Bucket(labelResourceIndex).Cursor()
+	for _, ids := range labelIDs {
+		carrying := map[influxdb.ID]bool{}
+		for _, id := range ids {
+			prefix, err := id.Encode()
+			if err != nil {
+				return nil, err
+			}
+
+			for k, v := cur.Seek(prefix); bytes.HasPrefix(k, prefix); k, v = cur.Next() {
+				if rt != "" && influxdb.ResourceType(v) != rt {
+					continue
+				}
+
+				var rid influxdb.ID
+				if err := rid.Decode(k[influxdb.IDLength:]); err != nil {
+					return nil, err
+				}
+				carrying[rid] = true
+			}
+		}
+
+		for rid := range carrying {
+			matches[rid]++
+		}
+	}
+
+	want := len(labelIDs)
+	if sel.Any {
+		want = 1
+	}
+	var rids []influxdb.ID
+	for rid, n := range matches {
+		if n >= want {
+			rids = append(rids, rid)
+		}
+	}
+	return rids, nil
+}
+
+// labelFilter returns a function reporting whether the resource with the
+// given ID carries the labels selected by sel.
+func (c *Client) labelFilter(ctx context.Context, tx *bolt.Tx, sel influxdb.LabelSelector) (func(influxdb.ID) bool, error) {
+	if sel.Empty() {
+		return func(influxdb.ID) bool { return true }, nil
+	}
+
+	rids, err := c.labeledResources(ctx, tx, "", sel)
+	if err != nil {
+		return nil, err
+	}
+
+	labeled := make(map[influxdb.ID]bool, len(rids))
+	for _, rid := range rids {
+		labeled[rid] = true
+	}
+	return func(id influxdb.ID) bool {
+		return labeled[id]
+	}, nil
+}
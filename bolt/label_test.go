@@ -2,6 +2,9 @@ package bolt_test
 
 import (
 	"context"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 
 	platform "github.com/influxdata/influxdb"
@@ -42,3 +45,124 @@ func initLabelService(f platformtesting.LabelFields, t *testing.T) (platform.Lab
 func TestLabelService_LabelService(t *testing.T) {
 	platformtesting.LabelService(initLabelService, t)
 }
+
+func TestClient_FindByLabels(t *testing.T) {
+	c, closeFn, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("failed to create new bolt client: %v", err)
+	}
+	defer closeFn()
+
+	ctx := context.Background()
+	org := &platform.Organization{Name: "org"}
+	if err := c.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+
+	labels := map[string]*platform.Label{}
+	for _, name := range []string{"prod", "iot"} {
+		l := &platform.Label{Name: name}
+		if err := c.CreateLabel(ctx, l); err != nil {
+			t.Fatal(err)
+		}
+		labels[name] = l
+	}
+
+	// Each bucket is named after the labels it carries.
+	for _, name := range []string{"prod", "iot", "prod,iot", "none"} {
+		b := &platform.Bucket{Name: name, OrganizationID: org.ID}
+		if err := c.CreateBucket(ctx, b); err != nil {
+			t.Fatal(err)
+		}
+		for _, ln := range strings.Split(name, ",") {
+			if l, ok := labels[ln]; ok {
+				m := &platform.LabelMapping{LabelID: l.ID, ResourceID: b.ID, ResourceType: platform.BucketsResourceType}
+				if err := c.CreateLabelMapping(ctx, m); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+	}
+
+	find := func(sel platform.LabelSelector) string {
+		bs, _, err := c.FindBuckets(ctx, platform.BucketFilter{OrganizationID: &org.ID, Labels: sel})
+		if err != nil {
+			t.Fatalf("unable to find buckets: %v", err)
+		}
+		names := make([]string, 0, len(bs))
+		for _, b := range bs {
+			names = append(names, b.Name)
+		}
+		sort.Strings(names)
+		return strings.Join(names, " ")
+	}
+
+	if got, want := find(platform.LabelSelector{Names: []string{"prod", "iot"}}), "prod,iot"; got != want {
+		t.Errorf("buckets with all labels = %q, want %q", got, want)
+	}
+	if got, want := find(platform.LabelSelector{Names: []string{"prod", "iot"}, Any: true}), "iot prod prod,iot"; got != want {
+		t.Errorf("buckets with any label = %q, want %q", got, want)
+	}
+	if got, want := find(platform.LabelSelector{Names: []string{"missing"}, Any: true}), ""; got != want {
+		t.Errorf("buckets with unknown label = %q, want %q", got, want)
+	}
+
+	iot, err := c.FindBucketByName(ctx, org.ID, "iot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bs, _, err := c.FindBuckets(ctx, platform.BucketFilter{ID: &iot.ID, Labels: platform.LabelSelector{Names: []string{"prod"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bs) != 0 {
+		t.Errorf("expected bucket iot not to carry prod, got %v", bs)
+	}
+
+	// Removing a mapping removes the resource from the index.
+	m := &platform.LabelMapping{LabelID: labels["iot"].ID, ResourceID: iot.ID, ResourceType: platform.BucketsResourceType}
+	if err := c.DeleteLabelMapping(ctx, m); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := find(platform.LabelSelector{Names: []string{"iot"}}), "prod,iot"; got != want {
+		t.Errorf("buckets with iot after unmapping = %q, want %q", got, want)
+	}
+}
+
+func TestClient_FindLabelResources(t *testing.T) {
+	c, closeFn, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("failed to create new bolt client: %v", err)
+	}
+	defer closeFn()
+
+	ctx := context.Background()
+	l := &platform.Label{Name: "prod"}
+	if err := c.CreateLabel(ctx, l); err != nil {
+		t.Fatal(err)
+	}
+
+	mappings := []*platform.LabelMapping{
+		{LabelID: l.ID, ResourceID: platform.ID(1), ResourceType: platform.BucketsResourceType},
+		{LabelID: l.ID, ResourceID: platform.ID(2), ResourceType: platform.TasksResourceType},
+		{LabelID: l.ID, ResourceID: platform.ID(3), ResourceType: platform.TasksResourceType},
+	}
+	for _, m := range mappings {
+		if err := c.CreateLabelMapping(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ids, err := c.FindLabelResources(ctx, platform.TasksResourceType, platform.LabelSelector{Names: []string{"prod"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	if want := []platform.ID{2, 3}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("tasks with label = %v, want %v", ids, want)
+	}
+
+	if _, err := c.FindLabelResources(ctx, platform.TasksResourceType, platform.LabelSelector{}); platform.ErrorCode(err) != platform.EInvalid {
+		t.Errorf("expected an invalid error for an empty selector, got %v", err)
+	}
+}
@@ -1,7 +1,6 @@
 package bolt
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 
@@ -12,8 +11,19 @@ import (
 var (
 	macroBucket    = []byte("macrosv1")
 	macroOrgsIndex = []byte("macroorgsv1")
+	macroNameIndex = []byte("macronameindexv1")
 )
 
+// macroPages orders macros for listings.
+var macroPages = &pageIndex{
+	bucket:  macroBucket,
+	indexes: map[string][]byte{"Name": macroNameIndex},
+	decode: func(v []byte) (platform.Pageable, error) {
+		m := &platform.Macro{}
+		return m, json.Unmarshal(v, m)
+	},
+}
+
 func (c *Client) initializeMacros(ctx context.Context, tx *bolt.Tx) error {
 	if _, err := tx.CreateBucketIfNotExists([]byte(macroBucket)); err != nil {
 		return err
@@ -24,85 +34,38 @@ func (c *Client) initializeMacros(ctx context.Context, tx *bolt.Tx) error {
 	return nil
 }
 
-func decodeMacroOrgsIndexKey(indexKey []byte) (orgID platform.ID, macroID platform.ID, err error) {
-	if len(indexKey) != 2*platform.IDLength {
-		return 0, 0, &platform.Error{
-			Code: platform.EInvalid,
-			Msg:  "malformed macro orgs index key (please report this error)",
-		}
-	}
-
-	if err := (&orgID).Decode(indexKey[:platform.IDLength]); err != nil {
-		return 0, 0, &platform.Error{
-			Code: platform.EInvalid,
-			Msg:  "bad org id",
-			Err:  platform.ErrInvalidID,
-		}
-	}
-
-	if err := (&macroID).Decode(indexKey[platform.IDLength:]); err != nil {
-		return 0, 0, &platform.Error{
-			Code: platform.EInvalid,
-			Msg:  "bad macro id",
-			Err:  platform.ErrInvalidID,
-		}
-	}
-
-	return orgID, macroID, nil
-}
-
-func (c *Client) findOrganizationMacros(ctx context.Context, tx *bolt.Tx, orgID platform.ID) ([]*platform.Macro, error) {
-	// TODO(leodido): support find options
-	cur := tx.Bucket(macroOrgsIndex).Cursor()
-	prefix, err := orgID.Encode()
-	if err != nil {
-		return nil, err
-	}
-
-	macros := []*platform.Macro{}
-	for k, _ := cur.Seek(prefix); bytes.HasPrefix(k, prefix); k, _ = cur.Next() {
-		_, id, err := decodeMacroOrgsIndexKey(k)
-		if err != nil {
-			return nil, err
-		}
-
-		m, err := c.findMacroByID(ctx, tx, id)
-		if err != nil {
-			return nil, err
-		}
-
-		macros = append(macros, m)
-	}
-
-	return macros, nil
-}
-
-func (c *Client) findMacros(ctx context.Context, tx *bolt.Tx, filter platform.MacroFilter) ([]*platform.Macro, error) {
-	if filter.OrganizationID != nil {
-		return c.findOrganizationMacros(ctx, tx, *filter.OrganizationID)
-	}
-
+func (c *Client) findMacros(ctx context.Context, tx *bolt.Tx, filter platform.MacroFilter, opts platform.FindOptions) ([]*platform.Macro, error) {
 	if filter.Organization != nil {
 		o, err := c.findOrganizationByName(ctx, tx, *filter.Organization)
 		if err != nil {
 			return nil, err
 		}
-		return c.findOrganizationMacros(ctx, tx, o.ID)
+		filter.OrganizationID = &o.ID
 	}
 
-	macros := []*platform.Macro{}
+	labeled, err := c.labelFilter(ctx, tx, filter.Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &pager{opts: opts}
 	filterFn := filterMacrosFn(filter)
-	err := c.forEachMacro(ctx, tx, func(m *platform.Macro) bool {
-		if filterFn(m) {
-			macros = append(macros, m)
+	err = c.forEachMacro(ctx, tx, opts, func(m *platform.Macro) bool {
+		if filterFn(m) && labeled(m.ID) {
+			return p.add(m)
 		}
 		return true
 	})
-
 	if err != nil {
 		return nil, err
 	}
 
+	page := p.results()
+	macros := make([]*platform.Macro, 0, len(page))
+	for _, m := range page {
+		macros = append(macros, m.(*platform.Macro))
+	}
+
 	return macros, nil
 }
 
@@ -122,10 +85,14 @@ func filterMacrosFn(filter platform.MacroFilter) func(m *platform.Macro) bool {
 	return func(m *platform.Macro) bool { return true }
 }
 
-// forEachMacro will iterate through all macros while fn returns true.
-func (c *Client) forEachMacro(ctx context.Context, tx *bolt.Tx, fn func(*platform.Macro) bool) error {
-	cur := tx.Bucket(macroBucket).Cursor()
-	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+// forEachMacro will iterate through macros in the order given by opts while fn returns true.
+func (c *Client) forEachMacro(ctx context.Context, tx *bolt.Tx, opts platform.FindOptions, fn func(*platform.Macro) bool) error {
+	s, err := macroPages.scan(tx, opts)
+	if err != nil {
+		return err
+	}
+
+	for v := s.next(); v != nil; v = s.next() {
 		m := &platform.Macro{}
 		if err := json.Unmarshal(v, m); err != nil {
 			return err
@@ -140,11 +107,10 @@ func (c *Client) forEachMacro(ctx context.Context, tx *bolt.Tx, fn func(*platfor
 
 // FindMacros returns all macros in the store
 func (c *Client) FindMacros(ctx context.Context, filter platform.MacroFilter, opt ...platform.FindOptions) ([]*platform.Macro, error) {
-	// todo(leodido) > handle find options
 	op := getOp(platform.OpFindMacros)
 	res := []*platform.Macro{}
 	err := c.db.View(func(tx *bolt.Tx) error {
-		macros, err := c.findMacros(ctx, tx, filter)
+		macros, err := c.findMacros(ctx, tx, filter, findOptions(opt))
 		if err != nil && platform.ErrorCode(err) != platform.ENotFound {
 			return err
 		}
@@ -315,6 +281,12 @@ func (c *Client) putMacro(ctx context.Context, tx *bolt.Tx, macro *platform.Macr
 		}
 	}
 
+	if err := macroPages.put(tx, encID, macro); err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+
 	if err := tx.Bucket(macroBucket).Put(encID, m); err != nil {
 		return &platform.Error{
 			Err: err,
@@ -384,6 +356,13 @@ func (c *Client) DeleteMacro(ctx context.Context, id platform.ID) error {
 			}
 		}
 
+		if err := macroPages.delete(tx, encID); err != nil {
+			return &platform.Error{
+				Op:  op,
+				Err: err,
+			}
+		}
+
 		if err := tx.Bucket(macroBucket).Delete(encID); err != nil {
 			return &platform.Error{
 				Op:  op,
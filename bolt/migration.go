@@ -0,0 +1,267 @@
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	bolt "github.com/coreos/bbolt"
+	"go.uber.org/zap"
+)
+
+var (
+	migrationBucket  = []byte("migrationsv1")
+	schemaVersionKey = []byte("version")
+)
+
+// backupTimeFormat is the timestamp format used in the names of pre-migration backups.
+const backupTimeFormat = "20060102T150405Z"
+
+var errMigrationRegistry = errors.New("migration registry is not contiguous (please report this error)")
+
+// Migration is a single versioned change to the layout of the bolt metadata store.
+// Up moves the schema from Version-1 to Version and Down reverts it. Both are run
+// inside the same transaction that records the new schema version, so a failed
+// step leaves the store untouched.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, c *Client, tx *bolt.Tx) error
+	// Down is nil when the migration cannot be reverted.
+	Down func(ctx context.Context, c *Client, tx *bolt.Tx) error
+}
+
+// migrations is the ordered registry of schema migrations. Versions must start
+// at 1 and increase by one; new migrations are only ever appended.
+var migrations = []Migration{
+	{
+		Version:     1,
+		Description: "create initial buckets",
+		Up:          createInitialBuckets,
+	},
+	{
+		Version:     2,
+		Description: "index label mappings by label",
+		Up:          createLabelResourceIndex,
+		Down:        dropLabelResourceIndex,
+	},
+	{
+		Version:     3,
+		Description: "create share grant buckets",
+		Up:          createShareBuckets,
+		Down:        dropShareBuckets,
+	},
+	{
+		Version:     4,
+		Description: "create scraper status bucket",
+		Up:          createScraperStatusBucket,
+		Down:        dropScraperStatusBucket,
+	},
+	{
+		Version:     5,
+		Description: "create telegraf agent bucket",
+		Up:          createTelegrafAgentBucket,
+		Down:        dropTelegrafAgentBucket,
+	},
+	{
+		Version:     6,
+		Description: "create dashboard version bucket",
+		Up:          createDashboardVersionBucket,
+		Down:        dropDashboardVersionBucket,
+	},
+	{
+		Version:     7,
+		Description: "index resources by sort field",
+		Up:          createPageIndexes,
+		Down:        dropPageIndexes,
+	},
+	{
+		Version:     8,
+		Description: "index telegraf configs and scraper targets by name",
+		Up:          createTelegrafScraperPageIndexes,
+		Down:        dropTelegrafScraperPageIndexes,
+	},
+}
+
+// Migrations returns the registered schema migrations in version order.
+func Migrations() []Migration {
+	ms := make([]Migration, len(migrations))
+	copy(ms, migrations)
+	return ms
+}
+
+// LatestSchemaVersion returns the schema version reached by applying every registered migration.
+func LatestSchemaVersion() int {
+	return len(migrations)
+}
+
+func (c *Client) initializeMigrations(ctx context.Context, tx *bolt.Tx) error {
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			return errMigrationRegistry
+		}
+	}
+
+	if _, err := tx.CreateBucketIfNotExists(migrationBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// SchemaVersion returns the schema version currently recorded in the store.
+// A store that predates migrations reports version 0.
+func (c *Client) SchemaVersion(ctx context.Context) (int, error) {
+	var v int
+	err := c.db.View(func(tx *bolt.Tx) error {
+		v = getSchemaVersion(tx)
+		return nil
+	})
+	return v, err
+}
+
+func getSchemaVersion(tx *bolt.Tx) int {
+	b := tx.Bucket(migrationBucket)
+	if b == nil {
+		return 0
+	}
+
+	v := b.Get(schemaVersionKey)
+	if len(v) != 8 {
+		return 0
+	}
+	return int(binary.BigEndian.Uint64(v))
+}
+
+func putSchemaVersion(tx *bolt.Tx, version int) error {
+	v := make([]byte, 8)
+	binary.BigEndian.PutUint64(v, uint64(version))
+	return tx.Bucket(migrationBucket).Put(schemaVersionKey, v)
+}
+
+// isEmptyStore reports whether the store holds nothing beyond the migrations bucket.
+func isEmptyStore(tx *bolt.Tx) (bool, error) {
+	empty := true
+	err := tx.ForEach(func(name []byte, _ *bolt.Bucket) error {
+		if !bytes.Equal(name, migrationBucket) {
+			empty = false
+		}
+		return nil
+	})
+	return empty, err
+}
+
+// schemaState returns the current schema version and whether the store is empty.
+func (c *Client) schemaState(ctx context.Context) (version int, empty bool, err error) {
+	err = c.db.View(func(tx *bolt.Tx) error {
+		version = getSchemaVersion(tx)
+		empty, err = isEmptyStore(tx)
+		return err
+	})
+	return version, empty, err
+}
+
+func validateSchemaVersion(target int) error {
+	if target < 0 || target > LatestSchemaVersion() {
+		return fmt.Errorf("unknown schema version %d; latest is %d", target, LatestSchemaVersion())
+	}
+	return nil
+}
+
+// MigrateUp applies, in order, every migration above the current schema version up to and including target.
+func (c *Client) MigrateUp(ctx context.Context, target int) error {
+	if err := validateSchemaVersion(target); err != nil {
+		return err
+	}
+
+	current, empty, err := c.schemaState(ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.migrateUp(ctx, current, target, !empty)
+}
+
+func (c *Client) migrateUp(ctx context.Context, current, target int, backup bool) error {
+	if current >= target {
+		return nil
+	}
+
+	if backup {
+		if err := c.backup(ctx, current); err != nil {
+			return err
+		}
+	}
+
+	for _, m := range migrations[current:target] {
+		m := m
+		c.Logger.Info("Applying schema migration", zap.Int("version", m.Version), zap.String("description", m.Description))
+		if err := c.db.Update(func(tx *bolt.Tx) error {
+			if err := m.Up(ctx, c, tx); err != nil {
+				return err
+			}
+			return putSchemaVersion(tx, m.Version)
+		}); err != nil {
+			return fmt.Errorf("schema migration %d (%s) failed: %v", m.Version, m.Description, err)
+		}
+	}
+
+	return nil
+}
+
+// MigrateDown reverts, in reverse order, every applied migration above target.
+func (c *Client) MigrateDown(ctx context.Context, target int) error {
+	if err := validateSchemaVersion(target); err != nil {
+		return err
+	}
+
+	current, empty, err := c.schemaState(ctx)
+	if err != nil {
+		return err
+	}
+	if current <= target {
+		return nil
+	}
+
+	// Check every step before touching the store so a down migration is never left half done.
+	for _, m := range migrations[target:current] {
+		if m.Down == nil {
+			return fmt.Errorf("schema migration %d (%s) cannot be reverted", m.Version, m.Description)
+		}
+	}
+
+	if !empty {
+		if err := c.backup(ctx, current); err != nil {
+			return err
+		}
+	}
+
+	for i := current - 1; i >= target; i-- {
+		m := migrations[i]
+		c.Logger.Info("Reverting schema migration", zap.Int("version", m.Version), zap.String("description", m.Description))
+		if err := c.db.Update(func(tx *bolt.Tx) error {
+			if err := m.Down(ctx, c, tx); err != nil {
+				return err
+			}
+			return putSchemaVersion(tx, m.Version-1)
+		}); err != nil {
+			return fmt.Errorf("reverting schema migration %d (%s) failed: %v", m.Version, m.Description, err)
+		}
+	}
+
+	return nil
+}
+
+// backup copies the bolt file next to itself before a migration away from version runs.
+func (c *Client) backup(ctx context.Context, version int) error {
+	path := fmt.Sprintf("%s.v%d-%s.bak", c.Path, version, c.time().UTC().Format(backupTimeFormat))
+	if err := c.db.View(func(tx *bolt.Tx) error {
+		return tx.CopyFile(path, 0600)
+	}); err != nil {
+		return fmt.Errorf("unable to back up %s before migrating: %v", c.Path, err)
+	}
+
+	c.Logger.Info("Backed up bolt file before schema migration", zap.String("path", path))
+	return nil
+}
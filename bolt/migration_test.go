@@ -0,0 +1,322 @@
+package bolt_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	bbolt "github.com/coreos/bbolt"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/bolt"
+)
+
+func TestClient_MigratesOnOpen(t *testing.T) {
+	c, closeFn, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("failed to create new bolt client: %v", err)
+	}
+	defer closeFn()
+
+	v, err := c.SchemaVersion(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error getting schema version: %v", err)
+	}
+	if want := bolt.LatestSchemaVersion(); v != want {
+		t.Fatalf("expected schema version %d, got %d", want, v)
+	}
+
+	backups, err := filepath.Glob(c.Path + ".v*.bak")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 0 {
+		t.Fatalf("expected no backup of a new store, got %v", backups)
+	}
+}
+
+func TestClient_MigrateLegacyStore(t *testing.T) {
+	c, closeFn, err := newTestClient()
+	if err != nil {
+		t.Fatalf("failed to create new bolt client: %v", err)
+	}
+	defer closeFn()
+
+	// Write a store that predates schema versioning.
+	db, err := bbolt.Open(c.Path, 0600, &bbolt.Options{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("unable to open bolt file: %v", err)
+	}
+	if err := db.Update(func(tx *bbolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte("usersv1"))
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte("0000000000000001"), []byte(`{"id":"0000000000000001","name":"user"}`))
+	}); err != nil {
+		t.Fatalf("unable to populate bolt file: %v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	c.DisableAutoMigrate = true
+	if err := c.Open(ctx); err != nil {
+		t.Fatalf("unable to open client: %v", err)
+	}
+
+	if v, err := c.SchemaVersion(ctx); err != nil {
+		t.Fatalf("unexpected error getting schema version: %v", err)
+	} else if v != 0 {
+		t.Fatalf("expected legacy store to be at schema version 0, got %d", v)
+	}
+
+	if err := c.MigrateUp(ctx, bolt.LatestSchemaVersion()); err != nil {
+		t.Fatalf("unexpected error migrating up: %v", err)
+	}
+
+	if v, err := c.SchemaVersion(ctx); err != nil {
+		t.Fatalf("unexpected error getting schema version: %v", err)
+	} else if want := bolt.LatestSchemaVersion(); v != want {
+		t.Fatalf("expected schema version %d, got %d", want, v)
+	}
+
+	backups, err := filepath.Glob(c.Path + ".v0-*.bak")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 1 {
+		t.Fatalf("expected a single backup of the legacy store, got %v", backups)
+	}
+	defer os.Remove(backups[0])
+
+	backup, err := bbolt.Open(backups[0], 0600, &bbolt.Options{Timeout: time.Second, ReadOnly: true})
+	if err != nil {
+		t.Fatalf("unable to open backup: %v", err)
+	}
+	defer backup.Close()
+
+	if err := backup.View(func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte("usersv1"))
+		if b == nil || b.Get([]byte("0000000000000001")) == nil {
+			t.Errorf("expected backup to contain the legacy data")
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClient_MigrateDownIrreversible(t *testing.T) {
+	c, closeFn, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("failed to create new bolt client: %v", err)
+	}
+	defer closeFn()
+
+	ctx := context.Background()
+	if err := c.MigrateDown(ctx, 0); err == nil {
+		t.Fatalf("expected error reverting the initial schema")
+	}
+
+	if v, err := c.SchemaVersion(ctx); err != nil {
+		t.Fatalf("unexpected error getting schema version: %v", err)
+	} else if want := bolt.LatestSchemaVersion(); v != want {
+		t.Fatalf("expected schema version to remain %d, got %d", want, v)
+	}
+
+	if err := c.MigrateUp(ctx, bolt.LatestSchemaVersion()+1); err == nil {
+		t.Fatalf("expected error migrating to an unknown version")
+	}
+}
+
+func TestClient_MigrateLabelResourceIndex(t *testing.T) {
+	c, closeFn, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("failed to create new bolt client: %v", err)
+	}
+	defer closeFn()
+
+	ctx := context.Background()
+	org := &platform.Organization{Name: "org"}
+	if err := c.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	b := &platform.Bucket{Name: "bucket", OrganizationID: org.ID}
+	if err := c.CreateBucket(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+	l := &platform.Label{Name: "prod"}
+	if err := c.CreateLabel(ctx, l); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.CreateLabelMapping(ctx, &platform.LabelMapping{LabelID: l.ID, ResourceID: b.ID, ResourceType: platform.BucketsResourceType}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.MigrateDown(ctx, 1); err != nil {
+		t.Fatalf("unexpected error migrating down: %v", err)
+	}
+	defer func() {
+		backups, _ := filepath.Glob(c.Path + ".v*.bak")
+		for _, b := range backups {
+			os.Remove(b)
+		}
+	}()
+
+	if err := c.DB().View(func(tx *bbolt.Tx) error {
+		if tx.Bucket([]byte("labelresourcesv1")) != nil {
+			t.Errorf("expected label resource index to be dropped")
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.MigrateUp(ctx, bolt.LatestSchemaVersion()); err != nil {
+		t.Fatalf("unexpected error migrating up: %v", err)
+	}
+
+	bs, _, err := c.FindBuckets(ctx, platform.BucketFilter{Labels: platform.LabelSelector{Names: []string{"prod"}}})
+	if err != nil {
+		t.Fatalf("unable to find buckets: %v", err)
+	}
+	if len(bs) != 1 || bs[0].ID != b.ID {
+		t.Errorf("expected the existing mapping to be indexed, got %v", bs)
+	}
+}
+
+// openTestClientAtVersion writes a store at schema version, as an earlier
+// release would have left it, and opens it with automatic migration.
+func openTestClientAtVersion(t *testing.T, version int) (*bolt.Client, func()) {
+	t.Helper()
+
+	c, closeFn, err := newTestClient()
+	if err != nil {
+		t.Fatalf("failed to create new bolt client: %v", err)
+	}
+
+	ctx := context.Background()
+	c.DisableAutoMigrate = true
+	if err := c.Open(ctx); err != nil {
+		t.Fatalf("unable to open client: %v", err)
+	}
+	if err := c.MigrateUp(ctx, version); err != nil {
+		t.Fatalf("unexpected error migrating up: %v", err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	c.DisableAutoMigrate = false
+	if err := c.Open(ctx); err != nil {
+		t.Fatalf("unable to open client: %v", err)
+	}
+
+	return c, func() {
+		closeFn()
+		backups, _ := filepath.Glob(c.Path + ".v*.bak")
+		for _, b := range backups {
+			os.Remove(b)
+		}
+	}
+}
+
+func TestClient_MigrateScraperStatusBucket(t *testing.T) {
+	c, closeFn := openTestClientAtVersion(t, 3)
+	defer closeFn()
+
+	ctx := context.Background()
+	target := &platform.ScraperTarget{
+		Name:     "target",
+		Type:     platform.PrometheusScraperType,
+		URL:      "http://localhost:9100/metrics",
+		OrgID:    1,
+		BucketID: 2,
+	}
+	if err := c.AddTarget(ctx, target, 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.UpdateScraperStatus(ctx, target.ID, &platform.ScraperStatus{Samples: 1}); err != nil {
+		t.Fatalf("unable to update scraper status: %v", err)
+	}
+	if ts, err := c.ListTargets(ctx); err != nil {
+		t.Fatalf("unable to list targets: %v", err)
+	} else if len(ts) != 1 || ts[0].Status == nil || ts[0].Status.Samples != 1 {
+		t.Fatalf("expected the target with its status, got %v", ts)
+	}
+	if err := c.RemoveTarget(ctx, target.ID); err != nil {
+		t.Fatalf("unable to remove target: %v", err)
+	}
+}
+
+func TestClient_MigrateTelegrafAgentBucket(t *testing.T) {
+	c, closeFn := openTestClientAtVersion(t, 4)
+	defer closeFn()
+
+	ctx := context.Background()
+	a := &platform.TelegrafAgent{TelegrafID: 1, Hostname: "host", Version: 1}
+	if err := c.CheckInTelegrafAgent(ctx, a); err != nil {
+		t.Fatalf("unable to check in telegraf agent: %v", err)
+	}
+	if as, err := c.FindTelegrafAgents(ctx, a.TelegrafID); err != nil {
+		t.Fatalf("unable to find telegraf agents: %v", err)
+	} else if len(as) != 1 || as[0].Hostname != "host" {
+		t.Fatalf("expected the checked in agent, got %v", as)
+	}
+}
+
+func TestClient_MigrateDashboardVersionBucket(t *testing.T) {
+	c, closeFn := openTestClientAtVersion(t, 5)
+	defer closeFn()
+
+	ctx := context.Background()
+	d := &platform.Dashboard{Name: "dashboard", OrganizationID: 1}
+	if err := c.CreateDashboard(ctx, d); err != nil {
+		t.Fatal(err)
+	}
+	name := "renamed"
+	if _, err := c.UpdateDashboard(ctx, d.ID, platform.DashboardUpdate{Name: &name}); err != nil {
+		t.Fatalf("unable to update dashboard: %v", err)
+	}
+	if vs, n, err := c.FindDashboardVersions(ctx, d.ID, platform.FindOptions{}); err != nil {
+		t.Fatalf("unable to find dashboard versions: %v", err)
+	} else if n != 1 || len(vs) != 1 || vs[0].Dashboard.Name != "dashboard" {
+		t.Fatalf("expected the version before the update, got %v", vs)
+	}
+}
+
+func TestClient_MigratePageIndexes(t *testing.T) {
+	c, closeFn := openTestClientAtVersion(t, bolt.LatestSchemaVersion())
+	defer closeFn()
+
+	ctx := context.Background()
+	for _, name := range []string{"c", "a", "b"} {
+		if err := c.CreateOrganization(ctx, &platform.Organization{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Reverting and reapplying the migration indexes the existing organizations.
+	if err := c.MigrateDown(ctx, 6); err != nil {
+		t.Fatalf("unexpected error migrating down: %v", err)
+	}
+	if err := c.MigrateUp(ctx, 7); err != nil {
+		t.Fatalf("unexpected error migrating up: %v", err)
+	}
+
+	orgs, _, err := c.FindOrganizations(ctx, platform.OrganizationFilter{}, platform.FindOptions{SortBy: "Name"})
+	if err != nil {
+		t.Fatalf("unable to find organizations: %v", err)
+	}
+	var names []string
+	for _, o := range orgs {
+		names = append(names, o.Name)
+	}
+	if got, want := fmt.Sprint(names), "[a b c]"; got != want {
+		t.Fatalf("organizations sorted by name = %s, want %s", got, want)
+	}
+}
@@ -12,10 +12,21 @@ import (
 )
 
 var (
-	organizationBucket = []byte("organizationsv1")
-	organizationIndex  = []byte("organizationindexv1")
+	organizationBucket    = []byte("organizationsv1")
+	organizationIndex     = []byte("organizationindexv1")
+	organizationNameIndex = []byte("organizationnameindexv1")
 )
 
+// organizationPages orders organizations for listings.
+var organizationPages = &pageIndex{
+	bucket:  organizationBucket,
+	indexes: map[string][]byte{"Name": organizationNameIndex},
+	decode: func(v []byte) (influxdb.Pageable, error) {
+		o := &influxdb.Organization{}
+		return o, json.Unmarshal(v, o)
+	},
+}
+
 var _ influxdb.OrganizationService = (*Client)(nil)
 var _ influxdb.OrganizationOperationLogService = (*Client)(nil)
 
@@ -144,7 +155,7 @@ func (c *Client) FindOrganization(ctx context.Context, filter influxdb.Organizat
 
 	var o *influxdb.Organization
 	err := c.db.View(func(tx *bolt.Tx) error {
-		return forEachOrganization(ctx, tx, func(org *influxdb.Organization) bool {
+		return forEachOrganization(ctx, tx, influxdb.FindOptions{}, func(org *influxdb.Organization) bool {
 			if filterFn(org) {
 				o = org
 				return false
@@ -216,12 +227,12 @@ func (c *Client) FindOrganizations(ctx context.Context, filter influxdb.Organiza
 		return []*influxdb.Organization{o}, 1, nil
 	}
 
-	os := []*influxdb.Organization{}
+	p := &pager{opts: findOptions(opt)}
 	filterFn := filterOrganizationsFn(filter)
 	err := c.db.View(func(tx *bolt.Tx) error {
-		return forEachOrganization(ctx, tx, func(o *influxdb.Organization) bool {
+		return forEachOrganization(ctx, tx, p.opts, func(o *influxdb.Organization) bool {
 			if filterFn(o) {
-				os = append(os, o)
+				return p.add(o)
 			}
 			return true
 		})
@@ -234,6 +245,12 @@ func (c *Client) FindOrganizations(ctx context.Context, filter influxdb.Organiza
 		}
 	}
 
+	page := p.results()
+	os := make([]*influxdb.Organization, 0, len(page))
+	for _, o := range page {
+		os = append(os, o.(*influxdb.Organization))
+	}
+
 	return os, len(os), nil
 }
 
@@ -298,6 +315,11 @@ func (c *Client) putOrganization(ctx context.Context, tx *bolt.Tx, o *influxdb.O
 			Err: err,
 		}
 	}
+	if err := organizationPages.put(tx, encodedID, o); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
 	if err = tx.Bucket(organizationBucket).Put(encodedID, v); err != nil {
 		return &influxdb.Error{
 			Err: err,
@@ -310,10 +332,14 @@ func organizationIndexKey(n string) []byte {
 	return []byte(n)
 }
 
-// forEachOrganization will iterate through all organizations while fn returns true.
-func forEachOrganization(ctx context.Context, tx *bolt.Tx, fn func(*influxdb.Organization) bool) error {
-	cur := tx.Bucket(organizationBucket).Cursor()
-	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+// forEachOrganization will iterate through organizations in the order given by opts while fn returns true.
+func forEachOrganization(ctx context.Context, tx *bolt.Tx, opts influxdb.FindOptions, fn func(*influxdb.Organization) bool) error {
+	s, err := organizationPages.scan(tx, opts)
+	if err != nil {
+		return err
+	}
+
+	for v := s.next(); v != nil; v = s.next() {
 		o := &influxdb.Organization{}
 		if err := json.Unmarshal(v, o); err != nil {
 			return err
@@ -416,6 +442,11 @@ func (c *Client) deleteOrganization(ctx context.Context, tx *bolt.Tx, id influxd
 			Err:  err,
 		}
 	}
+	if err := organizationPages.delete(tx, encodedID); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
 	if err = tx.Bucket(organizationBucket).Delete(encodedID); err != nil {
 		return &influxdb.Error{
 			Err: err,
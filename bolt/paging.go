@@ -0,0 +1,275 @@
+package bolt
+
+import (
+	"bytes"
+	"context"
+
+	bolt "github.com/coreos/bbolt"
+	platform "github.com/influxdata/influxdb"
+)
+
+// pageIndex orders the resources of a bucket keyed by resource ID by the
+// fields they can be listed by. Listings ordered by ID follow the key order of
+// the bucket itself; each other field has an index bucket keyed by the value
+// of the field and the resource ID, in the order of platform.PageCursor.
+type pageIndex struct {
+	bucket  []byte
+	indexes map[string][]byte
+	decode  func(v []byte) (platform.Pageable, error)
+}
+
+// createPageIndexBuckets creates the index buckets of each of ixs and indexes the
+// resources already in their buckets.
+func createPageIndexBuckets(tx *bolt.Tx, ixs ...*pageIndex) error {
+	for _, ix := range ixs {
+		if err := ix.createIndexes(tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropPageIndexBuckets deletes the index buckets of each of ixs.
+func dropPageIndexBuckets(tx *bolt.Tx, ixs ...*pageIndex) error {
+	for _, ix := range ixs {
+		if err := ix.dropIndexes(tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func createPageIndexes(ctx context.Context, c *Client, tx *bolt.Tx) error {
+	return createPageIndexBuckets(tx, bucketPages, dashboardPages, macroPages, organizationPages, userPages)
+}
+
+func dropPageIndexes(ctx context.Context, c *Client, tx *bolt.Tx) error {
+	return dropPageIndexBuckets(tx, bucketPages, dashboardPages, macroPages, organizationPages, userPages)
+}
+
+func createTelegrafScraperPageIndexes(ctx context.Context, c *Client, tx *bolt.Tx) error {
+	return createPageIndexBuckets(tx, telegrafPages, scraperPages)
+}
+
+func dropTelegrafScraperPageIndexes(ctx context.Context, c *Client, tx *bolt.Tx) error {
+	return dropPageIndexBuckets(tx, telegrafPages, scraperPages)
+}
+
+// pageIndexKey returns the key of the resource at cursor c in an index bucket.
+// The ID follows a zero byte so that keys order by field value and then by ID.
+func pageIndexKey(c platform.PageCursor) []byte {
+	k := make([]byte, 0, len(c.Key)+1+len(c.ID))
+	k = append(k, c.Key...)
+	k = append(k, 0)
+	return append(k, c.ID...)
+}
+
+// createIndexes creates the index buckets of ix and indexes the resources
+// already in the bucket.
+func (ix *pageIndex) createIndexes(tx *bolt.Tx) error {
+	for _, name := range ix.indexes {
+		if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+			return err
+		}
+	}
+
+	b := tx.Bucket(ix.bucket)
+	if b == nil {
+		return nil
+	}
+	return b.ForEach(func(_, v []byte) error {
+		r, err := ix.decode(v)
+		if err != nil {
+			return err
+		}
+		return ix.index(tx, r)
+	})
+}
+
+// dropIndexes deletes the index buckets of ix.
+func (ix *pageIndex) dropIndexes(tx *bolt.Tx) error {
+	for _, name := range ix.indexes {
+		if err := tx.DeleteBucket(name); err != nil && err != bolt.ErrBucketNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+// index adds the entries of r to the index buckets.
+func (ix *pageIndex) index(tx *bolt.Tx, r platform.Pageable) error {
+	for sortBy, name := range ix.indexes {
+		c, err := r.Cursor(sortBy)
+		if err != nil {
+			return err
+		}
+		if err := tx.Bucket(name).Put(pageIndexKey(c), []byte(c.ID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// delete removes the entries of the resource stored under key from the index
+// buckets, if there is one. It must be called before the resource is deleted
+// from the bucket.
+func (ix *pageIndex) delete(tx *bolt.Tx, key []byte) error {
+	if len(ix.indexes) == 0 {
+		return nil
+	}
+
+	v := tx.Bucket(ix.bucket).Get(key)
+	if v == nil {
+		return nil
+	}
+	r, err := ix.decode(v)
+	if err != nil {
+		return err
+	}
+
+	for sortBy, name := range ix.indexes {
+		c, err := r.Cursor(sortBy)
+		if err != nil {
+			return err
+		}
+		if err := tx.Bucket(name).Delete(pageIndexKey(c)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// put indexes r, replacing the entries of the resource it replaces under key.
+// It must be called before r is written to the bucket.
+func (ix *pageIndex) put(tx *bolt.Tx, key []byte, r platform.Pageable) error {
+	if err := ix.delete(tx, key); err != nil {
+		return err
+	}
+	return ix.index(tx, r)
+}
+
+// pageScan iterates over the resources of a bucket in the order of a listing.
+type pageScan struct {
+	cur        *bolt.Cursor
+	values     *bolt.Bucket // nil when cur is over the bucket of resources itself
+	descending bool
+	k, v       []byte
+}
+
+// scan returns a scan of the listing described by opts. A listing starting
+// after opts.After seeks to the entry following the one of the cursor.
+func (ix *pageIndex) scan(tx *bolt.Tx, opts platform.FindOptions) (*pageScan, error) {
+	s := &pageScan{descending: opts.Descending}
+
+	sortBy := opts.SortBy
+	if opts.SortedByID() {
+		sortBy = ""
+		s.cur = tx.Bucket(ix.bucket).Cursor()
+	} else {
+		name, ok := ix.indexes[sortBy]
+		if !ok {
+			return nil, platform.ErrUnsupportedSortField(sortBy)
+		}
+		s.cur = tx.Bucket(name).Cursor()
+		s.values = tx.Bucket(ix.bucket)
+	}
+
+	if opts.After == "" {
+		if s.descending {
+			s.k, s.v = s.cur.Last()
+		} else {
+			s.k, s.v = s.cur.First()
+		}
+		return s, nil
+	}
+
+	after, err := platform.DecodePageCursor(opts.After)
+	if err != nil {
+		return nil, err
+	}
+	if after.SortBy == "ID" {
+		after.SortBy = ""
+	}
+	if after.SortBy != sortBy {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "page cursor does not match the sort order",
+		}
+	}
+
+	var key []byte
+	if s.values == nil {
+		id, err := platform.IDFromString(after.ID)
+		if err != nil {
+			return nil, err
+		}
+		if key, err = id.Encode(); err != nil {
+			return nil, err
+		}
+	} else {
+		key = pageIndexKey(after)
+	}
+
+	s.k, s.v = s.cur.Seek(key)
+	switch {
+	case s.descending && s.k == nil:
+		s.k, s.v = s.cur.Last()
+	case s.descending:
+		s.k, s.v = s.cur.Prev()
+	case bytes.Equal(s.k, key):
+		s.k, s.v = s.cur.Next()
+	}
+	return s, nil
+}
+
+// next returns the value of the next resource of the scan, or nil at its end.
+func (s *pageScan) next() []byte {
+	for s.k != nil {
+		v := s.v
+		if s.descending {
+			s.k, s.v = s.cur.Prev()
+		} else {
+			s.k, s.v = s.cur.Next()
+		}
+
+		if s.values == nil {
+			return v
+		}
+		// Index entries hold the ID their resource is stored under.
+		if v := s.values.Get(v); v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+// pager collects a page of resources from a listing, skipping opts.Offset
+// resources unless the page starts after a cursor.
+type pager struct {
+	opts    platform.FindOptions
+	skipped int
+	page    []platform.Pageable
+}
+
+// add offers the next matching resource and reports whether the scan should continue.
+func (p *pager) add(r platform.Pageable) bool {
+	if p.opts.After == "" && p.skipped < p.opts.Offset {
+		p.skipped++
+		return true
+	}
+
+	p.page = append(p.page, r)
+	return p.opts.Limit <= 0 || len(p.page) < p.opts.Limit
+}
+
+// results returns the resources of the page in order.
+func (p *pager) results() []platform.Pageable {
+	return p.page
+}
+
+func findOptions(opts []platform.FindOptions) platform.FindOptions {
+	if len(opts) > 0 {
+		return opts[0]
+	}
+	return platform.FindOptions{}
+}
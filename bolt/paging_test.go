@@ -0,0 +1,182 @@
+package bolt_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestClient_FindOrganizationsPages(t *testing.T) {
+	c, closeFn, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("failed to create new bolt client: %v", err)
+	}
+	defer closeFn()
+
+	ctx := context.Background()
+	for i := 1; i <= 5; i++ {
+		o := &platform.Organization{Name: fmt.Sprintf("org%d", 6-i)}
+		c.IDGenerator = mock.NewIDGenerator(fmt.Sprintf("%016x", i), t)
+		if err := c.CreateOrganization(ctx, o); err != nil {
+			t.Fatalf("unable to create organization: %v", err)
+		}
+	}
+
+	// walk fetches every page of organizations, two at a time, following cursors.
+	walk := func(opts platform.FindOptions) []string {
+		var names []string
+		opts.Limit = 2
+		for {
+			os, _, err := c.FindOrganizations(ctx, platform.OrganizationFilter{}, opts)
+			if err != nil {
+				t.Fatalf("unable to find organizations: %v", err)
+			}
+			for _, o := range os {
+				names = append(names, o.Name)
+			}
+			if len(os) < opts.Limit {
+				return names
+			}
+
+			cur, err := os[len(os)-1].Cursor(opts.SortBy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			opts.After = cur.Encode()
+		}
+	}
+
+	tests := []struct {
+		opts platform.FindOptions
+		want string
+	}{
+		{opts: platform.FindOptions{}, want: "[org5 org4 org3 org2 org1]"},
+		{opts: platform.FindOptions{Descending: true}, want: "[org1 org2 org3 org4 org5]"},
+		{opts: platform.FindOptions{SortBy: "Name"}, want: "[org1 org2 org3 org4 org5]"},
+		{opts: platform.FindOptions{SortBy: "Name", Descending: true}, want: "[org5 org4 org3 org2 org1]"},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprint(walk(tt.opts)); got != tt.want {
+			t.Errorf("FindOrganizations(%+v) pages = %s, want %s", tt.opts, got, tt.want)
+		}
+	}
+}
+
+func TestClient_FindDashboardsPages(t *testing.T) {
+	c, closeFn, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("failed to create new bolt client: %v", err)
+	}
+	defer closeFn()
+
+	ctx := context.Background()
+	ds := make([]*platform.Dashboard, 4)
+	for i := range ds {
+		ds[i] = &platform.Dashboard{Name: fmt.Sprintf("dashboard%d", 4-i), OrganizationID: 1}
+		c.IDGenerator = mock.NewIDGenerator(fmt.Sprintf("%016x", i+1), t)
+		if err := c.CreateDashboard(ctx, ds[i]); err != nil {
+			t.Fatalf("unable to create dashboard: %v", err)
+		}
+	}
+
+	// Renaming a dashboard moves it within listings sorted by name.
+	name := "dashboard0"
+	if _, err := c.UpdateDashboard(ctx, ds[1].ID, platform.DashboardUpdate{Name: &name}); err != nil {
+		t.Fatalf("unable to update dashboard: %v", err)
+	}
+
+	opts := platform.FindOptions{SortBy: "Name", Limit: 2}
+	filter := platform.DashboardFilter{OrganizationID: &ds[0].OrganizationID}
+	var names []string
+	for {
+		page, _, err := c.FindDashboards(ctx, filter, opts)
+		if err != nil {
+			t.Fatalf("unable to find dashboards: %v", err)
+		}
+		for _, d := range page {
+			names = append(names, d.Name)
+		}
+		if len(page) < opts.Limit {
+			break
+		}
+
+		cur, err := page[len(page)-1].Cursor(opts.SortBy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		opts.After = cur.Encode()
+	}
+	if got, want := fmt.Sprint(names), "[dashboard0 dashboard1 dashboard2 dashboard4]"; got != want {
+		t.Fatalf("FindDashboards pages = %s, want %s", got, want)
+	}
+
+	if _, _, err := c.FindDashboards(ctx, filter, platform.FindOptions{SortBy: "Owner"}); platform.ErrorCode(err) != platform.EInvalid {
+		t.Fatalf("expected invalid sort field error, got %v", err)
+	}
+}
+
+func TestClient_ListTargetsPages(t *testing.T) {
+	c, closeFn, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("failed to create new bolt client: %v", err)
+	}
+	defer closeFn()
+
+	ctx := context.Background()
+	for i := 1; i <= 3; i++ {
+		target := &platform.ScraperTarget{
+			Name:     fmt.Sprintf("target%d", 4-i),
+			Type:     platform.PrometheusScraperType,
+			URL:      "http://localhost:9999/metrics",
+			OrgID:    1,
+			BucketID: 2,
+		}
+		c.IDGenerator = mock.NewIDGenerator(fmt.Sprintf("%016x", i), t)
+		if err := c.AddTarget(ctx, target, 3); err != nil {
+			t.Fatalf("unable to add scraper target: %v", err)
+		}
+
+		tc := &platform.TelegrafConfig{Name: fmt.Sprintf("telegraf%d", 4-i), OrganizationID: 1}
+		c.IDGenerator = mock.NewIDGenerator(fmt.Sprintf("%016x", 10+i), t)
+		if err := c.CreateTelegrafConfig(ctx, tc, 3); err != nil {
+			t.Fatalf("unable to create telegraf config: %v", err)
+		}
+	}
+
+	opts := platform.FindOptions{SortBy: "Name", Limit: 2}
+	ts, err := c.ListTargets(ctx, opts)
+	if err != nil {
+		t.Fatalf("unable to list scraper targets: %v", err)
+	}
+	if len(ts) != 2 || ts[0].Name != "target1" || ts[1].Name != "target2" {
+		t.Fatalf("unexpected first page of scraper targets: %v", ts)
+	}
+	cur, err := ts[1].Cursor(opts.SortBy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.After = cur.Encode()
+	if ts, err = c.ListTargets(ctx, opts); err != nil {
+		t.Fatalf("unable to list scraper targets: %v", err)
+	}
+	if len(ts) != 1 || ts[0].Name != "target3" {
+		t.Fatalf("unexpected second page of scraper targets: %v", ts)
+	}
+
+	filter := platform.TelegrafConfigFilter{
+		UserResourceMappingFilter: platform.UserResourceMappingFilter{
+			UserID:       3,
+			ResourceType: platform.TelegrafsResourceType,
+		},
+	}
+	tcs, _, err := c.FindTelegrafConfigs(ctx, filter, platform.FindOptions{SortBy: "Name", Descending: true, Limit: 2})
+	if err != nil {
+		t.Fatalf("unable to find telegraf configs: %v", err)
+	}
+	if len(tcs) != 2 || tcs[0].Name != "telegraf3" || tcs[1].Name != "telegraf2" {
+		t.Fatalf("unexpected page of telegraf configs: %v", tcs)
+	}
+}
@@ -9,10 +9,25 @@ import (
 )
 
 var (
-	scraperBucket = []byte("scraperv2")
+	scraperBucket       = []byte("scraperv2")
+	scraperStatusBucket = []byte("scraperstatusv1")
+	scraperNameIndex    = []byte("scrapernameindexv1")
 )
 
-var _ platform.ScraperTargetStoreService = (*Client)(nil)
+// scraperPages orders scraper targets for listings.
+var scraperPages = &pageIndex{
+	bucket:  scraperBucket,
+	indexes: map[string][]byte{"Name": scraperNameIndex},
+	decode: func(v []byte) (platform.Pageable, error) {
+		t := &platform.ScraperTarget{}
+		return t, json.Unmarshal(v, t)
+	},
+}
+
+var (
+	_ platform.ScraperTargetStoreService = (*Client)(nil)
+	_ platform.ScraperStatusService      = (*Client)(nil)
+)
 
 func (c *Client) initializeScraperTargets(ctx context.Context, tx *bolt.Tx) error {
 	if _, err := tx.CreateBucketIfNotExists([]byte(scraperBucket)); err != nil {
@@ -21,19 +36,52 @@ func (c *Client) initializeScraperTargets(ctx context.Context, tx *bolt.Tx) erro
 	return nil
 }
 
-// ListTargets will list all scrape targets.
-func (c *Client) ListTargets(ctx context.Context) (list []platform.ScraperTarget, err error) {
+// createScraperStatusBucket creates the bucket holding the status of the last
+// scrape of each target.
+func createScraperStatusBucket(ctx context.Context, c *Client, tx *bolt.Tx) error {
+	if _, err := tx.CreateBucketIfNotExists(scraperStatusBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+func dropScraperStatusBucket(ctx context.Context, c *Client, tx *bolt.Tx) error {
+	return tx.DeleteBucket(scraperStatusBucket)
+}
+
+// ListTargets will list scrape targets.
+// Additional options provide pagination & sorting.
+func (c *Client) ListTargets(ctx context.Context, opt ...platform.FindOptions) (list []platform.ScraperTarget, err error) {
 	list = make([]platform.ScraperTarget, 0)
-	err = c.db.View(func(tx *bolt.Tx) (err error) {
-		cur := tx.Bucket(scraperBucket).Cursor()
-		for k, v := cur.First(); k != nil; k, v = cur.Next() {
+	p := &pager{opts: findOptions(opt)}
+	err = c.db.View(func(tx *bolt.Tx) error {
+		s, err := scraperPages.scan(tx, p.opts)
+		if err != nil {
+			return err
+		}
+
+		for v := s.next(); v != nil; v = s.next() {
 			target := new(platform.ScraperTarget)
-			if err = json.Unmarshal(v, target); err != nil {
+			if err := json.Unmarshal(v, target); err != nil {
+				return err
+			}
+			if !p.add(target) {
+				break
+			}
+		}
+
+		for _, t := range p.results() {
+			target := t.(*platform.ScraperTarget)
+			encID, err := target.ID.Encode()
+			if err != nil {
+				return err
+			}
+			if target.Status, err = c.findScraperStatus(tx, encID); err != nil {
 				return err
 			}
 			list = append(list, *target)
 		}
-		return err
+		return nil
 	})
 	if err != nil {
 		return nil, &platform.Error{
@@ -96,9 +144,15 @@ func (c *Client) RemoveTarget(ctx context.Context, id platform.ID) error {
 				Err:  err,
 			}
 		}
+		if err = scraperPages.delete(tx, encID); err != nil {
+			return err
+		}
 		if err = tx.Bucket(scraperBucket).Delete(encID); err != nil {
 			return nil
 		}
+		if err = tx.Bucket(scraperStatusBucket).Delete(encID); err != nil {
+			return err
+		}
 		return c.deleteUserResourceMappings(ctx, tx, platform.UserResourceMappingFilter{
 			ResourceID:   id,
 			ResourceType: platform.ScraperResourceType,
@@ -135,6 +189,7 @@ func (c *Client) UpdateTarget(ctx context.Context, update *platform.ScraperTarge
 		if !update.OrgID.Valid() {
 			update.OrgID = target.OrgID
 		}
+		update.Status = target.Status
 		target = update
 		return c.putTarget(ctx, tx, target)
 	})
@@ -189,11 +244,19 @@ func (c *Client) findTargetByID(ctx context.Context, tx *bolt.Tx, id platform.ID
 			Err: err,
 		}
 	}
+	if target.Status, err = c.findScraperStatus(tx, encID); err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
 	return target, nil
 }
 
 func (c *Client) putTarget(ctx context.Context, tx *bolt.Tx, target *platform.ScraperTarget) (err error) {
-	v, err := json.Marshal(target)
+	// The status of the target is kept apart, so updates don't overwrite it.
+	t := *target
+	t.Status = nil
+	v, err := json.Marshal(t)
 	if err != nil {
 		return err
 	}
@@ -201,6 +264,9 @@ func (c *Client) putTarget(ctx context.Context, tx *bolt.Tx, target *platform.Sc
 	if err != nil {
 		return err
 	}
+	if err := scraperPages.put(tx, encID, &t); err != nil {
+		return err
+	}
 	return tx.Bucket(scraperBucket).Put(encID, v)
 }
 
@@ -210,3 +276,46 @@ func (c *Client) PutTarget(ctx context.Context, target *platform.ScraperTarget)
 		return c.putTarget(ctx, tx, target)
 	})
 }
+
+// UpdateScraperStatus records the status of the last scrape of the target id.
+func (c *Client) UpdateScraperStatus(ctx context.Context, id platform.ID, status *platform.ScraperStatus) error {
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		encID, err := id.Encode()
+		if err != nil {
+			return &platform.Error{
+				Code: platform.EInvalid,
+				Err:  err,
+			}
+		}
+		if len(tx.Bucket(scraperBucket).Get(encID)) == 0 {
+			return &platform.Error{
+				Code: platform.ENotFound,
+				Msg:  "scraper target is not found",
+			}
+		}
+		v, err := json.Marshal(status)
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(scraperStatusBucket).Put(encID, v)
+	})
+	if err != nil {
+		return &platform.Error{
+			Op:  getOp(platform.OpUpdateScraperStatus),
+			Err: err,
+		}
+	}
+	return nil
+}
+
+func (c *Client) findScraperStatus(tx *bolt.Tx, encID []byte) (*platform.ScraperStatus, error) {
+	v := tx.Bucket(scraperStatusBucket).Get(encID)
+	if len(v) == 0 {
+		return nil, nil
+	}
+	status := new(platform.ScraperStatus)
+	if err := json.Unmarshal(v, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
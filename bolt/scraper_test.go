@@ -39,3 +39,7 @@ func initScraperTargetStoreService(f platformtesting.TargetFields, t *testing.T)
 func TestScraperTargetStoreService(t *testing.T) {
 	platformtesting.ScraperService(initScraperTargetStoreService, t)
 }
+
+func TestScraperStatusService(t *testing.T) {
+	platformtesting.ScraperStatusService(initScraperTargetStoreService, t)
+}
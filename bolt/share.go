@@ -0,0 +1,353 @@
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	bolt "github.com/coreos/bbolt"
+	platform "github.com/influxdata/influxdb"
+)
+
+var (
+	shareBucket = []byte("sharesv1")
+	// shareResourceIndex maps resource ID + share ID to nothing, so the grants
+	// of a resource can be found without scanning every grant.
+	shareResourceIndex = []byte("shareresourcesv1")
+)
+
+// createShareBuckets creates the buckets holding share grants.
+func createShareBuckets(ctx context.Context, c *Client, tx *bolt.Tx) error {
+	if _, err := tx.CreateBucketIfNotExists(shareBucket); err != nil {
+		return err
+	}
+	if _, err := tx.CreateBucketIfNotExists(shareResourceIndex); err != nil {
+		return err
+	}
+	return nil
+}
+
+func dropShareBuckets(ctx context.Context, c *Client, tx *bolt.Tx) error {
+	if err := tx.DeleteBucket(shareResourceIndex); err != nil {
+		return err
+	}
+	return tx.DeleteBucket(shareBucket)
+}
+
+// FindShareByID returns a single share grant by ID.
+func (c *Client) FindShareByID(ctx context.Context, id platform.ID) (*platform.ShareGrant, error) {
+	var g *platform.ShareGrant
+	err := c.db.View(func(tx *bolt.Tx) error {
+		grant, pe := c.findShareByID(ctx, tx, id)
+		if pe != nil {
+			return pe
+		}
+		g = grant
+		return nil
+	})
+
+	if err != nil {
+		return nil, &platform.Error{
+			Op:  getOp(platform.OpFindShareByID),
+			Err: err,
+		}
+	}
+
+	return g, nil
+}
+
+func (c *Client) findShareByID(ctx context.Context, tx *bolt.Tx, id platform.ID) (*platform.ShareGrant, *platform.Error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+
+	v := tx.Bucket(shareBucket).Get(encodedID)
+	if len(v) == 0 {
+		return nil, &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  platform.ErrShareNotFound,
+		}
+	}
+
+	g := &platform.ShareGrant{}
+	if err := json.Unmarshal(v, g); err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
+
+	return g, nil
+}
+
+func filterSharesFn(filter platform.ShareFilter) func(g *platform.ShareGrant) bool {
+	return func(g *platform.ShareGrant) bool {
+		return (filter.ResourceID == nil || *filter.ResourceID == g.ResourceID) &&
+			(filter.ResourceType == "" || filter.ResourceType == g.ResourceType) &&
+			(filter.OrgID == nil || *filter.OrgID == g.OrgID) &&
+			(filter.TargetOrgID == nil || *filter.TargetOrgID == g.TargetOrgID)
+	}
+}
+
+// FindShares returns a list of share grants that match filter and the total count of matching grants.
+func (c *Client) FindShares(ctx context.Context, filter platform.ShareFilter) ([]*platform.ShareGrant, int, error) {
+	gs := []*platform.ShareGrant{}
+	err := c.db.View(func(tx *bolt.Tx) error {
+		grants, err := c.findShares(ctx, tx, filter)
+		if err != nil {
+			return err
+		}
+		gs = grants
+		return nil
+	})
+
+	if err != nil {
+		return nil, 0, &platform.Error{
+			Op:  getOp(platform.OpFindShares),
+			Err: err,
+		}
+	}
+
+	return gs, len(gs), nil
+}
+
+func (c *Client) findShares(ctx context.Context, tx *bolt.Tx, filter platform.ShareFilter) ([]*platform.ShareGrant, error) {
+	gs := []*platform.ShareGrant{}
+	filterFn := filterSharesFn(filter)
+
+	if filter.ResourceID != nil {
+		ids, err := c.findResourceShareIDs(ctx, tx, *filter.ResourceID)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			g, pe := c.findShareByID(ctx, tx, id)
+			if pe != nil {
+				return nil, pe
+			}
+			if filterFn(g) {
+				gs = append(gs, g)
+			}
+		}
+		return gs, nil
+	}
+
+	err := tx.Bucket(shareBucket).ForEach(func(k, v []byte) error {
+		g := &platform.ShareGrant{}
+		if err := json.Unmarshal(v, g); err != nil {
+			return err
+		}
+		if filterFn(g) {
+			gs = append(gs, g)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return gs, nil
+}
+
+// findResourceShareIDs returns the IDs of the grants of the resource id.
+func (c *Client) findResourceShareIDs(ctx context.Context, tx *bolt.Tx, id platform.ID) ([]platform.ID, error) {
+	prefix, err := id.Encode()
+	if err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+
+	var ids []platform.ID
+	cur := tx.Bucket(shareResourceIndex).Cursor()
+	for k, _ := cur.Seek(prefix); bytes.HasPrefix(k, prefix); k, _ = cur.Next() {
+		var shareID platform.ID
+		if err := shareID.Decode(k[platform.IDLength:]); err != nil {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "malformed share resource index key (please report this error)",
+				Err:  err,
+			}
+		}
+		ids = append(ids, shareID)
+	}
+
+	return ids, nil
+}
+
+// CreateShare creates a new share grant and sets g.ID with the new identifier.
+func (c *Client) CreateShare(ctx context.Context, g *platform.ShareGrant) error {
+	return c.db.Update(func(tx *bolt.Tx) error {
+		if pe := c.createShare(ctx, tx, g); pe != nil {
+			return &platform.Error{
+				Op:  getOp(platform.OpCreateShare),
+				Err: pe,
+			}
+		}
+		return nil
+	})
+}
+
+func (c *Client) createShare(ctx context.Context, tx *bolt.Tx, g *platform.ShareGrant) error {
+	switch g.ResourceType {
+	case platform.BucketsResourceType:
+		b, pe := c.findBucketByID(ctx, tx, g.ResourceID)
+		if pe != nil {
+			return pe
+		}
+		g.OrgID = b.OrganizationID
+	case platform.DashboardsResourceType:
+		d, pe := c.findDashboardByID(ctx, tx, g.ResourceID)
+		if pe != nil {
+			return pe
+		}
+		g.OrgID = d.OrganizationID
+	}
+
+	if err := g.Valid(); err != nil {
+		return err
+	}
+
+	if _, pe := c.findOrganizationByID(ctx, tx, g.TargetOrgID); pe != nil {
+		return pe
+	}
+
+	existing, err := c.findShares(ctx, tx, platform.ShareFilter{
+		ResourceID:  &g.ResourceID,
+		TargetOrgID: &g.TargetOrgID,
+	})
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		return &platform.Error{
+			Code: platform.EConflict,
+			Msg:  "resource is already shared with the organization",
+		}
+	}
+
+	g.ID = c.IDGenerator.ID()
+	return c.putShare(ctx, tx, g)
+}
+
+func shareResourceIndexKey(g *platform.ShareGrant) ([]byte, error) {
+	resourceID, err := g.ResourceID.Encode()
+	if err != nil {
+		return nil, err
+	}
+	shareID, err := g.ID.Encode()
+	if err != nil {
+		return nil, err
+	}
+	return append(resourceID, shareID...), nil
+}
+
+func (c *Client) putShare(ctx context.Context, tx *bolt.Tx, g *platform.ShareGrant) error {
+	encodedID, err := g.ID.Encode()
+	if err != nil {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+
+	v, err := json.Marshal(g)
+	if err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+
+	key, err := shareResourceIndexKey(g)
+	if err != nil {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+
+	if err := tx.Bucket(shareResourceIndex).Put(key, nil); err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+
+	if err := tx.Bucket(shareBucket).Put(encodedID, v); err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+// DeleteShare revokes a share grant.
+func (c *Client) DeleteShare(ctx context.Context, id platform.ID) error {
+	return c.db.Update(func(tx *bolt.Tx) error {
+		if pe := c.deleteShare(ctx, tx, id); pe != nil {
+			return &platform.Error{
+				Op:  getOp(platform.OpDeleteShare),
+				Err: pe,
+			}
+		}
+		return nil
+	})
+}
+
+func (c *Client) deleteShare(ctx context.Context, tx *bolt.Tx, id platform.ID) error {
+	g, pe := c.findShareByID(ctx, tx, id)
+	if pe != nil {
+		return pe
+	}
+
+	key, err := shareResourceIndexKey(g)
+	if err != nil {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+
+	if err := tx.Bucket(shareResourceIndex).Delete(key); err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+
+	encodedID, err := id.Encode()
+	if err != nil {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+
+	if err := tx.Bucket(shareBucket).Delete(encodedID); err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+// deleteResourceShares revokes every grant of the resource id; it is called
+// when the resource is deleted.
+func (c *Client) deleteResourceShares(ctx context.Context, tx *bolt.Tx, id platform.ID) error {
+	ids, err := c.findResourceShareIDs(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	for _, shareID := range ids {
+		if err := c.deleteShare(ctx, tx, shareID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
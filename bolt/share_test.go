@@ -0,0 +1,118 @@
+package bolt_test
+
+import (
+	"context"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+func TestClient_Shares(t *testing.T) {
+	c, closeFn, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("failed to create new bolt client: %v", err)
+	}
+	defer closeFn()
+
+	ctx := context.Background()
+	owner := &platform.Organization{Name: "owner"}
+	target := &platform.Organization{Name: "target"}
+	for _, o := range []*platform.Organization{owner, target} {
+		if err := c.CreateOrganization(ctx, o); err != nil {
+			t.Fatalf("unable to create organization: %v", err)
+		}
+	}
+
+	b := &platform.Bucket{Name: "reference", OrganizationID: owner.ID}
+	if err := c.CreateBucket(ctx, b); err != nil {
+		t.Fatalf("unable to create bucket: %v", err)
+	}
+
+	g := &platform.ShareGrant{
+		ResourceType: platform.BucketsResourceType,
+		ResourceID:   b.ID,
+		TargetOrgID:  target.ID,
+		Action:       platform.ReadAction,
+	}
+	if err := c.CreateShare(ctx, g); err != nil {
+		t.Fatalf("unable to create share: %v", err)
+	}
+	if g.OrgID != owner.ID {
+		t.Errorf("expected grant to be owned by %s, got %s", owner.ID, g.OrgID)
+	}
+
+	dup := *g
+	if err := c.CreateShare(ctx, &dup); platform.ErrorCode(err) != platform.EConflict {
+		t.Errorf("expected conflict sharing the bucket twice, got %v", err)
+	}
+
+	self := &platform.ShareGrant{
+		ResourceType: platform.BucketsResourceType,
+		ResourceID:   b.ID,
+		TargetOrgID:  owner.ID,
+		Action:       platform.ReadAction,
+	}
+	if err := c.CreateShare(ctx, self); platform.ErrorCode(err) != platform.EInvalid {
+		t.Errorf("expected sharing with the owner to be invalid, got %v", err)
+	}
+
+	gs, n, err := c.FindShares(ctx, platform.ShareFilter{TargetOrgID: &target.ID})
+	if err != nil {
+		t.Fatalf("unable to find shares: %v", err)
+	}
+	if n != 1 || gs[0].ID != g.ID {
+		t.Fatalf("expected grant %s, got %+v", g.ID, gs)
+	}
+
+	if err := c.DeleteBucket(ctx, b.ID); err != nil {
+		t.Fatalf("unable to delete bucket: %v", err)
+	}
+	if _, err := c.FindShareByID(ctx, g.ID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Errorf("expected grant to be revoked with its bucket, got %v", err)
+	}
+	if _, n, err := c.FindShares(ctx, platform.ShareFilter{ResourceID: &b.ID}); err != nil || n != 0 {
+		t.Errorf("expected no grants of the deleted bucket, got %d (%v)", n, err)
+	}
+}
+
+func TestClient_DeleteShare(t *testing.T) {
+	c, closeFn, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("failed to create new bolt client: %v", err)
+	}
+	defer closeFn()
+
+	ctx := context.Background()
+	owner := &platform.Organization{Name: "owner"}
+	target := &platform.Organization{Name: "target"}
+	for _, o := range []*platform.Organization{owner, target} {
+		if err := c.CreateOrganization(ctx, o); err != nil {
+			t.Fatalf("unable to create organization: %v", err)
+		}
+	}
+
+	d := &platform.Dashboard{Name: "overview", OrganizationID: owner.ID}
+	if err := c.CreateDashboard(ctx, d); err != nil {
+		t.Fatalf("unable to create dashboard: %v", err)
+	}
+
+	g := &platform.ShareGrant{
+		ResourceType: platform.DashboardsResourceType,
+		ResourceID:   d.ID,
+		TargetOrgID:  target.ID,
+		Action:       platform.WriteAction,
+	}
+	if err := c.CreateShare(ctx, g); err != nil {
+		t.Fatalf("unable to create share: %v", err)
+	}
+
+	if err := c.DeleteShare(ctx, g.ID); err != nil {
+		t.Fatalf("unable to delete share: %v", err)
+	}
+	if _, n, err := c.FindShares(ctx, platform.ShareFilter{ResourceID: &d.ID}); err != nil || n != 0 {
+		t.Errorf("expected the grant to be revoked, got %d (%v)", n, err)
+	}
+	if err := c.DeleteShare(ctx, g.ID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Errorf("expected revoking twice to fail with not found, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package bolt
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 
@@ -9,10 +10,23 @@ import (
 )
 
 var (
-	telegrafBucket = []byte("telegrafv1")
+	telegrafBucket      = []byte("telegrafv1")
+	telegrafAgentBucket = []byte("telegrafagentsv1")
+	telegrafNameIndex   = []byte("telegrafnameindexv1")
 )
 
+// telegrafPages orders telegraf configs for listings.
+var telegrafPages = &pageIndex{
+	bucket:  telegrafBucket,
+	indexes: map[string][]byte{"Name": telegrafNameIndex},
+	decode: func(v []byte) (platform.Pageable, error) {
+		tc := &platform.TelegrafConfig{}
+		return tc, json.Unmarshal(v, tc)
+	},
+}
+
 var _ platform.TelegrafConfigStore = new(Client)
+var _ platform.TelegrafAgentService = new(Client)
 
 func (c *Client) initializeTelegraf(ctx context.Context, tx *bolt.Tx) error {
 	if _, err := tx.CreateBucketIfNotExists(telegrafBucket); err != nil {
@@ -21,6 +35,19 @@ func (c *Client) initializeTelegraf(ctx context.Context, tx *bolt.Tx) error {
 	return nil
 }
 
+// createTelegrafAgentBucket creates the bucket holding the agents that
+// fetched each telegraf config.
+func createTelegrafAgentBucket(ctx context.Context, c *Client, tx *bolt.Tx) error {
+	if _, err := tx.CreateBucketIfNotExists(telegrafAgentBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+func dropTelegrafAgentBucket(ctx context.Context, c *Client, tx *bolt.Tx) error {
+	return tx.DeleteBucket(telegrafAgentBucket)
+}
+
 // FindTelegrafConfigByID returns a single telegraf config by ID.
 func (c *Client) FindTelegrafConfigByID(ctx context.Context, id platform.ID) (tc *platform.TelegrafConfig, err error) {
 	op := OpPrefix + platform.OpFindTelegrafConfigByID
@@ -77,34 +104,69 @@ func (c *Client) FindTelegrafConfig(ctx context.Context, filter platform.Telegra
 	}
 }
 
-func (c *Client) findTelegrafConfigs(ctx context.Context, tx *bolt.Tx, filter platform.TelegrafConfigFilter, opt ...platform.FindOptions) ([]*platform.TelegrafConfig, int, *platform.Error) {
+func (c *Client) findTelegrafConfigs(ctx context.Context, tx *bolt.Tx, filter platform.TelegrafConfigFilter, opts platform.FindOptions) ([]*platform.TelegrafConfig, *platform.Error) {
 	tcs := make([]*platform.TelegrafConfig, 0)
 	m, err := c.findUserResourceMappings(ctx, tx, filter.UserResourceMappingFilter)
 	if err != nil {
-		return nil, 0, &platform.Error{
+		return nil, &platform.Error{
 			Err: err,
 		}
 	}
 	if len(m) == 0 {
-		return tcs, 0, nil
+		return tcs, nil
 	}
+	mapped := make(map[platform.ID]bool, len(m))
 	for _, item := range m {
-		tc, err := c.findTelegrafConfigByID(ctx, tx, item.ResourceID)
-		if err != nil && platform.ErrorCode(err) != platform.ENotFound {
-			return nil, 0, &platform.Error{
-				// return internal error, for any mapping issue
-				Err: err,
-			}
+		mapped[item.ResourceID] = true
+	}
+	labeled, err := c.labelFilter(ctx, tx, filter.Labels)
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
 		}
-		if tc != nil {
-			// Restrict results by organization ID, if it has been provided
-			if filter.OrganizationID != nil && filter.OrganizationID.Valid() && tc.OrganizationID != *filter.OrganizationID {
-				continue
-			}
-			tcs = append(tcs, tc)
+	}
+
+	p := &pager{opts: opts}
+	err = c.forEachTelegrafConfig(ctx, tx, opts, func(tc *platform.TelegrafConfig) bool {
+		if !mapped[tc.ID] || !labeled(tc.ID) {
+			return true
+		}
+		// Restrict results by organization ID, if it has been provided
+		if filter.OrganizationID != nil && filter.OrganizationID.Valid() && tc.OrganizationID != *filter.OrganizationID {
+			return true
+		}
+		return p.add(tc)
+	})
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
+
+	for _, tc := range p.results() {
+		tcs = append(tcs, tc.(*platform.TelegrafConfig))
+	}
+	return tcs, nil
+}
+
+// forEachTelegrafConfig will iterate through telegraf configs in the order given by opts while fn returns true.
+func (c *Client) forEachTelegrafConfig(ctx context.Context, tx *bolt.Tx, opts platform.FindOptions, fn func(*platform.TelegrafConfig) bool) error {
+	s, err := telegrafPages.scan(tx, opts)
+	if err != nil {
+		return err
+	}
+
+	for v := s.next(); v != nil; v = s.next() {
+		tc := new(platform.TelegrafConfig)
+		if err := json.Unmarshal(v, tc); err != nil {
+			return err
+		}
+		if !fn(tc) {
+			break
 		}
 	}
-	return tcs, len(tcs), nil
+
+	return nil
 }
 
 // FindTelegrafConfigs returns a list of telegraf configs that match filter and the total count of matching telegraf configs.
@@ -113,7 +175,7 @@ func (c *Client) FindTelegrafConfigs(ctx context.Context, filter platform.Telegr
 	op := OpPrefix + platform.OpFindTelegrafConfigs
 	err = c.db.View(func(tx *bolt.Tx) error {
 		var pErr *platform.Error
-		tcs, n, pErr = c.findTelegrafConfigs(ctx, tx, filter)
+		tcs, pErr = c.findTelegrafConfigs(ctx, tx, filter, findOptions(opt))
 		if pErr != nil {
 			pErr.Op = op
 			return pErr
@@ -143,6 +205,11 @@ func (c *Client) putTelegrafConfig(ctx context.Context, tx *bolt.Tx, tc *platfor
 			Msg:  platform.ErrTelegrafConfigInvalidOrganizationID,
 		}
 	}
+	if err := telegrafPages.put(tx, encodedID, tc); err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
 	err = tx.Bucket(telegrafBucket).Put(encodedID, v)
 	if err != nil {
 		return &platform.Error{
@@ -157,6 +224,7 @@ func (c *Client) CreateTelegrafConfig(ctx context.Context, tc *platform.Telegraf
 	op := OpPrefix + platform.OpCreateTelegrafConfig
 	return c.db.Update(func(tx *bolt.Tx) error {
 		tc.ID = c.IDGenerator.ID()
+		tc.Version = 1
 
 		pErr := c.putTelegrafConfig(ctx, tx, tc)
 		if pErr != nil {
@@ -192,6 +260,7 @@ func (c *Client) UpdateTelegrafConfig(ctx context.Context, id platform.ID, tc *p
 		tc.ID = id
 		// OrganizationID can not be updated
 		tc.OrganizationID = current.OrganizationID
+		tc.Version = current.Version + 1
 		pErr = c.putTelegrafConfig(ctx, tx, tc)
 		if pErr != nil {
 			return &platform.Error{
@@ -214,10 +283,16 @@ func (c *Client) DeleteTelegrafConfig(ctx context.Context, id platform.ID) error
 				Err:  err,
 			}
 		}
+		if err := telegrafPages.delete(tx, encodedID); err != nil {
+			return err
+		}
 		err = tx.Bucket(telegrafBucket).Delete(encodedID)
 		if err != nil {
 			return err
 		}
+		if err := c.deleteTelegrafAgents(ctx, tx, encodedID); err != nil {
+			return err
+		}
 		return c.deleteUserResourceMappings(ctx, tx, platform.UserResourceMappingFilter{
 			ResourceID:   id,
 			ResourceType: platform.TelegrafsResourceType,
@@ -243,3 +318,95 @@ func (c *Client) PutTelegrafConfig(ctx context.Context, tc *platform.TelegrafCon
 		return nil
 	})
 }
+
+// telegrafAgentKey returns the key of the agent hostname of the telegraf config
+// encodedID, so that the agents of a config share a prefix.
+func telegrafAgentKey(encodedID []byte, hostname string) []byte {
+	k := make([]byte, 0, len(encodedID)+len(hostname))
+	k = append(k, encodedID...)
+	return append(k, hostname...)
+}
+
+// CheckInTelegrafAgent records that an agent fetched a version of a telegraf config.
+func (c *Client) CheckInTelegrafAgent(ctx context.Context, a *platform.TelegrafAgent) error {
+	op := OpPrefix + platform.OpCheckInTelegrafAgent
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		encodedID, err := a.TelegrafID.Encode()
+		if err != nil {
+			return &platform.Error{
+				Code: platform.EEmptyValue,
+				Err:  err,
+			}
+		}
+		if a.Hostname == "" {
+			return &platform.Error{
+				Code: platform.EEmptyValue,
+				Msg:  "telegraf agent hostname is required",
+			}
+		}
+		v, err := json.Marshal(a)
+		if err != nil {
+			return &platform.Error{
+				Err: err,
+			}
+		}
+		return tx.Bucket(telegrafAgentBucket).Put(telegrafAgentKey(encodedID, a.Hostname), v)
+	})
+	if err != nil {
+		return &platform.Error{
+			Code: platform.ErrorCode(err),
+			Op:   op,
+			Err:  err,
+		}
+	}
+	return nil
+}
+
+// FindTelegrafAgents returns the agents that fetched the telegraf config id, by hostname.
+func (c *Client) FindTelegrafAgents(ctx context.Context, id platform.ID) ([]*platform.TelegrafAgent, error) {
+	op := OpPrefix + platform.OpFindTelegrafAgents
+	agents := []*platform.TelegrafAgent{}
+	err := c.db.View(func(tx *bolt.Tx) error {
+		encodedID, err := id.Encode()
+		if err != nil {
+			return &platform.Error{
+				Code: platform.EEmptyValue,
+				Err:  err,
+			}
+		}
+		cur := tx.Bucket(telegrafAgentBucket).Cursor()
+		for k, v := cur.Seek(encodedID); bytes.HasPrefix(k, encodedID); k, v = cur.Next() {
+			a := new(platform.TelegrafAgent)
+			if err := json.Unmarshal(v, a); err != nil {
+				return &platform.Error{
+					Err: err,
+				}
+			}
+			agents = append(agents, a)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, &platform.Error{
+			Code: platform.ErrorCode(err),
+			Op:   op,
+			Err:  err,
+		}
+	}
+	return agents, nil
+}
+
+func (c *Client) deleteTelegrafAgents(ctx context.Context, tx *bolt.Tx, encodedID []byte) error {
+	b := tx.Bucket(telegrafAgentBucket)
+	var keys [][]byte
+	cur := b.Cursor()
+	for k, _ := cur.Seek(encodedID); bytes.HasPrefix(k, encodedID); k, _ = cur.Next() {
+		keys = append(keys, k)
+	}
+	for _, k := range keys {
+		if err := b.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -38,3 +38,7 @@ func initTelegrafConfigStore(f platformtesting.TelegrafConfigFields, t *testing.
 func TestTelegrafConfigStore(t *testing.T) {
 	platformtesting.TelegrafConfigStore(initTelegrafConfigStore, t)
 }
+
+func TestTelegrafAgentService(t *testing.T) {
+	platformtesting.TelegrafAgentService(initTelegrafConfigStore, t)
+}
@@ -14,9 +14,20 @@ import (
 var (
 	userBucket         = []byte("usersv1")
 	userIndex          = []byte("userindexv1")
+	userNameIndex      = []byte("usernameindexv1")
 	userpasswordBucket = []byte("userspasswordv1")
 )
 
+// userPages orders users for listings.
+var userPages = &pageIndex{
+	bucket:  userBucket,
+	indexes: map[string][]byte{"Name": userNameIndex},
+	decode: func(v []byte) (platform.Pageable, error) {
+		u := &platform.User{}
+		return u, json.Unmarshal(v, u)
+	},
+}
+
 var _ platform.UserService = (*Client)(nil)
 var _ platform.UserOperationLogService = (*Client)(nil)
 var _ platform.BasicAuthService = (*Client)(nil)
@@ -150,7 +161,7 @@ func (c *Client) FindUser(ctx context.Context, filter platform.UserFilter) (*pla
 	filterFn := filterUsersFn(filter)
 
 	err = c.db.View(func(tx *bolt.Tx) error {
-		return forEachUser(ctx, tx, func(usr *platform.User) bool {
+		return forEachUser(ctx, tx, platform.FindOptions{}, func(usr *platform.User) bool {
 			if filterFn(usr) {
 				u = usr
 				return false
@@ -221,12 +232,12 @@ func (c *Client) FindUsers(ctx context.Context, filter platform.UserFilter, opt
 		return []*platform.User{u}, 1, nil
 	}
 
-	us := []*platform.User{}
+	p := &pager{opts: findOptions(opt)}
 	filterFn := filterUsersFn(filter)
 	err := c.db.View(func(tx *bolt.Tx) error {
-		return forEachUser(ctx, tx, func(u *platform.User) bool {
+		return forEachUser(ctx, tx, p.opts, func(u *platform.User) bool {
 			if filterFn(u) {
-				us = append(us, u)
+				return p.add(u)
 			}
 			return true
 		})
@@ -236,6 +247,12 @@ func (c *Client) FindUsers(ctx context.Context, filter platform.UserFilter, opt
 		return nil, 0, err
 	}
 
+	page := p.results()
+	us := make([]*platform.User, 0, len(page))
+	for _, u := range page {
+		us = append(us, u.(*platform.User))
+	}
+
 	return us, len(us), nil
 }
 
@@ -287,6 +304,9 @@ func (c *Client) putUser(ctx context.Context, tx *bolt.Tx, u *platform.User) err
 	if err := tx.Bucket(userIndex).Put(userIndexKey(u.Name), encodedID); err != nil {
 		return err
 	}
+	if err := userPages.put(tx, encodedID, u); err != nil {
+		return err
+	}
 	return tx.Bucket(userBucket).Put(encodedID, v)
 }
 
@@ -294,10 +314,14 @@ func userIndexKey(n string) []byte {
 	return []byte(n)
 }
 
-// forEachUser will iterate through all users while fn returns true.
-func forEachUser(ctx context.Context, tx *bolt.Tx, fn func(*platform.User) bool) error {
-	cur := tx.Bucket(userBucket).Cursor()
-	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+// forEachUser will iterate through users in the order given by opts while fn returns true.
+func forEachUser(ctx context.Context, tx *bolt.Tx, opts platform.FindOptions, fn func(*platform.User) bool) error {
+	s, err := userPages.scan(tx, opts)
+	if err != nil {
+		return err
+	}
+
+	for v := s.next(); v != nil; v = s.next() {
 		u := &platform.User{}
 		if err := json.Unmarshal(v, u); err != nil {
 			return err
@@ -401,6 +425,11 @@ func (c *Client) deleteUser(ctx context.Context, tx *bolt.Tx, id platform.ID) *p
 			Err: err,
 		}
 	}
+	if err := userPages.delete(tx, encodedID); err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
 	if err := tx.Bucket(userBucket).Delete(encodedID); err != nil {
 		return &platform.Error{
 			Err: err,
@@ -420,7 +449,7 @@ func (c *Client) deleteUsersAuthorizations(ctx context.Context, tx *bolt.Tx, id
 	authFilter := platform.AuthorizationFilter{
 		UserID: &id,
 	}
-	as, err := c.findAuthorizations(ctx, tx, authFilter)
+	as, err := c.findAuthorizations(ctx, tx, authFilter, platform.FindOptions{})
 	if err != nil {
 		return &platform.Error{
 			Err: err,
@@ -28,6 +28,12 @@ type Bucket struct {
 	RetentionPeriod     time.Duration `json:"retentionPeriod"`
 }
 
+// Cursor returns the position of the bucket in a listing sorted by sortBy.
+// Buckets may be sorted by ID or Name.
+func (b *Bucket) Cursor(sortBy string) (PageCursor, error) {
+	return namePageCursor(sortBy, b.Name, b.ID)
+}
+
 // ops for buckets error and buckets op logs.
 var (
 	OpFindBucketByID = "FindBucketByID"
@@ -74,6 +80,7 @@ type BucketFilter struct {
 	Name           *string
 	OrganizationID *ID
 	Organization   *string
+	Labels         LabelSelector
 }
 
 // QueryParams Converts BucketFilter fields to url query params.
@@ -95,6 +102,10 @@ func (f BucketFilter) QueryParams() map[string][]string {
 		qp["org"] = []string{*f.Organization}
 	}
 
+	for k, v := range f.Labels.QueryParams() {
+		qp[k] = v
+	}
+
 	return qp
 }
 
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/ghodss/yaml"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influx/internal"
+	"github.com/influxdata/influxdb/http"
+	"github.com/spf13/cobra"
+)
+
+// Dashboard Command
+var dashboardCmd = &cobra.Command{
+	Use:   "dashboard",
+	Short: "Dashboard management commands",
+	Run:   dashboardF,
+}
+
+func dashboardF(cmd *cobra.Command, args []string) {
+	cmd.Usage()
+}
+
+func newDashboardService(f Flags) *http.DashboardService {
+	return &http.DashboardService{
+		Addr:  f.host,
+		Token: f.token,
+	}
+}
+
+// DashboardExportFlags define the Export Command
+type DashboardExportFlags struct {
+	id     string
+	file   string
+	format string
+}
+
+var dashboardExportFlags DashboardExportFlags
+
+func init() {
+	dashboardExportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export a dashboard with its views, macros and labels",
+		RunE:  wrapCheckSetup(dashboardExportF),
+	}
+
+	dashboardExportCmd.Flags().StringVarP(&dashboardExportFlags.id, "id", "i", "", "The ID of the dashboard (required)")
+	dashboardExportCmd.Flags().StringVarP(&dashboardExportFlags.file, "file", "f", "", "Path to write the dashboard to; defaults to stdout")
+	dashboardExportCmd.Flags().StringVarP(&dashboardExportFlags.format, "format", "", "json", "Format of the dashboard, json or yaml")
+	dashboardExportCmd.MarkFlagRequired("id")
+
+	dashboardCmd.AddCommand(dashboardExportCmd)
+}
+
+func dashboardExportF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	var id platform.ID
+	if err := id.DecodeFromString(dashboardExportFlags.id); err != nil {
+		return fmt.Errorf("failed to decode dashboard id %q: %v", dashboardExportFlags.id, err)
+	}
+
+	s := newDashboardService(flags)
+	e, err := s.ExportDashboard(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to export dashboard: %v", err)
+	}
+
+	var b []byte
+	switch dashboardExportFlags.format {
+	case "json":
+		b, err = json.MarshalIndent(e, "", "  ")
+		b = append(b, '\n')
+	case "yaml":
+		b, err = yaml.Marshal(e)
+	default:
+		return fmt.Errorf("unknown format %q", dashboardExportFlags.format)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode dashboard: %v", err)
+	}
+
+	if dashboardExportFlags.file == "" {
+		_, err = os.Stdout.Write(b)
+		return err
+	}
+	return ioutil.WriteFile(dashboardExportFlags.file, b, 0644)
+}
+
+// DashboardImportFlags define the Import Command
+type DashboardImportFlags struct {
+	TemplateOrgFlags
+	file string
+}
+
+var dashboardImportFlags DashboardImportFlags
+
+func init() {
+	dashboardImportCmd := &cobra.Command{
+		Use:   "import",
+		Short: "Create a dashboard in an organization from an export",
+		Long: `Create a dashboard in an organization from a JSON or YAML export.
+The dashboard and its cells are given new IDs. Macros missing from the
+organization and labels that do not exist are created; existing ones are
+matched by name.`,
+		RunE: wrapCheckSetup(dashboardImportF),
+	}
+
+	dashboardImportCmd.Flags().StringVarP(&dashboardImportFlags.file, "file", "f", "", "Path to the dashboard export (required)")
+	dashboardImportCmd.Flags().StringVarP(&dashboardImportFlags.org, "org", "o", "", "The name of the organization")
+	dashboardImportCmd.Flags().StringVarP(&dashboardImportFlags.orgID, "org-id", "", "", "The ID of the organization")
+	dashboardImportCmd.MarkFlagRequired("file")
+
+	dashboardCmd.AddCommand(dashboardImportCmd)
+}
+
+func dashboardImportF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	orgID, err := dashboardImportFlags.find(ctx)
+	if err != nil {
+		return err
+	}
+
+	b, err := ioutil.ReadFile(dashboardImportFlags.file)
+	if err != nil {
+		return fmt.Errorf("failed to read dashboard: %v", err)
+	}
+
+	// YAML is a superset of JSON, so both formats decode the same way.
+	var e platform.DashboardExport
+	if err := yaml.Unmarshal(b, &e); err != nil {
+		return fmt.Errorf("failed to decode dashboard %q: %v", dashboardImportFlags.file, err)
+	}
+
+	s := newDashboardService(flags)
+	d, err := s.ImportDashboard(ctx, orgID, &e)
+	if err != nil {
+		return fmt.Errorf("failed to import dashboard: %v", err)
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"Name",
+		"OrgID",
+		"Cells",
+	)
+	w.Write(map[string]interface{}{
+		"ID":    d.ID.String(),
+		"Name":  d.Name,
+		"OrgID": d.OrganizationID.String(),
+		"Cells": len(d.Cells),
+	})
+	w.Flush()
+
+	return nil
+}
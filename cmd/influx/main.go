@@ -25,8 +25,11 @@ var influxCmd = &cobra.Command{
 }
 
 func init() {
+	influxCmd.AddCommand(applyCmd)
 	influxCmd.AddCommand(authorizationCmd)
 	influxCmd.AddCommand(bucketCmd)
+	influxCmd.AddCommand(dashboardCmd)
+	influxCmd.AddCommand(exportCmd)
 	influxCmd.AddCommand(organizationCmd)
 	influxCmd.AddCommand(queryCmd)
 	influxCmd.AddCommand(replCmd)
@@ -3,9 +3,13 @@ package main
 import (
 	"context"
 	"fmt"
+	"os"
+	"time"
 
 	"github.com/influxdata/flux/repl"
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influx/internal"
+	"github.com/influxdata/influxdb/http"
 	_ "github.com/influxdata/influxdb/query/builtin"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -39,43 +43,53 @@ func init() {
 	}
 }
 
-func fluxQueryF(cmd *cobra.Command, args []string) error {
-	if flags.local {
-		return fmt.Errorf("local flag not supported for query command")
-	}
-
-	if (queryFlags.OrgID != "" && queryFlags.Org != "") || (queryFlags.OrgID == "" && queryFlags.Org == "") {
-		return fmt.Errorf("must specify exactly one of org or org-id")
-	}
-
-	q, err := repl.LoadQuery(args[0])
-	if err != nil {
-		return fmt.Errorf("failed to load query: %v", err)
-	}
-
+// queryOrgID returns the ID of the organization given by the org or org-id flag.
+func queryOrgID() (platform.ID, error) {
 	var orgID platform.ID
 
 	if queryFlags.OrgID != "" {
 		if err := orgID.DecodeFromString(queryFlags.OrgID); err != nil {
-			return fmt.Errorf("failed to decode org-id: %v", err)
+			return 0, fmt.Errorf("failed to decode org-id: %v", err)
 		}
 	}
 
 	if queryFlags.Org != "" {
 		orgSvc, err := newOrganizationService(flags)
 		if err != nil {
-			return fmt.Errorf("failed to initialized organization service client: %v", err)
+			return 0, fmt.Errorf("failed to initialized organization service client: %v", err)
 		}
 
 		filter := platform.OrganizationFilter{Name: &queryFlags.Org}
 		o, err := orgSvc.FindOrganization(context.Background(), filter)
 		if err != nil {
-			return fmt.Errorf("failed to retrieve organization %q: %v", queryFlags.Org, err)
+			return 0, fmt.Errorf("failed to retrieve organization %q: %v", queryFlags.Org, err)
 		}
 
 		orgID = o.ID
 	}
 
+	return orgID, nil
+}
+
+func fluxQueryF(cmd *cobra.Command, args []string) error {
+	if flags.local {
+		return fmt.Errorf("local flag not supported for query command")
+	}
+
+	if (queryFlags.OrgID != "" && queryFlags.Org != "") || (queryFlags.OrgID == "" && queryFlags.Org == "") {
+		return fmt.Errorf("must specify exactly one of org or org-id")
+	}
+
+	q, err := repl.LoadQuery(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to load query: %v", err)
+	}
+
+	orgID, err := queryOrgID()
+	if err != nil {
+		return err
+	}
+
 	r, err := getFluxREPL(flags.host, flags.token, orgID)
 	if err != nil {
 		return fmt.Errorf("failed to get the flux REPL: %v", err)
@@ -87,3 +101,94 @@ func fluxQueryF(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func newRunningQueryService(f Flags) *http.RunningQueryService {
+	return &http.RunningQueryService{
+		Addr:  f.host,
+		Token: f.token,
+	}
+}
+
+func init() {
+	queryPsCmd := &cobra.Command{
+		Use:   "ps",
+		Short: "List the queries being run",
+		Long: `List the queries being run in the organizations readable with the token,
+or in the organization given by the org or org-id flag.`,
+		Args: cobra.NoArgs,
+		RunE: wrapCheckSetup(queryPsF),
+	}
+
+	queryKillCmd := &cobra.Command{
+		Use:   "kill [query ID]",
+		Short: "Cancel a query being run",
+		Args:  cobra.ExactArgs(1),
+		RunE:  wrapCheckSetup(queryKillF),
+	}
+
+	queryCmd.AddCommand(queryPsCmd, queryKillCmd)
+}
+
+func queryPsF(cmd *cobra.Command, args []string) error {
+	if queryFlags.OrgID != "" && queryFlags.Org != "" {
+		return fmt.Errorf("must specify at most one of org or org-id")
+	}
+
+	var filter platform.RunningQueryFilter
+	if queryFlags.OrgID != "" || queryFlags.Org != "" {
+		orgID, err := queryOrgID()
+		if err != nil {
+			return err
+		}
+		filter.OrganizationID = &orgID
+	}
+
+	s := newRunningQueryService(flags)
+	qs, err := s.FindRunningQueries(context.Background(), filter)
+	if err != nil {
+		return fmt.Errorf("failed to list queries: %v", err)
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"OrgID",
+		"UserID",
+		"State",
+		"Elapsed",
+		"MaxAllocated",
+		"Query",
+	)
+	for _, q := range qs {
+		userID := ""
+		if q.UserID.Valid() {
+			userID = q.UserID.String()
+		}
+		w.Write(map[string]interface{}{
+			"ID":           q.ID.String(),
+			"OrgID":        q.OrganizationID.String(),
+			"UserID":       userID,
+			"State":        q.State,
+			"Elapsed":      q.Elapsed.Round(time.Millisecond).String(),
+			"MaxAllocated": q.MaxAllocated,
+			"Query":        q.Query,
+		})
+	}
+	w.Flush()
+
+	return nil
+}
+
+func queryKillF(cmd *cobra.Command, args []string) error {
+	var id platform.ID
+	if err := id.DecodeFromString(args[0]); err != nil {
+		return fmt.Errorf("failed to decode query id %q: %v", args[0], err)
+	}
+
+	s := newRunningQueryService(flags)
+	if err := s.CancelRunningQuery(context.Background(), id); err != nil {
+		return fmt.Errorf("failed to cancel query: %v", err)
+	}
+
+	return nil
+}
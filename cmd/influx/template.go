@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/ghodss/yaml"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influx/internal"
+	"github.com/influxdata/influxdb/http"
+	"github.com/spf13/cobra"
+)
+
+// TemplateOrgFlags select the organization a template is exported from or applied to.
+type TemplateOrgFlags struct {
+	org   string
+	orgID string
+}
+
+func (f TemplateOrgFlags) find(ctx context.Context) (platform.ID, error) {
+	if (f.org == "" && f.orgID == "") || (f.org != "" && f.orgID != "") {
+		return platform.InvalidID(), fmt.Errorf("must specify exactly one of org or org-id")
+	}
+
+	if f.orgID != "" {
+		var id platform.ID
+		if err := id.DecodeFromString(f.orgID); err != nil {
+			return platform.InvalidID(), fmt.Errorf("failed to decode org id %q: %v", f.orgID, err)
+		}
+		return id, nil
+	}
+
+	orgSvc := &http.OrganizationService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+	o, err := orgSvc.FindOrganization(ctx, platform.OrganizationFilter{Name: &f.org})
+	if err != nil {
+		return platform.InvalidID(), fmt.Errorf("failed to find org %q: %v", f.org, err)
+	}
+	return o.ID, nil
+}
+
+func newTemplateService(f Flags) platform.TemplateService {
+	return &http.TemplateService{
+		Addr:  f.host,
+		Token: f.token,
+	}
+}
+
+// ApplyFlags define the Apply Command
+type ApplyFlags struct {
+	TemplateOrgFlags
+	file   string
+	dryRun bool
+}
+
+var applyFlags ApplyFlags
+
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Apply a template to an organization",
+	Long: `Create or update the buckets, tasks, dashboards, telegraf configs,
+labels, macros and scrapers of an organization to match a YAML or JSON template.
+Resources are matched by name, and resources absent from the template are left
+untouched, so applying the same template again changes nothing.`,
+	RunE: wrapCheckSetup(applyF),
+}
+
+func init() {
+	applyCmd.Flags().StringVarP(&applyFlags.file, "file", "f", "", "Path to the template file (required)")
+	applyCmd.Flags().BoolVarP(&applyFlags.dryRun, "dry-run", "", false, "Print the changes without making them")
+	applyCmd.Flags().StringVarP(&applyFlags.org, "org", "o", "", "The name of the organization")
+	applyCmd.Flags().StringVarP(&applyFlags.orgID, "org-id", "", "", "The ID of the organization")
+	applyCmd.MarkFlagRequired("file")
+}
+
+func applyF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	orgID, err := applyFlags.find(ctx)
+	if err != nil {
+		return err
+	}
+
+	b, err := ioutil.ReadFile(applyFlags.file)
+	if err != nil {
+		return fmt.Errorf("failed to read template: %v", err)
+	}
+
+	// YAML is a superset of JSON, so both formats decode the same way.
+	var t platform.Template
+	if err := yaml.Unmarshal(b, &t); err != nil {
+		return fmt.Errorf("failed to decode template %q: %v", applyFlags.file, err)
+	}
+
+	s := newTemplateService(flags)
+	changes, err := s.ApplyTemplate(ctx, orgID, &t, platform.TemplateApplyOptions{DryRun: applyFlags.dryRun})
+	if err != nil {
+		return fmt.Errorf("failed to apply template: %v", err)
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"Kind",
+		"Name",
+		"Action",
+		"ID",
+	)
+	for _, c := range changes {
+		id := ""
+		if c.ID.Valid() {
+			id = c.ID.String()
+		}
+		w.Write(map[string]interface{}{
+			"Kind":   c.Kind,
+			"Name":   c.Name,
+			"Action": c.Action,
+			"ID":     id,
+		})
+	}
+	w.Flush()
+
+	return nil
+}
+
+// ExportFlags define the Export Command
+type ExportFlags struct {
+	TemplateOrgFlags
+	file   string
+	format string
+}
+
+var exportFlags ExportFlags
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export an organization as a template",
+	RunE:  wrapCheckSetup(exportF),
+}
+
+func init() {
+	exportCmd.Flags().StringVarP(&exportFlags.file, "file", "f", "", "Path to write the template to; defaults to stdout")
+	exportCmd.Flags().StringVarP(&exportFlags.format, "format", "", "yaml", "Format of the template, yaml or json")
+	exportCmd.Flags().StringVarP(&exportFlags.org, "org", "o", "", "The name of the organization")
+	exportCmd.Flags().StringVarP(&exportFlags.orgID, "org-id", "", "", "The ID of the organization")
+}
+
+func exportF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	orgID, err := exportFlags.find(ctx)
+	if err != nil {
+		return err
+	}
+
+	s := newTemplateService(flags)
+	t, err := s.ExportTemplate(ctx, orgID)
+	if err != nil {
+		return fmt.Errorf("failed to export template: %v", err)
+	}
+
+	var b []byte
+	switch exportFlags.format {
+	case "yaml":
+		b, err = yaml.Marshal(t)
+	case "json":
+		b, err = json.MarshalIndent(t, "", "  ")
+		b = append(b, '\n')
+	default:
+		return fmt.Errorf("unknown format %q", exportFlags.format)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode template: %v", err)
+	}
+
+	if exportFlags.file == "" {
+		_, err = os.Stdout.Write(b)
+		return err
+	}
+	return ioutil.WriteFile(exportFlags.file, b, 0644)
+}
@@ -10,6 +10,7 @@ import (
 	"os/user"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync/atomic"
@@ -189,7 +190,8 @@ func (cmd *Command) processRetentionPolicy(sfile *tsdb.SeriesFile, dbName, rpNam
 
 				id, name := shards[i].ID, shards[i].Path
 				log := cmd.Logger.With(logger.Database(dbName), logger.RetentionPolicy(rpName), logger.Shard(id))
-				errC <- IndexShard(sfile, filepath.Join(dataDir, name), filepath.Join(walDir, name), cmd.maxLogFileSize, cmd.maxCacheSize, cmd.batchSize, log, cmd.Verbose)
+				shardDir := filepath.Join(dataDir, name)
+				errC <- IndexShard(sfile, filepath.Join(shardDir, "index"), shardDir, filepath.Join(walDir, name), cmd.maxLogFileSize, cmd.maxCacheSize, cmd.batchSize, log, cmd.Verbose)
 			}
 		}()
 	}
@@ -203,11 +205,12 @@ func (cmd *Command) processRetentionPolicy(sfile *tsdb.SeriesFile, dbName, rpNam
 	return nil
 }
 
-func IndexShard(sfile *tsdb.SeriesFile, dataDir, walDir string, maxLogFileSize int64, maxCacheSize uint64, batchSize int, log *zap.Logger, verboseLogging bool) error {
+// IndexShard builds a tsi1 index at indexPath, adding the series of the TSM
+// files in dataDir and of the WAL segments in walDir to the index and sfile.
+func IndexShard(sfile *tsdb.SeriesFile, indexPath, dataDir, walDir string, maxLogFileSize int64, maxCacheSize uint64, batchSize int, log *zap.Logger, verboseLogging bool) error {
 	log.Info("Rebuilding shard")
 
 	// Check if shard already has a TSI index.
-	indexPath := filepath.Join(dataDir, "index")
 	log.Info("Checking index path", zap.String("path", indexPath))
 	if _, err := os.Stat(indexPath); !os.IsNotExist(err) {
 		log.Info("tsi1 index already exists, skipping", zap.String("path", indexPath))
@@ -217,7 +220,7 @@ func IndexShard(sfile *tsdb.SeriesFile, dataDir, walDir string, maxLogFileSize i
 	log.Info("Opening shard")
 
 	// Remove temporary index files if this is being re-run.
-	tmpPath := filepath.Join(dataDir, ".index")
+	tmpPath := filepath.Join(filepath.Dir(indexPath), "."+filepath.Base(indexPath))
 	log.Info("Cleaning up partial index from previous run, if any")
 	if err := os.RemoveAll(tmpPath); err != nil {
 		return err
@@ -245,7 +248,7 @@ func IndexShard(sfile *tsdb.SeriesFile, dataDir, walDir string, maxLogFileSize i
 
 	// Write out tsm1 files.
 	// Find shard files.
-	tsmPaths, err := collectTSMFiles(dataDir)
+	tsmPaths, err := CollectTSMFiles(dataDir)
 	if err != nil {
 		return err
 	}
@@ -259,7 +262,7 @@ func IndexShard(sfile *tsdb.SeriesFile, dataDir, walDir string, maxLogFileSize i
 	}
 
 	// Write out wal files.
-	walPaths, err := collectWALFiles(walDir)
+	walPaths, err := CollectWALFiles(walDir)
 	if err != nil {
 		if !os.IsNotExist(err) {
 			return err
@@ -267,10 +270,8 @@ func IndexShard(sfile *tsdb.SeriesFile, dataDir, walDir string, maxLogFileSize i
 
 	} else {
 		log.Info("Building cache from wal files")
-		cache := tsm1.NewCache(tsm1.DefaultCacheMaxMemorySize)
-		loader := tsm1.NewCacheLoader(walPaths)
-		loader.WithLogger(log)
-		if err := loader.Load(cache); err != nil {
+		cache := tsm1.NewCache(maxCacheSize)
+		if err := LoadWAL(cache, walPaths, log); err != nil {
 			return err
 		}
 
@@ -393,7 +394,52 @@ func IndexTSMFile(index *tsi1.Index, path string, batchSize int, log *zap.Logger
 	return nil
 }
 
-func collectTSMFiles(path string) ([]string, error) {
+// LoadWAL writes the entries of the WAL segment files at paths to cache.
+// Unlike a tsm1.CacheLoader, it does not truncate corrupt segments, but skips
+// the rest of a segment after its first corrupt entry.
+func LoadWAL(cache *tsm1.Cache, paths []string, log *zap.Logger) error {
+	sort.Strings(paths)
+	for _, path := range paths {
+		if err := loadWALSegment(cache, path, log); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func loadWALSegment(cache *tsm1.Cache, path string, log *zap.Logger) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := wal.NewWALSegmentReader(f)
+	for r.Next() {
+		entry, err := r.Read()
+		if err != nil {
+			log.Warn("WAL segment corrupt, skipping remaining entries",
+				zap.String("path", path), zap.Int64("pos", r.Count()), zap.Error(err))
+			return nil
+		}
+
+		switch en := entry.(type) {
+		case *wal.WriteWALEntry:
+			if err := cache.WriteMulti(en.Values); err != nil {
+				return err
+			}
+
+		case *wal.DeleteBucketRangeWALEntry:
+			encoded := tsdb.EncodeName(en.OrgID, en.BucketID)
+			name := models.EscapeMeasurement(encoded[:])
+			cache.DeleteBucketRange(name, en.Min, en.Max)
+		}
+	}
+	return nil
+}
+
+// CollectTSMFiles returns the paths of the TSM files in the directory path.
+func CollectTSMFiles(path string) ([]string, error) {
 	fis, err := ioutil.ReadDir(path)
 	if err != nil {
 		return nil, err
@@ -409,7 +455,9 @@ func collectTSMFiles(path string) ([]string, error) {
 	return paths, nil
 }
 
-func collectWALFiles(path string) ([]string, error) {
+// CollectWALFiles returns the paths of the WAL segment files in the directory
+// path. It returns an error satisfying os.IsNotExist if path does not exist.
+func CollectWALFiles(path string) ([]string, error) {
 	if path == "" {
 		return nil, os.ErrNotExist
 	}
@@ -0,0 +1,334 @@
+package inspect
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "github.com/coreos/bbolt"
+	"github.com/influxdata/influxdb/cmd/influx_inspect/buildtsi"
+	"github.com/influxdata/influxdb/internal/fs"
+	influxlogger "github.com/influxdata/influxdb/logger"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/pkg/estimator/hll"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/tsi1"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// buildTSIDirName is the directory of the engine the series file and index are
+// built in before replacing the existing ones.
+const buildTSIDirName = ".build-tsi"
+
+type buildTSIConfig struct {
+	enginePath     string
+	boltPath       string
+	batchSize      int
+	maxLogFileSize int64
+	maxCacheSize   uint64
+	dryRun         bool
+	verbose        bool
+}
+
+var buildTSIFlags buildTSIConfig
+
+func newBuildTSICommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "build-tsi",
+		Short: "Rebuild the series file and tsi1 index of an engine from its TSM files and WAL",
+		Long: `Rebuild the series file and tsi1 index of an engine from its TSM files and WAL.
+
+The series file and index are built in a temporary directory of the engine and
+verified to hold every series of the TSM files and WAL before they replace the
+existing ones. The WAL is only read. influxd must not be running: the bolt
+store of influxd is locked while the series file and index are replaced.`,
+		Args: cobra.NoArgs,
+		RunE: buildTSIF,
+	}
+
+	defaultPath, defaultBoltPath := "engine", "influxd.bolt"
+	if dir, err := fs.InfluxDir(); err == nil {
+		defaultPath = filepath.Join(dir, defaultPath)
+		defaultBoltPath = filepath.Join(dir, defaultBoltPath)
+	}
+	cmd.Flags().StringVar(&buildTSIFlags.enginePath, "engine-path", defaultPath, "path to the engine")
+	cmd.Flags().StringVar(&buildTSIFlags.boltPath, "bolt-path", defaultBoltPath, "path to the bolt store of influxd, locked to ensure influxd is not running")
+	cmd.Flags().IntVar(&buildTSIFlags.batchSize, "batch-size", 10000, "size of the batches of series written to the index")
+	cmd.Flags().Int64Var(&buildTSIFlags.maxLogFileSize, "max-log-file-size", tsi1.DefaultMaxIndexLogFileSize, "maximum size of the index log files")
+	cmd.Flags().Uint64Var(&buildTSIFlags.maxCacheSize, "max-cache-size", tsm1.DefaultCacheMaxMemorySize, "maximum size of the cache the WAL is loaded into")
+	cmd.Flags().BoolVar(&buildTSIFlags.dryRun, "dry-run", false, "report the files and estimated series that would be indexed, without writing anything")
+	cmd.Flags().BoolVarP(&buildTSIFlags.verbose, "verbose", "v", false, "log every series indexed")
+	return cmd
+}
+
+func buildTSIF(cmd *cobra.Command, args []string) error {
+	logconf := &influxlogger.Config{
+		Format: "auto",
+		Level:  zapcore.InfoLevel,
+	}
+	log, err := logconf.New(os.Stderr)
+	if err != nil {
+		return err
+	}
+
+	return buildTSI(os.Stdout, buildTSIFlags, log)
+}
+
+// buildTSI rebuilds the series file and index of the engine at c.enginePath,
+// or reports what would be rebuilt if c.dryRun is set.
+func buildTSI(out io.Writer, c buildTSIConfig, log *zap.Logger) error {
+	config := storage.NewConfig()
+	seriesPath := config.GetSeriesFilePath(c.enginePath)
+	indexPath := config.GetIndexPath(c.enginePath)
+	dataPath := config.GetEnginePath(c.enginePath)
+	walPath := config.GetWALPath(c.enginePath)
+
+	tsmPaths, err := buildtsi.CollectTSMFiles(dataPath)
+	if err != nil {
+		return err
+	}
+	walPaths, err := buildtsi.CollectWALFiles(walPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if c.dryRun {
+		sketch := hll.NewDefaultPlus()
+		if err := forEachSeriesKey(tsmPaths, walPaths, c.maxCacheSize, log, func(key []byte) error {
+			sketch.Add(key)
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "TSM files: %d\n", len(tsmPaths))
+		fmt.Fprintf(out, "WAL segments: %d\n", len(walPaths))
+		fmt.Fprintf(out, "Estimated series: %d\n", sketch.Count())
+		fmt.Fprintf(out, "Would replace %s and %s\n", seriesPath, indexPath)
+		return nil
+	}
+
+	unlock, err := lockBolt(c.boltPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// Build the series file and index alongside the existing ones.
+	buildPath := filepath.Join(c.enginePath, buildTSIDirName)
+	if err := os.RemoveAll(buildPath); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(buildPath, 0777); err != nil {
+		return err
+	}
+	newSeriesPath := filepath.Join(buildPath, storage.DefaultSeriesFileDirectoryName)
+	newIndexPath := filepath.Join(buildPath, storage.DefaultIndexDirectoryName)
+
+	sfile := tsdb.NewSeriesFile(newSeriesPath)
+	sfile.WithLogger(log)
+	if err := sfile.Open(); err != nil {
+		return err
+	}
+	if err := buildtsi.IndexShard(sfile, newIndexPath, dataPath, walPath, c.maxLogFileSize, c.maxCacheSize, c.batchSize, log, c.verbose); err != nil {
+		sfile.Close()
+		return err
+	}
+	if err := sfile.Close(); err != nil {
+		return err
+	}
+
+	log.Info("Verifying rebuilt series file and index")
+	n, err := verifyTSI(newSeriesPath, newIndexPath, tsmPaths, walPaths, c.maxCacheSize, log)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Replacing series file and index", zap.String("series_path", seriesPath), zap.String("index_path", indexPath))
+	if err := replaceDirs([]string{seriesPath, indexPath}, []string{newSeriesPath, newIndexPath}); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(buildPath); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Rebuilt series file and index with %d series\n", n)
+	return nil
+}
+
+// forEachSeriesKey calls fn with the series key of every key of the TSM files
+// and of the WAL segments. A series key is passed once per field.
+func forEachSeriesKey(tsmPaths, walPaths []string, maxCacheSize uint64, log *zap.Logger, fn func(key []byte) error) error {
+	for _, path := range tsmPaths {
+		if err := forEachTSMSeriesKey(path, log, fn); err != nil {
+			return err
+		}
+	}
+
+	if len(walPaths) == 0 {
+		return nil
+	}
+	cache := tsm1.NewCache(maxCacheSize)
+	if err := buildtsi.LoadWAL(cache, walPaths, log); err != nil {
+		return err
+	}
+	for _, key := range cache.Keys() {
+		seriesKey, _ := tsm1.SeriesAndFieldFromCompositeKey(key)
+		if err := fn(seriesKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func forEachTSMSeriesKey(path string, log *zap.Logger, fn func(key []byte) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Unreadable files are skipped when indexing, so they are here too.
+	r, err := tsm1.NewTSMReader(f)
+	if err != nil {
+		log.Warn("Unable to read, skipping", zap.String("path", path), zap.Error(err))
+		return nil
+	}
+	defer r.Close()
+
+	iter := r.Iterator(nil)
+	for iter.Next() {
+		seriesKey, _ := tsm1.SeriesAndFieldFromCompositeKey(iter.Key())
+		if err := fn(seriesKey); err != nil {
+			return err
+		}
+	}
+	return iter.Err()
+}
+
+// verifyTSI checks every series of the TSM files and WAL segments is in the
+// series file and index at seriesPath and indexPath, and returns the number of
+// series in the series file.
+func verifyTSI(seriesPath, indexPath string, tsmPaths, walPaths []string, maxCacheSize uint64, log *zap.Logger) (uint64, error) {
+	sfile := tsdb.NewSeriesFile(seriesPath)
+	sfile.WithLogger(log)
+	if err := sfile.Open(); err != nil {
+		return 0, err
+	}
+	defer sfile.Close()
+
+	index := tsi1.NewIndex(sfile, tsi1.NewConfig(),
+		tsi1.WithPath(indexPath),
+		tsi1.DisableMetrics(),
+	)
+	index.WithLogger(log)
+	if err := index.Open(); err != nil {
+		return 0, err
+	}
+	defer index.Close()
+
+	ids := index.SeriesIDSet()
+	var checked, missing int
+	if err := forEachSeriesKey(tsmPaths, walPaths, maxCacheSize, log, func(key []byte) error {
+		checked++
+		name, tags := models.ParseKeyBytes(key)
+		id := sfile.SeriesID(name, tags, nil)
+		if id.IsZero() || !ids.Contains(id) {
+			if missing == 0 {
+				log.Error("Series missing from rebuilt index", zap.String("key", string(key)))
+			}
+			missing++
+		}
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+
+	if missing > 0 {
+		return 0, fmt.Errorf("%d of %d series keys missing from rebuilt series file and index in %s", missing, checked, filepath.Dir(indexPath))
+	}
+	if n := sfile.SeriesCount(); uint64(index.SeriesN()) != n {
+		return 0, fmt.Errorf("rebuilt index holds %d series but series file holds %d", index.SeriesN(), n)
+	}
+	return sfile.SeriesCount(), nil
+}
+
+// lockBolt takes the lock of the bolt store at path, which influxd holds while
+// it is running, and returns a function releasing it. A store that does not
+// exist is not locked.
+func lockBolt(path string) (func() error, error) {
+	if path == "" {
+		return func() error { return nil }, nil
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return func() error { return nil }, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 1 * time.Second, ReadOnly: true})
+	if err == bolt.ErrTimeout {
+		return nil, fmt.Errorf("unable to lock %s: influxd must be stopped before rebuilding the series file and index", path)
+	} else if err != nil {
+		return nil, err
+	}
+	return db.Close, nil
+}
+
+// replaceDirs replaces each directory of paths with the one at the same
+// position of newPaths. Every existing directory is moved aside before any new
+// one is moved into place, and every directory is moved back if any of the
+// renames fails, so either all of the directories are replaced or none are.
+func replaceDirs(paths, newPaths []string) (err error) {
+	oldPaths := make([]string, len(paths))
+	for i, path := range paths {
+		oldPaths[i] = path + ".old"
+		if err := os.RemoveAll(oldPaths[i]); err != nil {
+			return err
+		}
+	}
+
+	var moved, replaced []int
+	defer func() {
+		if err == nil {
+			return
+		}
+		for _, i := range replaced {
+			os.Rename(paths[i], newPaths[i])
+		}
+		for _, i := range moved {
+			os.Rename(oldPaths[i], paths[i])
+		}
+	}()
+
+	for i, path := range paths {
+		if err := os.Rename(path, oldPaths[i]); os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		moved = append(moved, i)
+	}
+	for i, path := range paths {
+		if err := os.Rename(newPaths[i], path); err != nil {
+			return err
+		}
+		replaced = append(replaced, i)
+	}
+
+	// Every directory is replaced, so the old ones are no longer rolled back to.
+	old := moved
+	moved, replaced = nil, nil
+	for _, i := range old {
+		if err := os.RemoveAll(oldPaths[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package inspect
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	bolt "github.com/coreos/bbolt"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/storage/wal"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/tsi1"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+	"github.com/influxdata/influxdb/tsdb/value"
+	"go.uber.org/zap"
+)
+
+// mustWriteEngine writes a TSM file holding the series cpu,host=A and a WAL
+// segment holding the series mem,host=B to the engine at path.
+func mustWriteEngine(t *testing.T, path string) {
+	t.Helper()
+
+	dataPath := filepath.Join(path, storage.DefaultEngineDirectoryName)
+	if err := os.MkdirAll(dataPath, 0777); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(filepath.Join(dataPath, "000000001-000000001.tsm"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := tsm1.NewTSMWriter(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write([]byte("cpu,host=A#!~#value"), tsm1.Values{tsm1.NewValue(1, 1.0)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteIndex(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	l := wal.NewWAL(filepath.Join(path, storage.DefaultWALDirectoryName))
+	if err := l.Open(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.WriteMulti(map[string][]value.Value{
+		"mem,host=B#!~#value": []value.Value{value.NewValue(1, int64(1))},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuildTSI(t *testing.T) {
+	dir, err := ioutil.TempDir("", "inspect-build-tsi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	mustWriteEngine(t, dir)
+
+	c := buildTSIConfig{
+		enginePath:     dir,
+		batchSize:      10,
+		maxLogFileSize: tsi1.DefaultMaxIndexLogFileSize,
+		maxCacheSize:   tsm1.DefaultCacheMaxMemorySize,
+		dryRun:         true,
+	}
+
+	// A dry run writes nothing.
+	var buf bytes.Buffer
+	if err := buildTSI(&buf, c, zap.NewNop()); err != nil {
+		t.Fatal(err)
+	}
+	if got, exp := buf.String(), "Estimated series: 2"; !strings.Contains(got, exp) {
+		t.Fatalf("unexpected output: got %q, exp it to contain %q", got, exp)
+	}
+	for _, name := range []string{storage.DefaultSeriesFileDirectoryName, storage.DefaultIndexDirectoryName, buildTSIDirName} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Fatalf("expected %s not to exist after a dry run, got %v", name, err)
+		}
+	}
+
+	c.dryRun = false
+	buf.Reset()
+	if err := buildTSI(&buf, c, zap.NewNop()); err != nil {
+		t.Fatal(err)
+	}
+	if got, exp := buf.String(), "with 2 series"; !strings.Contains(got, exp) {
+		t.Fatalf("unexpected output: got %q, exp it to contain %q", got, exp)
+	}
+	if _, err := os.Stat(filepath.Join(dir, buildTSIDirName)); !os.IsNotExist(err) {
+		t.Fatalf("expected build directory to be removed, got %v", err)
+	}
+
+	sfile := tsdb.NewSeriesFile(filepath.Join(dir, storage.DefaultSeriesFileDirectoryName))
+	if err := sfile.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer sfile.Close()
+	for _, key := range []string{"cpu,host=A", "mem,host=B"} {
+		name, tags := models.ParseKeyBytes([]byte(key))
+		if !sfile.HasSeries(name, tags, nil) {
+			t.Fatalf("expected series %s in rebuilt series file", key)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, storage.DefaultIndexDirectoryName)); err != nil {
+		t.Fatalf("expected rebuilt index: %v", err)
+	}
+}
+
+func TestBuildTSI_Running(t *testing.T) {
+	dir, err := ioutil.TempDir("", "inspect-build-tsi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	mustWriteEngine(t, dir)
+
+	// Hold the lock of the bolt store as a running influxd does.
+	boltPath := filepath.Join(dir, "influxd.bolt")
+	db, err := bolt.Open(boltPath, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	c := buildTSIConfig{
+		enginePath:     dir,
+		boltPath:       boltPath,
+		batchSize:      10,
+		maxLogFileSize: tsi1.DefaultMaxIndexLogFileSize,
+		maxCacheSize:   tsm1.DefaultCacheMaxMemorySize,
+	}
+	var buf bytes.Buffer
+	if err := buildTSI(&buf, c, zap.NewNop()); err == nil || !strings.Contains(err.Error(), "influxd must be stopped") {
+		t.Fatalf("expected an error locking the bolt store, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, buildTSIDirName)); !os.IsNotExist(err) {
+		t.Fatalf("expected nothing to be built, got %v", err)
+	}
+}
+
+func TestReplaceDirs_Rollback(t *testing.T) {
+	dir, err := ioutil.TempDir("", "inspect-replace-dirs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	paths := []string{filepath.Join(dir, "a"), filepath.Join(dir, "b")}
+	newPaths := []string{filepath.Join(dir, "new-a"), filepath.Join(dir, "new-b")}
+	for _, path := range append(paths, newPaths[0]) {
+		if err := os.MkdirAll(path, 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(path, "name"), []byte(filepath.Base(path)), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The second new directory is missing, so the first must not be replaced either.
+	if err := replaceDirs(paths, newPaths); err == nil {
+		t.Fatal("expected an error replacing a directory with a missing one")
+	}
+	for _, path := range append(paths, newPaths[0]) {
+		got, err := ioutil.ReadFile(filepath.Join(path, "name"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exp := filepath.Base(path); string(got) != exp {
+			t.Fatalf("unexpected directory at %s: got %q, exp %q", path, got, exp)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+// Package inspect implements the influxd inspect command, which examines and
+// repairs the files of the storage engine offline.
+package inspect
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewCommand returns the influxd inspect command.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "inspect",
+		Short:        "Inspect and repair the files of the storage engine",
+		SilenceUsage: true,
+	}
+
+	cmd.AddCommand(newWALCommand(), newBuildTSICommand())
+	return cmd
+}
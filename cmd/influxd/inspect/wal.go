@@ -0,0 +1,186 @@
+package inspect
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/influxdata/influxdb/internal/fs"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/storage/wal"
+	"github.com/spf13/cobra"
+)
+
+var walFlags struct {
+	walPath string
+	verbose bool
+}
+
+func newWALCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wal",
+		Short: "Dump or verify the entries of WAL segments",
+	}
+
+	defaultPath := filepath.Join("engine", storage.DefaultWALDirectoryName)
+	if dir, err := fs.InfluxDir(); err == nil {
+		defaultPath = filepath.Join(dir, defaultPath)
+	}
+	cmd.PersistentFlags().StringVar(&walFlags.walPath, "wal-path", defaultPath, "path to the WAL directory, used if no segment files are given")
+
+	dumpCmd := &cobra.Command{
+		Use:   "dump [segment files]",
+		Short: "Print the entries of WAL segments",
+		RunE:  walDumpF,
+	}
+	dumpCmd.Flags().BoolVarP(&walFlags.verbose, "verbose", "v", false, "print the values of write entries")
+
+	verifyCmd := &cobra.Command{
+		Use:   "verify [segment files]",
+		Short: "Check the entries of WAL segments are valid, without modifying them",
+		RunE:  walVerifyF,
+	}
+
+	cmd.AddCommand(dumpCmd, verifyCmd)
+	return cmd
+}
+
+// walSegments returns the segment files given as args, or those of the WAL
+// directory if there are none.
+func walSegments(args []string) ([]string, error) {
+	if len(args) > 0 {
+		files := append([]string(nil), args...)
+		sort.Strings(files)
+		return files, nil
+	}
+
+	files, err := wal.SegmentFileNames(walFlags.walPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no WAL segments found in %s", walFlags.walPath)
+	}
+	return files, nil
+}
+
+func walDumpF(cmd *cobra.Command, args []string) error {
+	files, err := walSegments(args)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if err := dumpSegment(os.Stdout, file, walFlags.verbose); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func walVerifyF(cmd *cobra.Command, args []string) error {
+	files, err := walSegments(args)
+	if err != nil {
+		return err
+	}
+
+	var corrupt int
+	for _, file := range files {
+		ok, err := verifySegment(os.Stdout, file)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			corrupt++
+		}
+	}
+
+	if corrupt > 0 {
+		return fmt.Errorf("%d of %d WAL segments are corrupt", corrupt, len(files))
+	}
+	return nil
+}
+
+// readSegment calls fn with the offset of each entry of the segment file and
+// the entry. It returns the size of the valid entries of the file, the size of
+// the file and the error reading the first invalid entry, if any.
+func readSegment(file string, fn func(offset int64, entry wal.WALEntry)) (valid, size int64, entryErr, err error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	r := wal.NewWALSegmentReader(f)
+	for offset := int64(0); r.Next(); offset = r.Count() {
+		entry, err := r.Read()
+		if err != nil {
+			return r.Count(), stat.Size(), err, nil
+		}
+		fn(offset, entry)
+	}
+	return r.Count(), stat.Size(), nil, nil
+}
+
+func dumpSegment(out io.Writer, file string, verbose bool) error {
+	fmt.Fprintf(out, "%s:\n", file)
+
+	valid, size, entryErr, err := readSegment(file, func(offset int64, entry wal.WALEntry) {
+		switch e := entry.(type) {
+		case *wal.WriteWALEntry:
+			keys := make([]string, 0, len(e.Values))
+			var n int
+			for k, values := range e.Values {
+				keys = append(keys, k)
+				n += len(values)
+			}
+			sort.Strings(keys)
+
+			fmt.Fprintf(out, "  [%d] write: %d keys, %d values\n", offset, len(keys), n)
+			if !verbose {
+				return
+			}
+			for _, k := range keys {
+				for _, v := range e.Values[k] {
+					fmt.Fprintf(out, "    %s %d %v\n", k, v.UnixNano(), v.Value())
+				}
+			}
+
+		case *wal.DeleteBucketRangeWALEntry:
+			fmt.Fprintf(out, "  [%d] delete bucket range: org %s, bucket %s, min %d, max %d\n",
+				offset, e.OrgID, e.BucketID, e.Min, e.Max)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if entryErr != nil {
+		fmt.Fprintf(out, "  [%d] corrupt: %v (%d bytes unreadable)\n", valid, entryErr, size-valid)
+	}
+	return nil
+}
+
+// verifySegment prints whether the entries of the segment file are valid and
+// returns false if they are not.
+func verifySegment(out io.Writer, file string) (bool, error) {
+	var entries int
+	valid, size, entryErr, err := readSegment(file, func(int64, wal.WALEntry) { entries++ })
+	if err != nil {
+		return false, err
+	}
+
+	if entryErr != nil {
+		fmt.Fprintf(out, "%s: corrupt at offset %d of %d bytes after %d entries: %v\n", file, valid, size, entries, entryErr)
+		return false, nil
+	}
+	fmt.Fprintf(out, "%s: %d entries, %d bytes OK\n", file, entries, size)
+	return true, nil
+}
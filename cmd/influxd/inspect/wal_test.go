@@ -0,0 +1,72 @@
+package inspect
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb/storage/wal"
+	"github.com/influxdata/influxdb/tsdb/value"
+)
+
+func TestVerifySegment(t *testing.T) {
+	dir, err := ioutil.TempDir("", "inspect-wal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	w := wal.NewWAL(dir)
+	if err := w.Open(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := w.WriteMulti(map[string][]value.Value{
+			"cpu,host=A#!~#value": []value.Value{value.NewValue(int64(i), float64(i))},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := wal.SegmentFileNames(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if ok, err := verifySegment(&buf, files[0]); err != nil || !ok {
+		t.Fatalf("expected valid segment, got %v, %v: %s", ok, err, buf.String())
+	}
+	if got, exp := buf.String(), "2 entries"; !strings.Contains(got, exp) {
+		t.Fatalf("unexpected output: got %q, exp it to contain %q", got, exp)
+	}
+
+	stat, err := os.Stat(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Truncate(files[0], stat.Size()-1); err != nil {
+		t.Fatal(err)
+	}
+
+	buf.Reset()
+	if ok, err := verifySegment(&buf, files[0]); err != nil || ok {
+		t.Fatalf("expected corrupt segment, got %v, %v: %s", ok, err, buf.String())
+	}
+	if got, exp := buf.String(), "after 1 entries"; !strings.Contains(got, exp) {
+		t.Fatalf("unexpected output: got %q, exp it to contain %q", got, exp)
+	}
+
+	buf.Reset()
+	if err := dumpSegment(&buf, files[0], true); err != nil {
+		t.Fatal(err)
+	}
+	if got, exp := buf.String(), "cpu,host=A#!~#value 0 0"; !strings.Contains(got, exp) {
+		t.Fatalf("unexpected output: got %q, exp it to contain %q", got, exp)
+	}
+}
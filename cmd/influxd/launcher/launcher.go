@@ -24,11 +24,13 @@ import (
 	"github.com/influxdata/influxdb/kit/cli"
 	"github.com/influxdata/influxdb/kit/prom"
 	influxlogger "github.com/influxdata/influxdb/logger"
+	"github.com/influxdata/influxdb/macro"
 	"github.com/influxdata/influxdb/nats"
 	infprom "github.com/influxdata/influxdb/prometheus"
 	"github.com/influxdata/influxdb/proto"
 	"github.com/influxdata/influxdb/query"
 	pcontrol "github.com/influxdata/influxdb/query/control"
+	"github.com/influxdata/influxdb/query/querylog"
 	"github.com/influxdata/influxdb/snowflake"
 	"github.com/influxdata/influxdb/source"
 	"github.com/influxdata/influxdb/storage"
@@ -38,8 +40,9 @@ import (
 	taskbolt "github.com/influxdata/influxdb/task/backend/bolt"
 	"github.com/influxdata/influxdb/task/backend/coordinator"
 	taskexecutor "github.com/influxdata/influxdb/task/backend/executor"
+	"github.com/influxdata/influxdb/toml"
 	_ "github.com/influxdata/influxdb/tsdb/tsi1" // needed for tsi1
-	_ "github.com/influxdata/influxdb/tsdb/tsm1" // needed for tsm1
+	"github.com/influxdata/influxdb/tsdb/tsm1"
 	"github.com/influxdata/influxdb/vault"
 	pzap "github.com/influxdata/influxdb/zap"
 	opentracing "github.com/opentracing/opentracing-go"
@@ -64,6 +67,24 @@ type Launcher struct {
 	protosPath      string
 	secretStore     string
 
+	scraperDiscoveryConfig string
+
+	compactionPlanner        string
+	compactionTimeWindow     time.Duration
+	compactionBlockSummaries bool
+
+	dashboardVersionLimit int
+
+	queryLogDisabled   bool
+	slowQueryThreshold time.Duration
+
+	queryConcurrency    int
+	queryMemoryBytes    int
+	queryMaxMemoryBytes int
+	queryOrgMemoryBytes int
+	queryQueueSize      int
+	queryQueueTimeout   time.Duration
+
 	boltClient *bolt.Client
 	engine     *storage.Engine
 
@@ -210,6 +231,24 @@ func (m *Launcher) Run(ctx context.Context, args ...string) error {
 				Default: filepath.Join(dir, "engine"),
 				Desc:    "path to persistent engine files",
 			},
+			{
+				DestP:   &m.compactionPlanner,
+				Flag:    "storage-compaction-planner",
+				Default: tsm1.DefaultCompactPlanner,
+				Desc:    "planner of TSM file compactions (level or time-window); time-window compacts backfilled data within its own window of time",
+			},
+			{
+				DestP:   &m.compactionTimeWindow,
+				Flag:    "storage-compaction-time-window",
+				Default: tsm1.DefaultCompactTimeWindow,
+				Desc:    "duration of the windows of time TSM files are grouped by with the time-window compaction planner",
+			},
+			{
+				DestP:   &m.compactionBlockSummaries,
+				Flag:    "storage-compaction-block-summaries",
+				Default: tsm1.DefaultCompactBlockSummaries,
+				Desc:    "write summary statistics of each block to compacted TSM files, at the cost of decoding the blocks compactions copy",
+			},
 			{
 				DestP:   &m.secretStore,
 				Flag:    "secret-store",
@@ -222,6 +261,66 @@ func (m *Launcher) Run(ctx context.Context, args ...string) error {
 				Default: filepath.Join(dir, "protos"),
 				Desc:    "path to protos on the filesystem",
 			},
+			{
+				DestP:   &m.scraperDiscoveryConfig,
+				Flag:    "scraper-discovery-config",
+				Default: "",
+				Desc:    "path to a YAML or JSON list of file and DNS discovery configs of scraper targets",
+			},
+			{
+				DestP:   &m.dashboardVersionLimit,
+				Flag:    "dashboard-version-limit",
+				Default: bolt.DefaultDashboardVersionLimit,
+				Desc:    "number of prior versions kept per dashboard",
+			},
+			{
+				DestP:   &m.queryLogDisabled,
+				Flag:    "query-log-disabled",
+				Default: false,
+				Desc:    "disable writing completed queries to the query log system bucket of each organization",
+			},
+			{
+				DestP:   &m.slowQueryThreshold,
+				Flag:    "slow-query-threshold",
+				Default: time.Duration(0),
+				Desc:    "log queries taking at least this duration; zero disables slow query logging",
+			},
+			{
+				DestP:   &m.queryConcurrency,
+				Flag:    "query-concurrency",
+				Default: 10,
+				Desc:    "number of queries run at once; the others wait in a queue, interactive queries before tasks",
+			},
+			{
+				DestP:   &m.queryMemoryBytes,
+				Flag:    "query-memory-bytes",
+				Default: 0,
+				Desc:    "memory the queries run at once may reserve in total; zero for no limit",
+			},
+			{
+				DestP:   &m.queryMaxMemoryBytes,
+				Flag:    "query-max-memory-bytes",
+				Default: 0,
+				Desc:    "memory reserved by and available to each query; zero for no limit",
+			},
+			{
+				DestP:   &m.queryOrgMemoryBytes,
+				Flag:    "query-org-memory-bytes",
+				Default: 0,
+				Desc:    "memory the queries of an organization run at once may reserve; zero for no limit",
+			},
+			{
+				DestP:   &m.queryQueueSize,
+				Flag:    "query-queue-size",
+				Default: 0,
+				Desc:    "number of queries that may wait to be run before new ones are rejected; zero for no limit",
+			},
+			{
+				DestP:   &m.queryQueueTimeout,
+				Flag:    "query-queue-timeout",
+				Default: time.Duration(0),
+				Desc:    "how long a query may wait to be run before it is rejected; zero for no limit",
+			},
 			{
 				DestP:   &m.reportingDisabled,
 				Flag:    "reporting-disabled",
@@ -269,6 +368,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 
 	m.boltClient = bolt.NewClient()
 	m.boltClient.Path = m.boltPath
+	m.boltClient.DashboardVersionLimit = m.dashboardVersionLimit
 	m.boltClient.WithLogger(m.logger.With(zap.String("service", "bolt")))
 
 	if err := m.boltClient.Open(ctx); err != nil {
@@ -305,6 +405,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		labelSvc         platform.LabelService                    = m.boltClient
 		secretSvc        platform.SecretService                   = m.boltClient
 		lookupSvc        platform.LookupService                   = m.boltClient
+		shareSvc         platform.ShareService                    = m.boltClient
 	)
 
 	switch m.secretStore {
@@ -349,7 +450,16 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 
 	var pointsWriter storage.PointsWriter
 	{
-		m.engine = storage.NewEngine(m.enginePath, storage.NewConfig(), storage.WithRetentionEnforcer(bucketSvc))
+		config := storage.NewConfig()
+		config.Engine.Compaction.Planner = m.compactionPlanner
+		config.Engine.Compaction.TimeWindow = toml.Duration(m.compactionTimeWindow)
+		config.Engine.Compaction.BlockSummaries = m.compactionBlockSummaries
+		if err := config.Engine.Compaction.Validate(); err != nil {
+			m.logger.Error("Invalid storage compaction configuration", zap.Error(err))
+			return err
+		}
+
+		m.engine = storage.NewEngine(m.enginePath, config, storage.WithRetentionEnforcer(bucketSvc))
 		m.engine.WithLogger(m.logger)
 
 		if err := m.engine.Open(); err != nil {
@@ -361,20 +471,25 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 
 		pointsWriter = m.engine
 
-		const (
-			concurrencyQuota = 10
-			memoryBytesQuota = 1e6
-		)
-
-		cc := control.Config{
-			ExecutorDependencies: make(execute.Dependencies),
-			ConcurrencyQuota:     concurrencyQuota,
-			MemoryBytesQuota:     int64(memoryBytesQuota),
-			Logger:               m.logger.With(zap.String("service", "storage-reads")),
+		cc := pcontrol.Config{
+			Config: control.Config{
+				ExecutorDependencies: make(execute.Dependencies),
+				ConcurrencyQuota:     m.queryConcurrency,
+				MemoryBytesQuota:     int64(m.queryMemoryBytes),
+				Logger:               m.logger.With(zap.String("service", "storage-reads")),
+			},
+			QueryMemoryBytesQuota: int64(m.queryMaxMemoryBytes),
+			OrgMemoryBytesQuota:   int64(m.queryOrgMemoryBytes),
+			MaxQueueLength:        m.queryQueueSize,
+			QueueTimeout:          m.queryQueueTimeout,
+		}
+		if err := cc.Validate(); err != nil {
+			m.logger.Error("Invalid query controller configuration", zap.Error(err))
+			return err
 		}
 
 		if err := readservice.AddControllerConfigDependencies(
-			&cc, m.engine, bucketSvc, orgSvc,
+			&cc.Config, m.engine, bucketSvc, orgSvc, shareSvc,
 		); err != nil {
 			m.logger.Error("Failed to configure query controller dependencies", zap.Error(err))
 			return err
@@ -385,6 +500,24 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	}
 
 	var storageQueryService = readservice.NewProxyQueryService(m.queryController)
+	var executorQueryService query.AsyncQueryService = m.queryController
+	if !m.queryLogDisabled || m.slowQueryThreshold > 0 {
+		// Slow queries are logged even if the query log is disabled.
+		var pw querylog.PointsWriter
+		if !m.queryLogDisabled {
+			pw = pointsWriter
+		}
+		ql := querylog.NewPointLogger(pw, m.logger.With(zap.String("service", "query-log")))
+		ql.SlowQueryThreshold = m.slowQueryThreshold
+		storageQueryService = &query.LoggingServiceBridge{
+			QueryService: query.QueryServiceBridge{AsyncQueryService: m.queryController},
+			QueryLogger:  ql,
+		}
+		executorQueryService = query.LoggingAsyncServiceBridge{
+			AsyncQueryService: m.queryController,
+			QueryLogger:       ql,
+		}
+	}
 	var taskSvc platform.TaskService
 	{
 		boltStore, err := taskbolt.New(m.boltClient.DB(), "tasks")
@@ -393,7 +526,8 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 			return err
 		}
 
-		executor := taskexecutor.NewAsyncQueryServiceExecutor(m.logger.With(zap.String("service", "task-executor")), m.queryController, boltStore)
+		macroBindingSvc := macro.NewValuesService(macroSvc, storageQueryService)
+		executor := taskexecutor.NewAsyncQueryServiceExecutor(m.logger.With(zap.String("service", "task-executor")), query.PriorityServiceBridge{AsyncQueryService: executorQueryService, Priority: query.PriorityTask}, boltStore, taskexecutor.WithMacroBindingService(macroBindingSvc))
 
 		lw := taskbackend.NewPointLogWriter(pointsWriter)
 		m.scheduler = taskbackend.NewScheduler(boltStore, executor, lw, time.Now().UTC().Unix(), taskbackend.WithTicker(ctx, 100*time.Millisecond), taskbackend.WithLogger(m.logger))
@@ -403,7 +537,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		queryService := query.QueryServiceBridge{AsyncQueryService: m.queryController}
 		lr := taskbackend.NewQueryLogReader(queryService)
 		taskSvc = task.PlatformAdapter(coordinator.New(m.logger.With(zap.String("service", "task-coordinator")), m.scheduler, boltStore), lr, m.scheduler)
-		taskSvc = task.NewValidator(taskSvc, bucketSvc)
+		taskSvc = task.NewValidator(taskSvc, bucketSvc, shareSvc)
 		m.taskStore = boltStore
 	}
 
@@ -433,7 +567,28 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 			Writer: pointsWriter,
 		},
 	})
-	scraperScheduler, err := gather.NewScheduler(10, m.logger, scraperTargetSvc, publisher, subscriber, 10*time.Second, 30*time.Second)
+	var discoverers []gather.Discoverer
+	if m.scraperDiscoveryConfig != "" {
+		cs, err := gather.LoadDiscoveryConfig(m.scraperDiscoveryConfig)
+		if err != nil {
+			m.logger.Error("failed to load scraper discovery config", zap.Error(err))
+			return err
+		}
+		for _, c := range cs {
+			d, err := gather.NewDiscoverer(c)
+			if err != nil {
+				m.logger.Error("invalid scraper discovery config", zap.Error(err))
+				return err
+			}
+			discoverers = append(discoverers, d)
+		}
+	}
+
+	scraperScheduler, err := gather.NewScheduler(10, m.logger, scraperTargetSvc, publisher, subscriber, 10*time.Second, 30*time.Second,
+		gather.WithSecretService(secretSvc),
+		gather.WithScraperStatusService(m.boltClient),
+		gather.WithDiscoverers(discoverers...),
+	)
 	if err != nil {
 		m.logger.Error("failed to create scraper subscriber", zap.Error(err))
 		return err
@@ -467,8 +622,10 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		OrganizationService:             orgSvc,
 		UserResourceMappingService:      userResourceSvc,
 		LabelService:                    labelSvc,
+		LabelResourceService:            m.boltClient,
 		DashboardService:                dashboardSvc,
 		DashboardOperationLogService:    dashboardLogSvc,
+		DashboardVersionService:         m.boltClient,
 		BucketOperationLogService:       bucketLogSvc,
 		UserOperationLogService:         userLogSvc,
 		OrganizationOperationLogService: orgLogSvc,
@@ -477,11 +634,14 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		BasicAuthService:                basicAuthSvc,
 		OnboardingService:               onboardingSvc,
 		ProxyQueryService:               storageQueryService,
+		RunningQueryService:             m.queryController,
 		TaskService:                     taskSvc,
 		TelegrafService:                 telegrafSvc,
+		TelegrafAgentService:            m.boltClient,
 		ScraperTargetStoreService:       scraperTargetSvc,
 		ChronografService:               chronografSvc,
 		SecretService:                   secretSvc,
+		ShareService:                    shareSvc,
 		LookupService:                   lookupSvc,
 		ProtoService:                    protoSvc,
 		OrgLookupService:                m.boltClient,
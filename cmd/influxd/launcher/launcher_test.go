@@ -89,7 +89,8 @@ func TestLauncher_WriteAndQuery(t *testing.T) {
 }
 
 func TestLauncher_BucketDelete(t *testing.T) {
-	l := RunLauncherOrFail(t, ctx)
+	// The query log would add series of its own to the engine.
+	l := RunLauncherOrFail(t, ctx, "--query-log-disabled")
 	l.SetupOrFail(t)
 	defer l.ShutdownOrFail(t, ctx)
 
@@ -156,6 +157,30 @@ func TestLauncher_BucketDelete(t *testing.T) {
 	}
 }
 
+func TestLauncher_QueryLog(t *testing.T) {
+	l := RunLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	engine := l.Launcher.Engine()
+	if got := engine.SeriesCardinality(); got != 0 {
+		t.Fatalf("got %d series before querying, exp 0", got)
+	}
+
+	var buf bytes.Buffer
+	req := (http.QueryRequest{Query: `from(bucket:"BUCKET") |> range(start:-1h)`, Org: l.Org}).WithDefaults()
+	if preq, err := req.ProxyRequest(); err != nil {
+		t.Fatal(err)
+	} else if _, err := l.FluxService().Query(ctx, &buf, preq); err != nil {
+		t.Fatal(err)
+	}
+
+	// The query is written to the query log of the organization.
+	if got := engine.SeriesCardinality(); got == 0 {
+		t.Fatal("expected the query to be logged")
+	}
+}
+
 // Launcher is a test wrapper for launcher.Launcher.
 type Launcher struct {
 	*launcher.Launcher
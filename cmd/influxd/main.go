@@ -8,12 +8,15 @@ import (
 	"sync"
 	"time"
 
+	"github.com/influxdata/influxdb/cmd/influxd/inspect"
 	"github.com/influxdata/influxdb/cmd/influxd/launcher"
+	"github.com/influxdata/influxdb/cmd/influxd/migrate"
 	"github.com/influxdata/influxdb/kit/signals"
 	_ "github.com/influxdata/influxdb/query/builtin"
 	"github.com/influxdata/influxdb/telemetry"
 	_ "github.com/influxdata/influxdb/tsdb/tsi1"
 	_ "github.com/influxdata/influxdb/tsdb/tsm1"
+	"github.com/spf13/cobra"
 )
 
 var (
@@ -22,7 +25,25 @@ var (
 	date    = "unknown"
 )
 
+// commands are the influxd subcommands that run to completion instead of starting the server.
+var commands = []*cobra.Command{
+	migrate.NewCommand(),
+	inspect.NewCommand(),
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		for _, cmd := range commands {
+			if os.Args[1] == cmd.Name() {
+				cmd.SetArgs(os.Args[2:])
+				if err := cmd.Execute(); err != nil {
+					os.Exit(1)
+				}
+				return
+			}
+		}
+	}
+
 	// exit with SIGINT and SIGTERM
 	ctx := context.Background()
 	ctx = signals.WithStandardSignals(ctx)
@@ -0,0 +1,169 @@
+// Package migrate implements the influxd migrate command, which inspects and
+// changes the schema version of the bolt metadata store.
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/influxdata/influxdb/bolt"
+	"github.com/influxdata/influxdb/internal/fs"
+	influxlogger "github.com/influxdata/influxdb/logger"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap/zapcore"
+)
+
+var flags struct {
+	boltPath string
+	upTo     int
+	downTo   int
+}
+
+// NewCommand returns the influxd migrate command.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "migrate",
+		Short:        "Inspect and migrate the schema of the bolt metadata store",
+		SilenceUsage: true,
+	}
+
+	defaultPath := "influxd.bolt"
+	if dir, err := fs.InfluxDir(); err == nil {
+		defaultPath = filepath.Join(dir, "influxd.bolt")
+	}
+	cmd.PersistentFlags().StringVar(&flags.boltPath, "bolt-path", defaultPath, "path to boltdb database")
+
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the current schema version and the state of each migration",
+		Args:  cobra.NoArgs,
+		RunE:  statusF,
+	}
+
+	upCmd := &cobra.Command{
+		Use:   "up",
+		Short: "Apply pending migrations",
+		Args:  cobra.NoArgs,
+		RunE:  upF,
+	}
+	upCmd.Flags().IntVar(&flags.upTo, "to", bolt.LatestSchemaVersion(), "schema version to migrate up to")
+
+	downCmd := &cobra.Command{
+		Use:   "down",
+		Short: "Revert applied migrations",
+		Args:  cobra.NoArgs,
+		RunE:  downF,
+	}
+	downCmd.Flags().IntVar(&flags.downTo, "to", -1, "schema version to migrate down to (defaults to one version below the current)")
+
+	cmd.AddCommand(statusCmd, upCmd, downCmd)
+	return cmd
+}
+
+func openClient(ctx context.Context) (*bolt.Client, error) {
+	if _, err := os.Stat(flags.boltPath); err != nil {
+		return nil, fmt.Errorf("unable to open bolt file: %v", err)
+	}
+
+	logconf := &influxlogger.Config{
+		Format: "auto",
+		Level:  zapcore.InfoLevel,
+	}
+	logger, err := logconf.New(os.Stderr)
+	if err != nil {
+		return nil, err
+	}
+
+	c := bolt.NewClient()
+	c.Path = flags.boltPath
+	c.DisableAutoMigrate = true
+	c.WithLogger(logger)
+	if err := c.Open(ctx); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func statusF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	c, err := openClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	current, err := c.SchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	return writeStatus(os.Stdout, current)
+}
+
+func writeStatus(out io.Writer, current int) error {
+	fmt.Fprintf(out, "Current schema version: %d (latest %d)\n\n", current, bolt.LatestSchemaVersion())
+
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "Version\tStatus\tDescription")
+	for _, m := range bolt.Migrations() {
+		status := "pending"
+		if m.Version <= current {
+			status = "applied"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", m.Version, status, m.Description)
+	}
+	return w.Flush()
+}
+
+func upF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	c, err := openClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if err := c.MigrateUp(ctx, flags.upTo); err != nil {
+		return err
+	}
+
+	current, err := c.SchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Schema is at version %d\n", current)
+	return nil
+}
+
+func downF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	c, err := openClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	current, err := c.SchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	to := flags.downTo
+	if to < 0 {
+		to = current - 1
+	}
+	if to < 0 {
+		return fmt.Errorf("schema is already at version 0")
+	}
+
+	if err := c.MigrateDown(ctx, to); err != nil {
+		return err
+	}
+
+	fmt.Printf("Schema is at version %d\n", to)
+	return nil
+}
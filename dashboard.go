@@ -1,7 +1,9 @@
 package influxdb
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"net/url"
 	"sort"
 	"time"
@@ -26,6 +28,12 @@ const (
 	OpUpdateDashboardCellView = "UpdateDashboardCellView"
 	OpDeleteDashboard         = "DeleteDashboard"
 	OpReplaceDashboardCells   = "ReplaceDashboardCells"
+	OpExportDashboard         = "ExportDashboard"
+	OpImportDashboard         = "ImportDashboard"
+
+	OpFindDashboardVersions   = "FindDashboardVersions"
+	OpFindDashboardVersion    = "FindDashboardVersion"
+	OpRestoreDashboardVersion = "RestoreDashboardVersion"
 )
 
 // DashboardService represents a service for managing dashboard data.
@@ -82,6 +90,18 @@ type DashboardMeta struct {
 	UpdatedAt time.Time `json:"updatedAt"`
 }
 
+// Cursor returns the position of the dashboard in a listing sorted by sortBy.
+// Dashboards may be sorted by ID, Name, CreatedAt or UpdatedAt.
+func (d *Dashboard) Cursor(sortBy string) (PageCursor, error) {
+	switch sortBy {
+	case "CreatedAt":
+		return NewPageCursor(sortBy, timePageKey(d.Meta.CreatedAt), d.ID), nil
+	case "UpdatedAt":
+		return NewPageCursor(sortBy, timePageKey(d.Meta.UpdatedAt), d.ID), nil
+	}
+	return namePageCursor(sortBy, d.Name, d.ID)
+}
+
 // DefaultDashboardFindOptions are the default find options for dashboards
 var DefaultDashboardFindOptions = FindOptions{
 	SortBy: "ID",
@@ -129,6 +149,7 @@ type DashboardFilter struct {
 	IDs            []*ID
 	OrganizationID *ID
 	Organization   *string
+	Labels         LabelSelector
 }
 
 // QueryParams turns a dashboard filter into query params
@@ -150,6 +171,10 @@ func (f DashboardFilter) QueryParams() map[string][]string {
 		qp.Add("org", *f.Organization)
 	}
 
+	for k, v := range f.Labels.QueryParams() {
+		qp[k] = v
+	}
+
 	return qp
 }
 
@@ -229,3 +254,203 @@ func (u CellUpdate) Valid() *Error {
 
 	return nil
 }
+
+// DashboardExportService exports dashboards as self-contained documents and
+// imports them into organizations.
+type DashboardExportService interface {
+	// ExportDashboard returns the dashboard id with the views of its cells and
+	// the macros and labels they refer to.
+	ExportDashboard(ctx context.Context, id ID) (*DashboardExport, error)
+
+	// ImportDashboard creates the dashboard of e in the organization orgID and
+	// returns it. The dashboard, its cells and views are given new IDs; macros
+	// and labels are matched by name and only created when missing.
+	ImportDashboard(ctx context.Context, orgID ID, e *DashboardExport) (*Dashboard, error)
+}
+
+// DashboardExport is a dashboard in a single document: the dashboard and the
+// views of its cells, keyed by cell ID as in a ProtoDashboard, along with the
+// macros referenced by the queries of the views and the labels of the dashboard.
+type DashboardExport struct {
+	ProtoDashboard
+	Macros []*Macro `json:"macros,omitempty"`
+	Labels []*Label `json:"labels,omitempty"`
+}
+
+// Valid returns an error if the export cannot be imported.
+func (e *DashboardExport) Valid() error {
+	if e.Dashboard.Name == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "dashboard name is required",
+		}
+	}
+	for _, m := range e.Macros {
+		if m == nil || m.Name == "" {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "macro name is required",
+			}
+		}
+	}
+	for _, l := range e.Labels {
+		if l == nil || l.Name == "" {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "label name is required",
+			}
+		}
+	}
+	return nil
+}
+
+// ErrDashboardVersionNotFound is the error msg for a missing dashboard version.
+const ErrDashboardVersionNotFound = "dashboard version not found"
+
+// DashboardVersionService keeps the prior states of dashboards so that they
+// can be restored.
+type DashboardVersionService interface {
+	// FindDashboardVersions returns the versions of the dashboard id, newest
+	// first, and the total count of its versions.
+	FindDashboardVersions(ctx context.Context, id ID, opts FindOptions) ([]*DashboardVersion, int, error)
+
+	// FindDashboardVersion returns a single version of the dashboard id.
+	FindDashboardVersion(ctx context.Context, id ID, version int) (*DashboardVersion, error)
+
+	// RestoreDashboardVersion returns the dashboard id and the views of its
+	// cells to a version. The state it replaces is kept as a new version.
+	RestoreDashboardVersion(ctx context.Context, id ID, version int) (*Dashboard, error)
+}
+
+// DashboardVersion is the state of a dashboard, and the views of its cells
+// keyed by cell ID, before a change was made to it. Versions are numbered
+// from 1 in the order they were recorded.
+type DashboardVersion struct {
+	Version int `json:"version"`
+	ProtoDashboard
+	// Change describes the change that replaced the version.
+	Change string    `json:"change"`
+	UserID ID        `json:"userID,omitempty"`
+	Time   time.Time `json:"time"`
+}
+
+// Dashboard changes.
+const (
+	DashboardChangeAdded   = "added"
+	DashboardChangeRemoved = "removed"
+	DashboardChangeUpdated = "updated"
+)
+
+// DashboardChange is a difference between two states of a dashboard. Changes
+// to cells carry the ID of the cell.
+type DashboardChange struct {
+	Action string `json:"action"`
+	CellID ID     `json:"cellID,omitempty"`
+	// Field is what changed: the name or description of the dashboard, or a
+	// cell, its position or its view.
+	Field string      `json:"field"`
+	From  interface{} `json:"from,omitempty"`
+	To    interface{} `json:"to,omitempty"`
+}
+
+// DiffDashboards returns the changes that turn the dashboard from into to.
+func DiffDashboards(from, to *ProtoDashboard) ([]DashboardChange, error) {
+	var changes []DashboardChange
+	if from.Dashboard.Name != to.Dashboard.Name {
+		changes = append(changes, DashboardChange{
+			Action: DashboardChangeUpdated,
+			Field:  "name",
+			From:   from.Dashboard.Name,
+			To:     to.Dashboard.Name,
+		})
+	}
+	if from.Dashboard.Description != to.Dashboard.Description {
+		changes = append(changes, DashboardChange{
+			Action: DashboardChangeUpdated,
+			Field:  "description",
+			From:   from.Dashboard.Description,
+			To:     to.Dashboard.Description,
+		})
+	}
+
+	toCells := make(map[ID]*Cell, len(to.Dashboard.Cells))
+	for _, c := range to.Dashboard.Cells {
+		toCells[c.ID] = c
+	}
+	fromCells := make(map[ID]bool, len(from.Dashboard.Cells))
+	for _, fc := range from.Dashboard.Cells {
+		fromCells[fc.ID] = true
+
+		tc, ok := toCells[fc.ID]
+		if !ok {
+			changes = append(changes, DashboardChange{
+				Action: DashboardChangeRemoved,
+				CellID: fc.ID,
+				Field:  "cell",
+				From:   fc,
+			})
+			continue
+		}
+
+		if fc.X != tc.X || fc.Y != tc.Y || fc.W != tc.W || fc.H != tc.H {
+			changes = append(changes, DashboardChange{
+				Action: DashboardChangeUpdated,
+				CellID: fc.ID,
+				Field:  "position",
+				From:   fc,
+				To:     tc,
+			})
+		}
+
+		key := fc.ID.String()
+		fv, fok := from.Views[key]
+		tv, tok := to.Views[key]
+		if fok != tok {
+			changes = append(changes, DashboardChange{
+				Action: DashboardChangeUpdated,
+				CellID: fc.ID,
+				Field:  "view",
+				From:   optionalView(fv, fok),
+				To:     optionalView(tv, tok),
+			})
+			continue
+		}
+		fb, err := json.Marshal(fv)
+		if err != nil {
+			return nil, err
+		}
+		tb, err := json.Marshal(tv)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(fb, tb) {
+			changes = append(changes, DashboardChange{
+				Action: DashboardChangeUpdated,
+				CellID: fc.ID,
+				Field:  "view",
+				From:   fv,
+				To:     tv,
+			})
+		}
+	}
+
+	for _, tc := range to.Dashboard.Cells {
+		if fromCells[tc.ID] {
+			continue
+		}
+		changes = append(changes, DashboardChange{
+			Action: DashboardChangeAdded,
+			CellID: tc.ID,
+			Field:  "cell",
+			To:     tc,
+		})
+	}
+	return changes, nil
+}
+
+func optionalView(v View, ok bool) interface{} {
+	if !ok {
+		return nil
+	}
+	return v
+}
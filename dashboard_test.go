@@ -0,0 +1,49 @@
+package influxdb_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	platform "github.com/influxdata/influxdb"
+)
+
+func TestDiffDashboards(t *testing.T) {
+	kept := &platform.Cell{ID: 1, W: 4, H: 4}
+	moved := &platform.Cell{ID: 1, X: 4, W: 4, H: 4}
+	removed := &platform.Cell{ID: 2, W: 2, H: 2}
+	added := &platform.Cell{ID: 3, W: 2, H: 2}
+	note := func(s string) platform.View {
+		return platform.View{
+			ViewContents: platform.ViewContents{ID: 1, Name: "notes"},
+			Properties:   platform.MarkdownViewProperties{Type: "markdown", Note: s},
+		}
+	}
+
+	from := &platform.ProtoDashboard{
+		Dashboard: platform.Dashboard{Name: "d", Cells: []*platform.Cell{kept, removed}},
+		Views:     map[string]platform.View{kept.ID.String(): note("a")},
+	}
+	to := &platform.ProtoDashboard{
+		Dashboard: platform.Dashboard{Name: "d", Description: "desc", Cells: []*platform.Cell{moved, added}},
+		Views:     map[string]platform.View{kept.ID.String(): note("b")},
+	}
+
+	changes, err := platform.DiffDashboards(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []platform.DashboardChange{
+		{Action: platform.DashboardChangeUpdated, Field: "description", From: "", To: "desc"},
+		{Action: platform.DashboardChangeUpdated, CellID: 1, Field: "position", From: kept, To: moved},
+		{Action: platform.DashboardChangeUpdated, CellID: 1, Field: "view", From: note("a"), To: note("b")},
+		{Action: platform.DashboardChangeRemoved, CellID: 2, Field: "cell", From: removed},
+		{Action: platform.DashboardChangeAdded, CellID: 3, Field: "cell", To: added},
+	}
+	if diff := cmp.Diff(changes, want); diff != "" {
+		t.Errorf("changes are different -got/+want\ndiff %s", diff)
+	}
+
+	if changes, err := platform.DiffDashboards(from, from); err != nil || len(changes) != 0 {
+		t.Errorf("expected no changes between equal dashboards, got %v, %v", changes, err)
+	}
+}
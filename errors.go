@@ -20,6 +20,7 @@ const (
 	EForbidden           = "forbidden"
 	EUnauthorized        = "unauthorized"
 	EMethodNotAllowed    = "method not allowed"
+	ETooManyRequests     = "too many requests"
 )
 
 // Error is the error struct of platform.
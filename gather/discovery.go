@@ -0,0 +1,327 @@
+package gather
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/influxdata/influxdb"
+)
+
+// Discoverer finds scraper targets that are not kept in the target store,
+// such as the exporters of a deployment. Discovered targets have no ID, and
+// their scrapes have no recorded status.
+type Discoverer interface {
+	// Discover returns the targets currently discovered. If some of them
+	// cannot be looked up, it returns the others with an error.
+	Discover(ctx context.Context) ([]influxdb.ScraperTarget, error)
+}
+
+// Discovery types.
+const (
+	// FileDiscovery reads targets from Prometheus file_sd files.
+	FileDiscovery = "file"
+	// DNSDiscovery looks targets up in DNS.
+	DNSDiscovery = "dns"
+)
+
+// DNS record types of DNS discovery.
+const (
+	DNSRecordSRV = "SRV"
+	DNSRecordA   = "A"
+)
+
+// DefaultDNSRefreshInterval is the default time between DNS lookups.
+const DefaultDNSRefreshInterval = 30 * time.Second
+
+// DiscoveryConfig configures a Discoverer.
+type DiscoveryConfig struct {
+	Type string `json:"type"`
+
+	// Files are the paths, or glob patterns of the paths, of the file_sd
+	// files of file discovery, in JSON or YAML.
+	Files []string `json:"files,omitempty"`
+
+	// Names are the names DNS discovery looks up.
+	Names []string `json:"names,omitempty"`
+	// RecordType is the type of the records looked up, SRV by default. A
+	// looks up all the addresses of the names.
+	RecordType string `json:"recordType,omitempty"`
+	// Port is the port of the targets found by A lookups.
+	Port int `json:"port,omitempty"`
+	// RefreshInterval is the time between lookups, in milliseconds.
+	RefreshInterval int64 `json:"refreshInterval,omitempty"`
+
+	// Scheme and MetricsPath make up the URLs of targets with their
+	// discovered addresses; http and /metrics by default.
+	Scheme      string `json:"scheme,omitempty"`
+	MetricsPath string `json:"metricsPath,omitempty"`
+
+	// Target is the template of discovered targets: their organization,
+	// bucket, interval, credentials and filters. Its URL is ignored.
+	Target influxdb.ScraperTarget `json:"target"`
+}
+
+// LoadDiscoveryConfig reads the list of discovery configs of the YAML or JSON
+// file at path.
+func LoadDiscoveryConfig(path string) ([]DiscoveryConfig, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cs []DiscoveryConfig
+	if err := yaml.Unmarshal(b, &cs); err != nil {
+		return nil, fmt.Errorf("invalid discovery config %q: %v", path, err)
+	}
+	return cs, nil
+}
+
+// NewDiscoverer returns the Discoverer configured by c.
+func NewDiscoverer(c DiscoveryConfig) (Discoverer, error) {
+	if !c.Target.OrgID.Valid() || !c.Target.BucketID.Valid() {
+		return nil, fmt.Errorf("discovered targets require an org id and a bucket id")
+	}
+	if err := c.Target.Valid(); err != nil {
+		return nil, err
+	}
+	if c.Target.Type == "" {
+		c.Target.Type = influxdb.PrometheusScraperType
+	}
+	if c.Scheme == "" {
+		c.Scheme = "http"
+	}
+	if c.MetricsPath == "" {
+		c.MetricsPath = "/metrics"
+	}
+
+	switch c.Type {
+	case FileDiscovery:
+		if len(c.Files) == 0 {
+			return nil, fmt.Errorf("file discovery requires files")
+		}
+		return &fileDiscoverer{
+			config: c,
+			files:  map[string]fileTargets{},
+		}, nil
+	case DNSDiscovery:
+		if len(c.Names) == 0 {
+			return nil, fmt.Errorf("dns discovery requires names")
+		}
+		switch c.RecordType {
+		case "":
+			c.RecordType = DNSRecordSRV
+		case DNSRecordSRV:
+		case DNSRecordA:
+			if c.Port <= 0 {
+				return nil, fmt.Errorf("dns discovery of A records requires a port")
+			}
+		default:
+			return nil, fmt.Errorf("invalid dns record type %q", c.RecordType)
+		}
+		interval := DefaultDNSRefreshInterval
+		if c.RefreshInterval > 0 {
+			interval = time.Duration(c.RefreshInterval) * time.Millisecond
+		}
+		return &dnsDiscoverer{
+			config:   c,
+			interval: interval,
+			resolver: net.DefaultResolver,
+			now:      time.Now,
+			names:    map[string][]influxdb.ScraperTarget{},
+		}, nil
+	default:
+		return nil, fmt.Errorf("invalid discovery type %q", c.Type)
+	}
+}
+
+// target returns the target of c scraping address, with tags added to the
+// tags of the template.
+func (c DiscoveryConfig) target(address string, tags map[string]string) influxdb.ScraperTarget {
+	t := c.Target
+	t.ID = 0
+	t.URL = (&url.URL{Scheme: c.Scheme, Host: address, Path: c.MetricsPath}).String()
+	if t.Name == "" {
+		t.Name = address
+	} else {
+		t.Name += "/" + address
+	}
+
+	t.Tags = make(map[string]string, len(c.Target.Tags)+len(tags))
+	for k, v := range c.Target.Tags {
+		t.Tags[k] = v
+	}
+	for k, v := range tags {
+		t.Tags[k] = v
+	}
+	return t
+}
+
+// fileDiscoverer discovers the targets of Prometheus file_sd files. Files are
+// read again when their size or modification time changes.
+type fileDiscoverer struct {
+	config DiscoveryConfig
+	files  map[string]fileTargets
+}
+
+type fileTargets struct {
+	modTime time.Time
+	size    int64
+	targets []influxdb.ScraperTarget
+}
+
+// fileGroup is a group of targets of a file_sd file.
+type fileGroup struct {
+	Targets []string          `json:"targets"`
+	Labels  map[string]string `json:"labels"`
+}
+
+func (d *fileDiscoverer) Discover(ctx context.Context) ([]influxdb.ScraperTarget, error) {
+	var paths []string
+	for _, pattern := range d.config.Files {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, matches...)
+	}
+
+	var errs []string
+	var targets []influxdb.ScraperTarget
+	files := make(map[string]fileTargets, len(paths))
+	for _, path := range paths {
+		prev, seen := d.files[path]
+		fi, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+
+		f := prev
+		if !seen || !prev.modTime.Equal(fi.ModTime()) || prev.size != fi.Size() {
+			ts, err := d.read(path)
+			if err != nil {
+				// The targets last read are kept until the file is fixed.
+				errs = append(errs, err.Error())
+			} else {
+				f = fileTargets{modTime: fi.ModTime(), size: fi.Size(), targets: ts}
+			}
+		}
+		files[path] = f
+		targets = append(targets, f.targets...)
+	}
+	d.files = files
+
+	if len(errs) > 0 {
+		return targets, fmt.Errorf("file discovery failed: %s", strings.Join(errs, "; "))
+	}
+	return targets, nil
+}
+
+func (d *fileDiscoverer) read(path string) ([]influxdb.ScraperTarget, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []fileGroup
+	if err := yaml.Unmarshal(b, &groups); err != nil {
+		return nil, fmt.Errorf("invalid file_sd file %q: %v", path, err)
+	}
+
+	var targets []influxdb.ScraperTarget
+	for _, g := range groups {
+		for _, address := range g.Targets {
+			targets = append(targets, d.config.target(address, g.Labels))
+		}
+	}
+	return targets, nil
+}
+
+// resolver looks names up in DNS; *net.Resolver implements it.
+type resolver interface {
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// dnsDiscoverer discovers targets by DNS SRV or A lookups. Names are looked
+// up again every interval; the targets of a failed lookup are kept until a
+// later lookup succeeds.
+type dnsDiscoverer struct {
+	config   DiscoveryConfig
+	interval time.Duration
+	resolver resolver
+	now      func() time.Time
+
+	refreshed time.Time
+	err       error
+	names     map[string][]influxdb.ScraperTarget
+}
+
+func (d *dnsDiscoverer) Discover(ctx context.Context) ([]influxdb.ScraperTarget, error) {
+	if now := d.now(); d.refreshed.IsZero() || now.Sub(d.refreshed) >= d.interval {
+		d.refreshed = now
+		d.err = d.lookup(ctx)
+	}
+
+	var targets []influxdb.ScraperTarget
+	for _, name := range d.config.Names {
+		targets = append(targets, d.names[name]...)
+	}
+	return targets, d.err
+}
+
+func (d *dnsDiscoverer) lookup(ctx context.Context) error {
+	var errs []string
+	for _, name := range d.config.Names {
+		addresses, err := d.addresses(ctx, name)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+
+		targets := make([]influxdb.ScraperTarget, 0, len(addresses))
+		for _, address := range addresses {
+			targets = append(targets, d.config.target(address, map[string]string{"dns_name": name}))
+		}
+		d.names[name] = targets
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("dns discovery failed: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (d *dnsDiscoverer) addresses(ctx context.Context, name string) ([]string, error) {
+	var addresses []string
+	switch d.config.RecordType {
+	case DNSRecordSRV:
+		_, srvs, err := d.resolver.LookupSRV(ctx, "", "", name)
+		if err != nil {
+			return nil, err
+		}
+		for _, srv := range srvs {
+			host := strings.TrimSuffix(srv.Target, ".")
+			addresses = append(addresses, net.JoinHostPort(host, strconv.Itoa(int(srv.Port))))
+		}
+	case DNSRecordA:
+		hosts, err := d.resolver.LookupHost(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, host := range hosts {
+			addresses = append(addresses, net.JoinHostPort(host, strconv.Itoa(d.config.Port)))
+		}
+	}
+	return addresses, nil
+}
@@ -0,0 +1,194 @@
+package gather
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+)
+
+func discoveryConfig(typ string) DiscoveryConfig {
+	return DiscoveryConfig{
+		Type: typ,
+		Target: influxdb.ScraperTarget{
+			OrgID:    *orgID,
+			BucketID: *bucketID,
+			Interval: 15000,
+			Tags:     map[string]string{"env": "prod"},
+		},
+	}
+}
+
+func urls(ts []influxdb.ScraperTarget) []string {
+	us := []string{}
+	for _, t := range ts {
+		us = append(us, t.URL)
+	}
+	return us
+}
+
+func TestFileDiscoverer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file_sd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	write := func(name, content string, mod time.Time) {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mod, mod); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mod := time.Unix(1000, 0)
+	write("a.json", `[{"targets": ["a:9100", "b:9100"], "labels": {"job": "node"}}]`, mod)
+	write("b.yml", "- targets: ['c:9100']\n", mod)
+
+	c := discoveryConfig(FileDiscovery)
+	c.Files = []string{filepath.Join(dir, "*.json"), filepath.Join(dir, "*.yml")}
+	d, err := NewDiscoverer(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targets, err := d.Discover(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"http://a:9100/metrics", "http://b:9100/metrics", "http://c:9100/metrics"}
+	if diff := cmp.Diff(urls(targets), want); diff != "" {
+		t.Fatalf("discovered targets are different -got/+want\ndiff %s", diff)
+	}
+	first := targets[0]
+	if first.ID.Valid() || first.OrgID != *orgID || first.Interval != 15000 {
+		t.Fatalf("discovered targets must be made from the template, got %+v", first)
+	}
+	if diff := cmp.Diff(first.Tags, map[string]string{"env": "prod", "job": "node"}); diff != "" {
+		t.Fatalf("discovered labels are different -got/+want\ndiff %s", diff)
+	}
+
+	// Changed files are read again; broken files keep their last targets.
+	write("a.json", `[{"targets": ["d:9100"]}]`, mod.Add(time.Second))
+	write("b.yml", "- targets: [", mod.Add(time.Second))
+	targets, err = d.Discover(context.Background())
+	if err == nil {
+		t.Fatal("expected invalid files to be reported")
+	}
+	want = []string{"http://d:9100/metrics", "http://c:9100/metrics"}
+	if diff := cmp.Diff(urls(targets), want); diff != "" {
+		t.Fatalf("discovered targets are different -got/+want\ndiff %s", diff)
+	}
+
+	os.Remove(filepath.Join(dir, "b.yml"))
+	targets, err = d.Discover(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(urls(targets), want[:1]); diff != "" {
+		t.Fatalf("discovered targets are different -got/+want\ndiff %s", diff)
+	}
+}
+
+type fakeResolver struct {
+	srvs    map[string][]*net.SRV
+	hosts   map[string][]string
+	lookups int
+}
+
+func (r *fakeResolver) LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	r.lookups++
+	srvs, ok := r.srvs[name]
+	if !ok {
+		return "", nil, &net.DNSError{Err: "no such host", Name: name}
+	}
+	return name, srvs, nil
+}
+
+func (r *fakeResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	r.lookups++
+	hosts, ok := r.hosts[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host}
+	}
+	return hosts, nil
+}
+
+func TestDNSDiscoverer(t *testing.T) {
+	r := &fakeResolver{
+		srvs: map[string][]*net.SRV{
+			"_node._tcp.example.com": {
+				{Target: "a.example.com.", Port: 9100},
+				{Target: "b.example.com.", Port: 9101},
+			},
+		},
+	}
+
+	c := discoveryConfig(DNSDiscovery)
+	c.Names = []string{"_node._tcp.example.com"}
+	disc, err := NewDiscoverer(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := disc.(*dnsDiscoverer)
+	d.resolver = r
+	now := time.Unix(0, 0)
+	d.now = func() time.Time { return now }
+
+	targets, err := d.Discover(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"http://a.example.com:9100/metrics", "http://b.example.com:9101/metrics"}
+	if diff := cmp.Diff(urls(targets), want); diff != "" {
+		t.Fatalf("discovered targets are different -got/+want\ndiff %s", diff)
+	}
+	if targets[0].Tags["dns_name"] != "_node._tcp.example.com" {
+		t.Fatalf("expected the name looked up as a tag, got %v", targets[0].Tags)
+	}
+
+	if _, err := d.Discover(context.Background()); err != nil || r.lookups != 1 {
+		t.Fatalf("expected lookups to be cached, got %d lookups, error %v", r.lookups, err)
+	}
+
+	// Failed lookups keep the targets found before.
+	delete(r.srvs, "_node._tcp.example.com")
+	now = now.Add(DefaultDNSRefreshInterval)
+	targets, err = d.Discover(context.Background())
+	if err == nil || r.lookups != 2 {
+		t.Fatalf("expected a failed lookup, got %d lookups, error %v", r.lookups, err)
+	}
+	if diff := cmp.Diff(urls(targets), want); diff != "" {
+		t.Fatalf("discovered targets are different -got/+want\ndiff %s", diff)
+	}
+
+	c = discoveryConfig(DNSDiscovery)
+	c.Names = []string{"node.example.com"}
+	c.RecordType = DNSRecordA
+	if _, err := NewDiscoverer(c); err == nil {
+		t.Fatal("expected A lookups without a port to be invalid")
+	}
+	c.Port = 9100
+	disc, err = NewDiscoverer(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d = disc.(*dnsDiscoverer)
+	d.resolver = &fakeResolver{hosts: map[string][]string{"node.example.com": {"10.0.0.1", "::1"}}}
+	targets, err = d.Discover(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []string{"http://10.0.0.1:9100/metrics", "http://[::1]:9100/metrics"}
+	if diff := cmp.Diff(urls(targets), want); diff != "" {
+		t.Fatalf("discovered targets are different -got/+want\ndiff %s", diff)
+	}
+}
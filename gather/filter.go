@@ -0,0 +1,195 @@
+package gather
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/influxdata/influxdb"
+)
+
+// filter drops and relabels the metrics scraped from a target, as configured
+// by its allow and deny lists and relabel rules.
+type filter struct {
+	metricAllow []*regexp.Regexp
+	metricDeny  []*regexp.Regexp
+	labelAllow  map[string]bool
+	labelDeny   map[string]bool
+	relabel     []relabelRule
+}
+
+type relabelRule struct {
+	influxdb.RelabelRule
+	re *regexp.Regexp
+}
+
+// newFilter compiles the filter of target t.
+func newFilter(t influxdb.ScraperTarget) (*filter, error) {
+	f := &filter{}
+
+	var err error
+	if f.metricAllow, err = compileAll(t.MetricAllow); err != nil {
+		return nil, err
+	}
+	if f.metricDeny, err = compileAll(t.MetricDeny); err != nil {
+		return nil, err
+	}
+	if len(t.LabelAllow) > 0 {
+		f.labelAllow = set(t.LabelAllow)
+	}
+	if len(t.LabelDeny) > 0 {
+		f.labelDeny = set(t.LabelDeny)
+	}
+
+	for _, r := range t.Relabel {
+		if r.Separator == "" {
+			r.Separator = ";"
+		}
+		if r.Regex == "" {
+			r.Regex = "(.*)"
+		}
+		if r.Replacement == "" {
+			r.Replacement = "$1"
+		}
+		if r.Action == "" {
+			r.Action = influxdb.RelabelReplace
+		}
+		re, err := regexp.Compile("^(?:" + r.Regex + ")$")
+		if err != nil {
+			return nil, err
+		}
+		f.relabel = append(f.relabel, relabelRule{RelabelRule: r, re: re})
+	}
+	return f, nil
+}
+
+// apply returns the metrics of ms kept by the filter, with their labels
+// rewritten. ms is modified in place.
+func (f *filter) apply(ms []Metrics) []Metrics {
+	kept := ms[:0]
+	for _, m := range ms {
+		if !f.allowName(m.Name) {
+			continue
+		}
+		if f.labelAllow != nil || f.labelDeny != nil {
+			m.Tags = f.filterLabels(m.Tags)
+		}
+		if len(f.relabel) > 0 {
+			var ok bool
+			if m.Name, m.Tags, ok = f.relabelMetric(m.Name, m.Tags); !ok {
+				continue
+			}
+		}
+		kept = append(kept, m)
+	}
+	return kept
+}
+
+func (f *filter) allowName(name string) bool {
+	if len(f.metricAllow) > 0 && !matchAny(f.metricAllow, name) {
+		return false
+	}
+	return !matchAny(f.metricDeny, name)
+}
+
+func (f *filter) filterLabels(tags map[string]string) map[string]string {
+	out := make(map[string]string, len(tags))
+	for k, v := range tags {
+		if f.labelAllow != nil && !f.labelAllow[k] {
+			continue
+		}
+		if f.labelDeny[k] {
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// relabelMetric applies the relabel rules to the metric name and its tags.
+// It returns false if the metric is dropped.
+func (f *filter) relabelMetric(name string, tags map[string]string) (string, map[string]string, bool) {
+	labels := make(map[string]string, len(tags)+1)
+	for k, v := range tags {
+		labels[k] = v
+	}
+	labels[influxdb.RelabelMetricName] = name
+
+	for _, r := range f.relabel {
+		values := make([]string, len(r.SourceLabels))
+		for i, l := range r.SourceLabels {
+			values[i] = labels[l]
+		}
+		value := strings.Join(values, r.Separator)
+
+		switch r.Action {
+		case influxdb.RelabelReplace:
+			match := r.re.FindStringSubmatchIndex(value)
+			if match == nil {
+				continue
+			}
+			target := string(r.re.ExpandString(nil, r.TargetLabel, value, match))
+			replacement := string(r.re.ExpandString(nil, r.Replacement, value, match))
+			if replacement == "" {
+				delete(labels, target)
+			} else {
+				labels[target] = replacement
+			}
+		case influxdb.RelabelKeep:
+			if !r.re.MatchString(value) {
+				return "", nil, false
+			}
+		case influxdb.RelabelDrop:
+			if r.re.MatchString(value) {
+				return "", nil, false
+			}
+		case influxdb.RelabelLabelKeep:
+			for k := range labels {
+				if k != influxdb.RelabelMetricName && !r.re.MatchString(k) {
+					delete(labels, k)
+				}
+			}
+		case influxdb.RelabelLabelDrop:
+			for k := range labels {
+				if k != influxdb.RelabelMetricName && r.re.MatchString(k) {
+					delete(labels, k)
+				}
+			}
+		}
+	}
+
+	name = labels[influxdb.RelabelMetricName]
+	if name == "" {
+		return "", nil, false
+	}
+	delete(labels, influxdb.RelabelMetricName)
+	return name, labels, true
+}
+
+func compileAll(exprs []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(exprs))
+	for _, expr := range exprs {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+func matchAny(res []*regexp.Regexp, s string) bool {
+	for _, re := range res {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+func set(ss []string) map[string]bool {
+	m := make(map[string]bool, len(ss))
+	for _, s := range ss {
+		m[s] = true
+	}
+	return m
+}
@@ -0,0 +1,77 @@
+package gather
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+)
+
+func TestFilter(t *testing.T) {
+	ms := func() []Metrics {
+		return []Metrics{
+			{Name: "go_goroutines", Tags: map[string]string{"instance": "a:9100", "job": "node"}},
+			{Name: "go_info", Tags: map[string]string{"instance": "a:9100", "version": "go1.11"}},
+			{Name: "http_requests_total", Tags: map[string]string{"instance": "b:9100", "code": "200"}},
+			{Name: "process_cpu_seconds_total", Tags: map[string]string{"instance": "a:9100"}},
+		}
+	}
+
+	cases := []struct {
+		name   string
+		target influxdb.ScraperTarget
+		want   []Metrics
+	}{
+		{
+			name: "allow and deny metrics",
+			target: influxdb.ScraperTarget{
+				MetricAllow: []string{"^go_", "^http_"},
+				MetricDeny:  []string{"_info$"},
+			},
+			want: []Metrics{
+				{Name: "go_goroutines", Tags: map[string]string{"instance": "a:9100", "job": "node"}},
+				{Name: "http_requests_total", Tags: map[string]string{"instance": "b:9100", "code": "200"}},
+			},
+		},
+		{
+			name: "allow and deny labels",
+			target: influxdb.ScraperTarget{
+				MetricAllow: []string{"^go_"},
+				LabelAllow:  []string{"instance", "job"},
+				LabelDeny:   []string{"job"},
+			},
+			want: []Metrics{
+				{Name: "go_goroutines", Tags: map[string]string{"instance": "a:9100"}},
+				{Name: "go_info", Tags: map[string]string{"instance": "a:9100"}},
+			},
+		},
+		{
+			name: "relabel",
+			target: influxdb.ScraperTarget{
+				Relabel: []influxdb.RelabelRule{
+					{SourceLabels: []string{"instance"}, Regex: "b:.*", Action: influxdb.RelabelDrop},
+					{SourceLabels: []string{"instance"}, Regex: "(.*):.*", TargetLabel: "host"},
+					{Regex: "instance|version", Action: influxdb.RelabelLabelDrop},
+					{SourceLabels: []string{"__name__"}, Regex: "go_(.*)", TargetLabel: "__name__", Replacement: "golang_$1"},
+					{SourceLabels: []string{"__name__", "host"}, Regex: "golang_.*;a", Action: influxdb.RelabelKeep},
+				},
+			},
+			want: []Metrics{
+				{Name: "golang_goroutines", Tags: map[string]string{"host": "a", "job": "node"}},
+				{Name: "golang_info", Tags: map[string]string{"host": "a"}},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f, err := newFilter(c.target)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(f.apply(ms()), c.want); diff != "" {
+				t.Errorf("filtered metrics are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/nats"
@@ -15,6 +16,11 @@ type handler struct {
 	Scraper   Scraper
 	Publisher nats.Publisher
 	Logger    *zap.Logger
+
+	// Status records the outcome of each scrape, if set.
+	Status influxdb.ScraperStatusService
+	// Timeout is the time allowed for scrapes of targets without a timeout.
+	Timeout time.Duration
 }
 
 // Process consumes scraper target from scraper target queue,
@@ -29,7 +35,20 @@ func (h *handler) Process(s nats.Subscription, m nats.Message) {
 		return
 	}
 
-	ms, err := h.Scraper.Gather(context.TODO(), *req)
+	timeout := h.Timeout
+	if req.Timeout > 0 {
+		timeout = time.Duration(req.Timeout) * time.Millisecond
+	}
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	ms, err := h.Scraper.Gather(ctx, *req)
+	h.updateStatus(req.ID, start, len(ms.MetricsSlice), err)
 	if err != nil {
 		h.Logger.Error("unable to gather", zap.Error(err))
 		return
@@ -48,3 +67,21 @@ func (h *handler) Process(s nats.Subscription, m nats.Message) {
 	}
 
 }
+
+func (h *handler) updateStatus(id influxdb.ID, start time.Time, samples int, err error) {
+	if h.Status == nil || !id.Valid() {
+		return
+	}
+
+	status := &influxdb.ScraperStatus{
+		LastScrape: start.UTC(),
+		Duration:   int64(time.Since(start) / time.Millisecond),
+		Samples:    samples,
+	}
+	if err != nil {
+		status.Error = err.Error()
+	}
+	if err := h.Status.UpdateScraperStatus(context.Background(), id, status); err != nil {
+		h.Logger.Error("unable to update scraper status", zap.Error(err))
+	}
+}
@@ -0,0 +1,122 @@
+package gather
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+
+	"github.com/influxdata/influxdb"
+)
+
+// fetcher requests the metrics of targets over HTTP, with the credentials and
+// TLS settings of the targets.
+type fetcher struct {
+	// Secrets holds the credentials and client keys of targets.
+	Secrets influxdb.SecretService
+}
+
+// get requests the URL of target, accepting the media types of accept if it
+// is set. Responses other than 200 OK are errors.
+func (f *fetcher) get(ctx context.Context, target influxdb.ScraperTarget, accept string) (*http.Response, error) {
+	client, err := f.client(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", target.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if err := f.authorize(ctx, req, target); err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("server returned HTTP status %s", resp.Status)
+	}
+	return resp, nil
+}
+
+// client returns the client connecting to target.
+func (f *fetcher) client(ctx context.Context, target influxdb.ScraperTarget) (*http.Client, error) {
+	if target.TLS == nil {
+		return http.DefaultClient, nil
+	}
+
+	cfg := &tls.Config{
+		ServerName:         target.TLS.ServerName,
+		InsecureSkipVerify: target.TLS.InsecureSkipVerify,
+	}
+	if target.TLS.CA != "" {
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM([]byte(target.TLS.CA)) {
+			return nil, fmt.Errorf("no certificates found in tls ca")
+		}
+	}
+	if target.TLS.Cert != "" {
+		key, err := f.loadSecret(ctx, target, target.TLS.KeySecret)
+		if err != nil {
+			return nil, err
+		}
+		cert, err := tls.X509KeyPair([]byte(target.TLS.Cert), []byte(key))
+		if err != nil {
+			return nil, fmt.Errorf("invalid tls client certificate: %v", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	// Transports are built per scrape, so connections aren't kept alive.
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:             http.ProxyFromEnvironment,
+			TLSClientConfig:   cfg,
+			DisableKeepAlives: true,
+		},
+	}, nil
+}
+
+// authorize sets the credentials of target on req.
+func (f *fetcher) authorize(ctx context.Context, req *http.Request, target influxdb.ScraperTarget) error {
+	if target.Auth == nil {
+		return nil
+	}
+
+	switch target.Auth.Type {
+	case influxdb.ScraperAuthBasic:
+		var password string
+		if target.Auth.PasswordSecret != "" {
+			var err error
+			if password, err = f.loadSecret(ctx, target, target.Auth.PasswordSecret); err != nil {
+				return err
+			}
+		}
+		req.SetBasicAuth(target.Auth.Username, password)
+	case influxdb.ScraperAuthBearer:
+		token, err := f.loadSecret(ctx, target, target.Auth.TokenSecret)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	default:
+		return fmt.Errorf("unsupported auth type %q", target.Auth.Type)
+	}
+	return nil
+}
+
+func (f *fetcher) loadSecret(ctx context.Context, target influxdb.ScraperTarget, key string) (string, error) {
+	if f.Secrets == nil {
+		return "", fmt.Errorf("secret %q of target %q cannot be loaded without a secret service", key, target.Name)
+	}
+	return f.Secrets.LoadSecret(ctx, target.OrgID, key)
+}
@@ -0,0 +1,150 @@
+package gather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+// defaultJSONMeasurement is the name of the metrics of json targets without
+// a measurement.
+const defaultJSONMeasurement = "json"
+
+// jsonScraper records the values of the JSON document served by a target, as
+// configured by the JSON settings of the target. Numbers are recorded as
+// floats, so fields keep their type when values happen to be integral.
+// implements Scraper interfaces.
+type jsonScraper struct {
+	fetcher
+}
+
+// Gather reads the fields of the JSON document of a scraper target url.
+func (p *jsonScraper) Gather(ctx context.Context, target influxdb.ScraperTarget) (collected MetricsCollection, err error) {
+	resp, err := p.get(ctx, target, "application/json")
+	if err != nil {
+		return collected, err
+	}
+	defer resp.Body.Close()
+
+	return p.parse(resp.Body, time.Now(), target)
+}
+
+func (p *jsonScraper) parse(r io.Reader, now time.Time, target influxdb.ScraperTarget) (collected MetricsCollection, err error) {
+	var doc interface{}
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return collected, fmt.Errorf("reading json document failed: %s", err)
+	}
+
+	var cfg influxdb.ScraperJSON
+	if target.JSON != nil {
+		cfg = *target.JSON
+	}
+	if cfg.Measurement == "" {
+		cfg.Measurement = defaultJSONMeasurement
+	}
+
+	fields := make(map[string]interface{})
+	if len(cfg.Fields) == 0 {
+		flattenJSON(doc, "", fields)
+	}
+	for _, path := range cfg.Fields {
+		v, ok := lookupJSON(doc, path)
+		if !ok {
+			continue
+		}
+		if f, ok := jsonField(v); ok {
+			fields[path] = f
+		}
+	}
+	if len(fields) == 0 {
+		return collected, fmt.Errorf("no fields found in json document")
+	}
+
+	tags := make(map[string]string)
+	for _, path := range cfg.Tags {
+		v, ok := lookupJSON(doc, path)
+		if !ok {
+			continue
+		}
+		switch v := v.(type) {
+		case string:
+			tags[path] = v
+		case json.Number:
+			tags[path] = v.String()
+		case bool:
+			tags[path] = strconv.FormatBool(v)
+		}
+	}
+
+	return collect(target, []Metrics{
+		{
+			Name:      cfg.Measurement,
+			Tags:      tags,
+			Fields:    fields,
+			Timestamp: now,
+			Type:      MetricTypeUntyped,
+		},
+	})
+}
+
+// flattenJSON adds the numbers and booleans of v outside of arrays to fields,
+// named after their paths below prefix.
+func flattenJSON(v interface{}, prefix string, fields map[string]interface{}) {
+	if obj, ok := v.(map[string]interface{}); ok {
+		for k, child := range obj {
+			path := k
+			if prefix != "" {
+				path = prefix + "." + k
+			}
+			flattenJSON(child, path, fields)
+		}
+		return
+	}
+
+	if f, ok := jsonField(v); ok && prefix != "" {
+		fields[prefix] = f
+	}
+}
+
+// lookupJSON returns the value at path of v.
+func lookupJSON(v interface{}, path string) (interface{}, bool) {
+	for _, key := range strings.Split(path, ".") {
+		switch node := v.(type) {
+		case map[string]interface{}:
+			child, ok := node[key]
+			if !ok {
+				return nil, false
+			}
+			v = child
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			v = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+func jsonField(v interface{}) (interface{}, bool) {
+	switch v := v.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case bool:
+		return v, true
+	default:
+		return nil, false
+	}
+}
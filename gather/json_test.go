@@ -0,0 +1,71 @@
+package gather
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+)
+
+const sampleExpvar = `{
+"cmdline": ["/usr/bin/app", "-v"],
+"version": "1.2.3",
+"requests": 42,
+"memstats": {"Alloc": 1024, "NumGC": 3, "EnableGC": true, "PauseNs": [100, 200]}
+}`
+
+func TestJSONScraper(t *testing.T) {
+	now := time.Unix(1000, 0)
+	target := influxdb.ScraperTarget{OrgID: *orgID, BucketID: *bucketID}
+
+	collected, err := new(jsonScraper).parse(strings.NewReader(sampleExpvar), now, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := MetricsSlice{
+		{
+			Name: "json",
+			Tags: map[string]string{},
+			Fields: map[string]interface{}{
+				"requests":          float64(42),
+				"memstats.Alloc":    float64(1024),
+				"memstats.NumGC":    float64(3),
+				"memstats.EnableGC": true,
+			},
+			Timestamp: now,
+			Type:      MetricTypeUntyped,
+		},
+	}
+	if diff := cmp.Diff(collected.MetricsSlice, want); diff != "" {
+		t.Fatalf("json metrics are different -got/+want\ndiff %s", diff)
+	}
+
+	target.JSON = &influxdb.ScraperJSON{
+		Measurement: "expvar",
+		Fields:      []string{"memstats.Alloc", "memstats.PauseNs.1", "missing"},
+		Tags:        []string{"version", "cmdline.1"},
+	}
+	collected, err = new(jsonScraper).parse(strings.NewReader(sampleExpvar), now, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = MetricsSlice{
+		{
+			Name:      "expvar",
+			Tags:      map[string]string{"version": "1.2.3", "cmdline.1": "-v"},
+			Fields:    map[string]interface{}{"memstats.Alloc": float64(1024), "memstats.PauseNs.1": float64(200)},
+			Timestamp: now,
+			Type:      MetricTypeUntyped,
+		},
+	}
+	if diff := cmp.Diff(collected.MetricsSlice, want); diff != "" {
+		t.Fatalf("json metrics are different -got/+want\ndiff %s", diff)
+	}
+
+	target.JSON.Fields = []string{"version"}
+	if _, err := new(jsonScraper).parse(strings.NewReader(sampleExpvar), now, target); err == nil {
+		t.Fatal("expected documents without fields to fail")
+	}
+}
@@ -0,0 +1,56 @@
+package gather
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+)
+
+// lineProtocolScraper parses the line protocol points served by a target.
+// Points without timestamps are recorded at the time of the scrape.
+// implements Scraper interfaces.
+type lineProtocolScraper struct {
+	fetcher
+}
+
+// Gather parses the points of a scraper target url.
+func (p *lineProtocolScraper) Gather(ctx context.Context, target influxdb.ScraperTarget) (collected MetricsCollection, err error) {
+	resp, err := p.get(ctx, target, "text/plain")
+	if err != nil {
+		return collected, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return collected, err
+	}
+	return p.parse(body, time.Now(), target)
+}
+
+func (p *lineProtocolScraper) parse(body []byte, now time.Time, target influxdb.ScraperTarget) (collected MetricsCollection, err error) {
+	points, err := models.ParsePointsWithPrecision(body, now, "n")
+	if err != nil {
+		return collected, fmt.Errorf("reading line protocol failed: %s", err)
+	}
+
+	ms := make([]Metrics, 0, len(points))
+	for _, pt := range points {
+		fields, err := pt.Fields()
+		if err != nil {
+			return collected, fmt.Errorf("reading line protocol failed: %s", err)
+		}
+		ms = append(ms, Metrics{
+			Name:      string(pt.Name()),
+			Tags:      pt.Tags().Map(),
+			Fields:    fields,
+			Timestamp: pt.Time(),
+			Type:      MetricTypeUntyped,
+		})
+	}
+	return collect(target, ms)
+}
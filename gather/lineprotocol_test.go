@@ -0,0 +1,48 @@
+package gather
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+)
+
+func TestLineProtocolScraper(t *testing.T) {
+	now := time.Unix(1000, 0)
+	target := influxdb.ScraperTarget{
+		OrgID:    *orgID,
+		BucketID: *bucketID,
+		Tags:     map[string]string{"service": "api"},
+	}
+
+	body := []byte("cpu,host=a usage=0.5,count=3i,ok=true,state=\"idle\" 1000000000\nmem free=10i\n")
+	collected, err := new(lineProtocolScraper).parse(body, now, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := MetricsSlice{
+		{
+			Name:      "cpu",
+			Tags:      map[string]string{"host": "a", "service": "api"},
+			Fields:    map[string]interface{}{"usage": 0.5, "count": int64(3), "ok": true, "state": "idle"},
+			Timestamp: time.Unix(1, 0),
+			Type:      MetricTypeUntyped,
+		},
+		{
+			Name:      "mem",
+			Tags:      map[string]string{"service": "api"},
+			Fields:    map[string]interface{}{"free": int64(10)},
+			Timestamp: now,
+			Type:      MetricTypeUntyped,
+		},
+	}
+	if diff := cmp.Diff(collected.MetricsSlice, want); diff != "" {
+		t.Fatalf("line protocol metrics are different -got/+want\ndiff %s", diff)
+	}
+
+	if _, err := new(lineProtocolScraper).parse([]byte("cpu usage=\n"), now, target); err == nil {
+		t.Fatal("expected invalid line protocol to fail")
+	}
+}
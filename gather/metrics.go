@@ -2,7 +2,9 @@ package gather
 
 import (
 	"bytes"
+	"encoding/json"
 	"io"
+	"strconv"
 	"time"
 
 	"github.com/gogo/protobuf/proto"
@@ -26,6 +28,76 @@ type Metrics struct {
 	Type      MetricType             `json:"type"`
 }
 
+// Types of integer fields in the JSON encoding of Metrics, where numbers are
+// otherwise decoded as floats.
+const (
+	integerField  = "integer"
+	unsignedField = "unsigned"
+)
+
+// metricsJSON is the JSON encoding of Metrics.
+type metricsJSON struct {
+	metrics
+	FieldTypes map[string]string `json:"fieldTypes,omitempty"`
+}
+
+// metrics is Metrics without its JSON methods.
+type metrics Metrics
+
+// MarshalJSON implements the json.Marshaler interface, recording the types
+// of the integer fields of m.
+func (m Metrics) MarshalJSON() ([]byte, error) {
+	v := metricsJSON{metrics: metrics(m)}
+	for k, f := range m.Fields {
+		var typ string
+		switch f.(type) {
+		case int64, int:
+			typ = integerField
+		case uint64:
+			typ = unsignedField
+		default:
+			continue
+		}
+		if v.FieldTypes == nil {
+			v.FieldTypes = make(map[string]string)
+		}
+		v.FieldTypes[k] = typ
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (m *Metrics) UnmarshalJSON(b []byte) error {
+	var v metricsJSON
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return err
+	}
+
+	for k, f := range v.Fields {
+		n, ok := f.(json.Number)
+		if !ok {
+			continue
+		}
+		var err error
+		switch v.FieldTypes[k] {
+		case integerField:
+			v.Fields[k], err = n.Int64()
+		case unsignedField:
+			v.Fields[k], err = strconv.ParseUint(n.String(), 10, 64)
+		default:
+			v.Fields[k], err = n.Float64()
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	*m = Metrics(v.metrics)
+	return nil
+}
+
 // MetricsSlice is a slice of Metrics
 type MetricsSlice []Metrics
 
@@ -184,6 +184,22 @@ func TestMetricsMarshal(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "field types",
+			ms: []Metrics{
+				{
+					Timestamp: time.Unix(12345, 0),
+					Tags:      map[string]string{},
+					Fields: map[string]interface{}{
+						"float":    float64(36),
+						"integer":  int64(-7),
+						"unsigned": uint64(18446744073709551615),
+						"bool":     true,
+					},
+					Type: MetricTypeUntyped,
+				},
+			},
+		},
 	}
 	for _, c := range cases {
 		b, err := json.Marshal(c.ms)
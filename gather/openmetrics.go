@@ -0,0 +1,519 @@
+package gather
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+// openMetricsAccept asks targets for OpenMetrics rather than the Prometheus
+// text format.
+const openMetricsAccept = "application/openmetrics-text; version=1.0.0"
+
+// openMetricsScraper parses metrics in the OpenMetrics text format.
+//
+// The samples of a metric family sharing labels make up a metric named after
+// the family, with a field for each sample, as the prometheus scraper does:
+// counter and _total for counters, gauge for gauges, info for infos, a field
+// for each state of statesets, a field for each bucket and quantile of
+// histograms and summaries, with count and sum, and created for _created
+// samples. Exemplars are recorded as a <field>_exemplar field with a
+// <field>_exemplar_<label> field for each of their labels.
+// implements Scraper interfaces.
+type openMetricsScraper struct {
+	fetcher
+}
+
+// Gather parse metrics from a scraper target url.
+func (p *openMetricsScraper) Gather(ctx context.Context, target influxdb.ScraperTarget) (collected MetricsCollection, err error) {
+	resp, err := p.get(ctx, target, openMetricsAccept)
+	if err != nil {
+		return collected, err
+	}
+	defer resp.Body.Close()
+
+	return p.parse(resp.Body, time.Now(), target)
+}
+
+func (p *openMetricsScraper) parse(r io.Reader, now time.Time, target influxdb.ScraperTarget) (collected MetricsCollection, err error) {
+	families, err := parseOpenMetrics(r)
+	if err != nil {
+		return collected, fmt.Errorf("reading openmetrics format failed: %s", err)
+	}
+
+	ms := make([]Metrics, 0)
+	for _, f := range families {
+		ms = append(ms, f.metrics(now)...)
+	}
+	return collect(target, ms)
+}
+
+// OpenMetrics metric family types.
+const (
+	omCounter        = "counter"
+	omGauge          = "gauge"
+	omHistogram      = "histogram"
+	omGaugeHistogram = "gaugehistogram"
+	omSummary        = "summary"
+	omInfo           = "info"
+	omStateset       = "stateset"
+	omUnknown        = "unknown"
+)
+
+// omSuffixes are the suffixes of the names of the samples of each type.
+var omSuffixes = map[string][]string{
+	omCounter:        {"_total", "_created"},
+	omHistogram:      {"_bucket", "_count", "_sum", "_created"},
+	omGaugeHistogram: {"_bucket", "_gcount", "_gsum"},
+	omSummary:        {"_count", "_sum", "_created"},
+	omInfo:           {"_info"},
+}
+
+type omFamily struct {
+	name    string
+	typ     string
+	samples []omSample
+}
+
+type omSample struct {
+	// suffix is the suffix of the sample name after the family name.
+	suffix    string
+	labels    map[string]string
+	value     float64
+	timestamp *time.Time
+	exemplar  *omExemplar
+}
+
+type omExemplar struct {
+	labels map[string]string
+	value  float64
+}
+
+// parseOpenMetrics parses the metric families of r, which must end with
+// # EOF.
+func parseOpenMetrics(r io.Reader) ([]*omFamily, error) {
+	var families []*omFamily
+	var current *omFamily
+	family := func(name, typ string) *omFamily {
+		if current != nil && current.name == name {
+			if typ != "" {
+				current.typ = typ
+			}
+			return current
+		}
+		if typ == "" {
+			typ = omUnknown
+		}
+		current = &omFamily{name: name, typ: typ}
+		families = append(families, current)
+		return current
+	}
+
+	scanner := bufio.NewScanner(r)
+	n := 0
+	for scanner.Scan() {
+		n++
+		line := scanner.Text()
+		if line == "# EOF" {
+			return families, nil
+		}
+
+		if strings.HasPrefix(line, "# ") {
+			parts := strings.SplitN(line[2:], " ", 3)
+			if len(parts) < 2 {
+				continue
+			}
+			switch parts[0] {
+			case "TYPE":
+				if len(parts) < 3 {
+					return nil, fmt.Errorf("line %d: missing type of metric family %q", n, parts[1])
+				}
+				typ := parts[2]
+				if _, ok := omSuffixes[typ]; !ok && typ != omGauge && typ != omStateset && typ != omUnknown {
+					return nil, fmt.Errorf("line %d: invalid type %q", n, typ)
+				}
+				family(parts[1], typ)
+			case "HELP", "UNIT":
+				family(parts[1], "")
+			}
+			continue
+		}
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		name, s, err := parseOMSample(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", n, err)
+		}
+		if current == nil || !current.owns(name) {
+			family(name, omUnknown)
+		}
+		s.suffix = name[len(current.name):]
+		current.samples = append(current.samples, s)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("missing # EOF")
+}
+
+// owns returns true if the samples named name belong to f.
+func (f *omFamily) owns(name string) bool {
+	if !strings.HasPrefix(name, f.name) {
+		return false
+	}
+	suffix := name[len(f.name):]
+	if suffix == "" {
+		switch f.typ {
+		case omGauge, omStateset, omUnknown, omSummary:
+			return true
+		}
+		return false
+	}
+	for _, s := range omSuffixes[f.typ] {
+		if suffix == s {
+			return true
+		}
+	}
+	return false
+}
+
+// metrics returns the metrics of the samples of f, recorded at now unless
+// they have timestamps.
+func (f *omFamily) metrics(now time.Time) []Metrics {
+	var ms []Metrics
+	index := map[string]int{}
+	for _, s := range f.samples {
+		field, tags := f.field(s)
+
+		key := groupKey(tags)
+		i, ok := index[key]
+		if !ok {
+			i = len(ms)
+			index[key] = i
+			ms = append(ms, Metrics{
+				Name:      f.name,
+				Tags:      tags,
+				Fields:    map[string]interface{}{},
+				Timestamp: now,
+				Type:      f.metricType(),
+			})
+		}
+		m := &ms[i]
+		if s.timestamp != nil {
+			m.Timestamp = *s.timestamp
+		}
+		if math.IsNaN(s.value) || math.IsInf(s.value, 0) {
+			continue
+		}
+		m.Fields[field] = s.value
+		if e := s.exemplar; e != nil {
+			m.Fields[field+"_exemplar"] = e.value
+			for k, v := range e.labels {
+				m.Fields[field+"_exemplar_"+k] = v
+			}
+		}
+	}
+
+	// Metrics without fields can't be recorded.
+	kept := ms[:0]
+	for _, m := range ms {
+		if len(m.Fields) > 0 {
+			kept = append(kept, m)
+		}
+	}
+	return kept
+}
+
+// field returns the field of sample s, and the tags of its metric.
+func (f *omFamily) field(s omSample) (string, map[string]string) {
+	tags := make(map[string]string, len(s.labels))
+	for k, v := range s.labels {
+		tags[k] = v
+	}
+
+	switch s.suffix {
+	case "_created":
+		return "created", tags
+	case "_count", "_gcount":
+		return "count", tags
+	case "_sum", "_gsum":
+		return "sum", tags
+	case "_bucket":
+		le := tags["le"]
+		delete(tags, "le")
+		return formatBound(le), tags
+	}
+
+	switch f.typ {
+	case omCounter:
+		return "counter", tags
+	case omGauge:
+		return "gauge", tags
+	case omInfo:
+		return "info", tags
+	case omStateset:
+		state := tags[f.name]
+		delete(tags, f.name)
+		return state, tags
+	case omSummary:
+		q := tags["quantile"]
+		delete(tags, "quantile")
+		return formatBound(q), tags
+	default:
+		return "value", tags
+	}
+}
+
+func (f *omFamily) metricType() MetricType {
+	switch f.typ {
+	case omCounter:
+		return MetricTypeCounter
+	case omGauge, omInfo, omStateset:
+		return MetricTypeGauge
+	case omSummary:
+		return MetricTypeSummary
+	case omHistogram, omGaugeHistogram:
+		return MetricTypeHistogrm
+	default:
+		return MetricTypeUntyped
+	}
+}
+
+// formatBound formats bucket bounds and quantiles as the prometheus scraper
+// names their fields.
+func formatBound(s string) string {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return s
+	}
+	return fmt.Sprint(f)
+}
+
+func groupKey(tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k)
+		b.WriteByte(0)
+		b.WriteString(tags[k])
+		b.WriteByte(0)
+	}
+	return b.String()
+}
+
+// parseOMSample parses a sample line:
+//
+//	name{label="value",...} value [timestamp] [# {label="value",...} value [timestamp]]
+func parseOMSample(line string) (string, omSample, error) {
+	l := &omLexer{s: line}
+	var s omSample
+
+	name := l.name()
+	if name == "" {
+		return "", s, fmt.Errorf("invalid metric name")
+	}
+
+	var err error
+	if s.labels, err = l.labels(); err != nil {
+		return "", s, err
+	}
+	if s.value, err = l.number(); err != nil {
+		return "", s, err
+	}
+
+	if l.done() {
+		return name, s, nil
+	}
+	if !l.consume(' ') {
+		return "", s, fmt.Errorf("unexpected %q after value", l.rest())
+	}
+	if l.peek() != '#' {
+		ts, err := l.timestamp()
+		if err != nil {
+			return "", s, err
+		}
+		s.timestamp = &ts
+		if l.done() {
+			return name, s, nil
+		}
+		if !l.consume(' ') {
+			return "", s, fmt.Errorf("unexpected %q after timestamp", l.rest())
+		}
+	}
+
+	if !l.consume('#') || !l.consume(' ') {
+		return "", s, fmt.Errorf("unexpected %q", l.rest())
+	}
+	e := &omExemplar{}
+	if l.peek() != '{' {
+		return "", s, fmt.Errorf("exemplar missing labels")
+	}
+	if e.labels, err = l.labels(); err != nil {
+		return "", s, err
+	}
+	if e.value, err = l.number(); err != nil {
+		return "", s, err
+	}
+	// The timestamp of the exemplar is not recorded.
+	if !l.done() {
+		if !l.consume(' ') {
+			return "", s, fmt.Errorf("unexpected %q after exemplar", l.rest())
+		}
+		if _, err := l.timestamp(); err != nil {
+			return "", s, err
+		}
+	}
+	if !l.done() {
+		return "", s, fmt.Errorf("unexpected %q after exemplar", l.rest())
+	}
+	s.exemplar = e
+	return name, s, nil
+}
+
+// omLexer reads the tokens of a sample line.
+type omLexer struct {
+	s   string
+	pos int
+}
+
+func (l *omLexer) done() bool   { return l.pos >= len(l.s) }
+func (l *omLexer) rest() string { return l.s[l.pos:] }
+
+func (l *omLexer) peek() byte {
+	if l.done() {
+		return 0
+	}
+	return l.s[l.pos]
+}
+
+func (l *omLexer) consume(c byte) bool {
+	if l.peek() != c || l.done() {
+		return false
+	}
+	l.pos++
+	return true
+}
+
+// name reads a metric or label name.
+func (l *omLexer) name() string {
+	start := l.pos
+	for !l.done() {
+		c := l.s[l.pos]
+		if c == '_' || c == ':' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (l.pos > start && c >= '0' && c <= '9') {
+			l.pos++
+			continue
+		}
+		break
+	}
+	return l.s[start:l.pos]
+}
+
+// labels reads the labels of a sample or exemplar, if any, and the space
+// following them.
+func (l *omLexer) labels() (map[string]string, error) {
+	labels := map[string]string{}
+	if l.consume('{') {
+		for !l.consume('}') {
+			name := l.name()
+			if name == "" {
+				return nil, fmt.Errorf("invalid label name at %q", l.rest())
+			}
+			if !l.consume('=') || !l.consume('"') {
+				return nil, fmt.Errorf("invalid label %q", name)
+			}
+			value, err := l.quoted()
+			if err != nil {
+				return nil, err
+			}
+			labels[name] = value
+			if !l.consume(',') && l.peek() != '}' {
+				return nil, fmt.Errorf("unexpected %q after label %q", l.rest(), name)
+			}
+		}
+	}
+	if !l.consume(' ') {
+		return nil, fmt.Errorf("missing value")
+	}
+	return labels, nil
+}
+
+// quoted reads the rest of a quoted label value.
+func (l *omLexer) quoted() (string, error) {
+	var b strings.Builder
+	for !l.done() {
+		c := l.s[l.pos]
+		l.pos++
+		switch c {
+		case '"':
+			return b.String(), nil
+		case '\\':
+			if l.done() {
+				return "", fmt.Errorf("unterminated label value")
+			}
+			switch e := l.s[l.pos]; e {
+			case 'n':
+				b.WriteByte('\n')
+			case '"', '\\':
+				b.WriteByte(e)
+			default:
+				return "", fmt.Errorf("invalid escape \\%c in label value", e)
+			}
+			l.pos++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", fmt.Errorf("unterminated label value")
+}
+
+func (l *omLexer) token() string {
+	start := l.pos
+	for !l.done() && l.s[l.pos] != ' ' {
+		l.pos++
+	}
+	return l.s[start:l.pos]
+}
+
+func (l *omLexer) number() (float64, error) {
+	tok := l.token()
+	f, err := strconv.ParseFloat(tok, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", tok)
+	}
+	return f, nil
+}
+
+// timestamp reads a timestamp in seconds.
+func (l *omLexer) timestamp() (time.Time, error) {
+	tok := l.token()
+	f, err := strconv.ParseFloat(tok, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q", tok)
+	}
+	// Decimal timestamps are read exactly, as floats lose nanoseconds.
+	if i := strings.IndexByte(tok, '.'); i > 0 && i >= len(tok)-10 && !strings.ContainsAny(tok, "eE") {
+		sec, err := strconv.ParseInt(tok[:i], 10, 64)
+		frac := (tok[i+1:] + "000000000")[:9]
+		nsec, ferr := strconv.ParseInt(frac, 10, 64)
+		if err == nil && ferr == nil && sec >= 0 {
+			return time.Unix(sec, nsec).UTC(), nil
+		}
+	}
+	sec, frac := math.Modf(f)
+	return time.Unix(int64(sec), int64(math.Round(frac*1e9))).UTC(), nil
+}
@@ -0,0 +1,127 @@
+package gather
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+)
+
+const sampleOpenMetrics = `# TYPE http_requests counter
+# HELP http_requests Requests served.
+http_requests_total{code="200"} 1027 1520879607.789 # {trace_id="KOO5S4vxi0o"} 0.67
+http_requests_created{code="200"} 1520430000.123
+# TYPE build info
+build_info{version="1.2.3",revision="abc"} 1
+# TYPE state stateset
+state{state="up"} 1
+state{state="down"} 0
+# TYPE latency histogram
+# UNIT latency seconds
+latency_bucket{le="0.1"} 8
+latency_bucket{le="+Inf"} 10 # {trace_id="a\"b"} 1.5 1520879607.0
+latency_count 10
+latency_sum 3.2
+# TYPE rpc summary
+rpc{quantile="0.5"} 0.25
+rpc_count 4
+rpc_sum 1.5
+# TYPE temperature gauge
+temperature{room="a b"} NaN
+temperature{room="c"} 21.5
+untyped_thing 3
+# EOF
+`
+
+func TestOpenMetricsScraper(t *testing.T) {
+	now := time.Unix(1000, 0)
+	target := influxdb.ScraperTarget{OrgID: *orgID, BucketID: *bucketID}
+
+	collected, err := new(openMetricsScraper).parse(strings.NewReader(sampleOpenMetrics), now, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := MetricsSlice{
+		{
+			Name: "http_requests",
+			Type: MetricTypeCounter,
+			Tags: map[string]string{"code": "200"},
+			Fields: map[string]interface{}{
+				"counter":                   float64(1027),
+				"counter_exemplar":          0.67,
+				"counter_exemplar_trace_id": "KOO5S4vxi0o",
+				"created":                   1520430000.123,
+			},
+			Timestamp: time.Unix(1520879607, 789000000).UTC(),
+		},
+		{
+			Name:      "build",
+			Type:      MetricTypeGauge,
+			Tags:      map[string]string{"version": "1.2.3", "revision": "abc"},
+			Fields:    map[string]interface{}{"info": float64(1)},
+			Timestamp: now,
+		},
+		{
+			Name:      "state",
+			Type:      MetricTypeGauge,
+			Tags:      map[string]string{},
+			Fields:    map[string]interface{}{"up": float64(1), "down": float64(0)},
+			Timestamp: now,
+		},
+		{
+			Name: "latency",
+			Type: MetricTypeHistogrm,
+			Tags: map[string]string{},
+			Fields: map[string]interface{}{
+				"0.1":                    float64(8),
+				"+Inf":                   float64(10),
+				"+Inf_exemplar":          1.5,
+				"+Inf_exemplar_trace_id": `a"b`,
+				"count":                  float64(10),
+				"sum":                    3.2,
+			},
+			Timestamp: now,
+		},
+		{
+			Name:      "rpc",
+			Type:      MetricTypeSummary,
+			Tags:      map[string]string{},
+			Fields:    map[string]interface{}{"0.5": 0.25, "count": float64(4), "sum": 1.5},
+			Timestamp: now,
+		},
+		{
+			Name:      "temperature",
+			Type:      MetricTypeGauge,
+			Tags:      map[string]string{"room": "c"},
+			Fields:    map[string]interface{}{"gauge": 21.5},
+			Timestamp: now,
+		},
+		{
+			Name:      "untyped_thing",
+			Type:      MetricTypeUntyped,
+			Tags:      map[string]string{},
+			Fields:    map[string]interface{}{"value": float64(3)},
+			Timestamp: now,
+		},
+	}
+	if diff := cmp.Diff(collected.MetricsSlice, want); diff != "" {
+		t.Fatalf("openmetrics metrics are different -got/+want\ndiff %s", diff)
+	}
+	if _, err := collected.MetricsSlice.Points(); err != nil {
+		t.Fatalf("openmetrics metrics must make valid points: %v", err)
+	}
+
+	for _, bad := range []string{
+		"up 1\n",
+		"up{a=\"b} 1\n# EOF\n",
+		"up 1 # trace\n# EOF\n",
+		"# TYPE up nonsense\n# EOF\n",
+	} {
+		if _, err := new(openMetricsScraper).parse(strings.NewReader(bad), now, target); err == nil {
+			t.Errorf("expected %q to be invalid", bad)
+		}
+	}
+}
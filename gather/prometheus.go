@@ -17,11 +17,13 @@ import (
 
 // prometheusScraper handles parsing prometheus metrics.
 // implements Scraper interfaces.
-type prometheusScraper struct{}
+type prometheusScraper struct {
+	fetcher
+}
 
 // Gather parse metrics from a scraper target url.
 func (p *prometheusScraper) Gather(ctx context.Context, target influxdb.ScraperTarget) (collected MetricsCollection, err error) {
-	resp, err := http.Get(target.URL)
+	resp, err := p.get(ctx, target, "")
 	if err != nil {
 		return collected, err
 	}
@@ -102,13 +104,7 @@ func (p *prometheusScraper) parse(r io.Reader, header http.Header, target influx
 
 	}
 
-	collected = MetricsCollection{
-		MetricsSlice: ms,
-		OrgID:        target.OrgID,
-		BucketID:     target.BucketID,
-	}
-
-	return collected, nil
+	return collect(target, ms)
 }
 
 // Get labels from metric
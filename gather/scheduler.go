@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/influxdata/influxdb"
@@ -14,16 +15,21 @@ import (
 
 // nats subjects
 const (
-	MetricsSubject    = "metrics"
-	promTargetSubject = "promTarget"
+	MetricsSubject = "metrics"
+	targetSubject  = "scraperTarget"
 )
 
+// resolution is the longest time between checks for targets due a scrape.
+const resolution = time.Second
+
 // Scheduler is struct to run scrape jobs.
 type Scheduler struct {
 	Targets influxdb.ScraperTargetStoreService
-	// Interval is between each metrics gathering event.
+	// Interval is between each metrics gathering event of targets
+	// without an interval of their own.
 	Interval time.Duration
 	// Timeout is the maxisium time duration allowed by each TCP request
+	// of targets without a timeout of their own.
 	Timeout time.Duration
 
 	// Publisher will send the gather requests and gathered metrics to the queue.
@@ -32,6 +38,39 @@ type Scheduler struct {
 	Logger *zap.Logger
 
 	gather chan struct{}
+
+	secrets     influxdb.SecretService
+	status      influxdb.ScraperStatusService
+	discoverers []Discoverer
+
+	// next is the time each target is next due a scrape, by scheduleKey.
+	next map[string]time.Time
+	now  func() time.Time
+}
+
+// SchedulerOption configures a Scheduler.
+type SchedulerOption func(*Scheduler)
+
+// WithSecretService sets the service the credentials and client keys of
+// targets are loaded from.
+func WithSecretService(s influxdb.SecretService) SchedulerOption {
+	return func(sch *Scheduler) {
+		sch.secrets = s
+	}
+}
+
+// WithScraperStatusService sets the service the status of scrapes is recorded in.
+func WithScraperStatusService(s influxdb.ScraperStatusService) SchedulerOption {
+	return func(sch *Scheduler) {
+		sch.status = s
+	}
+}
+
+// WithDiscoverers adds the targets found by ds to the targets of the store.
+func WithDiscoverers(ds ...Discoverer) SchedulerOption {
+	return func(sch *Scheduler) {
+		sch.discoverers = append(sch.discoverers, ds...)
+	}
 }
 
 // NewScheduler creates a new Scheduler and subscriptions for scraper jobs.
@@ -43,6 +82,7 @@ func NewScheduler(
 	s nats.Subscriber,
 	interval time.Duration,
 	timeout time.Duration,
+	opts ...SchedulerOption,
 ) (*Scheduler, error) {
 	if interval == 0 {
 		interval = 60 * time.Second
@@ -57,13 +97,20 @@ func NewScheduler(
 		Publisher: p,
 		Logger:    l,
 		gather:    make(chan struct{}, 100),
+		next:      make(map[string]time.Time),
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		opt(scheduler)
 	}
 
 	for i := 0; i < numScrapers; i++ {
-		err := s.Subscribe(promTargetSubject, "metrics", &handler{
-			Scraper:   new(prometheusScraper),
+		err := s.Subscribe(targetSubject, "metrics", &handler{
+			Scraper:   newScrapers(scheduler.secrets),
 			Publisher: p,
 			Logger:    l,
+			Status:    scheduler.status,
+			Timeout:   timeout,
 		})
 		if err != nil {
 			return nil, err
@@ -77,11 +124,13 @@ func NewScheduler(
 // and publish them to nats job queue for gather.
 func (s *Scheduler) Run(ctx context.Context) error {
 	go func(s *Scheduler, ctx context.Context) {
+		ticker := time.NewTicker(s.tick())
+		defer ticker.Stop()
 		for {
 			select {
 			case <-ctx.Done():
 				return
-			case <-time.After(s.Interval): // TODO: change to ticker because of garbage collection
+			case <-ticker.C:
 				s.gather <- struct{}{}
 			}
 		}
@@ -89,6 +138,14 @@ func (s *Scheduler) Run(ctx context.Context) error {
 	return s.run(ctx)
 }
 
+// tick is the time between checks for targets due a scrape.
+func (s *Scheduler) tick() time.Duration {
+	if s.Interval < resolution {
+		return s.Interval
+	}
+	return resolution
+}
+
 func (s *Scheduler) run(ctx context.Context) error {
 	for {
 		select {
@@ -102,7 +159,8 @@ func (s *Scheduler) run(ctx context.Context) error {
 				s.Logger.Error("cannot list targets", zap.Error(err))
 				continue
 			}
-			for _, target := range targets {
+			targets = append(targets, s.discover(ctx)...)
+			for _, target := range s.due(targets) {
 				if err := requestScrape(target, s.Publisher); err != nil {
 					s.Logger.Error("json encoding error", zap.Error(err))
 				}
@@ -111,15 +169,64 @@ func (s *Scheduler) run(ctx context.Context) error {
 	}
 }
 
+// discover returns the targets found by the discoverers of s.
+func (s *Scheduler) discover(ctx context.Context) []influxdb.ScraperTarget {
+	var targets []influxdb.ScraperTarget
+	for _, d := range s.discoverers {
+		ts, err := d.Discover(ctx)
+		if err != nil {
+			s.Logger.Error("cannot discover targets", zap.Error(err))
+		}
+		targets = append(targets, ts...)
+	}
+	return targets
+}
+
+// due returns the targets due a scrape, and schedules their next scrape.
+func (s *Scheduler) due(targets []influxdb.ScraperTarget) []influxdb.ScraperTarget {
+	now := s.now()
+	// Targets due within half a tick are scraped now rather than a tick late.
+	slack := s.tick() / 2
+
+	next := make(map[string]time.Time, len(targets))
+	due := make([]influxdb.ScraperTarget, 0, len(targets))
+	for _, target := range targets {
+		key := scheduleKey(target)
+		t, ok := s.next[key]
+		if ok && t.Sub(now) > slack {
+			next[key] = t
+			continue
+		}
+
+		interval := s.Interval
+		if target.Interval > 0 {
+			interval = time.Duration(target.Interval) * time.Millisecond
+		}
+		next[key] = now.Add(interval)
+		due = append(due, target)
+	}
+	// Targets removed since the last check are forgotten.
+	s.next = next
+	return due
+}
+
+// scheduleKey identifies a target across checks. Discovered targets have no
+// ID, and are identified by where they are scraped from and written to.
+func scheduleKey(t influxdb.ScraperTarget) string {
+	if t.ID.Valid() {
+		return t.ID.String()
+	}
+	return strings.Join([]string{t.URL, t.OrgID.String(), t.BucketID.String()}, " ")
+}
+
 func requestScrape(t influxdb.ScraperTarget, publisher nats.Publisher) error {
 	buf := new(bytes.Buffer)
 	err := json.NewEncoder(buf).Encode(t)
 	if err != nil {
 		return err
 	}
-	switch t.Type {
-	case influxdb.PrometheusScraperType:
-		return publisher.Publish(promTargetSubject, buf)
+	if !influxdb.ValidScraperType(string(t.Type)) {
+		return fmt.Errorf("unsupported target scrape type: %s", t.Type)
 	}
-	return fmt.Errorf("unsupported target scrape type: %s", t.Type)
+	return publisher.Publish(targetSubject, buf)
 }
@@ -48,7 +48,7 @@ func TestScheduler(t *testing.T) {
 	})
 
 	scheduler, err := NewScheduler(10, logger,
-		storage, publisher, subscriber, time.Millisecond, time.Microsecond)
+		storage, publisher, subscriber, time.Millisecond, time.Second)
 
 	go func() {
 		err = scheduler.run(ctx)
@@ -92,6 +92,46 @@ func TestScheduler(t *testing.T) {
 	ts.Close()
 }
 
+func TestScheduler_due(t *testing.T) {
+	s := &Scheduler{
+		Interval: 10 * time.Second,
+		next:     make(map[string]time.Time),
+	}
+	now := time.Unix(0, 0)
+	s.now = func() time.Time { return now }
+
+	targets := []influxdb.ScraperTarget{
+		{ID: 1, URL: "http://a:9100/metrics"},
+		{ID: 2, URL: "http://b:9100/metrics", Interval: 3000},
+		// Discovered targets have no ID.
+		{URL: "http://c:9100/metrics", Interval: 5000},
+	}
+	urls := func(ts []influxdb.ScraperTarget) []string {
+		us := []string{}
+		for _, t := range ts {
+			us = append(us, t.URL)
+		}
+		return us
+	}
+
+	a, b, c := targets[0].URL, targets[1].URL, targets[2].URL
+	want := map[time.Duration][]string{
+		0:                {a, b, c},
+		time.Second:      {},
+		3 * time.Second:  {b},
+		5 * time.Second:  {c},
+		6 * time.Second:  {b},
+		9 * time.Second:  {b},
+		10 * time.Second: {a, c},
+	}
+	for _, d := range []time.Duration{0, time.Second, 3 * time.Second, 5 * time.Second, 6 * time.Second, 9 * time.Second, 10 * time.Second} {
+		now = time.Unix(0, 0).Add(d)
+		if diff := cmp.Diff(urls(s.due(targets)), want[d]); diff != "" {
+			t.Fatalf("targets due after %s are different -got/+want\ndiff %s", d, diff)
+		}
+	}
+}
+
 const sampleRespSmall = `
 # HELP go_goroutines Number of goroutines that currently exist.
 # TYPE go_goroutines gauge
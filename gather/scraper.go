@@ -2,6 +2,7 @@ package gather
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/influxdata/influxdb"
 )
@@ -10,3 +11,53 @@ import (
 type Scraper interface {
 	Gather(ctx context.Context, target influxdb.ScraperTarget) (collected MetricsCollection, err error)
 }
+
+// scrapers gathers metrics with the scraper of the type of each target.
+type scrapers map[influxdb.ScraperType]Scraper
+
+// newScrapers returns the scrapers of all the scraper types, loading the
+// credentials of targets from secrets.
+func newScrapers(secrets influxdb.SecretService) scrapers {
+	f := fetcher{Secrets: secrets}
+	return scrapers{
+		influxdb.PrometheusScraperType:   &prometheusScraper{fetcher: f},
+		influxdb.OpenMetricsScraperType:  &openMetricsScraper{fetcher: f},
+		influxdb.LineProtocolScraperType: &lineProtocolScraper{fetcher: f},
+		influxdb.JSONScraperType:         &jsonScraper{fetcher: f},
+	}
+}
+
+// Gather gathers the metrics of target with the scraper of its type.
+func (s scrapers) Gather(ctx context.Context, target influxdb.ScraperTarget) (MetricsCollection, error) {
+	scraper, ok := s[target.Type]
+	if !ok {
+		return MetricsCollection{}, fmt.Errorf("unsupported target scrape type: %s", target.Type)
+	}
+	return scraper.Gather(ctx, target)
+}
+
+// collect returns the metrics ms scraped from target, with the tags of the
+// target added, filtered and relabeled as configured by the target.
+func collect(target influxdb.ScraperTarget, ms []Metrics) (MetricsCollection, error) {
+	f, err := newFilter(target)
+	if err != nil {
+		return MetricsCollection{}, err
+	}
+
+	if len(target.Tags) > 0 {
+		for i := range ms {
+			if ms[i].Tags == nil {
+				ms[i].Tags = make(map[string]string, len(target.Tags))
+			}
+			for k, v := range target.Tags {
+				ms[i].Tags[k] = v
+			}
+		}
+	}
+
+	return MetricsCollection{
+		MetricsSlice: f.apply(ms),
+		OrgID:        target.OrgID,
+		BucketID:     target.BucketID,
+	}, nil
+}
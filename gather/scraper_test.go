@@ -11,6 +11,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
 )
 
 var (
@@ -155,6 +156,46 @@ func TestPrometheusScraper(t *testing.T) {
 	}
 }
 
+func TestPrometheusScraper_Auth(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.Write([]byte(sampleRespSmall))
+	}))
+	defer ts.Close()
+
+	secrets := mock.NewSecretService()
+	secrets.LoadSecretFn = func(ctx context.Context, id influxdb.ID, k string) (string, error) {
+		if id != *orgID || k != "node-token" {
+			return "", &influxdb.Error{Code: influxdb.ENotFound, Msg: influxdb.ErrSecretNotFound}
+		}
+		return "secret-token", nil
+	}
+	scraper := &prometheusScraper{fetcher{Secrets: secrets}}
+
+	target := influxdb.ScraperTarget{
+		URL:      ts.URL + "/metrics",
+		OrgID:    *orgID,
+		BucketID: *bucketID,
+		TLS:      &influxdb.ScraperTLS{InsecureSkipVerify: true},
+	}
+	if _, err := scraper.Gather(context.Background(), target); err == nil {
+		t.Fatal("expected scrapes without credentials to fail")
+	}
+
+	target.Auth = &influxdb.ScraperAuth{Type: influxdb.ScraperAuthBearer, TokenSecret: "node-token"}
+	results, err := scraper.Gather(context.Background(), target)
+	if err != nil {
+		t.Fatalf("unable to scrape with bearer auth: %v", err)
+	}
+	if len(results.MetricsSlice) != 1 || results.MetricsSlice[0].Name != "go_goroutines" {
+		t.Fatalf("unexpected metrics %v", results.MetricsSlice)
+	}
+}
+
 const sampleResp = `
 # 	HELP go_gc_duration_seconds A summary of the GC invocation durations.
 # TYPE go_gc_duration_seconds summary
@@ -208,7 +249,7 @@ func (s *mockStorage) Record(collected MetricsCollection) error {
 	return nil
 }
 
-func (s *mockStorage) ListTargets(ctx context.Context) (targets []influxdb.ScraperTarget, err error) {
+func (s *mockStorage) ListTargets(ctx context.Context, opt ...influxdb.FindOptions) (targets []influxdb.ScraperTarget, err error) {
 	s.RLock()
 	defer s.RUnlock()
 	if s.Targets == nil {
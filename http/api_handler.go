@@ -7,8 +7,10 @@ import (
 	influxdb "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/authorizer"
 	"github.com/influxdata/influxdb/chronograf/server"
+	"github.com/influxdata/influxdb/macro"
 	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/template"
 	"go.uber.org/zap"
 )
 
@@ -28,10 +30,13 @@ type APIHandler struct {
 	TaskHandler          *TaskHandler
 	TelegrafHandler      *TelegrafHandler
 	QueryHandler         *FluxHandler
+	RunningQueryHandler  *RunningQueryHandler
 	ProtoHandler         *ProtoHandler
 	WriteHandler         *WriteHandler
 	SetupHandler         *SetupHandler
 	SessionHandler       *SessionHandler
+	ShareHandler         *ShareHandler
+	TemplateHandler      *TemplateHandler
 	SwaggerHandler       http.HandlerFunc
 }
 
@@ -52,8 +57,10 @@ type APIBackend struct {
 	OrganizationService             influxdb.OrganizationService
 	UserResourceMappingService      influxdb.UserResourceMappingService
 	LabelService                    influxdb.LabelService
+	LabelResourceService            influxdb.LabelResourceService
 	DashboardService                influxdb.DashboardService
 	DashboardOperationLogService    influxdb.DashboardOperationLogService
+	DashboardVersionService         influxdb.DashboardVersionService
 	BucketOperationLogService       influxdb.BucketOperationLogService
 	UserOperationLogService         influxdb.UserOperationLogService
 	OrganizationOperationLogService influxdb.OrganizationOperationLogService
@@ -62,10 +69,13 @@ type APIBackend struct {
 	BasicAuthService                influxdb.BasicAuthService
 	OnboardingService               influxdb.OnboardingService
 	ProxyQueryService               query.ProxyQueryService
+	RunningQueryService             influxdb.RunningQueryService
 	TaskService                     influxdb.TaskService
 	TelegrafService                 influxdb.TelegrafConfigStore
+	TelegrafAgentService            influxdb.TelegrafAgentService
 	ScraperTargetStoreService       influxdb.ScraperTargetStoreService
 	SecretService                   influxdb.SecretService
+	ShareService                    influxdb.ShareService
 	LookupService                   influxdb.LookupService
 	ChronografService               *server.Service
 	ProtoService                    influxdb.ProtoService
@@ -84,7 +94,7 @@ func NewAPIHandler(b *APIBackend) *APIHandler {
 	h.SessionHandler = NewSessionHandler(sessionBackend)
 
 	bucketBackend := NewBucketBackend(b)
-	bucketBackend.BucketService = authorizer.NewBucketService(b.BucketService)
+	bucketBackend.BucketService = authorizer.NewBucketService(b.BucketService, b.ShareService)
 	h.BucketHandler = NewBucketHandler(bucketBackend)
 
 	orgBackend := NewOrgBackend(b)
@@ -96,11 +106,13 @@ func NewAPIHandler(b *APIBackend) *APIHandler {
 	h.UserHandler = NewUserHandler(userBackend)
 
 	dashboardBackend := NewDashboardBackend(b)
-	dashboardBackend.DashboardService = authorizer.NewDashboardService(b.DashboardService)
-	h.DashboardHandler = NewDashboardHandler(dashboardBackend)
+	dashboardBackend.DashboardService = authorizer.NewDashboardService(b.DashboardService, b.ShareService)
+	dashboardBackend.DashboardVersionService = authorizer.NewDashboardVersionService(b.DashboardVersionService, b.DashboardService, b.ShareService)
 
 	macroBackend := NewMacroBackend(b)
 	macroBackend.MacroService = authorizer.NewMacroService(b.MacroService)
+	macroValuesService := macro.NewValuesService(macroBackend.MacroService, b.ProxyQueryService)
+	macroBackend.MacroValuesService = macroValuesService
 	h.MacroHandler = NewMacroHandler(macroBackend)
 
 	authorizationBackend := NewAuthorizationBackend(b)
@@ -132,8 +144,13 @@ func NewAPIHandler(b *APIBackend) *APIHandler {
 	h.WriteHandler = NewWriteHandler(writeBackend)
 
 	fluxBackend := NewFluxBackend(b)
+	fluxBackend.MacroBindingService = macroValuesService
 	h.QueryHandler = NewFluxHandler(fluxBackend)
 
+	runningQueryBackend := NewRunningQueryBackend(b)
+	runningQueryBackend.RunningQueryService = authorizer.NewRunningQueryService(b.RunningQueryService)
+	h.RunningQueryHandler = NewRunningQueryHandler(runningQueryBackend)
+
 	h.ProtoHandler = NewProtoHandler(NewProtoBackend(b))
 
 	h.ChronografHandler = NewChronografHandler(b.ChronografService)
@@ -142,6 +159,27 @@ func NewAPIHandler(b *APIBackend) *APIHandler {
 
 	h.LabelHandler = NewLabelHandler(b.LabelService)
 
+	shareBackend := NewShareBackend(b)
+	shareBackend.ShareService = authorizer.NewShareService(b.OrgLookupService, b.ShareService)
+	h.ShareHandler = NewShareHandler(shareBackend)
+
+	templateService := &template.Service{
+		BucketService:             bucketBackend.BucketService,
+		LabelService:              b.LabelService,
+		MacroService:              macroBackend.MacroService,
+		DashboardService:          dashboardBackend.DashboardService,
+		TaskService:               b.TaskService,
+		TelegrafService:           telegrafBackend.TelegrafService,
+		ScraperTargetStoreService: scraperBackend.ScraperStorageService,
+	}
+
+	dashboardBackend.DashboardExportService = templateService
+	h.DashboardHandler = NewDashboardHandler(dashboardBackend)
+
+	templateBackend := NewTemplateBackend(b)
+	templateBackend.TemplateService = templateService
+	h.TemplateHandler = NewTemplateHandler(templateBackend)
+
 	return h
 }
 
@@ -154,11 +192,12 @@ var apiLinks = map[string]interface{}{
 	"external": map[string]string{
 		"statusFeed": "https://www.influxdata.com/feed/json",
 	},
-	"labels": "/api/v2/labels",
-	"macros": "/api/v2/macros",
-	"me":     "/api/v2/me",
-	"orgs":   "/api/v2/orgs",
-	"protos": "/api/v2/protos",
+	"labels":  "/api/v2/labels",
+	"macros":  "/api/v2/macros",
+	"me":      "/api/v2/me",
+	"orgs":    "/api/v2/orgs",
+	"protos":  "/api/v2/protos",
+	"queries": "/api/v2/queries",
 	"query": map[string]string{
 		"self":        "/api/v2/query",
 		"ast":         "/api/v2/query/ast",
@@ -167,6 +206,7 @@ var apiLinks = map[string]interface{}{
 		"suggestions": "/api/v2/query/suggestions",
 	},
 	"setup":    "/api/v2/setup",
+	"shares":   "/api/v2/shares",
 	"signin":   "/api/v2/signin",
 	"signout":  "/api/v2/signout",
 	"sources":  "/api/v2/sources",
@@ -179,8 +219,12 @@ var apiLinks = map[string]interface{}{
 	},
 	"tasks":     "/api/v2/tasks",
 	"telegrafs": "/api/v2/telegrafs",
-	"users":     "/api/v2/users",
-	"write":     "/api/v2/write",
+	"templates": map[string]string{
+		"export": "/api/v2/templates/export",
+		"apply":  "/api/v2/templates/apply",
+	},
+	"users": "/api/v2/users",
+	"write": "/api/v2/write",
 }
 
 func (h *APIHandler) serveLinks(w http.ResponseWriter, r *http.Request) {
@@ -224,6 +268,11 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if strings.HasPrefix(r.URL.Path, "/api/v2/queries") {
+		h.RunningQueryHandler.ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/api/v2/buckets") {
 		h.BucketHandler.ServeHTTP(w, r)
 		return
@@ -254,6 +303,11 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if strings.HasPrefix(r.URL.Path, "/api/v2/shares") {
+		h.ShareHandler.ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/api/v2/dashboards") {
 		h.DashboardHandler.ServeHTTP(w, r)
 		return
@@ -279,6 +333,11 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if strings.HasPrefix(r.URL.Path, "/api/v2/templates") {
+		h.TemplateHandler.ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/api/v2/macros") {
 		h.MacroHandler.ServeHTTP(w, r)
 		return
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"path"
 	"time"
 
@@ -276,8 +277,12 @@ func newBucketsResponse(ctx context.Context, opts influxdb.FindOptions, f influx
 		labels, _ := labelService.FindResourceLabels(ctx, influxdb.LabelMappingFilter{ResourceID: b.ID})
 		rs = append(rs, newBucketResponse(b, labels))
 	}
+	var last influxdb.Pageable
+	if len(bs) > 0 {
+		last = bs[len(bs)-1]
+	}
 	return &bucketsResponse{
-		Links:   newPagingLinks(bucketsPath, opts, f, len(bs)),
+		Links:   newPagingLinks(bucketsPath, opts, f, len(bs), last),
 		Buckets: rs,
 	}
 }
@@ -500,6 +505,10 @@ func decodeGetBucketsRequest(ctx context.Context, r *http.Request) (*getBucketsR
 		req.filter.ID = id
 	}
 
+	if req.filter.Labels, err = decodeLabelSelector(qp); err != nil {
+		return nil, err
+	}
+
 	return req, nil
 }
 
@@ -637,7 +646,7 @@ func (s *BucketService) FindBucket(ctx context.Context, filter influxdb.BucketFi
 }
 
 // FindBuckets returns a list of buckets that match filter and the total count of matching buckets.
-// Additional options provide pagination & sorting.
+// Additional options provide pagination & sorting. Without a limit every page is fetched.
 func (s *BucketService) FindBuckets(ctx context.Context, filter influxdb.BucketFilter, opt ...influxdb.FindOptions) ([]*influxdb.Bucket, int, error) {
 	u, err := newURL(s.Addr, bucketPath)
 	if err != nil {
@@ -657,50 +666,70 @@ func (s *BucketService) FindBuckets(ctx context.Context, filter influxdb.BucketF
 	if filter.Name != nil {
 		query.Add("name", *filter.Name)
 	}
+	encodeLabelSelector(query, filter.Labels)
 
+	paginate := true
 	if len(opt) > 0 {
 		for k, vs := range opt[0].QueryParams() {
 			for _, v := range vs {
 				query.Add(k, v)
 			}
 		}
+		paginate = opt[0].Limit == 0
 	}
+	u.RawQuery = query.Encode()
+
+	buckets := []*influxdb.Bucket{}
+	for u != nil {
+		bs, err := s.findBuckets(u)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		for _, b := range bs.Buckets {
+			pb, err := b.bucket.toInfluxDB()
+			if err != nil {
+				return nil, 0, err
+			}
 
+			buckets = append(buckets, pb)
+		}
+
+		if !paginate {
+			break
+		}
+		if u, err = nextPageURL(u, bs.Links); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return buckets, len(buckets), nil
+}
+
+// findBuckets fetches the single page of buckets at u.
+func (s *BucketService) findBuckets(u *url.URL) (*bucketsResponse, error) {
 	req, err := http.NewRequest("GET", u.String(), nil)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
-
-	req.URL.RawQuery = query.Encode()
 	SetToken(s.Token, req)
 
 	hc := newClient(u.Scheme, s.InsecureSkipVerify)
 	resp, err := hc.Do(req)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if err := CheckError(resp); err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
 	var bs bucketsResponse
 	if err := json.NewDecoder(resp.Body).Decode(&bs); err != nil {
-		return nil, 0, err
-	}
-
-	buckets := make([]*influxdb.Bucket, 0, len(bs.Buckets))
-	for _, b := range bs.Buckets {
-		pb, err := b.bucket.toInfluxDB()
-		if err != nil {
-			return nil, 0, err
-		}
-
-		buckets = append(buckets, pb)
+		return nil, err
 	}
-
-	return buckets, len(buckets), nil
+	return &bs, nil
 }
 
 // CreateBucket creates a new bucket and sets b.ID with the new identifier.
@@ -101,7 +101,7 @@ func TestService_handleGetBuckets(t *testing.T) {
 {
   "links": {
     "self": "/api/v2/buckets?descending=false&limit=1&offset=0",
-    "next": "/api/v2/buckets?descending=false&limit=1&offset=1"
+    "next": "/api/v2/buckets?after=eyJpZCI6ImMwMTc1ZjAwNzdhNzcwMDUifQ&descending=false&limit=1"
   },
   "buckets": [
     {
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"path"
 
 	platform "github.com/influxdata/influxdb"
@@ -21,9 +22,14 @@ type DashboardBackend struct {
 
 	DashboardService             platform.DashboardService
 	DashboardOperationLogService platform.DashboardOperationLogService
+	DashboardVersionService      platform.DashboardVersionService
 	UserResourceMappingService   platform.UserResourceMappingService
 	LabelService                 platform.LabelService
 	UserService                  platform.UserService
+
+	// DashboardExportService is left to the caller, since it is built from
+	// the authorized services of the other backends.
+	DashboardExportService platform.DashboardExportService
 }
 
 func NewDashboardBackend(b *APIBackend) *DashboardBackend {
@@ -32,6 +38,7 @@ func NewDashboardBackend(b *APIBackend) *DashboardBackend {
 
 		DashboardService:             b.DashboardService,
 		DashboardOperationLogService: b.DashboardOperationLogService,
+		DashboardVersionService:      b.DashboardVersionService,
 		UserResourceMappingService:   b.UserResourceMappingService,
 		LabelService:                 b.LabelService,
 		UserService:                  b.UserService,
@@ -46,9 +53,11 @@ type DashboardHandler struct {
 
 	DashboardService             platform.DashboardService
 	DashboardOperationLogService platform.DashboardOperationLogService
+	DashboardVersionService      platform.DashboardVersionService
 	UserResourceMappingService   platform.UserResourceMappingService
 	LabelService                 platform.LabelService
 	UserService                  platform.UserService
+	DashboardExportService       platform.DashboardExportService
 }
 
 const (
@@ -64,6 +73,8 @@ const (
 	dashboardsIDOwnersIDPath    = "/api/v2/dashboards/:id/owners/:userID"
 	dashboardsIDLabelsPath      = "/api/v2/dashboards/:id/labels"
 	dashboardsIDLabelsIDPath    = "/api/v2/dashboards/:id/labels/:lid"
+	dashboardsIDExportPath      = "/api/v2/dashboards/:id/export"
+	dashboardsImportPath        = "/api/v2/dashboards/import"
 )
 
 // NewDashboardHandler returns a new instance of DashboardHandler.
@@ -74,9 +85,11 @@ func NewDashboardHandler(b *DashboardBackend) *DashboardHandler {
 
 		DashboardService:             b.DashboardService,
 		DashboardOperationLogService: b.DashboardOperationLogService,
+		DashboardVersionService:      b.DashboardVersionService,
 		UserResourceMappingService:   b.UserResourceMappingService,
 		LabelService:                 b.LabelService,
 		UserService:                  b.UserService,
+		DashboardExportService:       b.DashboardExportService,
 	}
 
 	h.HandlerFunc("POST", dashboardsPath, h.handlePostDashboard)
@@ -85,6 +98,12 @@ func NewDashboardHandler(b *DashboardBackend) *DashboardHandler {
 	h.HandlerFunc("GET", dashboardsIDLogPath, h.handleGetDashboardLog)
 	h.HandlerFunc("DELETE", dashboardsIDPath, h.handleDeleteDashboard)
 	h.HandlerFunc("PATCH", dashboardsIDPath, h.handlePatchDashboard)
+	h.HandlerFunc("GET", dashboardsIDExportPath, h.handleExportDashboard)
+
+	h.HandlerFunc("GET", dashboardsIDVersionsPath, h.handleGetDashboardVersions)
+	h.HandlerFunc("GET", dashboardsIDVersionsIDPath, h.handleGetDashboardVersion)
+	h.HandlerFunc("GET", dashboardsIDVersionsIDDiffPath, h.handleGetDashboardVersionDiff)
+	h.HandlerFunc("POST", dashboardsIDVersionsIDRestorePath, h.handlePostDashboardVersionRestore)
 
 	h.HandlerFunc("PUT", dashboardsIDCellsPath, h.handlePutDashboardCells)
 	h.HandlerFunc("POST", dashboardsIDCellsPath, h.handlePostDashboardCell)
@@ -129,6 +148,16 @@ func NewDashboardHandler(b *DashboardBackend) *DashboardHandler {
 	return h
 }
 
+// ServeHTTP serves dashboard imports itself, since the router cannot hold the
+// import path next to the dashboard ID routes.
+func (h *DashboardHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == "POST" && r.URL.Path == dashboardsImportPath {
+		h.handleImportDashboard(w, r)
+		return
+	}
+	h.Router.ServeHTTP(w, r)
+}
+
 type dashboardLinks struct {
 	Self         string `json:"self"`
 	Members      string `json:"members"`
@@ -355,6 +384,10 @@ func decodeGetDashboardsRequest(ctx context.Context, r *http.Request) (*getDashb
 		req.filter.Organization = &org
 	}
 
+	if req.filter.Labels, err = decodeLabelSelector(qp); err != nil {
+		return nil, err
+	}
+
 	return req, nil
 }
 
@@ -372,8 +405,12 @@ func (d getDashboardsResponse) toPlatform() []*platform.Dashboard {
 }
 
 func newGetDashboardsResponse(ctx context.Context, dashboards []*platform.Dashboard, filter platform.DashboardFilter, opts platform.FindOptions, labelService platform.LabelService) getDashboardsResponse {
+	var last platform.Pageable
+	if len(dashboards) > 0 {
+		last = dashboards[len(dashboards)-1]
+	}
 	res := getDashboardsResponse{
-		Links:      newPagingLinks(dashboardsPath, opts, filter, len(dashboards)),
+		Links:      newPagingLinks(dashboardsPath, opts, filter, len(dashboards), last),
 		Dashboards: make([]dashboardResponse, 0, len(dashboards)),
 	}
 
@@ -477,6 +514,93 @@ func decodeGetDashboardRequest(ctx context.Context, r *http.Request) (*getDashbo
 	}, nil
 }
 
+// handleExportDashboard returns a dashboard with the views of its cells and
+// the macros and labels they refer to.
+func (h *DashboardHandler) handleExportDashboard(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeGetDashboardRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	e, err := h.DashboardExportService.ExportDashboard(ctx, req.DashboardID)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, e); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+type importDashboardRequest struct {
+	OrgID  platform.ID
+	Export *platform.DashboardExport
+}
+
+func decodeImportDashboardRequest(ctx context.Context, r *http.Request) (*importDashboardRequest, error) {
+	orgID := r.URL.Query().Get("orgID")
+	if orgID == "" {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "orgID is required",
+		}
+	}
+
+	req := &importDashboardRequest{
+		Export: &platform.DashboardExport{},
+	}
+	if err := req.OrgID.DecodeFromString(orgID); err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "invalid orgID",
+			Err:  err,
+		}
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(req.Export); err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "invalid dashboard export",
+			Err:  err,
+		}
+	}
+
+	return req, nil
+}
+
+// handleImportDashboard creates a dashboard in an organization from an export.
+func (h *DashboardHandler) handleImportDashboard(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeImportDashboardRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	d, err := h.DashboardExportService.ImportDashboard(ctx, req.OrgID, req.Export)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	labels, err := h.LabelService.FindResourceLabels(ctx, platform.LabelMappingFilter{ResourceID: d.ID})
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newDashboardResponse(d, labels)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
 // hanldeGetDashboardLog retrieves a dashboard log by the dashboards ID.
 func (h *DashboardHandler) handleGetDashboardLog(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -1027,16 +1151,16 @@ func (s *DashboardService) FindDashboardByID(ctx context.Context, id platform.ID
 }
 
 // FindDashboards returns a list of dashboards that match filter and the total count of matching dashboards.
-// Additional options provide pagination & sorting.
+// Additional options provide pagination & sorting. Without a limit every page is fetched.
 func (s *DashboardService) FindDashboards(ctx context.Context, filter platform.DashboardFilter, opts platform.FindOptions) ([]*platform.Dashboard, int, error) {
 	dashboards := []*platform.Dashboard{}
-	url, err := newURL(s.Addr, dashboardsPath)
+	u, err := newURL(s.Addr, dashboardsPath)
 
 	if err != nil {
 		return dashboards, 0, err
 	}
 
-	qp := url.Query()
+	qp := u.Query()
 	for _, id := range filter.IDs {
 		qp.Add("id", id.String())
 	}
@@ -1046,38 +1170,58 @@ func (s *DashboardService) FindDashboards(ctx context.Context, filter platform.D
 	if filter.Organization != nil {
 		qp.Add("org", *filter.Organization)
 	}
+	encodeLabelSelector(qp, filter.Labels)
 	for k, vs := range opts.QueryParams() {
 		for _, v := range vs {
 			qp.Add(k, v)
 		}
 	}
-	url.RawQuery = qp.Encode()
+	u.RawQuery = qp.Encode()
 
-	req, err := http.NewRequest("GET", url.String(), nil)
+	for u != nil {
+		dr, err := s.findDashboards(u)
+		if err != nil {
+			return dashboards, 0, err
+		}
+
+		dashboards = append(dashboards, dr.toPlatform()...)
+
+		if opts.Limit != 0 {
+			break
+		}
+		if u, err = nextPageURL(u, dr.Links); err != nil {
+			return dashboards, 0, err
+		}
+	}
+
+	return dashboards, len(dashboards), nil
+}
+
+// findDashboards fetches the single page of dashboards at u.
+func (s *DashboardService) findDashboards(u *url.URL) (*getDashboardsResponse, error) {
+	req, err := http.NewRequest("GET", u.String(), nil)
 	if err != nil {
-		return dashboards, 0, err
+		return nil, err
 	}
 
 	SetToken(s.Token, req)
-	hc := newClient(url.Scheme, s.InsecureSkipVerify)
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
 
 	resp, err := hc.Do(req)
 	if err != nil {
-		return dashboards, 0, err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if err := CheckError(resp); err != nil {
-		return dashboards, 0, err
+		return nil, err
 	}
 
 	var dr getDashboardsResponse
 	if err := json.NewDecoder(resp.Body).Decode(&dr); err != nil {
-		return dashboards, 0, err
+		return nil, err
 	}
-
-	dashboards = dr.toPlatform()
-	return dashboards, len(dashboards), nil
+	return &dr, nil
 }
 
 // CreateDashboard creates a new dashboard and sets b.ID with the new identifier.
@@ -1425,3 +1569,75 @@ func cellViewPath(dashboardID, cellID platform.ID) string {
 func dashboardCellIDPath(id platform.ID, cellID platform.ID) string {
 	return path.Join(cellPath(id), cellID.String())
 }
+
+// ExportDashboard returns the dashboard id with the views of its cells and the
+// macros and labels they refer to.
+func (s *DashboardService) ExportDashboard(ctx context.Context, id platform.ID) (*platform.DashboardExport, error) {
+	u, err := newURL(s.Addr, path.Join(dashboardIDPath(id), "export"))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var e platform.DashboardExport
+	if err := json.NewDecoder(resp.Body).Decode(&e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// ImportDashboard creates the dashboard of e in the organization orgID.
+func (s *DashboardService) ImportDashboard(ctx context.Context, orgID platform.ID, e *platform.DashboardExport) (*platform.Dashboard, error) {
+	u, err := newURL(s.Addr, dashboardsImportPath)
+	if err != nil {
+		return nil, err
+	}
+	query := u.Query()
+	query.Set("orgID", orgID.String())
+	u.RawQuery = query.Encode()
+
+	octets, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(octets))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var dr dashboardResponse
+	if err := json.NewDecoder(resp.Body).Decode(&dr); err != nil {
+		return nil, err
+	}
+	return dr.toPlatform(), nil
+}
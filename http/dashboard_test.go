@@ -16,6 +16,7 @@ import (
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/inmem"
 	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/template"
 	platformtesting "github.com/influxdata/influxdb/testing"
 	"github.com/julienschmidt/httprouter"
 )
@@ -1289,6 +1290,68 @@ func TestDashboardService(t *testing.T) {
 	platformtesting.DashboardService(initDashboardService, t)
 }
 
+func TestDashboardService_ExportImportDashboard(t *testing.T) {
+	ctx := context.Background()
+	svc := inmem.NewService()
+
+	src, dst := &platform.Organization{Name: "src"}, &platform.Organization{Name: "dst"}
+	for _, o := range []*platform.Organization{src, dst} {
+		if err := svc.CreateOrganization(ctx, o); err != nil {
+			t.Fatal(err)
+		}
+	}
+	d := &platform.Dashboard{OrganizationID: src.ID, Name: "hosts"}
+	if err := svc.CreateDashboard(ctx, d); err != nil {
+		t.Fatal(err)
+	}
+	view := &platform.View{
+		ViewContents: platform.ViewContents{Name: "notes"},
+		Properties:   platform.MarkdownViewProperties{Type: "markdown", Note: "# hosts"},
+	}
+	if err := svc.AddDashboardCell(ctx, d.ID, &platform.Cell{W: 4, H: 4}, platform.AddDashboardCellOptions{View: view}); err != nil {
+		t.Fatal(err)
+	}
+
+	dashboardBackend := NewMockDashboardBackend()
+	dashboardBackend.DashboardService = svc
+	dashboardBackend.LabelService = svc
+	dashboardBackend.DashboardExportService = &template.Service{
+		DashboardService: svc,
+		MacroService:     svc,
+		LabelService:     svc,
+	}
+	server := httptest.NewServer(NewDashboardHandler(dashboardBackend))
+	defer server.Close()
+	client := DashboardService{Addr: server.URL}
+
+	e, err := client.ExportDashboard(ctx, d.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(e.Views) != 1 || e.Dashboard.ID != d.ID {
+		t.Fatalf("unexpected export %+v", e)
+	}
+
+	nd, err := client.ImportDashboard(ctx, dst.ID, e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nd.ID == d.ID || nd.OrganizationID != dst.ID || len(nd.Cells) != 1 {
+		t.Fatalf("unexpected imported dashboard %+v", nd)
+	}
+	v, err := svc.GetDashboardCellView(ctx, nd.ID, nd.Cells[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Name != "notes" || v.Properties.GetType() != "markdown" {
+		t.Fatalf("unexpected imported view %+v", v)
+	}
+
+	if _, err := client.ImportDashboard(ctx, dst.ID, &platform.DashboardExport{}); platform.ErrorCode(err) != platform.EInvalid {
+		t.Fatalf("expected an invalid error, got %v", err)
+	}
+}
+
 func TestService_handlePostDashboardLabel(t *testing.T) {
 	type fields struct {
 		LabelService platform.LabelService
@@ -0,0 +1,315 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	dashboardsIDVersionsPath          = "/api/v2/dashboards/:id/versions"
+	dashboardsIDVersionsIDPath        = "/api/v2/dashboards/:id/versions/:version"
+	dashboardsIDVersionsIDDiffPath    = "/api/v2/dashboards/:id/versions/:version/diff"
+	dashboardsIDVersionsIDRestorePath = "/api/v2/dashboards/:id/versions/:version/restore"
+)
+
+type dashboardVersionResponse struct {
+	Links map[string]string `json:"links"`
+	*platform.DashboardVersion
+}
+
+func newDashboardVersionResponse(id platform.ID, v *platform.DashboardVersion) *dashboardVersionResponse {
+	self := fmt.Sprintf("/api/v2/dashboards/%s/versions/%d", id, v.Version)
+	return &dashboardVersionResponse{
+		Links: map[string]string{
+			"self":    self,
+			"diff":    self + "/diff",
+			"restore": self + "/restore",
+		},
+		DashboardVersion: v,
+	}
+}
+
+type dashboardVersionsResponse struct {
+	Links    map[string]string           `json:"links"`
+	Versions []*dashboardVersionResponse `json:"versions"`
+}
+
+func newDashboardVersionsResponse(id platform.ID, vs []*platform.DashboardVersion) *dashboardVersionsResponse {
+	versions := make([]*dashboardVersionResponse, 0, len(vs))
+	for _, v := range vs {
+		versions = append(versions, newDashboardVersionResponse(id, v))
+	}
+	return &dashboardVersionsResponse{
+		Links: map[string]string{
+			"self": fmt.Sprintf("/api/v2/dashboards/%s/versions", id),
+		},
+		Versions: versions,
+	}
+}
+
+// dashboardDiffResponse holds the changes from a version of a dashboard to
+// another version, or to the current dashboard when To is zero.
+type dashboardDiffResponse struct {
+	From    int                        `json:"from"`
+	To      int                        `json:"to"`
+	Changes []platform.DashboardChange `json:"changes"`
+}
+
+type dashboardVersionRequest struct {
+	DashboardID platform.ID
+	Version     int
+}
+
+func decodeDashboardVersionRequest(ctx context.Context, r *http.Request) (*dashboardVersionRequest, error) {
+	req, err := decodeGetDashboardRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	params := httprouter.ParamsFromContext(ctx)
+	version, err := strconv.Atoi(params.ByName("version"))
+	if err != nil || version <= 0 {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "version must be a positive integer",
+		}
+	}
+
+	return &dashboardVersionRequest{
+		DashboardID: req.DashboardID,
+		Version:     version,
+	}, nil
+}
+
+// handleGetDashboardVersions lists the versions of a dashboard, newest first.
+func (h *DashboardHandler) handleGetDashboardVersions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeGetDashboardLogRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	vs, _, err := h.DashboardVersionService.FindDashboardVersions(ctx, req.DashboardID, req.opts)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newDashboardVersionsResponse(req.DashboardID, vs)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleGetDashboardVersion retrieves a single version of a dashboard.
+func (h *DashboardHandler) handleGetDashboardVersion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeDashboardVersionRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	v, err := h.DashboardVersionService.FindDashboardVersion(ctx, req.DashboardID, req.Version)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newDashboardVersionResponse(req.DashboardID, v)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleGetDashboardVersionDiff returns the changes from a version of a
+// dashboard to the version given by the to parameter, or to the current dashboard.
+func (h *DashboardHandler) handleGetDashboardVersionDiff(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeDashboardVersionRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	resp := dashboardDiffResponse{From: req.Version}
+	if to := r.URL.Query().Get("to"); to != "" {
+		if resp.To, err = strconv.Atoi(to); err != nil || resp.To <= 0 {
+			EncodeError(ctx, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "to must be a positive integer",
+			}, w)
+			return
+		}
+	}
+
+	from, err := h.DashboardVersionService.FindDashboardVersion(ctx, req.DashboardID, req.Version)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	var to *platform.ProtoDashboard
+	if resp.To > 0 {
+		v, err := h.DashboardVersionService.FindDashboardVersion(ctx, req.DashboardID, resp.To)
+		if err != nil {
+			EncodeError(ctx, err, w)
+			return
+		}
+		to = &v.ProtoDashboard
+	} else if to, err = h.currentDashboard(ctx, req.DashboardID); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if resp.Changes, err = platform.DiffDashboards(&from.ProtoDashboard, to); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	if resp.Changes == nil {
+		resp.Changes = []platform.DashboardChange{}
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, resp); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// currentDashboard returns the dashboard id with the views of its cells.
+func (h *DashboardHandler) currentDashboard(ctx context.Context, id platform.ID) (*platform.ProtoDashboard, error) {
+	d, err := h.DashboardService.FindDashboardByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	pd := &platform.ProtoDashboard{
+		Dashboard: *d,
+		Views:     make(map[string]platform.View, len(d.Cells)),
+	}
+	for _, c := range d.Cells {
+		v, err := h.DashboardService.GetDashboardCellView(ctx, d.ID, c.ID)
+		if platform.ErrorCode(err) == platform.ENotFound {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		pd.Views[c.ID.String()] = *v
+	}
+	return pd, nil
+}
+
+// handlePostDashboardVersionRestore returns a dashboard to a version.
+func (h *DashboardHandler) handlePostDashboardVersionRestore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeDashboardVersionRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	d, err := h.DashboardVersionService.RestoreDashboardVersion(ctx, req.DashboardID, req.Version)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	labels, err := h.LabelService.FindResourceLabels(ctx, platform.LabelMappingFilter{ResourceID: d.ID})
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newDashboardResponse(d, labels)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func dashboardVersionPath(id platform.ID, version int) string {
+	return path.Join(dashboardIDPath(id), "versions", strconv.Itoa(version))
+}
+
+// FindDashboardVersions returns the versions of the dashboard id, newest first.
+func (s *DashboardService) FindDashboardVersions(ctx context.Context, id platform.ID, opts platform.FindOptions) ([]*platform.DashboardVersion, int, error) {
+	u, err := newURL(s.Addr, path.Join(dashboardIDPath(id), "versions"))
+	if err != nil {
+		return nil, 0, err
+	}
+	query := u.Query()
+	for k, vs := range opts.QueryParams() {
+		for _, v := range vs {
+			query.Add(k, v)
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	var resp struct {
+		Versions []*platform.DashboardVersion `json:"versions"`
+	}
+	if err := s.doVersionRequest(ctx, "GET", u.String(), &resp); err != nil {
+		return nil, 0, err
+	}
+	return resp.Versions, len(resp.Versions), nil
+}
+
+// FindDashboardVersion returns a single version of the dashboard id.
+func (s *DashboardService) FindDashboardVersion(ctx context.Context, id platform.ID, version int) (*platform.DashboardVersion, error) {
+	u, err := newURL(s.Addr, dashboardVersionPath(id, version))
+	if err != nil {
+		return nil, err
+	}
+
+	var v platform.DashboardVersion
+	if err := s.doVersionRequest(ctx, "GET", u.String(), &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// RestoreDashboardVersion returns the dashboard id to a version.
+func (s *DashboardService) RestoreDashboardVersion(ctx context.Context, id platform.ID, version int) (*platform.Dashboard, error) {
+	u, err := newURL(s.Addr, path.Join(dashboardVersionPath(id, version), "restore"))
+	if err != nil {
+		return nil, err
+	}
+
+	var dr dashboardResponse
+	if err := s.doVersionRequest(ctx, "POST", u.String(), &dr); err != nil {
+		return nil, err
+	}
+	return dr.toPlatform(), nil
+}
+
+func (s *DashboardService) doVersionRequest(ctx context.Context, method, url string, v interface{}) error {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return err
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(req.URL.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return err
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
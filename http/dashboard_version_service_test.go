@@ -0,0 +1,106 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestDashboardHandler_Versions(t *testing.T) {
+	cell := &platform.Cell{ID: 2, W: 4, H: 4}
+	version := &platform.DashboardVersion{
+		Version: 1,
+		ProtoDashboard: platform.ProtoDashboard{
+			Dashboard: platform.Dashboard{ID: 1, Name: "before", Cells: []*platform.Cell{cell}},
+			Views:     map[string]platform.View{},
+		},
+		Change: "Dashboard Cell Removed",
+	}
+
+	versions := mock.NewDashboardVersionService()
+	versions.FindDashboardVersionsF = func(ctx context.Context, id platform.ID, opts platform.FindOptions) ([]*platform.DashboardVersion, int, error) {
+		if opts.Limit != 1 {
+			t.Errorf("expected a limit of 1, got %d", opts.Limit)
+		}
+		return []*platform.DashboardVersion{version}, 1, nil
+	}
+	versions.FindDashboardVersionF = func(ctx context.Context, id platform.ID, v int) (*platform.DashboardVersion, error) {
+		if v != 1 {
+			return nil, &platform.Error{Code: platform.ENotFound, Msg: platform.ErrDashboardVersionNotFound}
+		}
+		return version, nil
+	}
+	versions.RestoreDashboardVersionF = func(ctx context.Context, id platform.ID, v int) (*platform.Dashboard, error) {
+		d := version.Dashboard
+		return &d, nil
+	}
+
+	dashboards := mock.NewDashboardService()
+	dashboards.FindDashboardByIDF = func(ctx context.Context, id platform.ID) (*platform.Dashboard, error) {
+		return &platform.Dashboard{ID: id, Name: "after"}, nil
+	}
+
+	b := NewMockDashboardBackend()
+	b.DashboardService = dashboards
+	b.DashboardVersionService = versions
+	server := httptest.NewServer(NewDashboardHandler(b))
+	defer server.Close()
+	client := DashboardService{Addr: server.URL}
+	ctx := context.Background()
+
+	vs, _, err := client.FindDashboardVersions(ctx, 1, platform.FindOptions{Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vs) != 1 || vs[0].Version != 1 || vs[0].Change != version.Change {
+		t.Fatalf("unexpected versions %+v", vs)
+	}
+
+	if _, err := client.FindDashboardVersion(ctx, 1, 2); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected a missing version to be not found, got %v", err)
+	}
+
+	d, err := client.RestoreDashboardVersion(ctx, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Name != "before" || len(d.Cells) != 1 {
+		t.Fatalf("unexpected restored dashboard %+v", d)
+	}
+
+	resp, err := http.Get(server.URL + "/api/v2/dashboards/0000000000000001/versions/1/diff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status %d", resp.StatusCode)
+	}
+	var diff dashboardDiffResponse
+	if err := json.NewDecoder(resp.Body).Decode(&diff); err != nil {
+		t.Fatal(err)
+	}
+	if diff.From != 1 || diff.To != 0 || len(diff.Changes) != 2 {
+		t.Fatalf("unexpected diff %+v", diff)
+	}
+	if c := diff.Changes[0]; c.Field != "name" || c.From != "before" || c.To != "after" {
+		t.Errorf("unexpected change %+v", c)
+	}
+	if c := diff.Changes[1]; c.Action != platform.DashboardChangeRemoved || c.CellID != cell.ID {
+		t.Errorf("unexpected change %+v", c)
+	}
+
+	resp, err = http.Get(server.URL + "/api/v2/dashboards/0000000000000001/versions/0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected an invalid version to be rejected, got status %d", resp.StatusCode)
+	}
+}
@@ -6,7 +6,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"path"
+	"strings"
 
 	"go.uber.org/zap"
 
@@ -353,6 +355,42 @@ func decodeGetLabelsRequest(ctx context.Context, r *http.Request, rt platform.Re
 	return req, nil
 }
 
+// decodeLabelSelector decodes the labels selected by the query params of a find request.
+// Labels are given by name with repeated or comma separated labels params, and
+// labelMatch chooses whether resources must carry all of them (the default) or any.
+func decodeLabelSelector(qp url.Values) (platform.LabelSelector, error) {
+	var sel platform.LabelSelector
+	for _, v := range qp["labels"] {
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				sel.Names = append(sel.Names, name)
+			}
+		}
+	}
+
+	switch match := qp.Get("labelMatch"); match {
+	case "", "all":
+	case "any":
+		sel.Any = true
+	default:
+		return sel, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  fmt.Sprintf("labelMatch must be all or any, not %q", match),
+		}
+	}
+
+	return sel, nil
+}
+
+// encodeLabelSelector adds the query params selecting the labels of sel to qp.
+func encodeLabelSelector(qp url.Values, sel platform.LabelSelector) {
+	for k, vs := range sel.QueryParams() {
+		for _, v := range vs {
+			qp.Add(k, v)
+		}
+	}
+}
+
 // newPostLabelHandler returns a handler func for a POST to /labels endpoints
 func newPostLabelHandler(b *LabelBackend) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
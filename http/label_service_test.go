@@ -0,0 +1,65 @@
+package http
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+func TestLabelSelector_decode(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    platform.LabelSelector
+		wantErr bool
+	}{
+		{
+			name:  "no labels",
+			query: "",
+		},
+		{
+			name:  "repeated and comma separated labels",
+			query: "labels=prod,iot&labels=eu",
+			want:  platform.LabelSelector{Names: []string{"prod", "iot", "eu"}},
+		},
+		{
+			name:  "any label",
+			query: "labels=prod&labels=iot&labelMatch=any",
+			want:  platform.LabelSelector{Names: []string{"prod", "iot"}, Any: true},
+		},
+		{
+			name:    "unknown match",
+			query:   "labels=prod&labelMatch=some",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qp, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := decodeLabelSelector(qp)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeLabelSelector() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeLabelSelector() = %+v, want %+v", got, tt.want)
+			}
+
+			// Encoding the selector must round trip.
+			if tt.wantErr {
+				return
+			}
+			enc := url.Values{}
+			encodeLabelSelector(enc, got)
+			if again, _ := decodeLabelSelector(enc); !reflect.DeepEqual(again, got) {
+				t.Errorf("encodeLabelSelector() = %v, decodes to %+v", enc, again)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"path"
 
 	platform "github.com/influxdata/influxdb"
@@ -20,8 +21,9 @@ const (
 // MacroBackend is all services and associated parameters required to construct
 // the MacroHandler.
 type MacroBackend struct {
-	Logger       *zap.Logger
-	MacroService platform.MacroService
+	Logger             *zap.Logger
+	MacroService       platform.MacroService
+	MacroValuesService platform.MacroValuesService
 }
 
 func NewMacroBackend(b *APIBackend) *MacroBackend {
@@ -37,7 +39,8 @@ type MacroHandler struct {
 
 	Logger *zap.Logger
 
-	MacroService platform.MacroService
+	MacroService       platform.MacroService
+	MacroValuesService platform.MacroValuesService
 }
 
 // NewMacroHandler creates a new MacroHandler
@@ -46,7 +49,8 @@ func NewMacroHandler(b *MacroBackend) *MacroHandler {
 		Router: NewRouter(),
 		Logger: b.Logger,
 
-		MacroService: b.MacroService,
+		MacroService:       b.MacroService,
+		MacroValuesService: b.MacroValuesService,
 	}
 
 	entityPath := fmt.Sprintf("%s/:id", macroPath)
@@ -57,6 +61,7 @@ func NewMacroHandler(b *MacroBackend) *MacroHandler {
 	h.HandlerFunc("PATCH", entityPath, h.handlePatchMacro)
 	h.HandlerFunc("PUT", entityPath, h.handlePutMacro)
 	h.HandlerFunc("DELETE", entityPath, h.handleDeleteMacro)
+	h.HandlerFunc("GET", entityPath+"/values", h.handleGetMacroValues)
 
 	return h
 }
@@ -76,9 +81,13 @@ func (r getMacrosResponse) ToPlatform() []*platform.Macro {
 
 func newGetMacrosResponse(macros []*platform.Macro, f platform.MacroFilter, opts platform.FindOptions) getMacrosResponse {
 	num := len(macros)
+	var last platform.Pageable
+	if num > 0 {
+		last = macros[num-1]
+	}
 	resp := getMacrosResponse{
 		Macros: make([]macroResponse, 0, num),
-		Links:  newPagingLinks(macroPath, opts, f, num),
+		Links:  newPagingLinks(macroPath, opts, f, num, last),
 	}
 
 	for _, macro := range macros {
@@ -116,6 +125,10 @@ func decodeGetMacrosRequest(ctx context.Context, r *http.Request) (*getMacrosReq
 		req.filter.Organization = &org
 	}
 
+	if req.filter.Labels, err = decodeLabelSelector(qp); err != nil {
+		return nil, err
+	}
+
 	return req, nil
 }
 
@@ -188,6 +201,44 @@ func (h *MacroHandler) handleGetMacro(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+type macroValuesResponse struct {
+	Values []string `json:"values"`
+	Links  struct {
+		Self  string `json:"self"`
+		Macro string `json:"macro"`
+	} `json:"links"`
+}
+
+func newMacroValuesResponse(id platform.ID, values []string) macroValuesResponse {
+	resp := macroValuesResponse{Values: values}
+	resp.Links.Self = fmt.Sprintf("/api/v2/macros/%s/values", id)
+	resp.Links.Macro = fmt.Sprintf("/api/v2/macros/%s", id)
+	return resp
+}
+
+// handleGetMacroValues is the HTTP handler for the GET /api/v2/macros/:id/values route.
+func (h *MacroHandler) handleGetMacroValues(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := requestMacroID(ctx)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	values, err := h.MacroValuesService.FindMacroValues(ctx, id)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	err = encodeResponse(ctx, w, http.StatusOK, newMacroValuesResponse(id, values))
+	if err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
 type macroLinks struct {
 	Self string `json:"self"`
 	Org  string `json:"org"`
@@ -442,14 +493,14 @@ func (s *MacroService) FindMacroByID(ctx context.Context, id platform.ID) (*plat
 
 // FindMacros returns a list of macros that match filter.
 //
-// Additional options provide pagination & sorting.
+// Additional options provide pagination & sorting. Without a limit every page is fetched.
 func (s *MacroService) FindMacros(ctx context.Context, filter platform.MacroFilter, opts ...platform.FindOptions) ([]*platform.Macro, error) {
-	url, err := newURL(s.Addr, macroPath)
+	u, err := newURL(s.Addr, macroPath)
 	if err != nil {
 		return nil, err
 	}
 
-	query := url.Query()
+	query := u.Query()
 	if filter.OrganizationID != nil {
 		query.Add("orgID", filter.OrganizationID.String())
 	}
@@ -459,15 +510,48 @@ func (s *MacroService) FindMacros(ctx context.Context, filter platform.MacroFilt
 	if filter.ID != nil {
 		query.Add("id", filter.ID.String())
 	}
+	encodeLabelSelector(query, filter.Labels)
 
-	req, err := http.NewRequest("GET", url.String(), nil)
+	paginate := true
+	if len(opts) > 0 {
+		for k, vs := range opts[0].QueryParams() {
+			for _, v := range vs {
+				query.Add(k, v)
+			}
+		}
+		paginate = opts[0].Limit == 0
+	}
+	u.RawQuery = query.Encode()
+
+	macros := []*platform.Macro{}
+	for u != nil {
+		ms, err := s.findMacros(u)
+		if err != nil {
+			return nil, err
+		}
+
+		macros = append(macros, ms.ToPlatform()...)
+
+		if !paginate {
+			break
+		}
+		if u, err = nextPageURL(u, ms.Links); err != nil {
+			return nil, err
+		}
+	}
+
+	return macros, nil
+}
+
+// findMacros fetches the single page of macros at u.
+func (s *MacroService) findMacros(u *url.URL) (*getMacrosResponse, error) {
+	req, err := http.NewRequest("GET", u.String(), nil)
 	if err != nil {
 		return nil, err
 	}
-	req.URL.RawQuery = query.Encode()
 	SetToken(s.Token, req)
 
-	hc := newClient(url.Scheme, s.InsecureSkipVerify)
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
 
 	resp, err := hc.Do(req)
 	if err != nil {
@@ -483,9 +567,7 @@ func (s *MacroService) FindMacros(ctx context.Context, filter platform.MacroFilt
 	if err := json.NewDecoder(resp.Body).Decode(&ms); err != nil {
 		return nil, err
 	}
-
-	macros := ms.ToPlatform()
-	return macros, nil
+	return &ms, nil
 }
 
 // CreateMacro creates a new macro and assigns it an platform.ID
@@ -632,6 +714,38 @@ func (s *MacroService) DeleteMacro(ctx context.Context, id platform.ID) error {
 	return CheckError(resp)
 }
 
+// FindMacroValues returns the values the macro id expands to.
+func (s *MacroService) FindMacroValues(ctx context.Context, id platform.ID) ([]string, error) {
+	url, err := newURL(s.Addr, path.Join(macroIDPath(id), "values"))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", url.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	SetToken(s.Token, req)
+	hc := newClient(url.Scheme, s.InsecureSkipVerify)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var vr macroValuesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&vr); err != nil {
+		return nil, err
+	}
+	return vr.Values, nil
+}
+
 func macroIDPath(id platform.ID) string {
 	return path.Join(macroPath, id.String())
 }
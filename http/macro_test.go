@@ -8,10 +8,12 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/macro"
 	"github.com/influxdata/influxdb/mock"
 	platformtesting "github.com/influxdata/influxdb/testing"
 	"github.com/julienschmidt/httprouter"
@@ -630,3 +632,43 @@ func initMacroService(f platformtesting.MacroFields, t *testing.T) (platform.Mac
 func TestMacroService(t *testing.T) {
 	platformtesting.MacroService(initMacroService, t)
 }
+
+func TestMacroService_FindMacroValues(t *testing.T) {
+	svc := inmem.NewService()
+	ctx := context.Background()
+
+	m := &platform.Macro{
+		OrganizationID: 1,
+		Name:           "region",
+		Selected:       []string{"west"},
+		Arguments: &platform.MacroArguments{
+			Type:   "map",
+			Values: platform.MacroMapValues{"west": "us-west-2", "east": "us-east-1"},
+		},
+	}
+	if err := svc.CreateMacro(ctx, m); err != nil {
+		t.Fatalf("failed to create macro: %v", err)
+	}
+
+	macroBackend := NewMockMacroBackend()
+	macroBackend.MacroService = svc
+	macroBackend.MacroValuesService = macro.NewValuesService(svc, mock.NewProxyQueryService())
+	server := httptest.NewServer(NewMacroHandler(macroBackend))
+	defer server.Close()
+
+	client := MacroService{
+		Addr: server.URL,
+	}
+
+	values, err := client.FindMacroValues(ctx, m.ID)
+	if err != nil {
+		t.Fatalf("failed to find macro values: %v", err)
+	}
+	if want := []string{"east", "west"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("got values %v, want %v", values, want)
+	}
+
+	if _, err := client.FindMacroValues(ctx, platform.ID(1)); platform.ErrorCode(err) != platform.ENotFound {
+		t.Errorf("expected values of a missing macro to be not found, got %v", err)
+	}
+}
@@ -55,12 +55,23 @@ func decodeFindOptions(ctx context.Context, r *http.Request) (*platform.FindOpti
 		opts.Descending = desc
 	}
 
+	if after := qp.Get("after"); after != "" {
+		if _, err := platform.DecodePageCursor(after); err != nil {
+			return nil, err
+		}
+
+		opts.After = after
+		opts.Offset = 0
+	}
+
 	return opts, nil
 }
 
 // newPagingLinks returns a PagingLinks.
-// num is the number of returned results.
-func newPagingLinks(basePath string, opts platform.FindOptions, f platform.PagingFilter, num int) *platform.PagingLinks {
+// num is the number of returned results and last is the last of them, if any.
+// When last is known the next link continues after it with a cursor token,
+// which stays correct when resources are created or deleted between requests.
+func newPagingLinks(basePath string, opts platform.FindOptions, f platform.PagingFilter, num int, last platform.Pageable) *platform.PagingLinks {
 	u := url.URL{
 		Path: basePath,
 	}
@@ -87,17 +98,23 @@ func newPagingLinks(basePath string, opts platform.FindOptions, f platform.Pagin
 	self = u.String()
 
 	if num >= opts.Limit {
-		nextOffset := opts.Offset + opts.Limit
-		values.Set("offset", strconv.Itoa(nextOffset))
+		if c, err := cursorOf(last, opts.SortBy); err == nil {
+			values.Del("offset")
+			values.Set("after", c.Encode())
+		} else {
+			nextOffset := opts.Offset + opts.Limit
+			values.Set("offset", strconv.Itoa(nextOffset))
+		}
 		u.RawQuery = values.Encode()
 		next = u.String()
 	}
 
-	if opts.Offset > 0 {
+	if opts.After == "" && opts.Offset > 0 {
 		prevOffset := opts.Offset - opts.Limit
 		if prevOffset < 0 {
 			prevOffset = 0
 		}
+		values.Del("after")
 		values.Set("offset", strconv.Itoa(prevOffset))
 		u.RawQuery = values.Encode()
 		prev = u.String()
@@ -111,3 +128,31 @@ func newPagingLinks(basePath string, opts platform.FindOptions, f platform.Pagin
 
 	return links
 }
+
+func cursorOf(p platform.Pageable, sortBy string) (platform.PageCursor, error) {
+	if p == nil {
+		return platform.PageCursor{}, &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  "no resource to page after",
+		}
+	}
+	return p.Cursor(sortBy)
+}
+
+// nextPageURL returns the URL of the page following the one described by links,
+// or nil if there is none. Only the query of the next link is used so that
+// any path prefix of the service address u is preserved.
+func nextPageURL(u *url.URL, links *platform.PagingLinks) (*url.URL, error) {
+	if links == nil || links.Next == "" {
+		return nil, nil
+	}
+
+	next, err := url.Parse(links.Next)
+	if err != nil {
+		return nil, err
+	}
+
+	nu := *u
+	nu.RawQuery = next.RawQuery
+	return &nu, nil
+}
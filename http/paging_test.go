@@ -57,6 +57,23 @@ func TestPaging_decodeFindOptions(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "decode FindOptions with cursor",
+			args: args{
+				map[string]string{
+					"offset": "10",
+					"limit":  "10",
+					"after":  "eyJpZCI6IjAwMDAwMDAwMDAwMDAwMDEifQ",
+				},
+			},
+			wants: wants{
+				opts: platform.FindOptions{
+					Offset: 0,
+					Limit:  10,
+					After:  "eyJpZCI6IjAwMDAwMDAwMDAwMDAwMDEifQ",
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -85,16 +102,27 @@ func TestPaging_decodeFindOptions(t *testing.T) {
 			if opts.Descending != tt.wants.opts.Descending {
 				t.Errorf("%q. decodeFindOptions() = %v, want %v", tt.name, opts.Descending, tt.wants.opts.Descending)
 			}
+			if opts.After != tt.wants.opts.After {
+				t.Errorf("%q. decodeFindOptions() = %v, want %v", tt.name, opts.After, tt.wants.opts.After)
+			}
 		})
 	}
 }
 
+func TestPaging_decodeFindOptionsInvalidCursor(t *testing.T) {
+	r := httptest.NewRequest("GET", "http://any.url?after=notacursor", nil)
+	if _, err := decodeFindOptions(context.Background(), r); platform.ErrorCode(err) != platform.EInvalid {
+		t.Errorf("expected invalid error decoding bad cursor, got %v", err)
+	}
+}
+
 func TestPaging_newPagingLinks(t *testing.T) {
 	type args struct {
 		basePath string
 		num      int
 		opts     platform.FindOptions
 		filter   mock.PagingFilter
+		last     platform.Pageable
 	}
 	type wants struct {
 		links platform.PagingLinks
@@ -174,11 +202,55 @@ func TestPaging_newPagingLinks(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "new PagingLinks with cursor next link",
+			args: args{
+				basePath: "/api/v2/buckets",
+				num:      10,
+				opts: platform.FindOptions{
+					Offset: 10,
+					Limit:  10,
+				},
+				filter: mock.PagingFilter{
+					Name: "name",
+				},
+				last: &platform.Bucket{ID: 1, Name: "b"},
+			},
+			wants: wants{
+				links: platform.PagingLinks{
+					Prev: "/api/v2/buckets?descending=false&limit=10&name=name&offset=0",
+					Self: "/api/v2/buckets?descending=false&limit=10&name=name&offset=10",
+					Next: "/api/v2/buckets?after=eyJpZCI6IjAwMDAwMDAwMDAwMDAwMDEifQ&descending=false&limit=10&name=name",
+				},
+			},
+		},
+		{
+			name: "new PagingLinks from cursor",
+			args: args{
+				basePath: "/api/v2/buckets",
+				num:      10,
+				opts: platform.FindOptions{
+					Limit: 10,
+					After: "eyJpZCI6IjAwMDAwMDAwMDAwMDAwMDEifQ",
+				},
+				filter: mock.PagingFilter{
+					Name: "name",
+				},
+				last: &platform.Bucket{ID: 2, Name: "c"},
+			},
+			wants: wants{
+				links: platform.PagingLinks{
+					Prev: "",
+					Self: "/api/v2/buckets?after=eyJpZCI6IjAwMDAwMDAwMDAwMDAwMDEifQ&descending=false&limit=10&name=name",
+					Next: "/api/v2/buckets?after=eyJpZCI6IjAwMDAwMDAwMDAwMDAwMDIifQ&descending=false&limit=10&name=name",
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			links := newPagingLinks(tt.args.basePath, tt.args.opts, tt.args.filter, tt.args.num)
+			links := newPagingLinks(tt.args.basePath, tt.args.opts, tt.args.filter, tt.args.num, tt.args.last)
 
 			if links.Prev != tt.wants.links.Prev {
 				t.Errorf("%q. newPagingLinks() = %v, want %v", tt.name, links.Prev, tt.wants.links.Prev)
@@ -22,6 +22,7 @@ import (
 	"github.com/influxdata/flux/semantic"
 	"github.com/influxdata/flux/values"
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/macro"
 	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxql"
 )
@@ -34,6 +35,11 @@ type QueryRequest struct {
 	Type    string       `json:"type"`
 	Dialect QueryDialect `json:"dialect"`
 
+	// Macros binds the macros referenced by the query, as v.name, to values.
+	// Other macros are bound to the selected values of the macros of the
+	// organization.
+	Macros map[string]string `json:"macros,omitempty"`
+
 	Org *platform.Organization `json:"-"`
 }
 
@@ -247,6 +253,36 @@ func toSpec(p *ast.Package, now func() time.Time) (*flux.Spec, error) {
 	return flux.ToSpec(sideEffects, nowTime.Time().Time())
 }
 
+// BindMacros binds the macros referenced by the query to the values of
+// r.Macros or, lacking one, to the selected values of the macros of the
+// organization found by s. A nil s only binds the values of r.Macros.
+func (r *QueryRequest) BindMacros(ctx context.Context, s platform.MacroBindingService) error {
+	if r.Query == "" {
+		return nil
+	}
+
+	names := macro.References(macro.LanguageFlux, r.Query)
+	if len(names) == 0 {
+		return nil
+	}
+
+	bindings := r.Macros
+	if s != nil {
+		var err error
+		bindings, err = s.FindMacroBindings(ctx, r.Org.ID, names, r.Macros)
+		if err != nil {
+			return err
+		}
+	}
+
+	q, err := macro.Bind(macro.LanguageFlux, r.Query, bindings)
+	if err != nil {
+		return err
+	}
+	r.Query = q
+	return nil
+}
+
 // ProxyRequest returns a request to proxy from the flux.
 func (r QueryRequest) ProxyRequest() (*query.ProxyRequest, error) {
 	return r.proxyRequest(time.Now)
@@ -365,12 +401,16 @@ func decodeQueryRequest(ctx context.Context, r *http.Request, svc platform.Organ
 	return &req, err
 }
 
-func decodeProxyQueryRequest(ctx context.Context, r *http.Request, auth platform.Authorizer, svc platform.OrganizationService) (*query.ProxyRequest, error) {
+func decodeProxyQueryRequest(ctx context.Context, r *http.Request, auth platform.Authorizer, svc platform.OrganizationService, macroSvc platform.MacroBindingService) (*query.ProxyRequest, error) {
 	req, err := decodeQueryRequest(ctx, r, svc)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := req.BindMacros(ctx, macroSvc); err != nil {
+		return nil, err
+	}
+
 	pr, err := req.ProxyRequest()
 	if err != nil {
 		return nil, err
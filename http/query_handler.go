@@ -33,6 +33,7 @@ type FluxBackend struct {
 	Logger *zap.Logger
 
 	OrganizationService platform.OrganizationService
+	MacroBindingService platform.MacroBindingService
 	ProxyQueryService   query.ProxyQueryService
 }
 
@@ -54,6 +55,7 @@ type FluxHandler struct {
 
 	Now                 func() time.Time
 	OrganizationService platform.OrganizationService
+	MacroBindingService platform.MacroBindingService
 	ProxyQueryService   query.ProxyQueryService
 }
 
@@ -66,6 +68,7 @@ func NewFluxHandler(b *FluxBackend) *FluxHandler {
 
 		ProxyQueryService:   b.ProxyQueryService,
 		OrganizationService: b.OrganizationService,
+		MacroBindingService: b.MacroBindingService,
 	}
 
 	h.HandlerFunc("POST", fluxPath, h.handleQuery)
@@ -86,7 +89,7 @@ func (h *FluxHandler) handleQuery(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	req, err := decodeProxyQueryRequest(ctx, r, a, h.OrganizationService)
+	req, err := decodeProxyQueryRequest(ctx, r, a, h.OrganizationService, h.MacroBindingService)
 	if err != nil && err != platform.ErrAuthorizerNotSupported {
 		EncodeError(ctx, err, w)
 		return
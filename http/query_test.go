@@ -17,6 +17,7 @@ import (
 	"github.com/influxdata/flux/csv"
 	"github.com/influxdata/flux/lang"
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/macro"
 	"github.com/influxdata/influxdb/mock"
 	"github.com/influxdata/influxdb/query"
 	_ "github.com/influxdata/influxdb/query/builtin"
@@ -456,10 +457,11 @@ func Test_decodeQueryRequest(t *testing.T) {
 
 func Test_decodeProxyQueryRequest(t *testing.T) {
 	type args struct {
-		ctx  context.Context
-		r    *http.Request
-		auth *platform.Authorization
-		svc  platform.OrganizationService
+		ctx    context.Context
+		r      *http.Request
+		auth   *platform.Authorization
+		svc    platform.OrganizationService
+		macros platform.MacroBindingService
 	}
 	tests := []struct {
 		name    string
@@ -525,6 +527,54 @@ func Test_decodeProxyQueryRequest(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "valid post query request binding macros",
+			args: args{
+				r: httptest.NewRequest("POST", "/", bytes.NewBufferString(`{"query": "from(bucket: v.bucket) |> filter(fn: (r) => r.host == v.host)", "macros": {"host": "a"}}`)),
+				svc: &mock.OrganizationService{
+					FindOrganizationF: func(ctx context.Context, filter platform.OrganizationFilter) (*platform.Organization, error) {
+						return &platform.Organization{
+							ID: func() platform.ID { s, _ := platform.IDFromString("deadbeefdeadbeef"); return *s }(),
+						}, nil
+					},
+				},
+				macros: macro.NewValuesService(&mock.MacroService{
+					FindMacrosF: func(ctx context.Context, filter platform.MacroFilter, opts ...platform.FindOptions) ([]*platform.Macro, error) {
+						return []*platform.Macro{
+							{
+								Name:     "bucket",
+								Selected: []string{"telegraf"},
+								Arguments: &platform.MacroArguments{
+									Type:   "constant",
+									Values: platform.MacroConstantValues{"telegraf", "system"},
+								},
+							},
+							{
+								Name: "host",
+								Arguments: &platform.MacroArguments{
+									Type:   "constant",
+									Values: platform.MacroConstantValues{"b"},
+								},
+							},
+						}, nil
+					},
+				}, mock.NewProxyQueryService()),
+			},
+			want: &query.ProxyRequest{
+				Request: query.Request{
+					OrganizationID: func() platform.ID { s, _ := platform.IDFromString("deadbeefdeadbeef"); return *s }(),
+					Compiler: lang.FluxCompiler{
+						Query: "v = {bucket: \"telegraf\", host: \"a\"}\n\nfrom(bucket: v.bucket) |> filter(fn: (r) => r.host == v.host)",
+					},
+				},
+				Dialect: &csv.Dialect{
+					ResultEncoderConfig: csv.ResultEncoderConfig{
+						NoHeader:  false,
+						Delimiter: ',',
+					},
+				},
+			},
+		},
 	}
 	var cmpOptions = cmp.Options{
 		cmpopts.IgnoreUnexported(query.ProxyRequest{}),
@@ -533,7 +583,7 @@ func Test_decodeProxyQueryRequest(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := decodeProxyQueryRequest(tt.args.ctx, tt.args.r, tt.args.auth, tt.args.svc)
+			got, err := decodeProxyQueryRequest(tt.args.ctx, tt.args.r, tt.args.auth, tt.args.svc, tt.args.macros)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("decodeProxyQueryRequest() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -0,0 +1,265 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	runningQueriesPath   = "/api/v2/queries"
+	runningQueriesIDPath = "/api/v2/queries/:id"
+)
+
+// RunningQueryBackend is all services and associated parameters required to construct
+// the RunningQueryHandler.
+type RunningQueryBackend struct {
+	Logger *zap.Logger
+
+	RunningQueryService platform.RunningQueryService
+}
+
+// NewRunningQueryBackend returns a new instance of RunningQueryBackend.
+func NewRunningQueryBackend(b *APIBackend) *RunningQueryBackend {
+	return &RunningQueryBackend{
+		Logger: b.Logger.With(zap.String("handler", "running_query")),
+
+		RunningQueryService: b.RunningQueryService,
+	}
+}
+
+// RunningQueryHandler is the handler for listing and canceling the queries being run.
+type RunningQueryHandler struct {
+	*httprouter.Router
+
+	Logger *zap.Logger
+
+	RunningQueryService platform.RunningQueryService
+}
+
+// NewRunningQueryHandler creates a new RunningQueryHandler.
+func NewRunningQueryHandler(b *RunningQueryBackend) *RunningQueryHandler {
+	h := &RunningQueryHandler{
+		Router: NewRouter(),
+		Logger: b.Logger,
+
+		RunningQueryService: b.RunningQueryService,
+	}
+
+	h.HandlerFunc("GET", runningQueriesPath, h.handleGetRunningQueries)
+	h.HandlerFunc("GET", runningQueriesIDPath, h.handleGetRunningQuery)
+	h.HandlerFunc("DELETE", runningQueriesIDPath, h.handleDeleteRunningQuery)
+
+	return h
+}
+
+type runningQueryResponse struct {
+	Links map[string]string `json:"links"`
+	*platform.RunningQuery
+}
+
+func newRunningQueryResponse(q *platform.RunningQuery) *runningQueryResponse {
+	return &runningQueryResponse{
+		Links: map[string]string{
+			"self": fmt.Sprintf("/api/v2/queries/%s", q.ID),
+			"org":  fmt.Sprintf("/api/v2/orgs/%s", q.OrganizationID),
+		},
+		RunningQuery: q,
+	}
+}
+
+type runningQueriesResponse struct {
+	Links   map[string]string       `json:"links"`
+	Queries []*runningQueryResponse `json:"queries"`
+}
+
+func newRunningQueriesResponse(qs []*platform.RunningQuery) *runningQueriesResponse {
+	queries := make([]*runningQueryResponse, 0, len(qs))
+	for _, q := range qs {
+		queries = append(queries, newRunningQueryResponse(q))
+	}
+	return &runningQueriesResponse{
+		Links: map[string]string{
+			"self": runningQueriesPath,
+		},
+		Queries: queries,
+	}
+}
+
+func decodeGetRunningQueriesRequest(ctx context.Context, r *http.Request) (*platform.RunningQueryFilter, error) {
+	filter := &platform.RunningQueryFilter{}
+	if orgID := r.URL.Query().Get("orgID"); orgID != "" {
+		id, err := platform.IDFromString(orgID)
+		if err != nil {
+			return nil, err
+		}
+		filter.OrganizationID = id
+	}
+	return filter, nil
+}
+
+// handleGetRunningQueries lists the queries being run.
+func (h *RunningQueryHandler) handleGetRunningQueries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter, err := decodeGetRunningQueriesRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	qs, err := h.RunningQueryService.FindRunningQueries(ctx, *filter)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newRunningQueriesResponse(qs)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodeRunningQueryID(ctx context.Context) (platform.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	id := params.ByName("id")
+	if id == "" {
+		return platform.InvalidID(), &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	var i platform.ID
+	if err := i.DecodeFromString(id); err != nil {
+		return platform.InvalidID(), err
+	}
+	return i, nil
+}
+
+// handleGetRunningQuery retrieves a single query being run.
+func (h *RunningQueryHandler) handleGetRunningQuery(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeRunningQueryID(ctx)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	q, err := h.RunningQueryService.FindRunningQueryByID(ctx, id)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newRunningQueryResponse(q)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteRunningQuery cancels a query being run.
+func (h *RunningQueryHandler) handleDeleteRunningQuery(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeRunningQueryID(ctx)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := h.RunningQueryService.CancelRunningQuery(ctx, id); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RunningQueryService connects to Influx via HTTP using tokens to manage running queries.
+type RunningQueryService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ platform.RunningQueryService = (*RunningQueryService)(nil)
+
+func runningQueryIDPath(id platform.ID) string {
+	return path.Join(runningQueriesPath, id.String())
+}
+
+// FindRunningQueries returns the queries being run that match filter.
+func (s *RunningQueryService) FindRunningQueries(ctx context.Context, filter platform.RunningQueryFilter) ([]*platform.RunningQuery, error) {
+	u, err := newURL(s.Addr, runningQueriesPath)
+	if err != nil {
+		return nil, err
+	}
+	if filter.OrganizationID != nil {
+		query := u.Query()
+		query.Set("orgID", filter.OrganizationID.String())
+		u.RawQuery = query.Encode()
+	}
+
+	var resp struct {
+		Queries []*platform.RunningQuery `json:"queries"`
+	}
+	if err := s.do(ctx, "GET", u.String(), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Queries, nil
+}
+
+// FindRunningQueryByID returns a single query being run by ID.
+func (s *RunningQueryService) FindRunningQueryByID(ctx context.Context, id platform.ID) (*platform.RunningQuery, error) {
+	u, err := newURL(s.Addr, runningQueryIDPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	var q platform.RunningQuery
+	if err := s.do(ctx, "GET", u.String(), &q); err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
+// CancelRunningQuery stops the execution of a query being run.
+func (s *RunningQueryService) CancelRunningQuery(ctx context.Context, id platform.ID) error {
+	u, err := newURL(s.Addr, runningQueryIDPath(id))
+	if err != nil {
+		return err
+	}
+	return s.do(ctx, "DELETE", u.String(), nil)
+}
+
+func (s *RunningQueryService) do(ctx context.Context, method, url string, v interface{}) error {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return err
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(req.URL.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return err
+	}
+
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,88 @@
+package http
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func TestRunningQueryService(t *testing.T) {
+	running := &platform.RunningQuery{
+		ID:              1,
+		OrganizationID:  2,
+		AuthorizationID: 3,
+		UserID:          4,
+		CompilerType:    "flux",
+		Query:           `from(bucket: "b") |> range(start: -1h)`,
+		State:           "executing",
+		Start:           time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC),
+		Elapsed:         time.Minute,
+		MaxAllocated:    1024,
+	}
+
+	var canceled platform.ID
+	s := mock.NewRunningQueryService()
+	s.FindRunningQueriesF = func(ctx context.Context, filter platform.RunningQueryFilter) ([]*platform.RunningQuery, error) {
+		if filter.OrganizationID != nil && *filter.OrganizationID != running.OrganizationID {
+			return []*platform.RunningQuery{}, nil
+		}
+		return []*platform.RunningQuery{running}, nil
+	}
+	s.FindRunningQueryByIDF = func(ctx context.Context, id platform.ID) (*platform.RunningQuery, error) {
+		if id != running.ID {
+			return nil, &platform.Error{Code: platform.ENotFound, Msg: platform.ErrRunningQueryNotFound}
+		}
+		return running, nil
+	}
+	s.CancelRunningQueryF = func(ctx context.Context, id platform.ID) error {
+		canceled = id
+		return nil
+	}
+
+	server := httptest.NewServer(NewRunningQueryHandler(&RunningQueryBackend{
+		Logger:              zap.NewNop(),
+		RunningQueryService: s,
+	}))
+	defer server.Close()
+	client := RunningQueryService{Addr: server.URL}
+	ctx := context.Background()
+
+	qs, err := client.FindRunningQueries(ctx, platform.RunningQueryFilter{OrganizationID: &running.OrganizationID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qs) != 1 || *qs[0] != *running {
+		t.Fatalf("unexpected running queries %+v", qs)
+	}
+
+	otherOrgID := platform.ID(5)
+	if qs, err := client.FindRunningQueries(ctx, platform.RunningQueryFilter{OrganizationID: &otherOrgID}); err != nil {
+		t.Fatal(err)
+	} else if len(qs) != 0 {
+		t.Fatalf("expected no running queries, got %+v", qs)
+	}
+
+	q, err := client.FindRunningQueryByID(ctx, running.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *q != *running {
+		t.Fatalf("unexpected running query %+v", q)
+	}
+
+	if _, err := client.FindRunningQueryByID(ctx, 6); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected a missing query to be not found, got %v", err)
+	}
+
+	if err := client.CancelRunningQuery(ctx, running.ID); err != nil {
+		t.Fatal(err)
+	}
+	if canceled != running.ID {
+		t.Fatalf("expected query %s to be canceled, got %s", running.ID, canceled)
+	}
+}
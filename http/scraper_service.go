@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"net/url"
 	"path"
 
 	"github.com/influxdata/influxdb"
@@ -223,13 +224,19 @@ func (h *ScraperHandler) handleGetScraperTarget(w http.ResponseWriter, r *http.R
 func (h *ScraperHandler) handleGetScraperTargets(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	targets, err := h.ScraperStorageService.ListTargets(ctx)
+	opts, err := decodeFindOptions(ctx, r)
 	if err != nil {
 		EncodeError(ctx, err, w)
 		return
 	}
 
-	resp, err := h.newListTargetsResponse(ctx, targets)
+	targets, err := h.ScraperStorageService.ListTargets(ctx, *opts)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	resp, err := h.newListTargetsResponse(ctx, *opts, targets)
 	if err != nil {
 		EncodeError(ctx, err, w)
 		return
@@ -246,6 +253,9 @@ func decodeScraperTargetUpdateRequest(ctx context.Context, r *http.Request) (*in
 	if err := json.NewDecoder(r.Body).Decode(update); err != nil {
 		return nil, err
 	}
+	if err := validScraperTarget(update); err != nil {
+		return nil, err
+	}
 	id, err := decodeScraperTargetIDRequest(ctx, r)
 	if err != nil {
 		return nil, err
@@ -259,9 +269,25 @@ func decodeScraperTargetAddRequest(ctx context.Context, r *http.Request) (*influ
 	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
 		return nil, err
 	}
+	if err := validScraperTarget(req); err != nil {
+		return nil, err
+	}
 	return req, nil
 }
 
+// validScraperTarget validates a decoded target, dropping the status the
+// store keeps for it.
+func validScraperTarget(t *influxdb.ScraperTarget) error {
+	t.Status = nil
+	if err := t.Valid(); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+	return nil
+}
+
 func decodeScraperTargetIDRequest(ctx context.Context, r *http.Request) (*influxdb.ID, error) {
 	params := httprouter.ParamsFromContext(ctx)
 	id := params.ByName("id")
@@ -289,24 +315,57 @@ type ScraperService struct {
 	OpPrefix string
 }
 
-// ListTargets returns a list of all scraper targets.
-func (s *ScraperService) ListTargets(ctx context.Context) ([]influxdb.ScraperTarget, error) {
-	url, err := newURL(s.Addr, targetsPath)
+// ListTargets returns a list of scraper targets.
+// Additional options provide pagination & sorting. Without a limit every page is fetched.
+func (s *ScraperService) ListTargets(ctx context.Context, opt ...influxdb.FindOptions) ([]influxdb.ScraperTarget, error) {
+	u, err := newURL(s.Addr, targetsPath)
 	if err != nil {
 		return nil, err
 	}
 
-	query := url.Query()
+	query := u.Query()
+	paginate := true
+	if len(opt) > 0 {
+		for k, vs := range opt[0].QueryParams() {
+			for _, v := range vs {
+				query.Add(k, v)
+			}
+		}
+		paginate = opt[0].Limit == 0
+	}
+	u.RawQuery = query.Encode()
 
-	req, err := http.NewRequest("GET", url.String(), nil)
+	targets := []influxdb.ScraperTarget{}
+	for u != nil {
+		targetsResp, err := s.listTargets(u)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range targetsResp.Targets {
+			targets = append(targets, v.ScraperTarget)
+		}
+
+		if !paginate {
+			break
+		}
+		if u, err = nextPageURL(u, targetsResp.Links); err != nil {
+			return nil, err
+		}
+	}
+
+	return targets, nil
+}
+
+// listTargets fetches the single page of scraper targets at u.
+func (s *ScraperService) listTargets(u *url.URL) (*getTargetsResponse, error) {
+	req, err := http.NewRequest("GET", u.String(), nil)
 	if err != nil {
 		return nil, err
 	}
-
-	req.URL.RawQuery = query.Encode()
 	SetToken(s.Token, req)
 
-	hc := newClient(url.Scheme, s.InsecureSkipVerify)
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
 	resp, err := hc.Do(req)
 	if err != nil {
 		return nil, err
@@ -320,13 +379,7 @@ func (s *ScraperService) ListTargets(ctx context.Context) ([]influxdb.ScraperTar
 	if err := json.NewDecoder(resp.Body).Decode(&targetsResp); err != nil {
 		return nil, err
 	}
-
-	targets := make([]influxdb.ScraperTarget, len(targetsResp.Targets))
-	for k, v := range targetsResp.Targets {
-		targets[k] = v.ScraperTarget
-	}
-
-	return targets, nil
+	return &targetsResp, nil
 }
 
 // UpdateTarget updates a single scraper target with changeset.
@@ -489,13 +542,9 @@ func targetIDPath(id influxdb.ID) string {
 	return path.Join(targetsPath, id.String())
 }
 
-type getTargetsLinks struct {
-	Self string `json:"self"`
-}
-
 type getTargetsResponse struct {
-	Links   getTargetsLinks  `json:"links"`
-	Targets []targetResponse `json:"configurations"`
+	Links   *influxdb.PagingLinks `json:"links"`
+	Targets []targetResponse      `json:"configurations"`
 }
 
 type targetLinks struct {
@@ -511,11 +560,13 @@ type targetResponse struct {
 	Links        targetLinks `json:"links"`
 }
 
-func (h *ScraperHandler) newListTargetsResponse(ctx context.Context, targets []influxdb.ScraperTarget) (getTargetsResponse, error) {
+func (h *ScraperHandler) newListTargetsResponse(ctx context.Context, opts influxdb.FindOptions, targets []influxdb.ScraperTarget) (getTargetsResponse, error) {
+	var last influxdb.Pageable
+	if len(targets) > 0 {
+		last = &targets[len(targets)-1]
+	}
 	res := getTargetsResponse{
-		Links: getTargetsLinks{
-			Self: targetsPath,
-		},
+		Links:   newPagingLinks(targetsPath, opts, influxdb.ScraperTargetFilter{}, len(targets), last),
 		Targets: make([]targetResponse, 0, len(targets)),
 	}
 
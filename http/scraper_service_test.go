@@ -87,7 +87,7 @@ func TestService_handleGetScraperTargets(t *testing.T) {
 					},
 				},
 				ScraperTargetStoreService: &mock.ScraperTargetStoreService{
-					ListTargetsF: func(ctx context.Context) ([]platform.ScraperTarget, error) {
+					ListTargetsF: func(ctx context.Context, opt ...platform.FindOptions) ([]platform.ScraperTarget, error) {
 						return []platform.ScraperTarget{
 							{
 								ID:       targetOneID,
@@ -117,7 +117,7 @@ func TestService_handleGetScraperTargets(t *testing.T) {
 					`
 					{
 					  "links": {
-					    "self": "/api/v2/scrapers"
+					    "self": "/api/v2/scrapers?descending=false&limit=20&offset=0"
 					  },
 					  "configurations": [
 					    {
@@ -178,7 +178,7 @@ func TestService_handleGetScraperTargets(t *testing.T) {
 					},
 				},
 				ScraperTargetStoreService: &mock.ScraperTargetStoreService{
-					ListTargetsF: func(ctx context.Context) ([]platform.ScraperTarget, error) {
+					ListTargetsF: func(ctx context.Context, opt ...platform.FindOptions) ([]platform.ScraperTarget, error) {
 						return []platform.ScraperTarget{}, nil
 					},
 				},
@@ -190,7 +190,7 @@ func TestService_handleGetScraperTargets(t *testing.T) {
 				body: `
                 {
                   "links": {
-                    "self": "/api/v2/scrapers"
+                    "self": "/api/v2/scrapers?descending=false&limit=20&offset=0"
                   },
                   "configurations": []
                 }
@@ -0,0 +1,394 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	sharesPath = "/api/v2/shares"
+)
+
+// ShareBackend is all services and associated parameters required to construct
+// the ShareHandler.
+type ShareBackend struct {
+	Logger       *zap.Logger
+	ShareService platform.ShareService
+}
+
+// NewShareBackend returns a new instance of ShareBackend.
+func NewShareBackend(b *APIBackend) *ShareBackend {
+	return &ShareBackend{
+		Logger:       b.Logger.With(zap.String("handler", "share")),
+		ShareService: b.ShareService,
+	}
+}
+
+// ShareHandler is the handler for the share grant service.
+type ShareHandler struct {
+	*httprouter.Router
+
+	Logger *zap.Logger
+
+	ShareService platform.ShareService
+}
+
+// NewShareHandler creates a new ShareHandler.
+func NewShareHandler(b *ShareBackend) *ShareHandler {
+	h := &ShareHandler{
+		Router: NewRouter(),
+		Logger: b.Logger,
+
+		ShareService: b.ShareService,
+	}
+
+	entityPath := fmt.Sprintf("%s/:id", sharesPath)
+
+	h.HandlerFunc("GET", sharesPath, h.handleGetShares)
+	h.HandlerFunc("POST", sharesPath, h.handlePostShare)
+	h.HandlerFunc("GET", entityPath, h.handleGetShare)
+	h.HandlerFunc("DELETE", entityPath, h.handleDeleteShare)
+
+	return h
+}
+
+type shareLinks struct {
+	Self      string `json:"self"`
+	Resource  string `json:"resource"`
+	Org       string `json:"org"`
+	TargetOrg string `json:"targetOrg"`
+}
+
+type shareResponse struct {
+	*platform.ShareGrant
+	Links shareLinks `json:"links"`
+}
+
+func newShareResponse(g *platform.ShareGrant) shareResponse {
+	return shareResponse{
+		ShareGrant: g,
+		Links: shareLinks{
+			Self:      shareIDPath(g.ID),
+			Resource:  fmt.Sprintf("/api/v2/%s/%s", g.ResourceType, g.ResourceID),
+			Org:       fmt.Sprintf("/api/v2/orgs/%s", g.OrgID),
+			TargetOrg: fmt.Sprintf("/api/v2/orgs/%s", g.TargetOrgID),
+		},
+	}
+}
+
+type sharesResponse struct {
+	Shares []shareResponse   `json:"shares"`
+	Links  map[string]string `json:"links"`
+}
+
+func newSharesResponse(gs []*platform.ShareGrant) sharesResponse {
+	resp := sharesResponse{
+		Shares: make([]shareResponse, 0, len(gs)),
+		Links: map[string]string{
+			"self": sharesPath,
+		},
+	}
+
+	for _, g := range gs {
+		resp.Shares = append(resp.Shares, newShareResponse(g))
+	}
+
+	return resp
+}
+
+func (h *ShareHandler) handleGetShares(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter, err := decodeShareFilter(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	gs, _, err := h.ShareService.FindShares(ctx, *filter)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newSharesResponse(gs)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodeShareFilter(ctx context.Context, r *http.Request) (*platform.ShareFilter, error) {
+	qp := r.URL.Query()
+	f := &platform.ShareFilter{}
+
+	if id := qp.Get("resourceID"); id != "" {
+		i, err := platform.IDFromString(id)
+		if err != nil {
+			return nil, err
+		}
+		f.ResourceID = i
+	}
+
+	if rt := qp.Get("resourceType"); rt != "" {
+		f.ResourceType = platform.ResourceType(rt)
+	}
+
+	if id := qp.Get("orgID"); id != "" {
+		i, err := platform.IDFromString(id)
+		if err != nil {
+			return nil, err
+		}
+		f.OrgID = i
+	}
+
+	if id := qp.Get("targetOrgID"); id != "" {
+		i, err := platform.IDFromString(id)
+		if err != nil {
+			return nil, err
+		}
+		f.TargetOrgID = i
+	}
+
+	return f, nil
+}
+
+func (h *ShareHandler) handlePostShare(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	g := &platform.ShareGrant{}
+	if err := json.NewDecoder(r.Body).Decode(g); err != nil {
+		EncodeError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  err.Error(),
+		}, w)
+		return
+	}
+
+	if err := h.ShareService.CreateShare(ctx, g); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newShareResponse(g)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func requestShareID(ctx context.Context) (platform.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	urlID := params.ByName("id")
+	if urlID == "" {
+		return platform.InvalidID(), &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	id, err := platform.IDFromString(urlID)
+	if err != nil {
+		return platform.InvalidID(), &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  err.Error(),
+		}
+	}
+
+	return *id, nil
+}
+
+func (h *ShareHandler) handleGetShare(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := requestShareID(ctx)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	g, err := h.ShareService.FindShareByID(ctx, id)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newShareResponse(g)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *ShareHandler) handleDeleteShare(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := requestShareID(ctx)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := h.ShareService.DeleteShare(ctx, id); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ShareService is a share grant service over HTTP to the influxdb server.
+type ShareService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+// FindShareByID returns a single share grant by ID.
+func (s *ShareService) FindShareByID(ctx context.Context, id platform.ID) (*platform.ShareGrant, error) {
+	u, err := newURL(s.Addr, shareIDPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var sr shareResponse
+	if err := json.NewDecoder(resp.Body).Decode(&sr); err != nil {
+		return nil, err
+	}
+
+	return sr.ShareGrant, nil
+}
+
+// FindShares returns a list of share grants that match filter and the total count of matching grants.
+func (s *ShareService) FindShares(ctx context.Context, filter platform.ShareFilter) ([]*platform.ShareGrant, int, error) {
+	u, err := newURL(s.Addr, sharesPath)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := u.Query()
+	if filter.ResourceID != nil {
+		query.Add("resourceID", filter.ResourceID.String())
+	}
+	if filter.ResourceType != "" {
+		query.Add("resourceType", string(filter.ResourceType))
+	}
+	if filter.OrgID != nil {
+		query.Add("orgID", filter.OrgID.String())
+	}
+	if filter.TargetOrgID != nil {
+		query.Add("targetOrgID", filter.TargetOrgID.String())
+	}
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, 0, err
+	}
+
+	var sr sharesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&sr); err != nil {
+		return nil, 0, err
+	}
+
+	gs := make([]*platform.ShareGrant, 0, len(sr.Shares))
+	for _, g := range sr.Shares {
+		gs = append(gs, g.ShareGrant)
+	}
+
+	return gs, len(gs), nil
+}
+
+// CreateShare creates a new share grant and sets g.ID with the new identifier.
+func (s *ShareService) CreateShare(ctx context.Context, g *platform.ShareGrant) error {
+	u, err := newURL(s.Addr, sharesPath)
+	if err != nil {
+		return err
+	}
+
+	octets, err := json.Marshal(g)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(octets))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return err
+	}
+
+	return json.NewDecoder(resp.Body).Decode(g)
+}
+
+// DeleteShare revokes a share grant.
+func (s *ShareService) DeleteShare(ctx context.Context, id platform.ID) error {
+	u, err := newURL(s.Addr, shareIDPath(id))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("DELETE", u.String(), nil)
+	if err != nil {
+		return err
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return CheckError(resp)
+}
+
+func shareIDPath(id platform.ID) string {
+	return path.Join(sharesPath, id.String())
+}
@@ -0,0 +1,89 @@
+package http
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func NewMockShareBackend() *ShareBackend {
+	return &ShareBackend{
+		Logger:       zap.NewNop().With(zap.String("handler", "share")),
+		ShareService: mock.NewShareService(),
+	}
+}
+
+func TestShareService(t *testing.T) {
+	grants := map[platform.ID]*platform.ShareGrant{}
+	svc := mock.NewShareService()
+	svc.CreateShareF = func(ctx context.Context, g *platform.ShareGrant) error {
+		g.ID = platform.ID(len(grants) + 1)
+		g.OrgID = 10
+		grants[g.ID] = g
+		return nil
+	}
+	svc.FindShareByIDF = func(ctx context.Context, id platform.ID) (*platform.ShareGrant, error) {
+		g, ok := grants[id]
+		if !ok {
+			return nil, &platform.Error{Code: platform.ENotFound, Msg: platform.ErrShareNotFound}
+		}
+		return g, nil
+	}
+	svc.FindSharesF = func(ctx context.Context, filter platform.ShareFilter) ([]*platform.ShareGrant, int, error) {
+		gs := []*platform.ShareGrant{}
+		for _, g := range grants {
+			if filter.TargetOrgID == nil || *filter.TargetOrgID == g.TargetOrgID {
+				gs = append(gs, g)
+			}
+		}
+		return gs, len(gs), nil
+	}
+	svc.DeleteShareF = func(ctx context.Context, id platform.ID) error {
+		if _, ok := grants[id]; !ok {
+			return &platform.Error{Code: platform.ENotFound, Msg: platform.ErrShareNotFound}
+		}
+		delete(grants, id)
+		return nil
+	}
+
+	backend := NewMockShareBackend()
+	backend.ShareService = svc
+	server := httptest.NewServer(NewShareHandler(backend))
+	defer server.Close()
+
+	client := &ShareService{Addr: server.URL}
+	ctx := context.Background()
+
+	g := &platform.ShareGrant{
+		ResourceType: platform.BucketsResourceType,
+		ResourceID:   1,
+		TargetOrgID:  20,
+		Action:       platform.ReadAction,
+	}
+	if err := client.CreateShare(ctx, g); err != nil {
+		t.Fatalf("unable to create share: %v", err)
+	}
+	if g.ID != 1 || g.OrgID != 10 {
+		t.Fatalf("expected created grant to be returned, got %+v", g)
+	}
+
+	target := platform.ID(20)
+	gs, n, err := client.FindShares(ctx, platform.ShareFilter{TargetOrgID: &target})
+	if err != nil {
+		t.Fatalf("unable to find shares: %v", err)
+	}
+	if n != 1 || *gs[0] != *g {
+		t.Fatalf("expected [%+v], got %+v", g, gs)
+	}
+
+	if err := client.DeleteShare(ctx, g.ID); err != nil {
+		t.Fatalf("unable to delete share: %v", err)
+	}
+	if _, err := client.FindShareByID(ctx, g.ID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected revoked grant to be not found, got %v", err)
+	}
+}
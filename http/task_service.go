@@ -30,6 +30,7 @@ type TaskBackend struct {
 	OrganizationService        platform.OrganizationService
 	UserResourceMappingService platform.UserResourceMappingService
 	LabelService               platform.LabelService
+	LabelResourceService       platform.LabelResourceService
 	UserService                platform.UserService
 }
 
@@ -42,6 +43,7 @@ func NewTaskBackend(b *APIBackend) *TaskBackend {
 		OrganizationService:        b.OrganizationService,
 		UserResourceMappingService: b.UserResourceMappingService,
 		LabelService:               b.LabelService,
+		LabelResourceService:       b.LabelResourceService,
 		UserService:                b.UserService,
 	}
 }
@@ -56,6 +58,7 @@ type TaskHandler struct {
 	OrganizationService        platform.OrganizationService
 	UserResourceMappingService platform.UserResourceMappingService
 	LabelService               platform.LabelService
+	LabelResourceService       platform.LabelResourceService
 	UserService                platform.UserService
 }
 
@@ -86,6 +89,7 @@ func NewTaskHandler(b *TaskBackend) *TaskHandler {
 		OrganizationService:        b.OrganizationService,
 		UserResourceMappingService: b.UserResourceMappingService,
 		LabelService:               b.LabelService,
+		LabelResourceService:       b.LabelResourceService,
 		UserService:                b.UserService,
 	}
 
@@ -262,7 +266,7 @@ func (h *TaskHandler) handleGetTasks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tasks, _, err := h.TaskService.FindTasks(ctx, req.filter)
+	tasks, err := h.findTasks(ctx, req.filter)
 	if err != nil {
 		err = &platform.Error{
 			Err: err,
@@ -289,6 +293,55 @@ func (h *TaskHandler) handleGetTasks(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// findTasks returns a page of tasks matching filter. The task store does not
+// know about labels, so when labels are selected the tasks carrying them are
+// found first, and the store's pages are read in turn until filter.Limit of
+// them have been found.
+func (h *TaskHandler) findTasks(ctx context.Context, filter platform.TaskFilter) ([]*platform.Task, error) {
+	if filter.Labels.Empty() {
+		tasks, _, err := h.TaskService.FindTasks(ctx, filter)
+		return tasks, err
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = platform.TaskDefaultPageSize
+	}
+
+	ids, err := h.LabelResourceService.FindLabelResources(ctx, platform.TasksResourceType, filter.Labels)
+	if err != nil {
+		return nil, err
+	}
+	labeled := make(map[platform.ID]bool, len(ids))
+	for _, id := range ids {
+		labeled[id] = true
+	}
+
+	// The pages are read until every labeled task has been found.
+	tasks := []*platform.Task{}
+	for len(tasks) < len(labeled) {
+		page, _, err := h.TaskService.FindTasks(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, t := range page {
+			if !labeled[t.ID] {
+				continue
+			}
+			tasks = append(tasks, t)
+			if len(tasks) == filter.Limit {
+				return tasks, nil
+			}
+		}
+
+		if len(page) < filter.Limit {
+			break
+		}
+		filter.After = &page[len(page)-1].ID
+	}
+	return tasks, nil
+}
+
 type getTasksRequest struct {
 	filter platform.TaskFilter
 }
@@ -337,6 +390,12 @@ func decodeGetTasksRequest(ctx context.Context, r *http.Request) (*getTasksReque
 		req.filter.Limit = platform.TaskDefaultPageSize
 	}
 
+	labels, err := decodeLabelSelector(qp)
+	if err != nil {
+		return nil, err
+	}
+	req.filter.Labels = labels
+
 	return req, nil
 }
 
@@ -1196,9 +1255,33 @@ func (t TaskService) FindTaskByID(ctx context.Context, id platform.ID) (*platfor
 	return &tr.Task, nil
 }
 
-// FindTasks returns a list of tasks that match a filter (limit 100) and the total count
-// of matching tasks.
+// FindTasks returns a list of tasks that match a filter and the total count
+// of matching tasks. Without a limit, every page of at most TaskMaxPageSize
+// tasks is fetched in turn.
 func (t TaskService) FindTasks(ctx context.Context, filter platform.TaskFilter) ([]*platform.Task, int, error) {
+	if filter.Limit != 0 {
+		return t.findTasks(ctx, filter)
+	}
+
+	var tasks []*platform.Task
+	filter.Limit = platform.TaskMaxPageSize
+	for {
+		page, n, err := t.findTasks(ctx, filter)
+		if err != nil {
+			return nil, 0, err
+		}
+		tasks = append(tasks, page...)
+
+		if n < filter.Limit {
+			break
+		}
+		filter.After = &page[n-1].ID
+	}
+	return tasks, len(tasks), nil
+}
+
+// findTasks fetches a single page of tasks.
+func (t TaskService) findTasks(ctx context.Context, filter platform.TaskFilter) ([]*platform.Task, int, error) {
 	u, err := newURL(t.Addr, tasksPath)
 	if err != nil {
 		return nil, 0, err
@@ -1217,6 +1300,7 @@ func (t TaskService) FindTasks(ctx context.Context, filter platform.TaskFilter)
 	if filter.Limit != 0 {
 		val.Add("limit", strconv.Itoa(filter.Limit))
 	}
+	encodeLabelSelector(val, filter.Labels)
 
 	u.RawQuery = val.Encode()
 
@@ -8,6 +8,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -48,6 +49,7 @@ func NewMockTaskBackend() *TaskBackend {
 		},
 		UserResourceMappingService: mock.NewUserResourceMappingService(),
 		LabelService:               mock.NewLabelService(),
+		LabelResourceService:       mock.NewLabelResourceService(),
 		UserService:                mock.NewUserService(),
 	}
 }
@@ -512,6 +514,61 @@ func TestTaskHandler_handleGetRuns(t *testing.T) {
 	}
 }
 
+func TestTaskHandler_findTasks_Labels(t *testing.T) {
+	var tasks []*platform.Task
+	for id := platform.ID(1); id <= 25; id++ {
+		tasks = append(tasks, &platform.Task{ID: id})
+	}
+
+	taskBackend := NewMockTaskBackend()
+	taskBackend.TaskService = &mock.TaskService{
+		FindTasksFn: func(ctx context.Context, f platform.TaskFilter) ([]*platform.Task, int, error) {
+			if f.Limit == 0 {
+				t.Fatal("tasks found without a limit")
+			}
+			var page []*platform.Task
+			for _, task := range tasks {
+				if (f.After == nil || task.ID > *f.After) && len(page) < f.Limit {
+					page = append(page, task)
+				}
+			}
+			return page, len(page), nil
+		},
+	}
+	taskBackend.LabelResourceService = &mock.LabelResourceService{
+		FindLabelResourcesFn: func(ctx context.Context, rt platform.ResourceType, sel platform.LabelSelector) ([]platform.ID, error) {
+			if rt != platform.TasksResourceType {
+				t.Fatalf("unexpected resource type %q", rt)
+			}
+			return []platform.ID{25, 3, 14, 99}, nil
+		},
+	}
+	h := NewTaskHandler(taskBackend)
+
+	for _, tt := range []struct {
+		limit int
+		want  []platform.ID
+	}{
+		{limit: 0, want: []platform.ID{3, 14, 25}},
+		{limit: 2, want: []platform.ID{3, 14}},
+		{limit: 4, want: []platform.ID{3, 14, 25}},
+	} {
+		filter := platform.TaskFilter{Limit: tt.limit, Labels: platform.LabelSelector{Names: []string{"prod"}}}
+		found, err := h.findTasks(context.Background(), filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got []platform.ID
+		for _, task := range found {
+			got = append(got, task.ID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("limit %d: tasks = %v, want %v", tt.limit, got, tt.want)
+		}
+	}
+}
+
 func TestTaskHandler_NotFoundStatus(t *testing.T) {
 	// Ensure that the HTTP handlers return 404s for missing resources, and OKs for matching.
 
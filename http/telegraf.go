@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/golang/gddo/httputil"
 	platform "github.com/influxdata/influxdb"
@@ -20,6 +21,7 @@ type TelegrafBackend struct {
 	Logger *zap.Logger
 
 	TelegrafService            platform.TelegrafConfigStore
+	TelegrafAgentService       platform.TelegrafAgentService
 	UserResourceMappingService platform.UserResourceMappingService
 	LabelService               platform.LabelService
 	UserService                platform.UserService
@@ -32,6 +34,7 @@ func NewTelegrafBackend(b *APIBackend) *TelegrafBackend {
 		Logger: b.Logger.With(zap.String("handler", "telegraf")),
 
 		TelegrafService:            b.TelegrafService,
+		TelegrafAgentService:       b.TelegrafAgentService,
 		UserResourceMappingService: b.UserResourceMappingService,
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
@@ -45,6 +48,7 @@ type TelegrafHandler struct {
 	Logger *zap.Logger
 
 	TelegrafService            platform.TelegrafConfigStore
+	TelegrafAgentService       platform.TelegrafAgentService
 	UserResourceMappingService platform.UserResourceMappingService
 	LabelService               platform.LabelService
 	UserService                platform.UserService
@@ -54,6 +58,7 @@ type TelegrafHandler struct {
 const (
 	telegrafsPath            = "/api/v2/telegrafs"
 	telegrafsIDPath          = "/api/v2/telegrafs/:id"
+	telegrafsIDAgentsPath    = "/api/v2/telegrafs/:id/agents"
 	telegrafsIDMembersPath   = "/api/v2/telegrafs/:id/members"
 	telegrafsIDMembersIDPath = "/api/v2/telegrafs/:id/members/:userID"
 	telegrafsIDOwnersPath    = "/api/v2/telegrafs/:id/owners"
@@ -69,6 +74,7 @@ func NewTelegrafHandler(b *TelegrafBackend) *TelegrafHandler {
 		Logger: b.Logger,
 
 		TelegrafService:            b.TelegrafService,
+		TelegrafAgentService:       b.TelegrafAgentService,
 		UserResourceMappingService: b.UserResourceMappingService,
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
@@ -79,6 +85,7 @@ func NewTelegrafHandler(b *TelegrafBackend) *TelegrafHandler {
 	h.HandlerFunc("GET", telegrafsIDPath, h.handleGetTelegraf)
 	h.HandlerFunc("DELETE", telegrafsIDPath, h.handleDeleteTelegraf)
 	h.HandlerFunc("PUT", telegrafsIDPath, h.handlePutTelegraf)
+	h.HandlerFunc("GET", telegrafsIDAgentsPath, h.handleGetTelegrafAgents)
 
 	memberBackend := MemberBackend{
 		Logger:                     b.Logger.With(zap.String("handler", "member")),
@@ -127,7 +134,8 @@ type telegrafResponse struct {
 }
 
 type telegrafResponses struct {
-	TelegrafConfigs []telegrafResponse `json:"configurations"`
+	Links           *platform.PagingLinks `json:"links"`
+	TelegrafConfigs []telegrafResponse    `json:"configurations"`
 }
 
 func newTelegrafResponse(tc *platform.TelegrafConfig, labels []*platform.Label) telegrafResponse {
@@ -147,8 +155,13 @@ func newTelegrafResponse(tc *platform.TelegrafConfig, labels []*platform.Label)
 	return res
 }
 
-func newTelegrafResponses(ctx context.Context, tcs []*platform.TelegrafConfig, labelService platform.LabelService) telegrafResponses {
+func newTelegrafResponses(ctx context.Context, opts platform.FindOptions, f platform.TelegrafConfigFilter, tcs []*platform.TelegrafConfig, labelService platform.LabelService) telegrafResponses {
+	var last platform.Pageable
+	if len(tcs) > 0 {
+		last = tcs[len(tcs)-1]
+	}
 	resp := telegrafResponses{
+		Links:           newPagingLinks(telegrafsPath, opts, f, len(tcs), last),
 		TelegrafConfigs: make([]telegrafResponse, len(tcs)),
 	}
 	for i, c := range tcs {
@@ -182,12 +195,17 @@ func (h *TelegrafHandler) handleGetTelegrafs(w http.ResponseWriter, r *http.Requ
 		EncodeError(ctx, err, w)
 		return
 	}
-	tcs, _, err := h.TelegrafService.FindTelegrafConfigs(ctx, *filter)
+	opts, err := decodeFindOptions(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	tcs, _, err := h.TelegrafService.FindTelegrafConfigs(ctx, *filter, *opts)
 	if err != nil {
 		EncodeError(ctx, err, w)
 		return
 	}
-	if err := encodeResponse(ctx, w, http.StatusOK, newTelegrafResponses(ctx, tcs, h.LabelService)); err != nil {
+	if err := encodeResponse(ctx, w, http.StatusOK, newTelegrafResponses(ctx, *opts, *filter, tcs, h.LabelService)); err != nil {
 		logEncodingError(h.Logger, r, err)
 		return
 	}
@@ -209,13 +227,7 @@ func (h *TelegrafHandler) handleGetTelegraf(w http.ResponseWriter, r *http.Reque
 	offers := []string{"application/toml", "application/json", "application/octet-stream"}
 	defaultOffer := "application/toml"
 	mimeType := httputil.NegotiateContentType(r, offers, defaultOffer)
-	switch mimeType {
-	case "application/octet-stream":
-		w.Header().Set("Content-Type", "application/octet-stream")
-		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.toml\"", strings.Replace(strings.TrimSpace(tc.Name), " ", "_", -1)))
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(tc.TOML()))
-	case "application/json":
+	if mimeType == "application/json" {
 		labels, err := h.LabelService.FindResourceLabels(ctx, platform.LabelMappingFilter{ResourceID: tc.ID})
 		if err != nil {
 			EncodeError(ctx, err, w)
@@ -226,6 +238,25 @@ func (h *TelegrafHandler) handleGetTelegraf(w http.ResponseWriter, r *http.Reque
 			logEncodingError(h.Logger, r, err)
 			return
 		}
+		return
+	}
+
+	// Agents fetch the TOML, so record which version they run.
+	h.checkInTelegrafAgent(ctx, r, tc)
+
+	etag := telegrafETag(tc)
+	w.Header().Set("ETag", etag)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	switch mimeType {
+	case "application/octet-stream":
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.toml\"", strings.Replace(strings.TrimSpace(tc.Name), " ", "_", -1)))
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(tc.TOML()))
 	case "application/toml":
 		w.Header().Set("Content-Type", "application/toml; charset=utf-8")
 		w.WriteHeader(http.StatusOK)
@@ -233,6 +264,115 @@ func (h *TelegrafHandler) handleGetTelegraf(w http.ResponseWriter, r *http.Reque
 	}
 }
 
+// telegrafHostnameHeader is the header agents name their host with when
+// fetching their config, as an alternative to the hostname query parameter.
+const telegrafHostnameHeader = "Telegraf-Hostname"
+
+// checkInTelegrafAgent records that the agent fetching tc, if it names its
+// host, runs the current version of tc.
+func (h *TelegrafHandler) checkInTelegrafAgent(ctx context.Context, r *http.Request, tc *platform.TelegrafConfig) {
+	if h.TelegrafAgentService == nil {
+		return
+	}
+	hostname := r.URL.Query().Get("hostname")
+	if hostname == "" {
+		hostname = r.Header.Get(telegrafHostnameHeader)
+	}
+	if hostname == "" {
+		return
+	}
+
+	a := &platform.TelegrafAgent{
+		TelegrafID: tc.ID,
+		Hostname:   hostname,
+		Version:    tc.Version,
+		LastSeen:   time.Now().UTC(),
+	}
+	if err := h.TelegrafAgentService.CheckInTelegrafAgent(ctx, a); err != nil {
+		// Failing to record the agent must not keep it from its config.
+		h.Logger.Info("failed to check in telegraf agent", zap.String("hostname", hostname), zap.Error(err))
+	}
+}
+
+// telegrafETag returns the entity tag of the TOML of tc, which changes with
+// every version of tc.
+func telegrafETag(tc *platform.TelegrafConfig) string {
+	return fmt.Sprintf(`"%s-%d"`, tc.ID, tc.Version)
+}
+
+// etagMatches reports whether the If-None-Match header value ifNoneMatch
+// matches etag.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, t := range strings.Split(ifNoneMatch, ",") {
+		t = strings.TrimPrefix(strings.TrimSpace(t), "W/")
+		if t == etag || t == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+type telegrafAgentResponse struct {
+	*platform.TelegrafAgent
+	// Stale is true if the agent has not fetched the current version of the config.
+	Stale bool `json:"stale"`
+}
+
+type telegrafAgentsResponse struct {
+	Version int64                   `json:"version"`
+	Agents  []telegrafAgentResponse `json:"agents"`
+	Links   map[string]string       `json:"links"`
+}
+
+func newTelegrafAgentsResponse(tc *platform.TelegrafConfig, agents []*platform.TelegrafAgent) telegrafAgentsResponse {
+	res := telegrafAgentsResponse{
+		Version: tc.Version,
+		Agents:  make([]telegrafAgentResponse, 0, len(agents)),
+		Links: map[string]string{
+			"self":     fmt.Sprintf("/api/v2/telegrafs/%s/agents", tc.ID),
+			"telegraf": fmt.Sprintf("/api/v2/telegrafs/%s", tc.ID),
+		},
+	}
+	for _, a := range agents {
+		res.Agents = append(res.Agents, telegrafAgentResponse{
+			TelegrafAgent: a,
+			Stale:         a.Version < tc.Version,
+		})
+	}
+	return res
+}
+
+// handleGetTelegrafAgents is the HTTP handler for the GET /api/v2/telegrafs/:id/agents route.
+func (h *TelegrafHandler) handleGetTelegrafAgents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := decodeGetTelegrafRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	// Finding the config checks that the caller may read it.
+	tc, err := h.TelegrafService.FindTelegrafConfigByID(ctx, id)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	var agents []*platform.TelegrafAgent
+	if h.TelegrafAgentService != nil {
+		agents, err = h.TelegrafAgentService.FindTelegrafAgents(ctx, tc.ID)
+		if err != nil {
+			EncodeError(ctx, err, w)
+			return
+		}
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newTelegrafAgentsResponse(tc, agents)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
 func decodeTelegrafConfigFilter(ctx context.Context, r *http.Request) (*platform.TelegrafConfigFilter, error) {
 	f := &platform.TelegrafConfigFilter{}
 	urm, err := decodeUserResourceMappingFilter(ctx, r)
@@ -252,8 +392,14 @@ func decodeTelegrafConfigFilter(ctx context.Context, r *http.Request) (*platform
 		}
 		f.OrganizationID = orgID
 	} else if orgNameStr := q.Get("org"); orgNameStr != "" {
-		*f.Organization = orgNameStr
+		f.Organization = &orgNameStr
+	}
+
+	labels, lerr := decodeLabelSelector(q)
+	if lerr != nil {
+		return f, lerr
 	}
+	f.Labels = labels
 	return f, err
 }
 
@@ -13,6 +13,7 @@ import (
 	"go.uber.org/zap"
 
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
 	"github.com/influxdata/influxdb/mock"
 	"github.com/influxdata/influxdb/telegraf/plugins/inputs"
 	"github.com/influxdata/influxdb/telegraf/plugins/outputs"
@@ -71,6 +72,9 @@ func TestTelegrafHandler_handleGetTelegrafs(t *testing.T) {
 				contentType: "application/json; charset=utf-8",
 				body: `
 				{
+					"links": {
+						"self": "/api/v2/telegrafs?descending=false&limit=20&offset=0&orgID=0000000000000002"
+					},
 					"configurations":[
 					  {
 						"id":"0000000000000001",
@@ -129,8 +133,10 @@ func TestTelegrafHandler_handleGetTelegrafs(t *testing.T) {
 			wants: wants{
 				statusCode:  http.StatusOK,
 				contentType: "application/json; charset=utf-8",
-				// TODO(goller): once links are in for telegraf, this will need to change.
 				body: `{
+          "links": {
+            "self": "/api/v2/telegrafs?descending=false&limit=20&offset=0"
+          },
           "configurations": [
             {
             "id": "0000000000000001",
@@ -760,6 +766,9 @@ func Test_newTelegrafResponses(t *testing.T) {
 				},
 			},
 			want: `{
+        "links": {
+          "self": "/api/v2/telegrafs?descending=false&limit=20&offset=0"
+        },
         "configurations": [
           {
           "id": "0000000000000001",
@@ -797,7 +806,8 @@ func Test_newTelegrafResponses(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			ctx := context.Background()
-			res := newTelegrafResponses(ctx, tt.args.tcs, mock.NewLabelService())
+			opts := platform.FindOptions{Limit: platform.DefaultPageSize}
+			res := newTelegrafResponses(ctx, opts, platform.TelegrafConfigFilter{}, tt.args.tcs, mock.NewLabelService())
 			got, err := json.Marshal(res)
 			if err != nil {
 				t.Fatalf("newTelegrafResponses() JSON marshal %v", err)
@@ -888,3 +898,87 @@ func Test_newTelegrafResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestTelegrafHandler_agents(t *testing.T) {
+	s := inmem.NewService()
+	ctx := context.Background()
+
+	tc := &platform.TelegrafConfig{
+		OrganizationID: platform.ID(1),
+		Name:           "hosts",
+		Agent:          platform.TelegrafAgentConfig{Interval: 10000},
+		Plugins:        []platform.TelegrafPlugin{{Config: &inputs.CPUStats{}}},
+	}
+	if err := s.CreateTelegrafConfig(ctx, tc, platform.ID(2)); err != nil {
+		t.Fatal(err)
+	}
+
+	telegrafBackend := NewMockTelegrafBackend()
+	telegrafBackend.TelegrafService = s
+	telegrafBackend.TelegrafAgentService = s
+	h := NewTelegrafHandler(telegrafBackend)
+
+	get := func(path string, header http.Header) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", path, nil)
+		for k, v := range header {
+			r.Header[k] = v
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	w := get("/api/v2/telegrafs/"+tc.ID.String()+"?hostname=a", http.Header{"Accept": {"application/toml"}})
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status fetching config: %d", w.Code)
+	}
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("expected the config to have an ETag")
+	}
+
+	w = get("/api/v2/telegrafs/"+tc.ID.String(), http.Header{
+		"Accept":               {"application/toml"},
+		"If-None-Match":        {etag},
+		telegrafHostnameHeader: {"b"},
+	})
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Fatalf("expected an unchanged config not to be sent again, got status %d", w.Code)
+	}
+
+	if _, err := s.UpdateTelegrafConfig(ctx, tc.ID, tc, platform.ID(2)); err != nil {
+		t.Fatal(err)
+	}
+	w = get("/api/v2/telegrafs/"+tc.ID.String()+"?hostname=b", http.Header{
+		"Accept":        {"application/toml"},
+		"If-None-Match": {etag},
+	})
+	if w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+		t.Fatalf("expected an updated config to be sent with a new ETag, got status %d", w.Code)
+	}
+
+	w = get("/api/v2/telegrafs/"+tc.ID.String()+"/agents", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status listing agents: %d", w.Code)
+	}
+	var res struct {
+		Version int64 `json:"version"`
+		Agents  []struct {
+			Hostname string `json:"hostname"`
+			Version  int64  `json:"version"`
+			Stale    bool   `json:"stale"`
+		} `json:"agents"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Version != 2 || len(res.Agents) != 2 {
+		t.Fatalf("expected two agents of version 2 of the config, got %+v", res)
+	}
+	if a := res.Agents[0]; a.Hostname != "a" || a.Version != 1 || !a.Stale {
+		t.Errorf("expected agent a to run stale version 1, got %+v", a)
+	}
+	if a := res.Agents[1]; a.Hostname != "b" || a.Version != 2 || a.Stale {
+		t.Errorf("expected agent b to run version 2, got %+v", a)
+	}
+}
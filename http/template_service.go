@@ -0,0 +1,282 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	platform "github.com/influxdata/influxdb"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	templatesExportPath = "/api/v2/templates/export"
+	templatesApplyPath  = "/api/v2/templates/apply"
+)
+
+// TemplateBackend is all services and associated parameters required to construct
+// the TemplateHandler.
+type TemplateBackend struct {
+	Logger          *zap.Logger
+	TemplateService platform.TemplateService
+}
+
+// NewTemplateBackend returns a new instance of TemplateBackend. The
+// TemplateService is left to the caller, since it is built from the
+// authorized services of the other backends.
+func NewTemplateBackend(b *APIBackend) *TemplateBackend {
+	return &TemplateBackend{
+		Logger: b.Logger.With(zap.String("handler", "template")),
+	}
+}
+
+// TemplateHandler is the handler for exporting and applying org templates.
+type TemplateHandler struct {
+	*httprouter.Router
+
+	Logger *zap.Logger
+
+	TemplateService platform.TemplateService
+}
+
+// NewTemplateHandler creates a new TemplateHandler.
+func NewTemplateHandler(b *TemplateBackend) *TemplateHandler {
+	h := &TemplateHandler{
+		Router: NewRouter(),
+		Logger: b.Logger,
+
+		TemplateService: b.TemplateService,
+	}
+
+	h.HandlerFunc("GET", templatesExportPath, h.handleExportTemplate)
+	h.HandlerFunc("POST", templatesApplyPath, h.handleApplyTemplate)
+
+	return h
+}
+
+// isYAML reports whether a media type names YAML; templates are JSON otherwise.
+func isYAML(mediaType string) bool {
+	return strings.Contains(mediaType, "yaml")
+}
+
+func decodeTemplateOrgID(r *http.Request) (platform.ID, error) {
+	orgID := r.URL.Query().Get("orgID")
+	if orgID == "" {
+		return platform.InvalidID(), &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "orgID is required",
+		}
+	}
+
+	id, err := platform.IDFromString(orgID)
+	if err != nil {
+		return platform.InvalidID(), &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "invalid orgID",
+			Err:  err,
+		}
+	}
+	return *id, nil
+}
+
+func (h *TemplateHandler) handleExportTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	orgID, err := decodeTemplateOrgID(r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	t, err := h.TemplateService.ExportTemplate(ctx, orgID)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if !isYAML(r.Header.Get("Accept")) {
+		if err := encodeResponse(ctx, w, http.StatusOK, t); err != nil {
+			logEncodingError(h.Logger, r, err)
+		}
+		return
+	}
+
+	b, err := yaml.Marshal(t)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-yaml")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(b); err != nil {
+		logEncodingError(h.Logger, r, err)
+	}
+}
+
+type applyTemplateRequest struct {
+	orgID    platform.ID
+	template *platform.Template
+	opts     platform.TemplateApplyOptions
+}
+
+func decodeApplyTemplateRequest(ctx context.Context, r *http.Request) (*applyTemplateRequest, error) {
+	orgID, err := decodeTemplateOrgID(r)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &applyTemplateRequest{
+		orgID:    orgID,
+		template: &platform.Template{},
+	}
+
+	if dryRun := r.URL.Query().Get("dryRun"); dryRun != "" {
+		if req.opts.DryRun, err = strconv.ParseBool(dryRun); err != nil {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "invalid dryRun",
+				Err:  err,
+			}
+		}
+	}
+
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	if isYAML(r.Header.Get("Content-Type")) {
+		err = yaml.Unmarshal(b, req.template)
+	} else {
+		err = json.Unmarshal(b, req.template)
+	}
+	if err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "invalid template",
+			Err:  err,
+		}
+	}
+
+	return req, nil
+}
+
+type applyTemplateResponse struct {
+	DryRun  bool                      `json:"dryRun"`
+	Changes []platform.TemplateChange `json:"changes"`
+}
+
+func (h *TemplateHandler) handleApplyTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeApplyTemplateRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	changes, err := h.TemplateService.ApplyTemplate(ctx, req.orgID, req.template, req.opts)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	resp := applyTemplateResponse{
+		DryRun:  req.opts.DryRun,
+		Changes: changes,
+	}
+	if resp.Changes == nil {
+		resp.Changes = []platform.TemplateChange{}
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, resp); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// TemplateService exports and applies org templates over HTTP to the influxdb server.
+type TemplateService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+// ExportTemplate returns a template describing the resources of the organization orgID.
+func (s *TemplateService) ExportTemplate(ctx context.Context, orgID platform.ID) (*platform.Template, error) {
+	u, err := newURL(s.Addr, templatesExportPath)
+	if err != nil {
+		return nil, err
+	}
+	query := u.Query()
+	query.Set("orgID", orgID.String())
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var t platform.Template
+	if err := json.NewDecoder(resp.Body).Decode(&t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// ApplyTemplate applies t to the organization orgID and returns the changes made.
+func (s *TemplateService) ApplyTemplate(ctx context.Context, orgID platform.ID, t *platform.Template, opts platform.TemplateApplyOptions) ([]platform.TemplateChange, error) {
+	u, err := newURL(s.Addr, templatesApplyPath)
+	if err != nil {
+		return nil, err
+	}
+	query := u.Query()
+	query.Set("orgID", orgID.String())
+	query.Set("dryRun", strconv.FormatBool(opts.DryRun))
+	u.RawQuery = query.Encode()
+
+	octets, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(octets))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var ar applyTemplateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&ar); err != nil {
+		return nil, err
+	}
+	return ar.Changes, nil
+}
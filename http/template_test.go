@@ -0,0 +1,120 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/template"
+	"go.uber.org/zap"
+)
+
+func NewMockTemplateBackend(s *inmem.Service) *TemplateBackend {
+	return &TemplateBackend{
+		Logger: zap.NewNop().With(zap.String("handler", "template")),
+		TemplateService: &template.Service{
+			BucketService:    s,
+			LabelService:     s,
+			MacroService:     s,
+			DashboardService: s,
+			TaskService: &mock.TaskService{
+				FindTasksFn: func(context.Context, platform.TaskFilter) ([]*platform.Task, int, error) {
+					return nil, 0, nil
+				},
+			},
+			TelegrafService:           s,
+			ScraperTargetStoreService: s,
+		},
+	}
+}
+
+// withAuthorizer sets an authorizer on the requests to h, as the authentication handler does.
+func withAuthorizer(h http.Handler, a platform.Authorizer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, r.WithContext(pcontext.SetAuthorizer(r.Context(), a)))
+	})
+}
+
+func TestTemplateService(t *testing.T) {
+	s := inmem.NewService()
+	ctx := context.Background()
+
+	org := &platform.Organization{Name: "org"}
+	if err := s.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewTemplateHandler(NewMockTemplateBackend(s))
+	server := httptest.NewServer(withAuthorizer(h, &platform.Authorization{UserID: 1}))
+	defer server.Close()
+
+	client := &TemplateService{Addr: server.URL}
+
+	tmpl := &platform.Template{
+		Version: platform.TemplateVersion,
+		Labels:  []platform.TemplateLabel{{Name: "ops"}},
+		Buckets: []platform.TemplateBucket{{Name: "metrics", RetentionPeriod: "1h0m0s", Labels: []string{"ops"}}},
+	}
+
+	changes, err := client.ApplyTemplate(ctx, org.ID, tmpl, platform.TemplateApplyOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("unable to dry run template: %v", err)
+	}
+	if len(changes) != 2 || changes[1].Action != platform.TemplateActionCreate || changes[1].ID.Valid() {
+		t.Fatalf("expected a label and a bucket to be created, got %+v", changes)
+	}
+
+	if _, err := client.ApplyTemplate(ctx, org.ID, tmpl, platform.TemplateApplyOptions{}); err != nil {
+		t.Fatalf("unable to apply template: %v", err)
+	}
+
+	req, err := http.NewRequest("GET", server.URL+templatesExportPath+"?orgID="+org.ID.String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Accept", "application/x-yaml")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "application/x-yaml" {
+		t.Fatalf("expected a YAML template, got content type %q", ct)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var exported platform.Template
+	if err := yaml.Unmarshal(body, &exported); err != nil {
+		t.Fatalf("unable to decode exported template: %v", err)
+	}
+	if len(exported.Buckets) != 1 || exported.Buckets[0].Name != "metrics" || exported.Buckets[0].Labels[0] != "ops" {
+		t.Fatalf("unexpected exported template:\n%s", body)
+	}
+
+	req, err = http.NewRequest("POST", server.URL+templatesApplyPath+"?orgID="+org.ID.String(), strings.NewReader(string(body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/x-yaml")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := ioutil.ReadAll(resp.Body)
+		t.Fatalf("unable to apply YAML template: %d %s", resp.StatusCode, b)
+	}
+}
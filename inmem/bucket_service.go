@@ -176,6 +176,9 @@ func (s *Service) findBuckets(ctx context.Context, filter platform.BucketFilter,
 			}
 		}
 
+		if !s.labelFilter(ctx, filter.Labels)(b.ID) {
+			return []*platform.Bucket{}, nil
+		}
 		return []*platform.Bucket{b}, nil
 	}
 
@@ -207,7 +210,10 @@ func (s *Service) findBuckets(ctx context.Context, filter platform.BucketFilter,
 		}
 	}
 
-	bs, err := s.filterBuckets(ctx, filterFunc, opt...)
+	labeled := s.labelFilter(ctx, filter.Labels)
+	bs, err := s.filterBuckets(ctx, func(b *platform.Bucket) bool {
+		return filterFunc(b) && labeled(b.ID)
+	}, opt...)
 	if err != nil {
 		return nil, &platform.Error{
 			Err: err,
@@ -90,7 +90,7 @@ func (s *Service) forEachDashboard(ctx context.Context, opts platform.FindOption
 func (s *Service) FindDashboards(ctx context.Context, filter platform.DashboardFilter, opts platform.FindOptions) ([]*platform.Dashboard, int, error) {
 	var ds []*platform.Dashboard
 	op := OpPrefix + platform.OpFindDashboards
-	if len(filter.IDs) == 1 {
+	if len(filter.IDs) == 1 && filter.Labels.Empty() {
 		d, err := s.FindDashboardByID(ctx, *filter.IDs[0])
 		if err != nil && platform.ErrorCode(err) != platform.ENotFound {
 			return ds, 0, &platform.Error{
@@ -106,8 +106,9 @@ func (s *Service) FindDashboards(ctx context.Context, filter platform.DashboardF
 
 	var count int
 	filterFn := filterDashboardFn(filter)
+	labeled := s.labelFilter(ctx, filter.Labels)
 	err := s.forEachDashboard(ctx, opts, func(d *platform.Dashboard) bool {
-		if filterFn(d) {
+		if filterFn(d) && labeled(d.ID) {
 			if count >= opts.Offset {
 				ds = append(ds, d)
 			}
@@ -153,6 +154,10 @@ func (s *Service) PutDashboard(ctx context.Context, d *platform.Dashboard) error
 
 // PutCellView puts the view for a cell.
 func (s *Service) PutCellView(ctx context.Context, cell *platform.Cell) error {
+	if _, err := s.loadView(ctx, cell.ID); err == nil {
+		// Keep the view of a cell that already has one.
+		return nil
+	}
 	v := &platform.View{}
 	v.ID = cell.ID
 	return s.PutView(ctx, v)
@@ -230,7 +235,7 @@ func (s *Service) AddDashboardCell(ctx context.Context, id platform.ID, cell *pl
 		}
 	}
 	cell.ID = s.IDGenerator.ID()
-	if err := s.createCellView(ctx, cell); err != nil {
+	if err := s.createCellView(ctx, cell, opts.View); err != nil {
 		return &platform.Error{
 			Err: err,
 			Op:  op,
@@ -247,9 +252,11 @@ func (s *Service) AddDashboardCell(ctx context.Context, id platform.ID, cell *pl
 	return nil
 }
 
-func (s *Service) createCellView(ctx context.Context, cell *platform.Cell) *platform.Error {
-	// If not view exists create the view
-	view := &platform.View{}
+func (s *Service) createCellView(ctx context.Context, cell *platform.Cell, view *platform.View) *platform.Error {
+	if view == nil {
+		// If not view exists create the view
+		view = &platform.View{}
+	}
 	view.ID = cell.ID
 	if err := s.PutView(ctx, view); err != nil {
 		return &platform.Error{
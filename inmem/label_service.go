@@ -140,10 +140,26 @@ func (s *Service) FindResourceLabels(ctx context.Context, filter influxdb.LabelM
 	return ls, nil
 }
 
+// labelFilter returns a function reporting whether the resource with the
+// given ID carries the labels selected by sel.
+func (s *Service) labelFilter(ctx context.Context, sel influxdb.LabelSelector) func(influxdb.ID) bool {
+	return func(id influxdb.ID) bool {
+		if sel.Empty() {
+			return true
+		}
+
+		ls, err := s.FindResourceLabels(ctx, influxdb.LabelMappingFilter{ResourceID: id})
+		if err != nil {
+			return false
+		}
+		return sel.Matches(ls)
+	}
+}
+
 // CreateLabel creates a new label.
 func (s *Service) CreateLabel(ctx context.Context, l *influxdb.Label) error {
 	l.ID = s.IDGenerator.ID()
-	s.labelKV.Store(l.ID, *l)
+	s.labelKV.Store(l.ID.String(), *l)
 	return nil
 }
 
@@ -61,7 +61,7 @@ func (s *Service) FindMacros(ctx context.Context, filter platform.MacroFilter, o
 	op := OpPrefix + platform.OpFindMacros
 	var macros []*platform.Macro
 
-	if filter.ID != nil {
+	if filter.ID != nil && filter.Labels.Empty() {
 		m, err := s.FindMacroByID(ctx, *filter.ID)
 		if err != nil && platform.ErrorCode(err) != platform.ENotFound {
 			return macros, &platform.Error{
@@ -77,12 +77,13 @@ func (s *Service) FindMacros(ctx context.Context, filter platform.MacroFilter, o
 	}
 
 	filterFn := filterMacrosFn(filter)
+	labeled := s.labelFilter(ctx, filter.Labels)
 	s.macroKV.Range(func(k, v interface{}) bool {
 		macro, ok := v.(*platform.Macro)
 		if !ok {
 			return false
 		}
-		if filterFn(macro) {
+		if filterFn(macro) && labeled(macro.ID) {
 			macros = append(macros, macro)
 		}
 
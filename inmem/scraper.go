@@ -11,7 +11,10 @@ const (
 	errScraperTargetNotFound = "scraper target is not found"
 )
 
-var _ platform.ScraperTargetStoreService = (*Service)(nil)
+var (
+	_ platform.ScraperTargetStoreService = (*Service)(nil)
+	_ platform.ScraperStatusService      = (*Service)(nil)
+)
 
 func (s *Service) loadScraperTarget(id platform.ID) (*platform.ScraperTarget, *platform.Error) {
 	i, ok := s.scraperTargetKV.Load(id.String())
@@ -29,12 +32,23 @@ func (s *Service) loadScraperTarget(id platform.ID) (*platform.ScraperTarget, *p
 			Msg:  fmt.Sprintf("type %T is not a scraper target", i),
 		}
 	}
+	b.Status = s.loadScraperStatus(id)
 	return &b, nil
 }
 
-// ListTargets will list all scrape targets.
-func (s *Service) ListTargets(ctx context.Context) (list []platform.ScraperTarget, err error) {
-	list = make([]platform.ScraperTarget, 0)
+func (s *Service) loadScraperStatus(id platform.ID) *platform.ScraperStatus {
+	i, ok := s.scraperStatusKV.Load(id)
+	if !ok {
+		return nil
+	}
+	status := i.(platform.ScraperStatus)
+	return &status
+}
+
+// ListTargets will list scrape targets.
+// Additional options provide pagination & sorting.
+func (s *Service) ListTargets(ctx context.Context, opt ...platform.FindOptions) (list []platform.ScraperTarget, err error) {
+	var ps []platform.Pageable
 	s.scraperTargetKV.Range(func(_, v interface{}) bool {
 		b, ok := v.(platform.ScraperTarget)
 		if !ok {
@@ -44,10 +58,27 @@ func (s *Service) ListTargets(ctx context.Context) (list []platform.ScraperTarge
 			}
 			return false
 		}
-		list = append(list, b)
+		b.Status = s.loadScraperStatus(b.ID)
+		ps = append(ps, &b)
 		return true
 	})
-	return list, err
+	if err != nil {
+		return nil, err
+	}
+
+	var opts platform.FindOptions
+	if len(opt) > 0 {
+		opts = opt[0]
+	}
+	page, err := platform.Paginate(ps, opts)
+	if err != nil {
+		return nil, err
+	}
+	list = make([]platform.ScraperTarget, 0, len(page))
+	for _, t := range page {
+		list = append(list, *t.(*platform.ScraperTarget))
+	}
+	return list, nil
 }
 
 // AddTarget add a new scraper target into storage.
@@ -94,6 +125,7 @@ func (s *Service) RemoveTarget(ctx context.Context, id platform.ID) error {
 		}
 	}
 	s.scraperTargetKV.Delete(id.String())
+	s.scraperStatusKV.Delete(id)
 	err := s.deleteUserResourceMapping(ctx, platform.UserResourceMappingFilter{
 		ResourceID:   id,
 		ResourceType: platform.ScraperResourceType,
@@ -132,6 +164,7 @@ func (s *Service) UpdateTarget(ctx context.Context, update *platform.ScraperTarg
 	if !update.BucketID.Valid() {
 		update.BucketID = oldTarget.BucketID
 	}
+	update.Status = oldTarget.Status
 	if err = s.PutTarget(ctx, update); err != nil {
 		return nil, &platform.Error{
 			Op:  op,
@@ -156,6 +189,20 @@ func (s *Service) GetTargetByID(ctx context.Context, id platform.ID) (target *pl
 
 // PutTarget will put a scraper target without setting an ID.
 func (s *Service) PutTarget(ctx context.Context, target *platform.ScraperTarget) error {
-	s.scraperTargetKV.Store(target.ID.String(), *target)
+	t := *target
+	t.Status = nil
+	s.scraperTargetKV.Store(target.ID.String(), t)
+	return nil
+}
+
+// UpdateScraperStatus records the status of the last scrape of the target id.
+func (s *Service) UpdateScraperStatus(ctx context.Context, id platform.ID, status *platform.ScraperStatus) error {
+	if _, pe := s.loadScraperTarget(id); pe != nil {
+		return &platform.Error{
+			Op:  OpPrefix + platform.OpUpdateScraperStatus,
+			Err: pe,
+		}
+	}
+	s.scraperStatusKV.Store(id, *status)
 	return nil
 }
@@ -26,7 +26,9 @@ type Service struct {
 	labelKV               sync.Map
 	labelMappingKV        sync.Map
 	scraperTargetKV       sync.Map
+	scraperStatusKV       sync.Map
 	telegrafConfigKV      sync.Map
+	telegrafAgentKV       sync.Map
 	onboardingKV          sync.Map
 	basicAuthKV           sync.Map
 
@@ -2,11 +2,13 @@ package inmem
 
 import (
 	"context"
+	"sort"
 
 	platform "github.com/influxdata/influxdb"
 )
 
 var _ platform.TelegrafConfigStore = new(Service)
+var _ platform.TelegrafAgentService = new(Service)
 
 // FindTelegrafConfigByID returns a single telegraf config by ID.
 func (s *Service) FindTelegrafConfigByID(ctx context.Context, id platform.ID) (tc *platform.TelegrafConfig, err error) {
@@ -66,7 +68,11 @@ func (s *Service) findTelegrafConfigs(ctx context.Context, filter platform.Teleg
 	if len(m) == 0 {
 		return tcs, 0, nil
 	}
+	labeled := s.labelFilter(ctx, filter.Labels)
 	for _, item := range m {
+		if !labeled(item.ResourceID) {
+			continue
+		}
 		tc, err := s.findTelegrafConfigByID(ctx, item.ResourceID)
 		if err != nil && platform.ErrorCode(err) != platform.ENotFound {
 			return nil, 0, &platform.Error{
@@ -94,9 +100,28 @@ func (s *Service) FindTelegrafConfigs(ctx context.Context, filter platform.Teleg
 	tcs, n, pErr = s.findTelegrafConfigs(ctx, filter)
 	if pErr != nil {
 		pErr.Op = op
-		err = pErr
+		return tcs, n, pErr
+	}
+	if len(opt) == 0 {
+		return tcs, n, nil
+	}
+
+	ps := make([]platform.Pageable, 0, len(tcs))
+	for _, tc := range tcs {
+		ps = append(ps, tc)
+	}
+	page, err := platform.Paginate(ps, opt[0])
+	if err != nil {
+		return nil, 0, &platform.Error{
+			Op:  op,
+			Err: err,
+		}
+	}
+	tcs = make([]*platform.TelegrafConfig, 0, len(page))
+	for _, tc := range page {
+		tcs = append(tcs, tc.(*platform.TelegrafConfig))
 	}
-	return tcs, n, err
+	return tcs, len(tcs), nil
 }
 
 func (s *Service) putTelegrafConfig(ctx context.Context, tc *platform.TelegrafConfig) *platform.Error {
@@ -120,6 +145,7 @@ func (s *Service) putTelegrafConfig(ctx context.Context, tc *platform.TelegrafCo
 func (s *Service) CreateTelegrafConfig(ctx context.Context, tc *platform.TelegrafConfig, userID platform.ID) error {
 	op := OpPrefix + platform.OpCreateTelegrafConfig
 	tc.ID = s.IDGenerator.ID()
+	tc.Version = 1
 
 	pErr := s.putTelegrafConfig(ctx, tc)
 	if pErr != nil {
@@ -154,6 +180,7 @@ func (s *Service) UpdateTelegrafConfig(ctx context.Context, id platform.ID, tc *
 	tc.ID = id
 	// OrganizationID can not be updated
 	tc.OrganizationID = current.OrganizationID
+	tc.Version = current.Version + 1
 	pErr = s.putTelegrafConfig(ctx, tc)
 	if pErr != nil {
 		pErr.Op = op
@@ -178,6 +205,12 @@ func (s *Service) DeleteTelegrafConfig(ctx context.Context, id platform.ID) erro
 		return pErr
 	}
 	s.telegrafConfigKV.Delete(id)
+	s.telegrafAgentKV.Range(func(k, v interface{}) bool {
+		if v.(platform.TelegrafAgent).TelegrafID == id {
+			s.telegrafAgentKV.Delete(k)
+		}
+		return true
+	})
 
 	err = s.deleteUserResourceMapping(ctx, platform.UserResourceMappingFilter{
 		ResourceID:   id,
@@ -193,3 +226,44 @@ func (s *Service) DeleteTelegrafConfig(ctx context.Context, id platform.ID) erro
 	}
 	return nil
 }
+
+type telegrafAgentKey struct {
+	id       platform.ID
+	hostname string
+}
+
+// CheckInTelegrafAgent records that an agent fetched a version of a telegraf config.
+func (s *Service) CheckInTelegrafAgent(ctx context.Context, a *platform.TelegrafAgent) error {
+	op := OpPrefix + platform.OpCheckInTelegrafAgent
+	if !a.TelegrafID.Valid() {
+		return &platform.Error{
+			Op:   op,
+			Code: platform.EEmptyValue,
+			Err:  platform.ErrInvalidID,
+		}
+	}
+	if a.Hostname == "" {
+		return &platform.Error{
+			Op:   op,
+			Code: platform.EEmptyValue,
+			Msg:  "telegraf agent hostname is required",
+		}
+	}
+	s.telegrafAgentKV.Store(telegrafAgentKey{id: a.TelegrafID, hostname: a.Hostname}, *a)
+	return nil
+}
+
+// FindTelegrafAgents returns the agents that fetched the telegraf config id, by hostname.
+func (s *Service) FindTelegrafAgents(ctx context.Context, id platform.ID) ([]*platform.TelegrafAgent, error) {
+	agents := []*platform.TelegrafAgent{}
+	s.telegrafAgentKV.Range(func(k, v interface{}) bool {
+		if a := v.(platform.TelegrafAgent); a.TelegrafID == id {
+			agents = append(agents, &a)
+		}
+		return true
+	})
+	sort.Slice(agents, func(i, j int) bool {
+		return agents[i].Hostname < agents[j].Hostname
+	})
+	return agents, nil
+}
@@ -28,3 +28,7 @@ func initTelegrafStore(f platformtesting.TelegrafConfigFields, t *testing.T) (pl
 func TestTelegrafStore(t *testing.T) {
 	platformtesting.TelegrafConfigStore(initTelegrafStore, t)
 }
+
+func TestTelegrafAgentService(t *testing.T) {
+	platformtesting.TelegrafAgentService(initTelegrafStore, t)
+}
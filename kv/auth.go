@@ -0,0 +1,528 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+
+	influxdb "github.com/influxdata/influxdb"
+)
+
+var (
+	authBucket = []byte("authorizationsv1")
+	authIndex  = []byte("authorizationindexv1")
+)
+
+var _ influxdb.AuthorizationService = (*Service)(nil)
+
+func (s *Service) initializeAuths(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(authBucket); err != nil {
+		return err
+	}
+	if _, err := authIndexBucket(tx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindAuthorizationByID retrieves a authorization by id.
+func (s *Service) FindAuthorizationByID(ctx context.Context, id influxdb.ID) (*influxdb.Authorization, error) {
+	var a *influxdb.Authorization
+	var err error
+	err = s.kv.View(func(tx Tx) error {
+		var pe *influxdb.Error
+		a, pe = s.findAuthorizationByID(ctx, tx, id)
+		if pe != nil {
+			pe.Op = getOp(influxdb.OpFindAuthorizationByID)
+			err = pe
+		}
+		return err
+	})
+
+	return a, err
+}
+
+func (s *Service) findAuthorizationByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.Authorization, *influxdb.Error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(authBucket)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	v, err := b.Get(encodedID)
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  "authorization not found",
+		}
+	}
+
+	if err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	var a influxdb.Authorization
+	if err := decodeAuthorization(v, &a); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	return &a, nil
+}
+
+// FindAuthorizationByToken returns a authorization by token for a particular authorization.
+func (s *Service) FindAuthorizationByToken(ctx context.Context, n string) (*influxdb.Authorization, error) {
+	var a *influxdb.Authorization
+	var err error
+	err = s.kv.View(func(tx Tx) error {
+		var pe *influxdb.Error
+		a, pe = s.findAuthorizationByToken(ctx, tx, n)
+		if pe != nil {
+			pe.Op = getOp(influxdb.OpFindAuthorizationByToken)
+			err = pe
+		}
+		return err
+	})
+
+	return a, err
+}
+
+func (s *Service) findAuthorizationByToken(ctx context.Context, tx Tx, n string) (*influxdb.Authorization, *influxdb.Error) {
+	idx, err := authIndexBucket(tx)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	a, err := idx.Get(authIndexKey(n))
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  "authorization not found",
+		}
+	}
+
+	if err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	var id influxdb.ID
+	if err := id.Decode(a); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+	return s.findAuthorizationByID(ctx, tx, id)
+}
+
+func filterAuthorizationsFn(filter influxdb.AuthorizationFilter) func(a *influxdb.Authorization) bool {
+	if filter.ID != nil {
+		return func(a *influxdb.Authorization) bool {
+			return a.ID == *filter.ID
+		}
+	}
+
+	if filter.Token != nil {
+		return func(a *influxdb.Authorization) bool {
+			return a.Token == *filter.Token
+		}
+	}
+
+	if filter.UserID != nil {
+		return func(a *influxdb.Authorization) bool {
+			return a.UserID == *filter.UserID
+		}
+	}
+
+	return func(a *influxdb.Authorization) bool { return true }
+}
+
+// FindAuthorizations retrives all authorizations that match an arbitrary authorization filter.
+// Filters using ID, or Token should be efficient.
+// Other filters will do a linear scan across all authorizations searching for a match.
+func (s *Service) FindAuthorizations(ctx context.Context, filter influxdb.AuthorizationFilter, opt ...influxdb.FindOptions) ([]*influxdb.Authorization, int, error) {
+	if filter.ID != nil {
+		a, err := s.FindAuthorizationByID(ctx, *filter.ID)
+		if err != nil {
+			return nil, 0, &influxdb.Error{
+				Err: err,
+				Op:  getOp(influxdb.OpFindAuthorizations),
+			}
+		}
+
+		return []*influxdb.Authorization{a}, 1, nil
+	}
+
+	if filter.Token != nil {
+		a, err := s.FindAuthorizationByToken(ctx, *filter.Token)
+		if err != nil {
+			return nil, 0, &influxdb.Error{
+				Err: err,
+				Op:  getOp(influxdb.OpFindAuthorizations),
+			}
+		}
+
+		return []*influxdb.Authorization{a}, 1, nil
+	}
+
+	as := []*influxdb.Authorization{}
+	err := s.kv.View(func(tx Tx) error {
+		auths, err := s.findAuthorizations(ctx, tx, filter)
+		if err != nil {
+			return err
+		}
+		as = auths
+		return nil
+	})
+
+	if err != nil {
+		return nil, 0, &influxdb.Error{
+			Err: err,
+			Op:  getOp(influxdb.OpFindAuthorizations),
+		}
+	}
+
+	return as, len(as), nil
+}
+
+func (s *Service) findAuthorizations(ctx context.Context, tx Tx, f influxdb.AuthorizationFilter) ([]*influxdb.Authorization, error) {
+	// If the users name was provided, look up user by ID first
+	if f.User != nil {
+		u, err := s.findUserByName(ctx, tx, *f.User)
+		if err != nil {
+			return nil, err
+		}
+		f.UserID = &u.ID
+	}
+
+	as := []*influxdb.Authorization{}
+	filterFn := filterAuthorizationsFn(f)
+	err := s.forEachAuthorization(ctx, tx, func(a *influxdb.Authorization) bool {
+		if filterFn(a) {
+			as = append(as, a)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return as, nil
+}
+
+// CreateAuthorization creates a influxdb authorization and sets b.ID, and b.UserID if not provided.
+func (s *Service) CreateAuthorization(ctx context.Context, a *influxdb.Authorization) error {
+	op := getOp(influxdb.OpCreateAuthorization)
+	if err := a.Valid(); err != nil {
+		return &influxdb.Error{
+			Err: err,
+			Op:  op,
+		}
+	}
+
+	return s.kv.Update(func(tx Tx) error {
+		if _, pe := s.findUserByID(ctx, tx, a.UserID); pe != nil {
+			return influxdb.ErrUnableToCreateToken
+		}
+
+		if _, pe := s.findOrganizationByID(ctx, tx, a.OrgID); pe != nil {
+			return influxdb.ErrUnableToCreateToken
+		}
+
+		if err := s.uniqueAuthorizationToken(ctx, tx, a); err != nil {
+			return influxdb.ErrUnableToCreateToken
+		}
+
+		if a.Token == "" {
+			token, err := s.TokenGenerator.Token()
+			if err != nil {
+				return &influxdb.Error{
+					Err: err,
+					Op:  op,
+				}
+			}
+			a.Token = token
+		}
+
+		a.ID = s.IDGenerator.ID()
+
+		if pe := s.putAuthorization(ctx, tx, a); pe != nil {
+			pe.Op = op
+			return pe
+		}
+
+		return nil
+	})
+}
+
+// PutAuthorization will put a authorization without setting an ID.
+func (s *Service) PutAuthorization(ctx context.Context, a *influxdb.Authorization) (err error) {
+	return s.kv.Update(func(tx Tx) error {
+		pe := s.putAuthorization(ctx, tx, a)
+		if pe != nil {
+			err = pe
+		}
+		return err
+	})
+}
+
+func encodeAuthorization(a *influxdb.Authorization) ([]byte, error) {
+	switch a.Status {
+	case influxdb.Active, influxdb.Inactive:
+	case "":
+		a.Status = influxdb.Active
+	default:
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unknown authorization status",
+		}
+	}
+
+	return json.Marshal(a)
+}
+
+func (s *Service) putAuthorization(ctx context.Context, tx Tx, a *influxdb.Authorization) *influxdb.Error {
+	v, err := encodeAuthorization(a)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	encodedID, err := a.ID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Err:  err,
+		}
+	}
+
+	idx, err := authIndexBucket(tx)
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	if err := idx.Put(authIndexKey(a.Token), encodedID); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(authBucket)
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	if err := b.Put(encodedID, v); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+func authIndexKey(n string) []byte {
+	return []byte(n)
+}
+
+func authIndexBucket(tx Tx) (Bucket, error) {
+	b, err := tx.Bucket([]byte(authIndex))
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	return b, nil
+}
+
+func decodeAuthorization(b []byte, a *influxdb.Authorization) error {
+	if err := json.Unmarshal(b, a); err != nil {
+		return err
+	}
+	if a.Status == "" {
+		a.Status = influxdb.Active
+	}
+	return nil
+}
+
+// forEachAuthorization will iterate through all authorizations while fn returns true.
+func (s *Service) forEachAuthorization(ctx context.Context, tx Tx, fn func(*influxdb.Authorization) bool) error {
+	b, err := tx.Bucket(authBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		a := &influxdb.Authorization{}
+
+		if err := decodeAuthorization(v, a); err != nil {
+			return err
+		}
+		if !fn(a) {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) uniqueAuthorizationToken(ctx context.Context, tx Tx, a *influxdb.Authorization) error {
+	idx, err := authIndexBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	_, err = idx.Get(authIndexKey(a.Token))
+	// if not found then this is unique.
+	if IsNotFound(err) {
+		return nil
+	}
+
+	// no error means this is not unique
+	if err == nil {
+		return &influxdb.Error{
+			Code: influxdb.EConflict,
+			Msg:  "token already exists",
+		}
+	}
+
+	// any other error is some sort of internal server error
+	return &influxdb.Error{
+		Code: influxdb.EInternal,
+		Err:  err,
+	}
+}
+
+// DeleteAuthorization deletes a authorization and prunes it from the index.
+func (s *Service) DeleteAuthorization(ctx context.Context, id influxdb.ID) error {
+	return s.kv.Update(func(tx Tx) (err error) {
+		pe := s.deleteAuthorization(ctx, tx, id)
+		if pe != nil {
+			pe.Op = getOp(influxdb.OpDeleteAuthorization)
+			err = pe
+		}
+		return err
+	})
+}
+
+func (s *Service) deleteAuthorization(ctx context.Context, tx Tx, id influxdb.ID) *influxdb.Error {
+	a, pe := s.findAuthorizationByID(ctx, tx, id)
+	if pe != nil {
+		return pe
+	}
+
+	idx, err := authIndexBucket(tx)
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	if err := idx.Delete(authIndexKey(a.Token)); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	encodedID, err := id.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	b, err := tx.Bucket(authBucket)
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	if err := b.Delete(encodedID); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+// SetAuthorizationStatus updates the status of the authorization. Useful
+// for setting an authorization to inactive or active.
+func (s *Service) SetAuthorizationStatus(ctx context.Context, id influxdb.ID, status influxdb.Status) error {
+	return s.kv.Update(func(tx Tx) error {
+		if pe := s.updateAuthorization(ctx, tx, id, status); pe != nil {
+			return &influxdb.Error{
+				Err: pe,
+				Op:  influxdb.OpSetAuthorizationStatus,
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Service) updateAuthorization(ctx context.Context, tx Tx, id influxdb.ID, status influxdb.Status) *influxdb.Error {
+	a, pe := s.findAuthorizationByID(ctx, tx, id)
+	if pe != nil {
+		return pe
+	}
+
+	a.Status = status
+	v, err := encodeAuthorization(a)
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	encodedID, err := id.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	b, err := tx.Bucket(authBucket)
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	if err = b.Put(encodedID, v); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+	return nil
+}
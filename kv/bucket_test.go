@@ -0,0 +1,77 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	influxdb "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	platformtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltBucketService(t *testing.T) {
+	platformtesting.BucketService(initBoltBucketService, t)
+}
+
+func TestInmemBucketService(t *testing.T) {
+	platformtesting.BucketService(initInmemBucketService, t)
+}
+
+func initBoltBucketService(f platformtesting.BucketFields, t *testing.T) (influxdb.BucketService, string, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	svc, op, closeSvc := initBucketService(s, f, t)
+	return svc, op, func() {
+		closeSvc()
+		closeBolt()
+	}
+}
+
+func initInmemBucketService(f platformtesting.BucketFields, t *testing.T) (influxdb.BucketService, string, func()) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	svc, op, closeSvc := initBucketService(s, f, t)
+	return svc, op, func() {
+		closeSvc()
+		closeInmem()
+	}
+}
+
+func initBucketService(s kv.Store, f platformtesting.BucketFields, t *testing.T) (influxdb.BucketService, string, func()) {
+	svc := kv.NewService(s)
+	svc.IDGenerator = f.IDGenerator
+
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing bucket service: %v", err)
+	}
+
+	for _, o := range f.Organizations {
+		if err := svc.PutOrganization(ctx, o); err != nil {
+			t.Fatalf("failed to populate organizations")
+		}
+	}
+	for _, b := range f.Buckets {
+		if err := svc.PutBucket(ctx, b); err != nil {
+			t.Fatalf("failed to populate buckets")
+		}
+	}
+	return svc, kv.OpPrefix, func() {
+		for _, o := range f.Organizations {
+			if err := svc.DeleteOrganization(ctx, o.ID); err != nil {
+				t.Logf("failed to remove organization: %v", err)
+			}
+		}
+		for _, b := range f.Buckets {
+			if err := svc.DeleteBucket(ctx, b.ID); err != nil {
+				t.Logf("failed to remove bucket: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	influxdb "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	platformtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltKeyValueLog(t *testing.T) {
+	platformtesting.KeyValueLog(initBoltKeyValueLog, t)
+}
+
+func TestInmemKeyValueLog(t *testing.T) {
+	platformtesting.KeyValueLog(initInmemKeyValueLog, t)
+}
+
+func initBoltKeyValueLog(f platformtesting.KeyValueLogFields, t *testing.T) (influxdb.KeyValueLog, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	svc, closeSvc := initKeyValueLog(s, f, t)
+	return svc, func() {
+		closeSvc()
+		closeBolt()
+	}
+}
+
+func initInmemKeyValueLog(f platformtesting.KeyValueLogFields, t *testing.T) (influxdb.KeyValueLog, func()) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	svc, closeSvc := initKeyValueLog(s, f, t)
+	return svc, func() {
+		closeSvc()
+		closeInmem()
+	}
+}
+
+func initKeyValueLog(s kv.Store, f platformtesting.KeyValueLogFields, t *testing.T) (influxdb.KeyValueLog, func()) {
+	svc := kv.NewService(s)
+
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing key value log: %v", err)
+	}
+
+	for _, e := range f.LogEntries {
+		if err := svc.AddLogEntry(ctx, e.Key, e.Value, e.Time); err != nil {
+			t.Fatalf("failed to populate log entries")
+		}
+	}
+	return svc, func() {}
+}
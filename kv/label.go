@@ -0,0 +1,635 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	influxdb "github.com/influxdata/influxdb"
+)
+
+var (
+	labelBucket        = []byte("labelsv1")
+	labelMappingBucket = []byte("labelmappingsv1")
+	// labelResourceIndex maps label ID + resource ID to the resource type.
+	labelResourceIndex = []byte("labelresourcesv1")
+)
+
+var _ influxdb.LabelService = (*Service)(nil)
+var _ influxdb.LabelResourceService = (*Service)(nil)
+
+func (s *Service) initializeLabels(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(labelBucket); err != nil {
+		return err
+	}
+
+	if _, err := tx.Bucket(labelMappingBucket); err != nil {
+		return err
+	}
+
+	if _, err := tx.Bucket(labelResourceIndex); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindLabelByID finds a label by its ID
+func (s *Service) FindLabelByID(ctx context.Context, id influxdb.ID) (*influxdb.Label, error) {
+	var l *influxdb.Label
+
+	err := s.kv.View(func(tx Tx) error {
+		label, pe := s.findLabelByID(ctx, tx, id)
+		if pe != nil {
+			return pe
+		}
+		l = label
+		return nil
+	})
+
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  getOp(influxdb.OpFindLabelByID),
+			Err: err,
+		}
+	}
+
+	return l, nil
+}
+
+func (s *Service) findLabelByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.Label, *influxdb.Error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	b, err := tx.Bucket(labelBucket)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	v, err := b.Get(encodedID)
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Err:  influxdb.ErrLabelNotFound,
+		}
+	}
+
+	if err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	var l influxdb.Label
+	if err := json.Unmarshal(v, &l); err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return &l, nil
+}
+
+func filterLabelsFn(filter influxdb.LabelFilter) func(l *influxdb.Label) bool {
+	return func(label *influxdb.Label) bool {
+		return (filter.Name == "" || (filter.Name == label.Name))
+	}
+}
+
+// FindLabels returns a list of labels that match a filter.
+func (s *Service) FindLabels(ctx context.Context, filter influxdb.LabelFilter, opt ...influxdb.FindOptions) ([]*influxdb.Label, error) {
+	ls := []*influxdb.Label{}
+	err := s.kv.View(func(tx Tx) error {
+		labels, err := s.findLabels(ctx, tx, filter)
+		if err != nil {
+			return err
+		}
+		ls = labels
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ls, nil
+}
+
+func (s *Service) findLabels(ctx context.Context, tx Tx, filter influxdb.LabelFilter) ([]*influxdb.Label, error) {
+	ls := []*influxdb.Label{}
+	filterFn := filterLabelsFn(filter)
+	err := s.forEachLabel(ctx, tx, func(l *influxdb.Label) bool {
+		if filterFn(l) {
+			ls = append(ls, l)
+		}
+		return true
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ls, nil
+}
+
+func decodeLabelMappingKey(key []byte) (resourceID influxdb.ID, labelID influxdb.ID, err error) {
+	if len(key) != 2*influxdb.IDLength {
+		return 0, 0, &influxdb.Error{Code: influxdb.EInvalid, Msg: "malformed label mapping key (please report this error)"}
+	}
+
+	if err := (&resourceID).Decode(key[:influxdb.IDLength]); err != nil {
+		return 0, 0, &influxdb.Error{Code: influxdb.EInvalid, Msg: "bad resource id", Err: influxdb.ErrInvalidID}
+	}
+
+	if err := (&labelID).Decode(key[influxdb.IDLength:]); err != nil {
+		return 0, 0, &influxdb.Error{Code: influxdb.EInvalid, Msg: "bad label id", Err: influxdb.ErrInvalidID}
+	}
+
+	return resourceID, labelID, nil
+}
+
+// FindResourceLabels returns a list of labels that are mapped to a resource.
+func (s *Service) FindResourceLabels(ctx context.Context, filter influxdb.LabelMappingFilter) ([]*influxdb.Label, error) {
+	if !filter.ResourceID.Valid() {
+		return nil, &influxdb.Error{Code: influxdb.EInvalid, Msg: "filter requires a valid resource id", Err: influxdb.ErrInvalidID}
+	}
+
+	ls := []*influxdb.Label{}
+	err := s.kv.View(func(tx Tx) error {
+		idx, err := tx.Bucket(labelMappingBucket)
+		if err != nil {
+			return err
+		}
+
+		cur, err := idx.Cursor()
+		if err != nil {
+			return err
+		}
+
+		prefix, err := filter.ResourceID.Encode()
+		if err != nil {
+			return err
+		}
+
+		for k, _ := cur.Seek(prefix); bytes.HasPrefix(k, prefix); k, _ = cur.Next() {
+			_, id, err := decodeLabelMappingKey(k)
+			if err != nil {
+				return err
+			}
+
+			l, pe := s.findLabelByID(ctx, tx, id)
+			if l == nil && pe != nil {
+				// TODO(jm): return error instead of continuing once orphaned mappings are fixed
+				// (see https://github.com/influxdata/influxdb/issues/11278)
+				continue
+			}
+
+			ls = append(ls, l)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+			Op:  getOp(influxdb.OpFindLabelMapping),
+		}
+	}
+
+	return ls, nil
+}
+
+// FindLabelResources returns the IDs of the resources of type rt carrying the labels selected by sel.
+func (s *Service) FindLabelResources(ctx context.Context, rt influxdb.ResourceType, sel influxdb.LabelSelector) ([]influxdb.ID, error) {
+	if sel.Empty() {
+		return nil, &influxdb.Error{Code: influxdb.EInvalid, Msg: "selector requires a label"}
+	}
+
+	var rids []influxdb.ID
+	err := s.kv.View(func(tx Tx) error {
+		var err error
+		rids, err = s.labeledResources(ctx, tx, rt, sel)
+		return err
+	})
+
+	if err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+			Op:  getOp(influxdb.OpFindLabelMapping),
+		}
+	}
+
+	return rids, nil
+}
+
+// CreateLabelMapping creates a new mapping between a resource and a label.
+func (s *Service) CreateLabelMapping(ctx context.Context, m *influxdb.LabelMapping) error {
+	_, err := s.FindLabelByID(ctx, m.LabelID)
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+			Op:  getOp(influxdb.OpCreateLabel),
+		}
+	}
+
+	err = s.kv.Update(func(tx Tx) error {
+		return s.putLabelMapping(ctx, tx, m)
+	})
+
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+			Op:  getOp(influxdb.OpCreateLabel),
+		}
+	}
+
+	return nil
+}
+
+// DeleteLabelMapping deletes a label mapping.
+func (s *Service) DeleteLabelMapping(ctx context.Context, m *influxdb.LabelMapping) error {
+	err := s.kv.Update(func(tx Tx) error {
+		return s.deleteLabelMapping(ctx, tx, m)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  getOp(influxdb.OpDeleteLabelMapping),
+			Err: err,
+		}
+	}
+	return nil
+}
+
+func (s *Service) deleteLabelMapping(ctx context.Context, tx Tx, m *influxdb.LabelMapping) error {
+	key, err := labelMappingKey(m)
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	idx, err := tx.Bucket(labelMappingBucket)
+	if err != nil {
+		return err
+	}
+
+	if err := idx.Delete(key); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	ridx, err := tx.Bucket(labelResourceIndex)
+	if err != nil {
+		return err
+	}
+
+	if err := ridx.Delete(labelResourceIndexKey(key)); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+// CreateLabel creates a new label.
+func (s *Service) CreateLabel(ctx context.Context, l *influxdb.Label) error {
+	err := s.kv.Update(func(tx Tx) error {
+		l.ID = s.IDGenerator.ID()
+
+		return s.putLabel(ctx, tx, l)
+	})
+
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+			Op:  getOp(influxdb.OpCreateLabel),
+		}
+	}
+	return nil
+}
+
+// PutLabel creates a label from the provided struct, without generating a new ID.
+func (s *Service) PutLabel(ctx context.Context, l *influxdb.Label) error {
+	return s.kv.Update(func(tx Tx) error {
+		return s.putLabel(ctx, tx, l)
+	})
+}
+
+func labelMappingKey(m *influxdb.LabelMapping) ([]byte, error) {
+	lid, err := m.LabelID.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	rid, err := m.ResourceID.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	key := make([]byte, len(rid)+len(lid))
+	copy(key, rid)
+	copy(key[len(rid):], lid)
+
+	return key, nil
+}
+
+func (s *Service) forEachLabel(ctx context.Context, tx Tx, fn func(*influxdb.Label) bool) error {
+	b, err := tx.Bucket(labelBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		l := &influxdb.Label{}
+		if err := json.Unmarshal(v, l); err != nil {
+			return err
+		}
+		if !fn(l) {
+			break
+		}
+	}
+
+	return nil
+}
+
+// UpdateLabel updates a label.
+func (s *Service) UpdateLabel(ctx context.Context, id influxdb.ID, upd influxdb.LabelUpdate) (*influxdb.Label, error) {
+	var label *influxdb.Label
+	err := s.kv.Update(func(tx Tx) error {
+		labelResponse, pe := s.updateLabel(ctx, tx, id, upd)
+		if pe != nil {
+			return &influxdb.Error{
+				Err: pe,
+				Op:  getOp(influxdb.OpUpdateLabel),
+			}
+		}
+		label = labelResponse
+		return nil
+	})
+
+	return label, err
+}
+
+func (s *Service) updateLabel(ctx context.Context, tx Tx, id influxdb.ID, upd influxdb.LabelUpdate) (*influxdb.Label, error) {
+	label, pe := s.findLabelByID(ctx, tx, id)
+	if pe != nil {
+		return nil, pe
+	}
+
+	if label.Properties == nil {
+		label.Properties = make(map[string]string)
+	}
+
+	for k, v := range upd.Properties {
+		if v == "" {
+			delete(label.Properties, k)
+		} else {
+			label.Properties[k] = v
+		}
+	}
+
+	if err := label.Validate(); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	if err := s.putLabel(ctx, tx, label); err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return label, nil
+}
+
+// set a label and overwrite any existing label
+func (s *Service) putLabel(ctx context.Context, tx Tx, l *influxdb.Label) error {
+	v, err := json.Marshal(l)
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	encodedID, err := l.ID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	b, err := tx.Bucket(labelBucket)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Put(encodedID, v); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+// PutLabelMapping writes a label mapping to the store.
+func (s *Service) PutLabelMapping(ctx context.Context, m *influxdb.LabelMapping) error {
+	return s.kv.Update(func(tx Tx) error {
+		return s.putLabelMapping(ctx, tx, m)
+	})
+}
+
+func (s *Service) putLabelMapping(ctx context.Context, tx Tx, m *influxdb.LabelMapping) error {
+	v, err := json.Marshal(m)
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	key, err := labelMappingKey(m)
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	idx, err := tx.Bucket(labelMappingBucket)
+	if err != nil {
+		return err
+	}
+
+	if err := idx.Put(key, v); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	ridx, err := tx.Bucket(labelResourceIndex)
+	if err != nil {
+		return err
+	}
+
+	if err := ridx.Put(labelResourceIndexKey(key), []byte(m.ResourceType)); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+// DeleteLabel deletes a label.
+func (s *Service) DeleteLabel(ctx context.Context, id influxdb.ID) error {
+	err := s.kv.Update(func(tx Tx) error {
+		return s.deleteLabel(ctx, tx, id)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  getOp(influxdb.OpDeleteLabel),
+			Err: err,
+		}
+	}
+	return nil
+}
+
+func (s *Service) deleteLabel(ctx context.Context, tx Tx, id influxdb.ID) error {
+	if _, pe := s.findLabelByID(ctx, tx, id); pe != nil {
+		return pe
+	}
+
+	encodedID, idErr := id.Encode()
+	if idErr != nil {
+		return &influxdb.Error{
+			Err: idErr,
+		}
+	}
+
+	b, err := tx.Bucket(labelBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Delete(encodedID)
+}
+
+// labelResourceIndexKey returns the index key of the label mapping with key mappingKey.
+// Mappings are keyed by resource ID then label ID; the index reverses the two.
+func labelResourceIndexKey(mappingKey []byte) []byte {
+	k := make([]byte, len(mappingKey))
+	copy(k, mappingKey[influxdb.IDLength:])
+	copy(k[influxdb.IDLength:], mappingKey[:influxdb.IDLength])
+	return k
+}
+
+// labeledResources returns the IDs of the resources of type rt, or of any type
+// if rt is empty, carrying the labels selected by sel, which must not be empty.
+// Resources are looked up in the label resource index, once per selected label.
+func (s *Service) labeledResources(ctx context.Context, tx Tx, rt influxdb.ResourceType, sel influxdb.LabelSelector) ([]influxdb.ID, error) {
+
+	labelIDs := map[string][]influxdb.ID{}
+	for _, name := range sel.Names {
+		labelIDs[name] = nil
+	}
+	err := s.forEachLabel(ctx, tx, func(l *influxdb.Label) bool {
+		if ids, ok := labelIDs[l.Name]; ok {
+			labelIDs[l.Name] = append(ids, l.ID)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	idx, err := tx.Bucket(labelResourceIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	cur, err := idx.Cursor()
+	if err != nil {
+		return nil, err
+	}
+
+	// matches counts the selected label names carried by each resource.
+	matches := map[influxdb.ID]int{}
+	for _, ids := range labelIDs {
+		carrying := map[influxdb.ID]bool{}
+		for _, id := range ids {
+			prefix, err := id.Encode()
+			if err != nil {
+				return nil, err
+			}
+
+			for k, v := cur.Seek(prefix); bytes.HasPrefix(k, prefix); k, v = cur.Next() {
+				if rt != "" && influxdb.ResourceType(v) != rt {
+					continue
+				}
+
+				var rid influxdb.ID
+				if err := rid.Decode(k[influxdb.IDLength:]); err != nil {
+					return nil, err
+				}
+				carrying[rid] = true
+			}
+		}
+
+		for rid := range carrying {
+			matches[rid]++
+		}
+	}
+
+	want := len(labelIDs)
+	if sel.Any {
+		want = 1
+	}
+	var rids []influxdb.ID
+	for rid, n := range matches {
+		if n >= want {
+			rids = append(rids, rid)
+		}
+	}
+	return rids, nil
+}
+
+// labelFilter returns a function reporting whether the resource with the
+// given ID carries the labels selected by sel.
+func (s *Service) labelFilter(ctx context.Context, tx Tx, sel influxdb.LabelSelector) (func(influxdb.ID) bool, error) {
+	if sel.Empty() {
+		return func(influxdb.ID) bool { return true }, nil
+	}
+
+	rids, err := s.labeledResources(ctx, tx, "", sel)
+	if err != nil {
+		return nil, err
+	}
+
+	labeled := make(map[influxdb.ID]bool, len(rids))
+	for _, rid := range rids {
+		labeled[rid] = true
+	}
+	return func(id influxdb.ID) bool {
+		return labeled[id]
+	}, nil
+}
@@ -0,0 +1,74 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	influxdb "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	platformtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltLabelService(t *testing.T) {
+	platformtesting.LabelService(initBoltLabelService, t)
+}
+
+func TestInmemLabelService(t *testing.T) {
+	platformtesting.LabelService(initInmemLabelService, t)
+}
+
+func initBoltLabelService(f platformtesting.LabelFields, t *testing.T) (influxdb.LabelService, string, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	svc, op, closeSvc := initLabelService(s, f, t)
+	return svc, op, func() {
+		closeSvc()
+		closeBolt()
+	}
+}
+
+func initInmemLabelService(f platformtesting.LabelFields, t *testing.T) (influxdb.LabelService, string, func()) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	svc, op, closeSvc := initLabelService(s, f, t)
+	return svc, op, func() {
+		closeSvc()
+		closeInmem()
+	}
+}
+
+func initLabelService(s kv.Store, f platformtesting.LabelFields, t *testing.T) (influxdb.LabelService, string, func()) {
+	svc := kv.NewService(s)
+	svc.IDGenerator = f.IDGenerator
+
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing label service: %v", err)
+	}
+
+	for _, l := range f.Labels {
+		if err := svc.PutLabel(ctx, l); err != nil {
+			t.Fatalf("failed to populate labels: %v", err)
+		}
+	}
+
+	for _, m := range f.Mappings {
+		if err := svc.PutLabelMapping(ctx, m); err != nil {
+			t.Fatalf("failed to populate label mappings: %v", err)
+		}
+	}
+
+	return svc, kv.OpPrefix, func() {
+		for _, l := range f.Labels {
+			if err := svc.DeleteLabel(ctx, l.ID); err != nil {
+				t.Logf("failed to remove label: %v", err)
+			}
+		}
+	}
+}
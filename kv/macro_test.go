@@ -0,0 +1,68 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	influxdb "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	platformtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltMacroService(t *testing.T) {
+	platformtesting.MacroService(initBoltMacroService, t)
+}
+
+func TestInmemMacroService(t *testing.T) {
+	platformtesting.MacroService(initInmemMacroService, t)
+}
+
+func initBoltMacroService(f platformtesting.MacroFields, t *testing.T) (influxdb.MacroService, string, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	svc, op, closeSvc := initMacroService(s, f, t)
+	return svc, op, func() {
+		closeSvc()
+		closeBolt()
+	}
+}
+
+func initInmemMacroService(f platformtesting.MacroFields, t *testing.T) (influxdb.MacroService, string, func()) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	svc, op, closeSvc := initMacroService(s, f, t)
+	return svc, op, func() {
+		closeSvc()
+		closeInmem()
+	}
+}
+
+func initMacroService(s kv.Store, f platformtesting.MacroFields, t *testing.T) (influxdb.MacroService, string, func()) {
+	svc := kv.NewService(s)
+	svc.IDGenerator = f.IDGenerator
+
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing macro service: %v", err)
+	}
+
+	for _, macro := range f.Macros {
+		if err := svc.ReplaceMacro(ctx, macro); err != nil {
+			t.Fatalf("failed to populate macros: %v", err)
+		}
+	}
+
+	return svc, kv.OpPrefix, func() {
+		for _, macro := range f.Macros {
+			if err := svc.DeleteMacro(ctx, macro.ID); err != nil {
+				t.Logf("failed to remove macro: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	influxdb "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	platformtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltOrganizationService(t *testing.T) {
+	platformtesting.OrganizationService(initBoltOrganizationService, t)
+}
+
+func TestInmemOrganizationService(t *testing.T) {
+	platformtesting.OrganizationService(initInmemOrganizationService, t)
+}
+
+func initBoltOrganizationService(f platformtesting.OrganizationFields, t *testing.T) (influxdb.OrganizationService, string, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	svc, op, closeSvc := initOrganizationService(s, f, t)
+	return svc, op, func() {
+		closeSvc()
+		closeBolt()
+	}
+}
+
+func initInmemOrganizationService(f platformtesting.OrganizationFields, t *testing.T) (influxdb.OrganizationService, string, func()) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	svc, op, closeSvc := initOrganizationService(s, f, t)
+	return svc, op, func() {
+		closeSvc()
+		closeInmem()
+	}
+}
+
+func initOrganizationService(s kv.Store, f platformtesting.OrganizationFields, t *testing.T) (influxdb.OrganizationService, string, func()) {
+	svc := kv.NewService(s)
+	svc.IDGenerator = f.IDGenerator
+
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing organization service: %v", err)
+	}
+
+	for _, o := range f.Organizations {
+		if err := svc.PutOrganization(ctx, o); err != nil {
+			t.Fatalf("failed to populate organizations")
+		}
+	}
+	return svc, kv.OpPrefix, func() {
+		for _, o := range f.Organizations {
+			if err := svc.DeleteOrganization(ctx, o.ID); err != nil {
+				t.Logf("failed to remove organizations: %v", err)
+			}
+		}
+	}
+}
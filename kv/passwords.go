@@ -0,0 +1,89 @@
+package kv
+
+import (
+	"context"
+
+	influxdb "github.com/influxdata/influxdb"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	userpasswordBucket = []byte("userspasswordv1")
+)
+
+var _ influxdb.BasicAuthService = (*Service)(nil)
+
+// HashCost currently using the default cost of bcrypt
+var HashCost = bcrypt.DefaultCost
+
+// SetPassword stores the password hash associated with a user.
+func (s *Service) SetPassword(ctx context.Context, name string, password string) error {
+	return s.kv.Update(func(tx Tx) error {
+		return s.setPassword(ctx, tx, name, password)
+	})
+}
+
+func (s *Service) setPassword(ctx context.Context, tx Tx, name string, password string) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), HashCost)
+	if err != nil {
+		return err
+	}
+
+	u, pe := s.findUserByName(ctx, tx, name)
+	if pe != nil {
+		return pe
+	}
+
+	encodedID, err := u.ID.Encode()
+	if err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(userpasswordBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(encodedID, hash)
+}
+
+// ComparePassword compares a provided password with the stored password hash.
+func (s *Service) ComparePassword(ctx context.Context, name string, password string) error {
+	return s.kv.View(func(tx Tx) error {
+		return s.comparePassword(ctx, tx, name, password)
+	})
+}
+
+func (s *Service) comparePassword(ctx context.Context, tx Tx, name string, password string) error {
+	u, pe := s.findUserByName(ctx, tx, name)
+	if pe != nil {
+		return pe
+	}
+
+	encodedID, err := u.ID.Encode()
+	if err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(userpasswordBucket)
+	if err != nil {
+		return err
+	}
+
+	hash, err := b.Get(encodedID)
+	if err != nil {
+		return err
+	}
+
+	return bcrypt.CompareHashAndPassword(hash, []byte(password))
+}
+
+// CompareAndSetPassword replaces the old password with the new password if thee old password is correct.
+func (s *Service) CompareAndSetPassword(ctx context.Context, name string, old string, new string) error {
+	return s.kv.Update(func(tx Tx) error {
+		if err := s.comparePassword(ctx, tx, name, old); err != nil {
+			return err
+		}
+		return s.setPassword(ctx, tx, name, new)
+	})
+}
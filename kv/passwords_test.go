@@ -0,0 +1,75 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	influxdb "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	platformtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltBasicAuth(t *testing.T) {
+	platformtesting.BasicAuth(initBoltBasicAuthService, t)
+}
+
+func TestInmemBasicAuth(t *testing.T) {
+	platformtesting.BasicAuth(initInmemBasicAuthService, t)
+}
+
+func TestBoltBasicAuth_CompareAndSet(t *testing.T) {
+	platformtesting.CompareAndSetPassword(initBoltBasicAuthService, t)
+}
+
+func TestInmemBasicAuth_CompareAndSet(t *testing.T) {
+	platformtesting.CompareAndSetPassword(initInmemBasicAuthService, t)
+}
+
+func initBoltBasicAuthService(f platformtesting.UserFields, t *testing.T) (influxdb.BasicAuthService, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	svc, closeSvc := initBasicAuthService(s, f, t)
+	return svc, func() {
+		closeSvc()
+		closeBolt()
+	}
+}
+
+func initInmemBasicAuthService(f platformtesting.UserFields, t *testing.T) (influxdb.BasicAuthService, func()) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	svc, closeSvc := initBasicAuthService(s, f, t)
+	return svc, func() {
+		closeSvc()
+		closeInmem()
+	}
+}
+
+func initBasicAuthService(s kv.Store, f platformtesting.UserFields, t *testing.T) (influxdb.BasicAuthService, func()) {
+	svc := kv.NewService(s)
+	svc.IDGenerator = f.IDGenerator
+
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing basic auth service: %v", err)
+	}
+
+	for _, u := range f.Users {
+		if err := svc.PutUser(ctx, u); err != nil {
+			t.Fatalf("failed to populate users")
+		}
+	}
+	return svc, func() {
+		for _, u := range f.Users {
+			if err := svc.DeleteUser(ctx, u.ID); err != nil {
+				t.Logf("failed to remove users: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	influxdb "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	platformtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltSecretService(t *testing.T) {
+	platformtesting.SecretService(initBoltSecretService, t)
+}
+
+func TestInmemSecretService(t *testing.T) {
+	platformtesting.SecretService(initInmemSecretService, t)
+}
+
+func initBoltSecretService(f platformtesting.SecretServiceFields, t *testing.T) (influxdb.SecretService, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	svc, closeSvc := initSecretService(s, f, t)
+	return svc, func() {
+		closeSvc()
+		closeBolt()
+	}
+}
+
+func initInmemSecretService(f platformtesting.SecretServiceFields, t *testing.T) (influxdb.SecretService, func()) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	svc, closeSvc := initSecretService(s, f, t)
+	return svc, func() {
+		closeSvc()
+		closeInmem()
+	}
+}
+
+func initSecretService(s kv.Store, f platformtesting.SecretServiceFields, t *testing.T) (influxdb.SecretService, func()) {
+	svc := kv.NewService(s)
+
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing secret service: %v", err)
+	}
+
+	for _, sec := range f.Secrets {
+		for k, v := range sec.Env {
+			if err := svc.PutSecret(ctx, sec.OrganizationID, k, v); err != nil {
+				t.Fatalf("failed to populate secrets")
+			}
+		}
+	}
+	return svc, func() {}
+}
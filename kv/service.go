@@ -0,0 +1,96 @@
+package kv
+
+import (
+	"context"
+	"time"
+
+	influxdb "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/rand"
+	"github.com/influxdata/influxdb/snowflake"
+	"go.uber.org/zap"
+)
+
+// OpPrefix is the prefix for kv errors.
+const OpPrefix = "kv/"
+
+func getOp(op string) string {
+	return OpPrefix + op
+}
+
+// Service is the struct that influxdb services are implemented on.
+// All of its state is stored in the provided kv.Store, which means that
+// any backend that implements kv.Store can host the services.
+type Service struct {
+	kv     Store
+	Logger *zap.Logger
+
+	IDGenerator    influxdb.IDGenerator
+	TokenGenerator influxdb.TokenGenerator
+	time           func() time.Time
+}
+
+// NewService returns an instance of a Service.
+func NewService(kv Store) *Service {
+	return &Service{
+		Logger:         zap.NewNop(),
+		IDGenerator:    snowflake.NewIDGenerator(),
+		TokenGenerator: rand.NewTokenGenerator(64),
+		kv:             kv,
+		time:           time.Now,
+	}
+}
+
+// WithTime sets the function for computing the current time. Used for updating meta data
+// about objects stored. Should only be used in tests for mocking.
+func (s *Service) WithTime(fn func() time.Time) {
+	s.time = fn
+}
+
+// WithStore sets kv store for the service.
+// Should only be used in tests for mocking.
+func (s *Service) WithStore(store Store) {
+	s.kv = store
+}
+
+// Initialize creates Buckets needed.
+func (s *Service) Initialize(ctx context.Context) error {
+	return s.kv.Update(func(tx Tx) error {
+		if err := s.initializeAuths(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeBuckets(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeDashboards(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeKVLog(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeLabels(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeMacros(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeOrgs(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeSecrets(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeURMs(ctx, tx); err != nil {
+			return err
+		}
+
+		return s.initializeUsers(ctx, tx)
+	})
+}
@@ -0,0 +1,733 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	influxdb "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/snowflake"
+	"github.com/influxdata/influxdb/task/backend"
+	"github.com/influxdata/influxdb/task/options"
+)
+
+// The task store is laid out in the following flat buckets:
+//
+//	tasksv1/tasks           key(:task_id) -> Content of submitted task (i.e. flux code).
+//	tasksv1/task_meta       key(:task_id) -> Protocol Buffer encoded backend.StoreTaskMeta.
+//	tasksv1/org_by_task_id  key(:task_id) -> The encoded organization ID associated with the task.
+//	tasksv1/user_by_task_id key(:task_id) -> The encoded user ID associated with the task.
+//	tasksv1/name_by_task_id key(:task_id) -> The user-supplied name of the script.
+//	tasksv1/orgs            key(:org_id:task_id) -> The encoded task ID; allows for lookup from org to tasks.
+//	tasksv1/users           key(:user_id:task_id) -> The encoded task ID; allows for lookup from user to tasks.
+var (
+	taskBucket          = []byte("tasksv1/tasks")
+	taskMetaBucket      = []byte("tasksv1/task_meta")
+	taskOrgByTaskIndex  = []byte("tasksv1/org_by_task_id")
+	taskUserByTaskIndex = []byte("tasksv1/user_by_task_id")
+	taskNameByTaskIndex = []byte("tasksv1/name_by_task_id")
+	taskOrgTasksIndex   = []byte("tasksv1/orgs")
+	taskUserTasksIndex  = []byte("tasksv1/users")
+)
+
+var (
+	errTaskRunNotFound   = errors.New("run not found")
+	errTaskIndexNotFound = errors.New("task index not found")
+)
+
+var _ backend.Store = (*TaskStore)(nil)
+
+// TaskStore is a task store built on a Store.
+type TaskStore struct {
+	kv    Store
+	idGen influxdb.IDGenerator
+}
+
+// NewTaskStore returns an instance of a TaskStore backed by kv.
+func NewTaskStore(kv Store) *TaskStore {
+	return &TaskStore{
+		kv:    kv,
+		idGen: snowflake.NewDefaultIDGenerator(),
+	}
+}
+
+// Initialize creates the buckets used by the task store.
+func (s *TaskStore) Initialize(ctx context.Context) error {
+	return s.kv.Update(func(tx Tx) error {
+		for _, b := range [][]byte{
+			taskBucket, taskMetaBucket,
+			taskOrgByTaskIndex, taskUserByTaskIndex, taskNameByTaskIndex,
+			taskOrgTasksIndex, taskUserTasksIndex,
+		} {
+			if _, err := tx.Bucket(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func taskIndexKey(ownerID, taskID []byte) []byte {
+	key := make([]byte, 0, len(ownerID)+len(taskID))
+	key = append(key, ownerID...)
+	key = append(key, taskID...)
+	return key
+}
+
+// taskBuckets holds the buckets of the task store for the lifetime of a single transaction.
+type taskBuckets struct {
+	tasks, meta, orgByTask, userByTask, nameByTask, orgTasks, userTasks Bucket
+}
+
+func openTaskBuckets(tx Tx) (*taskBuckets, error) {
+	var err error
+	b := &taskBuckets{}
+	for _, p := range []struct {
+		bucket *Bucket
+		name   []byte
+	}{
+		{&b.tasks, taskBucket},
+		{&b.meta, taskMetaBucket},
+		{&b.orgByTask, taskOrgByTaskIndex},
+		{&b.userByTask, taskUserByTaskIndex},
+		{&b.nameByTask, taskNameByTaskIndex},
+		{&b.orgTasks, taskOrgTasksIndex},
+		{&b.userTasks, taskUserTasksIndex},
+	} {
+		if *p.bucket, err = tx.Bucket(p.name); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// getString returns the value at k as a string, or the empty string when the key does not exist.
+func getString(b Bucket, k []byte) (string, error) {
+	v, err := b.Get(k)
+	if IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(v), nil
+}
+
+func getID(b Bucket, k []byte) (influxdb.ID, error) {
+	var id influxdb.ID
+	v, err := b.Get(k)
+	if err != nil {
+		return id, err
+	}
+	if err := id.Decode(v); err != nil {
+		return id, err
+	}
+	return id, nil
+}
+
+func (b *taskBuckets) findMeta(encodedID []byte) (*backend.StoreTaskMeta, error) {
+	v, err := b.meta.Get(encodedID)
+	if IsNotFound(err) {
+		return nil, backend.ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var stm backend.StoreTaskMeta
+	if err := stm.Unmarshal(v); err != nil {
+		return nil, err
+	}
+	return &stm, nil
+}
+
+func (b *taskBuckets) putMeta(encodedID []byte, stm *backend.StoreTaskMeta) error {
+	v, err := stm.Marshal()
+	if err != nil {
+		return err
+	}
+	return b.meta.Put(encodedID, v)
+}
+
+func (b *taskBuckets) findTask(id influxdb.ID) (*backend.StoreTask, error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	script, err := b.tasks.Get(encodedID)
+	if IsNotFound(err) {
+		return nil, backend.ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	t := &backend.StoreTask{
+		ID:     id,
+		Script: string(script),
+	}
+
+	if t.User, err = getID(b.userByTask, encodedID); err != nil {
+		return nil, err
+	}
+	if t.Org, err = getID(b.orgByTask, encodedID); err != nil {
+		return nil, err
+	}
+	if t.Name, err = getString(b.nameByTask, encodedID); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// deleteTask removes the task and all of its index entries.
+func (b *taskBuckets) deleteTask(encodedID []byte) error {
+	org, err := b.orgByTask.Get(encodedID)
+	if err != nil && !IsNotFound(err) {
+		return err
+	}
+	if len(org) > 0 {
+		if err := b.orgTasks.Delete(taskIndexKey(org, encodedID)); err != nil {
+			return err
+		}
+	}
+
+	user, err := b.userByTask.Get(encodedID)
+	if err != nil && !IsNotFound(err) {
+		return err
+	}
+	if len(user) > 0 {
+		if err := b.userTasks.Delete(taskIndexKey(user, encodedID)); err != nil {
+			return err
+		}
+	}
+
+	for _, bkt := range []Bucket{b.tasks, b.meta, b.orgByTask, b.userByTask, b.nameByTask} {
+		if err := bkt.Delete(encodedID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateTask creates a task in the task store.
+func (s *TaskStore) CreateTask(ctx context.Context, req backend.CreateTaskRequest) (influxdb.ID, error) {
+	o, err := backend.StoreValidator.CreateArgs(req)
+	if err != nil {
+		return influxdb.InvalidID(), err
+	}
+
+	id := s.idGen.ID()
+	err = s.kv.Update(func(tx Tx) error {
+		b, err := openTaskBuckets(tx)
+		if err != nil {
+			return err
+		}
+
+		encodedID, err := id.Encode()
+		if err != nil {
+			return err
+		}
+		encodedOrg, err := req.Org.Encode()
+		if err != nil {
+			return err
+		}
+		encodedUser, err := req.User.Encode()
+		if err != nil {
+			return err
+		}
+
+		if err := b.tasks.Put(encodedID, []byte(req.Script)); err != nil {
+			return err
+		}
+		if err := b.nameByTask.Put(encodedID, []byte(o.Name)); err != nil {
+			return err
+		}
+		if err := b.orgTasks.Put(taskIndexKey(encodedOrg, encodedID), encodedID); err != nil {
+			return err
+		}
+		if err := b.orgByTask.Put(encodedID, encodedOrg); err != nil {
+			return err
+		}
+		if err := b.userTasks.Put(taskIndexKey(encodedUser, encodedID), encodedID); err != nil {
+			return err
+		}
+		if err := b.userByTask.Put(encodedID, encodedUser); err != nil {
+			return err
+		}
+
+		stm := backend.NewStoreTaskMeta(req, o)
+		return b.putMeta(encodedID, &stm)
+	})
+
+	if err != nil {
+		return influxdb.InvalidID(), err
+	}
+
+	return id, nil
+}
+
+// UpdateTask updates the script, name and status of an existing task.
+func (s *TaskStore) UpdateTask(ctx context.Context, req backend.UpdateTaskRequest) (backend.UpdateTaskResult, error) {
+	var res backend.UpdateTaskResult
+	op, err := backend.StoreValidator.UpdateArgs(req)
+	if err != nil {
+		return res, err
+	}
+
+	encodedID, err := req.ID.Encode()
+	if err != nil {
+		return res, err
+	}
+
+	err = s.kv.Update(func(tx Tx) error {
+		b, err := openTaskBuckets(tx)
+		if err != nil {
+			return err
+		}
+
+		t, err := b.findTask(req.ID)
+		if err != nil {
+			return err
+		}
+		res.OldScript = t.Script
+		if res.OldScript == "" {
+			return errors.New("task script not stored properly")
+		}
+
+		var newScript string
+		if !req.Options.IsZero() || req.Script != "" {
+			if err = req.UpdateFlux(res.OldScript); err != nil {
+				return err
+			}
+			newScript = req.Script
+		}
+		if req.Script == "" {
+			// Need to build op from existing script.
+			op, err = options.FromScript(res.OldScript)
+			if err != nil {
+				return err
+			}
+			newScript = res.OldScript
+		} else {
+			op, err = options.FromScript(req.Script)
+			if err != nil {
+				return err
+			}
+			if err := b.tasks.Put(encodedID, []byte(req.Script)); err != nil {
+				return err
+			}
+			if err := b.nameByTask.Put(encodedID, []byte(op.Name)); err != nil {
+				return err
+			}
+		}
+
+		stm, err := b.findMeta(encodedID)
+		if err != nil {
+			return err
+		}
+		res.OldStatus = backend.TaskStatus(stm.Status)
+		if req.Status != "" {
+			stm.Status = string(req.Status)
+			if err := b.putMeta(encodedID, stm); err != nil {
+				return err
+			}
+		}
+		res.NewMeta = *stm
+
+		res.NewTask = backend.StoreTask{
+			ID:     req.ID,
+			Org:    t.Org,
+			User:   t.User,
+			Name:   op.Name,
+			Script: newScript,
+		}
+
+		return nil
+	})
+	return res, err
+}
+
+// ListTasks lists the tasks based on a filter.
+func (s *TaskStore) ListTasks(ctx context.Context, params backend.TaskSearchParams) ([]backend.StoreTaskWithMeta, error) {
+	if params.Org.Valid() && params.User.Valid() {
+		return nil, errors.New("ListTasks: org and user filters are mutually exclusive")
+	}
+
+	if params.PageSize < 0 {
+		return nil, errors.New("ListTasks: PageSize must be positive")
+	}
+	if params.PageSize > influxdb.TaskMaxPageSize {
+		return nil, fmt.Errorf("ListTasks: PageSize exceeds maximum of %d", influxdb.TaskMaxPageSize)
+	}
+	lim := params.PageSize
+	if lim == 0 {
+		lim = influxdb.TaskDefaultPageSize
+	}
+
+	var tasks []backend.StoreTaskWithMeta
+	err := s.kv.View(func(tx Tx) error {
+		b, err := openTaskBuckets(tx)
+		if err != nil {
+			return err
+		}
+
+		// When filtering by org or user, walk the matching index where keys are
+		// prefixed by the owner ID and the value is the task ID. Otherwise walk
+		// the task bucket directly where the key is the task ID.
+		var prefix []byte
+		idx := b.tasks
+		switch {
+		case params.Org.Valid():
+			if prefix, err = params.Org.Encode(); err != nil {
+				return err
+			}
+			idx = b.orgTasks
+		case params.User.Valid():
+			if prefix, err = params.User.Encode(); err != nil {
+				return err
+			}
+			idx = b.userTasks
+		}
+
+		cur, err := idx.Cursor()
+		if err != nil {
+			return err
+		}
+
+		var k, v []byte
+		if params.After.Valid() {
+			encodedAfter, err := params.After.Encode()
+			if err != nil {
+				return err
+			}
+			after := taskIndexKey(prefix, encodedAfter)
+			k, v = cur.Seek(after)
+			if bytes.Equal(k, after) {
+				k, v = cur.Next()
+			}
+		} else if len(prefix) > 0 {
+			k, v = cur.Seek(prefix)
+		} else {
+			k, v = cur.First()
+		}
+
+		taskIDs := make([]influxdb.ID, 0, lim)
+		for ; k != nil && bytes.HasPrefix(k, prefix) && len(taskIDs) < lim; k, v = cur.Next() {
+			encodedID := k
+			if len(prefix) > 0 {
+				encodedID = v
+			}
+
+			var id influxdb.ID
+			if err := id.Decode(encodedID); err != nil {
+				return err
+			}
+			taskIDs = append(taskIDs, id)
+		}
+
+		tasks = make([]backend.StoreTaskWithMeta, len(taskIDs))
+		for i, id := range taskIDs {
+			// TODO(docmerlin): optimization: don't check <-ctx.Done() every time though the loop
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+
+			t, err := b.findTask(id)
+			if err != nil {
+				return err
+			}
+			tasks[i].Task = *t
+
+			encodedID, err := id.Encode()
+			if err != nil {
+				return err
+			}
+			stm, err := b.findMeta(encodedID)
+			if err != nil {
+				return err
+			}
+			tasks[i].Meta = *stm
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// FindTaskByID finds a task with a given an ID.  It will return nil if the task does not exist.
+func (s *TaskStore) FindTaskByID(ctx context.Context, id influxdb.ID) (*backend.StoreTask, error) {
+	var t *backend.StoreTask
+	err := s.kv.View(func(tx Tx) error {
+		b, err := openTaskBuckets(tx)
+		if err != nil {
+			return err
+		}
+
+		t, err = b.findTask(id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// FindTaskMetaByID returns the metadata about a task.
+func (s *TaskStore) FindTaskMetaByID(ctx context.Context, id influxdb.ID) (*backend.StoreTaskMeta, error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	var stm *backend.StoreTaskMeta
+	err = s.kv.View(func(tx Tx) error {
+		b, err := openTaskBuckets(tx)
+		if err != nil {
+			return err
+		}
+
+		stm, err = b.findMeta(encodedID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stm, nil
+}
+
+// FindTaskByIDWithMeta combines finding the task and the meta into a single call.
+func (s *TaskStore) FindTaskByIDWithMeta(ctx context.Context, id influxdb.ID) (*backend.StoreTask, *backend.StoreTaskMeta, error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var t *backend.StoreTask
+	var stm *backend.StoreTaskMeta
+	err = s.kv.View(func(tx Tx) error {
+		b, err := openTaskBuckets(tx)
+		if err != nil {
+			return err
+		}
+
+		if t, err = b.findTask(id); err != nil {
+			return err
+		}
+
+		stm, err = b.findMeta(encodedID)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return t, stm, nil
+}
+
+// DeleteTask deletes the task.
+func (s *TaskStore) DeleteTask(ctx context.Context, id influxdb.ID) (deleted bool, err error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return false, err
+	}
+
+	err = s.kv.Update(func(tx Tx) error {
+		b, err := openTaskBuckets(tx)
+		if err != nil {
+			return err
+		}
+
+		if _, err := b.tasks.Get(encodedID); IsNotFound(err) {
+			return backend.ErrTaskNotFound
+		} else if err != nil {
+			return err
+		}
+
+		return b.deleteTask(encodedID)
+	})
+	if err != nil {
+		if err == backend.ErrTaskNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// CreateNextRun creates the earliest needed run scheduled no later than the given Unix timestamp now.
+func (s *TaskStore) CreateNextRun(ctx context.Context, taskID influxdb.ID, now int64) (backend.RunCreation, error) {
+	var rc backend.RunCreation
+
+	encodedID, err := taskID.Encode()
+	if err != nil {
+		return rc, err
+	}
+
+	err = s.kv.Update(func(tx Tx) error {
+		b, err := openTaskBuckets(tx)
+		if err != nil {
+			return err
+		}
+
+		stm, err := b.findMeta(encodedID)
+		if err != nil {
+			return err
+		}
+
+		rc, err = stm.CreateNextRun(now, func() (influxdb.ID, error) {
+			return s.idGen.ID(), nil
+		})
+		if err != nil {
+			return err
+		}
+		rc.Created.TaskID = taskID
+
+		return b.putMeta(encodedID, stm)
+	})
+	if err != nil {
+		return backend.RunCreation{}, err
+	}
+
+	return rc, nil
+}
+
+// FinishRun removes runID from the list of running tasks and if its `now` is later then last completed update it.
+func (s *TaskStore) FinishRun(ctx context.Context, taskID, runID influxdb.ID) error {
+	encodedID, err := taskID.Encode()
+	if err != nil {
+		return err
+	}
+
+	return s.kv.Update(func(tx Tx) error {
+		b, err := openTaskBuckets(tx)
+		if err != nil {
+			return err
+		}
+
+		stm, err := b.findMeta(encodedID)
+		if err != nil {
+			return err
+		}
+
+		if !stm.FinishRun(runID) {
+			return errTaskRunNotFound
+		}
+
+		return b.putMeta(encodedID, stm)
+	})
+}
+
+// ManuallyRunTimeRange enqueues a request to run the task with the given ID for all schedules no earlier than start and no later than end.
+func (s *TaskStore) ManuallyRunTimeRange(_ context.Context, taskID influxdb.ID, start, end, requestedAt int64) (*backend.StoreTaskMetaManualRun, error) {
+	encodedID, err := taskID.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	var mRun *backend.StoreTaskMetaManualRun
+	err = s.kv.Update(func(tx Tx) error {
+		b, err := openTaskBuckets(tx)
+		if err != nil {
+			return err
+		}
+
+		stm, err := b.findMeta(encodedID)
+		if err != nil {
+			return err
+		}
+
+		makeID := func() (influxdb.ID, error) { return s.idGen.ID(), nil }
+		if err := stm.ManuallyRunTimeRange(start, end, requestedAt, makeID); err != nil {
+			return err
+		}
+		mRun = stm.ManualRuns[len(stm.ManualRuns)-1]
+
+		return b.putMeta(encodedID, stm)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mRun, nil
+}
+
+// DeleteUser synchronously deletes a user and all their tasks from the task store.
+func (s *TaskStore) DeleteUser(ctx context.Context, id influxdb.ID) error {
+	err := s.deleteOwnerTasks(ctx, id, taskUserTasksIndex)
+	if err == errTaskIndexNotFound {
+		return backend.ErrUserNotFound
+	}
+	return err
+}
+
+// DeleteOrg synchronously deletes an org and all their tasks from the task store.
+func (s *TaskStore) DeleteOrg(ctx context.Context, id influxdb.ID) error {
+	err := s.deleteOwnerTasks(ctx, id, taskOrgTasksIndex)
+	if err == errTaskIndexNotFound {
+		return backend.ErrOrgNotFound
+	}
+	return err
+}
+
+// deleteOwnerTasks deletes all tasks found under the ownerID prefix of the index bucket.
+func (s *TaskStore) deleteOwnerTasks(ctx context.Context, ownerID influxdb.ID, index []byte) error {
+	prefix, err := ownerID.Encode()
+	if err != nil {
+		return err
+	}
+
+	return s.kv.Update(func(tx Tx) error {
+		b, err := openTaskBuckets(tx)
+		if err != nil {
+			return err
+		}
+
+		idx, err := tx.Bucket(index)
+		if err != nil {
+			return err
+		}
+
+		cur, err := idx.Cursor()
+		if err != nil {
+			return err
+		}
+
+		// Collect the IDs first so the index is not modified while it is being walked.
+		var taskIDs [][]byte
+		for k, v := cur.Seek(prefix); bytes.HasPrefix(k, prefix); k, v = cur.Next() {
+			taskIDs = append(taskIDs, append([]byte(nil), v...))
+		}
+		if len(taskIDs) == 0 {
+			return errTaskIndexNotFound
+		}
+
+		for i, encodedID := range taskIDs {
+			// check for cancelation every 256 tasks deleted
+			if i&0xFF == 0 {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				default:
+				}
+			}
+
+			if err := b.deleteTask(encodedID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Close is a no-op; the lifecycle of the underlying Store is owned by the caller.
+func (s *TaskStore) Close() error {
+	return nil
+}
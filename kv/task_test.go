@@ -0,0 +1,64 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb/kv"
+	_ "github.com/influxdata/influxdb/query/builtin"
+	"github.com/influxdata/influxdb/task/backend"
+	"github.com/influxdata/influxdb/task/backend/storetest"
+	"github.com/influxdata/influxdb/task/options"
+)
+
+func init() {
+	// TODO(mr): remove as part of https://github.com/influxdata/platform/issues/484.
+	options.EnableScriptCacheForTest()
+}
+
+func TestBoltTaskStore(t *testing.T) {
+	var closeFn func()
+	storetest.NewStoreTest(
+		"kv bolt task store",
+		func(t *testing.T) backend.Store {
+			s, closeBolt, err := NewTestBoltStore()
+			if err != nil {
+				t.Fatalf("failed to create new kv store: %v", err)
+			}
+			closeFn = closeBolt
+			return initTaskStore(s, t)
+		},
+		func(t *testing.T, s backend.Store) {
+			if err := s.Close(); err != nil {
+				t.Error(err)
+			}
+			closeFn()
+		},
+	)(t)
+}
+
+func TestInmemTaskStore(t *testing.T) {
+	storetest.NewStoreTest(
+		"kv inmem task store",
+		func(t *testing.T) backend.Store {
+			s, _, err := NewTestInmemStore()
+			if err != nil {
+				t.Fatalf("failed to create new kv store: %v", err)
+			}
+			return initTaskStore(s, t)
+		},
+		func(t *testing.T, s backend.Store) {
+			if err := s.Close(); err != nil {
+				t.Error(err)
+			}
+		},
+	)(t)
+}
+
+func initTaskStore(s kv.Store, t *testing.T) backend.Store {
+	ts := kv.NewTaskStore(s)
+	if err := ts.Initialize(context.Background()); err != nil {
+		t.Fatalf("error initializing task store: %v", err)
+	}
+	return ts
+}
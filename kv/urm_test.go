@@ -0,0 +1,83 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	influxdb "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	platformtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltUserResourceMappingService_FindUserResourceMappings(t *testing.T) {
+	platformtesting.FindUserResourceMappings(initBoltUserResourceMappingService, t)
+}
+
+func TestInmemUserResourceMappingService_FindUserResourceMappings(t *testing.T) {
+	platformtesting.FindUserResourceMappings(initInmemUserResourceMappingService, t)
+}
+
+func TestBoltUserResourceMappingService_CreateUserResourceMapping(t *testing.T) {
+	platformtesting.CreateUserResourceMapping(initBoltUserResourceMappingService, t)
+}
+
+func TestInmemUserResourceMappingService_CreateUserResourceMapping(t *testing.T) {
+	platformtesting.CreateUserResourceMapping(initInmemUserResourceMappingService, t)
+}
+
+func TestBoltUserResourceMappingService_DeleteUserResourceMapping(t *testing.T) {
+	platformtesting.DeleteUserResourceMapping(initBoltUserResourceMappingService, t)
+}
+
+func TestInmemUserResourceMappingService_DeleteUserResourceMapping(t *testing.T) {
+	platformtesting.DeleteUserResourceMapping(initInmemUserResourceMappingService, t)
+}
+
+func initBoltUserResourceMappingService(f platformtesting.UserResourceFields, t *testing.T) (influxdb.UserResourceMappingService, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	svc, closeSvc := initUserResourceMappingService(s, f, t)
+	return svc, func() {
+		closeSvc()
+		closeBolt()
+	}
+}
+
+func initInmemUserResourceMappingService(f platformtesting.UserResourceFields, t *testing.T) (influxdb.UserResourceMappingService, func()) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	svc, closeSvc := initUserResourceMappingService(s, f, t)
+	return svc, func() {
+		closeSvc()
+		closeInmem()
+	}
+}
+
+func initUserResourceMappingService(s kv.Store, f platformtesting.UserResourceFields, t *testing.T) (influxdb.UserResourceMappingService, func()) {
+	svc := kv.NewService(s)
+
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing user resource mapping service: %v", err)
+	}
+
+	for _, m := range f.UserResourceMappings {
+		if err := svc.CreateUserResourceMapping(ctx, m); err != nil {
+			t.Fatalf("failed to populate mappings")
+		}
+	}
+
+	return svc, func() {
+		for _, m := range f.UserResourceMappings {
+			if err := svc.DeleteUserResourceMapping(ctx, m.ResourceID, m.UserID); err != nil {
+				t.Logf("failed to remove user resource mapping: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,618 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	influxdb "github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+)
+
+var (
+	userBucket = []byte("usersv1")
+	userIndex  = []byte("userindexv1")
+)
+
+var _ influxdb.UserService = (*Service)(nil)
+var _ influxdb.UserOperationLogService = (*Service)(nil)
+
+func (s *Service) initializeUsers(ctx context.Context, tx Tx) error {
+	if _, err := s.userBucket(tx); err != nil {
+		return err
+	}
+	if _, err := s.userIndexBucket(tx); err != nil {
+		return err
+	}
+	if _, err := tx.Bucket(userpasswordBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *Service) userBucket(tx Tx) (Bucket, error) {
+	b, err := tx.Bucket(userBucket)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return b, nil
+}
+
+func (s *Service) userIndexBucket(tx Tx) (Bucket, error) {
+	b, err := tx.Bucket(userIndex)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return b, nil
+}
+
+// FindUserByID retrieves a user by id.
+func (s *Service) FindUserByID(ctx context.Context, id influxdb.ID) (*influxdb.User, error) {
+	var u *influxdb.User
+
+	err := s.kv.View(func(tx Tx) error {
+		usr, pe := s.findUserByID(ctx, tx, id)
+		if pe != nil {
+			return pe
+		}
+		u = usr
+		return nil
+	})
+
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  getOp(influxdb.OpFindUserByID),
+			Err: err,
+		}
+	}
+
+	return u, nil
+}
+
+func (s *Service) findUserByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.User, *influxdb.Error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	b, err := s.userBucket(tx)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	v, err := b.Get(encodedID)
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  "user not found",
+		}
+	}
+
+	if err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	var u influxdb.User
+	if err := json.Unmarshal(v, &u); err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return &u, nil
+}
+
+// FindUserByName returns a user by name for a particular user.
+func (s *Service) FindUserByName(ctx context.Context, n string) (*influxdb.User, error) {
+	var u *influxdb.User
+
+	err := s.kv.View(func(tx Tx) error {
+		usr, pe := s.findUserByName(ctx, tx, n)
+		if pe != nil {
+			return pe
+		}
+		u = usr
+		return nil
+	})
+
+	return u, err
+}
+
+func (s *Service) findUserByName(ctx context.Context, tx Tx, n string) (*influxdb.User, *influxdb.Error) {
+	b, err := s.userIndexBucket(tx)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	uid, err := b.Get(userIndexKey(n))
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  "user not found",
+		}
+	}
+
+	if err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	var id influxdb.ID
+	if err := id.Decode(uid); err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+	return s.findUserByID(ctx, tx, id)
+}
+
+// FindUser retrives a user using an arbitrary user filter.
+// Filters using ID, or Name should be efficient.
+// Other filters will do a linear scan across users until it finds a match.
+func (s *Service) FindUser(ctx context.Context, filter influxdb.UserFilter) (*influxdb.User, error) {
+	var u *influxdb.User
+	var err error
+	op := getOp(influxdb.OpFindUser)
+	if filter.ID != nil {
+		u, err = s.FindUserByID(ctx, *filter.ID)
+		if err != nil {
+			return nil, &influxdb.Error{
+				Op:  op,
+				Err: err,
+			}
+		}
+		return u, nil
+	}
+
+	if filter.Name != nil {
+		u, err = s.FindUserByName(ctx, *filter.Name)
+		if err != nil {
+			return nil, &influxdb.Error{
+				Op:  op,
+				Err: err,
+			}
+		}
+		return u, nil
+	}
+
+	filterFn := filterUsersFn(filter)
+
+	err = s.kv.View(func(tx Tx) error {
+		return forEachUser(ctx, tx, func(usr *influxdb.User) bool {
+			if filterFn(usr) {
+				u = usr
+				return false
+			}
+			return true
+		})
+	})
+
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  op,
+			Err: err,
+		}
+	}
+
+	if u == nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  "user not found",
+		}
+	}
+
+	return u, nil
+}
+
+func filterUsersFn(filter influxdb.UserFilter) func(u *influxdb.User) bool {
+	if filter.ID != nil {
+		return func(u *influxdb.User) bool {
+			return u.ID.Valid() && u.ID == *filter.ID
+		}
+	}
+
+	if filter.Name != nil {
+		return func(u *influxdb.User) bool {
+			return u.Name == *filter.Name
+		}
+	}
+
+	return func(u *influxdb.User) bool { return true }
+}
+
+// FindUsers retrives all users that match an arbitrary user filter.
+// Filters using ID, or Name should be efficient.
+// Other filters will do a linear scan across all users searching for a match.
+func (s *Service) FindUsers(ctx context.Context, filter influxdb.UserFilter, opt ...influxdb.FindOptions) ([]*influxdb.User, int, error) {
+	op := getOp(influxdb.OpFindUsers)
+	if filter.ID != nil {
+		u, err := s.FindUserByID(ctx, *filter.ID)
+		if err != nil {
+			return nil, 0, &influxdb.Error{
+				Err: err,
+				Op:  op,
+			}
+		}
+
+		return []*influxdb.User{u}, 1, nil
+	}
+
+	if filter.Name != nil {
+		u, err := s.FindUserByName(ctx, *filter.Name)
+		if err != nil {
+			return nil, 0, &influxdb.Error{
+				Err: err,
+				Op:  op,
+			}
+		}
+
+		return []*influxdb.User{u}, 1, nil
+	}
+
+	us := []*influxdb.User{}
+	filterFn := filterUsersFn(filter)
+	err := s.kv.View(func(tx Tx) error {
+		return forEachUser(ctx, tx, func(u *influxdb.User) bool {
+			if filterFn(u) {
+				us = append(us, u)
+			}
+			return true
+		})
+	})
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return us, len(us), nil
+}
+
+// CreateUser creates a influxdb user and sets b.ID.
+func (s *Service) CreateUser(ctx context.Context, u *influxdb.User) error {
+	err := s.kv.Update(func(tx Tx) error {
+		if err := s.uniqueUserName(ctx, tx, u); err != nil {
+			return err
+		}
+
+		u.ID = s.IDGenerator.ID()
+
+		if err := s.appendUserEventToLog(ctx, tx, u.ID, userCreatedEvent); err != nil {
+			return err
+		}
+
+		return s.putUser(ctx, tx, u)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+			Op:  getOp(influxdb.OpCreateUser),
+		}
+	}
+	return nil
+}
+
+// PutUser will put a user without setting an ID.
+func (s *Service) PutUser(ctx context.Context, u *influxdb.User) error {
+	return s.kv.Update(func(tx Tx) error {
+		return s.putUser(ctx, tx, u)
+	})
+}
+
+func (s *Service) putUser(ctx context.Context, tx Tx, u *influxdb.User) error {
+	v, err := json.Marshal(u)
+	if err != nil {
+		return err
+	}
+	encodedID, err := u.ID.Encode()
+	if err != nil {
+		return err
+	}
+
+	idx, err := s.userIndexBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	if err := idx.Put(userIndexKey(u.Name), encodedID); err != nil {
+		return err
+	}
+
+	b, err := s.userBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(encodedID, v)
+}
+
+func userIndexKey(n string) []byte {
+	return []byte(n)
+}
+
+// forEachUser will iterate through all users while fn returns true.
+func forEachUser(ctx context.Context, tx Tx, fn func(*influxdb.User) bool) error {
+	b, err := tx.Bucket(userBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		u := &influxdb.User{}
+		if err := json.Unmarshal(v, u); err != nil {
+			return err
+		}
+		if !fn(u) {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) uniqueUserName(ctx context.Context, tx Tx, u *influxdb.User) error {
+	key := userIndexKey(u.Name)
+
+	idx, err := s.userIndexBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	_, err = idx.Get(key)
+	// if not found then this is unique.
+	if IsNotFound(err) {
+		return nil
+	}
+
+	// no error means this is not unique
+	if err == nil {
+		return &influxdb.Error{
+			Code: influxdb.EConflict,
+			Msg:  fmt.Sprintf("user with name %s already exists", u.Name),
+		}
+	}
+
+	// any other error is some sort of internal server error
+	return &influxdb.Error{
+		Code: influxdb.EInternal,
+		Err:  err,
+	}
+}
+
+// UpdateUser updates a user according the parameters set on upd.
+func (s *Service) UpdateUser(ctx context.Context, id influxdb.ID, upd influxdb.UserUpdate) (*influxdb.User, error) {
+	var u *influxdb.User
+	err := s.kv.Update(func(tx Tx) error {
+		usr, pe := s.updateUser(ctx, tx, id, upd)
+		if pe != nil {
+			return &influxdb.Error{
+				Err: pe,
+				Op:  getOp(influxdb.OpUpdateUser),
+			}
+		}
+		u = usr
+		return nil
+	})
+
+	return u, err
+}
+
+func (s *Service) updateUser(ctx context.Context, tx Tx, id influxdb.ID, upd influxdb.UserUpdate) (*influxdb.User, *influxdb.Error) {
+	u, pe := s.findUserByID(ctx, tx, id)
+	if pe != nil {
+		return nil, pe
+	}
+
+	if upd.Name != nil {
+		// Users are indexed by name and so the user index must be pruned
+		// when name is modified.
+		idx, err := s.userIndexBucket(tx)
+		if err != nil {
+			return nil, &influxdb.Error{
+				Err: err,
+			}
+		}
+
+		if err := idx.Delete(userIndexKey(u.Name)); err != nil {
+			return nil, &influxdb.Error{
+				Err: err,
+			}
+		}
+		u.Name = *upd.Name
+	}
+
+	if err := s.appendUserEventToLog(ctx, tx, u.ID, userUpdatedEvent); err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	if err := s.putUser(ctx, tx, u); err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return u, nil
+}
+
+// DeleteUser deletes a user and prunes it from the index.
+func (s *Service) DeleteUser(ctx context.Context, id influxdb.ID) error {
+	err := s.kv.Update(func(tx Tx) error {
+		if pe := s.deleteUsersAuthorizations(ctx, tx, id); pe != nil {
+			return pe
+		}
+		if pe := s.deleteUser(ctx, tx, id); pe != nil {
+			return pe
+		}
+		return nil
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  getOp(influxdb.OpDeleteUser),
+			Err: err,
+		}
+	}
+	return nil
+}
+
+func (s *Service) deleteUser(ctx context.Context, tx Tx, id influxdb.ID) *influxdb.Error {
+	u, pe := s.findUserByID(ctx, tx, id)
+	if pe != nil {
+		return pe
+	}
+
+	encodedID, err := id.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	idx, err := s.userIndexBucket(tx)
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	if err := idx.Delete(userIndexKey(u.Name)); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	b, err := s.userBucket(tx)
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	if err := b.Delete(encodedID); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	if err := s.deleteUserResourceMappings(ctx, tx, influxdb.UserResourceMappingFilter{
+		UserID: id,
+	}); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) deleteUsersAuthorizations(ctx context.Context, tx Tx, id influxdb.ID) *influxdb.Error {
+	authFilter := influxdb.AuthorizationFilter{
+		UserID: &id,
+	}
+	as, err := s.findAuthorizations(ctx, tx, authFilter)
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+	for _, a := range as {
+		if err := s.deleteAuthorization(ctx, tx, a.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetUserOperationLog retrieves a user operation log.
+func (s *Service) GetUserOperationLog(ctx context.Context, id influxdb.ID, opts influxdb.FindOptions) ([]*influxdb.OperationLogEntry, int, error) {
+	// TODO(desa): might be worthwhile to allocate a slice of size opts.Limit
+	log := []*influxdb.OperationLogEntry{}
+
+	err := s.kv.View(func(tx Tx) error {
+		key, err := encodeUserOperationLogKey(id)
+		if err != nil {
+			return err
+		}
+
+		return s.forEachLogEntry(ctx, tx, key, opts, func(v []byte, t time.Time) error {
+			e := &influxdb.OperationLogEntry{}
+			if err := json.Unmarshal(v, e); err != nil {
+				return err
+			}
+			e.Time = t
+
+			log = append(log, e)
+
+			return nil
+		})
+	})
+
+	if err != nil && err != errKeyValueLogBoundsNotFound {
+		return nil, 0, err
+	}
+
+	return log, len(log), nil
+}
+
+// TODO(desa): what do we want these to be?
+const (
+	userCreatedEvent = "User Created"
+	userUpdatedEvent = "User Updated"
+)
+
+const userOperationLogKeyPrefix = "user"
+
+func encodeUserOperationLogKey(id influxdb.ID) ([]byte, error) {
+	buf, err := id.Encode()
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(userOperationLogKeyPrefix), buf...), nil
+}
+
+func (s *Service) appendUserEventToLog(ctx context.Context, tx Tx, id influxdb.ID, st string) error {
+	e := &influxdb.OperationLogEntry{
+		Description: st,
+	}
+	// TODO(desa): this is fragile and non explicit since it requires an authorizer to be on context. It should be
+	//             replaced with a higher level transaction so that adding to the log can take place in the http handler
+	//             where the userID will exist explicitly.
+	a, err := icontext.GetAuthorizer(ctx)
+	if err == nil {
+		// Add the user to the log if you can, but don't error if its not there.
+		e.UserID = a.GetUserID()
+	}
+
+	v, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	k, err := encodeUserOperationLogKey(id)
+	if err != nil {
+		return err
+	}
+
+	return s.addLogEntry(ctx, tx, k, v, s.time())
+}
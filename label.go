@@ -45,6 +45,12 @@ type LabelService interface {
 	DeleteLabelMapping(ctx context.Context, m *LabelMapping) error
 }
 
+// LabelResourceService finds resources by the labels they carry.
+type LabelResourceService interface {
+	// FindLabelResources returns the IDs of the resources of type rt carrying the labels selected by sel.
+	FindLabelResources(ctx context.Context, rt ResourceType, sel LabelSelector) ([]ID, error)
+}
+
 // Label is a tag set on a resource, typically used for filtering on a UI.
 type Label struct {
 	ID         ID                `json:"id,omitempty"`
@@ -113,3 +119,49 @@ type LabelMappingFilter struct {
 	ResourceID ID
 	ResourceType
 }
+
+// LabelSelector restricts the results of a find to resources carrying labels.
+// Labels are selected by name, so every label with a selected name counts.
+type LabelSelector struct {
+	Names []string
+	// Any selects resources carrying at least one of the labels instead of all of them.
+	Any bool
+}
+
+// Empty returns true if the selector selects every resource.
+func (s LabelSelector) Empty() bool {
+	return len(s.Names) == 0
+}
+
+// Matches returns true if a resource carrying labels is selected.
+func (s LabelSelector) Matches(labels []*Label) bool {
+	if s.Empty() {
+		return true
+	}
+
+	carried := make(map[string]bool, len(labels))
+	for _, l := range labels {
+		carried[l.Name] = true
+	}
+
+	for _, name := range s.Names {
+		if carried[name] == s.Any {
+			return s.Any
+		}
+	}
+	return !s.Any
+}
+
+// QueryParams converts the selector to url query params.
+func (s LabelSelector) QueryParams() map[string][]string {
+	qp := map[string][]string{}
+	if s.Empty() {
+		return qp
+	}
+
+	qp["labels"] = s.Names
+	if s.Any {
+		qp["labelMatch"] = []string{"any"}
+	}
+	return qp
+}
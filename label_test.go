@@ -39,3 +39,46 @@ func TestLabelValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestLabelSelector_Matches(t *testing.T) {
+	carried := []*platform.Label{{Name: "prod"}, {Name: "iot"}}
+
+	tests := []struct {
+		name     string
+		selector platform.LabelSelector
+		want     bool
+	}{
+		{
+			name: "empty selector matches everything",
+			want: true,
+		},
+		{
+			name:     "all labels carried",
+			selector: platform.LabelSelector{Names: []string{"prod", "iot"}},
+			want:     true,
+		},
+		{
+			name:     "one label missing",
+			selector: platform.LabelSelector{Names: []string{"prod", "dev"}},
+			want:     false,
+		},
+		{
+			name:     "any label carried",
+			selector: platform.LabelSelector{Names: []string{"dev", "iot"}, Any: true},
+			want:     true,
+		},
+		{
+			name:     "no label carried",
+			selector: platform.LabelSelector{Names: []string{"dev", "test"}, Any: true},
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.selector.Matches(carried); got != tt.want {
+				t.Errorf("LabelSelector.Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -18,6 +18,9 @@ const (
 	OpUpdateMacro   = "UpdateMacro"
 	OpReplaceMacro  = "ReplaceMacro"
 	OpDeleteMacro   = "DeleteMacro"
+
+	OpFindMacroValues   = "FindMacroValues"
+	OpFindMacroBindings = "FindMacroBindings"
 )
 
 // MacroService describes a service for managing Macros
@@ -41,6 +44,22 @@ type MacroService interface {
 	DeleteMacro(ctx context.Context, id ID) error
 }
 
+// MacroValuesService evaluates the values macros expand to.
+type MacroValuesService interface {
+	// FindMacroValues returns the values the macro id expands to. The values
+	// of query macros are the results of running their query as the caller.
+	FindMacroValues(ctx context.Context, id ID) ([]string, error)
+}
+
+// MacroBindingService resolves the values the macros of queries are bound to.
+type MacroBindingService interface {
+	// FindMacroBindings returns the values of the macros named names of the
+	// organization orgID. Names in bindings are bound to their value in
+	// bindings, and other names to the selected value of the macro of that
+	// name. Names that are neither are left out.
+	FindMacroBindings(ctx context.Context, orgID ID, names []string, bindings map[string]string) (map[string]string, error)
+}
+
 // A Macro describes a keyword that can be expanded into several possible
 // values when used in an InfluxQL or Flux query
 type Macro struct {
@@ -51,6 +70,12 @@ type Macro struct {
 	Arguments      *MacroArguments `json:"arguments"`
 }
 
+// Cursor returns the position of the macro in a listing sorted by sortBy.
+// Macros may be sorted by ID or Name.
+func (m *Macro) Cursor(sortBy string) (PageCursor, error) {
+	return namePageCursor(sortBy, m.Name, m.ID)
+}
+
 // DefaultMacroFindOptions are the default find options for macros.
 var DefaultMacroFindOptions = FindOptions{}
 
@@ -59,6 +84,7 @@ type MacroFilter struct {
 	ID             *ID
 	OrganizationID *ID
 	Organization   *string
+	Labels         LabelSelector
 }
 
 // QueryParams implements PagingFilter.
@@ -78,6 +104,10 @@ func (f MacroFilter) QueryParams() map[string][]string {
 		qp.Add("org", *f.Organization)
 	}
 
+	for k, v := range f.Labels.QueryParams() {
+		qp[k] = v
+	}
+
 	return qp
 }
 
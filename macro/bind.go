@@ -0,0 +1,184 @@
+package macro
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+	platform "github.com/influxdata/influxdb"
+)
+
+// Selected returns the value m is bound to, given the values it expands to:
+// the first selected value still among values, or else the first value. Map
+// macros are bound to the entry of the selected key.
+func Selected(m *platform.Macro, values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	value := values[0]
+	for _, s := range m.Selected {
+		if contains(values, s) {
+			value = s
+			break
+		}
+	}
+
+	if m.Arguments != nil {
+		if entries, ok := m.Arguments.Values.(platform.MacroMapValues); ok {
+			return entries[value]
+		}
+	}
+	return value
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// fluxReference matches the members of the v record in Flux, such as v.host.
+	fluxReference = regexp.MustCompile(`\bv\.([A-Za-z_][A-Za-z0-9_]*)`)
+	// influxqlReference matches InfluxQL template variables, such as :host:.
+	influxqlReference = regexp.MustCompile(`:([A-Za-z_][A-Za-z0-9_]*):`)
+)
+
+// References returns the names of the macros referenced by a query, in order
+// of first reference.
+func References(language, q string) []string {
+	re := fluxReference
+	if language == LanguageInfluxQL {
+		re = influxqlReference
+	}
+
+	var names []string
+	seen := map[string]bool{}
+	for _, match := range re.FindAllStringSubmatch(q, -1) {
+		if name := match[1]; !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Bind binds the macros of a query to values. Flux queries are given a v
+// record holding the values, and the template variables of InfluxQL queries
+// are replaced by the values.
+func Bind(language, q string, bindings map[string]string) (string, error) {
+	names := make([]string, 0, len(bindings))
+	for name := range bindings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	switch language {
+	case LanguageFlux:
+		if len(bindings) == 0 {
+			return q, nil
+		}
+		return bindFlux(q, names, bindings), nil
+	case LanguageInfluxQL:
+		for _, name := range names {
+			q = strings.Replace(q, ":"+name+":", bindings[name], -1)
+		}
+		return q, nil
+	default:
+		return "", &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  fmt.Sprintf("unsupported query language %q", language),
+		}
+	}
+}
+
+// fluxString returns s as a Flux string literal.
+func fluxString(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, `"`, `\"`, -1)
+	return `"` + s + `"`
+}
+
+// bindFlux binds the macros names of the Flux query q to bindings. A v record
+// declared by q, as a variable or an option, holds the defaults of the
+// macros: its properties are replaced by the bound values and the record
+// keeps the properties no value is bound to. Without one, a v record is
+// declared after the imports of q.
+func bindFlux(q string, names []string, bindings map[string]string) string {
+	pkg := parser.ParseSource(q)
+	if ast.Check(pkg) > 0 || len(pkg.Files[0].Body) == 0 {
+		// Leave q for the compiler to report its errors, or lack of results.
+		return q
+	}
+	file := pkg.Files[0]
+
+	if obj := fluxRecord(file); obj != nil {
+		var props []string
+		for _, p := range obj.Properties {
+			if _, ok := bindings[p.Key.Key()]; !ok {
+				props = append(props, ast.Format(p))
+			}
+		}
+		for _, name := range names {
+			props = append(props, name+": "+fluxString(bindings[name]))
+		}
+		start, end := offset(q, obj.Loc.Start), offset(q, obj.Loc.End)
+		return q[:start] + "{" + strings.Join(props, ", ") + "}" + q[end:]
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("v = {")
+	for i, name := range names {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(name)
+		buf.WriteString(": ")
+		buf.WriteString(fluxString(bindings[name]))
+	}
+	buf.WriteString("}\n\n")
+
+	start := offset(q, file.Body[0].Location().Start)
+	return q[:start] + buf.String() + q[start:]
+}
+
+// fluxRecord returns the v record declared by file, or nil.
+func fluxRecord(file *ast.File) *ast.ObjectExpression {
+	for _, s := range file.Body {
+		var a *ast.VariableAssignment
+		switch s := s.(type) {
+		case *ast.VariableAssignment:
+			a = s
+		case *ast.OptionStatement:
+			a, _ = s.Assignment.(*ast.VariableAssignment)
+		}
+		if a == nil || a.ID.Name != "v" {
+			continue
+		}
+		if obj, ok := a.Init.(*ast.ObjectExpression); ok && obj.Loc != nil {
+			return obj
+		}
+	}
+	return nil
+}
+
+// offset returns the byte offset of the position p in src.
+func offset(src string, p ast.Position) int {
+	i := 0
+	for line := 1; line < p.Line; line++ {
+		n := strings.IndexByte(src[i:], '\n')
+		if n < 0 {
+			return len(src)
+		}
+		i += n + 1
+	}
+	return i + p.Column - 1
+}
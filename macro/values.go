@@ -0,0 +1,401 @@
+// Package macro evaluates the values macros expand to.
+package macro
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/csv"
+	"github.com/influxdata/flux/lang"
+	platform "github.com/influxdata/influxdb"
+	platcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/influxql"
+)
+
+// Query languages of query macros.
+const (
+	LanguageFlux     = "flux"
+	LanguageInfluxQL = "influxql"
+)
+
+// DefaultTTL is the default time the results of macro queries are cached for.
+const DefaultTTL = time.Minute
+
+var (
+	_ platform.MacroValuesService  = (*ValuesService)(nil)
+	_ platform.MacroBindingService = (*ValuesService)(nil)
+)
+
+// ValuesService implements platform.MacroValuesService and
+// platform.MacroBindingService. Query macros are run through ProxyQueryService
+// as the authorizer on context, after binding the macros they reference, and
+// their results are cached for TTL.
+type ValuesService struct {
+	MacroService      platform.MacroService
+	ProxyQueryService query.ProxyQueryService
+
+	// DBRPMappingService maps the databases of InfluxQL queries to buckets.
+	// InfluxQL macros cannot be evaluated without it.
+	DBRPMappingService platform.DBRPMappingService
+
+	// TTL is the time query results are cached for; zero disables caching.
+	TTL time.Duration
+
+	now   func() time.Time
+	mu    sync.Mutex
+	cache map[string]cachedValues
+}
+
+type cachedValues struct {
+	values  []string
+	expires time.Time
+}
+
+// NewValuesService returns a ValuesService caching query results for DefaultTTL.
+func NewValuesService(ms platform.MacroService, qs query.ProxyQueryService) *ValuesService {
+	return &ValuesService{
+		MacroService:      ms,
+		ProxyQueryService: qs,
+		TTL:               DefaultTTL,
+		now:               time.Now,
+		cache:             make(map[string]cachedValues),
+	}
+}
+
+// FindMacroValues returns the values the macro id expands to: the values of
+// constant macros, the sorted keys of map macros and the results of the query
+// of query macros.
+func (s *ValuesService) FindMacroValues(ctx context.Context, id platform.ID) ([]string, error) {
+	m, err := s.MacroService.FindMacroByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := s.resolver(m.OrganizationID).values(ctx, m)
+	if err != nil {
+		return nil, &platform.Error{
+			Op:  platform.OpFindMacroValues,
+			Err: err,
+		}
+	}
+	return values, nil
+}
+
+// FindMacroBindings returns the values of the macros named names of the
+// organization orgID: the values of bindings, or else the selected values of
+// the macros of the organization. Names that are neither bound nor macros of
+// the organization are left out.
+func (s *ValuesService) FindMacroBindings(ctx context.Context, orgID platform.ID, names []string, bindings map[string]string) (map[string]string, error) {
+	r := s.resolver(orgID)
+	values, err := r.bindings(ctx, names, bindings)
+	if err != nil {
+		return nil, &platform.Error{
+			Op:  platform.OpFindMacroBindings,
+			Err: err,
+		}
+	}
+	return values, nil
+}
+
+func (s *ValuesService) resolver(orgID platform.ID) *resolver {
+	return &resolver{
+		ValuesService: s,
+		orgID:         orgID,
+		resolving:     map[platform.ID]bool{},
+	}
+}
+
+// resolver evaluates the macros of an organization, tracking the macros being
+// evaluated to detect macros depending on themselves.
+type resolver struct {
+	*ValuesService
+	orgID     platform.ID
+	resolving map[platform.ID]bool
+	byName    map[string]*platform.Macro
+}
+
+func (r *resolver) values(ctx context.Context, m *platform.Macro) ([]string, error) {
+	if m.Arguments == nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  fmt.Sprintf("macro %q has no arguments", m.Name),
+		}
+	}
+
+	switch values := m.Arguments.Values.(type) {
+	case platform.MacroConstantValues:
+		return values, nil
+	case platform.MacroMapValues:
+		keys := make([]string, 0, len(values))
+		for k := range values {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return keys, nil
+	case platform.MacroQueryValues:
+		return r.query(ctx, m, values)
+	default:
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  fmt.Sprintf("macro %q has unsupported arguments type %q", m.Name, m.Arguments.Type),
+		}
+	}
+}
+
+func (r *resolver) query(ctx context.Context, m *platform.Macro, q platform.MacroQueryValues) ([]string, error) {
+	if r.resolving[m.ID] {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  fmt.Sprintf("macro %q depends on itself", m.Name),
+		}
+	}
+	r.resolving[m.ID] = true
+	defer delete(r.resolving, m.ID)
+
+	language := strings.ToLower(q.Language)
+	if language == "" {
+		language = LanguageFlux
+	}
+
+	bindings, err := r.bindings(ctx, References(language, q.Query), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := Bind(language, q.Query, bindings)
+	if err != nil {
+		return nil, err
+	}
+	return r.run(ctx, r.orgID, language, text)
+}
+
+func (r *resolver) bindings(ctx context.Context, names []string, bindings map[string]string) (map[string]string, error) {
+	values := make(map[string]string, len(names))
+	for _, name := range names {
+		if v, ok := bindings[name]; ok {
+			values[name] = v
+			continue
+		}
+
+		m, err := r.macro(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if m == nil {
+			// Not a macro of the organization; the query binds it, or fails.
+			continue
+		}
+
+		vs, err := r.values(ctx, m)
+		if err != nil {
+			return nil, err
+		}
+		values[name] = Selected(m, vs)
+	}
+	return values, nil
+}
+
+// macro returns the macro of the organization named name, or nil.
+func (r *resolver) macro(ctx context.Context, name string) (*platform.Macro, error) {
+	if r.byName == nil {
+		ms, err := r.MacroService.FindMacros(ctx, platform.MacroFilter{OrganizationID: &r.orgID})
+		if err != nil {
+			return nil, err
+		}
+		r.byName = make(map[string]*platform.Macro, len(ms))
+		for _, m := range ms {
+			r.byName[m.Name] = m
+		}
+	}
+	return r.byName[name], nil
+}
+
+// run returns the values the query text of language returns, from the cache if
+// it holds them. Queries run as the authorizer on context; without one, as
+// for task runs, they run unauthorized.
+func (s *ValuesService) run(ctx context.Context, orgID platform.ID, language, text string) ([]string, error) {
+	var identifier string
+	auth, err := platcontext.GetAuthorizer(ctx)
+	if err == nil {
+		identifier = auth.Identifier().String()
+	}
+
+	// Results depend on who runs the query, as well as on the query.
+	key := strings.Join([]string{orgID.String(), identifier, language, text}, "\x00")
+	if values, ok := s.cached(key); ok {
+		return values, nil
+	}
+
+	req := &query.ProxyRequest{
+		Request: query.Request{
+			OrganizationID: orgID,
+		},
+		Dialect: csv.DefaultDialect(),
+	}
+	if a, ok := auth.(*platform.Authorization); ok {
+		req.Request.Authorization = a
+	}
+
+	switch language {
+	case LanguageFlux:
+		req.Request.Compiler = lang.FluxCompiler{Query: text}
+	case LanguageInfluxQL:
+		if s.DBRPMappingService == nil {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "influxql macros are not supported",
+			}
+		}
+		c := influxql.NewCompiler(s.DBRPMappingService)
+		c.Query = text
+		req.Request.Compiler = c
+	}
+
+	var buf bytes.Buffer
+	if _, err := s.ProxyQueryService.Query(ctx, &buf, req); err != nil {
+		return nil, err
+	}
+
+	values, err := decodeValues(&buf)
+	if err != nil {
+		return nil, err
+	}
+
+	s.store(key, values)
+	return values, nil
+}
+
+func (s *ValuesService) cached(key string) ([]string, bool) {
+	if s.TTL <= 0 {
+		return nil, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.cache[key]
+	if !ok || !s.now().Before(c.expires) {
+		return nil, false
+	}
+	return c.values, true
+}
+
+func (s *ValuesService) store(key string, values []string) {
+	if s.TTL <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for k, c := range s.cache {
+		if !now.Before(c.expires) {
+			delete(s.cache, k)
+		}
+	}
+	s.cache[key] = cachedValues{
+		values:  values,
+		expires: now.Add(s.TTL),
+	}
+}
+
+// systemColumns are the columns of query results that never hold macro values.
+var systemColumns = map[string]bool{
+	"result": true,
+	"table":  true,
+	"_start": true,
+	"_stop":  true,
+	"_time":  true,
+}
+
+// decodeValues returns the distinct values of the results encoded in buf, in
+// order. Values are read from the _value column of each table or, lacking one,
+// from its last column, as InfluxQL results name columns after fields.
+func decodeValues(buf *bytes.Buffer) ([]string, error) {
+	dec := csv.NewMultiResultDecoder(csv.ResultDecoderConfig{})
+	results, err := dec.Decode(ioutil.NopCloser(buf))
+	if err != nil {
+		return nil, err
+	}
+	defer results.Release()
+
+	var values []string
+	seen := map[string]bool{}
+	for results.More() {
+		err := results.Next().Tables().Do(func(tbl flux.Table) error {
+			return tbl.Do(func(cr flux.ColReader) error {
+				j := valueColumn(cr.Cols())
+				if j < 0 {
+					return nil
+				}
+				for i := 0; i < cr.Len(); i++ {
+					v, ok := formatValue(cr, i, j)
+					if ok && !seen[v] {
+						seen[v] = true
+						values = append(values, v)
+					}
+				}
+				return nil
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := results.Err(); err != nil {
+		return nil, err
+	}
+
+	if values == nil {
+		values = []string{}
+	}
+	return values, nil
+}
+
+func valueColumn(cols []flux.ColMeta) int {
+	j := -1
+	for k, c := range cols {
+		if c.Label == "_value" {
+			return k
+		}
+		if !systemColumns[c.Label] {
+			j = k
+		}
+	}
+	return j
+}
+
+func formatValue(cr flux.ColReader, i, j int) (string, bool) {
+	switch cr.Cols()[j].Type {
+	case flux.TString:
+		vs := cr.Strings(j)
+		return vs.ValueString(i), vs.IsValid(i)
+	case flux.TInt:
+		vs := cr.Ints(j)
+		return strconv.FormatInt(vs.Value(i), 10), vs.IsValid(i)
+	case flux.TUInt:
+		vs := cr.UInts(j)
+		return strconv.FormatUint(vs.Value(i), 10), vs.IsValid(i)
+	case flux.TFloat:
+		vs := cr.Floats(j)
+		return strconv.FormatFloat(vs.Value(i), 'f', -1, 64), vs.IsValid(i)
+	case flux.TBool:
+		vs := cr.Bools(j)
+		return strconv.FormatBool(vs.Value(i)), vs.IsValid(i)
+	case flux.TTime:
+		vs := cr.Times(j)
+		return time.Unix(0, vs.Value(i)).UTC().Format(time.RFC3339Nano), vs.IsValid(i)
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,223 @@
+package macro
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux/lang"
+	platform "github.com/influxdata/influxdb"
+	platcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/query"
+)
+
+const hostsCSV = `#datatype,string,long,string
+#group,false,false,false
+#default,_result,,
+,result,table,_value
+,,0,host1
+,,0,host2
+,,1,host1
+
+`
+
+func newMacroService(ms ...*platform.Macro) *mock.MacroService {
+	s := mock.NewMacroService()
+	s.FindMacroByIDF = func(ctx context.Context, id platform.ID) (*platform.Macro, error) {
+		for _, m := range ms {
+			if m.ID == id {
+				return m, nil
+			}
+		}
+		return nil, &platform.Error{Code: platform.ENotFound, Msg: platform.ErrMacroNotFound}
+	}
+	s.FindMacrosF = func(ctx context.Context, filter platform.MacroFilter, opts ...platform.FindOptions) ([]*platform.Macro, error) {
+		return ms, nil
+	}
+	return s
+}
+
+func TestValuesService_FindMacroValues(t *testing.T) {
+	bucket := &platform.Macro{
+		ID:             1,
+		OrganizationID: 10,
+		Name:           "bucket",
+		Selected:       []string{"b"},
+		Arguments: &platform.MacroArguments{
+			Type:   "constant",
+			Values: platform.MacroConstantValues{"a", "b"},
+		},
+	}
+	host := &platform.Macro{
+		ID:             2,
+		OrganizationID: 10,
+		Name:           "host",
+		Arguments: &platform.MacroArguments{
+			Type: "query",
+			Values: platform.MacroQueryValues{
+				Query:    `from(bucket: v.bucket) |> range(start: -1h) |> keep(columns: ["host"])`,
+				Language: "flux",
+			},
+		},
+	}
+	loop := &platform.Macro{
+		ID:             3,
+		OrganizationID: 10,
+		Name:           "loop",
+		Arguments: &platform.MacroArguments{
+			Type:   "query",
+			Values: platform.MacroQueryValues{Query: `v.loop`, Language: "flux"},
+		},
+	}
+
+	var queries []string
+	qs := mock.NewProxyQueryService()
+	qs.QueryFn = func(ctx context.Context, w io.Writer, req *query.ProxyRequest) (int64, error) {
+		q := req.Request.Compiler.(lang.FluxCompiler).Query
+		queries = append(queries, q)
+		if req.Request.OrganizationID != 10 {
+			t.Errorf("query run in org %s, want the org of the macro", req.Request.OrganizationID)
+		}
+		if req.Request.Authorization == nil || req.Request.Authorization.ID != 100 {
+			t.Error("query must run as the caller")
+		}
+		n, err := io.WriteString(w, hostsCSV)
+		return int64(n), err
+	}
+
+	s := NewValuesService(newMacroService(bucket, host, loop), qs)
+	now := time.Unix(0, 0)
+	s.now = func() time.Time { return now }
+
+	ctx := platcontext.SetAuthorizer(context.Background(), &platform.Authorization{ID: 100})
+
+	values, err := s.FindMacroValues(ctx, bucket.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "b"}; !reflect.DeepEqual(values, want) {
+		t.Fatalf("constant macro values = %v, want %v", values, want)
+	}
+
+	values, err = s.FindMacroValues(ctx, host.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"host1", "host2"}; !reflect.DeepEqual(values, want) {
+		t.Fatalf("query macro values = %v, want %v", values, want)
+	}
+	if len(queries) != 1 || !strings.HasPrefix(queries[0], `v = {bucket: "b"}`) {
+		t.Fatalf("expected the selected value of the bucket macro to be bound, got queries %q", queries)
+	}
+
+	if _, err := s.FindMacroValues(ctx, host.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(queries) != 1 {
+		t.Fatalf("expected query results to be cached, got %d queries", len(queries))
+	}
+
+	now = now.Add(DefaultTTL)
+	if _, err := s.FindMacroValues(ctx, host.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(queries) != 2 {
+		t.Fatalf("expected cached results to expire, got %d queries", len(queries))
+	}
+
+	if _, err := s.FindMacroValues(ctx, loop.ID); platform.ErrorCode(err) != platform.EInvalid {
+		t.Fatalf("expected a macro depending on itself to be invalid, got %v", err)
+	}
+}
+
+func TestValuesService_FindMacroBindings(t *testing.T) {
+	region := &platform.Macro{
+		ID:             1,
+		OrganizationID: 10,
+		Name:           "region",
+		Selected:       []string{"west"},
+		Arguments: &platform.MacroArguments{
+			Type:   "map",
+			Values: platform.MacroMapValues{"east": "us-east-1", "west": "us-west-2"},
+		},
+	}
+	host := &platform.Macro{
+		ID:             2,
+		OrganizationID: 10,
+		Name:           "host",
+		Arguments: &platform.MacroArguments{
+			Type:   "constant",
+			Values: platform.MacroConstantValues{"a", "b"},
+		},
+	}
+
+	s := NewValuesService(newMacroService(region, host), mock.NewProxyQueryService())
+
+	bindings, err := s.FindMacroBindings(context.Background(), 10, []string{"region", "host", "other"}, map[string]string{"host": "c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"region": "us-west-2", "host": "c"}; !reflect.DeepEqual(bindings, want) {
+		t.Fatalf("FindMacroBindings() = %v, want %v", bindings, want)
+	}
+}
+
+func TestSelected(t *testing.T) {
+	m := &platform.Macro{
+		Selected: []string{"gone", "west"},
+		Arguments: &platform.MacroArguments{
+			Type:   "map",
+			Values: platform.MacroMapValues{"east": "us-east-1", "west": "us-west-2"},
+		},
+	}
+	if got := Selected(m, []string{"east", "west"}); got != "us-west-2" {
+		t.Errorf("Selected() = %q, want the entry of the first selected key still present", got)
+	}
+	if got := Selected(m, []string{"east"}); got != "us-east-1" {
+		t.Errorf("Selected() = %q, want the entry of the first key", got)
+	}
+}
+
+func TestBind(t *testing.T) {
+	bindings := map[string]string{"host": `a"b`, "bucket": "telegraf"}
+
+	q, err := Bind(LanguageFlux, "from(bucket: v.bucket)", bindings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "v = {bucket: \"telegraf\", host: \"a\\\"b\"}\n\nfrom(bucket: v.bucket)"; q != want {
+		t.Errorf("Bind(flux) = %q, want %q", q, want)
+	}
+
+	q, err = Bind(LanguageFlux, "import \"strings\"\n\nfrom(bucket: v.bucket)", bindings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "import \"strings\"\n\nv = {bucket: \"telegraf\", host: \"a\\\"b\"}\n\nfrom(bucket: v.bucket)"; q != want {
+		t.Errorf("Bind(flux) = %q, want the record declared after the imports: %q", q, want)
+	}
+
+	q, err = Bind(LanguageFlux, "option v = {bucket: \"default\", region: \"west\"}\nfrom(bucket: v.bucket)", map[string]string{"bucket": "telegraf"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "option v = {region: \"west\", bucket: \"telegraf\"}\nfrom(bucket: v.bucket)"; q != want {
+		t.Errorf("Bind(flux) = %q, want the declared record to be bound: %q", q, want)
+	}
+
+	q, err = Bind(LanguageInfluxQL, `SELECT * FROM :bucket: WHERE host = ':host:'`, bindings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `SELECT * FROM telegraf WHERE host = 'a"b'`; q != want {
+		t.Errorf("Bind(influxql) = %q, want %q", q, want)
+	}
+
+	if _, err := Bind("promql", "up", bindings); platform.ErrorCode(err) != platform.EInvalid {
+		t.Errorf("expected unknown languages to be invalid, got %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.DashboardVersionService = &DashboardVersionService{}
+
+// DashboardVersionService is a mock of platform.DashboardVersionService.
+type DashboardVersionService struct {
+	FindDashboardVersionsF   func(context.Context, platform.ID, platform.FindOptions) ([]*platform.DashboardVersion, int, error)
+	FindDashboardVersionF    func(context.Context, platform.ID, int) (*platform.DashboardVersion, error)
+	RestoreDashboardVersionF func(context.Context, platform.ID, int) (*platform.Dashboard, error)
+}
+
+// NewDashboardVersionService returns a mock of DashboardVersionService where its methods will return zero values.
+func NewDashboardVersionService() *DashboardVersionService {
+	return &DashboardVersionService{
+		FindDashboardVersionsF: func(context.Context, platform.ID, platform.FindOptions) ([]*platform.DashboardVersion, int, error) {
+			return nil, 0, nil
+		},
+		FindDashboardVersionF: func(context.Context, platform.ID, int) (*platform.DashboardVersion, error) {
+			return nil, nil
+		},
+		RestoreDashboardVersionF: func(context.Context, platform.ID, int) (*platform.Dashboard, error) {
+			return nil, nil
+		},
+	}
+}
+
+func (s *DashboardVersionService) FindDashboardVersions(ctx context.Context, id platform.ID, opts platform.FindOptions) ([]*platform.DashboardVersion, int, error) {
+	return s.FindDashboardVersionsF(ctx, id, opts)
+}
+
+func (s *DashboardVersionService) FindDashboardVersion(ctx context.Context, id platform.ID, version int) (*platform.DashboardVersion, error) {
+	return s.FindDashboardVersionF(ctx, id, version)
+}
+
+func (s *DashboardVersionService) RestoreDashboardVersion(ctx context.Context, id platform.ID, version int) (*platform.Dashboard, error) {
+	return s.RestoreDashboardVersionF(ctx, id, version)
+}
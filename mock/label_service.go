@@ -80,3 +80,25 @@ func (s *LabelService) DeleteLabel(ctx context.Context, id platform.ID) error {
 func (s *LabelService) DeleteLabelMapping(ctx context.Context, m *platform.LabelMapping) error {
 	return s.DeleteLabelMappingFn(ctx, m)
 }
+
+var _ platform.LabelResourceService = &LabelResourceService{}
+
+// LabelResourceService is a mock implementation of platform.LabelResourceService
+type LabelResourceService struct {
+	FindLabelResourcesFn func(context.Context, platform.ResourceType, platform.LabelSelector) ([]platform.ID, error)
+}
+
+// NewLabelResourceService returns a mock of LabelResourceService
+// where its methods will return zero values.
+func NewLabelResourceService() *LabelResourceService {
+	return &LabelResourceService{
+		FindLabelResourcesFn: func(context.Context, platform.ResourceType, platform.LabelSelector) ([]platform.ID, error) {
+			return nil, nil
+		},
+	}
+}
+
+// FindLabelResources returns the IDs of the resources of type rt carrying the labels selected by sel.
+func (s *LabelResourceService) FindLabelResources(ctx context.Context, rt platform.ResourceType, sel platform.LabelSelector) ([]platform.ID, error) {
+	return s.FindLabelResourcesFn(ctx, rt, sel)
+}
@@ -0,0 +1,43 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.RunningQueryService = &RunningQueryService{}
+
+// RunningQueryService is a mock of platform.RunningQueryService.
+type RunningQueryService struct {
+	FindRunningQueriesF   func(context.Context, platform.RunningQueryFilter) ([]*platform.RunningQuery, error)
+	FindRunningQueryByIDF func(context.Context, platform.ID) (*platform.RunningQuery, error)
+	CancelRunningQueryF   func(context.Context, platform.ID) error
+}
+
+// NewRunningQueryService returns a mock of RunningQueryService where its methods will return zero values.
+func NewRunningQueryService() *RunningQueryService {
+	return &RunningQueryService{
+		FindRunningQueriesF: func(context.Context, platform.RunningQueryFilter) ([]*platform.RunningQuery, error) {
+			return nil, nil
+		},
+		FindRunningQueryByIDF: func(context.Context, platform.ID) (*platform.RunningQuery, error) {
+			return nil, nil
+		},
+		CancelRunningQueryF: func(context.Context, platform.ID) error {
+			return nil
+		},
+	}
+}
+
+func (s *RunningQueryService) FindRunningQueries(ctx context.Context, filter platform.RunningQueryFilter) ([]*platform.RunningQuery, error) {
+	return s.FindRunningQueriesF(ctx, filter)
+}
+
+func (s *RunningQueryService) FindRunningQueryByID(ctx context.Context, id platform.ID) (*platform.RunningQuery, error) {
+	return s.FindRunningQueryByIDF(ctx, id)
+}
+
+func (s *RunningQueryService) CancelRunningQuery(ctx context.Context, id platform.ID) error {
+	return s.CancelRunningQueryF(ctx, id)
+}
@@ -11,7 +11,7 @@ var _ platform.ScraperTargetStoreService = &ScraperTargetStoreService{}
 // ScraperTargetStoreService is a mock implementation of a platform.ScraperTargetStoreService.
 type ScraperTargetStoreService struct {
 	UserResourceMappingService
-	ListTargetsF   func(ctx context.Context) ([]platform.ScraperTarget, error)
+	ListTargetsF   func(ctx context.Context, opt ...platform.FindOptions) ([]platform.ScraperTarget, error)
 	AddTargetF     func(ctx context.Context, t *platform.ScraperTarget, userID platform.ID) error
 	GetTargetByIDF func(ctx context.Context, id platform.ID) (*platform.ScraperTarget, error)
 	RemoveTargetF  func(ctx context.Context, id platform.ID) error
@@ -19,8 +19,8 @@ type ScraperTargetStoreService struct {
 }
 
 // ListTargets lists all the scraper targets.
-func (s *ScraperTargetStoreService) ListTargets(ctx context.Context) ([]platform.ScraperTarget, error) {
-	return s.ListTargetsF(ctx)
+func (s *ScraperTargetStoreService) ListTargets(ctx context.Context, opt ...platform.FindOptions) ([]platform.ScraperTarget, error) {
+	return s.ListTargetsF(ctx, opt...)
 }
 
 // AddTarget adds a scraper target.
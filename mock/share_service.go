@@ -0,0 +1,44 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.ShareService = &ShareService{}
+
+type ShareService struct {
+	FindShareByIDF func(context.Context, platform.ID) (*platform.ShareGrant, error)
+	FindSharesF    func(context.Context, platform.ShareFilter) ([]*platform.ShareGrant, int, error)
+	CreateShareF   func(context.Context, *platform.ShareGrant) error
+	DeleteShareF   func(context.Context, platform.ID) error
+}
+
+// NewShareService returns a mock of ShareService where its methods will return zero values.
+func NewShareService() *ShareService {
+	return &ShareService{
+		FindShareByIDF: func(context.Context, platform.ID) (*platform.ShareGrant, error) { return nil, nil },
+		FindSharesF: func(context.Context, platform.ShareFilter) ([]*platform.ShareGrant, int, error) {
+			return nil, 0, nil
+		},
+		CreateShareF: func(context.Context, *platform.ShareGrant) error { return nil },
+		DeleteShareF: func(context.Context, platform.ID) error { return nil },
+	}
+}
+
+func (s *ShareService) FindShareByID(ctx context.Context, id platform.ID) (*platform.ShareGrant, error) {
+	return s.FindShareByIDF(ctx, id)
+}
+
+func (s *ShareService) FindShares(ctx context.Context, filter platform.ShareFilter) ([]*platform.ShareGrant, int, error) {
+	return s.FindSharesF(ctx, filter)
+}
+
+func (s *ShareService) CreateShare(ctx context.Context, g *platform.ShareGrant) error {
+	return s.CreateShareF(ctx, g)
+}
+
+func (s *ShareService) DeleteShare(ctx context.Context, id platform.ID) error {
+	return s.DeleteShareF(ctx, id)
+}
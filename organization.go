@@ -8,6 +8,12 @@ type Organization struct {
 	Name string `json:"name"`
 }
 
+// Cursor returns the position of the organization in a listing sorted by sortBy.
+// Organizations may be sorted by ID or Name.
+func (o *Organization) Cursor(sortBy string) (PageCursor, error) {
+	return namePageCursor(sortBy, o.Name, o.ID)
+}
+
 // ops for orgs error and orgs op logs.
 const (
 	OpFindOrganizationByID = "FindOrganizationByID"
@@ -1,7 +1,12 @@
 package influxdb
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
 	"strconv"
+	"time"
 )
 
 const (
@@ -28,6 +33,9 @@ type FindOptions struct {
 	Offset     int
 	SortBy     string
 	Descending bool
+	// After is an opaque cursor token; when set, results start immediately
+	// after the resource it refers to and Offset is ignored.
+	After string
 }
 
 // QueryParams returns a map containing url query params.
@@ -45,5 +53,171 @@ func (f FindOptions) QueryParams() map[string][]string {
 		qp["sortBy"] = []string{f.SortBy}
 	}
 
+	if f.After != "" {
+		qp["after"] = []string{f.After}
+		delete(qp, "offset")
+	}
+
 	return qp
 }
+
+// SortedByID returns true if results are ordered by resource ID, which is the
+// order in which stores keep resources and so can be paged without sorting.
+func (f FindOptions) SortedByID() bool {
+	return f.SortBy == "" || f.SortBy == "ID"
+}
+
+// PageCursor is the position of a resource within a sorted listing.
+// Resources are ordered by Key, the value of the field the listing is
+// sorted by, and then by ID so that every position is unique.
+type PageCursor struct {
+	SortBy string `json:"s,omitempty"`
+	Key    string `json:"k,omitempty"`
+	ID     string `json:"id"`
+}
+
+// Pageable is a resource that can be listed a page at a time.
+type Pageable interface {
+	// Cursor returns the position of the resource in a listing sorted by the field sortBy.
+	Cursor(sortBy string) (PageCursor, error)
+}
+
+// Encode returns the opaque token used to refer to the cursor in FindOptions.After.
+func (c PageCursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodePageCursor decodes a token created by PageCursor.Encode.
+func DecodePageCursor(token string) (PageCursor, error) {
+	var c PageCursor
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil {
+		err = json.Unmarshal(b, &c)
+	}
+	if err == nil {
+		_, err = IDFromString(c.ID)
+	}
+	if err != nil {
+		return c, &Error{
+			Code: EInvalid,
+			Msg:  "invalid page cursor",
+			Err:  err,
+		}
+	}
+	return c, nil
+}
+
+// less orders cursors by key and then by ID.
+func (c PageCursor) less(o PageCursor) bool {
+	if c.Key != o.Key {
+		return c.Key < o.Key
+	}
+	return c.ID < o.ID
+}
+
+// NewPageCursor returns the cursor of the resource id whose sort field sortBy has the value key.
+func NewPageCursor(sortBy, key string, id ID) PageCursor {
+	return PageCursor{
+		SortBy: sortBy,
+		Key:    key,
+		ID:     id.String(),
+	}
+}
+
+// idPageCursor returns the cursor of a resource in a listing ordered by ID;
+// it is the only order supported by resources without a name or timestamps.
+func idPageCursor(sortBy string, id ID) (PageCursor, error) {
+	if sortBy != "" && sortBy != "ID" {
+		return PageCursor{}, ErrUnsupportedSortField(sortBy)
+	}
+	return NewPageCursor(sortBy, "", id), nil
+}
+
+// namePageCursor returns the cursor of a named resource in a listing ordered by ID or Name.
+func namePageCursor(sortBy, name string, id ID) (PageCursor, error) {
+	if sortBy == "Name" {
+		return NewPageCursor(sortBy, name, id), nil
+	}
+	return idPageCursor(sortBy, id)
+}
+
+// timePageKey formats t so that keys of different times order lexically.
+func timePageKey(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000000000Z")
+}
+
+// ErrUnsupportedSortField is returned when a listing cannot be sorted by the field sortBy.
+func ErrUnsupportedSortField(sortBy string) error {
+	return &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("unsupported sort field %q", sortBy),
+	}
+}
+
+// Paginate sorts ps by opts.SortBy and returns the page described by opts.
+func Paginate(ps []Pageable, opts FindOptions) ([]Pageable, error) {
+	cursors := make([]PageCursor, len(ps))
+	for i, p := range ps {
+		c, err := p.Cursor(opts.SortBy)
+		if err != nil {
+			return nil, err
+		}
+		cursors[i] = c
+	}
+
+	sort.Sort(&pageSorter{ps: ps, cursors: cursors, descending: opts.Descending})
+
+	start := 0
+	if opts.After != "" {
+		after, err := DecodePageCursor(opts.After)
+		if err != nil {
+			return nil, err
+		}
+		if after.SortBy != opts.SortBy {
+			return nil, &Error{
+				Code: EInvalid,
+				Msg:  "page cursor does not match the sort order",
+			}
+		}
+
+		start = sort.Search(len(cursors), func(i int) bool {
+			if opts.Descending {
+				return cursors[i].less(after)
+			}
+			return after.less(cursors[i])
+		})
+	} else if opts.Offset > 0 {
+		start = opts.Offset
+	}
+
+	if start > len(ps) {
+		start = len(ps)
+	}
+	end := len(ps)
+	if opts.Limit > 0 && start+opts.Limit < end {
+		end = start + opts.Limit
+	}
+
+	return ps[start:end], nil
+}
+
+type pageSorter struct {
+	ps         []Pageable
+	cursors    []PageCursor
+	descending bool
+}
+
+func (s *pageSorter) Len() int { return len(s.ps) }
+
+func (s *pageSorter) Less(i, j int) bool {
+	if s.descending {
+		return s.cursors[j].less(s.cursors[i])
+	}
+	return s.cursors[i].less(s.cursors[j])
+}
+
+func (s *pageSorter) Swap(i, j int) {
+	s.ps[i], s.ps[j] = s.ps[j], s.ps[i]
+	s.cursors[i], s.cursors[j] = s.cursors[j], s.cursors[i]
+}
@@ -0,0 +1,115 @@
+package influxdb_test
+
+import (
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+func pagingTestBuckets() []platform.Pageable {
+	return []platform.Pageable{
+		&platform.Bucket{ID: 3, Name: "b"},
+		&platform.Bucket{ID: 1, Name: "c"},
+		&platform.Bucket{ID: 4, Name: "a"},
+		&platform.Bucket{ID: 2, Name: "b"},
+	}
+}
+
+func pagedIDs(ps []platform.Pageable) []platform.ID {
+	ids := make([]platform.ID, 0, len(ps))
+	for _, p := range ps {
+		ids = append(ids, p.(*platform.Bucket).ID)
+	}
+	return ids
+}
+
+func TestPaginate(t *testing.T) {
+	cursor := func(sortBy, key string, id platform.ID) string {
+		return platform.NewPageCursor(sortBy, key, id).Encode()
+	}
+
+	tests := []struct {
+		name string
+		opts platform.FindOptions
+		want []platform.ID
+	}{
+		{
+			name: "by ID",
+			opts: platform.FindOptions{},
+			want: []platform.ID{1, 2, 3, 4},
+		},
+		{
+			name: "by name descending",
+			opts: platform.FindOptions{SortBy: "Name", Descending: true},
+			want: []platform.ID{1, 3, 2, 4},
+		},
+		{
+			name: "by name with offset and limit",
+			opts: platform.FindOptions{SortBy: "Name", Offset: 1, Limit: 2},
+			want: []platform.ID{2, 3},
+		},
+		{
+			name: "by name after cursor",
+			opts: platform.FindOptions{SortBy: "Name", After: cursor("Name", "b", 2), Limit: 2},
+			want: []platform.ID{3, 1},
+		},
+		{
+			name: "by name descending after cursor",
+			opts: platform.FindOptions{SortBy: "Name", Descending: true, After: cursor("Name", "b", 3)},
+			want: []platform.ID{2, 4},
+		},
+		{
+			name: "after a removed resource",
+			opts: platform.FindOptions{After: cursor("", "", 0x2a)},
+			want: []platform.ID{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ps, err := platform.Paginate(pagingTestBuckets(), tt.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := pagedIDs(ps)
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("expected %v, got %v", tt.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestPaginate_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		opts platform.FindOptions
+	}{
+		{
+			name: "unsupported sort field",
+			opts: platform.FindOptions{SortBy: "RetentionPeriod"},
+		},
+		{
+			name: "malformed cursor",
+			opts: platform.FindOptions{After: "bad"},
+		},
+		{
+			name: "cursor of another sort order",
+			opts: platform.FindOptions{SortBy: "Name", After: platform.NewPageCursor("", "", 1).Encode()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := platform.Paginate(pagingTestBuckets(), tt.opts)
+			if platform.ErrorCode(err) != platform.EInvalid {
+				t.Fatalf("expected invalid error, got %v", err)
+			}
+		})
+	}
+}
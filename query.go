@@ -1,5 +1,10 @@
 package influxdb
 
+import (
+	"context"
+	"time"
+)
+
 // TODO(desa): These files are possibly a temporary. This is needed
 // as a part of the source work that is being done.
 // See https://github.com/influxdata/platform/issues/594 for more info.
@@ -9,3 +14,47 @@ type SourceQuery struct {
 	Query string `json:"query"`
 	Type  string `json:"type"`
 }
+
+// ErrRunningQueryNotFound is the error msg for a missing running query.
+const ErrRunningQueryNotFound = "running query not found"
+
+// ops for running queries.
+const (
+	OpFindRunningQueries   = "FindRunningQueries"
+	OpFindRunningQueryByID = "FindRunningQueryByID"
+	OpCancelRunningQuery   = "CancelRunningQuery"
+)
+
+// RunningQueryService lists and cancels the queries being run.
+type RunningQueryService interface {
+	// FindRunningQueries returns the queries being run that match filter.
+	FindRunningQueries(ctx context.Context, filter RunningQueryFilter) ([]*RunningQuery, error)
+
+	// FindRunningQueryByID returns a single query being run by ID.
+	FindRunningQueryByID(ctx context.Context, id ID) (*RunningQuery, error)
+
+	// CancelRunningQuery stops the execution of a query being run.
+	CancelRunningQuery(ctx context.Context, id ID) error
+}
+
+// RunningQuery describes a query being run.
+type RunningQuery struct {
+	ID              ID     `json:"id"`
+	OrganizationID  ID     `json:"orgID"`
+	AuthorizationID ID     `json:"authorizationID,omitempty"`
+	UserID          ID     `json:"userID,omitempty"`
+	CompilerType    string `json:"compilerType"`
+	Query           string `json:"query"`
+	// State is the state of the query in the controller, such as queueing or executing.
+	State string    `json:"state"`
+	Start time.Time `json:"start"`
+	// Elapsed is the time since the query was received, in nanoseconds.
+	Elapsed time.Duration `json:"elapsed"`
+	// MaxAllocated is the maximum number of bytes allocated by the query so far.
+	MaxAllocated int64 `json:"maxAllocated"`
+}
+
+// RunningQueryFilter represents a set of filters that restrict the returned running queries.
+type RunningQueryFilter struct {
+	OrganizationID *ID
+}
@@ -0,0 +1,190 @@
+package control
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query"
+)
+
+// admission holds the queries waiting to be handed to the flux controller.
+// Queries are admitted by priority, and in order within a priority, as long
+// as the number of admitted queries and the memory they reserve stay within
+// the global and per-org budgets.
+type admission struct {
+	maxQueries     int
+	maxQueueLength int
+	queueTimeout   time.Duration
+	queryMemory    int64
+	memory         int64
+	orgMemory      int64
+
+	metrics *admissionMetrics
+
+	mu        sync.Mutex
+	queues    [query.NumPriorities]*list.List
+	waiting   int
+	admitted  int
+	reserved  int64
+	orgsUsage map[platform.ID]int64
+}
+
+// ticket is a query waiting in, or admitted through, the admission queue.
+type ticket struct {
+	orgID    platform.ID
+	priority query.Priority
+	memory   int64
+
+	// ready is closed once the query is admitted.
+	ready chan struct{}
+}
+
+func newAdmission(c Config, metrics *admissionMetrics) *admission {
+	a := &admission{
+		maxQueries:     c.maxConcurrentQueries(),
+		maxQueueLength: c.MaxQueueLength,
+		queueTimeout:   c.QueueTimeout,
+		queryMemory:    c.QueryMemoryBytesQuota,
+		memory:         c.MemoryBytesQuota,
+		orgMemory:      c.OrgMemoryBytesQuota,
+		metrics:        metrics,
+		orgsUsage:      make(map[platform.ID]int64),
+	}
+	for i := range a.queues {
+		a.queues[i] = list.New()
+	}
+	return a
+}
+
+// fits reports whether t can be admitted within the global budgets,
+// and whether it can be admitted within the budget of its org.
+func (a *admission) fits(t *ticket) (global, org bool) {
+	global = a.admitted < a.maxQueries && (a.memory <= 0 || a.reserved+t.memory <= a.memory)
+	org = a.orgMemory <= 0 || a.orgsUsage[t.orgID]+t.memory <= a.orgMemory
+	return global, org
+}
+
+func (a *admission) admit(t *ticket) {
+	a.admitted++
+	a.reserved += t.memory
+	a.orgsUsage[t.orgID] += t.memory
+	close(t.ready)
+}
+
+// dispatch admits the waiting queries that fit. Queries of an org over its
+// budget are passed over, but no query is admitted ahead of one that only
+// waits on the global budgets.
+func (a *admission) dispatch() {
+	for p, q := range a.queues {
+		for e := q.Front(); e != nil; {
+			t := e.Value.(*ticket)
+			global, org := a.fits(t)
+			if !global {
+				return
+			}
+			next := e.Next()
+			if org {
+				a.removeLocked(e, query.Priority(p))
+				a.admit(t)
+			}
+			e = next
+		}
+	}
+}
+
+// acquire waits until a query of the org orgID with priority p may be
+// handed to the flux controller. The returned ticket must be released
+// once the query is done.
+func (a *admission) acquire(ctx context.Context, orgID platform.ID, p query.Priority) (*ticket, error) {
+	t := &ticket{
+		orgID:    orgID,
+		priority: p,
+		memory:   a.queryMemory,
+		ready:    make(chan struct{}),
+	}
+	priority := p.String()
+	start := time.Now()
+
+	a.mu.Lock()
+	e := a.queues[p].PushBack(t)
+	a.waiting++
+	a.metrics.queueLength.WithLabelValues(priority).Inc()
+	a.dispatch()
+	select {
+	case <-t.ready:
+		a.mu.Unlock()
+		a.metrics.waitDuration.WithLabelValues(priority).Observe(0)
+		return t, nil
+	default:
+	}
+	if a.maxQueueLength > 0 && a.waiting > a.maxQueueLength {
+		a.removeLocked(e, p)
+		a.mu.Unlock()
+		a.metrics.rejected.WithLabelValues(priority, "queue_full").Inc()
+		return nil, &platform.Error{
+			Code: platform.ETooManyRequests,
+			Msg:  "query queue is full",
+		}
+	}
+	a.mu.Unlock()
+
+	var timeout <-chan time.Time
+	if a.queueTimeout > 0 {
+		timer := time.NewTimer(a.queueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	var err error
+	select {
+	case <-t.ready:
+		a.metrics.waitDuration.WithLabelValues(priority).Observe(time.Since(start).Seconds())
+		return t, nil
+	case <-timeout:
+		a.metrics.rejected.WithLabelValues(priority, "timeout").Inc()
+		err = &platform.Error{
+			Code: platform.ETooManyRequests,
+			Msg:  "timed out waiting in the query queue",
+		}
+	case <-ctx.Done():
+		a.metrics.rejected.WithLabelValues(priority, "canceled").Inc()
+		err = ctx.Err()
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	select {
+	case <-t.ready:
+		// The query was admitted as it gave up waiting.
+		a.releaseLocked(t)
+	default:
+		a.removeLocked(e, p)
+	}
+	return nil, err
+}
+
+// removeLocked removes a ticket from the queue of priority p.
+func (a *admission) removeLocked(e *list.Element, p query.Priority) {
+	a.queues[p].Remove(e)
+	a.waiting--
+	a.metrics.queueLength.WithLabelValues(p.String()).Dec()
+}
+
+// release returns the budget reserved by an admitted query.
+func (a *admission) release(t *ticket) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.releaseLocked(t)
+}
+
+func (a *admission) releaseLocked(t *ticket) {
+	a.admitted--
+	a.reserved -= t.memory
+	if a.orgsUsage[t.orgID] -= t.memory; a.orgsUsage[t.orgID] <= 0 {
+		delete(a.orgsUsage, t.orgID)
+	}
+	a.dispatch()
+}
@@ -0,0 +1,192 @@
+package control
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux/control"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query"
+)
+
+func newTestAdmission(c Config) *admission {
+	return newAdmission(c, newAdmissionMetrics())
+}
+
+// acquireAsync acquires a ticket in the background, reporting it once admitted.
+func acquireAsync(t *testing.T, a *admission, orgID platform.ID, p query.Priority) <-chan *ticket {
+	t.Helper()
+	ch := make(chan *ticket, 1)
+	go func() {
+		tk, err := a.acquire(context.Background(), orgID, p)
+		if err != nil {
+			t.Error(err)
+		}
+		ch <- tk
+	}()
+
+	// Wait for the query to be queued.
+	for i := 0; ; i++ {
+		a.mu.Lock()
+		n := a.queues[p].Len()
+		a.mu.Unlock()
+		if n > 0 {
+			return ch
+		}
+		if i == 1000 {
+			t.Fatal("query was not queued")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestAdmission_Priority(t *testing.T) {
+	a := newTestAdmission(Config{Config: control.Config{ConcurrencyQuota: 1}})
+
+	first, err := a.acquire(context.Background(), 1, query.PriorityBackground)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	background := acquireAsync(t, a, 1, query.PriorityBackground)
+	task := acquireAsync(t, a, 1, query.PriorityTask)
+	interactive := acquireAsync(t, a, 1, query.PriorityInteractive)
+
+	a.release(first)
+	var second *ticket
+	select {
+	case second = <-interactive:
+	case <-task:
+		t.Fatal("task query admitted before the interactive one")
+	case <-background:
+		t.Fatal("background query admitted before the interactive one")
+	}
+
+	a.release(second)
+	third := <-task
+	a.release(third)
+	a.release(<-background)
+
+	if a.admitted != 0 || a.waiting != 0 {
+		t.Fatalf("expected empty admission, got %d admitted and %d waiting", a.admitted, a.waiting)
+	}
+}
+
+func TestAdmission_OrgMemory(t *testing.T) {
+	a := newTestAdmission(Config{
+		Config:                control.Config{ConcurrencyQuota: 10, MemoryBytesQuota: 300},
+		QueryMemoryBytesQuota: 100,
+		OrgMemoryBytesQuota:   200,
+	})
+	ctx := context.Background()
+
+	var org1 []*ticket
+	for i := 0; i < 2; i++ {
+		tk, err := a.acquire(ctx, 1, query.PriorityInteractive)
+		if err != nil {
+			t.Fatal(err)
+		}
+		org1 = append(org1, tk)
+	}
+
+	// The org is over its budget, but other orgs are not held behind it.
+	waiting := acquireAsync(t, a, 1, query.PriorityInteractive)
+	org2, err := a.acquire(ctx, 2, query.PriorityInteractive)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The global budget is spent.
+	blocked := acquireAsync(t, a, 3, query.PriorityInteractive)
+
+	a.release(org2)
+	select {
+	case <-waiting:
+		t.Fatal("query admitted over the budget of its org")
+	case tk := <-blocked:
+		a.release(tk)
+	}
+
+	a.release(org1[0])
+	a.release(<-waiting)
+	a.release(org1[1])
+
+	if a.reserved != 0 || len(a.orgsUsage) != 0 {
+		t.Fatalf("expected no reserved memory, got %d and %v", a.reserved, a.orgsUsage)
+	}
+}
+
+func TestAdmission_QueueFull(t *testing.T) {
+	a := newTestAdmission(Config{
+		Config:         control.Config{ConcurrencyQuota: 1},
+		MaxQueueLength: 1,
+	})
+	ctx := context.Background()
+
+	first, err := a.acquire(ctx, 1, query.PriorityInteractive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waiting := acquireAsync(t, a, 1, query.PriorityInteractive)
+
+	if _, err := a.acquire(ctx, 1, query.PriorityInteractive); platform.ErrorCode(err) != platform.ETooManyRequests {
+		t.Fatalf("expected a queue full error, got %v", err)
+	}
+
+	a.release(first)
+	a.release(<-waiting)
+}
+
+func TestAdmission_Timeout(t *testing.T) {
+	a := newTestAdmission(Config{
+		Config:       control.Config{ConcurrencyQuota: 1},
+		QueueTimeout: 10 * time.Millisecond,
+	})
+
+	first, err := a.acquire(context.Background(), 1, query.PriorityInteractive)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := a.acquire(context.Background(), 1, query.PriorityInteractive); platform.ErrorCode(err) != platform.ETooManyRequests {
+		t.Fatalf("expected a timeout error, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := a.acquire(ctx, 1, query.PriorityInteractive); err != context.Canceled {
+		t.Fatalf("expected a canceled error, got %v", err)
+	}
+
+	if a.waiting != 0 {
+		t.Fatalf("expected no waiting queries, got %d", a.waiting)
+	}
+	a.release(first)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		c     Config
+		valid bool
+	}{
+		{name: "no concurrency", c: Config{}},
+		{name: "concurrency", c: Config{Config: control.Config{ConcurrencyQuota: 1}}, valid: true},
+		{name: "max concurrent queries", c: Config{MaxConcurrentQueries: 1}, valid: true},
+		{
+			name: "query over memory quota",
+			c:    Config{Config: control.Config{ConcurrencyQuota: 1, MemoryBytesQuota: 1}, QueryMemoryBytesQuota: 2},
+		},
+		{
+			name: "query over org memory quota",
+			c:    Config{Config: control.Config{ConcurrencyQuota: 1}, QueryMemoryBytesQuota: 2, OrgMemoryBytesQuota: 1},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.c.Validate(); (err == nil) != tt.valid {
+				t.Fatalf("expected valid %v, got %v", tt.valid, err)
+			}
+		})
+	}
+}
@@ -3,9 +3,16 @@ package control
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"sync"
+	"time"
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/control"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/plan"
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/query"
 	"github.com/prometheus/client_golang/prometheus"
@@ -14,26 +21,117 @@ import (
 // orgLabel is the metric label to use in the controller
 const orgLabel = "org"
 
+var _ platform.RunningQueryService = (*Controller)(nil)
+
+// Config configures a Controller.
+type Config struct {
+	// Config configures the flux controller the queries are handed to once admitted.
+	// Its MemoryBytesQuota is the memory all admitted queries may reserve at once;
+	// zero means no global memory budget.
+	control.Config
+
+	// MaxConcurrentQueries is the number of queries admitted at once.
+	// It defaults to ConcurrencyQuota, since every query takes at least one worker.
+	MaxConcurrentQueries int
+
+	// QueryMemoryBytesQuota is the memory reserved by, and available to, each query.
+	// Zero means queries are not limited and reserve nothing from the memory budgets.
+	QueryMemoryBytesQuota int64
+
+	// OrgMemoryBytesQuota is the memory the admitted queries of a single organization
+	// may reserve at once. Zero means no per-org memory budget.
+	OrgMemoryBytesQuota int64
+
+	// MaxQueueLength is the number of queries that may wait to be admitted.
+	// Queries beyond it fail right away. Zero means no limit.
+	MaxQueueLength int
+
+	// QueueTimeout is how long a query may wait to be admitted.
+	// Zero means queries wait as long as their context allows.
+	QueueTimeout time.Duration
+}
+
+func (c Config) maxConcurrentQueries() int {
+	if c.MaxConcurrentQueries > 0 {
+		return c.MaxConcurrentQueries
+	}
+	return c.ConcurrencyQuota
+}
+
+// Validate returns an error if queries could never be admitted with c.
+func (c Config) Validate() error {
+	if c.maxConcurrentQueries() <= 0 {
+		return errors.New("the query concurrency quota must be positive")
+	}
+	if c.QueryMemoryBytesQuota < 0 {
+		return errors.New("the query memory quota must not be negative")
+	}
+	if c.MemoryBytesQuota > 0 && c.QueryMemoryBytesQuota > c.MemoryBytesQuota {
+		return errors.New("the query memory quota must not exceed the memory quota")
+	}
+	if c.OrgMemoryBytesQuota > 0 && c.QueryMemoryBytesQuota > c.OrgMemoryBytesQuota {
+		return errors.New("the query memory quota must not exceed the org memory quota")
+	}
+	return nil
+}
+
 // Controller implements AsyncQueryService by consuming a control.Controller.
+// Queries wait in an admission queue until they fit in the concurrency and
+// memory budgets, interactive queries first.
 type Controller struct {
 	c *control.Controller
+
+	admission *admission
+	metrics   *admissionMetrics
+
+	mu      sync.RWMutex
+	running map[platform.ID]*runningQuery
 }
 
 // NewController creates a new Controller specific to platform.
-func New(config control.Config) *Controller {
-	config.MetricLabelKeys = append(config.MetricLabelKeys, orgLabel)
-	c := control.New(config)
-	return &Controller{c: c}
+func New(config Config) *Controller {
+	metrics := newAdmissionMetrics()
+
+	fc := config.Config
+	fc.MetricLabelKeys = append(fc.MetricLabelKeys, orgLabel)
+	// The admission queue keeps queries within the memory budget; the flux
+	// controller counts the memory of limited queries only against its own.
+	if fc.MemoryBytesQuota <= 0 {
+		fc.MemoryBytesQuota = math.MaxInt64
+	}
+	if config.QueryMemoryBytesQuota > 0 {
+		fc.PPlannerOptions = append(fc.PPlannerOptions, plan.WithDefaultMemoryLimit(config.QueryMemoryBytesQuota))
+	}
+
+	return &Controller{
+		c:         control.New(fc),
+		admission: newAdmission(config, metrics),
+		metrics:   metrics,
+		running:   make(map[platform.ID]*runningQuery),
+	}
 }
 
 // Query satisfies the AsyncQueryService while ensuring the request is propagated on the context.
+// It waits for the query to be admitted, with the priority given by the context.
 func (c *Controller) Query(ctx context.Context, req *query.Request) (flux.Query, error) {
+	start := time.Now()
+
+	p := query.PriorityFromContext(ctx)
+	if p < 0 || int(p) >= query.NumPriorities {
+		p = query.PriorityBackground
+	}
+	t, err := c.admission.acquire(ctx, req.OrganizationID, p)
+	if err != nil {
+		return nil, err
+	}
+
 	// Set the request on the context so platform specific Flux operations can retrieve it later.
 	ctx = query.ContextWithRequest(ctx, req)
 	// Set the org label value for controller metrics
 	ctx = context.WithValue(ctx, orgLabel, req.OrganizationID.String())
 	q, err := c.c.Query(ctx, req.Compiler)
 	if err != nil {
+		c.admission.release(t)
 		// If the controller reports an error, it's usually because of a syntax error
 		// or other problem that the client must fix.
 		return q, &platform.Error{
@@ -42,12 +140,142 @@ func (c *Controller) Query(ctx context.Context, req *query.Request) (flux.Query,
 		}
 	}
 
-	return q, nil
+	return c.track(q, t, req, start), nil
+}
+
+// runningQuery is a query being run along with the request it was made from.
+type runningQuery struct {
+	flux.Query
+
+	id     platform.ID
+	ticket *ticket
+	req    *query.Request
+	start  time.Time
+	c      *Controller
+
+	done sync.Once
+}
+
+// Done frees the resources of the query and stops reporting it as running.
+func (q *runningQuery) Done() {
+	q.done.Do(func() {
+		q.c.mu.Lock()
+		delete(q.c.running, q.id)
+		q.c.mu.Unlock()
+
+		q.Query.Done()
+		q.c.admission.release(q.ticket)
+	})
+}
+
+func (c *Controller) track(q flux.Query, t *ticket, req *query.Request, start time.Time) flux.Query {
+	rq := &runningQuery{
+		Query:  q,
+		ticket: t,
+		req:    req,
+		start:  start,
+		c:      c,
+	}
+
+	// The queries of the flux controller carry their own ID; other
+	// implementations of flux.Query are not reported.
+	fq, ok := q.(*control.Query)
+	if !ok {
+		return rq
+	}
+	rq.id = platform.ID(fq.ID())
+
+	c.mu.Lock()
+	c.running[rq.id] = rq
+	c.mu.Unlock()
+	return rq
+}
+
+func (q *runningQuery) describe(now time.Time) *platform.RunningQuery {
+	rq := &platform.RunningQuery{
+		ID:             q.id,
+		OrganizationID: q.req.OrganizationID,
+		CompilerType:   string(q.req.Compiler.CompilerType()),
+		Query:          compilerQuery(q.req.Compiler),
+		State:          q.Query.(*control.Query).State().String(),
+		Start:          q.start,
+		Elapsed:        now.Sub(q.start),
+		MaxAllocated:   q.Statistics().MaxAllocated,
+	}
+	if a := q.req.Authorization; a != nil {
+		rq.AuthorizationID = a.ID
+		rq.UserID = a.UserID
+	}
+	return rq
+}
+
+// compilerQuery returns the text of a Flux query, or the JSON
+// representation of the compiler for other query languages.
+func compilerQuery(c flux.Compiler) string {
+	if fc, ok := c.(lang.FluxCompiler); ok {
+		return fc.Query
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// FindRunningQueries returns the queries being run that match filter.
+func (c *Controller) FindRunningQueries(ctx context.Context, filter platform.RunningQueryFilter) ([]*platform.RunningQuery, error) {
+	now := time.Now()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	qs := make([]*platform.RunningQuery, 0, len(c.running))
+	for _, q := range c.running {
+		if filter.OrganizationID != nil && q.req.OrganizationID != *filter.OrganizationID {
+			continue
+		}
+		qs = append(qs, q.describe(now))
+	}
+	return qs, nil
+}
+
+// FindRunningQueryByID returns a single query being run by ID.
+func (c *Controller) FindRunningQueryByID(ctx context.Context, id platform.ID) (*platform.RunningQuery, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	q, ok := c.running[id]
+	if !ok {
+		return nil, &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  platform.ErrRunningQueryNotFound,
+			Op:   platform.OpFindRunningQueryByID,
+		}
+	}
+	return q.describe(time.Now()), nil
+}
+
+// CancelRunningQuery stops the execution of a query being run.
+// The query is reported as running until whoever made it has released it.
+func (c *Controller) CancelRunningQuery(ctx context.Context, id platform.ID) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	q, ok := c.running[id]
+	if !ok {
+		return &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  platform.ErrRunningQueryNotFound,
+			Op:   platform.OpCancelRunningQuery,
+		}
+	}
+	q.Cancel()
+	return nil
 }
 
 // PrometheusCollectors satisifies the prom.PrometheusCollector interface.
 func (c *Controller) PrometheusCollectors() []prometheus.Collector {
-	return c.c.PrometheusCollectors()
+	return append(c.c.PrometheusCollectors(), c.metrics.PrometheusCollectors()...)
 }
 
 // Shutdown shuts down the underlying Controller.
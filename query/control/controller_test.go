@@ -0,0 +1,79 @@
+package control_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/flux/control"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/lang"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query"
+	_ "github.com/influxdata/influxdb/query/builtin"
+	pcontrol "github.com/influxdata/influxdb/query/control"
+)
+
+func TestController_RunningQueries(t *testing.T) {
+	ctx := context.Background()
+
+	c := pcontrol.New(pcontrol.Config{
+		Config: control.Config{
+			ExecutorDependencies: make(execute.Dependencies),
+			ConcurrencyQuota:     1,
+		},
+	})
+	defer c.Shutdown(ctx)
+
+	// The query runs until its results are read or it is canceled.
+	const text = `import "csv"
+csv.from(csv: "#datatype,string,long,dateTime:RFC3339,double\n#group,false,false,false,false\n#default,_result,,,\n,result,table,_time,_value\n,,0,2018-05-22T19:53:26Z,1.0\n")`
+	orgID := platform.ID(1)
+	q, err := c.Query(ctx, &query.Request{
+		Authorization:  &platform.Authorization{ID: 2, UserID: 3},
+		OrganizationID: orgID,
+		Compiler:       lang.FluxCompiler{Query: text},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	qs, err := c.FindRunningQueries(ctx, platform.RunningQueryFilter{OrganizationID: &orgID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qs) != 1 {
+		t.Fatalf("expected one running query, got %d", len(qs))
+	}
+	rq := qs[0]
+	if rq.OrganizationID != orgID || rq.AuthorizationID != 2 || rq.UserID != 3 || rq.Query != text || rq.CompilerType != string(lang.FluxCompilerType) {
+		t.Fatalf("unexpected running query %+v", rq)
+	}
+
+	otherOrgID := platform.ID(4)
+	if qs, err := c.FindRunningQueries(ctx, platform.RunningQueryFilter{OrganizationID: &otherOrgID}); err != nil {
+		t.Fatal(err)
+	} else if len(qs) != 0 {
+		t.Fatalf("expected no running queries in another org, got %d", len(qs))
+	}
+
+	if err := c.CancelRunningQuery(ctx, rq.ID); err != nil {
+		t.Fatal(err)
+	}
+	if rq, err := c.FindRunningQueryByID(ctx, rq.ID); err != nil {
+		t.Fatal(err)
+	} else if rq.State != "canceled" {
+		t.Fatalf("expected canceled query, got state %q", rq.State)
+	}
+
+	// Canceled queries are reported until they are released.
+	for range q.Ready() {
+	}
+	q.Done()
+
+	if _, err := c.FindRunningQueryByID(ctx, rq.ID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+	if err := c.CancelRunningQuery(ctx, rq.ID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
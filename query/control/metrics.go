@@ -0,0 +1,48 @@
+package control
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// admissionMetrics holds metrics related to the admission queue of the controller.
+type admissionMetrics struct {
+	queueLength  *prometheus.GaugeVec
+	waitDuration *prometheus.HistogramVec
+	rejected     *prometheus.CounterVec
+}
+
+func newAdmissionMetrics() *admissionMetrics {
+	const (
+		namespace = "query"
+		subsystem = "admission"
+	)
+
+	return &admissionMetrics{
+		queueLength: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "queue_length",
+			Help:      "Number of queries waiting to be admitted, split out by priority.",
+		}, []string{"priority"}),
+		waitDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "wait_duration_seconds",
+			Help:      "Time queries waited to be admitted, split out by priority.",
+			Buckets:   prometheus.ExponentialBuckets(1e-3, 5, 7),
+		}, []string{"priority"}),
+		rejected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "rejected_total",
+			Help:      "Number of queries that gave up waiting to be admitted, split out by priority and reason.",
+		}, []string{"priority", "reason"}),
+	}
+}
+
+// PrometheusCollectors satisfies the prom.PrometheusCollector interface.
+func (m *admissionMetrics) PrometheusCollectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.queueLength,
+		m.waitDuration,
+		m.rejected,
+	}
+}
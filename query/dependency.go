@@ -2,6 +2,7 @@ package query
 
 import (
 	"context"
+	"fmt"
 
 	platform "github.com/influxdata/influxdb"
 )
@@ -16,6 +17,9 @@ func FromBucketService(srv platform.BucketService) *BucketLookup {
 // BucketLookup converts Flux bucket lookups into platform.BucketService calls.
 type BucketLookup struct {
 	BucketService platform.BucketService
+	// ShareService, when set, is used to find the buckets that other
+	// organizations have shared with an organization.
+	ShareService platform.ShareService
 }
 
 // Lookup returns the bucket id and its existence given an org id and bucket name.
@@ -32,17 +36,103 @@ func (b *BucketLookup) Lookup(orgID platform.ID, name string) (platform.ID, bool
 	return bucket.ID, true
 }
 
-func (b *BucketLookup) FindAllBuckets(orgID platform.ID) ([]*platform.Bucket, int) {
+// LookupShared returns the bucket named name that another organization has
+// shared with orgID for reading. A name shared by several organizations is
+// ambiguous and is not resolved. It returns an ENotFound error if no bucket
+// named name is shared with orgID.
+func (b *BucketLookup) LookupShared(ctx context.Context, orgID platform.ID, name string) (*platform.Bucket, error) {
+	buckets, err := b.sharedBuckets(ctx, orgID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var found *platform.Bucket
+	for _, bucket := range buckets {
+		if bucket.Name != name {
+			continue
+		}
+		if found != nil {
+			return nil, &platform.Error{
+				Code: platform.EConflict,
+				Msg:  fmt.Sprintf("bucket %q is shared by several organizations", name),
+			}
+		}
+		found = bucket
+	}
+	if found == nil {
+		return nil, &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  fmt.Sprintf("bucket %q is not shared with the organization", name),
+		}
+	}
+	return found, nil
+}
+
+// LookupSharedByID returns the bucket with ID id if another organization has
+// shared it with orgID for reading. It returns an ENotFound error otherwise.
+func (b *BucketLookup) LookupSharedByID(ctx context.Context, orgID, id platform.ID) (*platform.Bucket, error) {
+	buckets, err := b.sharedBuckets(ctx, orgID, &id)
+	if err != nil {
+		return nil, err
+	}
+	if len(buckets) == 0 {
+		return nil, &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  fmt.Sprintf("bucket %s is not shared with the organization", id),
+		}
+	}
+	return buckets[0], nil
+}
+
+// FindAllBuckets returns the buckets of orgID followed by the buckets shared with it.
+func (b *BucketLookup) FindAllBuckets(ctx context.Context, orgID platform.ID) ([]*platform.Bucket, int, error) {
 	oid := platform.ID(orgID)
 	filter := platform.BucketFilter{
 		OrganizationID: &oid,
 	}
-	buckets, count, err := b.BucketService.FindBuckets(context.Background(), filter)
+	buckets, count, err := b.BucketService.FindBuckets(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	shared, err := b.sharedBuckets(ctx, orgID, nil)
 	if err != nil {
-		return nil, count
+		return nil, 0, err
 	}
-	return buckets, count
+	return append(buckets, shared...), count + len(shared), nil
+}
 
+// sharedBuckets returns the buckets shared with orgID for reading, or only the
+// bucket with ID id if id is not nil. Shares of buckets that no longer exist are
+// ignored.
+func (b *BucketLookup) sharedBuckets(ctx context.Context, orgID platform.ID, id *platform.ID) ([]*platform.Bucket, error) {
+	if b.ShareService == nil {
+		return nil, nil
+	}
+
+	gs, _, err := b.ShareService.FindShares(ctx, platform.ShareFilter{
+		ResourceID:   id,
+		ResourceType: platform.BucketsResourceType,
+		TargetOrgID:  &orgID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := make([]*platform.Bucket, 0, len(gs))
+	for _, g := range gs {
+		if !g.Allows(platform.ReadAction) {
+			continue
+		}
+		bucket, err := b.BucketService.FindBucketByID(ctx, g.ResourceID)
+		if platform.ErrorCode(err) == platform.ENotFound {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, bucket)
+	}
+	return buckets, nil
 }
 
 // FromOrganizationService wraps a platform.OrganizationService in the OrganizationLookup interface.
@@ -0,0 +1,86 @@
+package query_test
+
+import (
+	"context"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/query"
+)
+
+func TestBucketLookup_LookupShared(t *testing.T) {
+	buckets := map[platform.ID]*platform.Bucket{
+		1: {ID: 1, OrganizationID: 10, Name: "reference"},
+		2: {ID: 2, OrganizationID: 11, Name: "weather"},
+		3: {ID: 3, OrganizationID: 12, Name: "weather"},
+		4: {ID: 4, OrganizationID: 10, Name: "local"},
+	}
+	bs := mock.NewBucketService()
+	bs.FindBucketByIDFn = func(ctx context.Context, id platform.ID) (*platform.Bucket, error) {
+		return buckets[id], nil
+	}
+	bs.FindBucketsFn = func(ctx context.Context, filter platform.BucketFilter, opts ...platform.FindOptions) ([]*platform.Bucket, int, error) {
+		return []*platform.Bucket{buckets[4]}, 1, nil
+	}
+
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+
+	ss := mock.NewShareService()
+	ss.FindSharesF = func(ctx context.Context, filter platform.ShareFilter) ([]*platform.ShareGrant, int, error) {
+		if ctx.Value(ctxKey{}) == nil {
+			t.Error("FindShares was not called with the context of the lookup")
+		}
+		if *filter.TargetOrgID == 30 {
+			return nil, 0, &platform.Error{Code: platform.EInternal, Msg: "share store unavailable"}
+		}
+		if *filter.TargetOrgID != 20 {
+			return nil, 0, nil
+		}
+		var gs []*platform.ShareGrant
+		for _, g := range []*platform.ShareGrant{
+			{ResourceType: platform.BucketsResourceType, ResourceID: 1, OrgID: 10, TargetOrgID: 20, Action: platform.ReadAction},
+			{ResourceType: platform.BucketsResourceType, ResourceID: 2, OrgID: 11, TargetOrgID: 20, Action: platform.WriteAction},
+			{ResourceType: platform.BucketsResourceType, ResourceID: 3, OrgID: 12, TargetOrgID: 20, Action: platform.ReadAction},
+		} {
+			if filter.ResourceID == nil || *filter.ResourceID == g.ResourceID {
+				gs = append(gs, g)
+			}
+		}
+		return gs, len(gs), nil
+	}
+
+	l := query.FromBucketService(bs)
+	l.ShareService = ss
+
+	if b, err := l.LookupShared(ctx, 20, "reference"); err != nil || b.OrganizationID != 10 || b.ID != 1 {
+		t.Errorf("LookupShared(reference) = %v, %v; want bucket 0000000000000001 of 000000000000000a", b, err)
+	}
+	if _, err := l.LookupShared(ctx, 20, "weather"); platform.ErrorCode(err) != platform.EConflict {
+		t.Errorf("a name shared by several organizations must not resolve, got error %v", err)
+	}
+	if _, err := l.LookupShared(ctx, 21, "reference"); platform.ErrorCode(err) != platform.ENotFound {
+		t.Errorf("a bucket must not resolve for an organization it is not shared with, got error %v", err)
+	}
+	if _, err := l.LookupShared(ctx, 30, "reference"); platform.ErrorCode(err) != platform.EInternal {
+		t.Errorf("LookupShared() must return the error of the share service, got %v", err)
+	}
+
+	if b, err := l.LookupSharedByID(ctx, 20, 3); err != nil || b.OrganizationID != 12 {
+		t.Errorf("LookupSharedByID(3) = %v, %v; want bucket of 000000000000000c", b, err)
+	}
+	if b, err := l.LookupSharedByID(ctx, 20, 2); err != nil || b.OrganizationID != 11 {
+		t.Errorf("LookupSharedByID(2) = %v, %v; want bucket of 000000000000000b", b, err)
+	}
+	if _, err := l.LookupSharedByID(ctx, 20, 4); platform.ErrorCode(err) != platform.ENotFound {
+		t.Errorf("a bucket that is not shared must not resolve, got error %v", err)
+	}
+
+	if _, n, err := l.FindAllBuckets(ctx, 20); err != nil || n != 4 {
+		t.Errorf("FindAllBuckets() found %d buckets, %v; want the local bucket and 3 shared ones", n, err)
+	}
+	if _, _, err := l.FindAllBuckets(ctx, 30); platform.ErrorCode(err) != platform.EInternal {
+		t.Errorf("FindAllBuckets() must return the error of the share service, got %v", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"sync"
 	"time"
 
 	"github.com/influxdata/flux"
@@ -18,6 +19,7 @@ type LoggingServiceBridge struct {
 // Query executes and logs the query.
 func (s *LoggingServiceBridge) Query(ctx context.Context, w io.Writer, req *ProxyRequest) (n int64, err error) {
 	var stats flux.Statistics
+	start := time.Now()
 	defer func() {
 		r := recover()
 		if r != nil {
@@ -28,6 +30,7 @@ func (s *LoggingServiceBridge) Query(ctx context.Context, w io.Writer, req *Prox
 			ProxyRequest:   req,
 			ResponseSize:   n,
 			Time:           time.Now(),
+			Duration:       time.Since(start),
 			Statistics:     stats,
 		}
 		if err != nil {
@@ -57,3 +60,49 @@ func (s *LoggingServiceBridge) Query(ctx context.Context, w io.Writer, req *Prox
 	// The results iterator may have had an error independent of encoding errors.
 	return n, results.Err()
 }
+
+// LoggingAsyncServiceBridge implements AsyncQueryService and logs the queries
+// once they are done, while consuming an AsyncQueryService interface.
+type LoggingAsyncServiceBridge struct {
+	AsyncQueryService AsyncQueryService
+	QueryLogger       Logger
+}
+
+// Query starts the query and logs it once it is done.
+func (s LoggingAsyncServiceBridge) Query(ctx context.Context, req *Request) (flux.Query, error) {
+	start := time.Now()
+	q, err := s.AsyncQueryService.Query(ctx, req)
+	if err != nil {
+		s.log(req, start, err, flux.Statistics{})
+		return nil, err
+	}
+	return &loggingQuery{
+		Query: q,
+		log: func() {
+			s.log(req, start, q.Err(), q.Statistics())
+		},
+	}, nil
+}
+
+func (s LoggingAsyncServiceBridge) log(req *Request, start time.Time, err error, stats flux.Statistics) {
+	s.QueryLogger.Log(Log{
+		OrganizationID: req.OrganizationID,
+		ProxyRequest:   &ProxyRequest{Request: *req},
+		Time:           time.Now(),
+		Duration:       time.Since(start),
+		Error:          err,
+		Statistics:     stats,
+	})
+}
+
+// loggingQuery is a flux.Query that is logged the first time it is done.
+type loggingQuery struct {
+	flux.Query
+	once sync.Once
+	log  func()
+}
+
+func (q *loggingQuery) Done() {
+	q.Query.Done()
+	q.once.Do(q.log)
+}
@@ -0,0 +1,62 @@
+package query_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/mock"
+)
+
+type logs []query.Log
+
+func (l *logs) Log(q query.Log) error {
+	*l = append(*l, q)
+	return nil
+}
+
+// doneQuery is a flux.Query that has finished with an error.
+type doneQuery struct {
+	flux.Query
+	err error
+}
+
+func (q *doneQuery) Done()                       {}
+func (q *doneQuery) Err() error                  { return q.err }
+func (q *doneQuery) Statistics() flux.Statistics { return flux.Statistics{ScannedValues: 7} }
+
+func TestLoggingAsyncServiceBridge(t *testing.T) {
+	boom := errors.New("boom")
+	var l logs
+	s := query.LoggingAsyncServiceBridge{
+		AsyncQueryService: &mock.AsyncQueryService{
+			QueryF: func(ctx context.Context, req *query.Request) (flux.Query, error) {
+				return &doneQuery{err: boom}, nil
+			},
+		},
+		QueryLogger: &l,
+	}
+
+	q, err := s.Query(context.Background(), &query.Request{OrganizationID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(l) != 0 {
+		t.Fatalf("expected the query not to be logged before it is done, got %d logs", len(l))
+	}
+
+	// The query is logged once, however many times it is done.
+	q.Done()
+	q.Done()
+	if len(l) != 1 {
+		t.Fatalf("expected the query to be logged once, got %d logs", len(l))
+	}
+	if got := l[0]; got.OrganizationID != 1 || got.Error != boom || got.Statistics.ScannedValues != 7 {
+		t.Fatalf("unexpected log %+v", got)
+	}
+	if l[0].ProxyRequest == nil || l[0].ProxyRequest.Request.OrganizationID != 1 {
+		t.Fatalf("expected the request to be logged, got %+v", l[0].ProxyRequest)
+	}
+}
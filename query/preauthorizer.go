@@ -18,11 +18,26 @@ type PreAuthorizer interface {
 
 // NewPreAuthorizer creates a new PreAuthorizer
 func NewPreAuthorizer(bucketService platform.BucketService) PreAuthorizer {
-	return &preAuthorizer{bucketService: bucketService}
+	return NewSharedPreAuthorizer(bucketService, nil)
+}
+
+// NewSharedPreAuthorizer creates a new PreAuthorizer that also resolves the
+// buckets other organizations have shared with the organization of the
+// request on context, or of the authorization if there is none, the way
+// queries resolve them at runtime.
+func NewSharedPreAuthorizer(bucketService platform.BucketService, shareService platform.ShareService) PreAuthorizer {
+	return &preAuthorizer{
+		bucketService: bucketService,
+		lookup: &BucketLookup{
+			BucketService: bucketService,
+			ShareService:  shareService,
+		},
+	}
 }
 
 type preAuthorizer struct {
 	bucketService platform.BucketService
+	lookup        *BucketLookup
 }
 
 // PreAuthorize finds all the buckets read and written by the given spec, and ensures that execution is allowed
@@ -34,8 +49,10 @@ func (a *preAuthorizer) PreAuthorize(ctx context.Context, spec *flux.Spec, auth
 		return errors.Wrap(err, "could not retrieve buckets for query.Spec")
 	}
 
+	orgID := requestOrgID(ctx, auth)
+
 	for _, readBucketFilter := range readBuckets {
-		bucket, err := a.bucketService.FindBucket(ctx, readBucketFilter)
+		bucket, err := a.findBucket(ctx, orgID, readBucketFilter)
 		if err != nil {
 			return errors.Wrapf(err, "could not find read bucket with filter: %s", readBucketFilter)
 		}
@@ -44,30 +61,94 @@ func (a *preAuthorizer) PreAuthorize(ctx context.Context, spec *flux.Spec, auth
 			return errors.New("bucket service returned nil bucket")
 		}
 
-		reqPerm, err := platform.NewPermissionAtID(bucket.ID, platform.ReadAction, platform.BucketsResourceType, bucket.OrganizationID)
+		allowed, err := a.allowed(ctx, auth, orgID, bucket, platform.ReadAction)
 		if err != nil {
-			return errors.Wrapf(err, "could not create read bucket permission")
+			return errors.Wrapf(err, "could not check read bucket permission")
 		}
 
-		if !auth.Allowed(*reqPerm) {
+		if !allowed {
 			return errors.New("no read permission for bucket: \"" + bucket.Name + "\"")
 		}
 	}
 
 	for _, writeBucketFilter := range writeBuckets {
-		bucket, err := a.bucketService.FindBucket(ctx, writeBucketFilter)
+		bucket, err := a.findBucket(ctx, orgID, writeBucketFilter)
 		if err != nil {
 			return errors.Wrapf(err, "could not find write bucket with filter: %s", writeBucketFilter)
 		}
 
-		reqPerm, err := platform.NewPermissionAtID(bucket.ID, platform.WriteAction, platform.BucketsResourceType, bucket.OrganizationID)
+		allowed, err := a.allowed(ctx, auth, orgID, bucket, platform.WriteAction)
 		if err != nil {
-			return errors.Wrapf(err, "could not create write bucket permission")
+			return errors.Wrapf(err, "could not check write bucket permission")
 		}
-		if !auth.Allowed(*reqPerm) {
+		if !allowed {
 			return errors.New("no write permission for bucket: \"" + bucket.Name + "\"")
 		}
 	}
 
 	return nil
 }
+
+// requestOrgID returns the ID of the organization making the query.
+func requestOrgID(ctx context.Context, auth platform.Authorizer) platform.ID {
+	if req := RequestFromContext(ctx); req != nil && req.OrganizationID.Valid() {
+		return req.OrganizationID
+	}
+	if a, ok := auth.(*platform.Authorization); ok {
+		return a.OrgID
+	}
+	return platform.InvalidID()
+}
+
+// findBucket finds the bucket of filter. A bucket named in the filter that is
+// not found is looked up among the buckets shared with orgID, through the same
+// path as the buckets read by queries.
+func (a *preAuthorizer) findBucket(ctx context.Context, orgID platform.ID, filter platform.BucketFilter) (*platform.Bucket, error) {
+	bucket, err := a.bucketService.FindBucket(ctx, filter)
+	if err == nil || a.lookup.ShareService == nil || filter.Name == nil || !orgID.Valid() || platform.ErrorCode(err) != platform.ENotFound {
+		return bucket, err
+	}
+
+	shared, lerr := a.lookup.LookupShared(ctx, orgID, *filter.Name)
+	if platform.ErrorCode(lerr) == platform.ENotFound {
+		return nil, err
+	}
+	return shared, lerr
+}
+
+// allowed reports whether auth is allowed the action on bucket, either within
+// the organization owning it or within orgID if the bucket is shared with it.
+func (a *preAuthorizer) allowed(ctx context.Context, auth platform.Authorizer, orgID platform.ID, bucket *platform.Bucket, action platform.Action) (bool, error) {
+	reqPerm, err := platform.NewPermissionAtID(bucket.ID, action, platform.BucketsResourceType, bucket.OrganizationID)
+	if err != nil {
+		return false, err
+	}
+	if auth.Allowed(*reqPerm) {
+		return true, nil
+	}
+
+	if a.lookup.ShareService == nil || !orgID.Valid() || orgID == bucket.OrganizationID {
+		return false, nil
+	}
+	gs, _, err := a.lookup.ShareService.FindShares(ctx, platform.ShareFilter{
+		ResourceID:   &bucket.ID,
+		ResourceType: platform.BucketsResourceType,
+		TargetOrgID:  &orgID,
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, g := range gs {
+		if !g.Allows(action) {
+			continue
+		}
+		p, err := platform.NewPermissionAtID(bucket.ID, action, platform.BucketsResourceType, orgID)
+		if err != nil {
+			return false, err
+		}
+		if auth.Allowed(*p) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -2,6 +2,8 @@ package query_test
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -81,3 +83,62 @@ func TestPreAuthorizer_PreAuthorize(t *testing.T) {
 		t.Errorf("Expected successful authorization, but got error: \"%v\"", err.Error())
 	}
 }
+
+func TestPreAuthorizer_PreAuthorizeShared(t *testing.T) {
+	ctx := context.Background()
+	q := `from(bucket:"my_bucket") |> range(start:-2h) |> to(bucket:"shared_bucket", org:"my_org")`
+	spec, err := flux.Compile(ctx, q, time.Now().UTC())
+	if err != nil {
+		t.Fatalf("Error compiling query: %v", err)
+	}
+
+	orgID, ownerID := platform.ID(1), platform.ID(2)
+	shared := &platform.Bucket{ID: platform.ID(3), OrganizationID: ownerID, Name: "shared_bucket"}
+	bs := mock.NewBucketService()
+	bs.FindBucketFn = func(ctx context.Context, filter platform.BucketFilter) (*platform.Bucket, error) {
+		return nil, &platform.Error{Code: platform.ENotFound, Msg: "bucket not found"}
+	}
+	bs.FindBucketByIDFn = func(ctx context.Context, id platform.ID) (*platform.Bucket, error) {
+		if id != shared.ID {
+			return nil, &platform.Error{Code: platform.ENotFound, Msg: "bucket not found"}
+		}
+		return shared, nil
+	}
+
+	action := platform.ReadAction
+	ss := mock.NewShareService()
+	ss.FindSharesF = func(ctx context.Context, filter platform.ShareFilter) ([]*platform.ShareGrant, int, error) {
+		g := &platform.ShareGrant{
+			ResourceType: platform.BucketsResourceType,
+			ResourceID:   shared.ID,
+			OrgID:        ownerID,
+			TargetOrgID:  orgID,
+			Action:       action,
+		}
+		return []*platform.ShareGrant{g}, 1, nil
+	}
+
+	p, err := platform.NewPermission(platform.WriteAction, platform.BucketsResourceType, orgID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := &platform.Authorization{Status: platform.Active, OrgID: orgID, Permissions: []platform.Permission{*p}}
+
+	// Shared buckets are only resolved with a share service.
+	err = query.NewPreAuthorizer(bs).PreAuthorize(ctx, spec, auth)
+	if err == nil || !strings.Contains(err.Error(), "could not find write bucket") {
+		t.Fatalf("expected the shared bucket not to be found, got %v", err)
+	}
+
+	// The bucket is shared for reading only.
+	preAuthorizer := query.NewSharedPreAuthorizer(bs, ss)
+	err = preAuthorizer.PreAuthorize(ctx, spec, auth)
+	if diagnostic := cmp.Diff(`no write permission for bucket: "shared_bucket"`, fmt.Sprint(err)); diagnostic != "" {
+		t.Errorf("Authorize message mismatch: -want/+got:\n%v", diagnostic)
+	}
+
+	action = platform.WriteAction
+	if err := preAuthorizer.PreAuthorize(ctx, spec, auth); err != nil {
+		t.Errorf("Expected successful authorization, but got error: \"%v\"", err.Error())
+	}
+}
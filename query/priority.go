@@ -0,0 +1,66 @@
+package query
+
+import (
+	"context"
+
+	"github.com/influxdata/flux"
+)
+
+// Priority orders the queries waiting to be admitted by a controller.
+// Queries of a lower value are admitted first.
+type Priority int
+
+const (
+	// PriorityInteractive is the priority of queries made on behalf of a
+	// user waiting for the result, such as HTTP queries from dashboards.
+	PriorityInteractive Priority = iota
+	// PriorityTask is the priority of the queries of task runs.
+	PriorityTask
+	// PriorityBackground is the priority of internal queries no one is waiting on.
+	PriorityBackground
+
+	// NumPriorities is the number of query priorities.
+	NumPriorities = int(PriorityBackground) + 1
+)
+
+// String returns the name of the priority.
+func (p Priority) String() string {
+	switch p {
+	case PriorityInteractive:
+		return "interactive"
+	case PriorityTask:
+		return "task"
+	case PriorityBackground:
+		return "background"
+	default:
+		return "unknown"
+	}
+}
+
+type priorityContextKey struct{}
+
+// ContextWithPriority returns a new context with the priority of the queries made with it.
+func ContextWithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityContextKey{}, p)
+}
+
+// PriorityFromContext returns the priority of the queries made with ctx.
+// Queries are interactive unless the context says otherwise.
+func PriorityFromContext(ctx context.Context) Priority {
+	p, ok := ctx.Value(priorityContextKey{}).(Priority)
+	if !ok {
+		return PriorityInteractive
+	}
+	return p
+}
+
+// PriorityServiceBridge implements AsyncQueryService and makes all queries with the same priority.
+type PriorityServiceBridge struct {
+	AsyncQueryService AsyncQueryService
+	Priority          Priority
+}
+
+// Query makes the query with the priority of the bridge.
+func (b PriorityServiceBridge) Query(ctx context.Context, req *Request) (flux.Query, error) {
+	return b.AsyncQueryService.Query(ContextWithPriority(ctx, b.Priority), req)
+}
@@ -0,0 +1,169 @@
+// Package querylog provides a query.Logger that keeps a record of completed queries.
+package querylog
+
+import (
+	"encoding/json"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap"
+)
+
+const (
+	measurement = "queries"
+
+	userIDTag = "userID"
+	statusTag = "status"
+
+	requestField         = "request"
+	errorField           = "error"
+	durationField        = "duration"
+	responseSizeField    = "responseSize"
+	totalDurationField   = "totalDuration"
+	compileDurationField = "compileDuration"
+	queueDurationField   = "queueDuration"
+	planDurationField    = "planDuration"
+	requeueDurationField = "requeueDuration"
+	executeDurationField = "executeDuration"
+	concurrencyField     = "concurrency"
+	maxAllocatedField    = "maxAllocated"
+	scannedValuesField   = "scannedValues"
+	scannedBytesField    = "scannedBytes"
+
+	statusSuccess = "success"
+	statusFailed  = "failed"
+
+	// SystemBucketID is the fixed ID of the system bucket each organization's
+	// query log is written to.
+	SystemBucketID platform.ID = 11
+)
+
+// PointsWriter is a copy of the storage.PointsWriter interface.
+// Duplicating it here to avoid having querylog depend directly on storage.
+type PointsWriter interface {
+	WritePoints(points []models.Point) error
+}
+
+var _ query.Logger = (*PointLogger)(nil)
+
+// PointLogger writes completed queries as time-series points to the system
+// bucket of the organization that ran them. Queries that take at least the
+// slow query threshold are also logged. If the points writer is nil, queries
+// are only logged when they are slow.
+type PointLogger struct {
+	pointsWriter PointsWriter
+	logger       *zap.Logger
+
+	// SlowQueryThreshold is the duration from which a query is logged as slow.
+	// Slow queries are not logged when it is zero.
+	SlowQueryThreshold time.Duration
+}
+
+// NewPointLogger returns a PointLogger.
+func NewPointLogger(pw PointsWriter, logger *zap.Logger) *PointLogger {
+	return &PointLogger{
+		pointsWriter: pw,
+		logger:       logger,
+	}
+}
+
+// Log writes the redacted query q to the query log of its organization.
+func (l *PointLogger) Log(q query.Log) error {
+	q.Redact()
+
+	var req []byte
+	if q.ProxyRequest != nil && q.ProxyRequest.Request.Compiler != nil {
+		var err error
+		if req, err = json.Marshal(q.ProxyRequest.Request); err != nil {
+			return err
+		}
+	}
+
+	if l.SlowQueryThreshold > 0 && q.Duration >= l.SlowQueryThreshold {
+		l.logSlowQuery(q, req)
+	}
+	if l.pointsWriter == nil {
+		return nil
+	}
+
+	status := statusSuccess
+	if q.Error != nil {
+		status = statusFailed
+	}
+	tags := models.Tags{
+		models.NewTag([]byte(statusTag), []byte(status)),
+	}
+	if id := userID(q); id.Valid() {
+		tags = append(tags, models.NewTag([]byte(userIDTag), []byte(id.String())))
+	}
+
+	s := q.Statistics
+	fields := map[string]interface{}{
+		durationField:        int64(q.Duration),
+		responseSizeField:    q.ResponseSize,
+		totalDurationField:   int64(s.TotalDuration),
+		compileDurationField: int64(s.CompileDuration),
+		queueDurationField:   int64(s.QueueDuration),
+		planDurationField:    int64(s.PlanDuration),
+		requeueDurationField: int64(s.RequeueDuration),
+		executeDurationField: int64(s.ExecuteDuration),
+		concurrencyField:     int64(s.Concurrency),
+		maxAllocatedField:    s.MaxAllocated,
+		scannedValuesField:   int64(s.ScannedValues),
+		scannedBytesField:    int64(s.ScannedBytes),
+	}
+	if req != nil {
+		fields[requestField] = string(req)
+	}
+	if q.Error != nil {
+		fields[errorField] = q.Error.Error()
+	}
+
+	pt, err := models.NewPoint(measurement, tags, fields, q.Time)
+	if err != nil {
+		return err
+	}
+
+	exploded, err := tsdb.ExplodePoints(q.OrganizationID, SystemBucketID, []models.Point{pt})
+	if err != nil {
+		return err
+	}
+
+	return l.pointsWriter.WritePoints(exploded)
+}
+
+func (l *PointLogger) logSlowQuery(q query.Log, req []byte) {
+	s := q.Statistics
+	fields := []zap.Field{
+		zap.Stringer("org_id", q.OrganizationID),
+		zap.Duration("duration", q.Duration),
+		zap.Int64("response_size", q.ResponseSize),
+		zap.ByteString("request", req),
+		zap.Duration("compile_duration", s.CompileDuration),
+		zap.Duration("queue_duration", s.QueueDuration),
+		zap.Duration("plan_duration", s.PlanDuration),
+		zap.Duration("requeue_duration", s.RequeueDuration),
+		zap.Duration("execute_duration", s.ExecuteDuration),
+		zap.Int("concurrency", s.Concurrency),
+		zap.Int64("max_allocated", s.MaxAllocated),
+		zap.Int("scanned_values", s.ScannedValues),
+		zap.Int("scanned_bytes", s.ScannedBytes),
+	}
+	if id := userID(q); id.Valid() {
+		fields = append(fields, zap.Stringer("user_id", id))
+	}
+	if q.Error != nil {
+		fields = append(fields, zap.Error(q.Error))
+	}
+	l.logger.Warn("Slow query", fields...)
+}
+
+func userID(q query.Log) platform.ID {
+	if q.ProxyRequest == nil || q.ProxyRequest.Request.Authorization == nil {
+		return 0
+	}
+	return q.ProxyRequest.Request.Authorization.UserID
+}
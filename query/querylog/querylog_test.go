@@ -0,0 +1,143 @@
+package querylog_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/querylog"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type pointsWriter struct {
+	points []models.Point
+}
+
+func (w *pointsWriter) WritePoints(points []models.Point) error {
+	w.points = append(w.points, points...)
+	return nil
+}
+
+func newLog(d time.Duration, err error) query.Log {
+	return query.Log{
+		Time:           time.Unix(100, 0),
+		Duration:       d,
+		OrganizationID: 1,
+		Error:          err,
+		ProxyRequest: &query.ProxyRequest{
+			Request: query.Request{
+				Authorization:  &platform.Authorization{ID: 2, OrgID: 1, UserID: 3, Token: "secret"},
+				OrganizationID: 1,
+				Compiler:       lang.FluxCompiler{Query: `from(bucket: "b") |> range(start: -1h)`},
+			},
+		},
+		ResponseSize: 42,
+		Statistics: flux.Statistics{
+			TotalDuration: d,
+			ScannedValues: 7,
+		},
+	}
+}
+
+func TestPointLogger_Log(t *testing.T) {
+	w := &pointsWriter{}
+	core, logs := observer.New(zap.WarnLevel)
+	l := querylog.NewPointLogger(w, zap.New(core))
+	l.SlowQueryThreshold = time.Second
+
+	q := newLog(time.Millisecond, errors.New("boom"))
+	if err := l.Log(q); err != nil {
+		t.Fatal(err)
+	}
+
+	if q.ProxyRequest.Request.Authorization.Token != "secret" {
+		t.Fatal("logging should not modify the logged request")
+	}
+	if logs.Len() != 0 {
+		t.Fatalf("expected no slow query logs, got %d", logs.Len())
+	}
+
+	name := tsdb.EncodeName(1, querylog.SystemBucketID)
+	fields := map[string]bool{}
+	for _, pt := range w.points {
+		if !bytes.Equal(pt.Name(), name[:]) {
+			t.Fatalf("expected point to be written to the query log bucket, got %x", pt.Name())
+		}
+		if !pt.Time().Equal(q.Time) {
+			t.Fatalf("unexpected point time %v", pt.Time())
+		}
+		if s := pt.String(); strings.Contains(s, "secret") {
+			t.Fatalf("point contains the token: %s", s)
+		}
+		if got := string(pt.Tags().Get([]byte("status"))); got != "failed" {
+			t.Fatalf("unexpected status %q", got)
+		}
+		if got := string(pt.Tags().Get([]byte("userID"))); got != platform.ID(3).String() {
+			t.Fatalf("unexpected user ID %q", got)
+		}
+		fields[string(pt.Tags().Get(tsdb.FieldKeyTagKeyBytes))] = true
+	}
+	for _, f := range []string{"request", "error", "duration", "responseSize", "totalDuration", "scannedValues"} {
+		if !fields[f] {
+			t.Errorf("missing field %q in %v", f, fields)
+		}
+	}
+}
+
+func TestPointLogger_SlowQuery(t *testing.T) {
+	w := &pointsWriter{}
+	core, logs := observer.New(zap.WarnLevel)
+	l := querylog.NewPointLogger(w, zap.New(core))
+	l.SlowQueryThreshold = time.Second
+
+	if err := l.Log(newLog(2*time.Second, nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := logs.FilterMessage("Slow query").All()
+	if len(entries) != 1 {
+		t.Fatalf("expected one slow query log, got %d", len(entries))
+	}
+	ctx := entries[0].ContextMap()
+	if ctx["org_id"] != platform.ID(1).String() || ctx["user_id"] != platform.ID(3).String() {
+		t.Fatalf("unexpected slow query log %v", ctx)
+	}
+	if req, _ := ctx["request"].(string); !strings.Contains(req, "range(start: -1h)") || strings.Contains(req, "secret") {
+		t.Fatalf("unexpected request %q", req)
+	}
+
+	// Slow queries are not logged without a threshold.
+	l.SlowQueryThreshold = 0
+	if err := l.Log(newLog(time.Hour, nil)); err != nil {
+		t.Fatal(err)
+	}
+	if logs.Len() != 1 {
+		t.Fatalf("expected no more slow query logs, got %d", logs.Len())
+	}
+}
+
+func TestPointLogger_NoPointsWriter(t *testing.T) {
+	core, logs := observer.New(zap.WarnLevel)
+	l := querylog.NewPointLogger(nil, zap.New(core))
+	l.SlowQueryThreshold = time.Second
+
+	// Slow queries are logged without writing the query log.
+	if err := l.Log(newLog(time.Millisecond, nil)); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Log(newLog(2*time.Second, nil)); err != nil {
+		t.Fatal(err)
+	}
+	if got := logs.FilterMessage("Slow query").Len(); got != 1 {
+		t.Fatalf("expected one slow query log, got %d", got)
+	}
+}
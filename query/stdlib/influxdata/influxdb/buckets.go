@@ -1,6 +1,7 @@
 package influxdb
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/influxdata/flux"
@@ -19,6 +20,7 @@ func init() {
 }
 
 type BucketsDecoder struct {
+	ctx     context.Context
 	orgID   platform.ID
 	deps    BucketDependencies
 	buckets []*platform.Bucket
@@ -30,7 +32,10 @@ func (bd *BucketsDecoder) Connect() error {
 }
 
 func (bd *BucketsDecoder) Fetch() (bool, error) {
-	b, count := bd.deps.FindAllBuckets(bd.orgID)
+	b, count, err := bd.deps.FindAllBuckets(bd.ctx, bd.orgID)
+	if err != nil {
+		return false, err
+	}
 	if count <= 0 {
 		return false, fmt.Errorf("no buckets found in organization %v", bd.orgID)
 	}
@@ -116,13 +121,13 @@ func createBucketsSource(prSpec plan.ProcedureSpec, dsid execute.DatasetID, a ex
 	}
 	orgID := req.OrganizationID
 
-	bd := &BucketsDecoder{orgID: orgID, deps: deps, alloc: a.Allocator()}
+	bd := &BucketsDecoder{ctx: a.Context(), orgID: orgID, deps: deps, alloc: a.Allocator()}
 
 	return execute.CreateSourceFromDecoder(bd, dsid, a)
 }
 
 type AllBucketLookup interface {
-	FindAllBuckets(orgID platform.ID) ([]*platform.Bucket, int)
+	FindAllBuckets(ctx context.Context, orgID platform.ID) ([]*platform.Bucket, int, error)
 }
 type BucketDependencies AllBucketLookup
 
@@ -609,7 +609,19 @@ func createFromSource(prSpec plan.ProcedureSpec, dsid execute.DatasetID, a execu
 	case spec.Bucket != "":
 		b, ok := deps.BucketLookup.Lookup(orgID, spec.Bucket)
 		if !ok {
-			return nil, fmt.Errorf("could not find bucket %q", spec.Bucket)
+			// Buckets of the organization shadow those shared with it; data
+			// of a shared bucket is read from the organization owning it.
+			shared, isShared := deps.BucketLookup.(SharedBucketLookup)
+			if !isShared {
+				return nil, fmt.Errorf("could not find bucket %q", spec.Bucket)
+			}
+			bucket, err := shared.LookupShared(a.Context(), orgID, spec.Bucket)
+			if platform.ErrorCode(err) == platform.ENotFound {
+				return nil, fmt.Errorf("could not find bucket %q", spec.Bucket)
+			} else if err != nil {
+				return nil, err
+			}
+			orgID, b = bucket.OrganizationID, bucket.ID
 		}
 		bucketID = b
 	case len(spec.BucketID) != 0:
@@ -617,6 +629,17 @@ func createFromSource(prSpec plan.ProcedureSpec, dsid execute.DatasetID, a execu
 		if err != nil {
 			return nil, err
 		}
+
+		// Data of a bucket shared with the organization is read from the
+		// organization owning it.
+		if shared, ok := deps.BucketLookup.(SharedBucketLookup); ok {
+			bucket, err := shared.LookupSharedByID(a.Context(), orgID, bucketID)
+			if err == nil {
+				orgID = bucket.OrganizationID
+			} else if platform.ErrorCode(err) != platform.ENotFound {
+				return nil, err
+			}
+		}
 	}
 
 	return NewSource(
@@ -0,0 +1,87 @@
+package influxdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/memory"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query"
+)
+
+type mockAdministration struct {
+	ctx  context.Context
+	deps execute.Dependencies
+}
+
+func (a mockAdministration) Context() context.Context { return a.ctx }
+func (a mockAdministration) ResolveTime(qt flux.Time) execute.Time {
+	return execute.Time(qt.Absolute.UnixNano())
+}
+func (a mockAdministration) StreamContext() execute.StreamContext { return a }
+func (a mockAdministration) Allocator() *memory.Allocator         { return &memory.Allocator{} }
+func (a mockAdministration) Parents() []execute.DatasetID         { return nil }
+func (a mockAdministration) Dependencies() execute.Dependencies   { return a.deps }
+
+func (a mockAdministration) Bounds() *execute.Bounds {
+	return &execute.Bounds{Start: 0, Stop: 10}
+}
+
+// sharedLookup resolves the buckets of organization 20 and the bucket that
+// organization 10 shared with it.
+type sharedLookup struct{}
+
+func (sharedLookup) Lookup(orgID platform.ID, name string) (platform.ID, bool) {
+	if orgID == 20 && name == "local" {
+		return 2, true
+	}
+	return platform.InvalidID(), false
+}
+
+func (sharedLookup) LookupShared(ctx context.Context, orgID platform.ID, name string) (*platform.Bucket, error) {
+	if orgID == 20 && name == "reference" {
+		return &platform.Bucket{ID: 1, OrganizationID: 10, Name: name}, nil
+	}
+	return nil, &platform.Error{Code: platform.ENotFound, Msg: "bucket not found"}
+}
+
+func (sharedLookup) LookupSharedByID(ctx context.Context, orgID, id platform.ID) (*platform.Bucket, error) {
+	if orgID == 20 && id == 1 {
+		return &platform.Bucket{ID: 1, OrganizationID: 10, Name: "reference"}, nil
+	}
+	return nil, &platform.Error{Code: platform.ENotFound, Msg: "bucket not found"}
+}
+
+func TestCreateFromSource_SharedBucket(t *testing.T) {
+	a := mockAdministration{
+		ctx: query.ContextWithRequest(context.Background(), &query.Request{OrganizationID: 20}),
+		deps: execute.Dependencies{
+			FromKind: Dependencies{BucketLookup: sharedLookup{}},
+		},
+	}
+
+	for _, tt := range []struct {
+		name   string
+		spec   *FromProcedureSpec
+		org    platform.ID
+		bucket platform.ID
+	}{
+		{name: "shared bucket by name", spec: &FromProcedureSpec{Bucket: "reference"}, org: 10, bucket: 1},
+		{name: "shared bucket by ID", spec: &FromProcedureSpec{BucketID: platform.ID(1).String()}, org: 10, bucket: 1},
+		{name: "own bucket by name", spec: &FromProcedureSpec{Bucket: "local"}, org: 20, bucket: 2},
+		{name: "own bucket by ID", spec: &FromProcedureSpec{BucketID: platform.ID(2).String()}, org: 20, bucket: 2},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := createFromSource(tt.spec, execute.DatasetID{}, a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rs := s.(*source).readSpec
+			if rs.OrganizationID != tt.org || rs.BucketID != tt.bucket {
+				t.Errorf("read spec targets bucket %s of %s, want %s of %s", rs.BucketID, rs.OrganizationID, tt.bucket, tt.org)
+			}
+		})
+	}
+}
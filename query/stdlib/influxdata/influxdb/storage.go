@@ -22,6 +22,18 @@ type BucketLookup interface {
 	Lookup(orgID platform.ID, name string) (platform.ID, bool)
 }
 
+// SharedBucketLookup is implemented by a BucketLookup that also resolves the
+// buckets other organizations have shared with an organization.
+type SharedBucketLookup interface {
+	// LookupShared returns the bucket named name that has been shared with
+	// orgID, or an ENotFound error.
+	LookupShared(ctx context.Context, orgID platform.ID, name string) (*platform.Bucket, error)
+
+	// LookupSharedByID returns the bucket with ID id if it has been shared
+	// with orgID, or an ENotFound error.
+	LookupSharedByID(ctx context.Context, orgID, id platform.ID) (*platform.Bucket, error)
+}
+
 type OrganizationLookup interface {
 	Lookup(ctx context.Context, name string) (platform.ID, bool)
 }
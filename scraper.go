@@ -2,6 +2,9 @@ package influxdb
 
 import (
 	"context"
+	"fmt"
+	"regexp"
+	"time"
 )
 
 // ErrScraperTargetNotFound is the error msg for a missing scraper target.
@@ -14,6 +17,8 @@ const (
 	OpGetTargetByID = "GetTargetByID"
 	OpRemoveTarget  = "RemoveTarget"
 	OpUpdateTarget  = "UpdateTarget"
+
+	OpUpdateScraperStatus = "UpdateScraperStatus"
 )
 
 // ScraperTarget is a target to scrape
@@ -24,12 +29,204 @@ type ScraperTarget struct {
 	URL      string      `json:"url"`
 	OrgID    ID          `json:"orgID,omitempty"`
 	BucketID ID          `json:"bucketID,omitempty"`
+
+	// Interval is the time between scrapes of the target, in milliseconds.
+	// Zero scrapes the target at the interval of the scheduler.
+	Interval int64 `json:"interval,omitempty"`
+	// Timeout is the time allowed for a scrape, in milliseconds.
+	// Zero uses the timeout of the scheduler.
+	Timeout int64 `json:"timeout,omitempty"`
+
+	Auth *ScraperAuth `json:"auth,omitempty"`
+	TLS  *ScraperTLS  `json:"tls,omitempty"`
+
+	// MetricAllow and MetricDeny are regular expressions metric names must
+	// match, and must not match, to be recorded.
+	MetricAllow []string `json:"metricAllow,omitempty"`
+	MetricDeny  []string `json:"metricDeny,omitempty"`
+	// LabelAllow and LabelDeny are the names of the labels kept, and
+	// dropped, from the scraped metrics.
+	LabelAllow []string `json:"labelAllow,omitempty"`
+	LabelDeny  []string `json:"labelDeny,omitempty"`
+	// Relabel rules are applied in order after the allow and deny lists.
+	Relabel []RelabelRule `json:"relabel,omitempty"`
+
+	// Tags are added to every metric scraped from the target, replacing
+	// labels of the same name, before the metrics are filtered.
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// JSON configures the targets of type json.
+	JSON *ScraperJSON `json:"json,omitempty"`
+
+	// Status is the status of the last scrape of the target. It is set by
+	// the store and ignored when targets are added or updated.
+	Status *ScraperStatus `json:"status,omitempty"`
+}
+
+// Cursor returns the position of the scraper target in a listing sorted by sortBy.
+// Scraper targets may be sorted by ID or Name.
+func (t *ScraperTarget) Cursor(sortBy string) (PageCursor, error) {
+	return namePageCursor(sortBy, t.Name, t.ID)
+}
+
+// Scraper authentication types.
+const (
+	ScraperAuthBasic  = "basic"
+	ScraperAuthBearer = "bearer"
+)
+
+// ScraperAuth authenticates the requests of a scraper. Secrets are the keys of
+// secrets of the organization of the target in the SecretService.
+type ScraperAuth struct {
+	Type     string `json:"type"`
+	Username string `json:"username,omitempty"`
+	// PasswordSecret is the key of the password of basic authentication.
+	PasswordSecret string `json:"passwordSecret,omitempty"`
+	// TokenSecret is the key of the token of bearer authentication.
+	TokenSecret string `json:"tokenSecret,omitempty"`
+}
+
+// ScraperTLS configures the TLS connections of a scraper.
+type ScraperTLS struct {
+	// CA is the PEM encoded certificate authority the target must be signed by.
+	CA string `json:"ca,omitempty"`
+	// Cert is the PEM encoded client certificate presented to the target.
+	Cert string `json:"cert,omitempty"`
+	// KeySecret is the key of the secret holding the PEM encoded client key.
+	KeySecret          string `json:"keySecret,omitempty"`
+	ServerName         string `json:"serverName,omitempty"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify,omitempty"`
+}
+
+// ScraperJSON selects the values of the JSON document of a target recorded as
+// fields and tags. Paths are the keys of nested objects and the indexes of
+// arrays, joined with dots, like memstats.Alloc.
+type ScraperJSON struct {
+	// Measurement is the name of the recorded metric; json by default.
+	Measurement string `json:"measurement,omitempty"`
+	// Fields are the paths of the numbers and booleans recorded as fields,
+	// named after their paths. Without fields, all the numbers and booleans
+	// of the document outside of arrays are recorded.
+	Fields []string `json:"fields,omitempty"`
+	// Tags are the paths of the values recorded as tags, named after their
+	// paths.
+	Tags []string `json:"tags,omitempty"`
+}
+
+// Relabel actions.
+const (
+	RelabelReplace   = "replace"
+	RelabelKeep      = "keep"
+	RelabelDrop      = "drop"
+	RelabelLabelKeep = "labelkeep"
+	RelabelLabelDrop = "labeldrop"
+)
+
+// RelabelMetricName is the label holding the metric name in relabel rules.
+const RelabelMetricName = "__name__"
+
+// RelabelRule rewrites the labels of scraped metrics, as the relabel_configs
+// of Prometheus do.
+type RelabelRule struct {
+	// SourceLabels are joined with Separator, ";" by default, and matched
+	// against Regex, "(.*)" by default.
+	SourceLabels []string `json:"sourceLabels,omitempty"`
+	Separator    string   `json:"separator,omitempty"`
+	Regex        string   `json:"regex,omitempty"`
+	// TargetLabel is set to Replacement, "$1" by default, expanded with the
+	// submatches of Regex by the replace action.
+	TargetLabel string `json:"targetLabel,omitempty"`
+	Replacement string `json:"replacement,omitempty"`
+	// Action is one of replace, keep, drop, labelkeep and labeldrop;
+	// replace by default. labelkeep and labeldrop match Regex against
+	// label names.
+	Action string `json:"action,omitempty"`
+}
+
+// ScraperStatus is the outcome of the last scrape of a target.
+type ScraperStatus struct {
+	LastScrape time.Time `json:"lastScrape"`
+	// Duration is the time the scrape took, in milliseconds.
+	Duration int64 `json:"duration"`
+	// Samples is the number of metrics recorded.
+	Samples int    `json:"samples"`
+	Error   string `json:"error,omitempty"`
+}
+
+// ScraperStatusService records the status of scrapes.
+type ScraperStatusService interface {
+	UpdateScraperStatus(ctx context.Context, id ID, s *ScraperStatus) error
+}
+
+// Valid returns an error if a ScraperTarget contains invalid data.
+func (t *ScraperTarget) Valid() error {
+	if t.Type != "" && !ValidScraperType(string(t.Type)) {
+		return fmt.Errorf("invalid scraper type %q", t.Type)
+	}
+
+	if t.Interval < 0 || t.Timeout < 0 {
+		return fmt.Errorf("interval and timeout must not be negative")
+	}
+
+	if a := t.Auth; a != nil {
+		switch a.Type {
+		case ScraperAuthBasic:
+			if a.Username == "" {
+				return fmt.Errorf("basic auth requires a username")
+			}
+		case ScraperAuthBearer:
+			if a.TokenSecret == "" {
+				return fmt.Errorf("bearer auth requires a token secret")
+			}
+		default:
+			return fmt.Errorf("invalid auth type %q", a.Type)
+		}
+	}
+
+	if t.TLS != nil && (t.TLS.Cert == "") != (t.TLS.KeySecret == "") {
+		return fmt.Errorf("tls client certificate and key secret must be set together")
+	}
+
+	for _, expr := range append(append([]string{}, t.MetricAllow...), t.MetricDeny...) {
+		if _, err := regexp.Compile(expr); err != nil {
+			return fmt.Errorf("invalid metric pattern %q: %v", expr, err)
+		}
+	}
+
+	for _, r := range t.Relabel {
+		if err := r.Valid(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Valid returns an error if a RelabelRule contains invalid data.
+func (r RelabelRule) Valid() error {
+	if _, err := regexp.Compile(r.Regex); err != nil {
+		return fmt.Errorf("invalid relabel regex %q: %v", r.Regex, err)
+	}
+
+	switch r.Action {
+	case "", RelabelReplace:
+		if r.TargetLabel == "" {
+			return fmt.Errorf("relabel replace requires a target label")
+		}
+	case RelabelKeep, RelabelDrop:
+		if len(r.SourceLabels) == 0 {
+			return fmt.Errorf("relabel %s requires source labels", r.Action)
+		}
+	case RelabelLabelKeep, RelabelLabelDrop:
+	default:
+		return fmt.Errorf("invalid relabel action %q", r.Action)
+	}
+	return nil
 }
 
 // ScraperTargetStoreService defines the crud service for ScraperTarget.
 type ScraperTargetStoreService interface {
 	UserResourceMappingService
-	ListTargets(ctx context.Context) ([]ScraperTarget, error)
+	ListTargets(ctx context.Context, opt ...FindOptions) ([]ScraperTarget, error)
 	AddTarget(ctx context.Context, t *ScraperTarget, userID ID) error
 	GetTargetByID(ctx context.Context, id ID) (*ScraperTarget, error)
 	RemoveTarget(ctx context.Context, id ID) error
@@ -42,6 +239,20 @@ type ScraperTargetFilter struct {
 	Name *string `json:"name"`
 }
 
+// QueryParams converts ScraperTargetFilter fields to url query params.
+func (f ScraperTargetFilter) QueryParams() map[string][]string {
+	qp := map[string][]string{}
+	if f.ID != nil {
+		qp["id"] = []string{f.ID.String()}
+	}
+
+	if f.Name != nil {
+		qp["name"] = []string{*f.Name}
+	}
+
+	return qp
+}
+
 // ScraperType defines the scraper methods.
 type ScraperType string
 
@@ -49,12 +260,19 @@ type ScraperType string
 const (
 	// PrometheusScraperType parses metrics from a prometheus endpoint.
 	PrometheusScraperType = "prometheus"
+	// OpenMetricsScraperType parses metrics from an OpenMetrics endpoint.
+	OpenMetricsScraperType = "openmetrics"
+	// LineProtocolScraperType parses points from an endpoint serving line protocol.
+	LineProtocolScraperType = "lineprotocol"
+	// JSONScraperType reads fields from the JSON document of an endpoint,
+	// such as the expvar endpoint of Go programs.
+	JSONScraperType = "json"
 )
 
 // ValidScraperType returns true is the type string is valid
 func ValidScraperType(s string) bool {
 	switch s {
-	case PrometheusScraperType:
+	case PrometheusScraperType, OpenMetricsScraperType, LineProtocolScraperType, JSONScraperType:
 		return true
 	default:
 		return false
@@ -0,0 +1,112 @@
+package influxdb
+
+import (
+	"context"
+)
+
+// ErrShareNotFound is the error for a missing share grant.
+const ErrShareNotFound = "share grant not found"
+
+// ops for share grants.
+const (
+	OpFindShareByID = "FindShareByID"
+	OpFindShares    = "FindShares"
+	OpCreateShare   = "CreateShare"
+	OpDeleteShare   = "DeleteShare"
+)
+
+// ShareableResourceTypes is the list of resource types that may be shared with other organizations.
+var ShareableResourceTypes = []ResourceType{
+	BucketsResourceType,
+	DashboardsResourceType,
+}
+
+// ShareGrant gives a second organization access to a resource owned by another.
+// Members of the target organization are allowed the granted action on the
+// resource as if it belonged to the target organization; a write grant also
+// allows reading.
+type ShareGrant struct {
+	ID           ID           `json:"id,omitempty"`
+	ResourceType ResourceType `json:"resourceType"`
+	ResourceID   ID           `json:"resourceID"`
+	// OrgID is the organization owning the resource.
+	OrgID       ID     `json:"orgID,omitempty"`
+	TargetOrgID ID     `json:"targetOrgID"`
+	Action      Action `json:"action"`
+}
+
+// Valid returns an error if the grant is invalid.
+func (g *ShareGrant) Valid() error {
+	shareable := false
+	for _, rt := range ShareableResourceTypes {
+		if g.ResourceType == rt {
+			shareable = true
+			break
+		}
+	}
+	if !shareable {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "resource type " + string(g.ResourceType) + " cannot be shared",
+		}
+	}
+
+	if !g.ResourceID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "resource id is required",
+		}
+	}
+
+	if !g.TargetOrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "target organization id is required",
+		}
+	}
+
+	if g.TargetOrgID == g.OrgID {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "a resource cannot be shared with the organization owning it",
+		}
+	}
+
+	if g.Action != ReadAction && g.Action != WriteAction {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "unknown action " + string(g.Action),
+		}
+	}
+
+	return nil
+}
+
+// Allows reports whether the grant allows the action a on its resource.
+func (g *ShareGrant) Allows(a Action) bool {
+	return g.Action == a || g.Action == WriteAction
+}
+
+// ShareFilter represents a set of filters that restrict the returned share grants.
+type ShareFilter struct {
+	ResourceID   *ID
+	ResourceType ResourceType
+	OrgID        *ID
+	TargetOrgID  *ID
+}
+
+// ShareService represents a service for managing grants of resources to other organizations.
+type ShareService interface {
+	// FindShareByID returns a single share grant by ID.
+	FindShareByID(ctx context.Context, id ID) (*ShareGrant, error)
+
+	// FindShares returns a list of share grants that match filter and the total count of matching grants.
+	FindShares(ctx context.Context, filter ShareFilter) ([]*ShareGrant, int, error)
+
+	// CreateShare creates a new share grant and sets g.ID with the new identifier.
+	// The owning organization of the grant is that of the shared resource.
+	CreateShare(ctx context.Context, g *ShareGrant) error
+
+	// DeleteShare revokes a share grant.
+	DeleteShare(ctx context.Context, id ID) error
+}
@@ -123,6 +123,7 @@ func NewEngine(path string, c Config, options ...Option) *Engine {
 	// Initialize WAL
 	e.wal = wal.NewWAL(c.GetWALPath(path))
 	e.wal.WithFsyncDelay(time.Duration(c.WAL.FsyncDelay))
+	e.wal.WithCompression(c.WAL.Compression)
 	e.wal.EnableTraceLogging(c.TraceLoggingEnabled)
 	e.wal.SetEnabled(c.WAL.Enabled)
 
@@ -240,7 +241,7 @@ func (e *Engine) replayWAL() error {
 		switch en := entry.(type) {
 		case *wal.WriteWALEntry:
 			points := tsm1.ValuesToPoints(en.Values)
-			err := e.writePointsLocked(tsdb.NewSeriesCollection(points), en.Values)
+			err := e.writePointsLocked(tsdb.NewSeriesCollection(points), en.Values, nil)
 			if _, ok := err.(tsdb.PartialWriteError); ok {
 				err = nil
 			}
@@ -394,6 +395,21 @@ func (e *Engine) WritePoints(points []models.Point) error {
 	}
 	collection.Truncate(j)
 
+	// Convert the points to values for adding to the WAL/Cache.
+	values, err := tsm1.PointsToValues(collection.Points)
+	if err != nil {
+		return err
+	}
+
+	// Wait for room in the cache before taking the lock, which snapshots freeing
+	// up room need, and before writing to the WAL. The room stays reserved for
+	// the values until they are written to the cache.
+	reservation, err := e.engine.WaitForCacheRoom(values)
+	if err != nil {
+		return err
+	}
+	defer reservation.Release()
+
 	e.mu.RLock()
 	defer e.mu.RUnlock()
 
@@ -401,22 +417,17 @@ func (e *Engine) WritePoints(points []models.Point) error {
 		return ErrEngineClosed
 	}
 
-	// Convert the points to values for adding to the WAL/Cache.
-	values, err := tsm1.PointsToValues(collection.Points)
-	if err != nil {
-		return err
-	}
-
 	// Add the write to the WAL to be replayed if there is a crash or shutdown.
 	if _, err := e.wal.WriteMulti(values); err != nil {
 		return err
 	}
 
-	return e.writePointsLocked(collection, values)
+	return e.writePointsLocked(collection, values, reservation)
 }
 
 // writePointsLocked does the work of writing points and must be called under some sort of lock.
-func (e *Engine) writePointsLocked(collection *tsdb.SeriesCollection, values map[string][]value.Value) error {
+// The values are written into the room of the cache reserved by reservation, if it is not nil.
+func (e *Engine) writePointsLocked(collection *tsdb.SeriesCollection, values map[string][]value.Value, reservation *tsm1.CacheReservation) error {
 	// TODO(jeff): keep track of the values in the collection so that partial write
 	// errors get tracked all the way. Right now, the engine doesn't drop any values
 	// but if it ever did, the errors could end up missing some data.
@@ -437,7 +448,7 @@ func (e *Engine) writePointsLocked(collection *tsdb.SeriesCollection, values map
 	}
 
 	// Write the values to the engine.
-	if err := e.engine.WriteValues(values); err != nil {
+	if err := e.engine.WriteValuesReserved(values, reservation); err != nil {
 		return err
 	}
 
@@ -8,6 +8,7 @@ package reads
 
 import (
 	"errors"
+	"math"
 
 	"github.com/influxdata/influxdb/tsdb/cursors"
 )
@@ -132,6 +133,23 @@ func (c *floatMultiShardArrayCursor) Next() *cursors.FloatArray {
 	}
 }
 
+// NextSummary returns the summary of the next values of the cursor if the
+// underlying cursor can summarize them, see cursors.FloatArraySummaryCursor.
+func (c *floatMultiShardArrayCursor) NextSummary(max int64) (cursors.FloatArraySummary, bool) {
+	cur, ok := c.FloatArrayCursor.(cursors.FloatArraySummaryCursor)
+	if !ok {
+		return cursors.FloatArraySummary{}, false
+	}
+	if n := c.limit - c.count; n < max {
+		max = n
+	}
+	s, ok := cur.NextSummary(max)
+	if ok {
+		c.count += s.Count
+	}
+	return s, ok
+}
+
 func (c *floatMultiShardArrayCursor) nextArrayCursor() bool {
 	if len(c.itrs) == 0 {
 		return false
@@ -186,26 +204,42 @@ func newFloatArraySumCursor(cur cursors.FloatArrayCursor) *floatArraySumCursor {
 func (c floatArraySumCursor) Stats() cursors.CursorStats { return c.FloatArrayCursor.Stats() }
 
 func (c floatArraySumCursor) Next() *cursors.FloatArray {
-	a := c.FloatArrayCursor.Next()
-	if len(a.Timestamps) == 0 {
-		return a
-	}
-
-	ts := a.Timestamps[0]
-	var acc float64
+	sc, _ := c.FloatArrayCursor.(cursors.FloatArraySummaryCursor)
 
+	var (
+		ts    int64
+		acc   float64
+		found bool
+	)
 	for {
-		for _, v := range a.Values {
-			acc += v
+		// Whole blocks are summed from their summaries where possible.
+		if sc != nil {
+			if s, ok := sc.NextSummary(math.MaxInt64); ok {
+				if !found {
+					ts, found = s.MinTime, true
+				}
+				acc += s.Sum
+				continue
+			}
 		}
-		a = c.FloatArrayCursor.Next()
+
+		a := c.FloatArrayCursor.Next()
 		if len(a.Timestamps) == 0 {
+			if !found {
+				return a
+			}
 			c.ts[0] = ts
 			c.vs[0] = acc
 			c.res.Timestamps = c.ts[:]
 			c.res.Values = c.vs[:]
 			return c.res
 		}
+		if !found {
+			ts, found = a.Timestamps[0], true
+		}
+		for _, v := range a.Values {
+			acc += v
+		}
 	}
 }
 
@@ -218,22 +252,39 @@ func (c *integerFloatCountArrayCursor) Stats() cursors.CursorStats {
 }
 
 func (c *integerFloatCountArrayCursor) Next() *cursors.IntegerArray {
-	a := c.FloatArrayCursor.Next()
-	if len(a.Timestamps) == 0 {
-		return &cursors.IntegerArray{}
-	}
+	sc, _ := c.FloatArrayCursor.(cursors.FloatArraySummaryCursor)
 
-	ts := a.Timestamps[0]
-	var acc int64
+	var (
+		ts    int64
+		acc   int64
+		found bool
+	)
 	for {
-		acc += int64(len(a.Timestamps))
-		a = c.FloatArrayCursor.Next()
+		// Whole blocks are counted from their summaries where possible.
+		if sc != nil {
+			if s, ok := sc.NextSummary(math.MaxInt64); ok {
+				if !found {
+					ts, found = s.MinTime, true
+				}
+				acc += s.Count
+				continue
+			}
+		}
+
+		a := c.FloatArrayCursor.Next()
 		if len(a.Timestamps) == 0 {
+			if !found {
+				return &cursors.IntegerArray{}
+			}
 			res := cursors.NewIntegerArrayLen(1)
 			res.Timestamps[0] = ts
 			res.Values[0] = acc
 			return res
 		}
+		if !found {
+			ts, found = a.Timestamps[0], true
+		}
+		acc += int64(len(a.Timestamps))
 	}
 }
 
@@ -361,6 +412,23 @@ func (c *integerMultiShardArrayCursor) Next() *cursors.IntegerArray {
 	}
 }
 
+// NextSummary returns the summary of the next values of the cursor if the
+// underlying cursor can summarize them, see cursors.IntegerArraySummaryCursor.
+func (c *integerMultiShardArrayCursor) NextSummary(max int64) (cursors.IntegerArraySummary, bool) {
+	cur, ok := c.IntegerArrayCursor.(cursors.IntegerArraySummaryCursor)
+	if !ok {
+		return cursors.IntegerArraySummary{}, false
+	}
+	if n := c.limit - c.count; n < max {
+		max = n
+	}
+	s, ok := cur.NextSummary(max)
+	if ok {
+		c.count += s.Count
+	}
+	return s, ok
+}
+
 func (c *integerMultiShardArrayCursor) nextArrayCursor() bool {
 	if len(c.itrs) == 0 {
 		return false
@@ -415,26 +483,42 @@ func newIntegerArraySumCursor(cur cursors.IntegerArrayCursor) *integerArraySumCu
 func (c integerArraySumCursor) Stats() cursors.CursorStats { return c.IntegerArrayCursor.Stats() }
 
 func (c integerArraySumCursor) Next() *cursors.IntegerArray {
-	a := c.IntegerArrayCursor.Next()
-	if len(a.Timestamps) == 0 {
-		return a
-	}
-
-	ts := a.Timestamps[0]
-	var acc int64
+	sc, _ := c.IntegerArrayCursor.(cursors.IntegerArraySummaryCursor)
 
+	var (
+		ts    int64
+		acc   int64
+		found bool
+	)
 	for {
-		for _, v := range a.Values {
-			acc += v
+		// Whole blocks are summed from their summaries where possible.
+		if sc != nil {
+			if s, ok := sc.NextSummary(math.MaxInt64); ok {
+				if !found {
+					ts, found = s.MinTime, true
+				}
+				acc += s.Sum
+				continue
+			}
 		}
-		a = c.IntegerArrayCursor.Next()
+
+		a := c.IntegerArrayCursor.Next()
 		if len(a.Timestamps) == 0 {
+			if !found {
+				return a
+			}
 			c.ts[0] = ts
 			c.vs[0] = acc
 			c.res.Timestamps = c.ts[:]
 			c.res.Values = c.vs[:]
 			return c.res
 		}
+		if !found {
+			ts, found = a.Timestamps[0], true
+		}
+		for _, v := range a.Values {
+			acc += v
+		}
 	}
 }
 
@@ -447,22 +531,39 @@ func (c *integerIntegerCountArrayCursor) Stats() cursors.CursorStats {
 }
 
 func (c *integerIntegerCountArrayCursor) Next() *cursors.IntegerArray {
-	a := c.IntegerArrayCursor.Next()
-	if len(a.Timestamps) == 0 {
-		return &cursors.IntegerArray{}
-	}
+	sc, _ := c.IntegerArrayCursor.(cursors.IntegerArraySummaryCursor)
 
-	ts := a.Timestamps[0]
-	var acc int64
+	var (
+		ts    int64
+		acc   int64
+		found bool
+	)
 	for {
-		acc += int64(len(a.Timestamps))
-		a = c.IntegerArrayCursor.Next()
+		// Whole blocks are counted from their summaries where possible.
+		if sc != nil {
+			if s, ok := sc.NextSummary(math.MaxInt64); ok {
+				if !found {
+					ts, found = s.MinTime, true
+				}
+				acc += s.Count
+				continue
+			}
+		}
+
+		a := c.IntegerArrayCursor.Next()
 		if len(a.Timestamps) == 0 {
+			if !found {
+				return &cursors.IntegerArray{}
+			}
 			res := cursors.NewIntegerArrayLen(1)
 			res.Timestamps[0] = ts
 			res.Values[0] = acc
 			return res
 		}
+		if !found {
+			ts, found = a.Timestamps[0], true
+		}
+		acc += int64(len(a.Timestamps))
 	}
 }
 
@@ -590,6 +691,23 @@ func (c *unsignedMultiShardArrayCursor) Next() *cursors.UnsignedArray {
 	}
 }
 
+// NextSummary returns the summary of the next values of the cursor if the
+// underlying cursor can summarize them, see cursors.UnsignedArraySummaryCursor.
+func (c *unsignedMultiShardArrayCursor) NextSummary(max int64) (cursors.UnsignedArraySummary, bool) {
+	cur, ok := c.UnsignedArrayCursor.(cursors.UnsignedArraySummaryCursor)
+	if !ok {
+		return cursors.UnsignedArraySummary{}, false
+	}
+	if n := c.limit - c.count; n < max {
+		max = n
+	}
+	s, ok := cur.NextSummary(max)
+	if ok {
+		c.count += s.Count
+	}
+	return s, ok
+}
+
 func (c *unsignedMultiShardArrayCursor) nextArrayCursor() bool {
 	if len(c.itrs) == 0 {
 		return false
@@ -644,26 +762,42 @@ func newUnsignedArraySumCursor(cur cursors.UnsignedArrayCursor) *unsignedArraySu
 func (c unsignedArraySumCursor) Stats() cursors.CursorStats { return c.UnsignedArrayCursor.Stats() }
 
 func (c unsignedArraySumCursor) Next() *cursors.UnsignedArray {
-	a := c.UnsignedArrayCursor.Next()
-	if len(a.Timestamps) == 0 {
-		return a
-	}
-
-	ts := a.Timestamps[0]
-	var acc uint64
+	sc, _ := c.UnsignedArrayCursor.(cursors.UnsignedArraySummaryCursor)
 
+	var (
+		ts    int64
+		acc   uint64
+		found bool
+	)
 	for {
-		for _, v := range a.Values {
-			acc += v
+		// Whole blocks are summed from their summaries where possible.
+		if sc != nil {
+			if s, ok := sc.NextSummary(math.MaxInt64); ok {
+				if !found {
+					ts, found = s.MinTime, true
+				}
+				acc += s.Sum
+				continue
+			}
 		}
-		a = c.UnsignedArrayCursor.Next()
+
+		a := c.UnsignedArrayCursor.Next()
 		if len(a.Timestamps) == 0 {
+			if !found {
+				return a
+			}
 			c.ts[0] = ts
 			c.vs[0] = acc
 			c.res.Timestamps = c.ts[:]
 			c.res.Values = c.vs[:]
 			return c.res
 		}
+		if !found {
+			ts, found = a.Timestamps[0], true
+		}
+		for _, v := range a.Values {
+			acc += v
+		}
 	}
 }
 
@@ -676,22 +810,39 @@ func (c *integerUnsignedCountArrayCursor) Stats() cursors.CursorStats {
 }
 
 func (c *integerUnsignedCountArrayCursor) Next() *cursors.IntegerArray {
-	a := c.UnsignedArrayCursor.Next()
-	if len(a.Timestamps) == 0 {
-		return &cursors.IntegerArray{}
-	}
+	sc, _ := c.UnsignedArrayCursor.(cursors.UnsignedArraySummaryCursor)
 
-	ts := a.Timestamps[0]
-	var acc int64
+	var (
+		ts    int64
+		acc   int64
+		found bool
+	)
 	for {
-		acc += int64(len(a.Timestamps))
-		a = c.UnsignedArrayCursor.Next()
+		// Whole blocks are counted from their summaries where possible.
+		if sc != nil {
+			if s, ok := sc.NextSummary(math.MaxInt64); ok {
+				if !found {
+					ts, found = s.MinTime, true
+				}
+				acc += s.Count
+				continue
+			}
+		}
+
+		a := c.UnsignedArrayCursor.Next()
 		if len(a.Timestamps) == 0 {
+			if !found {
+				return &cursors.IntegerArray{}
+			}
 			res := cursors.NewIntegerArrayLen(1)
 			res.Timestamps[0] = ts
 			res.Values[0] = acc
 			return res
 		}
+		if !found {
+			ts, found = a.Timestamps[0], true
+		}
+		acc += int64(len(a.Timestamps))
 	}
 }
 
@@ -819,6 +970,23 @@ func (c *stringMultiShardArrayCursor) Next() *cursors.StringArray {
 	}
 }
 
+// NextSummary returns the summary of the next values of the cursor if the
+// underlying cursor can summarize them, see cursors.StringArraySummaryCursor.
+func (c *stringMultiShardArrayCursor) NextSummary(max int64) (cursors.ArraySummary, bool) {
+	cur, ok := c.StringArrayCursor.(cursors.StringArraySummaryCursor)
+	if !ok {
+		return cursors.ArraySummary{}, false
+	}
+	if n := c.limit - c.count; n < max {
+		max = n
+	}
+	s, ok := cur.NextSummary(max)
+	if ok {
+		c.count += s.Count
+	}
+	return s, ok
+}
+
 func (c *stringMultiShardArrayCursor) nextArrayCursor() bool {
 	if len(c.itrs) == 0 {
 		return false
@@ -865,22 +1033,39 @@ func (c *integerStringCountArrayCursor) Stats() cursors.CursorStats {
 }
 
 func (c *integerStringCountArrayCursor) Next() *cursors.IntegerArray {
-	a := c.StringArrayCursor.Next()
-	if len(a.Timestamps) == 0 {
-		return &cursors.IntegerArray{}
-	}
+	sc, _ := c.StringArrayCursor.(cursors.StringArraySummaryCursor)
 
-	ts := a.Timestamps[0]
-	var acc int64
+	var (
+		ts    int64
+		acc   int64
+		found bool
+	)
 	for {
-		acc += int64(len(a.Timestamps))
-		a = c.StringArrayCursor.Next()
+		// Whole blocks are counted from their summaries where possible.
+		if sc != nil {
+			if s, ok := sc.NextSummary(math.MaxInt64); ok {
+				if !found {
+					ts, found = s.MinTime, true
+				}
+				acc += s.Count
+				continue
+			}
+		}
+
+		a := c.StringArrayCursor.Next()
 		if len(a.Timestamps) == 0 {
+			if !found {
+				return &cursors.IntegerArray{}
+			}
 			res := cursors.NewIntegerArrayLen(1)
 			res.Timestamps[0] = ts
 			res.Values[0] = acc
 			return res
 		}
+		if !found {
+			ts, found = a.Timestamps[0], true
+		}
+		acc += int64(len(a.Timestamps))
 	}
 }
 
@@ -1008,6 +1193,23 @@ func (c *booleanMultiShardArrayCursor) Next() *cursors.BooleanArray {
 	}
 }
 
+// NextSummary returns the summary of the next values of the cursor if the
+// underlying cursor can summarize them, see cursors.BooleanArraySummaryCursor.
+func (c *booleanMultiShardArrayCursor) NextSummary(max int64) (cursors.ArraySummary, bool) {
+	cur, ok := c.BooleanArrayCursor.(cursors.BooleanArraySummaryCursor)
+	if !ok {
+		return cursors.ArraySummary{}, false
+	}
+	if n := c.limit - c.count; n < max {
+		max = n
+	}
+	s, ok := cur.NextSummary(max)
+	if ok {
+		c.count += s.Count
+	}
+	return s, ok
+}
+
 func (c *booleanMultiShardArrayCursor) nextArrayCursor() bool {
 	if len(c.itrs) == 0 {
 		return false
@@ -1054,22 +1256,39 @@ func (c *integerBooleanCountArrayCursor) Stats() cursors.CursorStats {
 }
 
 func (c *integerBooleanCountArrayCursor) Next() *cursors.IntegerArray {
-	a := c.BooleanArrayCursor.Next()
-	if len(a.Timestamps) == 0 {
-		return &cursors.IntegerArray{}
-	}
+	sc, _ := c.BooleanArrayCursor.(cursors.BooleanArraySummaryCursor)
 
-	ts := a.Timestamps[0]
-	var acc int64
+	var (
+		ts    int64
+		acc   int64
+		found bool
+	)
 	for {
-		acc += int64(len(a.Timestamps))
-		a = c.BooleanArrayCursor.Next()
+		// Whole blocks are counted from their summaries where possible.
+		if sc != nil {
+			if s, ok := sc.NextSummary(math.MaxInt64); ok {
+				if !found {
+					ts, found = s.MinTime, true
+				}
+				acc += s.Count
+				continue
+			}
+		}
+
+		a := c.BooleanArrayCursor.Next()
 		if len(a.Timestamps) == 0 {
+			if !found {
+				return &cursors.IntegerArray{}
+			}
 			res := cursors.NewIntegerArrayLen(1)
 			res.Timestamps[0] = ts
 			res.Values[0] = acc
 			return res
 		}
+		if !found {
+			ts, found = a.Timestamps[0], true
+		}
+		acc += int64(len(a.Timestamps))
 	}
 }
 
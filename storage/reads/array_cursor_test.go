@@ -0,0 +1,111 @@
+package reads
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+)
+
+// floatSummaryCursor yields its blocks either as summaries, if summary is
+// set, or as values.
+type floatSummaryCursor struct {
+	blocks  []*cursors.FloatArray
+	summary []bool
+}
+
+func (c *floatSummaryCursor) NextSummary(max int64) (cursors.FloatArraySummary, bool) {
+	if len(c.blocks) == 0 || !c.summary[0] || int64(c.blocks[0].Len()) > max {
+		return cursors.FloatArraySummary{}, false
+	}
+
+	a := c.blocks[0]
+	c.blocks, c.summary = c.blocks[1:], c.summary[1:]
+
+	s := cursors.FloatArraySummary{
+		ArraySummary: cursors.ArraySummary{
+			MinTime: a.MinTime(),
+			MaxTime: a.MaxTime(),
+			Count:   int64(a.Len()),
+		},
+	}
+	for _, v := range a.Values {
+		s.Sum += v
+	}
+	return s, true
+}
+
+func (c *floatSummaryCursor) Next() *cursors.FloatArray {
+	if len(c.blocks) == 0 {
+		return &cursors.FloatArray{}
+	}
+	a := c.blocks[0]
+	c.blocks, c.summary = c.blocks[1:], c.summary[1:]
+	return a
+}
+
+func (c *floatSummaryCursor) Close()                     {}
+func (c *floatSummaryCursor) Err() error                 { return nil }
+func (c *floatSummaryCursor) Stats() cursors.CursorStats { return cursors.CursorStats{} }
+
+func newFloatSummaryCursor(summary ...bool) *floatSummaryCursor {
+	c := &floatSummaryCursor{summary: summary}
+	for i := range summary {
+		a := cursors.NewFloatArrayLen(10)
+		for j := range a.Timestamps {
+			a.Timestamps[j] = int64(10*i + j + 1)
+			a.Values[j] = 1.5
+		}
+		c.blocks = append(c.blocks, a)
+	}
+	return c
+}
+
+func TestArraySumCursor_Summary(t *testing.T) {
+	for _, summary := range [][]bool{
+		{true, true, true},
+		{false, true, false},
+		{true, false, true},
+		{false, false, false},
+	} {
+		cur := newSumArrayCursor(newFloatSummaryCursor(summary...)).(cursors.FloatArrayCursor)
+		got := cur.Next()
+		exp := &cursors.FloatArray{Timestamps: []int64{1}, Values: []float64{45}}
+		if !cmp.Equal(got, exp) {
+			t.Fatalf("unexpected sum for %v: -got/+exp\n%v", summary, cmp.Diff(got, exp))
+		}
+	}
+}
+
+func TestArrayCountCursor_Summary(t *testing.T) {
+	for _, summary := range [][]bool{
+		{true, true, true},
+		{false, true, true},
+		{true, true, false},
+	} {
+		cur := newCountArrayCursor(newFloatSummaryCursor(summary...)).(cursors.IntegerArrayCursor)
+		got := cur.Next()
+		exp := &cursors.IntegerArray{Timestamps: []int64{1}, Values: []int64{30}}
+		if !cmp.Equal(got, exp) {
+			t.Fatalf("unexpected count for %v: -got/+exp\n%v", summary, cmp.Diff(got, exp))
+		}
+	}
+
+	cur := newCountArrayCursor(newFloatSummaryCursor()).(cursors.IntegerArrayCursor)
+	if got := cur.Next(); got.Len() != 0 {
+		t.Fatalf("expected no count, got %v", got)
+	}
+}
+
+func TestMultiShardArrayCursor_NextSummary_Limit(t *testing.T) {
+	var c floatMultiShardArrayCursor
+	c.cursorContext = cursorContext{limit: 25}
+	c.reset(newFloatSummaryCursor(true, true, true), nil, nil)
+
+	cur := newCountArrayCursor(&c).(cursors.IntegerArrayCursor)
+	got := cur.Next()
+	exp := &cursors.IntegerArray{Timestamps: []int64{1}, Values: []int64{25}}
+	if !cmp.Equal(got, exp) {
+		t.Fatalf("unexpected count: -got/+exp\n%v", cmp.Diff(got, exp))
+	}
+}
@@ -27,8 +27,10 @@ func AddControllerConfigDependencies(
 	engine *storage.Engine,
 	bucketSvc platform.BucketService,
 	orgSvc platform.OrganizationService,
+	shareSvc platform.ShareService,
 ) error {
 	bucketLookupSvc := query.FromBucketService(bucketSvc)
+	bucketLookupSvc.ShareService = shareSvc
 	orgLookupSvc := query.FromOrganizationService(orgSvc)
 	err := influxdb.InjectFromDependencies(cc.ExecutorDependencies, influxdb.Dependencies{
 		Reader:             reads.NewReader(newStore(engine)),
@@ -0,0 +1,102 @@
+package wal
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	// SnappyCompression compresses WAL entries with snappy, which is fast and
+	// the default.
+	SnappyCompression = "snappy"
+
+	// ZstdCompression compresses WAL entries with zstd, which compresses large
+	// batches better at the cost of more CPU.
+	ZstdCompression = "zstd"
+
+	// DefaultCompression is the compression used for WAL entries if none is set.
+	DefaultCompression = SnappyCompression
+)
+
+var (
+	zstdOnce    sync.Once
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+	zstdErr     error
+)
+
+// zstdCodec returns the shared zstd encoder and decoder, creating them on first use.
+func zstdCodec() (*zstd.Encoder, *zstd.Decoder, error) {
+	zstdOnce.Do(func() {
+		if zstdEncoder, zstdErr = zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1)); zstdErr != nil {
+			return
+		}
+		zstdDecoder, zstdErr = zstd.NewReader(nil)
+	})
+	return zstdEncoder, zstdDecoder, zstdErr
+}
+
+// validCompression returns an error if compression is not a supported WAL compression.
+func validCompression(compression string) error {
+	switch compression {
+	case SnappyCompression, ZstdCompression:
+		return nil
+	default:
+		return fmt.Errorf("unknown WAL compression %q", compression)
+	}
+}
+
+// compressEntry compresses the encoded entry b using compression. It returns a
+// buffer from bytesPool holding the compressed entry, which must be returned
+// to the pool once written, and the flags to set on the type of the entry.
+func compressEntry(compression string, b []byte) ([]byte, byte, error) {
+	switch compression {
+	case ZstdCompression:
+		enc, _, err := zstdCodec()
+		if err != nil {
+			return nil, 0, err
+		}
+		// EncodeAll grows the buffer if the entry does not compress.
+		buf := bytesPool.Get(len(b))
+		return enc.EncodeAll(b, buf[:0]), walEntryZstdFlag, nil
+	default:
+		buf := bytesPool.Get(snappy.MaxEncodedLen(len(b)))
+		return snappy.Encode(buf, b), 0, nil
+	}
+}
+
+// decompressEntry decompresses the entry b, compressed as indicated by the
+// flags of its type. The returned buffer must be returned with putBuf once the
+// entry has been unmarshaled.
+func decompressEntry(flags byte, b []byte) (*[]byte, error) {
+	if flags&walEntryZstdFlag != 0 {
+		_, dec, err := zstdCodec()
+		if err != nil {
+			return nil, err
+		}
+		buf := getBuf(0)
+		data, err := dec.DecodeAll(b, (*buf)[:0])
+		if err != nil {
+			putBuf(buf)
+			return nil, err
+		}
+		*buf = data
+		return buf, nil
+	}
+
+	decLen, err := snappy.DecodedLen(b)
+	if err != nil {
+		return nil, err
+	}
+	buf := getBuf(decLen)
+	data, err := snappy.Decode(*buf, b)
+	if err != nil {
+		putBuf(buf)
+		return nil, err
+	}
+	*buf = data
+	return buf, nil
+}
@@ -70,7 +70,11 @@ func (r *WALReader) readFile(file string, cb func(WALEntry) error) error {
 		entry, err := r.r.Read()
 		if err != nil {
 			n := r.r.Count()
-			r.logger.Info("File corrupt", zap.Error(err), zap.String("path", file), zap.Int64("pos", n))
+			r.logger.Warn("File corrupt, truncating after last valid entry",
+				zap.Error(err),
+				zap.String("path", file),
+				zap.Int64("pos", n),
+				zap.Int64("truncated_bytes", stat.Size()-n))
 			if err := f.Truncate(n); err != nil {
 				return err
 			}
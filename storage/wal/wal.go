@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"encoding/binary"
 	"fmt"
+	"hash/crc32"
 	"io"
 	"math"
 	"os"
@@ -16,7 +17,6 @@ import (
 	"sync/atomic"
 	"time"
 
-	"github.com/golang/snappy"
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/pkg/limiter"
 	"github.com/influxdata/influxdb/pkg/pool"
@@ -62,6 +62,20 @@ const (
 	DeleteBucketRangeWALEntryType WalEntryType = 0x04
 )
 
+const (
+	// walEntryChecksumFlag is set on the type of entries whose length is
+	// followed by a CRC-32 checksum of the entry. Entries written by earlier
+	// versions have no checksum.
+	walEntryChecksumFlag = 0x80
+
+	// walEntryZstdFlag is set on the type of entries compressed with zstd
+	// rather than snappy.
+	walEntryZstdFlag = 0x40
+
+	// walEntryFlagsMask masks the flags of the type of an entry.
+	walEntryFlagsMask = walEntryChecksumFlag | walEntryZstdFlag
+)
+
 var (
 	// ErrWALClosed is returned when attempting to write to a closed WAL file.
 	ErrWALClosed = fmt.Errorf("WAL closed")
@@ -69,6 +83,9 @@ var (
 	// ErrWALCorrupt is returned when reading a corrupt WAL entry.
 	ErrWALCorrupt = fmt.Errorf("corrupted WAL entry")
 
+	// ErrWALChecksum is returned when reading a WAL entry that does not match its checksum.
+	ErrWALChecksum = fmt.Errorf("WAL entry checksum mismatch")
+
 	defaultWaitingWALWrites = runtime.GOMAXPROCS(0) * 2
 
 	// bytePool is a shared bytes pool buffer re-cycle []byte slices to reduce allocations.
@@ -84,8 +101,9 @@ type WAL struct {
 	mu            sync.RWMutex
 	lastWriteTime time.Time
 
-	path    string
-	enabled bool
+	path        string
+	enabled     bool
+	compression string
 
 	// write variables
 	currentSegmentID     int
@@ -118,8 +136,9 @@ type WAL struct {
 func NewWAL(path string) *WAL {
 	logger := zap.NewNop()
 	return &WAL{
-		path:    path,
-		enabled: true,
+		path:        path,
+		enabled:     true,
+		compression: DefaultCompression,
 
 		// these options should be overriden by any options in the config
 		SegmentSize: DefaultSegmentSize,
@@ -149,6 +168,17 @@ func (l *WAL) SetEnabled(enabled bool) {
 	l.enabled = enabled
 }
 
+// WithCompression sets the compression used for new entries, either
+// SnappyCompression or ZstdCompression, and should be called before the WAL is
+// opened. An empty compression selects DefaultCompression. Entries are read
+// back whatever their compression.
+func (l *WAL) WithCompression(compression string) {
+	if compression == "" {
+		compression = DefaultCompression
+	}
+	l.compression = compression
+}
+
 // WithLogger sets the WAL's logger.
 func (l *WAL) WithLogger(log *zap.Logger) {
 	l.logger = log.With(zap.String("service", "wal"))
@@ -183,6 +213,10 @@ func (l *WAL) Open() error {
 		return nil
 	}
 
+	if err := validCompression(l.compression); err != nil {
+		return err
+	}
+
 	// Initialise metrics for trackers.
 	mmu.Lock()
 	if wms == nil {
@@ -219,6 +253,24 @@ func (l *WAL) Open() error {
 			return err
 		}
 
+		// Truncate any torn entry at the end of the last segment, so that new
+		// entries are not appended after it.
+		if stat.Size() > 0 {
+			n, err := repairSegment(lastSegment)
+			if err != nil {
+				return err
+			}
+			if n < stat.Size() {
+				l.logger.Warn("Truncated torn entry at end of WAL segment",
+					zap.String("path", lastSegment),
+					zap.Int64("size", stat.Size()),
+					zap.Int64("truncated_size", n))
+				if stat, err = os.Stat(lastSegment); err != nil {
+					return err
+				}
+			}
+		}
+
 		if stat.Size() == 0 {
 			os.Remove(lastSegment)
 			segments = segments[:len(segments)-1]
@@ -432,10 +484,11 @@ func (l *WAL) writeToLog(entry WALEntry) (int, error) {
 		return -1, err
 	}
 
-	encBuf := bytesPool.Get(snappy.MaxEncodedLen(len(b)))
-
-	compressed := snappy.Encode(encBuf, b)
+	compressed, flags, err := compressEntry(l.compression, b)
 	bytesPool.Put(bytes)
+	if err != nil {
+		return -1, err
+	}
 
 	syncErr := make(chan error)
 
@@ -456,7 +509,7 @@ func (l *WAL) writeToLog(entry WALEntry) (int, error) {
 		}
 
 		// write and sync
-		if err := l.currentSegmentWriter.Write(entry.Type(), compressed); err != nil {
+		if err := l.currentSegmentWriter.write(entry.Type(), flags, compressed); err != nil {
 			return -1, fmt.Errorf("error writing WAL entry: %v", err)
 		}
 
@@ -475,7 +528,7 @@ func (l *WAL) writeToLog(entry WALEntry) (int, error) {
 
 	}()
 
-	bytesPool.Put(encBuf)
+	bytesPool.Put(compressed)
 
 	if err != nil {
 		return segID, err
@@ -1077,12 +1130,24 @@ func (w *WALSegmentWriter) path() string {
 	return ""
 }
 
-// Write writes entryType and the buffer containing compressed entry data.
+// Write writes entryType and the buffer containing snappy compressed entry data.
 func (w *WALSegmentWriter) Write(entryType WalEntryType, compressed []byte) error {
-	var buf [5]byte
-	buf[0] = byte(entryType)
+	return w.write(entryType, 0, compressed)
+}
+
+// write writes the header of an entry of entryType, with flags indicating how
+// it is compressed, followed by the buffer containing the compressed entry
+// data. The header holds a checksum of the type, the length and the data.
+func (w *WALSegmentWriter) write(entryType WalEntryType, flags byte, compressed []byte) error {
+	var buf [9]byte
+	buf[0] = byte(entryType) | flags | walEntryChecksumFlag
 	binary.BigEndian.PutUint32(buf[1:5], uint32(len(compressed)))
 
+	crc := crc32.NewIEEE()
+	crc.Write(buf[:5])
+	crc.Write(compressed)
+	binary.BigEndian.PutUint32(buf[5:9], crc.Sum32())
+
 	if _, err := w.bw.Write(buf[:]); err != nil {
 		return err
 	}
@@ -1150,8 +1215,8 @@ func (r *WALSegmentReader) Next() bool {
 	var nReadOK int
 
 	// read the type and the length of the entry
-	var lv [5]byte
-	n, err := io.ReadFull(r.r, lv[:])
+	var lv [9]byte
+	n, err := io.ReadFull(r.r, lv[:5])
 	if err == io.EOF {
 		return false
 	}
@@ -1164,33 +1229,47 @@ func (r *WALSegmentReader) Next() bool {
 	}
 	nReadOK += n
 
-	entryType := lv[0]
+	flags := lv[0] & walEntryFlagsMask
+	entryType := lv[0] &^ walEntryFlagsMask
 	length := binary.BigEndian.Uint32(lv[1:5])
 
+	// read the checksum of the entry, if it has one
+	if flags&walEntryChecksumFlag != 0 {
+		n, err = io.ReadFull(r.r, lv[5:9])
+		if err != nil {
+			r.err = unexpectedEOF(err)
+			return true
+		}
+		nReadOK += n
+	}
+
 	b := *(getBuf(int(length)))
 	defer putBuf(&b)
 
 	// read the compressed block and decompress it
 	n, err = io.ReadFull(r.r, b[:length])
 	if err != nil {
-		r.err = err
+		r.err = unexpectedEOF(err)
 		return true
 	}
 	nReadOK += n
 
-	decLen, err := snappy.DecodedLen(b[:length])
-	if err != nil {
-		r.err = err
-		return true
+	if flags&walEntryChecksumFlag != 0 {
+		crc := crc32.NewIEEE()
+		crc.Write(lv[:5])
+		crc.Write(b[:length])
+		if crc.Sum32() != binary.BigEndian.Uint32(lv[5:9]) {
+			r.err = ErrWALChecksum
+			return true
+		}
 	}
-	decBuf := *(getBuf(decLen))
-	defer putBuf(&decBuf)
 
-	data, err := snappy.Decode(decBuf, b[:length])
+	decBuf, err := decompressEntry(flags, b[:length])
 	if err != nil {
 		r.err = err
 		return true
 	}
+	defer putBuf(decBuf)
 
 	// and marshal it and send it to the cache
 	switch WalEntryType(entryType) {
@@ -1204,7 +1283,7 @@ func (r *WALSegmentReader) Next() bool {
 		r.err = fmt.Errorf("unknown wal entry type: %v", entryType)
 		return true
 	}
-	r.err = r.entry.UnmarshalBinary(data)
+	r.err = r.entry.UnmarshalBinary(*decBuf)
 	if r.err == nil {
 		// Read and decode of this entry was successful.
 		r.n += int64(nReadOK)
@@ -1213,6 +1292,15 @@ func (r *WALSegmentReader) Next() bool {
 	return true
 }
 
+// unexpectedEOF returns io.ErrUnexpectedEOF if err is io.EOF, as the end of
+// a segment within an entry means the entry is torn.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
 // Read returns the next entry in the reader.
 func (r *WALSegmentReader) Read() (WALEntry, error) {
 	if r.err != nil {
@@ -1243,6 +1331,46 @@ func (r *WALSegmentReader) Close() error {
 	return err
 }
 
+// repairSegment truncates the segment file at path after its last valid entry,
+// removing an entry torn by a partial write from its end, and returns its
+// resulting size. A torn entry may be cut short or be of full length but fail
+// its checksum or decoding. A corrupt entry followed by valid entries is not
+// torn by a partial write, and is not repaired as the entries after it would
+// be lost: an error is returned instead.
+func repairSegment(path string) (int64, error) {
+	f, err := os.OpenFile(path, os.O_RDWR, 0666)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	r := NewWALSegmentReader(f)
+	for r.Next() {
+		if _, err := r.Read(); err != nil {
+			n := r.Count()
+			if err != io.ErrUnexpectedEOF && validEntryFollows(r) {
+				return 0, fmt.Errorf("corrupt WAL segment %s at offset %d: %v", path, n, err)
+			}
+			if err := f.Truncate(n); err != nil {
+				return 0, err
+			}
+			return n, nil
+		}
+	}
+	return r.Count(), nil
+}
+
+// validEntryFollows reports whether any of the entries remaining to be read by r
+// is valid.
+func validEntryFollows(r *WALSegmentReader) bool {
+	for r.Next() {
+		if _, err := r.Read(); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
 // idFromFileName parses the segment file ID from its name.
 func idFromFileName(name string) (int, error) {
 	parts := strings.Split(filepath.Base(name), ".")
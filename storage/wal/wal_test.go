@@ -1,8 +1,10 @@
 package wal
 
 import (
+	"encoding/binary"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"math/rand"
 	"os"
 	"reflect"
@@ -362,6 +364,313 @@ func TestWALWriter_Corrupt(t *testing.T) {
 	}
 }
 
+func TestWALSegmentReader_Checksum(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+	f := MustTempFile(dir)
+	w := NewWALSegmentWriter(f)
+
+	entry := &WriteWALEntry{
+		Values: map[string][]value.Value{
+			"cpu,host=A#!~#float": []value.Value{value.NewValue(1, 1.1)},
+		},
+	}
+	if err := w.Write(mustMarshalEntry(entry)); err != nil {
+		fatal(t, "write points", err)
+	}
+	if err := w.Flush(); err != nil {
+		fatal(t, "flush", err)
+	}
+
+	// Flip a bit of the last byte of the entry data.
+	var last [1]byte
+	if _, err := f.ReadAt(last[:], MustReadFileSize(f)-1); err != nil {
+		fatal(t, "read WAL segment", err)
+	}
+	last[0] ^= 1
+	if _, err := f.WriteAt(last[:], MustReadFileSize(f)-1); err != nil {
+		fatal(t, "corrupt WAL segment", err)
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		fatal(t, "seek", err)
+	}
+	r := NewWALSegmentReader(f)
+	if !r.Next() {
+		t.Fatalf("expected next, got false")
+	}
+	if _, err := r.Read(); err != ErrWALChecksum {
+		t.Fatalf("unexpected error: got %v, exp %v", err, ErrWALChecksum)
+	}
+	if n := r.Count(); n != 0 {
+		t.Fatalf("wrong count of bytes read, got %d, exp %d", n, 0)
+	}
+}
+
+// Ensure entries written without a checksum by earlier versions are read.
+func TestWALSegmentReader_NoChecksum(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+	f := MustTempFile(dir)
+
+	entry := &DeleteBucketRangeWALEntry{OrgID: 1, BucketID: 2, Min: 3, Max: 4}
+	typ, b := mustMarshalEntry(entry)
+
+	var hdr [5]byte
+	hdr[0] = byte(typ)
+	binary.BigEndian.PutUint32(hdr[1:], uint32(len(b)))
+	if _, err := f.Write(append(hdr[:], b...)); err != nil {
+		fatal(t, "write entry", err)
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		fatal(t, "seek", err)
+	}
+	r := NewWALSegmentReader(f)
+	if !r.Next() {
+		t.Fatalf("expected next, got false")
+	}
+	we, err := r.Read()
+	if err != nil {
+		fatal(t, "read entry", err)
+	}
+	if !reflect.DeepEqual(we, entry) {
+		t.Fatalf("entry mismatch: got %v, exp %v", we, entry)
+	}
+	if n := r.Count(); n != MustReadFileSize(f) {
+		t.Fatalf("wrong count of bytes read, got %d, exp %d", n, MustReadFileSize(f))
+	}
+}
+
+// Ensure a torn entry at the end of the last segment is truncated when the
+// WAL is opened, so that later entries can be read.
+func TestWAL_Open_TornEntry(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	write := func(w *WAL, v float64) {
+		t.Helper()
+		if _, err := w.WriteMulti(map[string][]value.Value{
+			"cpu,host=A#!~#value": []value.Value{value.NewValue(1, v)},
+		}); err != nil {
+			t.Fatalf("error writing points: %v", err)
+		}
+	}
+
+	w := NewWAL(dir)
+	if err := w.Open(); err != nil {
+		t.Fatalf("error opening WAL: %v", err)
+	}
+	write(w, 1)
+	write(w, 2)
+	if err := w.Close(); err != nil {
+		t.Fatalf("error closing wal: %v", err)
+	}
+
+	segments, err := SegmentFileNames(dir)
+	if err != nil {
+		t.Fatalf("error getting segments: %v", err)
+	}
+	stat, err := os.Stat(segments[0])
+	if err != nil {
+		t.Fatalf("error getting segment size: %v", err)
+	}
+	if err := os.Truncate(segments[0], stat.Size()-3); err != nil {
+		t.Fatalf("error truncating segment: %v", err)
+	}
+
+	w = NewWAL(dir)
+	if err := w.Open(); err != nil {
+		t.Fatalf("error opening WAL: %v", err)
+	}
+	write(w, 3)
+	if err := w.Close(); err != nil {
+		t.Fatalf("error closing wal: %v", err)
+	}
+
+	var got []float64
+	if err := NewWALReader(segments).Read(func(entry WALEntry) error {
+		for _, v := range entry.(*WriteWALEntry).Values["cpu,host=A#!~#value"] {
+			got = append(got, v.Value().(float64))
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("error reading WAL: %v", err)
+	}
+	if exp := []float64{1, 3}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("values mismatch: got %v, exp %v", got, exp)
+	}
+}
+
+func TestWAL_Compression(t *testing.T) {
+	for _, compression := range []string{SnappyCompression, ZstdCompression} {
+		t.Run(compression, func(t *testing.T) {
+			dir := MustTempDir()
+			defer os.RemoveAll(dir)
+
+			values := map[string][]value.Value{
+				"cpu,host=A#!~#value": []value.Value{value.NewValue(1, 1.1), value.NewValue(2, 2.2)},
+				"cpu,host=B#!~#value": []value.Value{value.NewValue(1, "string")},
+			}
+
+			w := NewWAL(dir)
+			w.WithCompression(compression)
+			if err := w.Open(); err != nil {
+				t.Fatalf("error opening WAL: %v", err)
+			}
+			if _, err := w.WriteMulti(values); err != nil {
+				t.Fatalf("error writing points: %v", err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("error closing wal: %v", err)
+			}
+
+			segments, err := SegmentFileNames(dir)
+			if err != nil {
+				t.Fatalf("error getting segments: %v", err)
+			}
+			var got []WALEntry
+			if err := NewWALReader(segments).Read(func(entry WALEntry) error {
+				got = append(got, entry)
+				return nil
+			}); err != nil {
+				t.Fatalf("error reading WAL: %v", err)
+			}
+			if exp := []WALEntry{&WriteWALEntry{Values: values}}; !reflect.DeepEqual(got, exp) {
+				t.Fatalf("entries mismatch: got %v, exp %v", got, exp)
+			}
+		})
+	}
+
+	w := NewWAL(MustTempDir())
+	defer os.RemoveAll(w.Path())
+	w.WithCompression("lz4")
+	if err := w.Open(); err == nil {
+		t.Fatal("expected error opening WAL with unknown compression")
+	}
+}
+
+// Ensure a full-length entry at the end of the last segment that fails its
+// checksum, as left by a write torn within its data, is truncated when the WAL
+// is opened.
+func TestWAL_Open_TornEntryChecksum(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	write := func(w *WAL, v float64) {
+		t.Helper()
+		if _, err := w.WriteMulti(map[string][]value.Value{
+			"cpu,host=A#!~#value": []value.Value{value.NewValue(1, v)},
+		}); err != nil {
+			t.Fatalf("error writing points: %v", err)
+		}
+	}
+
+	w := NewWAL(dir)
+	if err := w.Open(); err != nil {
+		t.Fatalf("error opening WAL: %v", err)
+	}
+	write(w, 1)
+	write(w, 2)
+	if err := w.Close(); err != nil {
+		t.Fatalf("error closing wal: %v", err)
+	}
+
+	segments, err := SegmentFileNames(dir)
+	if err != nil {
+		t.Fatalf("error getting segments: %v", err)
+	}
+	b, err := ioutil.ReadFile(segments[0])
+	if err != nil {
+		t.Fatalf("error reading segment: %v", err)
+	}
+
+	// Flip a bit of the last byte of the last entry, keeping its length.
+	first := int64(9 + binary.BigEndian.Uint32(b[1:5]))
+	b[len(b)-1] ^= 1
+	if err := ioutil.WriteFile(segments[0], b, 0666); err != nil {
+		t.Fatalf("error writing segment: %v", err)
+	}
+
+	w = NewWAL(dir)
+	if err := w.Open(); err != nil {
+		t.Fatalf("error opening WAL: %v", err)
+	}
+	if stat, err := os.Stat(segments[0]); err != nil {
+		t.Fatalf("error getting segment size: %v", err)
+	} else if got := stat.Size(); got != first {
+		t.Fatalf("segment size mismatch: got %d, exp %d", got, first)
+	}
+	write(w, 3)
+	if err := w.Close(); err != nil {
+		t.Fatalf("error closing wal: %v", err)
+	}
+
+	var got []float64
+	if err := NewWALReader(segments).Read(func(entry WALEntry) error {
+		for _, v := range entry.(*WriteWALEntry).Values["cpu,host=A#!~#value"] {
+			got = append(got, v.Value().(float64))
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("error reading WAL: %v", err)
+	}
+	if exp := []float64{1, 3}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("values mismatch: got %v, exp %v", got, exp)
+	}
+}
+
+// Ensure a corrupt entry before the end of the last segment is not truncated
+// when the WAL is opened, as the entries after it would be lost.
+func TestWAL_Open_CorruptEntry(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	w := NewWAL(dir)
+	if err := w.Open(); err != nil {
+		t.Fatalf("error opening WAL: %v", err)
+	}
+	for _, v := range []float64{1, 2} {
+		if _, err := w.WriteMulti(map[string][]value.Value{
+			"cpu,host=A#!~#value": []value.Value{value.NewValue(1, v)},
+		}); err != nil {
+			t.Fatalf("error writing points: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("error closing wal: %v", err)
+	}
+
+	segments, err := SegmentFileNames(dir)
+	if err != nil {
+		t.Fatalf("error getting segments: %v", err)
+	}
+	b, err := ioutil.ReadFile(segments[0])
+	if err != nil {
+		t.Fatalf("error reading segment: %v", err)
+	}
+
+	// Flip a bit of the last byte of the first entry.
+	b[9+binary.BigEndian.Uint32(b[1:5])-1] ^= 1
+	if err := ioutil.WriteFile(segments[0], b, 0666); err != nil {
+		t.Fatalf("error writing segment: %v", err)
+	}
+
+	w = NewWAL(dir)
+	if err := w.Open(); err == nil {
+		w.Close()
+		t.Fatal("expected error opening WAL with a corrupt entry")
+	}
+
+	stat, err := os.Stat(segments[0])
+	if err != nil {
+		t.Fatalf("error getting segment size: %v", err)
+	}
+	if got, exp := stat.Size(), int64(len(b)); got != exp {
+		t.Fatalf("segment size mismatch: got %d, exp %d", got, exp)
+	}
+}
+
 // Reproduces a `panic: runtime error: makeslice: cap out of range` when run with
 // GOARCH=386 go test -run TestWALSegmentReader_Corrupt -v ./tsdb/engine/tsm1/
 func TestWALSegmentReader_Corrupt(t *testing.T) {
@@ -224,6 +224,7 @@ type TaskFilter struct {
 	After        *ID
 	Organization *ID
 	User         *ID
+	Labels       LabelSelector
 	Limit        int
 }
 
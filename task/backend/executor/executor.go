@@ -9,17 +9,62 @@ import (
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/lang"
+	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/logger"
+	"github.com/influxdata/influxdb/macro"
 	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/task/backend"
 	"go.uber.org/zap"
 )
 
+// Option configures an executor.
+type Option func(*options)
+
+type options struct {
+	macros platform.MacroBindingService
+}
+
+// WithMacroBindingService binds the macros referenced by task scripts, as v.name,
+// to the selected values of the macros of the task's organization when a run
+// executes. Without it, macros keep the values the script declares them with.
+func WithMacroBindingService(s platform.MacroBindingService) Option {
+	return func(o *options) {
+		o.macros = s
+	}
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// script returns the script of t with its macros bound.
+func (o options) script(ctx context.Context, t *backend.StoreTask) (string, error) {
+	if o.macros == nil {
+		return t.Script, nil
+	}
+
+	names := macro.References(macro.LanguageFlux, t.Script)
+	if len(names) == 0 {
+		return t.Script, nil
+	}
+
+	bindings, err := o.macros.FindMacroBindings(ctx, t.Org, names, nil)
+	if err != nil {
+		return "", err
+	}
+	return macro.Bind(macro.LanguageFlux, t.Script, bindings)
+}
+
 // queryServiceExecutor is an implementation of backend.Executor that depends on a QueryService.
 type queryServiceExecutor struct {
 	svc    query.QueryService
 	st     backend.Store
 	logger *zap.Logger
+	opts   options
 	wg     sync.WaitGroup
 }
 
@@ -28,8 +73,8 @@ var _ backend.Executor = (*queryServiceExecutor)(nil)
 // NewQueryServiceExecutor returns a new executor based on the given QueryService.
 // In general, you should prefer NewAsyncQueryServiceExecutor, as that code is smaller and simpler,
 // because asynchronous queries are more in line with the Executor interface.
-func NewQueryServiceExecutor(logger *zap.Logger, svc query.QueryService, st backend.Store) backend.Executor {
-	return &queryServiceExecutor{logger: logger, svc: svc, st: st}
+func NewQueryServiceExecutor(logger *zap.Logger, svc query.QueryService, st backend.Store, opts ...Option) backend.Executor {
+	return &queryServiceExecutor{logger: logger, svc: svc, st: st, opts: newOptions(opts)}
 }
 
 func (e *queryServiceExecutor) Execute(ctx context.Context, run backend.QueuedRun) (backend.RunPromise, error) {
@@ -38,7 +83,14 @@ func (e *queryServiceExecutor) Execute(ctx context.Context, run backend.QueuedRu
 		return nil, err
 	}
 
-	return newSyncRunPromise(ctx, run, e, t), nil
+	script, err := e.opts.script(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+	bound := *t
+	bound.Script = script
+
+	return newSyncRunPromise(ctx, run, e, &bound), nil
 }
 
 func (e *queryServiceExecutor) Wait() {
@@ -180,14 +232,15 @@ type asyncQueryServiceExecutor struct {
 	svc    query.AsyncQueryService
 	st     backend.Store
 	logger *zap.Logger
+	opts   options
 	wg     sync.WaitGroup
 }
 
 var _ backend.Executor = (*asyncQueryServiceExecutor)(nil)
 
 // NewQueryServiceExecutor returns a new executor based on the given AsyncQueryService.
-func NewAsyncQueryServiceExecutor(logger *zap.Logger, svc query.AsyncQueryService, st backend.Store) backend.Executor {
-	return &asyncQueryServiceExecutor{logger: logger, svc: svc, st: st}
+func NewAsyncQueryServiceExecutor(logger *zap.Logger, svc query.AsyncQueryService, st backend.Store, opts ...Option) backend.Executor {
+	return &asyncQueryServiceExecutor{logger: logger, svc: svc, st: st, opts: newOptions(opts)}
 }
 
 func (e *asyncQueryServiceExecutor) Execute(ctx context.Context, run backend.QueuedRun) (backend.RunPromise, error) {
@@ -196,7 +249,12 @@ func (e *asyncQueryServiceExecutor) Execute(ctx context.Context, run backend.Que
 		return nil, err
 	}
 
-	spec, err := flux.Compile(ctx, t.Script, time.Unix(run.Now, 0))
+	script, err := e.opts.script(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := flux.Compile(ctx, script, time.Unix(run.Now, 0))
 	if err != nil {
 		return nil, err
 	}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -623,3 +624,83 @@ func testExecutorWait(t *testing.T, createSys createSysFn) {
 		})
 	})
 }
+
+// fakeMacroBindingService binds macros to the values it maps their names to.
+type fakeMacroBindingService map[string]string
+
+func (s fakeMacroBindingService) FindMacroBindings(ctx context.Context, orgID platform.ID, names []string, bindings map[string]string) (map[string]string, error) {
+	values := map[string]string{}
+	for _, name := range names {
+		if v, ok := s[name]; ok {
+			values[name] = v
+		}
+	}
+	return values, nil
+}
+
+const fmtMacroTestScript = `
+import "http"
+
+option task = {
+			name: %q,
+			every: 1m,
+}
+
+option v = {bucket: "one"}
+
+from(bucket: v.bucket) |> http.to(url: "http://example.com")`
+
+func TestExecutor_Macros(t *testing.T) {
+	var orgID = platformtesting.MustIDBase16("aaaaaaaaaaaaaaaa")
+	var userID = platformtesting.MustIDBase16("baaaaaaaaaaaaaab")
+
+	macros := executor.WithMacroBindingService(fakeMacroBindingService{"bucket": "two"})
+	for _, fn := range []createSysFn{
+		func() *system {
+			svc := newFakeQueryService()
+			st := backend.NewInMemStore()
+			return &system{
+				name: "AsyncExecutor",
+				svc:  svc,
+				st:   st,
+				ex:   executor.NewAsyncQueryServiceExecutor(zap.NewNop(), svc, st, macros),
+			}
+		},
+		func() *system {
+			svc := newFakeQueryService()
+			st := backend.NewInMemStore()
+			return &system{
+				name: "SynchronousExecutor",
+				svc:  svc,
+				st:   st,
+				ex:   executor.NewQueryServiceExecutor(zap.NewNop(), query.QueryServiceBridge{AsyncQueryService: svc}, st, macros),
+			}
+		},
+	} {
+		sys := fn()
+		t.Run(sys.name, func(t *testing.T) {
+			script := fmt.Sprintf(fmtMacroTestScript, t.Name())
+			tid, err := sys.st.CreateTask(context.Background(), backend.CreateTaskRequest{Org: orgID, User: userID, Script: script})
+			if err != nil {
+				t.Fatal(err)
+			}
+			rp, err := sys.ex.Execute(context.Background(), backend.QueuedRun{TaskID: tid, RunID: platform.ID(1), Now: 123})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The run queries the bucket the macro is bound to, not the declared default.
+			bound := fmt.Sprintf(fmtTestScript, t.Name())
+			bound = strings.Replace(bound, `"one"`, `"two"`, 1)
+			sys.svc.WaitForQueryLive(t, bound)
+			sys.svc.SucceedQuery(bound)
+			res, err := rp.Wait()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := res.Err(); got != nil {
+				t.Fatal(got)
+			}
+		})
+	}
+}
@@ -96,15 +96,17 @@ func newFullStackAwareLogReaderWriter(t *testing.T) *fullStackAwareLogReaderWrit
 		memoryBytesQuota = 1e6
 	)
 
-	cc := control.Config{
-		ExecutorDependencies: make(execute.Dependencies),
-		ConcurrencyQuota:     concurrencyQuota,
-		MemoryBytesQuota:     int64(memoryBytesQuota),
-		Logger:               logger.With(zap.String("service", "storage-reads")),
+	cc := pcontrol.Config{
+		Config: control.Config{
+			ExecutorDependencies: make(execute.Dependencies),
+			ConcurrencyQuota:     concurrencyQuota,
+			MemoryBytesQuota:     int64(memoryBytesQuota),
+			Logger:               logger.With(zap.String("service", "storage-reads")),
+		},
 	}
 
 	if err := readservice.AddControllerConfigDependencies(
-		&cc, engine, svc, svc,
+		&cc.Config, engine, svc, svc, nil,
 	); err != nil {
 		t.Fatal(err)
 	}
@@ -29,10 +29,13 @@ type taskServiceValidator struct {
 	preAuth query.PreAuthorizer
 }
 
-func NewValidator(ts platform.TaskService, bs platform.BucketService) platform.TaskService {
+// NewValidator returns a TaskService checking the permissions of the authorizer
+// on context. The buckets of task scripts are resolved in the organization of
+// the task with bs, including those shared with it through ss if it is not nil.
+func NewValidator(ts platform.TaskService, bs platform.BucketService, ss platform.ShareService) platform.TaskService {
 	return &taskServiceValidator{
 		TaskService: ts,
-		preAuth:     query.NewPreAuthorizer(bs),
+		preAuth:     query.NewSharedPreAuthorizer(bs, ss),
 	}
 }
 func (ts *taskServiceValidator) FindTaskByID(ctx context.Context, id platform.ID) (*platform.Task, error) {
@@ -80,7 +83,7 @@ func (ts *taskServiceValidator) CreateTask(ctx context.Context, t *platform.Task
 		return err
 	}
 
-	if err := validateBucket(ctx, t.Flux, ts.preAuth); err != nil {
+	if err := validateBucket(ctx, t.OrganizationID, t.Flux, ts.preAuth); err != nil {
 		return err
 	}
 
@@ -102,7 +105,7 @@ func (ts *taskServiceValidator) UpdateTask(ctx context.Context, id platform.ID,
 		return nil, err
 	}
 
-	if err := validateBucket(ctx, task.Flux, ts.preAuth); err != nil {
+	if err := validateBucket(ctx, task.OrganizationID, task.Flux, ts.preAuth); err != nil {
 		return nil, err
 	}
 
@@ -246,7 +249,7 @@ func validatePermission(ctx context.Context, perm platform.Permission) error {
 	return nil
 }
 
-func validateBucket(ctx context.Context, script string, preAuth query.PreAuthorizer) error {
+func validateBucket(ctx context.Context, orgID platform.ID, script string, preAuth query.PreAuthorizer) error {
 	auth, err := platcontext.GetAuthorizer(ctx)
 	if err != nil {
 		return err
@@ -260,6 +263,8 @@ func validateBucket(ctx context.Context, script string, preAuth query.PreAuthori
 			platform.WithErrorCode(platform.EInvalid))
 	}
 
+	// The buckets of the script are those of the organization of the task.
+	ctx = query.ContextWithRequest(ctx, &query.Request{OrganizationID: orgID})
 	if err := preAuth.PreAuthorize(ctx, spec, auth); err != nil {
 		return platform.NewError(
 			platform.WithErrorErr(err),
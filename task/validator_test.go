@@ -15,7 +15,7 @@ import (
 
 func TestOnboardingValidation(t *testing.T) {
 	svc := inmem.NewService()
-	validator := task.NewValidator(mockTaskService(), svc)
+	validator := task.NewValidator(mockTaskService(), svc, nil)
 
 	r, err := svc.Generate(context.Background(), &influxdb.OnboardingRequest{
 		User:            "dude",
@@ -110,7 +110,7 @@ from(bucket:"holder") |> range(start:-5m) |> to(bucket:"holder", org:"thing")`,
 
 func TestValidations(t *testing.T) {
 	inmem := inmem.NewService()
-	validTaskService := task.NewValidator(mockTaskService(), inmem)
+	validTaskService := task.NewValidator(mockTaskService(), inmem, nil)
 
 	r, err := inmem.Generate(context.Background(), &influxdb.OnboardingRequest{
 		User:            "dude",
@@ -8,8 +8,10 @@ import (
 	"time"
 
 	"github.com/influxdata/influxdb/telegraf/plugins"
+	"github.com/influxdata/influxdb/telegraf/plugins/aggregators"
 	"github.com/influxdata/influxdb/telegraf/plugins/inputs"
 	"github.com/influxdata/influxdb/telegraf/plugins/outputs"
+	"github.com/influxdata/influxdb/telegraf/plugins/processors"
 )
 
 // ErrTelegrafConfigInvalidOrganizationID is the error message for a missing or invalid organization ID.
@@ -26,6 +28,8 @@ var (
 	OpCreateTelegrafConfig   = "CreateTelegrafConfig"
 	OpUpdateTelegrafConfig   = "UpdateTelegrafConfig"
 	OpDeleteTelegrafConfig   = "DeleteTelegrafConfig"
+	OpCheckInTelegrafAgent   = "CheckInTelegrafAgent"
+	OpFindTelegrafAgents     = "FindTelegrafAgents"
 )
 
 // TelegrafConfigStore represents a service for managing telegraf config data.
@@ -55,30 +59,84 @@ type TelegrafConfigStore interface {
 	DeleteTelegrafConfig(ctx context.Context, id ID) error
 }
 
+// TelegrafAgentService records the telegraf agents fetching telegraf configs.
+type TelegrafAgentService interface {
+	// CheckInTelegrafAgent records that an agent fetched a version of a telegraf config,
+	// replacing the previous record of the agent for that config.
+	CheckInTelegrafAgent(ctx context.Context, a *TelegrafAgent) error
+
+	// FindTelegrafAgents returns the agents that fetched the telegraf config id, by hostname.
+	FindTelegrafAgents(ctx context.Context, id ID) ([]*TelegrafAgent, error)
+}
+
+// TelegrafAgent is a telegraf agent, as of its last fetch of its config.
+type TelegrafAgent struct {
+	TelegrafID ID        `json:"telegrafID"`
+	Hostname   string    `json:"hostname"`
+	Version    int64     `json:"version"`
+	LastSeen   time.Time `json:"lastSeen"`
+}
+
 // TelegrafConfigFilter represents a set of filter that restrict the returned telegraf configs.
 type TelegrafConfigFilter struct {
 	OrganizationID *ID
 	Organization   *string
+	Labels         LabelSelector
 	UserResourceMappingFilter
 }
 
+// QueryParams converts TelegrafConfigFilter fields to url query params.
+func (f TelegrafConfigFilter) QueryParams() map[string][]string {
+	qp := map[string][]string{}
+	if f.OrganizationID != nil {
+		qp["orgID"] = []string{f.OrganizationID.String()}
+	}
+
+	if f.Organization != nil {
+		qp["org"] = []string{*f.Organization}
+	}
+
+	if f.UserID.Valid() {
+		qp["userID"] = []string{f.UserID.String()}
+	}
+
+	if f.ResourceID.Valid() {
+		qp["resourceID"] = []string{f.ResourceID.String()}
+	}
+
+	for k, v := range f.Labels.QueryParams() {
+		qp[k] = v
+	}
+
+	return qp
+}
+
 // TelegrafConfig stores telegraf config for one telegraf instance.
 type TelegrafConfig struct {
 	ID             ID
 	OrganizationID ID
 	Name           string
 
+	// Version is incremented on every update of the config, starting at 1.
+	Version int64
+
 	Agent   TelegrafAgentConfig
 	Plugins []TelegrafPlugin
 }
 
+// Cursor returns the position of the telegraf config in a listing sorted by sortBy.
+// Telegraf configs may be sorted by ID or Name.
+func (tc *TelegrafConfig) Cursor(sortBy string) (PageCursor, error) {
+	return namePageCursor(sortBy, tc.Name, tc.ID)
+}
+
 // TOML returns the telegraf toml config string.
 func (tc TelegrafConfig) TOML() string {
 	plugins := ""
 	for _, p := range tc.Plugins {
 		plugins += p.Config.TOML()
 	}
-	interval := time.Duration(tc.Agent.Interval * 1000000)
+	a := tc.Agent.withDefaults()
 	return fmt.Sprintf(`# Configuration for telegraf agent
 [agent]
   ## Default data collection interval for all inputs
@@ -90,27 +148,27 @@ func (tc TelegrafConfig) TOML() string {
   ## Telegraf will send metrics to outputs in batches of at most
   ## metric_batch_size metrics.
   ## This controls the size of writes that Telegraf sends to output plugins.
-  metric_batch_size = 1000
+  metric_batch_size = %d
 
   ## For failed writes, telegraf will cache metric_buffer_limit metrics for each
   ## output, and will flush this buffer on a successful write. Oldest metrics
   ## are dropped first when this buffer fills.
   ## This buffer only fills when writes fail to output plugin(s).
-  metric_buffer_limit = 10000
+  metric_buffer_limit = %d
 
   ## Collection jitter is used to jitter the collection by a random amount.
   ## Each plugin will sleep for a random time within jitter before collecting.
   ## This can be used to avoid many plugins querying things like sysfs at the
   ## same time, which can have a measurable effect on the system.
-  collection_jitter = "0s"
+  collection_jitter = "%s"
 
   ## Default flushing interval for all outputs. Maximum flush_interval will be
   ## flush_interval + flush_jitter
-  flush_interval = "10s"
+  flush_interval = "%s"
   ## Jitter the flush interval by a random amount. This is primarily to avoid
   ## large write spikes for users running a large number of telegraf instances.
   ## ie, a jitter of 5s and interval 10s means flushes will happen every 10-15s
-  flush_jitter = "0s"
+  flush_jitter = "%s"
 
   ## By default or when set to "0s", precision will be set to the same
   ## timestamp order as the collection interval, with the maximum being 1s.
@@ -119,7 +177,7 @@ func (tc TelegrafConfig) TOML() string {
   ## Precision will NOT be used for service inputs. It is up to each individual
   ## service input to set the timestamp at the appropriate precision.
   ## Valid time units are "ns", "us" (or "µs"), "ms", "s".
-  precision = ""
+  precision = "%s"
 
   ## Logging configuration:
   ## Run telegraf with debug log messages.
@@ -133,7 +191,21 @@ func (tc TelegrafConfig) TOML() string {
   hostname = ""
   ## If set to true, do no set the "host" tag in the telegraf agent.
   omit_hostname = false
-%s`, interval.String(), plugins)
+%s`,
+		millis(a.Interval),
+		a.MetricBatchSize,
+		a.MetricBufferLimit,
+		millis(a.CollectionJitter),
+		millis(a.FlushInterval),
+		millis(a.FlushJitter),
+		a.Precision,
+		plugins,
+	)
+}
+
+// millis returns the duration of ms milliseconds as a string.
+func millis(ms int64) string {
+	return (time.Duration(ms) * time.Millisecond).String()
 }
 
 // telegrafConfigEncode is the helper struct for json encoding.
@@ -141,6 +213,7 @@ type telegrafConfigEncode struct {
 	ID             ID     `json:"id"`
 	OrganizationID ID     `json:"organizationID,omitempty"`
 	Name           string `json:"name"`
+	Version        int64  `json:"version,omitempty"`
 
 	Agent TelegrafAgentConfig `json:"agent"`
 
@@ -161,6 +234,7 @@ type telegrafConfigDecode struct {
 	ID             ID     `json:"id"`
 	OrganizationID ID     `json:"organizationID,omitempty"`
 	Name           string `json:"name"`
+	Version        int64  `json:"version,omitempty"`
 
 	Agent TelegrafAgentConfig `json:"agent"`
 
@@ -183,9 +257,97 @@ type TelegrafPlugin struct {
 }
 
 // TelegrafAgentConfig is based telegraf/internal/config AgentConfig.
+// Zero values stand for the telegraf defaults.
 type TelegrafAgentConfig struct {
 	// Interval at which to gather information in miliseconds.
 	Interval int64 `json:"collectionInterval"`
+	// MetricBatchSize is the maximum number of metrics written to outputs at once.
+	MetricBatchSize int64 `json:"metricBatchSize,omitempty"`
+	// MetricBufferLimit is the number of metrics buffered for each output while writes fail.
+	MetricBufferLimit int64 `json:"metricBufferLimit,omitempty"`
+	// CollectionJitter is the maximum random delay of collections in miliseconds.
+	CollectionJitter int64 `json:"collectionJitter,omitempty"`
+	// FlushInterval is the interval at which outputs are written in miliseconds.
+	FlushInterval int64 `json:"flushInterval,omitempty"`
+	// FlushJitter is the maximum random delay of flushes in miliseconds.
+	FlushJitter int64 `json:"flushJitter,omitempty"`
+	// Precision is the precision of timestamps, such as "1s"; empty derives it from Interval.
+	Precision string `json:"precision,omitempty"`
+}
+
+// telegraf agent defaults.
+const (
+	defaultTelegrafMetricBatchSize   = 1000
+	defaultTelegrafMetricBufferLimit = 10000
+	defaultTelegrafFlushInterval     = 10000
+)
+
+func (a TelegrafAgentConfig) withDefaults() TelegrafAgentConfig {
+	if a.MetricBatchSize == 0 {
+		a.MetricBatchSize = defaultTelegrafMetricBatchSize
+	}
+	if a.MetricBufferLimit == 0 {
+		a.MetricBufferLimit = defaultTelegrafMetricBufferLimit
+	}
+	if a.FlushInterval == 0 {
+		a.FlushInterval = defaultTelegrafFlushInterval
+	}
+	return a
+}
+
+// unmarshalTOML decodes the settings of the [agent] table.
+func (a *TelegrafAgentConfig) unmarshalTOML(agent map[string]interface{}) error {
+	intervalStr, ok := agent["interval"].(string)
+	if !ok {
+		return errors.New("agent interval is not string")
+	}
+	interval, err := time.ParseDuration(intervalStr)
+	if err != nil {
+		return err
+	}
+	*a = TelegrafAgentConfig{
+		Interval: interval.Nanoseconds() / 1000000,
+	}
+
+	for key, ms := range map[string]*int64{
+		"collection_jitter": &a.CollectionJitter,
+		"flush_interval":    &a.FlushInterval,
+		"flush_jitter":      &a.FlushJitter,
+	} {
+		v, ok := agent[key]
+		if !ok {
+			continue
+		}
+		str, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("agent %s is not string", key)
+		}
+		d, err := time.ParseDuration(str)
+		if err != nil {
+			return err
+		}
+		*ms = d.Nanoseconds() / 1000000
+	}
+
+	for key, n := range map[string]*int64{
+		"metric_batch_size":   &a.MetricBatchSize,
+		"metric_buffer_limit": &a.MetricBufferLimit,
+	} {
+		v, ok := agent[key]
+		if !ok {
+			continue
+		}
+		if *n, ok = v.(int64); !ok {
+			return fmt.Errorf("agent %s is not integer", key)
+		}
+	}
+
+	if v, ok := agent["precision"]; ok {
+		if a.Precision, ok = v.(string); !ok {
+			return errors.New("agent precision is not string")
+		}
+	}
+	return nil
 }
 
 // errors
@@ -203,6 +365,7 @@ func (tc *TelegrafConfig) MarshalJSON() ([]byte, error) {
 		ID:             tc.ID,
 		OrganizationID: tc.OrganizationID,
 		Name:           tc.Name,
+		Version:        tc.Version,
 		Agent:          tc.Agent,
 		Plugins:        make([]telegrafPluginEncode, len(tc.Plugins)),
 	}
@@ -228,18 +391,9 @@ func (tc *TelegrafConfig) UnmarshalTOML(data interface{}) error {
 		return errors.New("agent is missing")
 	}
 
-	intervalStr, ok := agent["interval"].(string)
-	if !ok {
-		return errors.New("agent interval is not string")
-	}
-
-	interval, err := time.ParseDuration(intervalStr)
-	if err != nil {
+	if err := tc.Agent.unmarshalTOML(agent); err != nil {
 		return err
 	}
-	tc.Agent = TelegrafAgentConfig{
-		Interval: interval.Nanoseconds() / 1000000,
-	}
 
 	for tp, ps := range dataOk {
 		if tp == "agent" {
@@ -270,25 +424,22 @@ func (tc *TelegrafConfig) UnmarshalTOML(data interface{}) error {
 }
 
 func (tc *TelegrafConfig) parseTOMLPluginConfig(typ, name string, configData interface{}) error {
-	var ok bool
-	var tpFn func() plugins.Config
+	var pluginType plugins.Type
 	switch typ {
 	case "inputs":
-		tpFn, ok = availableInputPlugins[name]
+		pluginType = plugins.Input
 	case "outputs":
-		tpFn, ok = availableOutputPlugins[name]
+		pluginType = plugins.Output
+	case "processors":
+		pluginType = plugins.Processor
+	case "aggregators":
+		pluginType = plugins.Aggregator
 	default:
 		return &Error{
 			Msg: fmt.Sprintf(ErrUnsupportTelegrafPluginType, typ),
 		}
 	}
-
-	if !ok {
-		return &Error{
-			Msg: fmt.Sprintf(ErrUnsupportTelegrafPluginName, name, typ),
-		}
-	}
-	p := tpFn()
+	p := newTelegrafPlugin(pluginType, name)
 
 	if err := p.UnmarshalTOML(configData); err != nil {
 		return err
@@ -309,6 +460,7 @@ func (tc *TelegrafConfig) UnmarshalJSON(b []byte) error {
 		ID:             tcd.ID,
 		OrganizationID: tcd.OrganizationID,
 		Name:           tcd.Name,
+		Version:        tcd.Version,
 		Agent:          tcd.Agent,
 		Plugins:        make([]TelegrafPlugin, len(tcd.Plugins)),
 	}
@@ -318,14 +470,8 @@ func (tc *TelegrafConfig) UnmarshalJSON(b []byte) error {
 func decodePluginRaw(tcd *telegrafConfigDecode, tc *TelegrafConfig) (err error) {
 	op := "unmarshal telegraf config raw plugin"
 	for k, pr := range tcd.Plugins {
-		var tpFn func() plugins.Config
-		var config plugins.Config
-		var ok bool
 		switch pr.Type {
-		case plugins.Input:
-			tpFn, ok = availableInputPlugins[pr.Name]
-		case plugins.Output:
-			tpFn, ok = availableOutputPlugins[pr.Name]
+		case plugins.Input, plugins.Output, plugins.Processor, plugins.Aggregator:
 		default:
 			return &Error{
 				Code: EInvalid,
@@ -333,31 +479,50 @@ func decodePluginRaw(tcd *telegrafConfigDecode, tc *TelegrafConfig) (err error)
 				Op:   op,
 			}
 		}
-		if ok {
-			config = tpFn()
-			if err = json.Unmarshal(pr.Config, config); err != nil {
-				return &Error{
-					Code: EInvalid,
-					Err:  err,
-					Op:   op,
-				}
+		if pr.Name == "" {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "telegraf plugin name is missing",
+				Op:   op,
 			}
-			tc.Plugins[k] = TelegrafPlugin{
-				Comment: pr.Comment,
-				Config:  config,
+		}
+
+		config := newTelegrafPlugin(pr.Type, pr.Name)
+		if err = json.Unmarshal(pr.Config, config); err != nil {
+			return &Error{
+				Code: EInvalid,
+				Err:  err,
+				Op:   op,
 			}
-			continue
 		}
-		return &Error{
-			Code: EInvalid,
-			Op:   op,
-			Msg:  fmt.Sprintf(ErrUnsupportTelegrafPluginName, pr.Name, pr.Type),
+		tc.Plugins[k] = TelegrafPlugin{
+			Comment: pr.Comment,
+			Config:  config,
 		}
-
 	}
 	return nil
 }
 
+// newTelegrafPlugin returns the config of the plugin name of type typ, or a
+// raw config for plugins without one.
+func newTelegrafPlugin(typ plugins.Type, name string) plugins.Config {
+	var available map[string](func() plugins.Config)
+	switch typ {
+	case plugins.Input:
+		available = availableInputPlugins
+	case plugins.Output:
+		available = availableOutputPlugins
+	case plugins.Processor:
+		available = availableProcessorPlugins
+	case plugins.Aggregator:
+		available = availableAggregatorPlugins
+	}
+	if fn, ok := available[name]; ok {
+		return fn()
+	}
+	return &plugins.Raw{PluginType: typ, Name: name}
+}
+
 var availableInputPlugins = map[string](func() plugins.Config){
 	"cpu":          func() plugins.Config { return &inputs.CPUStats{} },
 	"disk":         func() plugins.Config { return &inputs.DiskStats{} },
@@ -385,3 +550,12 @@ var availableOutputPlugins = map[string](func() plugins.Config){
 	"file":        func() plugins.Config { return &outputs.File{} },
 	"influxdb_v2": func() plugins.Config { return &outputs.InfluxDBV2{} },
 }
+
+var availableProcessorPlugins = map[string](func() plugins.Config){
+	"dedup": func() plugins.Config { return &processors.Dedup{} },
+}
+
+var availableAggregatorPlugins = map[string](func() plugins.Config){
+	"basicstats": func() plugins.Config { return &aggregators.BasicStats{} },
+	"minmax":     func() plugins.Config { return &aggregators.MinMax{} },
+}
@@ -0,0 +1,148 @@
+package aggregators
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/influxdata/influxdb/telegraf/plugins"
+)
+
+// local plugin
+type telegrafPluginConfig interface {
+	TOML() string
+	Type() plugins.Type
+	PluginName() string
+	UnmarshalTOML(data interface{}) error
+}
+
+func TestType(t *testing.T) {
+	b := baseAggregator(0)
+	if b.Type() != plugins.Aggregator {
+		t.Fatalf("aggregator plugins type should be aggregator, got %s", b.Type())
+	}
+}
+
+func TestTOML(t *testing.T) {
+	cases := []struct {
+		name    string
+		plugins map[telegrafPluginConfig]string
+	}{
+		{
+			name: "test empty plugins",
+			plugins: map[telegrafPluginConfig]string{
+				&BasicStats{}: `[[aggregators.basicstats]]
+  ## The period on which to flush & clear the aggregator.
+  period = "30s"
+  ## If true, the original metric will be dropped by the
+  ## aggregator and will not get sent to the output plugins.
+  drop_original = false
+  ## Configures which basic stats to push as fields
+  # stats = ["count", "min", "max", "mean", "stdev", "s2", "sum"]
+`,
+				&MinMax{}: `[[aggregators.minmax]]
+  ## General Aggregator Arguments:
+  ## The period on which to flush & clear the aggregator.
+  period = "30s"
+  ## If true, the original metric will be dropped by the
+  ## aggregator and will not get sent to the output plugins.
+  drop_original = false
+`,
+			},
+		},
+		{
+			name: "standard testing",
+			plugins: map[telegrafPluginConfig]string{
+				&BasicStats{
+					Period:       "1m",
+					DropOriginal: true,
+					Stats:        []string{"mean", "sum"},
+				}: `[[aggregators.basicstats]]
+  ## The period on which to flush & clear the aggregator.
+  period = "1m"
+  ## If true, the original metric will be dropped by the
+  ## aggregator and will not get sent to the output plugins.
+  drop_original = true
+  ## Configures which basic stats to push as fields
+  stats = ["mean", "sum"]
+`,
+			},
+		},
+	}
+	for _, c := range cases {
+		for output, toml := range c.plugins {
+			if toml != output.TOML() {
+				t.Fatalf("%s failed want %s, got %v", c.name, toml, output.TOML())
+			}
+		}
+	}
+}
+
+func TestDecodeTOML(t *testing.T) {
+	cases := []struct {
+		name    string
+		want    telegrafPluginConfig
+		wantErr error
+		output  telegrafPluginConfig
+		data    interface{}
+	}{
+		{
+			name:    "basicstats empty",
+			want:    &BasicStats{},
+			wantErr: errors.New("bad config for basicstats aggregator plugin"),
+			output:  &BasicStats{},
+		},
+		{
+			name:    "basicstats bad stats",
+			want:    &BasicStats{},
+			wantErr: errors.New("stats is not an array for basicstats aggregator plugin"),
+			output:  &BasicStats{},
+			data: map[string]interface{}{
+				"stats": "mean",
+			},
+		},
+		{
+			name: "basicstats",
+			want: &BasicStats{
+				Period:       "1m",
+				DropOriginal: true,
+				Stats:        []string{"mean"},
+			},
+			output: &BasicStats{},
+			data: map[string]interface{}{
+				"period":        "1m",
+				"drop_original": true,
+				"stats":         []interface{}{"mean"},
+			},
+		},
+		{
+			name:    "minmax bad period",
+			want:    &MinMax{},
+			wantErr: errors.New("period is not a string for minmax aggregator plugin"),
+			output:  &MinMax{},
+			data: map[string]interface{}{
+				"period": int64(30),
+			},
+		},
+		{
+			name:   "minmax",
+			want:   &MinMax{Period: "10s"},
+			output: &MinMax{},
+			data: map[string]interface{}{
+				"period": "10s",
+			},
+		},
+	}
+	for _, c := range cases {
+		err := c.output.UnmarshalTOML(c.data)
+		if c.wantErr != nil && (err == nil || err.Error() != c.wantErr.Error()) {
+			t.Fatalf("%s failed want err %s, got %v", c.name, c.wantErr.Error(), err)
+		}
+		if c.wantErr == nil && err != nil {
+			t.Fatalf("%s failed want err nil, got %v", c.name, err)
+		}
+		if !reflect.DeepEqual(c.output, c.want) {
+			t.Fatalf("%s failed want %v, got %v", c.name, c.want, c.output)
+		}
+	}
+}
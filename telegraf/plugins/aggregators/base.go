@@ -0,0 +1,38 @@
+package aggregators
+
+import (
+	"fmt"
+
+	"github.com/influxdata/influxdb/telegraf/plugins"
+)
+
+type baseAggregator int
+
+func (b baseAggregator) Type() plugins.Type {
+	return plugins.Aggregator
+}
+
+// defaultPeriod is the period aggregators flush at when none is set.
+const defaultPeriod = "30s"
+
+func period(p string) string {
+	if p == "" {
+		return defaultPeriod
+	}
+	return p
+}
+
+// decodeBase decodes the arguments all aggregators share.
+func decodeBase(data map[string]interface{}, period *string, dropOriginal *bool, name string) error {
+	if p, ok := data["period"]; ok {
+		if *period, ok = p.(string); !ok {
+			return fmt.Errorf("period is not a string for %s aggregator plugin", name)
+		}
+	}
+	if d, ok := data["drop_original"]; ok {
+		if *dropOriginal, ok = d.(bool); !ok {
+			return fmt.Errorf("drop_original is not a bool for %s aggregator plugin", name)
+		}
+	}
+	return nil
+}
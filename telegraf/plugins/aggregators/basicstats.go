@@ -0,0 +1,67 @@
+package aggregators
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// BasicStats is based on telegraf basicstats aggregator plugin.
+type BasicStats struct {
+	baseAggregator
+	Period       string   `json:"period"`
+	DropOriginal bool     `json:"dropOriginal"`
+	Stats        []string `json:"stats"`
+}
+
+// PluginName is based on telegraf plugin name.
+func (b *BasicStats) PluginName() string {
+	return "basicstats"
+}
+
+// TOML encodes to toml string.
+func (b *BasicStats) TOML() string {
+	stats := "# stats = [\"count\", \"min\", \"max\", \"mean\", \"stdev\", \"s2\", \"sum\"]"
+	if len(b.Stats) > 0 {
+		s := make([]string, len(b.Stats))
+		for k, v := range b.Stats {
+			s[k] = strconv.Quote(v)
+		}
+		stats = fmt.Sprintf("stats = [%s]", strings.Join(s, ", "))
+	}
+	return fmt.Sprintf(`[[aggregators.%s]]
+  ## The period on which to flush & clear the aggregator.
+  period = %q
+  ## If true, the original metric will be dropped by the
+  ## aggregator and will not get sent to the output plugins.
+  drop_original = %t
+  ## Configures which basic stats to push as fields
+  %s
+`, b.PluginName(), period(b.Period), b.DropOriginal, stats)
+}
+
+// UnmarshalTOML decodes the parsed data to the object
+func (b *BasicStats) UnmarshalTOML(data interface{}) error {
+	dataOK, ok := data.(map[string]interface{})
+	if !ok {
+		return errors.New("bad config for basicstats aggregator plugin")
+	}
+	if err := decodeBase(dataOK, &b.Period, &b.DropOriginal, b.PluginName()); err != nil {
+		return err
+	}
+	if stats, ok := dataOK["stats"]; ok {
+		ss, ok := stats.([]interface{})
+		if !ok {
+			return errors.New("stats is not an array for basicstats aggregator plugin")
+		}
+		for _, s := range ss {
+			str, ok := s.(string)
+			if !ok {
+				return errors.New("stats is not an array of strings for basicstats aggregator plugin")
+			}
+			b.Stats = append(b.Stats, str)
+		}
+	}
+	return nil
+}
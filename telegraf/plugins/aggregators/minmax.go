@@ -0,0 +1,39 @@
+package aggregators
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MinMax is based on telegraf minmax aggregator plugin.
+type MinMax struct {
+	baseAggregator
+	Period       string `json:"period"`
+	DropOriginal bool   `json:"dropOriginal"`
+}
+
+// PluginName is based on telegraf plugin name.
+func (m *MinMax) PluginName() string {
+	return "minmax"
+}
+
+// TOML encodes to toml string.
+func (m *MinMax) TOML() string {
+	return fmt.Sprintf(`[[aggregators.%s]]
+  ## General Aggregator Arguments:
+  ## The period on which to flush & clear the aggregator.
+  period = %q
+  ## If true, the original metric will be dropped by the
+  ## aggregator and will not get sent to the output plugins.
+  drop_original = %t
+`, m.PluginName(), period(m.Period), m.DropOriginal)
+}
+
+// UnmarshalTOML decodes the parsed data to the object
+func (m *MinMax) UnmarshalTOML(data interface{}) error {
+	dataOK, ok := data.(map[string]interface{})
+	if !ok {
+		return errors.New("bad config for minmax aggregator plugin")
+	}
+	return decodeBase(dataOK, &m.Period, &m.DropOriginal, m.PluginName())
+}
@@ -0,0 +1,44 @@
+package processors
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Dedup is based on telegraf dedup processor plugin.
+type Dedup struct {
+	baseProcessor
+	DedupInterval string `json:"dedupInterval"`
+}
+
+// PluginName is based on telegraf plugin name.
+func (d *Dedup) PluginName() string {
+	return "dedup"
+}
+
+// TOML encodes to toml string.
+func (d *Dedup) TOML() string {
+	interval := d.DedupInterval
+	if interval == "" {
+		interval = "600s"
+	}
+	return fmt.Sprintf(`[[processors.%s]]
+  ## Maximum time to suppress output
+  dedup_interval = %q
+`, d.PluginName(), interval)
+}
+
+// UnmarshalTOML decodes the parsed data to the object
+func (d *Dedup) UnmarshalTOML(data interface{}) error {
+	dataOK, ok := data.(map[string]interface{})
+	if !ok {
+		return errors.New("bad config for dedup processor plugin")
+	}
+	if interval, ok := dataOK["dedup_interval"]; ok {
+		d.DedupInterval, ok = interval.(string)
+		if !ok {
+			return errors.New("dedup_interval is not a string for dedup processor plugin")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package processors
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/influxdata/influxdb/telegraf/plugins"
+)
+
+// local plugin
+type telegrafPluginConfig interface {
+	TOML() string
+	Type() plugins.Type
+	PluginName() string
+	UnmarshalTOML(data interface{}) error
+}
+
+func TestType(t *testing.T) {
+	b := baseProcessor(0)
+	if b.Type() != plugins.Processor {
+		t.Fatalf("processor plugins type should be processor, got %s", b.Type())
+	}
+}
+
+func TestTOML(t *testing.T) {
+	cases := []struct {
+		name    string
+		plugins map[telegrafPluginConfig]string
+	}{
+		{
+			name: "test empty plugins",
+			plugins: map[telegrafPluginConfig]string{
+				&Dedup{}: `[[processors.dedup]]
+  ## Maximum time to suppress output
+  dedup_interval = "600s"
+`,
+			},
+		},
+		{
+			name: "standard testing",
+			plugins: map[telegrafPluginConfig]string{
+				&Dedup{DedupInterval: "60s"}: `[[processors.dedup]]
+  ## Maximum time to suppress output
+  dedup_interval = "60s"
+`,
+			},
+		},
+	}
+	for _, c := range cases {
+		for output, toml := range c.plugins {
+			if toml != output.TOML() {
+				t.Fatalf("%s failed want %s, got %v", c.name, toml, output.TOML())
+			}
+		}
+	}
+}
+
+func TestDecodeTOML(t *testing.T) {
+	cases := []struct {
+		name    string
+		want    telegrafPluginConfig
+		wantErr error
+		output  telegrafPluginConfig
+		data    interface{}
+	}{
+		{
+			name:    "dedup empty",
+			want:    &Dedup{},
+			wantErr: errors.New("bad config for dedup processor plugin"),
+			output:  &Dedup{},
+		},
+		{
+			name:    "dedup bad interval",
+			want:    &Dedup{},
+			wantErr: errors.New("dedup_interval is not a string for dedup processor plugin"),
+			output:  &Dedup{},
+			data: map[string]interface{}{
+				"dedup_interval": int64(60),
+			},
+		},
+		{
+			name:   "dedup",
+			want:   &Dedup{DedupInterval: "60s"},
+			output: &Dedup{},
+			data: map[string]interface{}{
+				"dedup_interval": "60s",
+			},
+		},
+	}
+	for _, c := range cases {
+		err := c.output.UnmarshalTOML(c.data)
+		if c.wantErr != nil && (err == nil || err.Error() != c.wantErr.Error()) {
+			t.Fatalf("%s failed want err %s, got %v", c.name, c.wantErr.Error(), err)
+		}
+		if c.wantErr == nil && err != nil {
+			t.Fatalf("%s failed want err nil, got %v", c.name, err)
+		}
+		if !reflect.DeepEqual(c.output, c.want) {
+			t.Fatalf("%s failed want %v, got %v", c.name, c.want, c.output)
+		}
+	}
+}
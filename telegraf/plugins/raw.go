@@ -0,0 +1,141 @@
+package plugins
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Raw is a plugin of any type and name, configured by the values decoded from
+// its TOML table. It keeps the configuration of plugins without a config type
+// of their own, so that they encode back to the TOML they were decoded from.
+type Raw struct {
+	PluginType Type
+	Name       string
+	Config     map[string]interface{}
+}
+
+// Type is the plugin type.
+func (r *Raw) Type() Type {
+	return r.PluginType
+}
+
+// PluginName is the telegraf plugin name.
+func (r *Raw) PluginName() string {
+	return r.Name
+}
+
+// TOML encodes to toml string.
+func (r *Raw) TOML() string {
+	config := r.Config
+	if config == nil {
+		config = map[string]interface{}{}
+	}
+
+	// Encoding the plugin within its table qualifies the names of the tables
+	// nested in its configuration, such as [inputs.name.tagpass].
+	var buf bytes.Buffer
+	enc := toml.NewEncoder(&buf)
+	enc.Indent = "  "
+	err := enc.Encode(map[string]interface{}{
+		r.PluginType.Table(): map[string]interface{}{
+			r.Name: []map[string]interface{}{config},
+		},
+	})
+	if err != nil {
+		return fmt.Sprintf("# unable to encode %s.%s: %v\n", r.PluginType.Table(), r.Name, err)
+	}
+
+	// Drop the header of the enclosing table and the indentation it adds.
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var s strings.Builder
+	for _, line := range lines[1:] {
+		line = strings.TrimPrefix(line, enc.Indent)
+		if line == "" && s.Len() == 0 {
+			continue
+		}
+		s.WriteString(line)
+		s.WriteString("\n")
+	}
+	return s.String()
+}
+
+// UnmarshalTOML decodes the parsed data to the object
+func (r *Raw) UnmarshalTOML(data interface{}) error {
+	if data == nil {
+		r.Config = map[string]interface{}{}
+		return nil
+	}
+	config, ok := data.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("bad config for %s %s plugin", r.Name, r.PluginType)
+	}
+	r.Config = config
+	return nil
+}
+
+// MarshalJSON encodes the configuration of the plugin.
+func (r *Raw) MarshalJSON() ([]byte, error) {
+	if r.Config == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(r.Config)
+}
+
+// UnmarshalJSON decodes the configuration of the plugin. Numbers without a
+// fraction or exponent decode as integers, as they do from TOML.
+func (r *Raw) UnmarshalJSON(b []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var config map[string]interface{}
+	if err := dec.Decode(&config); err != nil {
+		return err
+	}
+	if config == nil {
+		return errors.New("config is not an object")
+	}
+
+	v, err := fromJSON(config)
+	if err != nil {
+		return err
+	}
+	r.Config = v.(map[string]interface{})
+	return nil
+}
+
+// fromJSON converts the numbers of a value decoded from JSON to the integers
+// and floats TOML decodes numbers to.
+func fromJSON(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+		return v.Float64()
+	case map[string]interface{}:
+		for k, e := range v {
+			c, err := fromJSON(e)
+			if err != nil {
+				return nil, err
+			}
+			v[k] = c
+		}
+		return v, nil
+	case []interface{}:
+		for i, e := range v {
+			c, err := fromJSON(e)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = c
+		}
+		return v, nil
+	default:
+		return v, nil
+	}
+}
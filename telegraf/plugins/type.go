@@ -22,3 +22,9 @@ type Config interface {
 	// PluginName is the string value of telegraf plugin package name.
 	PluginName() string
 }
+
+// Table returns the name of the TOML table plugins of type t are configured
+// under, such as "inputs".
+func (t Type) Table() string {
+	return string(t) + "s"
+}
@@ -11,8 +11,10 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/influxdata/influxdb/telegraf/plugins"
+	"github.com/influxdata/influxdb/telegraf/plugins/aggregators"
 	"github.com/influxdata/influxdb/telegraf/plugins/inputs"
 	"github.com/influxdata/influxdb/telegraf/plugins/outputs"
+	"github.com/influxdata/influxdb/telegraf/plugins/processors"
 )
 
 var telegrafCmpOptions = cmp.Options{
@@ -22,7 +24,8 @@ var telegrafCmpOptions = cmp.Options{
 		inputs.File{},
 		outputs.File{},
 		outputs.InfluxDBV2{},
-		unsupportedPlugin{},
+		processors.Dedup{},
+		aggregators.MinMax{},
 	),
 	cmp.Transformer("Sort", func(in []*TelegrafConfig) []*TelegrafConfig {
 		out := append([]*TelegrafConfig(nil), in...)
@@ -46,33 +49,13 @@ func (u *unsupportedPluginType) PluginName() string {
 }
 
 func (u *unsupportedPluginType) Type() plugins.Type {
-	return plugins.Aggregator
+	return plugins.Type("bad")
 }
 
 func (u *unsupportedPluginType) UnmarshalTOML(data interface{}) error {
 	return nil
 }
 
-type unsupportedPlugin struct {
-	Field string `json:"field"`
-}
-
-func (u *unsupportedPlugin) TOML() string {
-	return ""
-}
-
-func (u *unsupportedPlugin) PluginName() string {
-	return "kafka"
-}
-
-func (u *unsupportedPlugin) Type() plugins.Type {
-	return plugins.Output
-}
-
-func (u *unsupportedPlugin) UnmarshalTOML(data interface{}) error {
-	return nil
-}
-
 func TestTelegrafConfigJSONDecodeWithoutID(t *testing.T) {
 	s := `{
 		"name": "config 2",
@@ -207,29 +190,44 @@ func TestTelegrafConfigJSON(t *testing.T) {
 			},
 			err: &Error{
 				Code: EInvalid,
-				Msg:  fmt.Sprintf(ErrUnsupportTelegrafPluginType, "aggregator"),
+				Msg:  fmt.Sprintf(ErrUnsupportTelegrafPluginType, "bad"),
 				Op:   "unmarshal telegraf config raw plugin",
 			},
 		},
 		{
-			name: "unsupported plugin",
+			name: "processors, aggregators and raw plugins",
 			cfg: &TelegrafConfig{
 				ID:             *id1,
 				OrganizationID: *id2,
 				Name:           "n1",
+				Agent: TelegrafAgentConfig{
+					Interval:        4000,
+					MetricBatchSize: 5000,
+					FlushInterval:   30000,
+					FlushJitter:     5000,
+					Precision:       "1s",
+				},
 				Plugins: []TelegrafPlugin{
 					{
-						Config: &unsupportedPlugin{
-							Field: "f2",
+						Config: &processors.Dedup{DedupInterval: "60s"},
+					},
+					{
+						Config: &aggregators.MinMax{Period: "10s", DropOriginal: true},
+					},
+					{
+						Comment: "kafka",
+						Config: &plugins.Raw{
+							PluginType: plugins.Output,
+							Name:       "kafka",
+							Config: map[string]interface{}{
+								"brokers":   []interface{}{"localhost:9092"},
+								"max_retry": int64(3),
+								"ratio":     0.5,
+							},
 						},
 					},
 				},
 			},
-			err: &Error{
-				Code: EInvalid,
-				Msg:  fmt.Sprintf(ErrUnsupportTelegrafPluginName, "kafka", plugins.Output),
-				Op:   "unmarshal telegraf config raw plugin",
-			},
 		},
 	}
 	for _, c := range cases {
@@ -399,3 +397,83 @@ func TestTOML(t *testing.T) {
 		t.Fatalf("telegraf toml parsing issue, want %q, got %q", tc, tcr)
 	}
 }
+
+func TestTOMLRawPlugins(t *testing.T) {
+	config := `[agent]
+  interval = "10s"
+  metric_batch_size = 5000
+  metric_buffer_limit = 20000
+  collection_jitter = "1s"
+  flush_interval = "20s"
+  flush_jitter = "2s"
+  precision = "1ms"
+[[inputs.kafka_consumer]]
+  brokers = ["localhost:9092"]
+  max_message_len = 1000000
+  topics = ["telegraf"]
+  [inputs.kafka_consumer.tags]
+    source = "kafka"
+[[processors.dedup]]
+  dedup_interval = "60s"
+[[processors.rename]]
+  [[processors.rename.replace]]
+    dest = "host_name"
+    tag = "host"
+[[aggregators.basicstats]]
+  period = "1m0s"
+  drop_original = false
+  stats = ["mean"]
+`
+	tc := new(TelegrafConfig)
+	if err := toml.Unmarshal([]byte(config), tc); err != nil {
+		t.Fatalf("telegraf toml parsing issue %s", err.Error())
+	}
+
+	wantAgent := TelegrafAgentConfig{
+		Interval:          10000,
+		MetricBatchSize:   5000,
+		MetricBufferLimit: 20000,
+		CollectionJitter:  1000,
+		FlushInterval:     20000,
+		FlushJitter:       2000,
+		Precision:         "1ms",
+	}
+	if tc.Agent != wantAgent {
+		t.Fatalf("unexpected agent settings, got %+v, want %+v", tc.Agent, wantAgent)
+	}
+
+	types := map[string]plugins.Config{}
+	for _, p := range tc.Plugins {
+		types[p.Config.PluginName()] = p.Config
+	}
+	if _, ok := types["kafka_consumer"].(*plugins.Raw); !ok {
+		t.Errorf("expected plugins without a config type to decode raw, got %T", types["kafka_consumer"])
+	}
+	if _, ok := types["rename"].(*plugins.Raw); !ok {
+		t.Errorf("expected plugins without a config type to decode raw, got %T", types["rename"])
+	}
+	if _, ok := types["dedup"].(*processors.Dedup); !ok {
+		t.Errorf("expected the dedup processor config, got %T", types["dedup"])
+	}
+	if _, ok := types["basicstats"].(*aggregators.BasicStats); !ok {
+		t.Errorf("expected the basicstats aggregator config, got %T", types["basicstats"])
+	}
+
+	// Encoding and decoding again changes nothing.
+	again := new(TelegrafConfig)
+	if err := toml.Unmarshal([]byte(tc.TOML()), again); err != nil {
+		t.Fatalf("telegraf toml parsing issue %s\n%s", err.Error(), tc.TOML())
+	}
+	sortPlugins := cmp.Transformer("SortPlugins", func(in []TelegrafPlugin) []TelegrafPlugin {
+		out := append([]TelegrafPlugin(nil), in...)
+		sort.Slice(out, func(i, j int) bool {
+			return out[i].Config.PluginName() < out[j].Config.PluginName()
+		})
+		return out
+	})
+	opts := append(cmp.Options{sortPlugins}, telegrafCmpOptions...)
+	opts = append(opts, cmpopts.IgnoreUnexported(aggregators.BasicStats{}))
+	if diff := cmp.Diff(again, tc, opts...); diff != "" {
+		t.Errorf("telegraf config changed by a TOML round trip -got/+want\ndiff %s", diff)
+	}
+}
@@ -0,0 +1,298 @@
+package influxdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// TemplateVersion is the version of the template format written by ExportTemplate.
+const TemplateVersion = "1"
+
+// ops for templates.
+const (
+	OpExportTemplate = "ExportTemplate"
+	OpApplyTemplate  = "ApplyTemplate"
+)
+
+// TemplateService exports the configuration of an organization as a template
+// and applies templates to organizations.
+type TemplateService interface {
+	// ExportTemplate returns a template describing the resources of the organization orgID.
+	ExportTemplate(ctx context.Context, orgID ID) (*Template, error)
+
+	// ApplyTemplate creates or updates the resources of the organization orgID
+	// so that they match t and returns the changes made. Resources missing from
+	// t are left untouched, so applying a template twice changes nothing.
+	ApplyTemplate(ctx context.Context, orgID ID, t *Template, opts TemplateApplyOptions) ([]TemplateChange, error)
+}
+
+// TemplateApplyOptions configure how a template is applied.
+type TemplateApplyOptions struct {
+	// DryRun returns the changes applying the template would make without making them.
+	DryRun bool
+}
+
+// Template is a declarative description of the resources of an organization.
+// Resources are identified by name, so that a template exported from one
+// organization can be applied to another.
+type Template struct {
+	Version    string              `json:"version"`
+	Labels     []TemplateLabel     `json:"labels,omitempty"`
+	Buckets    []TemplateBucket    `json:"buckets,omitempty"`
+	Macros     []TemplateMacro     `json:"macros,omitempty"`
+	Dashboards []TemplateDashboard `json:"dashboards,omitempty"`
+	Tasks      []TemplateTask      `json:"tasks,omitempty"`
+	Telegrafs  []TemplateTelegraf  `json:"telegrafs,omitempty"`
+	Scrapers   []TemplateScraper   `json:"scrapers,omitempty"`
+}
+
+// Valid returns an error if the template cannot be applied.
+func (t *Template) Valid() error {
+	if t.Version != TemplateVersion {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("unsupported template version %q", t.Version),
+		}
+	}
+
+	labels := map[string]bool{}
+	for _, l := range t.Labels {
+		labels[l.Name] = true
+	}
+
+	names := map[string]bool{}
+	check := func(kind, name string, ls []string) error {
+		if name == "" {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("%s name is required", kind),
+			}
+		}
+		if names[kind+"/"+name] {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("%s %q is declared more than once", kind, name),
+			}
+		}
+		names[kind+"/"+name] = true
+
+		for _, l := range ls {
+			if !labels[l] {
+				return &Error{
+					Code: EInvalid,
+					Msg:  fmt.Sprintf("%s %q refers to undeclared label %q", kind, name, l),
+				}
+			}
+		}
+		return nil
+	}
+
+	for _, l := range t.Labels {
+		if err := check(TemplateKindLabel, l.Name, nil); err != nil {
+			return err
+		}
+	}
+	for _, b := range t.Buckets {
+		if err := check(TemplateKindBucket, b.Name, b.Labels); err != nil {
+			return err
+		}
+		if _, err := b.Retention(); err != nil {
+			return err
+		}
+	}
+	for _, m := range t.Macros {
+		if err := check(TemplateKindMacro, m.Name, m.Labels); err != nil {
+			return err
+		}
+	}
+	for _, d := range t.Dashboards {
+		if err := check(TemplateKindDashboard, d.Name, d.Labels); err != nil {
+			return err
+		}
+	}
+	for _, tk := range t.Tasks {
+		if err := check(TemplateKindTask, tk.Name, tk.Labels); err != nil {
+			return err
+		}
+	}
+	for _, tc := range t.Telegrafs {
+		if err := check(TemplateKindTelegraf, tc.Name, tc.Labels); err != nil {
+			return err
+		}
+	}
+	for _, s := range t.Scrapers {
+		if err := check(TemplateKindScraper, s.Name, nil); err != nil {
+			return err
+		}
+		if !names[TemplateKindBucket+"/"+s.Bucket] {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("scraper %q refers to undeclared bucket %q", s.Name, s.Bucket),
+			}
+		}
+	}
+
+	return nil
+}
+
+// Kinds of template resources.
+const (
+	TemplateKindLabel     = "label"
+	TemplateKindBucket    = "bucket"
+	TemplateKindMacro     = "macro"
+	TemplateKindDashboard = "dashboard"
+	TemplateKindTask      = "task"
+	TemplateKindTelegraf  = "telegraf"
+	TemplateKindScraper   = "scraper"
+)
+
+// TemplateLabel is a label of a template.
+type TemplateLabel struct {
+	Name       string            `json:"name"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+// TemplateBucket is a bucket of a template.
+type TemplateBucket struct {
+	Name string `json:"name"`
+	// RetentionPeriod is a duration such as "720h"; empty means infinite retention.
+	RetentionPeriod string   `json:"retentionPeriod,omitempty"`
+	Labels          []string `json:"labels,omitempty"`
+}
+
+// Retention returns the retention period of the bucket.
+func (b TemplateBucket) Retention() (time.Duration, error) {
+	if b.RetentionPeriod == "" {
+		return 0, nil
+	}
+
+	d, err := time.ParseDuration(b.RetentionPeriod)
+	if err != nil {
+		return 0, &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("bucket %q has invalid retention period %q", b.Name, b.RetentionPeriod),
+			Err:  err,
+		}
+	}
+	return d, nil
+}
+
+// TemplateMacro is a macro of a template.
+type TemplateMacro struct {
+	Name      string          `json:"name"`
+	Selected  []string        `json:"selected,omitempty"`
+	Arguments *MacroArguments `json:"arguments"`
+	Labels    []string        `json:"labels,omitempty"`
+}
+
+// TemplateDashboard is a dashboard of a template along with the views of its cells.
+type TemplateDashboard struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Cells       []TemplateCell `json:"cells,omitempty"`
+	Labels      []string       `json:"labels,omitempty"`
+}
+
+// TemplateCell is a cell of a template dashboard.
+type TemplateCell struct {
+	X    int32 `json:"x"`
+	Y    int32 `json:"y"`
+	W    int32 `json:"w"`
+	H    int32 `json:"h"`
+	View *View `json:"view,omitempty"`
+}
+
+// TemplateTask is a task of a template. Name must match the name option of Flux.
+type TemplateTask struct {
+	Name   string   `json:"name"`
+	Flux   string   `json:"flux"`
+	Status string   `json:"status,omitempty"`
+	Labels []string `json:"labels,omitempty"`
+}
+
+// TemplateTelegraf is a telegraf config of a template.
+type TemplateTelegraf struct {
+	Name    string              `json:"name"`
+	Agent   TelegrafAgentConfig `json:"agent"`
+	Plugins []TelegrafPlugin    `json:"plugins"`
+	Labels  []string            `json:"labels,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (t TemplateTelegraf) MarshalJSON() ([]byte, error) {
+	ps := make([]telegrafPluginEncode, len(t.Plugins))
+	for i, p := range t.Plugins {
+		ps[i] = telegrafPluginEncode{
+			Name:    p.Config.PluginName(),
+			Type:    p.Config.Type(),
+			Comment: p.Comment,
+			Config:  p.Config,
+		}
+	}
+
+	return json.Marshal(struct {
+		Name    string                 `json:"name"`
+		Agent   TelegrafAgentConfig    `json:"agent"`
+		Plugins []telegrafPluginEncode `json:"plugins"`
+		Labels  []string               `json:"labels,omitempty"`
+	}{
+		Name:    t.Name,
+		Agent:   t.Agent,
+		Plugins: ps,
+		Labels:  t.Labels,
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (t *TemplateTelegraf) UnmarshalJSON(b []byte) error {
+	var td struct {
+		Name    string                 `json:"name"`
+		Agent   TelegrafAgentConfig    `json:"agent"`
+		Plugins []telegrafPluginDecode `json:"plugins"`
+		Labels  []string               `json:"labels,omitempty"`
+	}
+	if err := json.Unmarshal(b, &td); err != nil {
+		return err
+	}
+
+	tc := &TelegrafConfig{
+		Plugins: make([]TelegrafPlugin, len(td.Plugins)),
+	}
+	if err := decodePluginRaw(&telegrafConfigDecode{Plugins: td.Plugins}, tc); err != nil {
+		return err
+	}
+
+	*t = TemplateTelegraf{
+		Name:    td.Name,
+		Agent:   td.Agent,
+		Plugins: tc.Plugins,
+		Labels:  td.Labels,
+	}
+	return nil
+}
+
+// TemplateScraper is a scraper target of a template writing to the template bucket named Bucket.
+type TemplateScraper struct {
+	Name   string      `json:"name"`
+	Type   ScraperType `json:"type"`
+	URL    string      `json:"url"`
+	Bucket string      `json:"bucket"`
+}
+
+// Actions of template changes.
+const (
+	TemplateActionCreate    = "create"
+	TemplateActionUpdate    = "update"
+	TemplateActionUnchanged = "unchanged"
+)
+
+// TemplateChange is a change made, or to be made in a dry run, by applying a template.
+type TemplateChange struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Action string `json:"action"`
+	// ID is the ID of the resource, unknown for resources to be created in a dry run.
+	ID ID `json:"id,omitempty"`
+}
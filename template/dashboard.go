@@ -0,0 +1,224 @@
+package template
+
+import (
+	"context"
+	"sort"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/macro"
+)
+
+var _ platform.DashboardExportService = (*Service)(nil)
+
+// ExportDashboard returns the dashboard id with the views of its cells, the
+// macros of its organization referenced by the queries of the views, and its labels.
+func (s *Service) ExportDashboard(ctx context.Context, id platform.ID) (*platform.DashboardExport, error) {
+	e, err := s.exportDashboard(ctx, id)
+	if err != nil {
+		return nil, &platform.Error{
+			Op:  platform.OpExportDashboard,
+			Err: err,
+		}
+	}
+	return e, nil
+}
+
+func (s *Service) exportDashboard(ctx context.Context, id platform.ID) (*platform.DashboardExport, error) {
+	d, err := s.DashboardService.FindDashboardByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &platform.DashboardExport{
+		ProtoDashboard: platform.ProtoDashboard{
+			Dashboard: *d,
+			Views:     make(map[string]platform.View, len(d.Cells)),
+		},
+	}
+
+	referenced := map[string]bool{}
+	for _, c := range d.Cells {
+		v, err := s.DashboardService.GetDashboardCellView(ctx, d.ID, c.ID)
+		if err != nil {
+			return nil, err
+		}
+		e.Views[c.ID.String()] = *v
+
+		for _, q := range viewQueries(v.Properties) {
+			for _, name := range macro.References(q.Type, q.Text) {
+				referenced[name] = true
+			}
+		}
+	}
+
+	if len(referenced) > 0 {
+		ms, err := s.MacroService.FindMacros(ctx, platform.MacroFilter{OrganizationID: &d.OrganizationID})
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range ms {
+			if referenced[m.Name] {
+				e.Macros = append(e.Macros, m)
+			}
+		}
+		sort.Slice(e.Macros, func(i, j int) bool { return e.Macros[i].Name < e.Macros[j].Name })
+	}
+
+	ls, err := s.LabelService.FindResourceLabels(ctx, platform.LabelMappingFilter{
+		ResourceID:   d.ID,
+		ResourceType: platform.DashboardsResourceType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(ls, func(i, j int) bool { return ls[i].Name < ls[j].Name })
+	e.Labels = ls
+
+	return e, nil
+}
+
+// viewQueries returns the queries of the properties of a view.
+func viewQueries(p platform.ViewProperties) []platform.DashboardQuery {
+	switch p := p.(type) {
+	case platform.XYViewProperties:
+		return p.Queries
+	case platform.LinePlusSingleStatProperties:
+		return p.Queries
+	case platform.SingleStatViewProperties:
+		return p.Queries
+	case platform.GaugeViewProperties:
+		return p.Queries
+	case platform.TableViewProperties:
+		return p.Queries
+	}
+	return nil
+}
+
+// ImportDashboard creates the dashboard of e in the organization orgID, along
+// with its cells and views. The macros of e missing from the organization and
+// the labels of e that do not exist are created; existing ones are reused as is.
+func (s *Service) ImportDashboard(ctx context.Context, orgID platform.ID, e *platform.DashboardExport) (*platform.Dashboard, error) {
+	if err := e.Valid(); err != nil {
+		return nil, &platform.Error{
+			Op:  platform.OpImportDashboard,
+			Err: err,
+		}
+	}
+
+	d, err := s.importDashboard(ctx, orgID, e)
+	if err != nil {
+		return nil, &platform.Error{
+			Op:  platform.OpImportDashboard,
+			Err: err,
+		}
+	}
+	return d, nil
+}
+
+func (s *Service) importDashboard(ctx context.Context, orgID platform.ID, e *platform.DashboardExport) (*platform.Dashboard, error) {
+	if err := s.importMacros(ctx, orgID, e.Macros); err != nil {
+		return nil, err
+	}
+
+	d := &platform.Dashboard{
+		OrganizationID: orgID,
+		Name:           e.Dashboard.Name,
+		Description:    e.Dashboard.Description,
+	}
+	if err := s.DashboardService.CreateDashboard(ctx, d); err != nil {
+		return nil, err
+	}
+
+	for _, ec := range e.Dashboard.Cells {
+		if ec == nil {
+			continue
+		}
+		c := &platform.Cell{
+			X: ec.X,
+			Y: ec.Y,
+			W: ec.W,
+			H: ec.H,
+		}
+		var opts platform.AddDashboardCellOptions
+		if v, ok := e.Views[ec.ID.String()]; ok {
+			// The view takes the ID of the new cell.
+			v.ID = 0
+			opts.View = &v
+		}
+		if err := s.DashboardService.AddDashboardCell(ctx, d.ID, c, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.importLabels(ctx, d.ID, e.Labels); err != nil {
+		return nil, err
+	}
+
+	return s.DashboardService.FindDashboardByID(ctx, d.ID)
+}
+
+// importMacros creates the macros of ms missing from the organization orgID.
+func (s *Service) importMacros(ctx context.Context, orgID platform.ID, ms []*platform.Macro) error {
+	if len(ms) == 0 {
+		return nil
+	}
+
+	existing, err := s.MacroService.FindMacros(ctx, platform.MacroFilter{OrganizationID: &orgID})
+	if err != nil {
+		return err
+	}
+	names := map[string]bool{}
+	for _, m := range existing {
+		names[m.Name] = true
+	}
+
+	for _, em := range ms {
+		if names[em.Name] {
+			continue
+		}
+		m := &platform.Macro{
+			OrganizationID: orgID,
+			Name:           em.Name,
+			Selected:       em.Selected,
+			Arguments:      em.Arguments,
+		}
+		if err := s.MacroService.CreateMacro(ctx, m); err != nil {
+			return err
+		}
+		names[m.Name] = true
+	}
+	return nil
+}
+
+// importLabels maps the labels named as those of ls to the dashboard id,
+// creating the labels that do not exist.
+func (s *Service) importLabels(ctx context.Context, id platform.ID, ls []*platform.Label) error {
+	for _, el := range ls {
+		found, err := s.LabelService.FindLabels(ctx, platform.LabelFilter{Name: el.Name})
+		if err != nil {
+			return err
+		}
+
+		var l *platform.Label
+		if len(found) > 0 {
+			l = found[0]
+		} else {
+			l = &platform.Label{
+				Name:       el.Name,
+				Properties: el.Properties,
+			}
+			if err := s.LabelService.CreateLabel(ctx, l); err != nil {
+				return err
+			}
+		}
+
+		if err := s.LabelService.CreateLabelMapping(ctx, &platform.LabelMapping{
+			LabelID:      l.ID,
+			ResourceID:   id,
+			ResourceType: platform.DashboardsResourceType,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
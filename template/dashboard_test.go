@@ -0,0 +1,139 @@
+package template_test
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+func TestService_ExportImportDashboard(t *testing.T) {
+	ctx := context.Background()
+	svc, s := newService()
+
+	src, dst := &platform.Organization{Name: "src"}, &platform.Organization{Name: "dst"}
+	for _, o := range []*platform.Organization{src, dst} {
+		if err := s.CreateOrganization(ctx, o); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, m := range []*platform.Macro{
+		{
+			OrganizationID: src.ID,
+			Name:           "host",
+			Selected:       []string{"a"},
+			Arguments:      &platform.MacroArguments{Type: "constant", Values: platform.MacroConstantValues{"a", "b"}},
+		},
+		{
+			OrganizationID: src.ID,
+			Name:           "unused",
+			Selected:       []string{"x"},
+			Arguments:      &platform.MacroArguments{Type: "constant", Values: platform.MacroConstantValues{"x"}},
+		},
+	} {
+		if err := s.CreateMacro(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	d := &platform.Dashboard{OrganizationID: src.ID, Name: "hosts", Description: "host metrics"}
+	if err := s.CreateDashboard(ctx, d); err != nil {
+		t.Fatal(err)
+	}
+	view := &platform.View{
+		ViewContents: platform.ViewContents{Name: "cpu"},
+		Properties: platform.XYViewProperties{
+			Type: "xy",
+			Queries: []platform.DashboardQuery{
+				{Type: "flux", Text: `from(bucket: "b") |> filter(fn: (r) => r.host == v.host)`},
+			},
+		},
+	}
+	if err := s.AddDashboardCell(ctx, d.ID, &platform.Cell{X: 1, Y: 2, W: 3, H: 4}, platform.AddDashboardCellOptions{View: view}); err != nil {
+		t.Fatal(err)
+	}
+
+	l := &platform.Label{Name: "monitoring", Properties: map[string]string{"color": "ff0000"}}
+	if err := s.CreateLabel(ctx, l); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CreateLabelMapping(ctx, &platform.LabelMapping{LabelID: l.ID, ResourceID: d.ID, ResourceType: platform.DashboardsResourceType}); err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := svc.ExportDashboard(ctx, d.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(e.Macros) != 1 || e.Macros[0].Name != "host" {
+		t.Fatalf("expected the referenced macro only, got %+v", e.Macros)
+	}
+	if len(e.Labels) != 1 || e.Labels[0].Name != "monitoring" {
+		t.Fatalf("expected the dashboard label, got %+v", e.Labels)
+	}
+	if len(e.Views) != 1 {
+		t.Fatalf("expected one view, got %d", len(e.Views))
+	}
+
+	// Exports are carried as JSON, so the import reads one back.
+	b, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var imported platform.DashboardExport
+	if err := json.Unmarshal(b, &imported); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		nd, err := svc.ImportDashboard(ctx, dst.ID, &imported)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if nd.ID == d.ID || nd.OrganizationID != dst.ID || nd.Name != "hosts" || nd.Description != "host metrics" {
+			t.Fatalf("unexpected imported dashboard %+v", nd)
+		}
+		if len(nd.Cells) != 1 {
+			t.Fatalf("expected one cell, got %d", len(nd.Cells))
+		}
+		c := nd.Cells[0]
+		if c.ID == d.Cells[0].ID || c.X != 1 || c.Y != 2 || c.W != 3 || c.H != 4 {
+			t.Fatalf("unexpected imported cell %+v", c)
+		}
+		v, err := s.GetDashboardCellView(ctx, nd.ID, c.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.Name != "cpu" || !reflect.DeepEqual(v.Properties, view.Properties) {
+			t.Fatalf("unexpected imported view %+v", v)
+		}
+
+		ls, err := s.FindResourceLabels(ctx, platform.LabelMappingFilter{ResourceID: nd.ID, ResourceType: platform.DashboardsResourceType})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ls) != 1 || ls[0].ID != l.ID {
+			t.Fatalf("expected the existing label to be reused, got %+v", ls)
+		}
+	}
+
+	ms, err := s.FindMacros(ctx, platform.MacroFilter{OrganizationID: &dst.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ms) != 1 || ms[0].Name != "host" || !reflect.DeepEqual(ms[0].Selected, []string{"a"}) {
+		t.Fatalf("expected the referenced macro to be created once, got %+v", ms)
+	}
+}
+
+func TestService_ImportDashboard_Invalid(t *testing.T) {
+	svc, _ := newService()
+
+	_, err := svc.ImportDashboard(context.Background(), platform.ID(1), &platform.DashboardExport{})
+	if platform.ErrorCode(err) != platform.EInvalid {
+		t.Fatalf("expected an invalid error, got %v", err)
+	}
+}
@@ -0,0 +1,821 @@
+// Package template exports the configuration of organizations as templates
+// and applies templates to organizations.
+package template
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	platform "github.com/influxdata/influxdb"
+	platcontext "github.com/influxdata/influxdb/context"
+)
+
+var _ platform.TemplateService = (*Service)(nil)
+
+// Service implements platform.TemplateService on top of the services managing
+// each kind of resource. Permissions are those of the services, so the
+// services should authorize actions against the authorizer on context.
+type Service struct {
+	BucketService             platform.BucketService
+	LabelService              platform.LabelService
+	MacroService              platform.MacroService
+	DashboardService          platform.DashboardService
+	TaskService               platform.TaskService
+	TelegrafService           platform.TelegrafConfigStore
+	ScraperTargetStoreService platform.ScraperTargetStoreService
+}
+
+// ExportTemplate returns a template describing the resources of the organization orgID.
+// Labels are global, so only the labels of exported resources are exported.
+func (s *Service) ExportTemplate(ctx context.Context, orgID platform.ID) (*platform.Template, error) {
+	e := &exporter{
+		Service: s,
+		t:       &platform.Template{Version: platform.TemplateVersion},
+		labels:  map[platform.ID]*platform.Label{},
+	}
+
+	for _, export := range []func(context.Context, platform.ID) error{
+		e.exportBuckets,
+		e.exportMacros,
+		e.exportDashboards,
+		e.exportTasks,
+		e.exportTelegrafs,
+		e.exportScrapers,
+	} {
+		if err := export(ctx, orgID); err != nil {
+			return nil, &platform.Error{
+				Op:  platform.OpExportTemplate,
+				Err: err,
+			}
+		}
+	}
+
+	for _, l := range e.labels {
+		e.t.Labels = append(e.t.Labels, platform.TemplateLabel{
+			Name:       l.Name,
+			Properties: l.Properties,
+		})
+	}
+	sort.Slice(e.t.Labels, func(i, j int) bool { return e.t.Labels[i].Name < e.t.Labels[j].Name })
+
+	return e.t, nil
+}
+
+type exporter struct {
+	*Service
+	t       *platform.Template
+	labels  map[platform.ID]*platform.Label
+	buckets map[platform.ID]string
+}
+
+// resourceLabels returns the names of the labels of a resource and records the labels for export.
+func (e *exporter) resourceLabels(ctx context.Context, rt platform.ResourceType, id platform.ID) ([]string, error) {
+	ls, err := e.LabelService.FindResourceLabels(ctx, platform.LabelMappingFilter{
+		ResourceID:   id,
+		ResourceType: rt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, l := range ls {
+		e.labels[l.ID] = l
+		names = append(names, l.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (e *exporter) exportBuckets(ctx context.Context, orgID platform.ID) error {
+	bs, _, err := e.BucketService.FindBuckets(ctx, platform.BucketFilter{OrganizationID: &orgID})
+	if err != nil {
+		return err
+	}
+	sort.Slice(bs, func(i, j int) bool { return bs[i].Name < bs[j].Name })
+
+	e.buckets = map[platform.ID]string{}
+	for _, b := range bs {
+		e.buckets[b.ID] = b.Name
+
+		labels, err := e.resourceLabels(ctx, platform.BucketsResourceType, b.ID)
+		if err != nil {
+			return err
+		}
+
+		tb := platform.TemplateBucket{
+			Name:   b.Name,
+			Labels: labels,
+		}
+		if b.RetentionPeriod > 0 {
+			tb.RetentionPeriod = b.RetentionPeriod.String()
+		}
+		e.t.Buckets = append(e.t.Buckets, tb)
+	}
+	return nil
+}
+
+func (e *exporter) exportMacros(ctx context.Context, orgID platform.ID) error {
+	ms, err := e.MacroService.FindMacros(ctx, platform.MacroFilter{OrganizationID: &orgID})
+	if err != nil {
+		return err
+	}
+	sort.Slice(ms, func(i, j int) bool { return ms[i].Name < ms[j].Name })
+
+	for _, m := range ms {
+		labels, err := e.resourceLabels(ctx, platform.MacrosResourceType, m.ID)
+		if err != nil {
+			return err
+		}
+
+		e.t.Macros = append(e.t.Macros, platform.TemplateMacro{
+			Name:      m.Name,
+			Selected:  m.Selected,
+			Arguments: m.Arguments,
+			Labels:    labels,
+		})
+	}
+	return nil
+}
+
+func (e *exporter) exportDashboards(ctx context.Context, orgID platform.ID) error {
+	ds, _, err := e.DashboardService.FindDashboards(ctx, platform.DashboardFilter{OrganizationID: &orgID}, platform.FindOptions{})
+	if err != nil {
+		return err
+	}
+	sort.Slice(ds, func(i, j int) bool { return ds[i].Name < ds[j].Name })
+
+	for _, d := range ds {
+		labels, err := e.resourceLabels(ctx, platform.DashboardsResourceType, d.ID)
+		if err != nil {
+			return err
+		}
+
+		cells, err := dashboardCells(ctx, e.DashboardService, d)
+		if err != nil {
+			return err
+		}
+
+		e.t.Dashboards = append(e.t.Dashboards, platform.TemplateDashboard{
+			Name:        d.Name,
+			Description: d.Description,
+			Cells:       cells,
+			Labels:      labels,
+		})
+	}
+	return nil
+}
+
+// dashboardCells returns the cells of d in template form, with their views.
+func dashboardCells(ctx context.Context, s platform.DashboardService, d *platform.Dashboard) ([]platform.TemplateCell, error) {
+	cells := make([]platform.TemplateCell, 0, len(d.Cells))
+	for _, c := range d.Cells {
+		v, err := s.GetDashboardCellView(ctx, d.ID, c.ID)
+		if err != nil {
+			return nil, err
+		}
+		// Views are identified by their cell, so their IDs are left out.
+		view := *v
+		view.ID = 0
+
+		cells = append(cells, platform.TemplateCell{
+			X:    c.X,
+			Y:    c.Y,
+			W:    c.W,
+			H:    c.H,
+			View: &view,
+		})
+	}
+	return cells, nil
+}
+
+func (e *exporter) exportTasks(ctx context.Context, orgID platform.ID) error {
+	ts, err := findTasks(ctx, e.TaskService, orgID)
+	if err != nil {
+		return err
+	}
+	sort.Slice(ts, func(i, j int) bool { return ts[i].Name < ts[j].Name })
+
+	for _, t := range ts {
+		labels, err := e.resourceLabels(ctx, platform.TasksResourceType, t.ID)
+		if err != nil {
+			return err
+		}
+
+		e.t.Tasks = append(e.t.Tasks, platform.TemplateTask{
+			Name:   t.Name,
+			Flux:   t.Flux,
+			Status: t.Status,
+			Labels: labels,
+		})
+	}
+	return nil
+}
+
+// findTasks returns every task of orgID, reading the task store a page at a time.
+func findTasks(ctx context.Context, s platform.TaskService, orgID platform.ID) ([]*platform.Task, error) {
+	var tasks []*platform.Task
+	filter := platform.TaskFilter{
+		Organization: &orgID,
+		Limit:        platform.TaskMaxPageSize,
+	}
+	for {
+		ts, _, err := s.FindTasks(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, ts...)
+		if len(ts) < filter.Limit {
+			return tasks, nil
+		}
+		filter.After = &ts[len(ts)-1].ID
+	}
+}
+
+func (e *exporter) exportTelegrafs(ctx context.Context, orgID platform.ID) error {
+	tcs, _, err := e.TelegrafService.FindTelegrafConfigs(ctx, platform.TelegrafConfigFilter{OrganizationID: &orgID})
+	if err != nil {
+		return err
+	}
+	sort.Slice(tcs, func(i, j int) bool { return tcs[i].Name < tcs[j].Name })
+
+	for _, tc := range tcs {
+		labels, err := e.resourceLabels(ctx, platform.TelegrafsResourceType, tc.ID)
+		if err != nil {
+			return err
+		}
+
+		e.t.Telegrafs = append(e.t.Telegrafs, platform.TemplateTelegraf{
+			Name:    tc.Name,
+			Agent:   tc.Agent,
+			Plugins: tc.Plugins,
+			Labels:  labels,
+		})
+	}
+	return nil
+}
+
+func (e *exporter) exportScrapers(ctx context.Context, orgID platform.ID) error {
+	targets, err := e.ScraperTargetStoreService.ListTargets(ctx)
+	if err != nil {
+		return err
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].Name < targets[j].Name })
+
+	for _, st := range targets {
+		if st.OrgID != orgID {
+			continue
+		}
+
+		e.t.Scrapers = append(e.t.Scrapers, platform.TemplateScraper{
+			Name:   st.Name,
+			Type:   st.Type,
+			URL:    st.URL,
+			Bucket: e.buckets[st.BucketID],
+		})
+	}
+	return nil
+}
+
+// ApplyTemplate creates or updates the resources of the organization orgID so
+// that they match t. Resources are matched by name and labels are added to
+// resources, never removed.
+func (s *Service) ApplyTemplate(ctx context.Context, orgID platform.ID, t *platform.Template, opts platform.TemplateApplyOptions) ([]platform.TemplateChange, error) {
+	if err := t.Valid(); err != nil {
+		return nil, &platform.Error{
+			Op:  platform.OpApplyTemplate,
+			Err: err,
+		}
+	}
+
+	a := &applier{
+		Service: s,
+		orgID:   orgID,
+		dryRun:  opts.DryRun,
+		labels:  map[string]platform.ID{},
+		buckets: map[string]platform.ID{},
+	}
+
+	if !a.dryRun {
+		auth, err := platcontext.GetAuthorizer(ctx)
+		if err != nil {
+			return nil, err
+		}
+		a.userID = auth.GetUserID()
+	}
+
+	for _, apply := range []func(context.Context, *platform.Template) error{
+		a.applyLabels,
+		a.applyBuckets,
+		a.applyMacros,
+		a.applyDashboards,
+		a.applyTasks,
+		a.applyTelegrafs,
+		a.applyScrapers,
+	} {
+		if err := apply(ctx, t); err != nil {
+			return a.changes, &platform.Error{
+				Op:  platform.OpApplyTemplate,
+				Err: err,
+			}
+		}
+	}
+
+	return a.changes, nil
+}
+
+type applier struct {
+	*Service
+	orgID   platform.ID
+	userID  platform.ID
+	dryRun  bool
+	changes []platform.TemplateChange
+
+	// labels and buckets map names to the IDs of existing resources.
+	labels  map[string]platform.ID
+	buckets map[string]platform.ID
+}
+
+// change records the change of a resource; a resource whose labels are
+// missing counts as updated.
+func (a *applier) change(kind, name string, id platform.ID, exists, changed bool) {
+	action := platform.TemplateActionCreate
+	switch {
+	case exists && changed:
+		action = platform.TemplateActionUpdate
+	case exists:
+		action = platform.TemplateActionUnchanged
+	}
+
+	a.changes = append(a.changes, platform.TemplateChange{
+		Kind:   kind,
+		Name:   name,
+		Action: action,
+		ID:     id,
+	})
+}
+
+// missingLabels returns the IDs of the labels named names that the resource id does not carry.
+// Labels yet to be created in a dry run are counted as missing.
+func (a *applier) missingLabels(ctx context.Context, rt platform.ResourceType, id platform.ID, names []string) ([]platform.ID, bool, error) {
+	if len(names) == 0 {
+		return nil, false, nil
+	}
+
+	carried := map[platform.ID]bool{}
+	if id.Valid() {
+		ls, err := a.LabelService.FindResourceLabels(ctx, platform.LabelMappingFilter{
+			ResourceID:   id,
+			ResourceType: rt,
+		})
+		if err != nil {
+			return nil, false, err
+		}
+		for _, l := range ls {
+			carried[l.ID] = true
+		}
+	}
+
+	var missing []platform.ID
+	incomplete := false
+	for _, name := range names {
+		labelID, ok := a.labels[name]
+		if !ok {
+			incomplete = true
+			continue
+		}
+		if !carried[labelID] {
+			missing = append(missing, labelID)
+			incomplete = true
+		}
+	}
+	return missing, incomplete, nil
+}
+
+// addLabels maps the labels ids to the resource id.
+func (a *applier) addLabels(ctx context.Context, rt platform.ResourceType, id platform.ID, ids []platform.ID) error {
+	for _, labelID := range ids {
+		if err := a.LabelService.CreateLabelMapping(ctx, &platform.LabelMapping{
+			LabelID:      labelID,
+			ResourceID:   id,
+			ResourceType: rt,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *applier) applyLabels(ctx context.Context, t *platform.Template) error {
+	for _, tl := range t.Labels {
+		ls, err := a.LabelService.FindLabels(ctx, platform.LabelFilter{Name: tl.Name})
+		if err != nil {
+			return err
+		}
+
+		if len(ls) == 0 {
+			l := &platform.Label{
+				Name:       tl.Name,
+				Properties: tl.Properties,
+			}
+			if !a.dryRun {
+				if err := a.LabelService.CreateLabel(ctx, l); err != nil {
+					return err
+				}
+				a.labels[l.Name] = l.ID
+			}
+			a.change(platform.TemplateKindLabel, tl.Name, l.ID, false, true)
+			continue
+		}
+
+		l := ls[0]
+		a.labels[l.Name] = l.ID
+		changed := !equalProperties(l.Properties, tl.Properties)
+		if changed && !a.dryRun {
+			if _, err := a.LabelService.UpdateLabel(ctx, l.ID, platform.LabelUpdate{Properties: tl.Properties}); err != nil {
+				return err
+			}
+		}
+		a.change(platform.TemplateKindLabel, tl.Name, l.ID, true, changed)
+	}
+	return nil
+}
+
+func equalProperties(x, y map[string]string) bool {
+	if len(x) == 0 && len(y) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+func (a *applier) applyBuckets(ctx context.Context, t *platform.Template) error {
+	existing, _, err := a.BucketService.FindBuckets(ctx, platform.BucketFilter{OrganizationID: &a.orgID})
+	if err != nil {
+		return err
+	}
+	byName := map[string]*platform.Bucket{}
+	for _, b := range existing {
+		byName[b.Name] = b
+		a.buckets[b.Name] = b.ID
+	}
+
+	for _, tb := range t.Buckets {
+		retention, err := tb.Retention()
+		if err != nil {
+			return err
+		}
+
+		b, exists := byName[tb.Name]
+		if !exists {
+			b = &platform.Bucket{
+				OrganizationID:  a.orgID,
+				Name:            tb.Name,
+				RetentionPeriod: retention,
+			}
+			if !a.dryRun {
+				if err := a.BucketService.CreateBucket(ctx, b); err != nil {
+					return err
+				}
+				a.buckets[b.Name] = b.ID
+			}
+		}
+
+		missing, labelsChanged, err := a.missingLabels(ctx, platform.BucketsResourceType, b.ID, tb.Labels)
+		if err != nil {
+			return err
+		}
+
+		changed := exists && b.RetentionPeriod != retention
+		if changed && !a.dryRun {
+			if _, err := a.BucketService.UpdateBucket(ctx, b.ID, platform.BucketUpdate{RetentionPeriod: &retention}); err != nil {
+				return err
+			}
+		}
+		if !a.dryRun {
+			if err := a.addLabels(ctx, platform.BucketsResourceType, b.ID, missing); err != nil {
+				return err
+			}
+		}
+		a.change(platform.TemplateKindBucket, tb.Name, b.ID, exists, changed || labelsChanged)
+	}
+	return nil
+}
+
+func (a *applier) applyMacros(ctx context.Context, t *platform.Template) error {
+	existing, err := a.MacroService.FindMacros(ctx, platform.MacroFilter{OrganizationID: &a.orgID})
+	if err != nil {
+		return err
+	}
+	byName := map[string]*platform.Macro{}
+	for _, m := range existing {
+		byName[m.Name] = m
+	}
+
+	for _, tm := range t.Macros {
+		m, exists := byName[tm.Name]
+		if !exists {
+			m = &platform.Macro{
+				OrganizationID: a.orgID,
+				Name:           tm.Name,
+				Selected:       tm.Selected,
+				Arguments:      tm.Arguments,
+			}
+			if !a.dryRun {
+				if err := a.MacroService.CreateMacro(ctx, m); err != nil {
+					return err
+				}
+			}
+		}
+
+		missing, labelsChanged, err := a.missingLabels(ctx, platform.MacrosResourceType, m.ID, tm.Labels)
+		if err != nil {
+			return err
+		}
+
+		changed := false
+		if exists {
+			changed, err = differ(
+				platform.TemplateMacro{Selected: m.Selected, Arguments: m.Arguments},
+				platform.TemplateMacro{Selected: tm.Selected, Arguments: tm.Arguments},
+			)
+			if err != nil {
+				return err
+			}
+		}
+		if changed && !a.dryRun {
+			if _, err := a.MacroService.UpdateMacro(ctx, m.ID, &platform.MacroUpdate{
+				Selected:  tm.Selected,
+				Arguments: tm.Arguments,
+			}); err != nil {
+				return err
+			}
+		}
+		if !a.dryRun {
+			if err := a.addLabels(ctx, platform.MacrosResourceType, m.ID, missing); err != nil {
+				return err
+			}
+		}
+		a.change(platform.TemplateKindMacro, tm.Name, m.ID, exists, changed || labelsChanged)
+	}
+	return nil
+}
+
+// differ reports whether x and y have different JSON encodings.
+func differ(x, y interface{}) (bool, error) {
+	xb, err := json.Marshal(x)
+	if err != nil {
+		return false, err
+	}
+	yb, err := json.Marshal(y)
+	if err != nil {
+		return false, err
+	}
+	return string(xb) != string(yb), nil
+}
+
+func (a *applier) applyDashboards(ctx context.Context, t *platform.Template) error {
+	existing, _, err := a.DashboardService.FindDashboards(ctx, platform.DashboardFilter{OrganizationID: &a.orgID}, platform.FindOptions{})
+	if err != nil {
+		return err
+	}
+	byName := map[string]*platform.Dashboard{}
+	for _, d := range existing {
+		byName[d.Name] = d
+	}
+
+	for _, td := range t.Dashboards {
+		d, exists := byName[td.Name]
+		cellsChanged := !exists && len(td.Cells) > 0
+		if !exists {
+			d = &platform.Dashboard{
+				OrganizationID: a.orgID,
+				Name:           td.Name,
+				Description:    td.Description,
+			}
+			if !a.dryRun {
+				if err := a.DashboardService.CreateDashboard(ctx, d); err != nil {
+					return err
+				}
+			}
+		} else {
+			cells, err := dashboardCells(ctx, a.DashboardService, d)
+			if err != nil {
+				return err
+			}
+			if cellsChanged, err = differ(cells, td.Cells); err != nil {
+				return err
+			}
+		}
+
+		missing, labelsChanged, err := a.missingLabels(ctx, platform.DashboardsResourceType, d.ID, td.Labels)
+		if err != nil {
+			return err
+		}
+
+		descriptionChanged := exists && d.Description != td.Description
+		if !a.dryRun {
+			if descriptionChanged {
+				if _, err := a.DashboardService.UpdateDashboard(ctx, d.ID, platform.DashboardUpdate{Description: &td.Description}); err != nil {
+					return err
+				}
+			}
+			if cellsChanged {
+				if err := a.replaceCells(ctx, d, td.Cells); err != nil {
+					return err
+				}
+			}
+			if err := a.addLabels(ctx, platform.DashboardsResourceType, d.ID, missing); err != nil {
+				return err
+			}
+		}
+		a.change(platform.TemplateKindDashboard, td.Name, d.ID, exists, descriptionChanged || cellsChanged || labelsChanged)
+	}
+	return nil
+}
+
+// replaceCells replaces the cells of d, along with their views, by cells.
+func (a *applier) replaceCells(ctx context.Context, d *platform.Dashboard, cells []platform.TemplateCell) error {
+	for _, c := range d.Cells {
+		if err := a.DashboardService.RemoveDashboardCell(ctx, d.ID, c.ID); err != nil {
+			return err
+		}
+	}
+
+	for _, tc := range cells {
+		c := &platform.Cell{
+			X: tc.X,
+			Y: tc.Y,
+			W: tc.W,
+			H: tc.H,
+		}
+		var opts platform.AddDashboardCellOptions
+		if tc.View != nil {
+			// The view takes the ID of the cell, so the template is left untouched.
+			view := *tc.View
+			opts.View = &view
+		}
+		if err := a.DashboardService.AddDashboardCell(ctx, d.ID, c, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *applier) applyTasks(ctx context.Context, t *platform.Template) error {
+	existing, err := findTasks(ctx, a.TaskService, a.orgID)
+	if err != nil {
+		return err
+	}
+	byName := map[string]*platform.Task{}
+	for _, tk := range existing {
+		byName[tk.Name] = tk
+	}
+
+	for _, tt := range t.Tasks {
+		tk, exists := byName[tt.Name]
+		if !exists {
+			tk = &platform.Task{
+				OrganizationID: a.orgID,
+				Name:           tt.Name,
+				Flux:           tt.Flux,
+				Status:         tt.Status,
+				Owner:          platform.User{ID: a.userID},
+			}
+			if !a.dryRun {
+				if err := a.TaskService.CreateTask(ctx, tk); err != nil {
+					return err
+				}
+			}
+		}
+
+		missing, labelsChanged, err := a.missingLabels(ctx, platform.TasksResourceType, tk.ID, tt.Labels)
+		if err != nil {
+			return err
+		}
+
+		var upd platform.TaskUpdate
+		if exists && tk.Flux != tt.Flux {
+			upd.Flux = &tt.Flux
+		}
+		if exists && tt.Status != "" && tk.Status != tt.Status {
+			upd.Status = &tt.Status
+		}
+		changed := upd.Flux != nil || upd.Status != nil
+		if changed && !a.dryRun {
+			if _, err := a.TaskService.UpdateTask(ctx, tk.ID, upd); err != nil {
+				return err
+			}
+		}
+		if !a.dryRun {
+			if err := a.addLabels(ctx, platform.TasksResourceType, tk.ID, missing); err != nil {
+				return err
+			}
+		}
+		a.change(platform.TemplateKindTask, tt.Name, tk.ID, exists, changed || labelsChanged)
+	}
+	return nil
+}
+
+func (a *applier) applyTelegrafs(ctx context.Context, t *platform.Template) error {
+	existing, _, err := a.TelegrafService.FindTelegrafConfigs(ctx, platform.TelegrafConfigFilter{OrganizationID: &a.orgID})
+	if err != nil {
+		return err
+	}
+	byName := map[string]*platform.TelegrafConfig{}
+	for _, tc := range existing {
+		byName[tc.Name] = tc
+	}
+
+	for _, tt := range t.Telegrafs {
+		tc, exists := byName[tt.Name]
+		want := &platform.TelegrafConfig{
+			OrganizationID: a.orgID,
+			Name:           tt.Name,
+			Agent:          tt.Agent,
+			Plugins:        tt.Plugins,
+		}
+		if !exists {
+			tc = want
+			if !a.dryRun {
+				if err := a.TelegrafService.CreateTelegrafConfig(ctx, tc, a.userID); err != nil {
+					return err
+				}
+			}
+		}
+
+		missing, labelsChanged, err := a.missingLabels(ctx, platform.TelegrafsResourceType, tc.ID, tt.Labels)
+		if err != nil {
+			return err
+		}
+
+		changed := false
+		if exists {
+			changed, err = differ(
+				platform.TemplateTelegraf{Agent: tc.Agent, Plugins: tc.Plugins},
+				platform.TemplateTelegraf{Agent: tt.Agent, Plugins: tt.Plugins},
+			)
+			if err != nil {
+				return err
+			}
+		}
+		if changed && !a.dryRun {
+			want.ID = tc.ID
+			if _, err := a.TelegrafService.UpdateTelegrafConfig(ctx, tc.ID, want, a.userID); err != nil {
+				return err
+			}
+		}
+		if !a.dryRun {
+			if err := a.addLabels(ctx, platform.TelegrafsResourceType, tc.ID, missing); err != nil {
+				return err
+			}
+		}
+		a.change(platform.TemplateKindTelegraf, tt.Name, tc.ID, exists, changed || labelsChanged)
+	}
+	return nil
+}
+
+func (a *applier) applyScrapers(ctx context.Context, t *platform.Template) error {
+	targets, err := a.ScraperTargetStoreService.ListTargets(ctx)
+	if err != nil {
+		return err
+	}
+	byName := map[string]platform.ScraperTarget{}
+	for _, st := range targets {
+		if st.OrgID == a.orgID {
+			byName[st.Name] = st
+		}
+	}
+
+	for _, ts := range t.Scrapers {
+		want := platform.ScraperTarget{
+			Name:     ts.Name,
+			Type:     ts.Type,
+			URL:      ts.URL,
+			OrgID:    a.orgID,
+			BucketID: a.buckets[ts.Bucket],
+		}
+
+		st, exists := byName[ts.Name]
+		if !exists {
+			if !a.dryRun {
+				if err := a.ScraperTargetStoreService.AddTarget(ctx, &want, a.userID); err != nil {
+					return err
+				}
+			}
+			a.change(platform.TemplateKindScraper, ts.Name, want.ID, false, true)
+			continue
+		}
+
+		want.ID = st.ID
+		changed := st.Type != want.Type || st.URL != want.URL || st.BucketID != want.BucketID
+		if changed && !a.dryRun {
+			if _, err := a.ScraperTargetStoreService.UpdateTarget(ctx, &want, a.userID); err != nil {
+				return err
+			}
+		}
+		a.change(platform.TemplateKindScraper, ts.Name, st.ID, true, changed)
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package template_test
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	platcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/telegraf/plugins/inputs"
+	"github.com/influxdata/influxdb/template"
+)
+
+// newTaskService returns a task service keeping tasks in memory.
+func newTaskService() *mock.TaskService {
+	tasks := map[platform.ID]*platform.Task{}
+	next := platform.ID(1)
+	return &mock.TaskService{
+		FindTasksFn: func(ctx context.Context, filter platform.TaskFilter) ([]*platform.Task, int, error) {
+			var ts []*platform.Task
+			for id := platform.ID(1); id < next; id++ {
+				t, ok := tasks[id]
+				if !ok || t.OrganizationID != *filter.Organization {
+					continue
+				}
+				if filter.After != nil && id <= *filter.After {
+					continue
+				}
+				ts = append(ts, t)
+			}
+			return ts, len(ts), nil
+		},
+		CreateTaskFn: func(ctx context.Context, t *platform.Task) error {
+			t.ID = next
+			next++
+			tasks[t.ID] = t
+			return nil
+		},
+		UpdateTaskFn: func(ctx context.Context, id platform.ID, upd platform.TaskUpdate) (*platform.Task, error) {
+			t := tasks[id]
+			if upd.Flux != nil {
+				t.Flux = *upd.Flux
+			}
+			if upd.Status != nil {
+				t.Status = *upd.Status
+			}
+			return t, nil
+		},
+	}
+}
+
+func newService() (*template.Service, *inmem.Service) {
+	s := inmem.NewService()
+	return &template.Service{
+		BucketService:             s,
+		LabelService:              s,
+		MacroService:              s,
+		DashboardService:          s,
+		TaskService:               newTaskService(),
+		TelegrafService:           s,
+		ScraperTargetStoreService: s,
+	}, s
+}
+
+func testTemplate() *platform.Template {
+	return &platform.Template{
+		Version: platform.TemplateVersion,
+		Labels: []platform.TemplateLabel{
+			{Name: "monitoring", Properties: map[string]string{"color": "ff0000"}},
+		},
+		Buckets: []platform.TemplateBucket{
+			{Name: "metrics", RetentionPeriod: "720h0m0s", Labels: []string{"monitoring"}},
+		},
+		Macros: []platform.TemplateMacro{
+			{
+				Name:     "host",
+				Selected: []string{"a"},
+				Arguments: &platform.MacroArguments{
+					Type:   "constant",
+					Values: platform.MacroConstantValues{"a", "b"},
+				},
+			},
+		},
+		Dashboards: []platform.TemplateDashboard{
+			{
+				Name:        "system",
+				Description: "system metrics",
+				Cells: []platform.TemplateCell{
+					{
+						X: 0, Y: 0, W: 4, H: 4,
+						View: &platform.View{
+							ViewContents: platform.ViewContents{Name: "cpu"},
+							Properties:   platform.EmptyViewProperties{},
+						},
+					},
+				},
+				Labels: []string{"monitoring"},
+			},
+		},
+		Tasks: []platform.TemplateTask{
+			{
+				Name:   "downsample",
+				Flux:   `option task = {name: "downsample", every: 1h} from(bucket: "metrics") |> range(start: -1h)`,
+				Status: "active",
+			},
+		},
+		Telegrafs: []platform.TemplateTelegraf{
+			{
+				Name:    "hosts",
+				Agent:   platform.TelegrafAgentConfig{Interval: 10000},
+				Plugins: []platform.TelegrafPlugin{{Comment: "cpu", Config: &inputs.CPUStats{}}},
+			},
+		},
+		Scrapers: []platform.TemplateScraper{
+			{Name: "node", Type: platform.PrometheusScraperType, URL: "http://localhost:9100/metrics", Bucket: "metrics"},
+		},
+	}
+}
+
+func actions(changes []platform.TemplateChange) map[string]string {
+	as := map[string]string{}
+	for _, c := range changes {
+		as[c.Kind+"/"+c.Name] = c.Action
+	}
+	return as
+}
+
+func allActions(action string) map[string]string {
+	return map[string]string{
+		"label/monitoring": action,
+		"bucket/metrics":   action,
+		"macro/host":       action,
+		"dashboard/system": action,
+		"task/downsample":  action,
+		"telegraf/hosts":   action,
+		"scraper/node":     action,
+	}
+}
+
+func TestService_ApplyTemplate(t *testing.T) {
+	svc, s := newService()
+	ctx := context.Background()
+
+	org := &platform.Organization{Name: "org"}
+	if err := s.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := svc.ApplyTemplate(ctx, org.ID, testTemplate(), platform.TemplateApplyOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if got, want := actions(changes), allActions(platform.TemplateActionCreate); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected dry run changes: got %v, want %v", got, want)
+	}
+	if bs, _, _ := s.FindBuckets(ctx, platform.BucketFilter{OrganizationID: &org.ID}); len(bs) != 0 {
+		t.Fatalf("dry run must not create resources, found buckets %v", bs)
+	}
+
+	ctx = platcontext.SetAuthorizer(ctx, &platform.Authorization{UserID: 1})
+	changes, err = svc.ApplyTemplate(ctx, org.ID, testTemplate(), platform.TemplateApplyOptions{})
+	if err != nil {
+		t.Fatalf("apply failed: %v", err)
+	}
+	if got, want := actions(changes), allActions(platform.TemplateActionCreate); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected changes: got %v, want %v", got, want)
+	}
+	for _, c := range changes {
+		if !c.ID.Valid() {
+			t.Errorf("created %s %q has no ID", c.Kind, c.Name)
+		}
+	}
+
+	changes, err = svc.ApplyTemplate(ctx, org.ID, testTemplate(), platform.TemplateApplyOptions{})
+	if err != nil {
+		t.Fatalf("second apply failed: %v", err)
+	}
+	if got, want := actions(changes), allActions(platform.TemplateActionUnchanged); !reflect.DeepEqual(got, want) {
+		t.Fatalf("applying a template twice must change nothing: got %v, want %v", got, want)
+	}
+
+	updated := testTemplate()
+	updated.Buckets[0].RetentionPeriod = "24h0m0s"
+	changes, err = svc.ApplyTemplate(ctx, org.ID, updated, platform.TemplateApplyOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	want := allActions(platform.TemplateActionUnchanged)
+	want["bucket/metrics"] = platform.TemplateActionUpdate
+	if got := actions(changes); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected dry run changes: got %v, want %v", got, want)
+	}
+}
+
+func TestService_ExportTemplate(t *testing.T) {
+	svc, s := newService()
+	ctx := platcontext.SetAuthorizer(context.Background(), &platform.Authorization{UserID: 1})
+
+	org := &platform.Organization{Name: "org"}
+	if err := s.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.ApplyTemplate(ctx, org.ID, testTemplate(), platform.TemplateApplyOptions{}); err != nil {
+		t.Fatalf("apply failed: %v", err)
+	}
+
+	exported, err := svc.ExportTemplate(ctx, org.ID)
+	if err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+
+	got, err := json.Marshal(exported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := json.Marshal(testTemplate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Fatalf("exported template differs from the applied one:\ngot  %s\nwant %s", got, want)
+	}
+}
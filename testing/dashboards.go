@@ -1968,3 +1968,113 @@ func UpdateDashboardCellView(
 		})
 	}
 }
+
+// DashboardVersionService tests recording, listing and restoring the versions of dashboards.
+func DashboardVersionService(
+	init func(DashboardFields, *testing.T) (platform.DashboardService, string, func()),
+	t *testing.T,
+) {
+	next := MustIDBase16(dashTwoID)
+	fields := DashboardFields{
+		IDGenerator: mock.IDGenerator{
+			IDFn: func() platform.ID {
+				next++
+				return next
+			},
+		},
+		NowFn: func() time.Time { return time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC) },
+		Dashboards: []*platform.Dashboard{
+			{
+				ID:             MustIDBase16(dashOneID),
+				OrganizationID: MustIDBase16(orgOneID),
+				Name:           "dashboard1",
+			},
+		},
+	}
+	s, _, done := init(fields, t)
+	defer done()
+	vs := s.(platform.DashboardVersionService)
+	ctx := context.Background()
+	id := MustIDBase16(dashOneID)
+
+	cell := &platform.Cell{W: 4, H: 4}
+	view := &platform.View{
+		ViewContents: platform.ViewContents{Name: "notes"},
+		Properties:   platform.MarkdownViewProperties{Type: "markdown", Note: "# notes"},
+	}
+	if err := s.AddDashboardCell(ctx, id, cell, platform.AddDashboardCellOptions{View: view}); err != nil {
+		t.Fatalf("failed to add cell: %v", err)
+	}
+	name := "renamed notes"
+	if _, err := s.UpdateDashboardCellView(ctx, id, cell.ID, platform.ViewUpdate{ViewContentsUpdate: platform.ViewContentsUpdate{Name: &name}}); err != nil {
+		t.Fatalf("failed to update view: %v", err)
+	}
+	dname := "renamed"
+	if _, err := s.UpdateDashboard(ctx, id, platform.DashboardUpdate{Name: &dname}); err != nil {
+		t.Fatalf("failed to update dashboard: %v", err)
+	}
+	if err := s.RemoveDashboardCell(ctx, id, cell.ID); err != nil {
+		t.Fatalf("failed to remove cell: %v", err)
+	}
+
+	versions, total, err := vs.FindDashboardVersions(ctx, id, platform.FindOptions{})
+	if err != nil {
+		t.Fatalf("failed to find versions: %v", err)
+	}
+	if total != 4 || len(versions) != 4 {
+		t.Fatalf("expected 4 versions, got %d of %d", len(versions), total)
+	}
+	for i, v := range versions {
+		if v.Version != 4-i {
+			t.Fatalf("expected versions newest first, got version %d at %d", v.Version, i)
+		}
+	}
+
+	versions, _, err = vs.FindDashboardVersions(ctx, id, platform.FindOptions{Offset: 1, Limit: 2})
+	if err != nil {
+		t.Fatalf("failed to find versions: %v", err)
+	}
+	if len(versions) != 2 || versions[0].Version != 3 || versions[1].Version != 2 {
+		t.Fatalf("unexpected page of versions %+v", versions)
+	}
+
+	// The version recorded by the removal holds the cell and its view.
+	v, err := vs.FindDashboardVersion(ctx, id, 4)
+	if err != nil {
+		t.Fatalf("failed to find version: %v", err)
+	}
+	if v.Change != "Dashboard Cell Removed" || v.Dashboard.Name != "renamed" || len(v.Dashboard.Cells) != 1 {
+		t.Fatalf("unexpected version %+v", v)
+	}
+	if got := v.Views[cell.ID.String()]; got.Name != "renamed notes" {
+		t.Fatalf("expected the view of the removed cell, got %+v", got)
+	}
+
+	if _, err := vs.FindDashboardVersion(ctx, id, 99); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected a missing version to be not found, got %v", err)
+	}
+
+	d, err := vs.RestoreDashboardVersion(ctx, id, 4)
+	if err != nil {
+		t.Fatalf("failed to restore version: %v", err)
+	}
+	if d.Name != "renamed" || len(d.Cells) != 1 || d.Cells[0].ID != cell.ID {
+		t.Fatalf("unexpected restored dashboard %+v", d)
+	}
+	restored, err := s.GetDashboardCellView(ctx, id, cell.ID)
+	if err != nil {
+		t.Fatalf("failed to get restored view: %v", err)
+	}
+	if diff := cmp.Diff(restored.Properties, view.Properties); diff != "" || restored.Name != "renamed notes" {
+		t.Fatalf("restored view is different -got/+want\ndiff %s", diff)
+	}
+
+	// The restore is itself undoable.
+	v, err = vs.FindDashboardVersion(ctx, id, 5)
+	if err != nil {
+		t.Fatalf("failed to find version: %v", err)
+	}
+	if v.Change != "Dashboard Restored" || len(v.Dashboard.Cells) != 0 {
+		t.Fatalf("unexpected version %+v", v)
+	}
+}
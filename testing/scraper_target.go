@@ -5,6 +5,7 @@ import (
 	"context"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	platform "github.com/influxdata/influxdb"
@@ -793,3 +794,65 @@ func UpdateTarget(
 		})
 	}
 }
+
+// ScraperStatusService tests recording the status of scrapes.
+func ScraperStatusService(
+	init func(TargetFields, *testing.T) (platform.ScraperTargetStoreService, string, func()),
+	t *testing.T,
+) {
+	fields := TargetFields{
+		Targets: []*platform.ScraperTarget{
+			{
+				ID:       MustIDBase16(targetOneID),
+				Name:     "target1",
+				OrgID:    MustIDBase16(orgOneID),
+				BucketID: MustIDBase16(bucketOneID),
+			},
+		},
+	}
+	store, _, done := init(fields, t)
+	defer done()
+	s := store.(platform.ScraperStatusService)
+	ctx := context.Background()
+
+	status := &platform.ScraperStatus{
+		LastScrape: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+		Duration:   120,
+		Samples:    10,
+		Error:      "connection refused",
+	}
+	if err := s.UpdateScraperStatus(ctx, MustIDBase16(targetOneID), status); err != nil {
+		t.Fatalf("failed to update scraper status: %v", err)
+	}
+	if err := s.UpdateScraperStatus(ctx, MustIDBase16(targetTwoID), status); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected the status of a missing target to be rejected, got %v", err)
+	}
+
+	target, err := store.GetTargetByID(ctx, MustIDBase16(targetOneID))
+	if err != nil {
+		t.Fatalf("failed to get target: %v", err)
+	}
+	if diff := cmp.Diff(target.Status, status); diff != "" {
+		t.Errorf("target status is different -got/+want\ndiff %s", diff)
+	}
+
+	// Updating a target keeps its status.
+	_, err = store.UpdateTarget(ctx, &platform.ScraperTarget{
+		ID:       MustIDBase16(targetOneID),
+		Name:     "changed",
+		Interval: 30000,
+	}, MustIDBase16(oneID))
+	if err != nil {
+		t.Fatalf("failed to update target: %v", err)
+	}
+	targets, err := store.ListTargets(ctx)
+	if err != nil {
+		t.Fatalf("failed to list targets: %v", err)
+	}
+	if len(targets) != 1 || targets[0].Interval != 30000 {
+		t.Fatalf("unexpected targets %v", targets)
+	}
+	if diff := cmp.Diff(targets[0].Status, status); diff != "" {
+		t.Errorf("target status is different -got/+want\ndiff %s", diff)
+	}
+}
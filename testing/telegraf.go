@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -178,6 +179,7 @@ func CreateTelegrafConfig(
 						ID:             MustIDBase16(oneID),
 						OrganizationID: MustIDBase16(twoID),
 						Name:           "name1",
+						Version:        1,
 						Agent: platform.TelegrafAgentConfig{
 							Interval: 1000,
 						},
@@ -274,6 +276,7 @@ func CreateTelegrafConfig(
 						ID:             MustIDBase16(twoID),
 						OrganizationID: MustIDBase16(twoID),
 						Name:           "name2",
+						Version:        1,
 						Agent: platform.TelegrafAgentConfig{
 							Interval: 1001,
 						},
@@ -1333,6 +1336,7 @@ func UpdateTelegrafConfig(
 						ID:             MustIDBase16(twoID),
 						OrganizationID: MustIDBase16(fourID),
 						Name:           "tc2",
+						Version:        1,
 						Plugins: []platform.TelegrafPlugin{
 							{
 								Comment: "comment1",
@@ -1371,6 +1375,7 @@ func UpdateTelegrafConfig(
 					ID:             MustIDBase16(twoID),
 					OrganizationID: MustIDBase16(fourID),
 					Name:           "tc2",
+					Version:        2,
 					Plugins: []platform.TelegrafPlugin{
 						{
 							Comment: "comment3",
@@ -1402,6 +1407,7 @@ func UpdateTelegrafConfig(
 						ID:             MustIDBase16(twoID),
 						OrganizationID: MustIDBase16(oneID),
 						Name:           "tc2",
+						Version:        1,
 						Plugins: []platform.TelegrafPlugin{
 							{
 								Comment: "comment1",
@@ -1455,6 +1461,7 @@ func UpdateTelegrafConfig(
 					ID:             MustIDBase16(twoID),
 					OrganizationID: MustIDBase16(oneID),
 					Name:           "tc2",
+					Version:        2,
 					Plugins: []platform.TelegrafPlugin{
 						{
 							Comment: "comment1",
@@ -1849,3 +1856,74 @@ func DeleteTelegrafConfig(
 		})
 	}
 }
+
+// TelegrafAgentService tests recording the agents fetching telegraf configs.
+// The store returned by init must also implement platform.TelegrafAgentService.
+func TelegrafAgentService(
+	init func(TelegrafConfigFields, *testing.T) (platform.TelegrafConfigStore, func()),
+	t *testing.T,
+) {
+	fields := TelegrafConfigFields{
+		TelegrafConfigs: []*platform.TelegrafConfig{
+			{
+				ID:             MustIDBase16(oneID),
+				OrganizationID: MustIDBase16(twoID),
+				Name:           "tc1",
+				Version:        2,
+			},
+			{
+				ID:             MustIDBase16(threeID),
+				OrganizationID: MustIDBase16(twoID),
+				Name:           "tc3",
+				Version:        1,
+			},
+		},
+	}
+	store, done := init(fields, t)
+	defer done()
+	s := store.(platform.TelegrafAgentService)
+	ctx := context.Background()
+
+	now := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	checkIns := []*platform.TelegrafAgent{
+		{TelegrafID: MustIDBase16(oneID), Hostname: "b", Version: 1, LastSeen: now},
+		{TelegrafID: MustIDBase16(oneID), Hostname: "a", Version: 1, LastSeen: now},
+		{TelegrafID: MustIDBase16(threeID), Hostname: "a", Version: 1, LastSeen: now},
+		{TelegrafID: MustIDBase16(oneID), Hostname: "a", Version: 2, LastSeen: now.Add(time.Minute)},
+	}
+	for _, a := range checkIns {
+		if err := s.CheckInTelegrafAgent(ctx, a); err != nil {
+			t.Fatalf("failed to check in telegraf agent: %v", err)
+		}
+	}
+
+	if err := s.CheckInTelegrafAgent(ctx, &platform.TelegrafAgent{TelegrafID: MustIDBase16(oneID)}); platform.ErrorCode(err) != platform.EEmptyValue {
+		t.Fatalf("expected agents without hostname to be rejected, got %v", err)
+	}
+
+	agents, err := s.FindTelegrafAgents(ctx, MustIDBase16(oneID))
+	if err != nil {
+		t.Fatalf("failed to find telegraf agents: %v", err)
+	}
+	want := []*platform.TelegrafAgent{
+		{TelegrafID: MustIDBase16(oneID), Hostname: "a", Version: 2, LastSeen: now.Add(time.Minute)},
+		{TelegrafID: MustIDBase16(oneID), Hostname: "b", Version: 1, LastSeen: now},
+	}
+	if diff := cmp.Diff(agents, want); diff != "" {
+		t.Errorf("telegraf agents are different -got/+want\ndiff %s", diff)
+	}
+
+	if err := store.DeleteTelegrafConfig(ctx, MustIDBase16(oneID)); err != nil {
+		t.Fatalf("failed to delete telegraf config: %v", err)
+	}
+	agents, err = s.FindTelegrafAgents(ctx, MustIDBase16(oneID))
+	if err != nil {
+		t.Fatalf("failed to find telegraf agents: %v", err)
+	}
+	if len(agents) != 0 {
+		t.Errorf("expected the agents of a deleted config to be deleted, got %v", agents)
+	}
+	if agents, _ := s.FindTelegrafAgents(ctx, MustIDBase16(threeID)); len(agents) != 1 {
+		t.Errorf("expected the agents of other configs to be kept, got %v", agents)
+	}
+}
@@ -0,0 +1,55 @@
+package cursors
+
+// ArraySummary summarizes values read from a cursor without decoding them.
+type ArraySummary struct {
+	MinTime, MaxTime int64
+	Count            int64
+}
+
+// FloatArraySummary summarizes float values read without decoding them.
+type FloatArraySummary struct {
+	ArraySummary
+	Min, Max, Sum, First, Last float64
+}
+
+// IntegerArraySummary summarizes integer values read without decoding them.
+type IntegerArraySummary struct {
+	ArraySummary
+	Min, Max, Sum, First, Last int64
+}
+
+// UnsignedArraySummary summarizes unsigned values read without decoding them.
+type UnsignedArraySummary struct {
+	ArraySummary
+	Min, Max, Sum, First, Last uint64
+}
+
+// The summary cursors are implemented by cursors able to summarize whole
+// blocks of values without decoding them. NextSummary returns the summary of
+// the next values of the cursor if they can be summarized and number at most
+// max. Otherwise it returns false, and the values must be read with Next.
+
+type FloatArraySummaryCursor interface {
+	FloatArrayCursor
+	NextSummary(max int64) (FloatArraySummary, bool)
+}
+
+type IntegerArraySummaryCursor interface {
+	IntegerArrayCursor
+	NextSummary(max int64) (IntegerArraySummary, bool)
+}
+
+type UnsignedArraySummaryCursor interface {
+	UnsignedArrayCursor
+	NextSummary(max int64) (UnsignedArraySummary, bool)
+}
+
+type StringArraySummaryCursor interface {
+	StringArrayCursor
+	NextSummary(max int64) (ArraySummary, bool)
+}
+
+type BooleanArraySummaryCursor interface {
+	BooleanArrayCursor
+	NextSummary(max int64) (ArraySummary, bool)
+}
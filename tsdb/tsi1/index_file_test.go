@@ -2,6 +2,9 @@ package tsi1_test
 
 import (
 	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/influxdata/influxdb/models"
@@ -51,8 +54,15 @@ func TestGenerateIndexFile(t *testing.T) {
 
 // Ensure index file generated with uvarint encoding can be loaded.
 func TestGenerateIndexFile_Uvarint(t *testing.T) {
-	// Load previously generated series file.
-	sfile := tsdb.NewSeriesFile("testdata/uvarint/_series")
+	// Open the series file in a temporary directory so that the test does
+	// not write into testdata.
+	dir, err := ioutil.TempDir("", "tsi1-uvarint-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sfile := tsdb.NewSeriesFile(filepath.Join(dir, "_series"))
 	if err := sfile.Open(); err != nil {
 		t.Fatal(err)
 	}
@@ -28,6 +28,7 @@ type floatArrayAscendingCursor struct {
 		keyCursor *KeyCursor
 	}
 
+	seek  int64
 	end   int64
 	res   *tsdb.FloatArray
 	stats cursors.CursorStats
@@ -42,6 +43,7 @@ func newFloatArrayAscendingCursor() *floatArrayAscendingCursor {
 }
 
 func (c *floatArrayAscendingCursor) reset(seek, end int64, cacheValues Values, tsmKeyCursor *KeyCursor) {
+	c.seek = seek
 	c.end = end
 	c.cache.values = cacheValues
 	c.cache.pos = sort.Search(len(c.cache.values), func(i int) bool {
@@ -71,6 +73,12 @@ func (c *floatArrayAscendingCursor) Stats() cursors.CursorStats { return c.stats
 
 // Next returns the next key/value for the cursor.
 func (c *floatArrayAscendingCursor) Next() *tsdb.FloatArray {
+	// TSM blocks are read once the values of the previous block are consumed,
+	// so that whole blocks may be summarized instead.
+	if c.tsm.pos >= c.tsm.values.Len() {
+		c.nextTSM()
+	}
+
 	pos := 0
 	cvals := c.cache.values
 	tvals := c.tsm.values
@@ -109,16 +117,13 @@ func (c *floatArrayAscendingCursor) Next() *tsdb.FloatArray {
 				// optimization: all points served from TSM data
 				copy(c.res.Timestamps, tvals.Timestamps)
 				pos += copy(c.res.Values, tvals.Values)
-				c.nextTSM()
+				c.tsm.pos = len(tvals.Timestamps)
 			} else {
 				// copy as much as we can
 				n := copy(c.res.Timestamps[pos:], tvals.Timestamps[c.tsm.pos:])
 				copy(c.res.Values[pos:], tvals.Values[c.tsm.pos:])
 				pos += n
 				c.tsm.pos += n
-				if c.tsm.pos >= len(tvals.Timestamps) {
-					c.nextTSM()
-				}
 			}
 		}
 
@@ -151,6 +156,30 @@ func (c *floatArrayAscendingCursor) Next() *tsdb.FloatArray {
 	return c.res
 }
 
+// NextSummary returns the summary of the next TSM block if it can be read
+// instead of its values, see KeyCursor.ReadBlockSummary.
+func (c *floatArrayAscendingCursor) NextSummary(max int64) (cursors.FloatArraySummary, bool) {
+	// The values of the last block read must be consumed first.
+	if c.tsm.pos < c.tsm.values.Len() {
+		return cursors.FloatArraySummary{}, false
+	}
+	c.tsm.keyCursor.Next()
+
+	// Cache values up to the end of the block would need to be merged.
+	end := c.end
+	if c.cache.pos < len(c.cache.values) {
+		if t := c.cache.values[c.cache.pos].UnixNano() - 1; t < end {
+			end = t
+		}
+	}
+
+	minTime, maxTime, s, ok := c.tsm.keyCursor.ReadBlockSummary(c.seek, end, max)
+	if !ok {
+		return cursors.FloatArraySummary{}, false
+	}
+	return s.floatArraySummary(minTime, maxTime), true
+}
+
 func (c *floatArrayAscendingCursor) nextTSM() *tsdb.FloatArray {
 	c.tsm.keyCursor.Next()
 	c.tsm.values = c.readArrayBlock()
@@ -336,6 +365,7 @@ type integerArrayAscendingCursor struct {
 		keyCursor *KeyCursor
 	}
 
+	seek  int64
 	end   int64
 	res   *tsdb.IntegerArray
 	stats cursors.CursorStats
@@ -350,6 +380,7 @@ func newIntegerArrayAscendingCursor() *integerArrayAscendingCursor {
 }
 
 func (c *integerArrayAscendingCursor) reset(seek, end int64, cacheValues Values, tsmKeyCursor *KeyCursor) {
+	c.seek = seek
 	c.end = end
 	c.cache.values = cacheValues
 	c.cache.pos = sort.Search(len(c.cache.values), func(i int) bool {
@@ -379,6 +410,12 @@ func (c *integerArrayAscendingCursor) Stats() cursors.CursorStats { return c.sta
 
 // Next returns the next key/value for the cursor.
 func (c *integerArrayAscendingCursor) Next() *tsdb.IntegerArray {
+	// TSM blocks are read once the values of the previous block are consumed,
+	// so that whole blocks may be summarized instead.
+	if c.tsm.pos >= c.tsm.values.Len() {
+		c.nextTSM()
+	}
+
 	pos := 0
 	cvals := c.cache.values
 	tvals := c.tsm.values
@@ -417,16 +454,13 @@ func (c *integerArrayAscendingCursor) Next() *tsdb.IntegerArray {
 				// optimization: all points served from TSM data
 				copy(c.res.Timestamps, tvals.Timestamps)
 				pos += copy(c.res.Values, tvals.Values)
-				c.nextTSM()
+				c.tsm.pos = len(tvals.Timestamps)
 			} else {
 				// copy as much as we can
 				n := copy(c.res.Timestamps[pos:], tvals.Timestamps[c.tsm.pos:])
 				copy(c.res.Values[pos:], tvals.Values[c.tsm.pos:])
 				pos += n
 				c.tsm.pos += n
-				if c.tsm.pos >= len(tvals.Timestamps) {
-					c.nextTSM()
-				}
 			}
 		}
 
@@ -459,6 +493,30 @@ func (c *integerArrayAscendingCursor) Next() *tsdb.IntegerArray {
 	return c.res
 }
 
+// NextSummary returns the summary of the next TSM block if it can be read
+// instead of its values, see KeyCursor.ReadBlockSummary.
+func (c *integerArrayAscendingCursor) NextSummary(max int64) (cursors.IntegerArraySummary, bool) {
+	// The values of the last block read must be consumed first.
+	if c.tsm.pos < c.tsm.values.Len() {
+		return cursors.IntegerArraySummary{}, false
+	}
+	c.tsm.keyCursor.Next()
+
+	// Cache values up to the end of the block would need to be merged.
+	end := c.end
+	if c.cache.pos < len(c.cache.values) {
+		if t := c.cache.values[c.cache.pos].UnixNano() - 1; t < end {
+			end = t
+		}
+	}
+
+	minTime, maxTime, s, ok := c.tsm.keyCursor.ReadBlockSummary(c.seek, end, max)
+	if !ok {
+		return cursors.IntegerArraySummary{}, false
+	}
+	return s.integerArraySummary(minTime, maxTime), true
+}
+
 func (c *integerArrayAscendingCursor) nextTSM() *tsdb.IntegerArray {
 	c.tsm.keyCursor.Next()
 	c.tsm.values = c.readArrayBlock()
@@ -644,6 +702,7 @@ type unsignedArrayAscendingCursor struct {
 		keyCursor *KeyCursor
 	}
 
+	seek  int64
 	end   int64
 	res   *tsdb.UnsignedArray
 	stats cursors.CursorStats
@@ -658,6 +717,7 @@ func newUnsignedArrayAscendingCursor() *unsignedArrayAscendingCursor {
 }
 
 func (c *unsignedArrayAscendingCursor) reset(seek, end int64, cacheValues Values, tsmKeyCursor *KeyCursor) {
+	c.seek = seek
 	c.end = end
 	c.cache.values = cacheValues
 	c.cache.pos = sort.Search(len(c.cache.values), func(i int) bool {
@@ -687,6 +747,12 @@ func (c *unsignedArrayAscendingCursor) Stats() cursors.CursorStats { return c.st
 
 // Next returns the next key/value for the cursor.
 func (c *unsignedArrayAscendingCursor) Next() *tsdb.UnsignedArray {
+	// TSM blocks are read once the values of the previous block are consumed,
+	// so that whole blocks may be summarized instead.
+	if c.tsm.pos >= c.tsm.values.Len() {
+		c.nextTSM()
+	}
+
 	pos := 0
 	cvals := c.cache.values
 	tvals := c.tsm.values
@@ -725,16 +791,13 @@ func (c *unsignedArrayAscendingCursor) Next() *tsdb.UnsignedArray {
 				// optimization: all points served from TSM data
 				copy(c.res.Timestamps, tvals.Timestamps)
 				pos += copy(c.res.Values, tvals.Values)
-				c.nextTSM()
+				c.tsm.pos = len(tvals.Timestamps)
 			} else {
 				// copy as much as we can
 				n := copy(c.res.Timestamps[pos:], tvals.Timestamps[c.tsm.pos:])
 				copy(c.res.Values[pos:], tvals.Values[c.tsm.pos:])
 				pos += n
 				c.tsm.pos += n
-				if c.tsm.pos >= len(tvals.Timestamps) {
-					c.nextTSM()
-				}
 			}
 		}
 
@@ -767,6 +830,30 @@ func (c *unsignedArrayAscendingCursor) Next() *tsdb.UnsignedArray {
 	return c.res
 }
 
+// NextSummary returns the summary of the next TSM block if it can be read
+// instead of its values, see KeyCursor.ReadBlockSummary.
+func (c *unsignedArrayAscendingCursor) NextSummary(max int64) (cursors.UnsignedArraySummary, bool) {
+	// The values of the last block read must be consumed first.
+	if c.tsm.pos < c.tsm.values.Len() {
+		return cursors.UnsignedArraySummary{}, false
+	}
+	c.tsm.keyCursor.Next()
+
+	// Cache values up to the end of the block would need to be merged.
+	end := c.end
+	if c.cache.pos < len(c.cache.values) {
+		if t := c.cache.values[c.cache.pos].UnixNano() - 1; t < end {
+			end = t
+		}
+	}
+
+	minTime, maxTime, s, ok := c.tsm.keyCursor.ReadBlockSummary(c.seek, end, max)
+	if !ok {
+		return cursors.UnsignedArraySummary{}, false
+	}
+	return s.unsignedArraySummary(minTime, maxTime), true
+}
+
 func (c *unsignedArrayAscendingCursor) nextTSM() *tsdb.UnsignedArray {
 	c.tsm.keyCursor.Next()
 	c.tsm.values = c.readArrayBlock()
@@ -952,6 +1039,7 @@ type stringArrayAscendingCursor struct {
 		keyCursor *KeyCursor
 	}
 
+	seek  int64
 	end   int64
 	res   *tsdb.StringArray
 	stats cursors.CursorStats
@@ -966,6 +1054,7 @@ func newStringArrayAscendingCursor() *stringArrayAscendingCursor {
 }
 
 func (c *stringArrayAscendingCursor) reset(seek, end int64, cacheValues Values, tsmKeyCursor *KeyCursor) {
+	c.seek = seek
 	c.end = end
 	c.cache.values = cacheValues
 	c.cache.pos = sort.Search(len(c.cache.values), func(i int) bool {
@@ -995,6 +1084,12 @@ func (c *stringArrayAscendingCursor) Stats() cursors.CursorStats { return c.stat
 
 // Next returns the next key/value for the cursor.
 func (c *stringArrayAscendingCursor) Next() *tsdb.StringArray {
+	// TSM blocks are read once the values of the previous block are consumed,
+	// so that whole blocks may be summarized instead.
+	if c.tsm.pos >= c.tsm.values.Len() {
+		c.nextTSM()
+	}
+
 	pos := 0
 	cvals := c.cache.values
 	tvals := c.tsm.values
@@ -1033,16 +1128,13 @@ func (c *stringArrayAscendingCursor) Next() *tsdb.StringArray {
 				// optimization: all points served from TSM data
 				copy(c.res.Timestamps, tvals.Timestamps)
 				pos += copy(c.res.Values, tvals.Values)
-				c.nextTSM()
+				c.tsm.pos = len(tvals.Timestamps)
 			} else {
 				// copy as much as we can
 				n := copy(c.res.Timestamps[pos:], tvals.Timestamps[c.tsm.pos:])
 				copy(c.res.Values[pos:], tvals.Values[c.tsm.pos:])
 				pos += n
 				c.tsm.pos += n
-				if c.tsm.pos >= len(tvals.Timestamps) {
-					c.nextTSM()
-				}
 			}
 		}
 
@@ -1077,6 +1169,30 @@ func (c *stringArrayAscendingCursor) Next() *tsdb.StringArray {
 	return c.res
 }
 
+// NextSummary returns the summary of the next TSM block if it can be read
+// instead of its values, see KeyCursor.ReadBlockSummary.
+func (c *stringArrayAscendingCursor) NextSummary(max int64) (cursors.ArraySummary, bool) {
+	// The values of the last block read must be consumed first.
+	if c.tsm.pos < c.tsm.values.Len() {
+		return cursors.ArraySummary{}, false
+	}
+	c.tsm.keyCursor.Next()
+
+	// Cache values up to the end of the block would need to be merged.
+	end := c.end
+	if c.cache.pos < len(c.cache.values) {
+		if t := c.cache.values[c.cache.pos].UnixNano() - 1; t < end {
+			end = t
+		}
+	}
+
+	minTime, maxTime, s, ok := c.tsm.keyCursor.ReadBlockSummary(c.seek, end, max)
+	if !ok {
+		return cursors.ArraySummary{}, false
+	}
+	return s.arraySummary(minTime, maxTime), true
+}
+
 func (c *stringArrayAscendingCursor) nextTSM() *tsdb.StringArray {
 	c.tsm.keyCursor.Next()
 	c.tsm.values = c.readArrayBlock()
@@ -1264,6 +1380,7 @@ type booleanArrayAscendingCursor struct {
 		keyCursor *KeyCursor
 	}
 
+	seek  int64
 	end   int64
 	res   *tsdb.BooleanArray
 	stats cursors.CursorStats
@@ -1278,6 +1395,7 @@ func newBooleanArrayAscendingCursor() *booleanArrayAscendingCursor {
 }
 
 func (c *booleanArrayAscendingCursor) reset(seek, end int64, cacheValues Values, tsmKeyCursor *KeyCursor) {
+	c.seek = seek
 	c.end = end
 	c.cache.values = cacheValues
 	c.cache.pos = sort.Search(len(c.cache.values), func(i int) bool {
@@ -1307,6 +1425,12 @@ func (c *booleanArrayAscendingCursor) Stats() cursors.CursorStats { return c.sta
 
 // Next returns the next key/value for the cursor.
 func (c *booleanArrayAscendingCursor) Next() *tsdb.BooleanArray {
+	// TSM blocks are read once the values of the previous block are consumed,
+	// so that whole blocks may be summarized instead.
+	if c.tsm.pos >= c.tsm.values.Len() {
+		c.nextTSM()
+	}
+
 	pos := 0
 	cvals := c.cache.values
 	tvals := c.tsm.values
@@ -1345,16 +1469,13 @@ func (c *booleanArrayAscendingCursor) Next() *tsdb.BooleanArray {
 				// optimization: all points served from TSM data
 				copy(c.res.Timestamps, tvals.Timestamps)
 				pos += copy(c.res.Values, tvals.Values)
-				c.nextTSM()
+				c.tsm.pos = len(tvals.Timestamps)
 			} else {
 				// copy as much as we can
 				n := copy(c.res.Timestamps[pos:], tvals.Timestamps[c.tsm.pos:])
 				copy(c.res.Values[pos:], tvals.Values[c.tsm.pos:])
 				pos += n
 				c.tsm.pos += n
-				if c.tsm.pos >= len(tvals.Timestamps) {
-					c.nextTSM()
-				}
 			}
 		}
 
@@ -1387,6 +1508,30 @@ func (c *booleanArrayAscendingCursor) Next() *tsdb.BooleanArray {
 	return c.res
 }
 
+// NextSummary returns the summary of the next TSM block if it can be read
+// instead of its values, see KeyCursor.ReadBlockSummary.
+func (c *booleanArrayAscendingCursor) NextSummary(max int64) (cursors.ArraySummary, bool) {
+	// The values of the last block read must be consumed first.
+	if c.tsm.pos < c.tsm.values.Len() {
+		return cursors.ArraySummary{}, false
+	}
+	c.tsm.keyCursor.Next()
+
+	// Cache values up to the end of the block would need to be merged.
+	end := c.end
+	if c.cache.pos < len(c.cache.values) {
+		if t := c.cache.values[c.cache.pos].UnixNano() - 1; t < end {
+			end = t
+		}
+	}
+
+	minTime, maxTime, s, ok := c.tsm.keyCursor.ReadBlockSummary(c.seek, end, max)
+	if !ok {
+		return cursors.ArraySummary{}, false
+	}
+	return s.arraySummary(minTime, maxTime), true
+}
+
 func (c *booleanArrayAscendingCursor) nextTSM() *tsdb.BooleanArray {
 	c.tsm.keyCursor.Next()
 	c.tsm.values = c.readArrayBlock()
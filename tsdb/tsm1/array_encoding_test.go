@@ -3,6 +3,7 @@ package tsm1_test
 import (
 	"fmt"
 	"math/rand"
+	"reflect"
 	"testing"
 	"time"
 
@@ -32,6 +33,40 @@ func TestDecodeFloatArrayBlock(t *testing.T) {
 	}
 }
 
+func TestDecodeStringArrayBlock_Dictionary(t *testing.T) {
+	statuses := []string{"ok", "warn", "critical"}
+	valueCount := 1000
+	times := getTimes(valueCount, 60, time.Second)
+	values := make(tsm1.StringValues, len(times))
+	for i, t := range times {
+		values[i] = tsm1.NewStringValue(t, statuses[(i/10)%len(statuses)]).(tsm1.StringValue)
+	}
+	exp := tsm1.NewStringArrayFromValues(values)
+
+	// Compactions encode blocks from arrays, choosing dictionary encoding
+	// for values such as these. Encoding modifies the timestamps of the array.
+	b, err := tsm1.EncodeStringArrayBlock(tsm1.NewStringArrayFromValues(values), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := tsdb.NewStringArrayLen(exp.Len())
+	if err := tsm1.DecodeStringArrayBlock(b, got); err != nil {
+		t.Fatalf("unexpected error decoding block: %v", err)
+	}
+	if !cmp.Equal(got, exp) {
+		t.Fatalf("unexpected values -got/+exp\n%s", cmp.Diff(got, exp))
+	}
+
+	var decoded []tsm1.StringValue
+	if decoded, err = tsm1.DecodeStringBlock(b, &decoded); err != nil {
+		t.Fatalf("unexpected error decoding block: %v", err)
+	}
+	if !reflect.DeepEqual(tsm1.StringValues(decoded), values) {
+		t.Fatalf("unexpected results:\n\tgot: %v\n\texp: %v\n", decoded, values)
+	}
+}
+
 func BenchmarkDecodeBooleanArrayBlock(b *testing.B) {
 	cases := []int{
 		5,
@@ -12,13 +12,34 @@ var (
 	errStringBatchDecodeInvalidStringLength = fmt.Errorf("stringArrayDecodeAll: invalid encoded string length")
 	errStringBatchDecodeLengthOverflow      = fmt.Errorf("stringArrayDecodeAll: length overflow")
 	errStringBatchDecodeShortBuffer         = fmt.Errorf("stringArrayDecodeAll: short buffer")
+	errStringBatchDecodeInvalidDictionary   = fmt.Errorf("stringArrayDecodeAll: invalid dictionary")
+	errStringBatchDecodeInvalidRun          = fmt.Errorf("stringArrayDecodeAll: invalid run")
+)
+
+const (
+	// stringDictionaryMinValues is the number of values from which a block may
+	// be dictionary encoded.
+	stringDictionaryMinValues = 16
+
+	// stringDictionaryRatio is the number of values per distinct value from
+	// which a block is dictionary encoded.
+	stringDictionaryRatio = 4
+
+	// stringDictionaryMaxValues bounds the number of values decoded from a
+	// dictionary encoded block, well above the size of any block written.
+	stringDictionaryMaxValues = 1 << 20
 )
 
 // StringArrayEncodeAll encodes src into b, returning b and any error encountered.
 // The returned slice may be of a different length and capactity to b.
 //
-// Currently only the string compression scheme used snappy.
+// Blocks with a low ratio of distinct values are dictionary encoded, all
+// other blocks are compressed using snappy.
 func StringArrayEncodeAll(src []string, b []byte) ([]byte, error) {
+	if dict, index := stringDictionary(src); dict != nil {
+		return stringArrayEncodeAllDictionary(src, dict, index, b), nil
+	}
+
 	srcSz := 2 + len(src)*binary.MaxVarintLen32 // strings should't be longer than 64kb
 	for i := range src {
 		srcSz += len(src[i])
@@ -61,9 +82,78 @@ func StringArrayEncodeAll(src []string, b []byte) ([]byte, error) {
 	return dst[:len(res)+1], nil
 }
 
+// stringDictionary returns the distinct values of src in order of first
+// appearance and their index in the dictionary, if src is worth dictionary
+// encoding. It returns nil otherwise.
+func stringDictionary(src []string) ([]string, map[string]uint64) {
+	if len(src) < stringDictionaryMinValues || len(src) > stringDictionaryMaxValues {
+		return nil, nil
+	}
+
+	max := len(src) / stringDictionaryRatio
+	var dict []string
+	index := make(map[string]uint64)
+	for _, s := range src {
+		if _, ok := index[s]; ok {
+			continue
+		}
+		if len(dict) == max {
+			return nil, nil
+		}
+		index[s] = uint64(len(dict))
+		dict = append(dict, s)
+	}
+	return dict, index
+}
+
+// stringArrayEncodeAllDictionary encodes src into b using the dictionary dict.
+// The header is followed by the number of distinct values and the
+// length-prefixed values, then by the (index, length) pairs of each run of
+// identical values.
+func stringArrayEncodeAllDictionary(src, dict []string, index map[string]uint64, b []byte) []byte {
+	sz := 1 + binary.MaxVarintLen64
+	for i := range dict {
+		sz += binary.MaxVarintLen64 + len(dict[i])
+	}
+	sz += len(src) * 2 * binary.MaxVarintLen64 // at worst, one run per value
+
+	if cap(b) < sz {
+		b = make([]byte, sz)
+	} else {
+		b = b[:sz]
+	}
+
+	b[0] = stringCompressedDictionary << 4
+	n := 1
+	n += binary.PutUvarint(b[n:], uint64(len(dict)))
+	for i := range dict {
+		n += binary.PutUvarint(b[n:], uint64(len(dict[i])))
+		n += copy(b[n:], dict[i])
+	}
+
+	for i := 0; i < len(src); {
+		j := i + 1
+		for j < len(src) && src[j] == src[i] {
+			j++
+		}
+		n += binary.PutUvarint(b[n:], index[src[i]])
+		n += binary.PutUvarint(b[n:], uint64(j-i))
+		i = j
+	}
+	return b[:n]
+}
+
 func StringArrayDecodeAll(b []byte, dst []string) ([]string, error) {
-	// First byte stores the encoding type, only have snappy format
-	// currently so ignore for now.
+	// First byte stores the encoding type. Blocks are snappy compressed
+	// unless dictionary encoded.
+	if len(b) > 0 && b[0]>>4 == stringCompressedDictionary {
+		res, err := stringArrayDecodeAllDictionary(b, dst)
+		if err != nil {
+			return []string{}, err
+		}
+		return res, nil
+	}
+
 	if len(b) > 0 {
 		var err error
 		// it is important that to note that `snappy.Decode` always returns
@@ -126,3 +216,56 @@ func StringArrayDecodeAll(b []byte, dst []string) ([]string, error) {
 
 	return dst[:j], nil
 }
+
+// stringArrayDecodeAllDictionary decodes the dictionary encoded block b into dst.
+func stringArrayDecodeAllDictionary(b []byte, dst []string) ([]string, error) {
+	i := 1
+	count, n := binary.Uvarint(b[i:])
+	if n <= 0 || count > uint64(len(b)) {
+		return nil, errStringBatchDecodeInvalidDictionary
+	}
+	i += n
+
+	// The dictionary values are copied as b may refer to mmapped memory.
+	dict := make([]string, count)
+	for k := range dict {
+		length, n := binary.Uvarint(b[i:])
+		if n <= 0 {
+			return nil, errStringBatchDecodeInvalidStringLength
+		}
+
+		lower := i + n
+		upper := lower + int(length)
+		if upper < lower {
+			return nil, errStringBatchDecodeLengthOverflow
+		}
+		if upper > len(b) {
+			return nil, errStringBatchDecodeShortBuffer
+		}
+
+		dict[k] = string(b[lower:upper])
+		i = upper
+	}
+
+	dst = dst[:0]
+	for i < len(b) {
+		idx, n := binary.Uvarint(b[i:])
+		if n <= 0 || idx >= count {
+			return nil, errStringBatchDecodeInvalidRun
+		}
+		i += n
+
+		length, n := binary.Uvarint(b[i:])
+		if n <= 0 || length == 0 || length > uint64(stringDictionaryMaxValues-len(dst)) {
+			return nil, errStringBatchDecodeInvalidRun
+		}
+		i += n
+
+		s := dict[idx]
+		for k := uint64(0); k < length; k++ {
+			dst = append(dst, s)
+		}
+	}
+
+	return dst, nil
+}
@@ -164,6 +164,115 @@ func TestStringArrayEncodeAll_Quick(t *testing.T) {
 	}, nil)
 }
 
+func TestStringArrayEncodeAll_Dictionary(t *testing.T) {
+	statuses := []string{"ok", "warn", "critical"}
+	src := make([]string, 1000)
+	for i := range src {
+		src[i] = statuses[(i/10)%len(statuses)]
+	}
+
+	b, err := StringArrayEncodeAll(src, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if b[0]>>4 != stringCompressedDictionary {
+		t.Fatalf("unexpected encoding: got %v, exp %v", b[0]>>4, stringCompressedDictionary)
+	}
+
+	// 1 header byte, 1 byte for the dictionary size, 17 bytes for the values
+	// and 100 runs of 2 bytes.
+	if exp := 219; len(b) != exp {
+		t.Fatalf("unexpected length: got %v, exp %v", len(b), exp)
+	}
+
+	got, err := StringArrayDecodeAll(b, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cmp.Equal(got, src) {
+		t.Fatalf("unexpected value: -got/+exp\n%s", cmp.Diff(got, src))
+	}
+
+	var dec StringDecoder
+	if err := dec.SetBytes(b); err != nil {
+		t.Fatalf("unexpected error creating string decoder: %v", err)
+	}
+	for i, v := range src {
+		if !dec.Next() {
+			t.Fatalf("unexpected next value: got false, exp true")
+		}
+		if v != dec.Read() {
+			t.Fatalf("unexpected value at pos %d: got %v, exp %v", i, dec.Read(), v)
+		}
+	}
+	if dec.Next() {
+		t.Fatalf("unexpected next value: got true, exp false")
+	}
+}
+
+func TestStringArrayEncodeAll_Dictionary_HighCardinality(t *testing.T) {
+	// 1 distinct value every 3 values is too many for a dictionary.
+	src := make([]string, 999)
+	for i := range src {
+		src[i] = fmt.Sprintf("value %d", i/3)
+	}
+
+	b, err := StringArrayEncodeAll(src, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b[0]>>4 != stringCompressedSnappy {
+		t.Fatalf("unexpected encoding: got %v, exp %v", b[0]>>4, stringCompressedSnappy)
+	}
+}
+
+func TestStringArrayEncodeAll_Dictionary_Quick(t *testing.T) {
+	var base []byte
+	quick.Check(func(dict []string, indexes []uint8) bool {
+		if len(dict) == 0 {
+			dict = []string{""}
+		}
+		src := make([]string, len(indexes)*stringDictionaryRatio)
+		for i := range src {
+			src[i] = dict[int(indexes[i/stringDictionaryRatio])%len(dict)]
+		}
+
+		buf, err := StringArrayEncodeAll(src, base)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(src) >= stringDictionaryMinValues && buf[0]>>4 != stringCompressedDictionary {
+			t.Fatalf("unexpected encoding: got %v, exp %v", buf[0]>>4, stringCompressedDictionary)
+		}
+
+		got, err := StringArrayDecodeAll(buf, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !cmp.Equal(got, src) {
+			t.Fatalf("unexpected value: -got/+exp\n%s", cmp.Diff(got, src))
+		}
+
+		var dec StringDecoder
+		if err := dec.SetBytes(buf); err != nil {
+			t.Fatal(err)
+		}
+		got = got[:0]
+		for dec.Next() {
+			got = append(got, dec.Read())
+		}
+		if err := dec.Error(); err != nil {
+			t.Fatal(err)
+		}
+		if !cmp.Equal(got, src) {
+			t.Fatalf("unexpected value: -got/+exp\n%s", cmp.Diff(got, src))
+		}
+
+		return true
+	}, nil)
+}
+
 func TestStringArrayDecodeAll_NoValues(t *testing.T) {
 	enc := NewStringEncoder(1024)
 	b, err := enc.Bytes()
@@ -303,6 +412,72 @@ func TestStringArrayDecodeAll_CorruptBytes(t *testing.T) {
 	}
 }
 
+func TestStringArrayDecodeAll_Dictionary_CorruptBytes(t *testing.T) {
+	cases := []string{
+		"\x20",                                   // Missing dictionary
+		"\x20\x01\x05ok",                         // Higher length than actual data
+		"\x20\x01\x02ok\x01\x01",                 // Index out of the dictionary
+		"\x20\x01\x02ok\x00\x00",                 // Empty run
+		"\x20\x01\x02ok\x00",                     // Missing run length
+		"\x20\x01\x02ok\x00\xff\xff\xff\xff\x0f", // Run too long
+	}
+
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%q", c), func(t *testing.T) {
+			got, err := StringArrayDecodeAll([]byte(c), nil)
+			if err == nil {
+				t.Fatal("exp an err, got nil")
+			}
+
+			exp := []string{}
+			if !cmp.Equal(got, exp) {
+				t.Fatalf("unexpected value: -got/+exp\n%s", cmp.Diff(got, exp))
+			}
+
+			var dec StringDecoder
+			if err := dec.SetBytes([]byte(c)); err == nil {
+				t.Fatalf("exp an err, got nil: %q", c)
+			}
+		})
+	}
+}
+
+// TestStringArrayDecodeAll_Dictionary_Fuzz decodes randomly corrupted
+// dictionary encoded blocks, which must either fail or decode without panicking.
+func TestStringArrayDecodeAll_Dictionary_Fuzz(t *testing.T) {
+	statuses := []string{"ok", "warn", "critical"}
+	src := make([]string, 100)
+	for i := range src {
+		src[i] = statuses[(i/7)%len(statuses)]
+	}
+	b, err := StringArrayEncodeAll(src, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rnd := rand.New(rand.NewSource(0))
+	buf := make([]byte, len(b))
+	for i := 0; i < 10000; i++ {
+		copy(buf, b)
+		for j := rnd.Intn(4); j >= 0; j-- {
+			buf[1+rnd.Intn(len(buf)-1)] = byte(rnd.Intn(256))
+		}
+		n := 1 + rnd.Intn(len(buf))
+
+		if _, err := StringArrayDecodeAll(buf[:n], nil); err != nil {
+			continue
+		}
+
+		var dec StringDecoder
+		if err := dec.SetBytes(buf[:n]); err != nil {
+			t.Fatalf("exp string decoder to accept %x", buf[:n])
+		}
+		for dec.Next() {
+			dec.Read()
+		}
+	}
+}
+
 func BenchmarkEncodeStrings(b *testing.B) {
 	var err error
 	cases := []int{10, 100, 1000}
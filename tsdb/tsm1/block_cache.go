@@ -0,0 +1,270 @@
+package tsm1
+
+import (
+	"container/list"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/cespare/xxhash"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// blockCacheShards is the number of independently locked shards of a BlockCache.
+const blockCacheShards = 16
+
+// BlockCache is a size-bounded LRU cache of decoded TSM blocks, shared by the
+// cursors of a FileStore so that blocks read repeatedly, such as the recent
+// blocks of refreshing dashboards, are only decoded once.
+//
+// Blocks are identified by the path of their file and their offset in it. The
+// blocks of a file are evicted when it is unlinked or replaced, or when a
+// tombstone is written for it, which is observed by wrapping the observer of
+// the FileStore, see FileStore.WithBlockCache.
+//
+// A nil BlockCache caches nothing.
+type BlockCache struct {
+	shards  [blockCacheShards]blockCacheShard
+	tracker *blockCacheTracker
+}
+
+type blockCacheKey struct {
+	path   string
+	offset int64
+}
+
+type blockCacheEntry struct {
+	key    blockCacheKey
+	values interface{}
+	size   uint64
+}
+
+type blockCacheShard struct {
+	mu      sync.Mutex
+	maxSize uint64
+	size    uint64
+	lru     *list.List // most recently used first
+	entries map[blockCacheKey]*list.Element
+}
+
+// NewBlockCache returns a BlockCache holding at most maxSize bytes of decoded blocks.
+func NewBlockCache(maxSize uint64) *BlockCache {
+	c := &BlockCache{
+		tracker: newBlockCacheTracker(newBlockCacheMetrics(nil), nil),
+	}
+	for i := range c.shards {
+		c.shards[i].maxSize = maxSize / blockCacheShards
+		c.shards[i].lru = list.New()
+		c.shards[i].entries = make(map[blockCacheKey]*list.Element)
+	}
+	return c
+}
+
+func (c *BlockCache) shard(key blockCacheKey) *blockCacheShard {
+	h := xxhash.Sum64String(key.path) ^ uint64(key.offset)
+	return &c.shards[h%blockCacheShards]
+}
+
+// get returns the decoded values of the block at offset in the file path.
+func (c *BlockCache) get(path string, offset int64) (interface{}, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	key := blockCacheKey{path: path, offset: offset}
+	s := c.shard(key)
+	s.mu.Lock()
+	e, ok := s.entries[key]
+	if ok {
+		s.lru.MoveToFront(e)
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		c.tracker.IncMisses()
+		return nil, false
+	}
+	c.tracker.IncHits()
+	return e.Value.(*blockCacheEntry).values, true
+}
+
+// put adds the decoded values of the block at offset in the file path, whose
+// size in memory is size, evicting the least recently used blocks as needed.
+// The values must not be modified once added.
+func (c *BlockCache) put(path string, offset int64, values interface{}, size uint64) {
+	if c == nil {
+		return
+	}
+
+	key := blockCacheKey{path: path, offset: offset}
+	s := c.shard(key)
+	if size > s.maxSize {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.entries[key]; ok {
+		return
+	}
+
+	var evicted int
+	var freed uint64
+	for s.size+size > s.maxSize {
+		e := s.lru.Back()
+		freed += s.remove(e)
+		evicted++
+	}
+
+	s.entries[key] = s.lru.PushFront(&blockCacheEntry{key: key, values: values, size: size})
+	s.size += size
+
+	c.tracker.AddMemBytes(size)
+	c.tracker.SubMemBytes(freed)
+	c.tracker.AddEvictions(evicted)
+}
+
+// remove removes the element e from the shard and returns its size.
+func (s *blockCacheShard) remove(e *list.Element) uint64 {
+	entry := s.lru.Remove(e).(*blockCacheEntry)
+	delete(s.entries, entry.key)
+	s.size -= entry.size
+	return entry.size
+}
+
+// evictFile evicts the blocks of the file path.
+func (c *BlockCache) evictFile(path string) {
+	if c == nil {
+		return
+	}
+
+	for i := range c.shards {
+		s := &c.shards[i]
+
+		var evicted int
+		var freed uint64
+		s.mu.Lock()
+		for key, e := range s.entries {
+			if key.path == path {
+				freed += s.remove(e)
+				evicted++
+			}
+		}
+		s.mu.Unlock()
+
+		c.tracker.SubMemBytes(freed)
+		c.tracker.AddEvictions(evicted)
+	}
+}
+
+// Size returns the size in bytes of the decoded blocks in the cache.
+func (c *BlockCache) Size() uint64 {
+	if c == nil {
+		return 0
+	}
+
+	var size uint64
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.mu.Lock()
+		size += s.size
+		s.mu.Unlock()
+	}
+	return size
+}
+
+// blockCacheFilePath returns the path of the TSM file a file of the file store
+// belongs to, such as the file itself, its tombstone or temporary files.
+func blockCacheFilePath(path string) string {
+	path = strings.TrimSuffix(path, "."+CompactionTempExtension)
+	path = strings.TrimSuffix(path, filepath.Ext(path))
+	return path + "." + TSMFileExtension
+}
+
+// blockCacheObserver evicts the blocks of the files observed to be finished or
+// unlinked before notifying the wrapped observer.
+type blockCacheObserver struct {
+	FileStoreObserver
+	cache *BlockCache
+}
+
+func (o blockCacheObserver) FileFinishing(path string) error {
+	o.cache.evictFile(blockCacheFilePath(path))
+	return o.FileStoreObserver.FileFinishing(path)
+}
+
+func (o blockCacheObserver) FileUnlinking(path string) error {
+	o.cache.evictFile(blockCacheFilePath(path))
+	return o.FileStoreObserver.FileUnlinking(path)
+}
+
+// blockCacheTracker tracks the usage of the block cache.
+//
+// As well as being responsible for providing atomic reads and writes to the
+// statistics, blockCacheTracker also mirrors any changes to the external
+// prometheus metrics, which the Engine exposes.
+type blockCacheTracker struct {
+	metrics *blockCacheMetrics
+	labels  prometheus.Labels
+
+	// Used in testing.
+	memSizeBytes uint64
+	hits         uint64
+	misses       uint64
+	evictions    uint64
+}
+
+func newBlockCacheTracker(metrics *blockCacheMetrics, defaultLabels prometheus.Labels) *blockCacheTracker {
+	return &blockCacheTracker{metrics: metrics, labels: defaultLabels}
+}
+
+// Labels returns a copy of the default labels used by the tracker's metrics.
+// The returned map is safe for modification.
+func (t *blockCacheTracker) Labels() prometheus.Labels {
+	labels := make(prometheus.Labels, len(t.labels))
+	for k, v := range t.labels {
+		labels[k] = v
+	}
+	return labels
+}
+
+// AddMemBytes increases the number of bytes of decoded blocks.
+func (t *blockCacheTracker) AddMemBytes(bytes uint64) {
+	if bytes == 0 {
+		return
+	}
+	atomic.AddUint64(&t.memSizeBytes, bytes)
+	t.metrics.MemSize.With(t.labels).Add(float64(bytes))
+}
+
+// SubMemBytes decreases the number of bytes of decoded blocks.
+func (t *blockCacheTracker) SubMemBytes(bytes uint64) {
+	if bytes == 0 {
+		return
+	}
+	atomic.AddUint64(&t.memSizeBytes, ^(bytes - 1))
+	t.metrics.MemSize.With(t.labels).Sub(float64(bytes))
+}
+
+// IncHits increases the number of blocks read from the cache.
+func (t *blockCacheTracker) IncHits() {
+	atomic.AddUint64(&t.hits, 1)
+	t.metrics.Hits.With(t.labels).Inc()
+}
+
+// IncMisses increases the number of blocks missing from the cache.
+func (t *blockCacheTracker) IncMisses() {
+	atomic.AddUint64(&t.misses, 1)
+	t.metrics.Misses.With(t.labels).Inc()
+}
+
+// AddEvictions increases the number of blocks evicted from the cache.
+func (t *blockCacheTracker) AddEvictions(n int) {
+	if n == 0 {
+		return
+	}
+	atomic.AddUint64(&t.evictions, uint64(n))
+	t.metrics.Evictions.With(t.labels).Add(float64(n))
+}
@@ -0,0 +1,165 @@
+package tsm1
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+// sameShardOffsets returns n offsets of blocks of path held by the same shard of c.
+func sameShardOffsets(c *BlockCache, path string, n int) []int64 {
+	target := c.shard(blockCacheKey{path: path})
+	var offsets []int64
+	for off := int64(0); len(offsets) < n; off++ {
+		if c.shard(blockCacheKey{path: path, offset: off}) == target {
+			offsets = append(offsets, off)
+		}
+	}
+	return offsets
+}
+
+func TestBlockCache_LRU(t *testing.T) {
+	c := NewBlockCache(blockCacheShards * 100)
+	offsets := sameShardOffsets(c, "a.tsm", 3)
+
+	c.put("a.tsm", offsets[0], 0, 40)
+	c.put("a.tsm", offsets[1], 1, 40)
+	if _, ok := c.get("a.tsm", offsets[0]); !ok {
+		t.Fatal("expected block to be cached")
+	}
+
+	// The least recently used block is evicted to make room.
+	c.put("a.tsm", offsets[2], 2, 40)
+	if _, ok := c.get("a.tsm", offsets[1]); ok {
+		t.Fatal("expected least recently used block to be evicted")
+	}
+	for _, i := range []int{0, 2} {
+		if v, ok := c.get("a.tsm", offsets[i]); !ok || v.(int) != i {
+			t.Fatalf("unexpected block %d: %v, %v", i, v, ok)
+		}
+	}
+
+	if got, exp := c.Size(), uint64(80); got != exp {
+		t.Fatalf("size mismatch: got %v, exp %v", got, exp)
+	}
+	if got, exp := c.tracker.evictions, uint64(1); got != exp {
+		t.Fatalf("evictions mismatch: got %v, exp %v", got, exp)
+	}
+	if got, exp := c.tracker.memSizeBytes, uint64(80); got != exp {
+		t.Fatalf("tracked size mismatch: got %v, exp %v", got, exp)
+	}
+
+	// Blocks larger than a shard are not cached.
+	c.put("b.tsm", 0, 3, 101)
+	if _, ok := c.get("b.tsm", 0); ok {
+		t.Fatal("expected block larger than the cache not to be cached")
+	}
+}
+
+func TestBlockCache_EvictFile(t *testing.T) {
+	c := NewBlockCache(1 << 20)
+	for off := int64(0); off < 100; off++ {
+		c.put("a.tsm", off, off, 10)
+		c.put("b.tsm", off, off, 10)
+	}
+
+	c.evictFile("a.tsm")
+	if got, exp := c.Size(), uint64(1000); got != exp {
+		t.Fatalf("size mismatch: got %v, exp %v", got, exp)
+	}
+	if _, ok := c.get("a.tsm", 5); ok {
+		t.Fatal("expected blocks of evicted file to be removed")
+	}
+	if _, ok := c.get("b.tsm", 5); !ok {
+		t.Fatal("expected blocks of other files to be kept")
+	}
+}
+
+func TestBlockCache_Nil(t *testing.T) {
+	var c *BlockCache
+	c.put("a.tsm", 0, 0, 10)
+	if _, ok := c.get("a.tsm", 0); ok {
+		t.Fatal("expected nil cache to cache nothing")
+	}
+	c.evictFile("a.tsm")
+}
+
+func TestBlockCacheFilePath(t *testing.T) {
+	for _, path := range []string{
+		"/data/000000001-000000002.tsm",
+		"/data/000000001-000000002.tsm.tmp",
+		"/data/000000001-000000002.tombstone",
+		"/data/000000001-000000002.tombstone.tmp",
+		"/data/000000001-000000002.tss",
+	} {
+		if got, exp := blockCacheFilePath(path), "/data/000000001-000000002.tsm"; got != exp {
+			t.Errorf("%s: got %v, exp %v", path, got, exp)
+		}
+	}
+}
+
+func TestKeyCursor_BlockCache(t *testing.T) {
+	dir := mustTempDir()
+	defer os.RemoveAll(dir)
+	fs := NewFileStore(dir)
+	cache := NewBlockCache(1 << 20)
+	fs.WithBlockCache(cache)
+	defer fs.Close()
+
+	files := []string{
+		mustWriteSummaryFile(t, dir, 1, false, summaryKeyValues{"cpu", [][]Value{floatValues(0, 9)}}),
+		mustWriteSummaryFile(t, dir, 2, false, summaryKeyValues{"cpu", [][]Value{floatValues(10, 19)}}),
+	}
+	fatalIfErr(t, "replacing files", fs.Replace(nil, files))
+
+	read := func() []float64 {
+		var values []float64
+		c := fs.KeyCursor(context.Background(), []byte("cpu"), 0, true)
+		defer c.Close()
+		for {
+			a, err := c.ReadFloatArrayBlock(tsdb.NewFloatArrayLen(MaxPointsPerBlock))
+			fatalIfErr(t, "reading block", err)
+			if a.Len() == 0 {
+				return values
+			}
+			values = append(values, a.Values...)
+
+			// Modifying the values read must not modify the cache.
+			a.Values[0] = -1
+			c.Next()
+		}
+	}
+
+	exp := read()
+	if got := read(); !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected cached values: got %v, exp %v", got, exp)
+	}
+	if got, exp := len(exp), 20; got != exp {
+		t.Fatalf("values length mismatch: got %v, exp %v", got, exp)
+	}
+	if got, exp := cache.tracker.hits, uint64(2); got != exp {
+		t.Fatalf("hits mismatch: got %v, exp %v", got, exp)
+	}
+	if got, exp := cache.tracker.misses, uint64(2); got != exp {
+		t.Fatalf("misses mismatch: got %v, exp %v", got, exp)
+	}
+
+	// Writing a tombstone evicts the blocks of the file.
+	fatalIfErr(t, "deleting range", fs.DeleteRange([][]byte{[]byte("cpu")}, 5, 5))
+	if got, exp := cache.Size(), uint64(160); got != exp {
+		t.Fatalf("size mismatch after delete: got %v, exp %v", got, exp)
+	}
+
+	// Removing a file evicts its blocks.
+	fatalIfErr(t, "replacing files", fs.Replace(files[1:], nil))
+	if got, exp := cache.Size(), uint64(0); got != exp {
+		t.Fatalf("size mismatch after replace: got %v, exp %v", got, exp)
+	}
+
+	if got, exp := len(read()), 9; got != exp {
+		t.Fatalf("values length mismatch after delete: got %v, exp %v", got, exp)
+	}
+}
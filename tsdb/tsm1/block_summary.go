@@ -0,0 +1,433 @@
+package tsm1
+
+/*
+Block summaries are an optional extension of the index recording summary
+statistics of each block, so that aggregates over whole blocks can be computed
+without decoding them.  The summaries are written between the blocks and the
+index, where they are ignored by readers that do not know of them, and end
+with a trailer identifying them.
+
+┌──────────────────────────────────┬─────────┬─────────┬─────────┬─────────┐
+│            Summaries             │  CRC32  │ Length  │  Magic  │ Version │
+│        N × 56 bytes              │ 4 bytes │ 8 bytes │ 4 bytes │ 1 byte  │
+└──────────────────────────────────┴─────────┴─────────┴─────────┴─────────┘
+
+Each summary is identified by the offset of its block, and summaries are
+ordered by offset.  The CRC32 covers the summaries, and the length is the
+size in bytes of the summaries.
+
+┌──────────────────────────────────────────────────────────────────────┐
+│                               Summary                                │
+├─────────┬─────────┬─────────┬─────────┬─────────┬─────────┬──────────┤
+│ Offset  │  Count  │   Min   │   Max   │   Sum   │  First  │   Last   │
+│ 8 bytes │ 8 bytes │ 8 bytes │ 8 bytes │ 8 bytes │ 8 bytes │ 8 bytes  │
+└─────────┴─────────┴─────────┴─────────┴─────────┴─────────┴──────────┘
+*/
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"hash"
+	"hash/crc32"
+	"io"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+)
+
+const (
+	// BlockSummariesMagicNumber is written in the trailer of the block
+	// summaries to identify them.
+	BlockSummariesMagicNumber uint32 = 0x16D1B5D1
+
+	// BlockSummariesVersion indicates the version of the block summaries format.
+	BlockSummariesVersion byte = 1
+
+	// Size in bytes of a block summary
+	blockSummarySize = 56
+
+	// Size in bytes of the block summaries trailer
+	blockSummariesTrailerSize = 17
+)
+
+// BlockSummary holds summary statistics of the values of a block.
+//
+// Min, Max, Sum, First and Last hold the bits of values of the type of the
+// block: the IEEE 754 bits of floats, the bits of integers and unsigned
+// integers, and 0 or 1 for booleans, whose Sum is the number of true values.
+// They are zero for strings.
+type BlockSummary struct {
+	Count                      int64
+	Min, Max, Sum, First, Last uint64
+}
+
+// FloatMin returns the minimum value of a float block.
+func (s BlockSummary) FloatMin() float64 { return math.Float64frombits(s.Min) }
+
+// FloatMax returns the maximum value of a float block.
+func (s BlockSummary) FloatMax() float64 { return math.Float64frombits(s.Max) }
+
+// FloatSum returns the sum of the values of a float block.
+func (s BlockSummary) FloatSum() float64 { return math.Float64frombits(s.Sum) }
+
+// FloatFirst returns the first value of a float block.
+func (s BlockSummary) FloatFirst() float64 { return math.Float64frombits(s.First) }
+
+// FloatLast returns the last value of a float block.
+func (s BlockSummary) FloatLast() float64 { return math.Float64frombits(s.Last) }
+
+func (s BlockSummary) arraySummary(minTime, maxTime int64) cursors.ArraySummary {
+	return cursors.ArraySummary{MinTime: minTime, MaxTime: maxTime, Count: s.Count}
+}
+
+func (s BlockSummary) floatArraySummary(minTime, maxTime int64) cursors.FloatArraySummary {
+	return cursors.FloatArraySummary{
+		ArraySummary: s.arraySummary(minTime, maxTime),
+		Min:          s.FloatMin(),
+		Max:          s.FloatMax(),
+		Sum:          s.FloatSum(),
+		First:        s.FloatFirst(),
+		Last:         s.FloatLast(),
+	}
+}
+
+func (s BlockSummary) integerArraySummary(minTime, maxTime int64) cursors.IntegerArraySummary {
+	return cursors.IntegerArraySummary{
+		ArraySummary: s.arraySummary(minTime, maxTime),
+		Min:          int64(s.Min),
+		Max:          int64(s.Max),
+		Sum:          int64(s.Sum),
+		First:        int64(s.First),
+		Last:         int64(s.Last),
+	}
+}
+
+func (s BlockSummary) unsignedArraySummary(minTime, maxTime int64) cursors.UnsignedArraySummary {
+	return cursors.UnsignedArraySummary{
+		ArraySummary: s.arraySummary(minTime, maxTime),
+		Min:          s.Min,
+		Max:          s.Max,
+		Sum:          s.Sum,
+		First:        s.First,
+		Last:         s.Last,
+	}
+}
+
+func summarizeFloatArray(a *tsdb.FloatArray) BlockSummary {
+	vs := a.Values
+	min, max, sum := vs[0], vs[0], 0.0
+	for _, v := range vs {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+		sum += v
+	}
+	return BlockSummary{
+		Count: int64(len(vs)),
+		Min:   math.Float64bits(min),
+		Max:   math.Float64bits(max),
+		Sum:   math.Float64bits(sum),
+		First: math.Float64bits(vs[0]),
+		Last:  math.Float64bits(vs[len(vs)-1]),
+	}
+}
+
+func summarizeIntegerArray(a *tsdb.IntegerArray) BlockSummary {
+	vs := a.Values
+	min, max, sum := vs[0], vs[0], int64(0)
+	for _, v := range vs {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+		sum += v
+	}
+	return BlockSummary{
+		Count: int64(len(vs)),
+		Min:   uint64(min),
+		Max:   uint64(max),
+		Sum:   uint64(sum),
+		First: uint64(vs[0]),
+		Last:  uint64(vs[len(vs)-1]),
+	}
+}
+
+func summarizeUnsignedArray(a *tsdb.UnsignedArray) BlockSummary {
+	vs := a.Values
+	min, max, sum := vs[0], vs[0], uint64(0)
+	for _, v := range vs {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+		sum += v
+	}
+	return BlockSummary{
+		Count: int64(len(vs)),
+		Min:   min,
+		Max:   max,
+		Sum:   sum,
+		First: vs[0],
+		Last:  vs[len(vs)-1],
+	}
+}
+
+func summarizeBooleanArray(a *tsdb.BooleanArray) BlockSummary {
+	vs := a.Values
+	bit := func(v bool) uint64 {
+		if v {
+			return 1
+		}
+		return 0
+	}
+
+	s := BlockSummary{
+		Count: int64(len(vs)),
+		Min:   1,
+		First: bit(vs[0]),
+		Last:  bit(vs[len(vs)-1]),
+	}
+	for _, v := range vs {
+		s.Sum += bit(v)
+	}
+	if s.Sum < uint64(len(vs)) {
+		s.Min = 0
+	}
+	if s.Sum > 0 {
+		s.Max = 1
+	}
+	return s
+}
+
+// blockSummarizer computes the summaries of encoded blocks, reusing its
+// buffers between blocks.
+type blockSummarizer struct {
+	floats   tsdb.FloatArray
+	integers tsdb.IntegerArray
+	unsigned tsdb.UnsignedArray
+	booleans tsdb.BooleanArray
+}
+
+func (b *blockSummarizer) summarize(block []byte) (BlockSummary, error) {
+	blockType, err := BlockType(block)
+	if err != nil {
+		return BlockSummary{}, err
+	}
+
+	switch blockType {
+	case BlockFloat64:
+		err = DecodeFloatArrayBlock(block, &b.floats)
+		if err == nil && b.floats.Len() > 0 {
+			return summarizeFloatArray(&b.floats), nil
+		}
+	case BlockInteger:
+		err = DecodeIntegerArrayBlock(block, &b.integers)
+		if err == nil && b.integers.Len() > 0 {
+			return summarizeIntegerArray(&b.integers), nil
+		}
+	case BlockUnsigned:
+		err = DecodeUnsignedArrayBlock(block, &b.unsigned)
+		if err == nil && b.unsigned.Len() > 0 {
+			return summarizeUnsignedArray(&b.unsigned), nil
+		}
+	case BlockBoolean:
+		err = DecodeBooleanArrayBlock(block, &b.booleans)
+		if err == nil && b.booleans.Len() > 0 {
+			return summarizeBooleanArray(&b.booleans), nil
+		}
+	case BlockString:
+		// Strings are only counted, which does not require decoding them.
+		return BlockSummary{Count: int64(BlockCount(block))}, nil
+	}
+	return BlockSummary{}, err
+}
+
+// summarizeValues returns the summary of the block of values, which must all be
+// of the same type, without encoding it.
+func (b *blockSummarizer) summarizeValues(values Values) BlockSummary {
+	switch values[0].(type) {
+	case FloatValue:
+		b.floats.Values = b.floats.Values[:0]
+		for _, v := range values {
+			b.floats.Values = append(b.floats.Values, v.(FloatValue).RawValue())
+		}
+		return summarizeFloatArray(&b.floats)
+	case IntegerValue:
+		b.integers.Values = b.integers.Values[:0]
+		for _, v := range values {
+			b.integers.Values = append(b.integers.Values, v.(IntegerValue).RawValue())
+		}
+		return summarizeIntegerArray(&b.integers)
+	case UnsignedValue:
+		b.unsigned.Values = b.unsigned.Values[:0]
+		for _, v := range values {
+			b.unsigned.Values = append(b.unsigned.Values, v.(UnsignedValue).RawValue())
+		}
+		return summarizeUnsignedArray(&b.unsigned)
+	case BooleanValue:
+		b.booleans.Values = b.booleans.Values[:0]
+		for _, v := range values {
+			b.booleans.Values = append(b.booleans.Values, v.(BooleanValue).RawValue())
+		}
+		return summarizeBooleanArray(&b.booleans)
+	}
+	return BlockSummary{Count: int64(len(values))}
+}
+
+// blockSummaryBuffer holds the block summaries of a TSM file being written until
+// they are written ahead of its index.  The summaries are buffered in memory, or
+// in a temporary file so that those of large files are not held in memory.
+type blockSummaryBuffer struct {
+	fd  *os.File
+	buf *bytes.Buffer
+	w   *bufio.Writer
+
+	// The checksum and size in bytes of the summaries added.
+	crc hash.Hash32
+	n   int64
+}
+
+// newBlockSummaryBuffer returns a blockSummaryBuffer buffering the summaries in memory.
+func newBlockSummaryBuffer() *blockSummaryBuffer {
+	buf := new(bytes.Buffer)
+	return &blockSummaryBuffer{buf: buf, w: bufio.NewWriter(buf), crc: crc32.NewIEEE()}
+}
+
+// newDiskBlockSummaryBuffer returns a blockSummaryBuffer buffering the summaries in f.
+func newDiskBlockSummaryBuffer(f *os.File) *blockSummaryBuffer {
+	return &blockSummaryBuffer{fd: f, w: bufio.NewWriterSize(f, 1024*1024), crc: crc32.NewIEEE()}
+}
+
+// add adds the summary s of the block at offset.
+func (b *blockSummaryBuffer) add(offset int64, s BlockSummary) error {
+	var buf [blockSummarySize]byte
+	binary.BigEndian.PutUint64(buf[0:8], uint64(offset))
+	binary.BigEndian.PutUint64(buf[8:16], uint64(s.Count))
+	binary.BigEndian.PutUint64(buf[16:24], s.Min)
+	binary.BigEndian.PutUint64(buf[24:32], s.Max)
+	binary.BigEndian.PutUint64(buf[32:40], s.Sum)
+	binary.BigEndian.PutUint64(buf[40:48], s.First)
+	binary.BigEndian.PutUint64(buf[48:56], s.Last)
+
+	b.crc.Write(buf[:])
+	b.n += blockSummarySize
+	_, err := b.w.Write(buf[:])
+	return err
+}
+
+// Size returns the size in bytes of the summaries added and their trailer.
+func (b *blockSummaryBuffer) Size() int64 {
+	if b.n == 0 {
+		return 0
+	}
+	return b.n + blockSummariesTrailerSize
+}
+
+// WriteTo writes the summaries added followed by their trailer to w.  Nothing is
+// written if no summaries were added.
+func (b *blockSummaryBuffer) WriteTo(w io.Writer) (int64, error) {
+	if b.n == 0 {
+		return 0, nil
+	}
+
+	if err := b.w.Flush(); err != nil {
+		return 0, err
+	}
+
+	var r io.Reader = b.buf
+	if b.fd != nil {
+		if _, err := b.fd.Seek(0, io.SeekStart); err != nil {
+			return 0, err
+		}
+		r = bufio.NewReaderSize(b.fd, 1024*1024)
+	}
+
+	n, err := io.Copy(w, r)
+	if err != nil {
+		return n, err
+	}
+
+	var trailer [blockSummariesTrailerSize]byte
+	binary.BigEndian.PutUint32(trailer[0:4], b.crc.Sum32())
+	binary.BigEndian.PutUint64(trailer[4:12], uint64(b.n))
+	binary.BigEndian.PutUint32(trailer[12:16], BlockSummariesMagicNumber)
+	trailer[16] = BlockSummariesVersion
+	nn, err := w.Write(trailer[:])
+	return n + int64(nn), err
+}
+
+// Remove removes the temporary file buffering the summaries, if any.
+func (b *blockSummaryBuffer) Remove() error {
+	if b.fd == nil {
+		return nil
+	}
+
+	// Close the file handle to prevent leaking.  We ignore the error because
+	// we just want to cleanup and remove the file.
+	_ = b.fd.Close()
+
+	err := os.Remove(b.fd.Name())
+	b.fd = nil
+	return err
+}
+
+// findBlockSummaries returns the position of the block summaries of the
+// TSM file b whose index starts at indexStart, and their length. It returns
+// a zero length if the file has no valid summaries.
+func findBlockSummaries(b []byte, indexStart int64) (start, n int64) {
+	// The summaries follow the file header.
+	trailer := indexStart - blockSummariesTrailerSize
+	if trailer < 5 || indexStart > int64(len(b)) {
+		return 0, 0
+	}
+
+	t := b[trailer:indexStart]
+	if binary.BigEndian.Uint32(t[12:16]) != BlockSummariesMagicNumber || t[16] != BlockSummariesVersion {
+		return 0, 0
+	}
+
+	n = int64(binary.BigEndian.Uint64(t[4:12]))
+	if n < 0 || n%blockSummarySize != 0 || n > trailer-5 {
+		return 0, 0
+	}
+	start = trailer - n
+	if crc32.ChecksumIEEE(b[start:trailer]) != binary.BigEndian.Uint32(t[0:4]) {
+		return 0, 0
+	}
+	return start, n
+}
+
+// searchBlockSummary returns the summary of the block at offset in summaries.
+func searchBlockSummary(summaries []byte, offset int64) (BlockSummary, bool) {
+	n := len(summaries) / blockSummarySize
+	i := sort.Search(n, func(i int) bool {
+		return int64(binary.BigEndian.Uint64(summaries[i*blockSummarySize:])) >= offset
+	})
+	if i == n {
+		return BlockSummary{}, false
+	}
+
+	b := summaries[i*blockSummarySize : (i+1)*blockSummarySize]
+	if int64(binary.BigEndian.Uint64(b[0:8])) != offset {
+		return BlockSummary{}, false
+	}
+	return BlockSummary{
+		Count: int64(binary.BigEndian.Uint64(b[8:16])),
+		Min:   binary.BigEndian.Uint64(b[16:24]),
+		Max:   binary.BigEndian.Uint64(b[24:32]),
+		Sum:   binary.BigEndian.Uint64(b[32:40]),
+		First: binary.BigEndian.Uint64(b[40:48]),
+		Last:  binary.BigEndian.Uint64(b[48:56]),
+	}, true
+}
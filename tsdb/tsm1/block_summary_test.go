@@ -0,0 +1,348 @@
+package tsm1
+
+import (
+	"context"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/influxdata/influxdb/tsdb/cursors"
+)
+
+type summaryKeyValues struct {
+	key    string
+	blocks [][]Value
+}
+
+// mustWriteSummaryFile writes the blocks of values as TSM file id in dir and
+// returns its path.
+func mustWriteSummaryFile(t *testing.T, dir string, id int, summaries bool, data ...summaryKeyValues) string {
+	t.Helper()
+
+	f := mustTempFile(dir)
+	w, err := NewTSMWriter(f, WithBlockSummaries(summaries))
+	fatalIfErr(t, "creating writer", err)
+
+	for _, d := range data {
+		for _, values := range d.blocks {
+			fatalIfErr(t, "writing", w.Write([]byte(d.key), values))
+		}
+	}
+	fatalIfErr(t, "writing index", w.WriteIndex())
+	fatalIfErr(t, "closing", w.Close())
+
+	name := filepath.Join(dir, DefaultFormatFileName(id, 1)+".tsm")
+	fatalIfErr(t, "renaming", os.Rename(f.Name(), name))
+	return name
+}
+
+func floatValues(min, max int64) []Value {
+	var values []Value
+	for i := min; i <= max; i++ {
+		values = append(values, NewValue(i, float64(i)))
+	}
+	return values
+}
+
+func TestTSMReader_BlockSummary(t *testing.T) {
+	dir := mustTempDir()
+	defer os.RemoveAll(dir)
+
+	data := []summaryKeyValues{
+		{"bool", [][]Value{{NewValue(0, false), NewValue(1, true), NewValue(2, false)}}},
+		{"cpu", [][]Value{floatValues(0, 9), floatValues(10, 14)}},
+		{"mem", [][]Value{{NewValue(0, int64(3)), NewValue(1, int64(-4)), NewValue(2, int64(5))}}},
+		{"str", [][]Value{{NewValue(0, "a"), NewValue(1, "b")}}},
+		{"uint", [][]Value{{NewValue(0, uint64(7)), NewValue(1, uint64(2))}}},
+	}
+	exp := map[string][]BlockSummary{
+		"bool": {{Count: 3, Min: 0, Max: 1, Sum: 1, First: 0, Last: 0}},
+		"cpu": {
+			summarizeFloatArray(&cursors.FloatArray{Values: []float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}}),
+			summarizeFloatArray(&cursors.FloatArray{Values: []float64{10, 11, 12, 13, 14}}),
+		},
+		"mem":  {{Count: 3, Min: uint64(1<<64 - 4), Max: 5, Sum: 4, First: 3, Last: 5}},
+		"str":  {{Count: 2}},
+		"uint": {{Count: 2, Min: 2, Max: 7, Sum: 9, First: 7, Last: 2}},
+	}
+
+	f, err := os.Open(mustWriteSummaryFile(t, dir, 1, true, data...))
+	fatalIfErr(t, "opening file", err)
+	r, err := NewTSMReader(f)
+	fatalIfErr(t, "creating reader", err)
+	defer r.Close()
+
+	for key, summaries := range exp {
+		entries, err := r.ReadEntries([]byte(key), nil)
+		fatalIfErr(t, "reading entries", err)
+		if got, exp := len(entries), len(summaries); got != exp {
+			t.Fatalf("%s: entries length mismatch: got %v, exp %v", key, got, exp)
+		}
+		for i := range entries {
+			s, ok := r.BlockSummary(&entries[i])
+			if !ok {
+				t.Fatalf("%s: missing summary of block %d", key, i)
+			}
+			if !reflect.DeepEqual(s, summaries[i]) {
+				t.Fatalf("%s: summary mismatch of block %d: got %+v, exp %+v", key, i, s, summaries[i])
+			}
+		}
+	}
+
+	if got, exp := exp["cpu"][0].FloatSum(), 45.0; got != exp {
+		t.Fatalf("sum mismatch: got %v, exp %v", got, exp)
+	}
+
+	values, err := r.ReadAll([]byte("cpu"))
+	fatalIfErr(t, "reading values", err)
+	if got, exp := len(values), 15; got != exp {
+		t.Fatalf("values length mismatch: got %v, exp %v", got, exp)
+	}
+}
+
+func TestTSMReader_BlockSummary_Missing(t *testing.T) {
+	dir := mustTempDir()
+	defer os.RemoveAll(dir)
+
+	data := summaryKeyValues{"cpu", [][]Value{floatValues(0, 9)}}
+	f, err := os.Open(mustWriteSummaryFile(t, dir, 1, false, data))
+	fatalIfErr(t, "opening file", err)
+	r, err := NewTSMReader(f)
+	fatalIfErr(t, "creating reader", err)
+	defer r.Close()
+
+	entries, err := r.ReadEntries([]byte("cpu"), nil)
+	fatalIfErr(t, "reading entries", err)
+	if _, ok := r.BlockSummary(&entries[0]); ok {
+		t.Fatal("expected no summary")
+	}
+}
+
+func TestTSMReader_BlockSummary_Corrupt(t *testing.T) {
+	dir := mustTempDir()
+	defer os.RemoveAll(dir)
+
+	data := summaryKeyValues{"cpu", [][]Value{floatValues(0, 9)}}
+	name := mustWriteSummaryFile(t, dir, 1, true, data)
+
+	b, err := ioutil.ReadFile(name)
+	fatalIfErr(t, "reading file", err)
+
+	// Corrupt the count of the summary, which is checked by the CRC.
+	indexStart := int64(binary.BigEndian.Uint64(b[len(b)-8:]))
+	b[indexStart-blockSummariesTrailerSize-blockSummarySize+15]++
+	fatalIfErr(t, "writing file", ioutil.WriteFile(name, b, 0666))
+
+	f, err := os.Open(name)
+	fatalIfErr(t, "opening file", err)
+	r, err := NewTSMReader(f)
+	fatalIfErr(t, "creating reader", err)
+	defer r.Close()
+
+	entries, err := r.ReadEntries([]byte("cpu"), nil)
+	fatalIfErr(t, "reading entries", err)
+	if _, ok := r.BlockSummary(&entries[0]); ok {
+		t.Fatal("expected no summary")
+	}
+
+	values, err := r.ReadAll([]byte("cpu"))
+	fatalIfErr(t, "reading values", err)
+	if got, exp := len(values), 10; got != exp {
+		t.Fatalf("values length mismatch: got %v, exp %v", got, exp)
+	}
+}
+
+func TestTSMWriter_BlockSummaries_DiskBuffer(t *testing.T) {
+	dir := mustTempDir()
+	defer os.RemoveAll(dir)
+
+	tmp := filepath.Join(dir, DefaultFormatFileName(1, 1)+".tsm.tmp")
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_RDWR|os.O_EXCL, 0666)
+	fatalIfErr(t, "creating file", err)
+	w, err := NewTSMWriterWithDiskBuffer(f, WithBlockSummaries(true))
+	fatalIfErr(t, "creating writer", err)
+
+	block, err := Values{NewValue(0, int64(5)), NewValue(1, int64(-2))}.Encode(nil)
+	fatalIfErr(t, "encoding block", err)
+	fatalIfErr(t, "writing", w.Write([]byte("cpu"), floatValues(0, 9)))
+	fatalIfErr(t, "writing block", w.WriteBlock([]byte("mem"), 0, 1, block))
+	fatalIfErr(t, "writing index", w.WriteIndex())
+	fatalIfErr(t, "closing", w.Close())
+
+	// The summaries were buffered in a temporary file that is removed.
+	if _, err := os.Stat(filepath.Join(dir, DefaultFormatFileName(1, 1)+".sum.tmp")); !os.IsNotExist(err) {
+		t.Fatalf("expected summaries buffer to be removed, got %v", err)
+	}
+
+	f, err = os.Open(tmp)
+	fatalIfErr(t, "opening file", err)
+	r, err := NewTSMReader(f)
+	fatalIfErr(t, "creating reader", err)
+	defer r.Close()
+
+	exp := map[string]BlockSummary{
+		"cpu": summarizeFloatArray(&cursors.FloatArray{Values: []float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}}),
+		"mem": summarizeIntegerArray(&cursors.IntegerArray{Values: []int64{5, -2}}),
+	}
+	for key, summary := range exp {
+		entries, err := r.ReadEntries([]byte(key), nil)
+		fatalIfErr(t, "reading entries", err)
+		if s, ok := r.BlockSummary(&entries[0]); !ok {
+			t.Fatalf("%s: missing summary", key)
+		} else if !reflect.DeepEqual(s, summary) {
+			t.Fatalf("%s: summary mismatch: got %+v, exp %+v", key, s, summary)
+		}
+	}
+}
+
+func TestKeyCursor_ReadBlockSummary(t *testing.T) {
+	dir := mustTempDir()
+	defer os.RemoveAll(dir)
+	fs := NewFileStore(dir)
+	defer fs.Close()
+
+	files := []string{
+		mustWriteSummaryFile(t, dir, 1, true, summaryKeyValues{"cpu", [][]Value{floatValues(0, 9), floatValues(10, 19)}}),
+		mustWriteSummaryFile(t, dir, 2, true, summaryKeyValues{"cpu", [][]Value{floatValues(15, 16), floatValues(30, 39)}}),
+		mustWriteSummaryFile(t, dir, 3, true, summaryKeyValues{"cpu", [][]Value{floatValues(40, 49)}}),
+	}
+	fatalIfErr(t, "replacing files", fs.Replace(nil, files))
+	fatalIfErr(t, "deleting range", fs.DeleteRange([][]byte{[]byte("cpu")}, 45, 45))
+
+	c := fs.KeyCursor(context.Background(), []byte("cpu"), 0, true)
+	defer c.Close()
+
+	// The block holds more values than requested.
+	if _, _, _, ok := c.ReadBlockSummary(0, 100, 5); ok {
+		t.Fatal("expected no summary of a block with too many values")
+	}
+	// The block is not within the time range.
+	if _, _, _, ok := c.ReadBlockSummary(0, 5, 100); ok {
+		t.Fatal("expected no summary of a block beyond the time range")
+	}
+
+	min, max, s, ok := c.ReadBlockSummary(0, 100, 100)
+	if !ok {
+		t.Fatal("expected summary of the first block")
+	}
+	if min != 0 || max != 9 || s.Count != 10 || s.FloatSum() != 45 {
+		t.Fatalf("unexpected summary [%d, %d] %+v", min, max, s)
+	}
+
+	// The second block overlaps a block of the second file.
+	c.Next()
+	if _, _, _, ok := c.ReadBlockSummary(0, 100, 100); ok {
+		t.Fatal("expected no summary of an overlapped block")
+	}
+	buf := make([]FloatValue, 1000)
+	values, err := c.ReadFloatBlock(&buf)
+	fatalIfErr(t, "reading values", err)
+	if got, exp := len(values), 10; got != exp {
+		t.Fatalf("values length mismatch: got %v, exp %v", got, exp)
+	}
+
+	c.Next()
+	if _, _, s, ok := c.ReadBlockSummary(0, 100, 100); !ok || s.Count != 10 {
+		t.Fatalf("expected summary of the third block, got %+v", s)
+	}
+
+	// The last block has a tombstone.
+	c.Next()
+	if _, _, _, ok := c.ReadBlockSummary(0, 100, 100); ok {
+		t.Fatal("expected no summary of a deleted block")
+	}
+	values, err = c.ReadFloatBlock(&buf)
+	fatalIfErr(t, "reading values", err)
+	if got, exp := len(values), 9; got != exp {
+		t.Fatalf("values length mismatch: got %v, exp %v", got, exp)
+	}
+}
+
+func TestFloatArrayAscendingCursor_NextSummary(t *testing.T) {
+	dir := mustTempDir()
+	defer os.RemoveAll(dir)
+	fs := NewFileStore(dir)
+	defer fs.Close()
+
+	files := []string{
+		mustWriteSummaryFile(t, dir, 1, true, summaryKeyValues{"cpu", [][]Value{floatValues(0, 9), floatValues(10, 19), floatValues(20, 29)}}),
+	}
+	fatalIfErr(t, "replacing files", fs.Replace(nil, files))
+
+	cur := newFloatArrayAscendingCursor()
+	cur.reset(5, 100, nil, fs.KeyCursor(context.Background(), []byte("cpu"), 5, true))
+	defer cur.Close()
+
+	// The first block was partially read by seeking.
+	if _, ok := cur.NextSummary(1000); ok {
+		t.Fatal("expected no summary of a partially read block")
+	}
+	a := cur.Next()
+	if got, exp := a.Timestamps, []int64{5, 6, 7, 8, 9}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected timestamps: got %v, exp %v", got, exp)
+	}
+
+	s, ok := cur.NextSummary(1000)
+	if !ok {
+		t.Fatal("expected summary of the second block")
+	}
+	exp := cursors.FloatArraySummary{
+		ArraySummary: cursors.ArraySummary{MinTime: 10, MaxTime: 19, Count: 10},
+		Min:          10, Max: 19, Sum: 145, First: 10, Last: 19,
+	}
+	if !reflect.DeepEqual(s, exp) {
+		t.Fatalf("unexpected summary: got %+v, exp %+v", s, exp)
+	}
+
+	// Values are read once a summary was refused.
+	if _, ok := cur.NextSummary(5); ok {
+		t.Fatal("expected no summary of a block with too many values")
+	}
+	a = cur.Next()
+	if got, exp := a.Len(), 10; got != exp {
+		t.Fatalf("values length mismatch: got %v, exp %v", got, exp)
+	}
+
+	if _, ok := cur.NextSummary(1000); ok {
+		t.Fatal("expected no summary at the end of the cursor")
+	}
+	if a = cur.Next(); a.Len() != 0 {
+		t.Fatalf("expected no more values, got %d", a.Len())
+	}
+}
+
+func TestFloatArrayAscendingCursor_NextSummary_Cache(t *testing.T) {
+	dir := mustTempDir()
+	defer os.RemoveAll(dir)
+	fs := NewFileStore(dir)
+	defer fs.Close()
+
+	files := []string{
+		mustWriteSummaryFile(t, dir, 1, true, summaryKeyValues{"cpu", [][]Value{floatValues(0, 9), floatValues(10, 19)}}),
+	}
+	fatalIfErr(t, "replacing files", fs.Replace(nil, files))
+
+	cur := newFloatArrayAscendingCursor()
+	cache := Values{NewValue(-1, 100.0)}
+	cur.reset(-5, 100, cache, fs.KeyCursor(context.Background(), []byte("cpu"), -5, true))
+	defer cur.Close()
+
+	// The cached value is merged with the first block.
+	a := cur.Next()
+	if got, exp := a.Len(), 11; got != exp {
+		t.Fatalf("values length mismatch: got %v, exp %v", got, exp)
+	}
+	if got, exp := a.Values[0], 100.0; got != exp {
+		t.Fatalf("unexpected cached value: got %v, exp %v", got, exp)
+	}
+
+	if s, ok := cur.NextSummary(1000); !ok || s.Count != 10 || s.Sum != 145 {
+		t.Fatalf("expected summary of the second block, got %+v", s)
+	}
+	if a = cur.Next(); a.Len() != 0 {
+		t.Fatalf("expected no more values, got %d", a.Len())
+	}
+}
@@ -161,10 +161,11 @@ type storer interface {
 
 // Cache maintains an in-memory store of Values for a set of keys.
 type Cache struct {
-	_       uint64 // Padding for 32 bit struct alignment
-	mu      sync.RWMutex
-	store   storer
-	maxSize uint64
+	_        uint64 // Padding for 32 bit struct alignment
+	reserved uint64 // Room reserved for writes waiting to be written; accessed atomically
+	mu       sync.RWMutex
+	store    storer
+	maxSize  uint64
 
 	// snapshots are the cache objects that are currently being written to tsm files
 	// they're kept in memory while flushing so they can be queried along with the cache.
@@ -172,6 +173,11 @@ type Cache struct {
 	snapshot     *Cache
 	snapshotting bool
 
+	// freedC is closed and reset when a snapshot is successfully cleared or
+	// reserved room is released, to wake up the writes waiting for room in
+	// the cache.
+	freedC chan struct{}
+
 	tracker       *cacheTracker
 	lastSnapshot  time.Time
 	lastWriteTime time.Time
@@ -259,15 +265,27 @@ func (c *Cache) Write(key []byte, values []Value) error {
 // values as possible.  If one key fails, the others can still succeed and an
 // error will be returned.
 func (c *Cache) WriteMulti(values map[string][]Value) error {
+	return c.writeMulti(values, 0)
+}
+
+// WriteMultiReserved writes the map of keys and associated values to the cache
+// like WriteMulti, into room of the given size reserved for them by reserve.
+// The reserved room is released once the values are written.
+func (c *Cache) WriteMultiReserved(values map[string][]Value, reserved uint64) error {
+	defer c.release(reserved)
+	return c.writeMulti(values, reserved)
+}
+
+func (c *Cache) writeMulti(values map[string][]Value, reserved uint64) error {
 	c.init()
 	var addedSize uint64
 	for _, v := range values {
 		addedSize += uint64(Values(v).Size())
 	}
 
-	// Enough room in the cache?
+	// Enough room in the cache? Room reserved for other writes is not.
 	limit := c.maxSize // maxSize is safe for reading without a lock.
-	n := c.Size() + addedSize
+	n := c.Size() + atomic.LoadUint64(&c.reserved) - reserved + addedSize
 	if limit > 0 && n > limit {
 		c.tracker.IncWritesErr()
 		c.tracker.AddWrittenBytesDrop(uint64(addedSize))
@@ -401,6 +419,8 @@ func (c *Cache) ClearSnapshot(success bool) {
 	c.snapshotting = false
 
 	if success {
+		c.wakeWaiters()
+
 		snapshotSize := c.tracker.SnapshotSize()
 		c.tracker.SetSnapshotsActive(0)
 		c.tracker.SubMemBytes(snapshotSize) // decrement the number of bytes in cache
@@ -417,6 +437,54 @@ func (c *Cache) ClearSnapshot(success bool) {
 	}
 }
 
+// freed returns a channel that is closed the next time a snapshot is
+// successfully cleared or reserved room is released, freeing up room in the
+// cache.
+func (c *Cache) freed() <-chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.freedC == nil {
+		c.freedC = make(chan struct{})
+	}
+	return c.freedC
+}
+
+// reserve reserves room of the given size in the cache for a write and reports
+// whether there was room for it. The room must be released, either by writing
+// to it with WriteMultiReserved or by calling release.
+func (c *Cache) reserve(size uint64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	reserved := atomic.LoadUint64(&c.reserved)
+	if c.maxSize > 0 && c.Size()+reserved+size > c.maxSize {
+		return false
+	}
+	atomic.StoreUint64(&c.reserved, reserved+size)
+	return true
+}
+
+// release releases room of the given size reserved by reserve.
+func (c *Cache) release(size uint64) {
+	if size == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	atomic.AddUint64(&c.reserved, ^(size - 1))
+	c.wakeWaiters()
+}
+
+// wakeWaiters wakes up the writes waiting for room in the cache. The cache's
+// write lock must be held.
+func (c *Cache) wakeWaiters() {
+	if c.freedC != nil {
+		close(c.freedC)
+		c.freedC = nil
+	}
+}
+
 // Size returns the number of point-calcuated bytes the cache currently uses.
 func (c *Cache) Size() uint64 {
 	return c.tracker.CacheSize() + c.tracker.SnapshotSize()
@@ -697,6 +765,7 @@ type cacheTracker struct {
 	snapshottedBytes uint64
 	writesDropped    uint64
 	writesErr        uint64
+	writeWaits       uint64
 }
 
 func newCacheTracker(metrics *cacheMetrics, defaultLabels prometheus.Labels) *cacheTracker {
@@ -813,6 +882,12 @@ func (t *cacheTracker) IncWritesDrop() {
 	t.IncWrites("dropped")
 }
 
+// AddWriteWait records a write waiting d for room in the cache.
+func (t *cacheTracker) AddWriteWait(d time.Duration) {
+	atomic.AddUint64(&t.writeWaits, 1)
+	t.metrics.WriteWaits.With(t.labels).Observe(d.Seconds())
+}
+
 // CacheSize returns the live cache size.
 func (t *cacheTracker) CacheSize() uint64 { return atomic.LoadUint64(&t.cacheSize) }
 
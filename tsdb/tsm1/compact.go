@@ -681,6 +681,10 @@ func (c *DefaultPlanner) Release(groups []CompactionGroup) {
 	}
 }
 
+// snapshotSplitSize is the size of the cache above which snapshots are split
+// and written concurrently.
+const snapshotSplitSize = 256 * 1024 * 1024
+
 // Compactor merges multiple TSM files into new files or
 // writes a Cache into 1 or more TSM files.
 type Compactor struct {
@@ -695,6 +699,9 @@ type Compactor struct {
 	// RateLimit is the limit for disk writes for all concurrent compactions.
 	RateLimit limiter.Rate
 
+	// BlockSummaries controls whether block summaries are written to the new files.
+	BlockSummaries bool
+
 	formatFileName FormatFileNameFunc
 	parseFileName  ParseFileNameFunc
 
@@ -830,6 +837,15 @@ func (c *Compactor) WriteSnapshot(cache *Cache) ([]string, error) {
 		concurrency = 1
 	}
 
+	// Also write large snapshots concurrently, such as those of a cache that
+	// filled up faster than it could be snapshotted.
+	if n := int(cache.Size() / snapshotSplitSize); n > concurrency {
+		concurrency = n
+		if concurrency > 4 {
+			concurrency = 4
+		}
+	}
+
 	// Special case very high cardinality, use max concurrency and don't throttle writes.
 	if card >= 3e6 {
 		concurrency = 4
@@ -1105,12 +1121,12 @@ func (c *Compactor) write(path string, iter KeyIterator, throttle bool) (err err
 	// Use a disk based TSM buffer if it looks like we might create a big index
 	// in memory.
 	if iter.EstimatedIndexSize() > 64*1024*1024 {
-		w, err = NewTSMWriterWithDiskBuffer(limitWriter)
+		w, err = NewTSMWriterWithDiskBuffer(limitWriter, WithBlockSummaries(c.BlockSummaries))
 		if err != nil {
 			return err
 		}
 	} else {
-		w, err = NewTSMWriter(limitWriter)
+		w, err = NewTSMWriter(limitWriter, WithBlockSummaries(c.BlockSummaries))
 		if err != nil {
 			return err
 		}
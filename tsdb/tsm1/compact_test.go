@@ -2896,6 +2896,57 @@ func TestDefaultPlanner_Plan_ForceFull(t *testing.T) {
 
 }
 
+// BenchmarkCompactor_CompactFull_BlockSummaries compares full compactions of
+// files whose blocks are copied with and without writing block summaries.
+func BenchmarkCompactor_CompactFull_BlockSummaries(b *testing.B) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	// Each file holds a distinct range of time, so that its blocks are copied.
+	var files []string
+	for gen := 1; gen <= 4; gen++ {
+		writes := make(map[string][]tsm1.Value)
+		for i := 0; i < 100; i++ {
+			values := make([]tsm1.Value, 0, 10*tsm1.MaxPointsPerBlock)
+			for j := 0; j < cap(values); j++ {
+				ts := int64(gen*cap(values) + j)
+				values = append(values, tsm1.NewValue(ts, float64(ts)))
+			}
+			writes[fmt.Sprintf("cpu,host=%03d#!~#value", i)] = values
+		}
+		files = append(files, MustWriteTSM(dir, gen, writes))
+	}
+
+	for _, summaries := range []bool{false, true} {
+		b.Run(fmt.Sprintf("summaries=%t", summaries), func(b *testing.B) {
+			fs := &fakeFileStore{}
+			defer fs.Close()
+			compactor := tsm1.NewCompactor()
+			compactor.Dir = dir
+			compactor.FileStore = fs
+			compactor.BlockSummaries = summaries
+			compactor.Open()
+			defer compactor.Close()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				compacted, err := compactor.CompactFull(files)
+				if err != nil {
+					b.Fatalf("unexpected error compacting: %v", err)
+				}
+
+				b.StopTimer()
+				for _, f := range compacted {
+					os.Remove(f)
+					os.Remove(tsm1.StatsFilename(f))
+				}
+				b.StartTimer()
+			}
+		})
+	}
+}
+
 func assertValueEqual(t *testing.T, a, b tsm1.Value) {
 	if got, exp := a.UnixNano(), b.UnixNano(); got != exp {
 		t.Fatalf("time mismatch: got %v, exp %v", got, exp)
@@ -0,0 +1,479 @@
+package tsm1
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCompactTimeWindow is the default duration of the windows of time
+	// the TimeWindowPlanner groups TSM files by.
+	DefaultCompactTimeWindow = 24 * time.Hour
+
+	// DefaultCompactTombstoneRatio is the default ratio of the size of the
+	// tombstones of a TSM file to its size above which the TimeWindowPlanner
+	// rewrites the file.
+	DefaultCompactTombstoneRatio = 0.01
+)
+
+// TimeWindowPlanner implements CompactionPlanner by grouping generations of TSM
+// files by the window of time holding their data, as well as by level, so that
+// late-arriving or backfilled data is only compacted with the files of its own
+// window rather than causing every file of the shard to be rewritten.
+//
+// Each TSM file belongs to the windows its time range overlaps, and a generation
+// to the windows of its files.  As files are not split by time, windows sharing
+// a generation are planned as one, so that a generation holding data of several
+// windows, such as a snapshot of late-arriving and current data, is compacted
+// with the generations of those windows only.  Levelled compactions combine adjacent generations of the same level and
+// window, and once a window has not been written to for the write cold duration
+// all of its generations are rolled up into one.  Generations whose tombstones are large
+// relative to their size are rewritten on their own to reclaim the deleted data.
+//
+// Generations are only compacted together if no generation between them that
+// is left out overlaps them in time, so that the newest values still take
+// precedence once compacted.
+type TimeWindowPlanner struct {
+	FileStore fileStore
+
+	// window is the duration of the windows of time generations are grouped by.
+	window time.Duration
+
+	// compactFullWriteColdDuration specifies the length of time after which a
+	// window that has not been written to is fully compacted.
+	compactFullWriteColdDuration time.Duration
+
+	// TombstoneRatio is the ratio of the size of the tombstones of a file to
+	// its size above which the generation of the file is rewritten.
+	TombstoneRatio float64
+
+	mu sync.RWMutex
+
+	// forceFull causes the next full plan request to plan every window that
+	// has more than one generation.
+	forceFull bool
+
+	// filesInUse is the set of files that have been returned as part of a plan
+	// and might be being compacted.
+	filesInUse map[string]struct{}
+}
+
+// NewTimeWindowPlanner returns a TimeWindowPlanner grouping the files of fs by
+// windows of the duration window.
+func NewTimeWindowPlanner(fs fileStore, window, writeColdDuration time.Duration) *TimeWindowPlanner {
+	if window <= 0 {
+		window = DefaultCompactTimeWindow
+	}
+	return &TimeWindowPlanner{
+		FileStore:                    fs,
+		window:                       window,
+		compactFullWriteColdDuration: writeColdDuration,
+		TombstoneRatio:               DefaultCompactTombstoneRatio,
+		filesInUse:                   make(map[string]struct{}),
+	}
+}
+
+func (c *TimeWindowPlanner) SetFileStore(fs *FileStore) {
+	c.FileStore = fs
+}
+
+// timeWindow holds the generations, ordered by id, of a window of time, or of
+// several windows sharing generations.
+type timeWindow struct {
+	start       int64
+	generations tsmGenerations
+}
+
+// lastModified returns the time the most recently modified file of the window
+// was modified.
+func (w *timeWindow) lastModified() int64 {
+	var t int64
+	for _, g := range w.generations {
+		for _, f := range g.files {
+			if f.LastModified > t {
+				t = f.LastModified
+			}
+		}
+	}
+	return t
+}
+
+// generationTimeRange returns the time range of the files of g.
+func generationTimeRange(g *tsmGeneration) (min, max int64) {
+	min, max = g.files[0].MinTime, g.files[0].MaxTime
+	for _, f := range g.files[1:] {
+		if f.MinTime < min {
+			min = f.MinTime
+		}
+		if f.MaxTime > max {
+			max = f.MaxTime
+		}
+	}
+	return min, max
+}
+
+// generations returns all the generations of the file store ordered by id.
+func (c *TimeWindowPlanner) generations() tsmGenerations {
+	byID := make(map[int]*tsmGeneration)
+	for _, f := range c.FileStore.Stats() {
+		id, _, err := c.FileStore.ParseFileName(f.Path)
+		if err != nil {
+			continue
+		}
+
+		g := byID[id]
+		if g == nil {
+			g = newTsmGeneration(id, c.FileStore.ParseFileName)
+			byID[id] = g
+		}
+		g.files = append(g.files, f)
+	}
+
+	generations := make(tsmGenerations, 0, len(byID))
+	for _, g := range byID {
+		generations = append(generations, g)
+	}
+	sort.Sort(generations)
+	return generations
+}
+
+// windows groups generations by window, ordered by the start of the windows.
+// If skipInUse is true, generations that are part of a plan are left out.
+//
+// The windows of a generation are those overlapped by the time ranges of its
+// files.  Windows sharing a generation are merged into one, as are the windows
+// of generations whose files overlap, so that a generation is never planned
+// without the generations it may hold values of the same time as.
+func (c *TimeWindowPlanner) windows(generations tsmGenerations, skipInUse bool) []*timeWindow {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	// span is the range of windows [start, end) overlapped by a file of the
+	// generation at index g of kept.
+	type span struct {
+		start, end int64
+		g          int
+	}
+
+	var kept tsmGenerations
+	var spans []span
+	for _, g := range generations {
+		if skipInUse && c.inUse(g) {
+			continue
+		}
+
+		for _, f := range g.files {
+			spans = append(spans, span{
+				start: c.windowStart(f.MinTime),
+				end:   c.windowStart(f.MaxTime) + int64(c.window),
+				g:     len(kept),
+			})
+		}
+		kept = append(kept, g)
+	}
+
+	// Join the generations of overlapping spans.
+	root := make([]int, len(kept))
+	for i := range root {
+		root[i] = i
+	}
+	find := func(i int) int {
+		for root[i] != i {
+			root[i] = root[root[i]]
+			i = root[i]
+		}
+		return i
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	for i, end := 0, int64(0); i < len(spans); i++ {
+		if i > 0 && spans[i].start < end {
+			root[find(spans[i].g)] = find(spans[i-1].g)
+		}
+		if i == 0 || spans[i].end > end {
+			end = spans[i].end
+		}
+	}
+
+	byRoot := make(map[int]*timeWindow)
+	var windows []*timeWindow
+	for _, sp := range spans {
+		r := find(sp.g)
+		if w := byRoot[r]; w == nil {
+			w = &timeWindow{start: sp.start}
+			byRoot[r] = w
+			windows = append(windows, w)
+		}
+	}
+	for i, g := range kept {
+		w := byRoot[find(i)]
+		w.generations = append(w.generations, g)
+	}
+
+	sort.Slice(windows, func(i, j int) bool { return windows[i].start < windows[j].start })
+	return windows
+}
+
+// windowStart returns the start of the window holding t.
+func (c *TimeWindowPlanner) windowStart(t int64) int64 {
+	start := t - t%int64(c.window)
+	if t < 0 && t%int64(c.window) != 0 {
+		start -= int64(c.window)
+	}
+	return start
+}
+
+// inUse returns true if any file of g is part of a plan.  c.mu must be held.
+func (c *TimeWindowPlanner) inUse(g *tsmGeneration) bool {
+	for _, f := range g.files {
+		if _, ok := c.filesInUse[f.Path]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ordered returns true if group, ordered by id, can be compacted without any
+// of the generations in all that are left out overlapping the time range of an
+// older generation of the group, which would change which values take
+// precedence once the group is compacted into its newest generation.
+func ordered(group, all tsmGenerations) bool {
+	first, last := group[0].id, group[len(group)-1].id
+
+	in := make(map[int]struct{}, len(group))
+	for _, g := range group {
+		in[g.id] = struct{}{}
+	}
+
+	for _, g := range all {
+		if _, ok := in[g.id]; ok || g.id <= first || g.id >= last {
+			continue
+		}
+
+		min, max := generationTimeRange(g)
+		for _, m := range group {
+			if m.id > g.id {
+				break
+			}
+			for _, f := range m.files {
+				if f.OverlapsTimeRange(min, max) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// compactionGroup returns the files of generations as a CompactionGroup.
+func compactionGroup(generations tsmGenerations) CompactionGroup {
+	var group CompactionGroup
+	for _, g := range generations {
+		for _, f := range g.files {
+			group = append(group, f.Path)
+		}
+	}
+	sort.Strings(group)
+	return group
+}
+
+// FullyCompacted returns true if every window holds a single generation
+// that does not need to be rewritten because of its tombstones.
+func (c *TimeWindowPlanner) FullyCompacted() bool {
+	for _, w := range c.windows(c.generations(), false) {
+		if len(w.generations) > 1 || c.tombstoneHeavy(w.generations[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// ForceFull causes the planner to return a plan rolling up every window the
+// next time Plan is called.  Level and optimize plans are not returned until
+// then.
+func (c *TimeWindowPlanner) ForceFull() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.forceFull = true
+}
+
+// PlanLevel returns groups of adjacent generations of the same level and
+// window to compact together.
+func (c *TimeWindowPlanner) PlanLevel(level int) []CompactionGroup {
+	c.mu.RLock()
+	if c.forceFull {
+		c.mu.RUnlock()
+		return nil
+	}
+	c.mu.RUnlock()
+
+	minGenerations := 4
+	if level == 1 {
+		minGenerations = 8
+	}
+
+	all := c.generations()
+	var cGroups []CompactionGroup
+	for _, w := range c.windows(all, true) {
+		// Split the generations of the window into runs of the same level.
+		var runs []tsmGenerations
+		var run tsmGenerations
+		for _, g := range w.generations {
+			if g.level() != level {
+				if len(run) > 0 {
+					runs = append(runs, run)
+					run = nil
+				}
+				continue
+			}
+			run = append(run, g)
+		}
+		if len(run) > 0 {
+			runs = append(runs, run)
+		}
+
+		for _, run := range runs {
+			for _, chunk := range run.chunk(minGenerations) {
+				if len(chunk) < minGenerations && !chunk.hasTombstones() {
+					continue
+				}
+				if !ordered(chunk, all) {
+					continue
+				}
+				cGroups = append(cGroups, compactionGroup(chunk))
+			}
+		}
+	}
+
+	if !c.acquire(cGroups) {
+		return nil
+	}
+	return cGroups
+}
+
+// Plan returns a group per window with more than one generation that has not
+// been written to for the write cold duration, or per window if a full
+// compaction was forced or nothing has been written since lastWrite for the
+// write cold duration.  Otherwise, it returns groups of four adjacent level 4
+// generations of the same window.
+func (c *TimeWindowPlanner) Plan(lastWrite time.Time) []CompactionGroup {
+	c.mu.Lock()
+	forceFull := c.forceFull
+	c.forceFull = false
+	c.mu.Unlock()
+
+	cold := c.compactFullWriteColdDuration > 0 && time.Since(lastWrite) > c.compactFullWriteColdDuration
+	coldBefore := time.Now().Add(-c.compactFullWriteColdDuration).UnixNano()
+
+	all := c.generations()
+	var cGroups []CompactionGroup
+	for _, w := range c.windows(all, true) {
+		if len(w.generations) <= 1 {
+			continue
+		}
+
+		full := forceFull || cold
+		if !full && c.compactFullWriteColdDuration > 0 && w.lastModified() < coldBefore {
+			full = true
+		}
+
+		if full {
+			if ordered(w.generations, all) {
+				cGroups = append(cGroups, compactionGroup(w.generations))
+			}
+			continue
+		}
+
+		var run tsmGenerations
+		for _, g := range w.generations {
+			if g.level() < 4 {
+				run = nil
+				continue
+			}
+
+			run = append(run, g)
+			if len(run) == 4 {
+				if ordered(run, all) {
+					cGroups = append(cGroups, compactionGroup(run))
+				}
+				run = nil
+			}
+		}
+	}
+
+	if !c.acquire(cGroups) {
+		return nil
+	}
+	return cGroups
+}
+
+// PlanOptimize returns a group for each generation whose tombstones are large
+// relative to its size so that it is rewritten without the deleted data.
+func (c *TimeWindowPlanner) PlanOptimize() []CompactionGroup {
+	c.mu.RLock()
+	if c.forceFull {
+		c.mu.RUnlock()
+		return nil
+	}
+	c.mu.RUnlock()
+
+	var cGroups []CompactionGroup
+	for _, w := range c.windows(c.generations(), true) {
+		for _, g := range w.generations {
+			if c.tombstoneHeavy(g) {
+				cGroups = append(cGroups, compactionGroup(tsmGenerations{g}))
+			}
+		}
+	}
+
+	if !c.acquire(cGroups) {
+		return nil
+	}
+	return cGroups
+}
+
+// tombstoneHeavy returns true if the tombstones of g are large enough relative
+// to its size for g to be rewritten.
+func (c *TimeWindowPlanner) tombstoneHeavy(g *tsmGeneration) bool {
+	var size, tombstoneSize uint64
+	for _, f := range g.files {
+		size += uint64(f.Size)
+		tombstoneSize += uint64(f.TombstoneSize)
+	}
+	return tombstoneSize > 0 && float64(tombstoneSize) >= c.TombstoneRatio*float64(size)
+}
+
+func (c *TimeWindowPlanner) acquire(groups []CompactionGroup) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// See if the new files are already in use
+	for _, g := range groups {
+		for _, f := range g {
+			if _, ok := c.filesInUse[f]; ok {
+				return false
+			}
+		}
+	}
+
+	// Mark all the new files in use
+	for _, g := range groups {
+		for _, f := range g {
+			c.filesInUse[f] = struct{}{}
+		}
+	}
+	return true
+}
+
+// Release removes the files reference in each compaction group allowing new plans
+// to be able to use them.
+func (c *TimeWindowPlanner) Release(groups []CompactionGroup) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, g := range groups {
+		for _, f := range g {
+			delete(c.filesInUse, f)
+		}
+	}
+}
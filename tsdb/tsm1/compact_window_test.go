@@ -0,0 +1,204 @@
+package tsm1_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+)
+
+// windowFileStat returns the stat of a file holding data between min and max
+// hours, last modified at modified.
+func windowFileStat(path string, min, max int64, modified time.Time) tsm1.FileStat {
+	return tsm1.FileStat{
+		Path:         path,
+		Size:         1 * 1024 * 1024,
+		MinTime:      min * int64(time.Hour),
+		MaxTime:      max*int64(time.Hour) - 1,
+		LastModified: modified.UnixNano(),
+	}
+}
+
+func newTimeWindowPlanner(data []tsm1.FileStat) *tsm1.TimeWindowPlanner {
+	return tsm1.NewTimeWindowPlanner(
+		&fakeFileStore{
+			PathsFn: func() []tsm1.FileStat {
+				return data
+			},
+		}, time.Hour, tsm1.DefaultCompactFullWriteColdDuration,
+	)
+}
+
+// Ensure late-arriving data is not compacted with the data of other windows.
+func TestTimeWindowPlanner_PlanLevel_LateData(t *testing.T) {
+	now := time.Now()
+	data := []tsm1.FileStat{
+		windowFileStat("01-01.tsm1", 10, 11, now),
+		windowFileStat("02-01.tsm1", 10, 11, now),
+		windowFileStat("03-01.tsm1", 10, 11, now),
+		windowFileStat("04-01.tsm1", 10, 11, now),
+		windowFileStat("05-01.tsm1", 0, 1, now),
+		windowFileStat("06-01.tsm1", 10, 11, now),
+		windowFileStat("07-01.tsm1", 10, 11, now),
+		windowFileStat("08-01.tsm1", 10, 11, now),
+		windowFileStat("09-01.tsm1", 10, 11, now),
+	}
+
+	cp := newTimeWindowPlanner(data)
+	tsm := cp.PlanLevel(1)
+	exp := []tsm1.CompactionGroup{{
+		"01-01.tsm1", "02-01.tsm1", "03-01.tsm1", "04-01.tsm1",
+		"06-01.tsm1", "07-01.tsm1", "08-01.tsm1", "09-01.tsm1",
+	}}
+	if !reflect.DeepEqual(tsm, exp) {
+		t.Fatalf("unexpected plan: got %v, exp %v", tsm, exp)
+	}
+
+	// The files are in use until released.
+	if tsm := cp.PlanLevel(1); len(tsm) != 0 {
+		t.Fatalf("expected no plan for files in use, got %v", tsm)
+	}
+	cp.Release(tsm)
+	if tsm := cp.PlanLevel(1); !reflect.DeepEqual(tsm, exp) {
+		t.Fatalf("unexpected plan after release: got %v, exp %v", tsm, exp)
+	}
+}
+
+// Ensure generations are not compacted together when a generation left out
+// between them, as it is being compacted, overlaps them in time.
+func TestTimeWindowPlanner_PlanLevel_Overlap(t *testing.T) {
+	now := time.Now()
+	data := []tsm1.FileStat{
+		windowFileStat("01-01.tsm1", 10, 11, now),
+		windowFileStat("02-01.tsm1", 10, 11, now),
+		windowFileStat("03-01.tsm1", 10, 11, now),
+		windowFileStat("04-01.tsm1", 10, 11, now),
+		windowFileStat("05-01.tsm1", 10, 13, now),
+		windowFileStat("06-01.tsm1", 10, 11, now),
+		windowFileStat("07-01.tsm1", 10, 11, now),
+		windowFileStat("08-01.tsm1", 10, 11, now),
+		windowFileStat("09-01.tsm1", 10, 11, now),
+	}
+	data[4].HasTombstone, data[4].TombstoneSize = true, data[4].Size
+
+	cp := newTimeWindowPlanner(data)
+	tsm := cp.PlanOptimize()
+	if exp := []tsm1.CompactionGroup{{"05-01.tsm1"}}; !reflect.DeepEqual(tsm, exp) {
+		t.Fatalf("unexpected optimize plan: got %v, exp %v", tsm, exp)
+	}
+	if tsm := cp.PlanLevel(1); len(tsm) != 0 {
+		t.Fatalf("expected no plan, got %v", tsm)
+	}
+}
+
+// Ensure a window that has not been written to recently is rolled up on its
+// own.
+func TestTimeWindowPlanner_Plan_ColdWindow(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-2 * tsm1.DefaultCompactFullWriteColdDuration)
+	data := []tsm1.FileStat{
+		windowFileStat("01-04.tsm1", 0, 1, old),
+		windowFileStat("02-04.tsm1", 10, 11, now),
+		windowFileStat("03-01.tsm1", 0, 1, old),
+		windowFileStat("04-01.tsm1", 10, 11, now),
+	}
+
+	cp := newTimeWindowPlanner(data)
+	tsm := cp.Plan(now)
+	exp := []tsm1.CompactionGroup{{"01-04.tsm1", "03-01.tsm1"}}
+	if !reflect.DeepEqual(tsm, exp) {
+		t.Fatalf("unexpected plan: got %v, exp %v", tsm, exp)
+	}
+	cp.Release(tsm)
+
+	// Every window is rolled up when forced.
+	cp.ForceFull()
+	if tsm := cp.PlanLevel(1); len(tsm) != 0 {
+		t.Fatalf("expected no level plan while forcing a full plan, got %v", tsm)
+	}
+	tsm = cp.Plan(now)
+	exp = []tsm1.CompactionGroup{{"01-04.tsm1", "03-01.tsm1"}, {"02-04.tsm1", "04-01.tsm1"}}
+	if !reflect.DeepEqual(tsm, exp) {
+		t.Fatalf("unexpected forced plan: got %v, exp %v", tsm, exp)
+	}
+
+	if cp.FullyCompacted() {
+		t.Fatal("expected windows not to be fully compacted")
+	}
+}
+
+// Ensure a generation spanning several windows is compacted with the
+// generations of every window it overlaps.
+func TestTimeWindowPlanner_Plan_SpanningGeneration(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-2 * tsm1.DefaultCompactFullWriteColdDuration)
+	data := []tsm1.FileStat{
+		windowFileStat("01-04.tsm1", 0, 1, old),
+		windowFileStat("02-04.tsm1", 0, 3, old),
+		windowFileStat("03-04.tsm1", 2, 3, old),
+		windowFileStat("04-04.tsm1", 0, 1, old),
+		windowFileStat("05-04.tsm1", 5, 6, old),
+		windowFileStat("06-04.tsm1", 5, 6, old),
+	}
+
+	cp := newTimeWindowPlanner(data)
+	tsm := cp.Plan(now)
+	exp := []tsm1.CompactionGroup{
+		{"01-04.tsm1", "02-04.tsm1", "03-04.tsm1", "04-04.tsm1"},
+		{"05-04.tsm1", "06-04.tsm1"},
+	}
+	if !reflect.DeepEqual(tsm, exp) {
+		t.Fatalf("unexpected plan: got %v, exp %v", tsm, exp)
+	}
+}
+
+// Ensure the files of a snapshot holding late-arriving as well as current data
+// assign its generation to the windows of both, and not to the windows between.
+func TestTimeWindowPlanner_Plan_MixedTimeSnapshot(t *testing.T) {
+	now := time.Now()
+	data := []tsm1.FileStat{
+		windowFileStat("01-01.tsm1", 0, 1, now),
+		windowFileStat("02-01.tsm1", 10, 11, now),
+		windowFileStat("03-01.tsm1", 5, 6, now),
+		windowFileStat("04-01.tsm1", 0, 1, now),
+		windowFileStat("05-01.tsm1", 5, 6, now),
+		windowFileStat("06-01.tsm1", 10, 11, now),
+		windowFileStat("07-01.tsm1", 0, 1, now),
+		windowFileStat("07-02.tsm1", 10, 11, now),
+	}
+
+	cp := newTimeWindowPlanner(data)
+	cp.ForceFull()
+	tsm := cp.Plan(now)
+	exp := []tsm1.CompactionGroup{
+		{"01-01.tsm1", "02-01.tsm1", "04-01.tsm1", "06-01.tsm1", "07-01.tsm1", "07-02.tsm1"},
+		{"03-01.tsm1", "05-01.tsm1"},
+	}
+	if !reflect.DeepEqual(tsm, exp) {
+		t.Fatalf("unexpected plan: got %v, exp %v", tsm, exp)
+	}
+}
+
+// Ensure generations with large tombstones are rewritten.
+func TestTimeWindowPlanner_PlanOptimize_Tombstones(t *testing.T) {
+	now := time.Now()
+	data := []tsm1.FileStat{
+		windowFileStat("01-04.tsm1", 0, 1, now),
+		windowFileStat("02-04.tsm1", 10, 11, now),
+		windowFileStat("03-04.tsm1", 20, 21, now),
+	}
+	data[1].HasTombstone, data[1].TombstoneSize = true, data[1].Size/10
+	data[2].HasTombstone, data[2].TombstoneSize = true, 1
+
+	cp := newTimeWindowPlanner(data)
+	if cp.FullyCompacted() {
+		t.Fatal("expected windows with tombstones not to be fully compacted")
+	}
+
+	tsm := cp.PlanOptimize()
+	exp := []tsm1.CompactionGroup{{"02-04.tsm1"}}
+	if !reflect.DeepEqual(tsm, exp) {
+		t.Fatalf("unexpected plan: got %v, exp %v", tsm, exp)
+	}
+}
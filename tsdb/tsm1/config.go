@@ -1,6 +1,7 @@
 package tsm1
 
 import (
+	"fmt"
 	"runtime"
 	"time"
 
@@ -27,6 +28,7 @@ type Config struct {
 
 	Compaction CompactionConfig `toml:"compaction"`
 	Cache      CacheConfig      `toml:"cache"`
+	BlockCache BlockCacheConfig `toml:"block-cache"`
 }
 
 // NewConfig constructs a Config with the default values.
@@ -39,12 +41,19 @@ func NewConfig() Config {
 			MaxMemorySize:             toml.Size(DefaultCacheMaxMemorySize),
 			SnapshotMemorySize:        toml.Size(DefaultCacheSnapshotMemorySize),
 			SnapshotWriteColdDuration: toml.Duration(DefaultCacheSnapshotWriteColdDuration),
+			MaxWriteWait:              toml.Duration(DefaultCacheMaxWriteWait),
+		},
+		BlockCache: BlockCacheConfig{
+			MaxMemorySize: toml.Size(DefaultBlockCacheMaxMemorySize),
 		},
 		Compaction: CompactionConfig{
 			FullWriteColdDuration: toml.Duration(DefaultCompactFullWriteColdDuration),
 			Throughput:            toml.Size(DefaultCompactThroughput),
 			ThroughputBurst:       toml.Size(DefaultCompactThroughputBurst),
 			MaxConcurrent:         DefaultCompactMaxConcurrent,
+			BlockSummaries:        DefaultCompactBlockSummaries,
+			Planner:               DefaultCompactPlanner,
+			TimeWindow:            toml.Duration(DefaultCompactTimeWindow),
 		},
 	}
 }
@@ -54,6 +63,18 @@ const (
 	DefaultCompactThroughput            = 48 * 1024 * 1024
 	DefaultCompactThroughputBurst       = 48 * 1024 * 1024
 	DefaultCompactMaxConcurrent         = 0
+	DefaultCompactBlockSummaries        = false
+	DefaultCompactPlanner               = LevelCompactPlanner
+)
+
+// The compaction planners a CompactionConfig may select.
+const (
+	// LevelCompactPlanner compacts TSM files by level and age with the DefaultPlanner.
+	LevelCompactPlanner = "level"
+
+	// TimeWindowCompactPlanner compacts TSM files grouped by windows of time
+	// with the TimeWindowPlanner.
+	TimeWindowCompactPlanner = "time-window"
 )
 
 // CompactionConfing holds all of the configuration for compactions. Eventually we want
@@ -77,12 +98,43 @@ type CompactionConfig struct {
 	// MaxConcurrent is the maximum number of concurrent full and level compactions that can
 	// run at one time.  A value of 0 results in 50% of runtime.GOMAXPROCS(0) used at runtime.
 	MaxConcurrent int `toml:"max-concurrent"`
+
+	// BlockSummaries controls whether the summary statistics of each block are
+	// written to the TSM files, allowing aggregates over whole blocks to be
+	// computed without decoding them.  Compactions decode the blocks they copy
+	// to summarize them, so summaries are disabled by default.
+	BlockSummaries bool `toml:"block-summaries"`
+
+	// Planner selects the planner of compactions, either "level" or
+	// "time-window". The time-window planner only compacts files together
+	// with the files of the same window of time, which suits shards that are
+	// backfilled or receive late-arriving data.
+	Planner string `toml:"planner"`
+
+	// TimeWindow is the duration of the windows of time the time-window
+	// planner groups files by.
+	TimeWindow toml.Duration `toml:"time-window"`
+}
+
+// Validate returns an error if the compaction configuration is invalid.
+func (c CompactionConfig) Validate() error {
+	switch c.Planner {
+	case LevelCompactPlanner:
+	case TimeWindowCompactPlanner:
+		if c.TimeWindow <= 0 {
+			return fmt.Errorf("compaction time window must be positive, got %s", time.Duration(c.TimeWindow))
+		}
+	default:
+		return fmt.Errorf("unknown compaction planner %q, expected %q or %q", c.Planner, LevelCompactPlanner, TimeWindowCompactPlanner)
+	}
+	return nil
 }
 
 const (
 	DefaultCacheMaxMemorySize             = 1024 * 1024 * 1024 // 1GB
 	DefaultCacheSnapshotMemorySize        = 25 * 1024 * 1024   // 25MB
 	DefaultCacheSnapshotWriteColdDuration = time.Duration(10 * time.Minute)
+	DefaultCacheMaxWriteWait              = time.Duration(10 * time.Second)
 )
 
 // CacheConfig holds all of the configuration for the in memory cache of values that
@@ -100,11 +152,29 @@ type CacheConfig struct {
 	// the cache and write it to a new TSM file if the shard hasn't received writes or
 	// deletes
 	SnapshotWriteColdDuration toml.Duration `toml:"snapshot-write-cold-duration"`
+
+	// MaxWriteWait is the maximum length of time a write waits for a snapshot to
+	// free up room in a full cache before it is rejected. A value of 0 rejects
+	// writes to a full cache immediately.
+	MaxWriteWait toml.Duration `toml:"max-write-wait"`
+}
+
+const (
+	DefaultBlockCacheMaxMemorySize = 64 * 1024 * 1024 // 64MB
+)
+
+// BlockCacheConfig holds all of the configuration for the cache of decoded blocks
+// read from TSM files.
+type BlockCacheConfig struct {
+	// MaxMemorySize is the maximum size of the decoded blocks held by a shard's
+	// block cache. A value of 0 disables the block cache.
+	MaxMemorySize toml.Size `toml:"max-memory-size"`
 }
 
 const (
-	DefaultWALEnabled    = true
-	DefaultWALFsyncDelay = time.Duration(0)
+	DefaultWALEnabled     = true
+	DefaultWALFsyncDelay  = time.Duration(0)
+	DefaultWALCompression = "snappy"
 )
 
 // WALConfig holds all of the configuration about the WAL.
@@ -117,11 +187,16 @@ type WALConfig struct {
 	// useful for slower disks or when WAL write contention is seen.  A value of 0 fsyncs
 	// every write to the WAL.
 	FsyncDelay toml.Duration `toml:"fsync-delay"`
+
+	// Compression is the compression of new WAL entries, either "snappy" or
+	// "zstd".  zstd compresses large batches better but uses more CPU.
+	Compression string `toml:"compression"`
 }
 
 func NewWALConfig() WALConfig {
 	return WALConfig{
-		Enabled:    DefaultWALEnabled,
-		FsyncDelay: toml.Duration(DefaultWALFsyncDelay),
+		Enabled:     DefaultWALEnabled,
+		FsyncDelay:  toml.Duration(DefaultWALFsyncDelay),
+		Compression: DefaultWALCompression,
 	}
 }
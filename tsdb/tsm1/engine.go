@@ -148,6 +148,14 @@ type Engine struct {
 	// a snapshot of the cache to a TSM file
 	CacheFlushWriteColdDuration time.Duration
 
+	// CacheMaxWriteWait specifies the maximum length of time a write waits for
+	// a snapshot to free up room in a full cache before it is rejected.
+	CacheMaxWriteWait time.Duration
+
+	// snapshotC requests a snapshot of the cache on behalf of writes waiting
+	// for room in it.
+	snapshotC chan struct{}
+
 	// Invoked when creating a backup file "as new".
 	formatFileName FormatFileNameFunc
 
@@ -169,12 +177,16 @@ func NewEngine(path string, idx *tsi1.Index, config Config, options ...EngineOpt
 	fs := NewFileStore(path)
 	fs.openLimiter = limiter.NewFixed(config.MaxConcurrentOpens)
 	fs.tsmMMAPWillNeed = config.MADVWillNeed
+	if config.BlockCache.MaxMemorySize > 0 {
+		fs.WithBlockCache(NewBlockCache(uint64(config.BlockCache.MaxMemorySize)))
+	}
 
 	cache := NewCache(uint64(config.Cache.MaxMemorySize))
 
 	c := NewCompactor()
 	c.Dir = path
 	c.FileStore = fs
+	c.BlockSummaries = config.Compaction.BlockSummaries
 	c.RateLimit = limiter.NewRate(
 		int(config.Compaction.Throughput),
 		int(config.Compaction.ThroughputBurst))
@@ -209,13 +221,14 @@ func NewEngine(path string, idx *tsi1.Index, config Config, options ...EngineOpt
 
 		Cache: cache,
 
-		FileStore: fs,
-		Compactor: c,
-		CompactionPlan: NewDefaultPlanner(fs,
-			time.Duration(config.Compaction.FullWriteColdDuration)),
+		FileStore:      fs,
+		Compactor:      c,
+		CompactionPlan: newCompactionPlanner(fs, config.Compaction),
 
 		CacheFlushMemorySizeThreshold: uint64(config.Cache.SnapshotMemorySize),
 		CacheFlushWriteColdDuration:   time.Duration(config.Cache.SnapshotWriteColdDuration),
+		CacheMaxWriteWait:             time.Duration(config.Cache.MaxWriteWait),
+		snapshotC:                     make(chan struct{}, 1),
 		enableCompactionsOnOpen:       true,
 		formatFileName:                DefaultFormatFileName,
 		compactionLimiter:             limiter.NewFixed(maxCompactions),
@@ -230,6 +243,15 @@ func NewEngine(path string, idx *tsi1.Index, config Config, options ...EngineOpt
 	return e
 }
 
+// newCompactionPlanner returns the compaction planner selected by config for fs.
+func newCompactionPlanner(fs *FileStore, config CompactionConfig) CompactionPlanner {
+	writeCold := time.Duration(config.FullWriteColdDuration)
+	if config.Planner == TimeWindowCompactPlanner {
+		return NewTimeWindowPlanner(fs, time.Duration(config.TimeWindow), writeCold)
+	}
+	return NewDefaultPlanner(fs, writeCold)
+}
+
 func (e *Engine) WithFormatFileNameFunc(formatFileNameFunc FormatFileNameFunc) {
 	e.Compactor.WithFormatFileNameFunc(formatFileNameFunc)
 	e.formatFileName = formatFileNameFunc
@@ -495,6 +517,9 @@ func (e *Engine) initTrackers() {
 	e.compactionTracker = newCompactionTracker(bms.compactionMetrics, e.defaultMetricLabels)
 	e.FileStore.tracker = newFileTracker(bms.fileMetrics, e.defaultMetricLabels)
 	e.Cache.tracker = newCacheTracker(bms.cacheMetrics, e.defaultMetricLabels)
+	if e.FileStore.blockCache != nil {
+		e.FileStore.blockCache.tracker = newBlockCacheTracker(bms.blockCacheMetrics, e.defaultMetricLabels)
+	}
 
 	e.scheduler.setCompactionTracker(e.compactionTracker)
 }
@@ -586,6 +611,107 @@ func (e *Engine) WriteValues(values map[string][]Value) error {
 	return nil
 }
 
+// CacheReservation is room in the cache reserved for a write by
+// WaitForCacheRoom. The room is released when the write is written with
+// WriteValuesReserved, or by Release if it is not.
+type CacheReservation struct {
+	cache *Cache
+	size  uint64
+}
+
+// Release releases the room of the reservation if it was not written to. It is
+// safe to call on a nil reservation and more than once.
+func (r *CacheReservation) Release() {
+	if r == nil || r.cache == nil {
+		return
+	}
+	r.cache.release(r.size)
+	r.cache = nil
+}
+
+// WriteValuesReserved saves the set of values in the engine into the room of
+// the cache reserved for them by r, which may be nil if no room was reserved.
+func (e *Engine) WriteValuesReserved(values map[string][]Value, r *CacheReservation) error {
+	if r == nil || r.cache == nil {
+		return e.WriteValues(values)
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	cache, size := r.cache, r.size
+	r.cache = nil
+	return cache.WriteMultiReserved(values, size)
+}
+
+// WaitForCacheRoom waits up to CacheMaxWriteWait for the cache to have room for
+// values, requesting snapshots of the cache to free it up, so that bursts of
+// writes outpacing snapshots are delayed rather than rejected.  It returns an
+// error if there is still no room for values.
+//
+// The room is reserved for values until they are written with
+// WriteValuesReserved or the returned reservation is released, so that other
+// writes cannot take it in between. The reservation is nil if the cache has no
+// maximum size.
+//
+// WaitForCacheRoom must not be called while holding locks needed to snapshot
+// the cache, such as the engine's or the snapshotter's.
+func (e *Engine) WaitForCacheRoom(values map[string][]Value) (*CacheReservation, error) {
+	limit := e.Cache.MaxSize()
+	if limit == 0 {
+		return nil, nil
+	}
+
+	var size uint64
+	for _, v := range values {
+		size += uint64(Values(v).Size())
+	}
+
+	// Nothing frees up room without snapshots.
+	e.mu.RLock()
+	snapshots := e.snapDone != nil
+	e.mu.RUnlock()
+
+	var (
+		start time.Time
+		timer *time.Timer
+	)
+	for {
+		// Get the channel before checking the size so that a snapshot cleared
+		// in between is not missed.
+		freed := e.Cache.freed()
+		if e.Cache.reserve(size) {
+			if timer != nil {
+				e.Cache.tracker.AddWriteWait(time.Since(start))
+			}
+			return &CacheReservation{cache: e.Cache, size: size}, nil
+		}
+		n := e.Cache.Size() + atomic.LoadUint64(&e.Cache.reserved) + size
+
+		if timer == nil {
+			if e.CacheMaxWriteWait <= 0 || !snapshots || size > limit {
+				return nil, ErrCacheMemorySizeLimitExceeded(n, limit)
+			}
+			start = time.Now()
+			timer = time.NewTimer(e.CacheMaxWriteWait)
+			defer timer.Stop()
+		}
+
+		// Request a snapshot, unless one is already requested.
+		select {
+		case e.snapshotC <- struct{}{}:
+		default:
+		}
+
+		select {
+		case <-freed:
+		case <-timer.C:
+			e.Cache.tracker.AddWriteWait(time.Since(start))
+			return nil, ErrCacheMemorySizeLimitExceeded(n, limit)
+		}
+	}
+}
+
 // ForEachMeasurementName iterates over each measurement name in the engine.
 func (e *Engine) ForEachMeasurementName(fn func(name []byte) error) error {
 	return e.index.ForEachMeasurementName(fn)
@@ -630,6 +756,9 @@ type compactionTracker struct {
 	active [6]uint64 // Gauge of TSM compactions (by level) currently running.
 	errors [6]uint64 // Counter of TSM compcations (by level) that have failed due to error.
 	queue  [6]uint64 // Gauge of TSM compactions queues (by level).
+
+	queueSize     [6]uint64 // Gauge of the size of the TSM files in compaction queues (by level).
+	compactedSize [6]uint64 // Counter of the size of the TSM files (by level) successfully compacted.
 }
 
 func newCompactionTracker(metrics *compactionMetrics, defaultLables prometheus.Labels) *compactionTracker {
@@ -739,6 +868,24 @@ func (t *compactionTracker) SetQueue(level compactionLevel, length uint64) {
 	t.metrics.CompactionQueue.With(labels).Set(float64(length))
 }
 
+// SetQueueSize sets the size in bytes of the TSM files in the compaction
+// queue for the provided level, that is the work planned for the level.
+func (t *compactionTracker) SetQueueSize(level compactionLevel, size uint64) {
+	atomic.StoreUint64(&t.queueSize[level], size)
+
+	labels := t.Labels(level)
+	t.metrics.CompactionQueueSize.With(labels).Set(float64(size))
+}
+
+// AddCompactedSize increases the size in bytes of the TSM files successfully
+// compacted for the provided level, that is the work executed for the level.
+func (t *compactionTracker) AddCompactedSize(level compactionLevel, size uint64) {
+	atomic.AddUint64(&t.compactedSize[level], size)
+
+	labels := t.Labels(level)
+	t.metrics.CompactedSize.With(labels).Add(float64(size))
+}
+
 // SetOptimiseQueue sets the queue depth for Optimisation compactions.
 func (t *compactionTracker) SetOptimiseQueue(length uint64) { t.SetQueue(4, length) }
 
@@ -840,18 +987,29 @@ func (e *Engine) compactCache() {
 		case <-t.C:
 			e.Cache.UpdateAge()
 			if e.ShouldCompactCache(time.Now()) {
-				start := time.Now()
-				e.traceLogger.Info("Compacting cache", zap.String("path", e.path))
-				err := e.WriteSnapshot()
-				if err != nil && err != errCompactionsDisabled {
-					e.logger.Info("Error writing snapshot", zap.Error(err))
-				}
-				e.compactionTracker.SnapshotAttempted(err == nil || err == errCompactionsDisabled, time.Since(start))
+				e.snapshotCache()
+			}
+
+		case <-e.snapshotC:
+			// Writes are waiting for room in the cache, so snapshot it early.
+			if e.Cache.Size() > 0 {
+				e.snapshotCache()
 			}
 		}
 	}
 }
 
+// snapshotCache writes a snapshot of the cache, tracking the attempt.
+func (e *Engine) snapshotCache() {
+	start := time.Now()
+	e.traceLogger.Info("Compacting cache", zap.String("path", e.path))
+	err := e.WriteSnapshot()
+	if err != nil && err != errCompactionsDisabled {
+		e.logger.Info("Error writing snapshot", zap.Error(err))
+	}
+	e.compactionTracker.SnapshotAttempted(err == nil || err == errCompactionsDisabled, time.Since(start))
+}
+
 // ShouldCompactCache returns true if the Cache is over its flush threshold
 // or if the passed in lastWriteTime is older than the write cold threshold.
 func (e *Engine) ShouldCompactCache(t time.Time) bool {
@@ -901,6 +1059,13 @@ func (e *Engine) compact(wg *sync.WaitGroup) {
 			e.compactionTracker.SetQueue(2, uint64(len(level2Groups)))
 			e.compactionTracker.SetQueue(3, uint64(len(level3Groups)))
 
+			// Update the size of the planned work
+			stats := e.FileStore.Stats()
+			e.compactionTracker.SetQueueSize(1, compactionGroupsSize(stats, level1Groups))
+			e.compactionTracker.SetQueueSize(2, compactionGroupsSize(stats, level2Groups))
+			e.compactionTracker.SetQueueSize(3, compactionGroupsSize(stats, level3Groups))
+			e.compactionTracker.SetQueueSize(4, compactionGroupsSize(stats, level4Groups))
+
 			// Set the queue depths on the scheduler
 			e.scheduler.setDepth(1, len(level1Groups))
 			e.scheduler.setDepth(2, len(level2Groups))
@@ -1044,6 +1209,7 @@ func (s *compactionStrategy) compactGroup() {
 	log, logEnd := logger.NewOperation(s.logger, "TSM compaction", "tsm1_compact_group")
 	defer logEnd()
 
+	size := compactionGroupsSize(s.fileStore.Stats(), []CompactionGroup{group})
 	log.Info("Beginning compaction", zap.Int("tsm1_files_n", len(group)))
 	for i, f := range group {
 		log.Info("Compacting file", zap.Int("tsm1_index", i), zap.String("tsm1_file", f))
@@ -1089,6 +1255,26 @@ func (s *compactionStrategy) compactGroup() {
 	}
 	log.Info("Finished compacting files", zap.Int("tsm1_files_n", len(files)))
 	s.tracker.Attempted(s.level, true, time.Since(now))
+	s.tracker.AddCompactedSize(s.level, size)
+}
+
+// compactionGroupsSize returns the size in bytes of the files of groups, as
+// given by stats.
+func compactionGroupsSize(stats []FileStat, groups []CompactionGroup) uint64 {
+	paths := make(map[string]struct{})
+	for _, g := range groups {
+		for _, f := range g {
+			paths[f] = struct{}{}
+		}
+	}
+
+	var size uint64
+	for _, f := range stats {
+		if _, ok := paths[f.Path]; ok {
+			size += uint64(f.Size)
+		}
+	}
+	return size
 }
 
 // levelCompactionStrategy returns a compactionStrategy for the given level.
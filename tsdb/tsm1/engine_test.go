@@ -127,6 +127,44 @@ func TestEngine_SnapshotsDisabled(t *testing.T) {
 	}
 }
 
+func TestEngine_CompactionPlanner(t *testing.T) {
+	sfile := MustOpenSeriesFile()
+	defer sfile.Close()
+
+	dir, _ := ioutil.TempDir("", "tsm")
+	defer os.RemoveAll(dir)
+
+	idx := MustOpenIndex(filepath.Join(dir, "index"), tsdb.NewSeriesIDSet(), sfile.SeriesFile)
+	defer idx.Close()
+
+	config := tsm1.NewConfig()
+	if err := config.Compaction.Validate(); err != nil {
+		t.Fatalf("unexpected error validating the default config: %v", err)
+	}
+	e := tsm1.NewEngine(filepath.Join(dir, "data"), idx, config)
+	if _, ok := e.CompactionPlan.(*tsm1.DefaultPlanner); !ok {
+		t.Fatalf("expected the level planner, got %T", e.CompactionPlan)
+	}
+
+	config.Compaction.Planner = tsm1.TimeWindowCompactPlanner
+	if err := config.Compaction.Validate(); err != nil {
+		t.Fatalf("unexpected error validating the time-window planner: %v", err)
+	}
+	e = tsm1.NewEngine(filepath.Join(dir, "data"), idx, config)
+	if _, ok := e.CompactionPlan.(*tsm1.TimeWindowPlanner); !ok {
+		t.Fatalf("expected the time-window planner, got %T", e.CompactionPlan)
+	}
+
+	config.Compaction.TimeWindow = 0
+	if err := config.Compaction.Validate(); err == nil {
+		t.Fatal("expected an error validating a time window of zero")
+	}
+	config.Compaction.Planner = "unknown"
+	if err := config.Compaction.Validate(); err == nil {
+		t.Fatal("expected an error validating an unknown planner")
+	}
+}
+
 func TestEngine_ShouldCompactCache(t *testing.T) {
 	nowTime := time.Now()
 
@@ -168,6 +206,138 @@ func TestEngine_ShouldCompactCache(t *testing.T) {
 	}
 }
 
+func TestEngine_WaitForCacheRoom(t *testing.T) {
+	e := MustOpenEngine()
+	defer e.Close()
+
+	if err := e.WritePointsString("m,k=v f=1 1", "m,k=v f=2 2"); err != nil {
+		t.Fatal(err)
+	}
+	e.Cache.SetMaxSize(e.Cache.Size())
+
+	values := map[string][]tsm1.Value{
+		"m,k=v#!~#f": {tsm1.NewValue(3, 3.0), tsm1.NewValue(4, 4.0)},
+	}
+
+	// Writes to a full cache are rejected without waiting.
+	e.CacheMaxWriteWait = 0
+	if _, err := e.WaitForCacheRoom(values); err == nil || !strings.Contains(err.Error(), "cache-max-memory-size") {
+		t.Fatalf("expected cache full error, got %v", err)
+	}
+
+	// Writes to a full cache wait for an early snapshot to free up room.
+	e.CacheMaxWriteWait = 10 * time.Second
+	r, err := e.WaitForCacheRoom(values)
+	if err != nil {
+		t.Fatalf("unexpected error waiting for room: %v", err)
+	}
+	r.Release()
+	if got, exp := e.Cache.Size(), uint64(0); got != exp {
+		t.Fatalf("cache size mismatch: got %v, exp %v", got, exp)
+	}
+	if got, exp := len(e.FileStore.Files()), 1; got != exp {
+		t.Fatalf("files length mismatch: got %v, exp %v", got, exp)
+	}
+
+	// Writes are rejected once the wait is over.
+	if err := e.WritePointsString("m,k=v f=1 1", "m,k=v f=2 2"); err != nil {
+		t.Fatal(err)
+	}
+	e.Compactor.DisableSnapshots()
+	e.CacheMaxWriteWait = 10 * time.Millisecond
+	if _, err := e.WaitForCacheRoom(values); err == nil || !strings.Contains(err.Error(), "cache-max-memory-size") {
+		t.Fatalf("expected cache full error, got %v", err)
+	}
+}
+
+func TestEngine_WaitForCacheRoom_Reserved(t *testing.T) {
+	e := MustOpenEngine()
+	defer e.Close()
+
+	values := map[string][]tsm1.Value{
+		"m,k=v#!~#f": {tsm1.NewValue(1, 1.0), tsm1.NewValue(2, 2.0)},
+	}
+	size := uint64(tsm1.Values(values["m,k=v#!~#f"]).Size())
+	e.Cache.SetMaxSize(size)
+	e.CacheMaxWriteWait = 0
+
+	r, err := e.WaitForCacheRoom(values)
+	if err != nil {
+		t.Fatalf("unexpected error waiting for room: %v", err)
+	}
+
+	// The room is reserved, so other writes are rejected until it is released.
+	if _, err := e.WaitForCacheRoom(values); err == nil {
+		t.Fatal("expected cache full error while room is reserved")
+	}
+	if err := e.WriteValues(values); err == nil {
+		t.Fatal("expected cache full error writing into reserved room")
+	}
+
+	// The reserved write succeeds and releases the room.
+	if err := e.WriteValuesReserved(values, r); err != nil {
+		t.Fatalf("unexpected error writing into reserved room: %v", err)
+	}
+	r.Release()
+	if got, exp := e.Cache.Size(), size+uint64(len("m,k=v#!~#f")); got != exp {
+		t.Fatalf("cache size mismatch: got %v, exp %v", got, exp)
+	}
+
+	// Room released without a write can be reserved again.
+	e.Cache.SetMaxSize(e.Cache.Size() + size)
+	if r, err = e.WaitForCacheRoom(values); err != nil {
+		t.Fatalf("unexpected error waiting for room: %v", err)
+	}
+	r.Release()
+	if r, err = e.WaitForCacheRoom(values); err != nil {
+		t.Fatalf("unexpected error waiting for released room: %v", err)
+	}
+	r.Release()
+}
+
+func TestEngine_WaitForCacheRoom_ReleaseWakesWaiter(t *testing.T) {
+	e := MustOpenEngine()
+	defer e.Close()
+
+	values := map[string][]tsm1.Value{
+		"m,k=v#!~#f": {tsm1.NewValue(1, 1.0), tsm1.NewValue(2, 2.0)},
+	}
+	e.Cache.SetMaxSize(uint64(tsm1.Values(values["m,k=v#!~#f"]).Size()))
+	// Only the release can free up room for the waiting write.
+	e.Compactor.DisableSnapshots()
+	e.CacheMaxWriteWait = 10 * time.Second
+
+	r, err := e.WaitForCacheRoom(values)
+	if err != nil {
+		t.Fatalf("unexpected error waiting for room: %v", err)
+	}
+
+	errC := make(chan error, 1)
+	go func() {
+		r, err := e.WaitForCacheRoom(values)
+		r.Release()
+		errC <- err
+	}()
+
+	// Give the second write time to start waiting for room.
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case err := <-errC:
+		t.Fatalf("expected write to wait for reserved room, got %v", err)
+	default:
+	}
+
+	r.Release()
+	select {
+	case err := <-errC:
+		if err != nil {
+			t.Fatalf("unexpected error waiting for released room: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("write still waiting after the reserved room was released")
+	}
+}
+
 func makeBlockTypeSlice(n int) []byte {
 	r := make([]byte, n)
 	b := tsm1.BlockFloat64
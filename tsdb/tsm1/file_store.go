@@ -68,6 +68,10 @@ type TSMFile interface {
 	// Entries returns the index entries for all blocks for the given key.
 	ReadEntries(key []byte, entries []IndexEntry) ([]IndexEntry, error)
 
+	// BlockSummary returns the summary statistics of the block identified by
+	// entry, if the file has block summaries.
+	BlockSummary(entry *IndexEntry) (BlockSummary, bool)
+
 	// Contains returns true if the file contains any values for the given
 	// key.
 	Contains(key []byte) bool
@@ -177,6 +181,7 @@ var (
 	stringBlocksSizeCounter      = metrics.MustRegisterCounter("string_blocks_size_bytes", metrics.WithGroup(tsmGroup))
 	booleanBlocksDecodedCounter  = metrics.MustRegisterCounter("boolean_blocks_decoded", metrics.WithGroup(tsmGroup))
 	booleanBlocksSizeCounter     = metrics.MustRegisterCounter("boolean_blocks_size_bytes", metrics.WithGroup(tsmGroup))
+	blockSummariesReadCounter    = metrics.MustRegisterCounter("block_summaries_read", metrics.WithGroup(tsmGroup))
 )
 
 // FileStore is an abstraction around multiple TSM files.
@@ -205,7 +210,8 @@ type FileStore struct {
 
 	parseFileName ParseFileNameFunc
 
-	obs FileStoreObserver
+	obs        FileStoreObserver
+	blockCache *BlockCache
 }
 
 // FileStat holds information about a TSM file on disk.
@@ -213,6 +219,7 @@ type FileStat struct {
 	Path             string
 	HasTombstone     bool
 	Size             uint32
+	TombstoneSize    uint32
 	LastModified     int64
 	MinTime, MaxTime int64
 	MinKey, MaxKey   []byte
@@ -259,9 +266,21 @@ func (f *FileStore) WithObserver(obs FileStoreObserver) {
 	if obs == nil {
 		obs = noFileStoreObserver{}
 	}
+	if f.blockCache != nil {
+		obs = blockCacheObserver{FileStoreObserver: obs, cache: f.blockCache}
+	}
 	f.obs = obs
 }
 
+// WithBlockCache sets the cache of decoded blocks read by the cursors of the
+// file store. It must be called before the FileStore is opened.
+func (f *FileStore) WithBlockCache(c *BlockCache) {
+	f.blockCache = c
+	if c != nil {
+		f.obs = blockCacheObserver{FileStoreObserver: f.obs, cache: c}
+	}
+}
+
 func (f *FileStore) WithParseFileNameFunc(parseFileNameFunc ParseFileNameFunc) {
 	f.parseFileName = parseFileNameFunc
 }
@@ -1229,8 +1248,9 @@ type KeyCursor struct {
 	current []*location
 	buf     []Value
 
-	ctx context.Context
-	col *metrics.Group
+	ctx        context.Context
+	col        *metrics.Group
+	blockCache *BlockCache
 
 	// pos is the index within seeks.  Based on ascending, it will increment or
 	// decrement through the size of seeks slice.
@@ -1287,11 +1307,12 @@ func (a ascLocations) Less(i, j int) bool {
 // This function assumes the read-lock has been taken.
 func newKeyCursor(ctx context.Context, fs *FileStore, key []byte, t int64, ascending bool) *KeyCursor {
 	c := &KeyCursor{
-		key:       key,
-		seeks:     fs.locations(key, t, ascending),
-		ctx:       ctx,
-		col:       metrics.GroupFromContext(ctx),
-		ascending: ascending,
+		key:        key,
+		seeks:      fs.locations(key, t, ascending),
+		ctx:        ctx,
+		col:        metrics.GroupFromContext(ctx),
+		blockCache: fs.blockCache,
+		ascending:  ascending,
 	}
 
 	if ascending {
@@ -1434,6 +1455,46 @@ func (c *KeyCursor) nextDescending() {
 	}
 }
 
+// ReadBlockSummary returns the summary of the next block of the cursor and its
+// time range, if the values of the block can be aggregated without decoding
+// them. That is the block is within min and max and holds at most n values,
+// none of which were read or deleted, and no other block holds values in its
+// time range. The block is then marked as read.
+func (c *KeyCursor) ReadBlockSummary(min, max, n int64) (minTime, maxTime int64, s BlockSummary, ok bool) {
+	if len(c.current) == 0 {
+		return 0, 0, BlockSummary{}, false
+	}
+
+	first := c.current[0]
+	e := first.entry
+	if e.MinTime < min || e.MaxTime > max || e.OverlapsTimeRange(first.readMin, first.readMax) {
+		return 0, 0, BlockSummary{}, false
+	}
+
+	for _, cur := range c.current[1:] {
+		if !cur.read() && cur.entry.OverlapsTimeRange(e.MinTime, e.MaxTime) {
+			return 0, 0, BlockSummary{}, false
+		}
+	}
+
+	c.trbuf = first.r.TombstoneRange(c.key, c.trbuf[:0])
+	for _, t := range c.trbuf {
+		if e.OverlapsTimeRange(t.Min, t.Max) {
+			return 0, 0, BlockSummary{}, false
+		}
+	}
+
+	if s, ok = first.r.BlockSummary(&e); !ok || s.Count > n {
+		return 0, 0, BlockSummary{}, false
+	}
+	if c.col != nil {
+		c.col.GetCounter(blockSummariesReadCounter).Add(1)
+	}
+
+	first.markRead(e.MinTime, e.MaxTime)
+	return e.MinTime, e.MaxTime, s, true
+}
+
 type purger struct {
 	mu        sync.RWMutex
 	fileStore *FileStore
@@ -18,14 +18,10 @@ LOOP:
 
 	// First block is the oldest block containing the points we're searching for.
 	first := c.current[0]
-	err := first.r.ReadFloatArrayBlockAt(&first.entry, values)
+	err := c.readFloatArrayBlockAt(first, values)
 	if err != nil {
 		return nil, err
 	}
-	if c.col != nil {
-		c.col.GetCounter(floatBlocksDecodedCounter).Add(1)
-		c.col.GetCounter(floatBlocksSizeCounter).Add(int64(first.entry.Size))
-	}
 
 	// Remove values we already read
 	values.Exclude(first.readMin, first.readMax)
@@ -90,14 +86,10 @@ LOOP:
 			}
 
 			v := &tsdb.FloatArray{}
-			err := cur.r.ReadFloatArrayBlockAt(&cur.entry, v)
+			err := c.readFloatArrayBlockAt(cur, v)
 			if err != nil {
 				return nil, err
 			}
-			if c.col != nil {
-				c.col.GetCounter(floatBlocksDecodedCounter).Add(1)
-				c.col.GetCounter(floatBlocksSizeCounter).Add(int64(cur.entry.Size))
-			}
 
 			c.trbuf = cur.r.TombstoneRange(c.key, c.trbuf[:0])
 			// Remove any tombstoned values
@@ -152,14 +144,10 @@ LOOP:
 			}
 
 			v := &tsdb.FloatArray{}
-			err := cur.r.ReadFloatArrayBlockAt(&cur.entry, v)
+			err := c.readFloatArrayBlockAt(cur, v)
 			if err != nil {
 				return nil, err
 			}
-			if c.col != nil {
-				c.col.GetCounter(floatBlocksDecodedCounter).Add(1)
-				c.col.GetCounter(floatBlocksSizeCounter).Add(int64(cur.entry.Size))
-			}
 			c.trbuf = cur.r.TombstoneRange(c.key, c.trbuf[:0])
 			// Remove any tombstoned values
 			excludeTombstonesFloatArray(c.trbuf, v)
@@ -184,6 +172,39 @@ LOOP:
 	return values, err
 }
 
+// readFloatArrayBlockAt fills values with the block at loc, which is read
+// from the block cache unless it is missing from it.
+func (c *KeyCursor) readFloatArrayBlockAt(loc *location, values *tsdb.FloatArray) error {
+	if v, ok := c.blockCache.get(loc.r.Path(), loc.entry.Offset); ok {
+		a := v.(*tsdb.FloatArray)
+		values.Timestamps = append(values.Timestamps[:0], a.Timestamps...)
+		values.Values = append(values.Values[:0], a.Values...)
+		return nil
+	}
+
+	if err := loc.r.ReadFloatArrayBlockAt(&loc.entry, values); err != nil {
+		return err
+	}
+	if c.col != nil {
+		c.col.GetCounter(floatBlocksDecodedCounter).Add(1)
+		c.col.GetCounter(floatBlocksSizeCounter).Add(int64(loc.entry.Size))
+	}
+
+	if c.blockCache != nil {
+		a := &tsdb.FloatArray{
+			Timestamps: append([]int64(nil), values.Timestamps...),
+			Values:     append([]float64(nil), values.Values...),
+		}
+		c.blockCache.put(loc.r.Path(), loc.entry.Offset, a, blockCacheFloatArraySize(a))
+	}
+	return nil
+}
+
+// blockCacheFloatArraySize returns the approximate size in memory of a.
+func blockCacheFloatArraySize(a *tsdb.FloatArray) uint64 {
+	return uint64(len(a.Timestamps))*8 + uint64(len(a.Values))*8
+}
+
 func excludeTombstonesFloatArray(t []TimeRange, values *tsdb.FloatArray) {
 	for i := range t {
 		values.Exclude(t[i].Min, t[i].Max)
@@ -202,14 +223,10 @@ LOOP:
 
 	// First block is the oldest block containing the points we're searching for.
 	first := c.current[0]
-	err := first.r.ReadIntegerArrayBlockAt(&first.entry, values)
+	err := c.readIntegerArrayBlockAt(first, values)
 	if err != nil {
 		return nil, err
 	}
-	if c.col != nil {
-		c.col.GetCounter(integerBlocksDecodedCounter).Add(1)
-		c.col.GetCounter(integerBlocksSizeCounter).Add(int64(first.entry.Size))
-	}
 
 	// Remove values we already read
 	values.Exclude(first.readMin, first.readMax)
@@ -274,14 +291,10 @@ LOOP:
 			}
 
 			v := &tsdb.IntegerArray{}
-			err := cur.r.ReadIntegerArrayBlockAt(&cur.entry, v)
+			err := c.readIntegerArrayBlockAt(cur, v)
 			if err != nil {
 				return nil, err
 			}
-			if c.col != nil {
-				c.col.GetCounter(integerBlocksDecodedCounter).Add(1)
-				c.col.GetCounter(integerBlocksSizeCounter).Add(int64(cur.entry.Size))
-			}
 
 			c.trbuf = cur.r.TombstoneRange(c.key, c.trbuf[:0])
 			// Remove any tombstoned values
@@ -336,14 +349,10 @@ LOOP:
 			}
 
 			v := &tsdb.IntegerArray{}
-			err := cur.r.ReadIntegerArrayBlockAt(&cur.entry, v)
+			err := c.readIntegerArrayBlockAt(cur, v)
 			if err != nil {
 				return nil, err
 			}
-			if c.col != nil {
-				c.col.GetCounter(integerBlocksDecodedCounter).Add(1)
-				c.col.GetCounter(integerBlocksSizeCounter).Add(int64(cur.entry.Size))
-			}
 			c.trbuf = cur.r.TombstoneRange(c.key, c.trbuf[:0])
 			// Remove any tombstoned values
 			excludeTombstonesIntegerArray(c.trbuf, v)
@@ -368,6 +377,39 @@ LOOP:
 	return values, err
 }
 
+// readIntegerArrayBlockAt fills values with the block at loc, which is read
+// from the block cache unless it is missing from it.
+func (c *KeyCursor) readIntegerArrayBlockAt(loc *location, values *tsdb.IntegerArray) error {
+	if v, ok := c.blockCache.get(loc.r.Path(), loc.entry.Offset); ok {
+		a := v.(*tsdb.IntegerArray)
+		values.Timestamps = append(values.Timestamps[:0], a.Timestamps...)
+		values.Values = append(values.Values[:0], a.Values...)
+		return nil
+	}
+
+	if err := loc.r.ReadIntegerArrayBlockAt(&loc.entry, values); err != nil {
+		return err
+	}
+	if c.col != nil {
+		c.col.GetCounter(integerBlocksDecodedCounter).Add(1)
+		c.col.GetCounter(integerBlocksSizeCounter).Add(int64(loc.entry.Size))
+	}
+
+	if c.blockCache != nil {
+		a := &tsdb.IntegerArray{
+			Timestamps: append([]int64(nil), values.Timestamps...),
+			Values:     append([]int64(nil), values.Values...),
+		}
+		c.blockCache.put(loc.r.Path(), loc.entry.Offset, a, blockCacheIntegerArraySize(a))
+	}
+	return nil
+}
+
+// blockCacheIntegerArraySize returns the approximate size in memory of a.
+func blockCacheIntegerArraySize(a *tsdb.IntegerArray) uint64 {
+	return uint64(len(a.Timestamps))*8 + uint64(len(a.Values))*8
+}
+
 func excludeTombstonesIntegerArray(t []TimeRange, values *tsdb.IntegerArray) {
 	for i := range t {
 		values.Exclude(t[i].Min, t[i].Max)
@@ -386,14 +428,10 @@ LOOP:
 
 	// First block is the oldest block containing the points we're searching for.
 	first := c.current[0]
-	err := first.r.ReadUnsignedArrayBlockAt(&first.entry, values)
+	err := c.readUnsignedArrayBlockAt(first, values)
 	if err != nil {
 		return nil, err
 	}
-	if c.col != nil {
-		c.col.GetCounter(unsignedBlocksDecodedCounter).Add(1)
-		c.col.GetCounter(unsignedBlocksSizeCounter).Add(int64(first.entry.Size))
-	}
 
 	// Remove values we already read
 	values.Exclude(first.readMin, first.readMax)
@@ -458,14 +496,10 @@ LOOP:
 			}
 
 			v := &tsdb.UnsignedArray{}
-			err := cur.r.ReadUnsignedArrayBlockAt(&cur.entry, v)
+			err := c.readUnsignedArrayBlockAt(cur, v)
 			if err != nil {
 				return nil, err
 			}
-			if c.col != nil {
-				c.col.GetCounter(unsignedBlocksDecodedCounter).Add(1)
-				c.col.GetCounter(unsignedBlocksSizeCounter).Add(int64(cur.entry.Size))
-			}
 
 			c.trbuf = cur.r.TombstoneRange(c.key, c.trbuf[:0])
 			// Remove any tombstoned values
@@ -520,14 +554,10 @@ LOOP:
 			}
 
 			v := &tsdb.UnsignedArray{}
-			err := cur.r.ReadUnsignedArrayBlockAt(&cur.entry, v)
+			err := c.readUnsignedArrayBlockAt(cur, v)
 			if err != nil {
 				return nil, err
 			}
-			if c.col != nil {
-				c.col.GetCounter(unsignedBlocksDecodedCounter).Add(1)
-				c.col.GetCounter(unsignedBlocksSizeCounter).Add(int64(cur.entry.Size))
-			}
 			c.trbuf = cur.r.TombstoneRange(c.key, c.trbuf[:0])
 			// Remove any tombstoned values
 			excludeTombstonesUnsignedArray(c.trbuf, v)
@@ -552,6 +582,39 @@ LOOP:
 	return values, err
 }
 
+// readUnsignedArrayBlockAt fills values with the block at loc, which is read
+// from the block cache unless it is missing from it.
+func (c *KeyCursor) readUnsignedArrayBlockAt(loc *location, values *tsdb.UnsignedArray) error {
+	if v, ok := c.blockCache.get(loc.r.Path(), loc.entry.Offset); ok {
+		a := v.(*tsdb.UnsignedArray)
+		values.Timestamps = append(values.Timestamps[:0], a.Timestamps...)
+		values.Values = append(values.Values[:0], a.Values...)
+		return nil
+	}
+
+	if err := loc.r.ReadUnsignedArrayBlockAt(&loc.entry, values); err != nil {
+		return err
+	}
+	if c.col != nil {
+		c.col.GetCounter(unsignedBlocksDecodedCounter).Add(1)
+		c.col.GetCounter(unsignedBlocksSizeCounter).Add(int64(loc.entry.Size))
+	}
+
+	if c.blockCache != nil {
+		a := &tsdb.UnsignedArray{
+			Timestamps: append([]int64(nil), values.Timestamps...),
+			Values:     append([]uint64(nil), values.Values...),
+		}
+		c.blockCache.put(loc.r.Path(), loc.entry.Offset, a, blockCacheUnsignedArraySize(a))
+	}
+	return nil
+}
+
+// blockCacheUnsignedArraySize returns the approximate size in memory of a.
+func blockCacheUnsignedArraySize(a *tsdb.UnsignedArray) uint64 {
+	return uint64(len(a.Timestamps))*8 + uint64(len(a.Values))*8
+}
+
 func excludeTombstonesUnsignedArray(t []TimeRange, values *tsdb.UnsignedArray) {
 	for i := range t {
 		values.Exclude(t[i].Min, t[i].Max)
@@ -570,14 +633,10 @@ LOOP:
 
 	// First block is the oldest block containing the points we're searching for.
 	first := c.current[0]
-	err := first.r.ReadStringArrayBlockAt(&first.entry, values)
+	err := c.readStringArrayBlockAt(first, values)
 	if err != nil {
 		return nil, err
 	}
-	if c.col != nil {
-		c.col.GetCounter(stringBlocksDecodedCounter).Add(1)
-		c.col.GetCounter(stringBlocksSizeCounter).Add(int64(first.entry.Size))
-	}
 
 	// Remove values we already read
 	values.Exclude(first.readMin, first.readMax)
@@ -642,14 +701,10 @@ LOOP:
 			}
 
 			v := &tsdb.StringArray{}
-			err := cur.r.ReadStringArrayBlockAt(&cur.entry, v)
+			err := c.readStringArrayBlockAt(cur, v)
 			if err != nil {
 				return nil, err
 			}
-			if c.col != nil {
-				c.col.GetCounter(stringBlocksDecodedCounter).Add(1)
-				c.col.GetCounter(stringBlocksSizeCounter).Add(int64(cur.entry.Size))
-			}
 
 			c.trbuf = cur.r.TombstoneRange(c.key, c.trbuf[:0])
 			// Remove any tombstoned values
@@ -704,14 +759,10 @@ LOOP:
 			}
 
 			v := &tsdb.StringArray{}
-			err := cur.r.ReadStringArrayBlockAt(&cur.entry, v)
+			err := c.readStringArrayBlockAt(cur, v)
 			if err != nil {
 				return nil, err
 			}
-			if c.col != nil {
-				c.col.GetCounter(stringBlocksDecodedCounter).Add(1)
-				c.col.GetCounter(stringBlocksSizeCounter).Add(int64(cur.entry.Size))
-			}
 			c.trbuf = cur.r.TombstoneRange(c.key, c.trbuf[:0])
 			// Remove any tombstoned values
 			excludeTombstonesStringArray(c.trbuf, v)
@@ -736,6 +787,43 @@ LOOP:
 	return values, err
 }
 
+// readStringArrayBlockAt fills values with the block at loc, which is read
+// from the block cache unless it is missing from it.
+func (c *KeyCursor) readStringArrayBlockAt(loc *location, values *tsdb.StringArray) error {
+	if v, ok := c.blockCache.get(loc.r.Path(), loc.entry.Offset); ok {
+		a := v.(*tsdb.StringArray)
+		values.Timestamps = append(values.Timestamps[:0], a.Timestamps...)
+		values.Values = append(values.Values[:0], a.Values...)
+		return nil
+	}
+
+	if err := loc.r.ReadStringArrayBlockAt(&loc.entry, values); err != nil {
+		return err
+	}
+	if c.col != nil {
+		c.col.GetCounter(stringBlocksDecodedCounter).Add(1)
+		c.col.GetCounter(stringBlocksSizeCounter).Add(int64(loc.entry.Size))
+	}
+
+	if c.blockCache != nil {
+		a := &tsdb.StringArray{
+			Timestamps: append([]int64(nil), values.Timestamps...),
+			Values:     append([]string(nil), values.Values...),
+		}
+		c.blockCache.put(loc.r.Path(), loc.entry.Offset, a, blockCacheStringArraySize(a))
+	}
+	return nil
+}
+
+// blockCacheStringArraySize returns the approximate size in memory of a.
+func blockCacheStringArraySize(a *tsdb.StringArray) uint64 {
+	size := uint64(len(a.Timestamps)) * 8
+	for _, v := range a.Values {
+		size += 16 + uint64(len(v))
+	}
+	return size
+}
+
 func excludeTombstonesStringArray(t []TimeRange, values *tsdb.StringArray) {
 	for i := range t {
 		values.Exclude(t[i].Min, t[i].Max)
@@ -754,14 +842,10 @@ LOOP:
 
 	// First block is the oldest block containing the points we're searching for.
 	first := c.current[0]
-	err := first.r.ReadBooleanArrayBlockAt(&first.entry, values)
+	err := c.readBooleanArrayBlockAt(first, values)
 	if err != nil {
 		return nil, err
 	}
-	if c.col != nil {
-		c.col.GetCounter(booleanBlocksDecodedCounter).Add(1)
-		c.col.GetCounter(booleanBlocksSizeCounter).Add(int64(first.entry.Size))
-	}
 
 	// Remove values we already read
 	values.Exclude(first.readMin, first.readMax)
@@ -826,14 +910,10 @@ LOOP:
 			}
 
 			v := &tsdb.BooleanArray{}
-			err := cur.r.ReadBooleanArrayBlockAt(&cur.entry, v)
+			err := c.readBooleanArrayBlockAt(cur, v)
 			if err != nil {
 				return nil, err
 			}
-			if c.col != nil {
-				c.col.GetCounter(booleanBlocksDecodedCounter).Add(1)
-				c.col.GetCounter(booleanBlocksSizeCounter).Add(int64(cur.entry.Size))
-			}
 
 			c.trbuf = cur.r.TombstoneRange(c.key, c.trbuf[:0])
 			// Remove any tombstoned values
@@ -888,14 +968,10 @@ LOOP:
 			}
 
 			v := &tsdb.BooleanArray{}
-			err := cur.r.ReadBooleanArrayBlockAt(&cur.entry, v)
+			err := c.readBooleanArrayBlockAt(cur, v)
 			if err != nil {
 				return nil, err
 			}
-			if c.col != nil {
-				c.col.GetCounter(booleanBlocksDecodedCounter).Add(1)
-				c.col.GetCounter(booleanBlocksSizeCounter).Add(int64(cur.entry.Size))
-			}
 			c.trbuf = cur.r.TombstoneRange(c.key, c.trbuf[:0])
 			// Remove any tombstoned values
 			excludeTombstonesBooleanArray(c.trbuf, v)
@@ -920,6 +996,39 @@ LOOP:
 	return values, err
 }
 
+// readBooleanArrayBlockAt fills values with the block at loc, which is read
+// from the block cache unless it is missing from it.
+func (c *KeyCursor) readBooleanArrayBlockAt(loc *location, values *tsdb.BooleanArray) error {
+	if v, ok := c.blockCache.get(loc.r.Path(), loc.entry.Offset); ok {
+		a := v.(*tsdb.BooleanArray)
+		values.Timestamps = append(values.Timestamps[:0], a.Timestamps...)
+		values.Values = append(values.Values[:0], a.Values...)
+		return nil
+	}
+
+	if err := loc.r.ReadBooleanArrayBlockAt(&loc.entry, values); err != nil {
+		return err
+	}
+	if c.col != nil {
+		c.col.GetCounter(booleanBlocksDecodedCounter).Add(1)
+		c.col.GetCounter(booleanBlocksSizeCounter).Add(int64(loc.entry.Size))
+	}
+
+	if c.blockCache != nil {
+		a := &tsdb.BooleanArray{
+			Timestamps: append([]int64(nil), values.Timestamps...),
+			Values:     append([]bool(nil), values.Values...),
+		}
+		c.blockCache.put(loc.r.Path(), loc.entry.Offset, a, blockCacheBooleanArraySize(a))
+	}
+	return nil
+}
+
+// blockCacheBooleanArraySize returns the approximate size in memory of a.
+func blockCacheBooleanArraySize(a *tsdb.BooleanArray) uint64 {
+	return uint64(len(a.Timestamps))*8 + uint64(len(a.Values))
+}
+
 func excludeTombstonesBooleanArray(t []TimeRange, values *tsdb.BooleanArray) {
 	for i := range t {
 		values.Exclude(t[i].Min, t[i].Max)
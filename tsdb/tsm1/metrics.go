@@ -25,6 +25,7 @@ func PrometheusCollectors() []prometheus.Collector {
 		collectors = append(collectors, bms.compactionMetrics.PrometheusCollectors()...)
 		collectors = append(collectors, bms.fileMetrics.PrometheusCollectors()...)
 		collectors = append(collectors, bms.cacheMetrics.PrometheusCollectors()...)
+		collectors = append(collectors, bms.blockCacheMetrics.PrometheusCollectors()...)
 	}
 	return collectors
 }
@@ -35,6 +36,7 @@ const namespace = "storage"
 const compactionSubsystem = "compactions" // sub-system associated with metrics for compactions.
 const fileStoreSubsystem = "tsm_files"    // sub-system associated with metrics for TSM files.
 const cacheSubsystem = "cache"            // sub-system associated with metrics for the cache.
+const blockCacheSubsystem = "block_cache" // sub-system associated with metrics for the block cache.
 
 // blockMetrics are a set of metrics concerned with tracking data about block storage.
 type blockMetrics struct {
@@ -42,6 +44,7 @@ type blockMetrics struct {
 	*compactionMetrics
 	*fileMetrics
 	*cacheMetrics
+	*blockCacheMetrics
 }
 
 // newBlockMetrics initialises the prometheus metrics for the block subsystem.
@@ -51,6 +54,7 @@ func newBlockMetrics(labels prometheus.Labels) *blockMetrics {
 		compactionMetrics: newCompactionMetrics(labels),
 		fileMetrics:       newFileMetrics(labels),
 		cacheMetrics:      newCacheMetrics(labels),
+		blockCacheMetrics: newBlockCacheMetrics(labels),
 	}
 }
 
@@ -60,14 +64,17 @@ func (m *blockMetrics) PrometheusCollectors() []prometheus.Collector {
 	metrics = append(metrics, m.compactionMetrics.PrometheusCollectors()...)
 	metrics = append(metrics, m.fileMetrics.PrometheusCollectors()...)
 	metrics = append(metrics, m.cacheMetrics.PrometheusCollectors()...)
+	metrics = append(metrics, m.blockCacheMetrics.PrometheusCollectors()...)
 	return metrics
 }
 
 // compactionMetrics are a set of metrics concerned with tracking data about compactions.
 type compactionMetrics struct {
-	CompactionsActive  *prometheus.GaugeVec
-	CompactionDuration *prometheus.HistogramVec
-	CompactionQueue    *prometheus.GaugeVec
+	CompactionsActive   *prometheus.GaugeVec
+	CompactionDuration  *prometheus.HistogramVec
+	CompactionQueue     *prometheus.GaugeVec
+	CompactionQueueSize *prometheus.GaugeVec
+	CompactedSize       *prometheus.CounterVec
 
 	// The following metrics include a ``"status" = {ok, error}` label
 	Compactions *prometheus.CounterVec
@@ -111,6 +118,18 @@ func newCompactionMetrics(labels prometheus.Labels) *compactionMetrics {
 			Name:      "queued",
 			Help:      "Number of queued compactions.",
 		}, names),
+		CompactionQueueSize: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: compactionSubsystem,
+			Name:      "queued_bytes",
+			Help:      "Size in bytes of the TSM files planned to be compacted.",
+		}, names),
+		CompactedSize: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: compactionSubsystem,
+			Name:      "compacted_bytes_total",
+			Help:      "Size in bytes of the TSM files successfully compacted.",
+		}, names),
 	}
 }
 
@@ -121,6 +140,8 @@ func (m *compactionMetrics) PrometheusCollectors() []prometheus.Collector {
 		m.CompactionsActive,
 		m.CompactionDuration,
 		m.CompactionQueue,
+		m.CompactionQueueSize,
+		m.CompactedSize,
 	}
 }
 
@@ -169,6 +190,7 @@ type cacheMetrics struct {
 	SnapshotsActive  *prometheus.GaugeVec
 	Age              *prometheus.GaugeVec
 	SnapshottedBytes *prometheus.CounterVec
+	WriteWaits       *prometheus.HistogramVec
 
 	// The following metrics include a ``"status" = {ok, error, dropped}` label
 	WrittenBytes *prometheus.CounterVec
@@ -217,6 +239,14 @@ func newCacheMetrics(labels prometheus.Labels) *cacheMetrics {
 			Name:      "snapshot_bytes",
 			Help:      "Number of bytes snapshotted.",
 		}, names),
+		WriteWaits: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: cacheSubsystem,
+			Name:      "write_wait_seconds",
+			Help:      "Time writes waited for a snapshot to free up room in a full Cache.",
+			// 15 buckets spaced exponentially between 1ms and ~16s.
+			Buckets: prometheus.ExponentialBuckets(0.001, 2, 15),
+		}, names),
 		WrittenBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Namespace: namespace,
 			Subsystem: cacheSubsystem,
@@ -240,7 +270,63 @@ func (m *cacheMetrics) PrometheusCollectors() []prometheus.Collector {
 		m.SnapshotsActive,
 		m.Age,
 		m.SnapshottedBytes,
+		m.WriteWaits,
 		m.WrittenBytes,
 		m.Writes,
 	}
 }
+
+// blockCacheMetrics are a set of metrics concerned with tracking data about the
+// cache of decoded blocks.
+type blockCacheMetrics struct {
+	MemSize   *prometheus.GaugeVec
+	Hits      *prometheus.CounterVec
+	Misses    *prometheus.CounterVec
+	Evictions *prometheus.CounterVec
+}
+
+// newBlockCacheMetrics initialises the prometheus metrics for the block cache.
+func newBlockCacheMetrics(labels prometheus.Labels) *blockCacheMetrics {
+	var names []string
+	for k := range labels {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	return &blockCacheMetrics{
+		MemSize: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: blockCacheSubsystem,
+			Name:      "inuse_bytes",
+			Help:      "In-memory size of the decoded blocks in the block cache.",
+		}, names),
+		Hits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: blockCacheSubsystem,
+			Name:      "hits_total",
+			Help:      "Number of blocks read from the block cache.",
+		}, names),
+		Misses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: blockCacheSubsystem,
+			Name:      "misses_total",
+			Help:      "Number of blocks decoded as they were missing from the block cache.",
+		}, names),
+		Evictions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: blockCacheSubsystem,
+			Name:      "evictions_total",
+			Help:      "Number of blocks evicted from the block cache.",
+		}, names),
+	}
+}
+
+// PrometheusCollectors satisfies the prom.PrometheusCollector interface.
+func (m *blockCacheMetrics) PrometheusCollectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.MemSize,
+		m.Hits,
+		m.Misses,
+		m.Evictions,
+	}
+}
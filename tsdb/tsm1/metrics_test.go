@@ -146,6 +146,7 @@ func TestMetrics_Compactions(t *testing.T) {
 	gauges := []string{
 		base + "active",
 		base + "queued",
+		base + "queued_bytes",
 	}
 
 	counters := []string{base + "total"}
@@ -156,6 +157,7 @@ func TestMetrics_Compactions(t *testing.T) {
 		labels := tracker.Labels(2)
 		tracker.metrics.CompactionsActive.With(labels).Add(float64(i + len(gauges[0])))
 		tracker.SetQueue(2, uint64(i+len(gauges[1])))
+		tracker.SetQueueSize(2, uint64(i+len(gauges[2])))
 
 		labels = tracker.Labels(2)
 		labels["status"] = "ok"
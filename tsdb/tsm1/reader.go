@@ -378,6 +378,15 @@ func (t *TSMReader) ReadEntries(key []byte, entries []IndexEntry) ([]IndexEntry,
 	return t.index.ReadEntries(key, entries)
 }
 
+// BlockSummary returns the summary statistics of the block identified by
+// entry. It returns false if the file was written without block summaries.
+func (t *TSMReader) BlockSummary(entry *IndexEntry) (BlockSummary, bool) {
+	t.mu.RLock()
+	s, ok := t.accessor.readSummary(entry)
+	t.mu.RUnlock()
+	return s, ok
+}
+
 // IndexSize returns the size of the index in bytes.
 func (t *TSMReader) IndexSize() uint32 {
 	return t.index.Size()
@@ -432,15 +441,22 @@ func (t *TSMReader) TombstoneRange(key []byte, buf []TimeRange) []TimeRange {
 func (t *TSMReader) Stats() FileStat {
 	minTime, maxTime := t.index.TimeRange()
 	minKey, maxKey := t.index.KeyRange()
+
+	var tombstoneSize uint32
+	for _, f := range t.tombstoner.TombstoneFiles() {
+		tombstoneSize += f.Size
+	}
+
 	return FileStat{
-		Path:         t.Path(),
-		Size:         t.Size(),
-		LastModified: t.LastModified(),
-		MinTime:      minTime,
-		MaxTime:      maxTime,
-		MinKey:       minKey,
-		MaxKey:       maxKey,
-		HasTombstone: t.tombstoner.HasTombstones(),
+		Path:          t.Path(),
+		Size:          t.Size(),
+		TombstoneSize: tombstoneSize,
+		LastModified:  t.LastModified(),
+		MinTime:       minTime,
+		MaxTime:       maxTime,
+		MinKey:        minKey,
+		MaxKey:        maxKey,
+		HasTombstone:  t.tombstoner.HasTombstones(),
 	}
 }
 
@@ -25,6 +25,9 @@ type mmapAccessor struct {
 	f  *os.File
 
 	index *indirectIndex
+
+	// The position and length of the block summaries, if any.
+	summariesStart, summariesLen int64
 }
 
 func (m *mmapAccessor) init() (*indirectIndex, error) {
@@ -75,6 +78,8 @@ func (m *mmapAccessor) init() (*indirectIndex, error) {
 	}
 	m.index.logger = m.logger
 
+	m.summariesStart, m.summariesLen = findBlockSummaries(m.b, int64(indexStart))
+
 	// Allow resources to be freed immediately if requested
 	m.incAccess()
 	atomic.StoreUint64(&m.freeCount, 1)
@@ -201,6 +206,25 @@ func (m *mmapAccessor) readBytes(entry *IndexEntry, b []byte) (uint32, []byte, e
 	return crc, block, nil
 }
 
+// readSummary returns the summary of the block identified by entry, if the
+// file has block summaries.
+func (m *mmapAccessor) readSummary(entry *IndexEntry) (BlockSummary, bool) {
+	if m.summariesLen == 0 {
+		return BlockSummary{}, false
+	}
+
+	m.incAccess()
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	end := m.summariesStart + m.summariesLen
+	if int64(len(m.b)) < end {
+		return BlockSummary{}, false
+	}
+	return searchBlockSummary(m.b[m.summariesStart:end], entry.Offset)
+}
+
 // readAll returns all values for a key in all blocks.
 func (m *mmapAccessor) readAll(key []byte) ([]Value, error) {
 	m.incAccess()
@@ -619,6 +619,10 @@ func TestTSMReader_MMAP_TombstoneOverlapKeyRange(t *testing.T) {
 	if got, exp := len(r.TombstoneFiles()), 1; got != exp {
 		t.Fatalf("TombstoneFiles len mismatch: got %v, exp %v", got, exp)
 	}
+
+	if got, exp := r.Stats().TombstoneSize, r.TombstoneFiles()[0].Size; got != exp || got == 0 {
+		t.Fatalf("TombstoneSize mismatch: got %v, exp %v", got, exp)
+	}
 }
 
 func TestTSMReader_MMAP_TombstoneFullRange(t *testing.T) {
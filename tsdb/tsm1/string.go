@@ -4,6 +4,11 @@ package tsm1
 // appended to byte slice prefixed with a variable byte length followed by the string
 // bytes.  The bytes are compressed using snappy compressor and a 1 byte header is used
 // to indicate the type of encoding.
+//
+// Blocks with few distinct values may instead use dictionary encoding.  The distinct
+// values are written once, each prefixed with a variable byte length, followed by
+// runs of variable byte encoded (dictionary index, run length) pairs.  Dictionary
+// encoding is chosen by StringArrayEncodeAll, which is used by compactions.
 
 import (
 	"encoding/binary"
@@ -14,8 +19,14 @@ import (
 
 // Note: an uncompressed format is not yet implemented.
 
-// stringCompressedSnappy is a compressed encoding using Snappy compression
-const stringCompressedSnappy = 1
+const (
+	// stringCompressedSnappy is a compressed encoding using Snappy compression
+	stringCompressedSnappy = 1
+
+	// stringCompressedDictionary is a compressed encoding using a dictionary of
+	// the distinct values and run-length encoded indexes into it.
+	stringCompressedDictionary = 2
+)
 
 // StringEncoder encodes multiple strings into a byte slice.
 type StringEncoder struct {
@@ -63,13 +74,31 @@ type StringDecoder struct {
 	l   int
 	i   int
 	err error
+
+	// values holds the decoded values of a dictionary encoded block.
+	values []string
+	dict   bool
 }
 
 // SetBytes initializes the decoder with bytes to read from.
 // This must be called before calling any other method.
 func (e *StringDecoder) SetBytes(b []byte) error {
-	// First byte stores the encoding type, only have snappy format
-	// currently so ignore for now.
+	e.l = 0
+	e.i = 0
+	e.err = nil
+
+	// First byte stores the encoding type.
+	e.dict = len(b) > 0 && b[0]>>4 == stringCompressedDictionary
+	if e.dict {
+		values, err := stringArrayDecodeAllDictionary(b, e.values[:0])
+		if err != nil {
+			return fmt.Errorf("failed to decode string block: %v", err.Error())
+		}
+		e.values = values
+		e.b = nil
+		return nil
+	}
+
 	var data []byte
 	if len(b) > 0 {
 		var err error
@@ -80,9 +109,6 @@ func (e *StringDecoder) SetBytes(b []byte) error {
 	}
 
 	e.b = data
-	e.l = 0
-	e.i = 0
-	e.err = nil
 
 	return nil
 }
@@ -94,11 +120,19 @@ func (e *StringDecoder) Next() bool {
 	}
 
 	e.i += e.l
+	if e.dict {
+		return e.i < len(e.values)
+	}
 	return e.i < len(e.b)
 }
 
 // Read returns the next value from the decoder.
 func (e *StringDecoder) Read() string {
+	if e.dict {
+		e.l = 1
+		return e.values[e.i]
+	}
+
 	// Read the length of the string
 	length, n := binary.Uvarint(e.b[e.i:])
 	if n <= 0 {
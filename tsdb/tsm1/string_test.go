@@ -130,6 +130,45 @@ func Test_StringEncoder_Quick(t *testing.T) {
 	}, nil)
 }
 
+func Test_StringDecoder_Dictionary(t *testing.T) {
+	src := make([]string, 64)
+	for i := range src {
+		src[i] = "ok"
+	}
+	src[10], src[11] = "warn", "critical"
+
+	b, err := StringArrayEncodeAll(src, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b[0]>>4 != stringCompressedDictionary {
+		t.Fatalf("unexpected encoding: got %v, exp %v", b[0]>>4, stringCompressedDictionary)
+	}
+
+	// A decoder reused after a dictionary encoded block must decode snappy blocks.
+	var dec StringDecoder
+	for _, vs := range [][]string{src, {"a", "b"}, src} {
+		b, err := StringArrayEncodeAll(vs, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := dec.SetBytes(b); err != nil {
+			t.Fatalf("unexpected error creating string decoder: %v", err)
+		}
+
+		var got []string
+		for dec.Next() {
+			got = append(got, dec.Read())
+		}
+		if err := dec.Error(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(got, vs) {
+			t.Fatalf("unexpected values: -got/+exp\n%s", cmp.Diff(got, vs))
+		}
+	}
+}
+
 func Test_StringDecoder_Empty(t *testing.T) {
 	var dec StringDecoder
 	if err := dec.SetBytes([]byte{}); err != nil {
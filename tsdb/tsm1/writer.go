@@ -52,6 +52,9 @@ file, we can use the size to determine how much to read in a given IO.
 │ 2 bytes │ N bytes │1 byte│2 bytes│ 8 bytes │ 8 bytes │8 bytes │4 bytes │   │
 └─────────┴─────────┴──────┴───────┴─────────┴─────────┴────────┴────────┴───┘
 
+The blocks may be followed by summary statistics of each block, described in
+block_summary.go.  They are written by writers created with WithBlockSummaries.
+
 The last section is the footer that stores the offset of the start of the index.
 
 ┌─────────┐
@@ -592,25 +595,50 @@ type tsmWriter struct {
 	lastSync int64
 
 	stats MeasurementStats
+
+	// summarize is true if block summaries are written, which are
+	// buffered in summaries until the index is written.
+	summarize  bool
+	summarizer blockSummarizer
+	summaries  *blockSummaryBuffer
+}
+
+type tsmWriterOption func(*tsmWriter)
+
+// WithBlockSummaries is an option for specifying whether to write the summary
+// statistics of each block before the index.  Blocks written with WriteBlock
+// are decoded to summarize them.
+var WithBlockSummaries = func(enabled bool) tsmWriterOption {
+	return func(w *tsmWriter) {
+		w.summarize = enabled
+	}
 }
 
 // NewTSMWriter returns a new TSMWriter writing to w.
-func NewTSMWriter(w io.Writer) (TSMWriter, error) {
+func NewTSMWriter(w io.Writer, options ...tsmWriterOption) (TSMWriter, error) {
 	index := NewIndexWriter()
-	return &tsmWriter{
+	t := &tsmWriter{
 		wrapped: w,
 		w:       bufio.NewWriterSize(w, 1024*1024),
 		index:   index,
 		stats:   NewMeasurementStats(),
-	}, nil
+	}
+	for _, option := range options {
+		option(t)
+	}
+	if t.summarize {
+		t.summaries = newBlockSummaryBuffer()
+	}
+	return t, nil
 }
 
 // NewTSMWriterWithDiskBuffer returns a new TSMWriter writing to w and will use a disk
-// based buffer for the TSM index if possible.
-func NewTSMWriterWithDiskBuffer(w io.Writer) (TSMWriter, error) {
+// based buffer for the TSM index and block summaries if possible.
+func NewTSMWriterWithDiskBuffer(w io.Writer, options ...tsmWriterOption) (TSMWriter, error) {
 	var index IndexWriter
 	// Make sure is a File so we can write the temp index alongside it.
-	if fw, ok := w.(syncer); ok {
+	fw, ok := w.(syncer)
+	if ok {
 		f, err := os.OpenFile(strings.TrimSuffix(fw.Name(), ".tsm.tmp")+".idx.tmp", os.O_CREATE|os.O_RDWR|os.O_EXCL, 0666)
 		if err != nil {
 			return nil, err
@@ -621,12 +649,27 @@ func NewTSMWriterWithDiskBuffer(w io.Writer) (TSMWriter, error) {
 		index = NewIndexWriter()
 	}
 
-	return &tsmWriter{
+	t := &tsmWriter{
 		wrapped: w,
 		w:       bufio.NewWriterSize(w, 1024*1024),
 		index:   index,
 		stats:   NewMeasurementStats(),
-	}, nil
+	}
+	for _, option := range options {
+		option(t)
+	}
+
+	if t.summarize && ok {
+		f, err := os.OpenFile(strings.TrimSuffix(fw.Name(), ".tsm.tmp")+".sum.tmp", os.O_CREATE|os.O_RDWR|os.O_EXCL, 0666)
+		if err != nil {
+			index.Remove()
+			return nil, err
+		}
+		t.summaries = newDiskBlockSummaryBuffer(f)
+	} else if t.summarize {
+		t.summaries = newBlockSummaryBuffer()
+	}
+	return t, nil
 }
 
 // MeasurementStats returns the measurement statistics generated by the writer.
@@ -689,6 +732,11 @@ func (t *tsmWriter) Write(key []byte, values Values) error {
 
 	// Record this block in index
 	t.index.Add(key, blockType, values[0].UnixNano(), values[len(values)-1].UnixNano(), t.n, uint32(n))
+	if t.summarize {
+		if err := t.summaries.add(t.n, t.summarizer.summarizeValues(values)); err != nil {
+			return err
+		}
+	}
 
 	// Add block size to measurement stats.
 	name := models.ParseName(key)
@@ -745,6 +793,11 @@ func (t *tsmWriter) WriteBlock(key []byte, minTime, maxTime int64, block []byte)
 
 	// Record this block in index
 	t.index.Add(key, blockType, minTime, maxTime, t.n, uint32(n))
+	if t.summarize {
+		if err := t.addBlockSummary(block); err != nil {
+			return err
+		}
+	}
 
 	// Add block size to measurement stats.
 	name := models.ParseName(key)
@@ -768,15 +821,33 @@ func (t *tsmWriter) WriteBlock(key []byte, minTime, maxTime int64, block []byte)
 	return nil
 }
 
+// addBlockSummary decodes block, written at the current position, to add its summary.
+func (t *tsmWriter) addBlockSummary(block []byte) error {
+	s, err := t.summarizer.summarize(block)
+	if err != nil {
+		return err
+	}
+	return t.summaries.add(t.n, s)
+}
+
 // WriteIndex writes the index section of the file.  If there are no index entries to write,
 // this returns ErrNoValues.
 func (t *tsmWriter) WriteIndex() error {
-	indexPos := t.n
-
 	if t.index.KeyCount() == 0 {
 		return ErrNoValues
 	}
 
+	// Write the block summaries ahead of the index.
+	if t.summarize {
+		n, err := t.summaries.WriteTo(t.w)
+		t.n += n
+		if err != nil {
+			return err
+		}
+	}
+
+	indexPos := t.n
+
 	// Set the destination file on the index so we can periodically
 	// fsync while writing the index.
 	if f, ok := t.wrapped.(syncer); ok {
@@ -849,6 +920,12 @@ func (t *tsmWriter) Close() error {
 		return err
 	}
 
+	if t.summarize {
+		if err := t.summaries.Remove(); err != nil {
+			return err
+		}
+	}
+
 	// Write stats to disk, if writer is a file.
 	if err := t.writeStatsFile(); err != nil {
 		return err
@@ -866,6 +943,12 @@ func (t *tsmWriter) Remove() error {
 		return err
 	}
 
+	if t.summarize {
+		if err := t.summaries.Remove(); err != nil {
+			return err
+		}
+	}
+
 	// nameCloser is the most permissive interface we can close the wrapped
 	// value with.
 	type nameCloser interface {
@@ -888,7 +971,11 @@ func (t *tsmWriter) Remove() error {
 }
 
 func (t *tsmWriter) Size() uint32 {
-	return uint32(t.n) + t.index.Size()
+	size := uint32(t.n) + t.index.Size()
+	if t.summarize {
+		size += uint32(t.summaries.Size())
+	}
+	return size
 }
 
 // verifyVersion verifies that the reader's bytes are a TSM byte
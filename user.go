@@ -10,6 +10,12 @@ type User struct {
 	Name string `json:"name"`
 }
 
+// Cursor returns the position of the user in a listing sorted by sortBy.
+// Users may be sorted by ID or Name.
+func (u *User) Cursor(sortBy string) (PageCursor, error) {
+	return namePageCursor(sortBy, u.Name, u.ID)
+}
+
 // Ops for user errors and op log.
 const (
 	OpFindUserByID = "FindUserByID"